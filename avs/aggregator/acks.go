@@ -0,0 +1,35 @@
+package aggregator
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/ack"
+)
+
+// TaskResponseAccepted is the body returned for an accepted task response
+type TaskResponseAccepted struct {
+	Status string `json:"status"`
+	// Ack is the aggregator's signed acknowledgment, omitted when no
+	// aggregator key is configured
+	Ack *ack.SignedAck `json:"ack,omitempty"`
+}
+
+// signAck signs an acknowledgment that the response was accepted now. It
+// returns nil without an aggregator key.
+func (a *Aggregator) signAck(ctx context.Context, signedResponse SignedTaskResponse, responseDigest common.Hash) (*ack.SignedAck, error) {
+	if a.ackSigner == nil {
+		return nil, nil
+	}
+
+	signed, err := ack.Sign(ctx, ack.New(
+		signedResponse.TaskResponse.ReferenceTaskIndex,
+		common.Hash(signedResponse.OperatorId),
+		responseDigest,
+	), a.ackSigner)
+	if err != nil {
+		return nil, err
+	}
+	return &signed, nil
+}
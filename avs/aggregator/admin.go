@@ -0,0 +1,99 @@
+package aggregator
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type banRequest struct {
+	OperatorId string `json:"operatorId"`
+	Reason     string `json:"reason"`
+}
+
+// registerAdminRoutes adds the admin API. It is only exposed when an admin
+// token is configured.
+func (a *Aggregator) registerAdminRoutes(router *mux.Router) {
+	if a.config.AdminApiToken == "" {
+		return
+	}
+
+	admin := router.PathPrefix("/admin").Subrouter()
+	admin.Use(a.requireAdminToken)
+
+	admin.HandleFunc("/bans", a.listBansHandler).Methods("GET")
+	admin.HandleFunc("/bans", a.addBanHandler).Methods("POST")
+	admin.HandleFunc("/bans/{operatorId}", a.removeBanHandler).Methods("DELETE")
+	admin.HandleFunc("/tasks/history/{taskIndex}", a.deleteTaskHistoryHandler).Methods("DELETE")
+	admin.HandleFunc("/params", a.paramsHandler).Methods("GET")
+	admin.HandleFunc("/params", a.proposeParamUpdateHandler).Methods("POST")
+	admin.HandleFunc("/params/pending/{id}", a.cancelParamUpdateHandler).Methods("DELETE")
+}
+
+func (a *Aggregator) requireAdminToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(a.config.AdminApiToken)) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *Aggregator) listBansHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(a.banList.List())
+}
+
+func (a *Aggregator) addBanHandler(w http.ResponseWriter, r *http.Request) {
+	var request banRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	operatorId, err := parseOperatorId(request.OperatorId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := a.banList.Ban(operatorId, request.Reason, false); err != nil {
+		a.logger.Error("Failed to ban operator", "operatorId", request.OperatorId, "error", err)
+		http.Error(w, "Failed to ban operator", http.StatusInternalServerError)
+		return
+	}
+
+	a.logger.Info("Operator banned", "operatorId", request.OperatorId, "reason", request.Reason)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "banned"})
+}
+
+func (a *Aggregator) removeBanHandler(w http.ResponseWriter, r *http.Request) {
+	operatorId, err := parseOperatorId(mux.Vars(r)["operatorId"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	removed, err := a.banList.Unban(operatorId)
+	if err != nil {
+		a.logger.Error("Failed to unban operator", "operatorId", formatOperatorId(operatorId), "error", err)
+		http.Error(w, "Failed to unban operator", http.StatusInternalServerError)
+		return
+	}
+	if !removed {
+		http.Error(w, "Operator is not banned", http.StatusNotFound)
+		return
+	}
+
+	a.logger.Info("Operator unbanned", "operatorId", formatOperatorId(operatorId))
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "unbanned"})
+}
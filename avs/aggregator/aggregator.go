@@ -3,20 +3,99 @@ package aggregator
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"net"
 	"net/http"
 	"sync"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/Layr-Labs/eigensdk-go/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/eigenlvr/avs/pkg/apiversion"
 	"github.com/eigenlvr/avs/pkg/avsregistry"
+	"github.com/eigenlvr/avs/pkg/blsaggregation"
+	"github.com/eigenlvr/avs/pkg/compression"
+	"github.com/eigenlvr/avs/pkg/diagnostics"
+	"github.com/eigenlvr/avs/pkg/digest"
+	"github.com/eigenlvr/avs/pkg/erc4337"
+	"github.com/eigenlvr/avs/pkg/escrow"
+	"github.com/eigenlvr/avs/pkg/ipfs"
+	"github.com/eigenlvr/avs/pkg/logwatcher"
+	"github.com/eigenlvr/avs/pkg/networks"
+	"github.com/eigenlvr/avs/pkg/notify"
+	"github.com/eigenlvr/avs/pkg/poolmetrics"
+	"github.com/eigenlvr/avs/pkg/poolregistry"
+	"github.com/eigenlvr/avs/pkg/quorumapk"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+	"github.com/eigenlvr/avs/pkg/sdnotify"
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+	"github.com/eigenlvr/avs/pkg/sigchecker"
+	"github.com/eigenlvr/avs/pkg/txbump"
+	"github.com/eigenlvr/avs/pkg/wsproto"
+)
+
+const (
+	// SemVer is the semantic version of the aggregator
+	SemVer = "0.0.1"
+
+	// defaultMinOperators is the number of distinct responders required when
+	// MinOperators is not configured
+	defaultMinOperators = 2
+
+	// Resource limits applied when the corresponding config value is unset
+	defaultMaxOpenTasks        = 1000
+	defaultMaxResponsesPerTask = 256
+	defaultMaxRequestBodyBytes = 1 << 20 // 1 MiB
+
+	// defaultTaskRetention is how long tasks stay in memory when TaskRetention is unset
+	defaultTaskRetention = time.Hour
+
+	// taskResponseWindowBlocks mirrors the service manager's TASK_RESPONSE_WINDOW_BLOCK
+	taskResponseWindowBlocks = 30
+
+	// nonSignerStakesTimeout bounds the registry reads made to build submission calldata
+	nonSignerStakesTimeout = 30 * time.Second
+	// maxAggregationRetries is how many cleanup passes aggregate a task again
+	// after its submission couldn't be built
+	maxAggregationRetries = 3
+
+	// chainIdTimeout bounds the chain id read at startup
+	chainIdTimeout = 10 * time.Second
+	// registryWriterConfirmations is how many blocks must follow the
+	// aggregator's registry transactions
+	registryWriterConfirmations = 2
+)
+
+var (
+	// ErrOperatorBanned is returned when a response comes from a banned operator
+	ErrOperatorBanned = errors.New("operator is banned")
+
+	// ErrTooManyOpenTasks is returned when a response would open a task beyond MaxOpenTasks
+	ErrTooManyOpenTasks = errors.New("too many open tasks")
+
+	// ErrTooManyResponses is returned when a task already holds MaxResponsesPerTask responses
+	ErrTooManyResponses = errors.New("too many responses for task")
+
+	// ErrUnknownTask is returned when a response references a task index the
+	// service manager has not created
+	ErrUnknownTask = errors.New("unknown task")
+
+	// ErrInvalidSignature is returned when a response's signature doesn't verify
+	// against the operator's registered BLS key
+	ErrInvalidSignature = errors.New("invalid task response signature")
+
+	// ErrSignatureRejected is returned when a signature is refused for any
+	// other reason, such as the operator having no registered key or not
+	// being in the task's quorums
+	ErrSignatureRejected = errors.New("task response signature rejected")
 )
 
 type Aggregator struct {
@@ -25,35 +104,376 @@ type Aggregator struct {
 	ethClient  eth.Client
 	metricsReg *prometheus.Registry
 
-	avsWriter avsregistry.AvsRegistryChainWriter
+	// Sends registry transactions with the aggregator key, nil without one
+	avsWriter *avsregistry.AvsRegistryChainWriter
 	avsReader avsregistry.AvsRegistryChainReader
 
+	// Aggregation floors and thresholds, updated only after an announced delay
+	params *paramSchedule
+
+	banList *BanList
+	// Operators accepted in permissioned mode, nil when permissionless
+	allowlist *operatorAllowlist
+	// Recent stake participation, which thresholds are lowered to when low
+	participation *participationTracker
+	// Follows NewAuctionTaskCreated events when a service manager is configured
+	taskWatcher *logwatcher.Watcher
+	// Follows the primary aggregator's submissions in watch-only mode
+	shadow *shadowVerifier
+	// Accepts and reviews challenges against aggregated responses
+	challenges *challengeTracker
+	// Responses refused for their signature, per operator
+	signatureFailures *prometheus.CounterVec
+	// Responses repeated by an operator or sent after completion, by kind
+	repeatedResponses *prometheus.CounterVec
+
+	// Task numbering synced from the service manager, nil when none is configured
+	taskSync *taskSync
+
+	// Registered operators and their observed activity
+	operators                  *operatorTracker
+	operatorSetRefreshInterval time.Duration
+	operatorLivenessWindow     time.Duration
+
+	// Submission transactions are replaced with bumped fees while stuck. The
+	// sender is only set once the aggregator has a signing key.
+	submissionTxConfig txbump.Config
+	submissionSender   *txbump.Sender
+	// Sends submissions through a 4337 bundler instead, nil when off
+	userOpSender *erc4337.Sender
+	// Winners' deposits are re-checked before settling, nil without an escrow
+	escrow *escrow.Reader
+	// Sealed-bid auctions per pool and block, nil when bids aren't taken
+	bidBook *bidBook
+	// Pools auctions run for, nil when every pool is accepted
+	pools *poolregistry.Registry
+
+	// Operators connected over the persistent WebSocket
+	operatorHub *operatorHub
+	// Dashboards and other clients following task events over WebSocket
+	eventHub *eventHub
+	// Serves the gRPC interface, nil when it is off
+	grpc *grpcServer
+
+	// Signs acks of accepted responses and certificates, nil when no key is
+	// configured or the key can't sign digests
+	ackSigner remotesigner.Signer
+
+	// Quorum APKs used to pre-verify aggregate signatures before submission
+	apkTracker *quorumapk.Tracker
+	// Builds the non-signer data checkSignatures needs with each submission
+	sigBuilder *sigchecker.Builder
+	// eigensdk's BLS aggregation service, nil with the builtin backend
+	blsAggregation *blsaggregation.Service
+
+	// Retention and archiving of old tasks
+	taskRetention      time.Duration
+	taskStore          TaskStore
+	storeMaintenance   *storeMaintenance
+	checkpointInterval time.Duration
+
+	// Pins completed tasks' result bundles, nil when publication is off
+	ipfs *ipfs.Client
+	// Publishes result bundles in blob transactions, nil when off
+	blobs *blobPublisher
+
+	// Posts outcomes and incidents to chat channels, nil when none are
+	// configured. operatorLive is the liveness seen by the last health check.
+	notifier     *notify.Notifier
+	operatorLive map[string]bool
+
+	diagnostics *diagnostics.Collector
+	metrics     *taskMetrics
+	httpPolicy  *httpPolicy
+	// legacyApiSunset is announced on the deprecated unversioned routes
+	legacyApiSunset *time.Time
+
+	// Pets the systemd watchdog while the task loop keeps beating
+	watchdog *sdnotify.Watchdog
+
 	// Task aggregation
-	tasksMutex    sync.RWMutex
-	tasks         map[uint32]*TaskInfo
-	httpServer    *http.Server
+	tasksMutex sync.RWMutex
+	tasks      map[uint32]*TaskInfo
+	httpServer *http.Server
+	// The HTTP listener taken over from the previous aggregator, if any
+	inheritedListener net.Listener
 }
 
 type Config struct {
-	ServerIpPortAddr              string `json:"server_ip_port_address"`
+	// Network selects a preset (mainnet, holesky, sepolia) that fills in any
+	// contract address left unset
+	Network          string `json:"network"`
+	ServerIpPortAddr string `json:"server_ip_port_address"`
+	// GrpcServerIpPortAddr serves the gRPC interface alongside the HTTP API
+	// when set
+	GrpcServerIpPortAddr          string `json:"grpc_server_ip_port_address"`
 	EthRpcUrl                     string `json:"eth_rpc_url"`
 	RegistryCoordinatorAddress    string `json:"registry_coordinator_address"`
 	OperatorStateRetrieverAddress string `json:"operator_state_retriever_address"`
 	AggregatorPrivateKeyPath      string `json:"aggregator_private_key_path"`
 	EigenMetricsIpPortAddress     string `json:"eigen_metrics_ip_port_address"`
 	EnableMetrics                 bool   `json:"enable_metrics"`
+	MinOperators                  int    `json:"min_operators"`
+	MinTotalStake                 string `json:"min_total_stake"`
+	BanListPath                   string `json:"ban_list_path"`
+	AutoBanInvalidSignatures      int    `json:"auto_ban_invalid_signatures"`
+	AdminApiToken                 string `json:"admin_api_token"`
+	MaxOpenTasks                  int    `json:"max_open_tasks"`
+	MaxResponsesPerTask           int    `json:"max_responses_per_task"`
+	MaxRequestBodyBytes           int64  `json:"max_request_body_bytes"`
+	// ChainId is the chain transactions are signed for. It is read from
+	// EthRpcUrl at startup, and must match ChainId and Network when they are
+	// set.
+	ChainId uint64 `json:"chain_id"`
+	// The passphrase of an encrypted AggregatorPrivateKeyPath keystore is read
+	// from AggregatorPrivateKeyPasswordFile when set, else from
+	// EIGENLVR_ECDSA_KEY_PASSWORD, else prompted for on a terminal
+	AggregatorPrivateKeyPasswordFile string `json:"aggregator_private_key_password_file"`
+	// AggregatorSigner is "local" (default) to sign acks, certificates and
+	// published results with the key at AggregatorPrivateKeyPath, or
+	// "web3signer", "aws-kms" or "gcp-kms" to have the key at
+	// AggregatorSignerUrl or AggregatorSignerKeyId sign instead. Web3Signer
+	// only signs transactions, so acks and certificates are off with it.
+	AggregatorSigner        string `json:"aggregator_signer"`
+	AggregatorSignerUrl     string `json:"aggregator_signer_url"`
+	AggregatorSignerKeyId   string `json:"aggregator_signer_key_id"`
+	AggregatorSignerAddress string `json:"aggregator_signer_address"`
+	// With RpcCacheSize set, read calls to EthRpcUrl go through a caching
+	// proxy shared by every component of the process. Calls against the
+	// latest block are reused for RpcCacheHeadTtl.
+	RpcCacheSize    int    `json:"rpc_cache_size"`
+	RpcCacheHeadTtl string `json:"rpc_cache_head_ttl"`
+	// In the "enforce" OperatorAllowlistMode only operators listed, by address
+	// or operator id, in OperatorAllowlist or in the allowlist contract at
+	// OperatorAllowlistContract may respond; "monitor" only logs the others.
+	// Disabling the contract's allowlist makes the aggregator permissionless.
+	OperatorAllowlistMode     string   `json:"operator_allowlist_mode"`
+	OperatorAllowlist         []string `json:"operator_allowlist"`
+	OperatorAllowlistContract string   `json:"operator_allowlist_contract"`
+	// Tasks older than TaskRetention are removed from memory, and archived to
+	// the BoltDB file at TaskStorePath when one is configured
+	TaskRetention string `json:"task_retention"`
+	TaskStorePath string `json:"task_store_path"`
+	// Tasks in memory are checkpointed to the task store every
+	// CheckpointInterval so collected responses and results survive a restart,
+	// and aggregations a crash interrupted are finished on startup
+	CheckpointInterval string `json:"checkpoint_interval"`
+	// The task store is pruned every StoreMaintenanceInterval, and compacted
+	// once enough of it is free space. Archived tasks are kept for
+	// ArchiveRetention after archiving, soft-deleted ones for
+	// DeletedTaskRetention after deletion and checkpoints for
+	// CheckpointRetention after their task was created. Empty keeps forever.
+	StoreMaintenanceInterval string `json:"store_maintenance_interval"`
+	ArchiveRetention         string `json:"archive_retention"`
+	DeletedTaskRetention     string `json:"deleted_task_retention"`
+	CheckpointRetention      string `json:"checkpoint_retention"`
+	// Submissions pending for longer than SubmissionStuckAfter are replaced with
+	// fees raised by SubmissionGasBumpPercent, up to SubmissionMaxGasPriceGwei
+	SubmissionStuckAfter      string `json:"submission_stuck_after"`
+	SubmissionGasBumpPercent  int    `json:"submission_gas_bump_percent"`
+	SubmissionMaxGasPriceGwei uint64 `json:"submission_max_gas_price_gwei"`
+	SubmissionMaxGasBumps     int    `json:"submission_max_gas_bumps"`
+	// Submissions are signed with a key shared among ThresholdSignerParties
+	// by threshold ECDSA. Any ThresholdSignerThreshold of them must join each
+	// signature, so no single machine can submit aggregates.
+	// ThresholdSignerAddress is the shared key's address.
+	ThresholdSignerParties       []string `json:"threshold_signer_parties"`
+	ThresholdSignerThreshold     int      `json:"threshold_signer_threshold"`
+	ThresholdSignerAddress       string   `json:"threshold_signer_address"`
+	ThresholdSignerAuthorization string   `json:"threshold_signer_authorization"`
+	ThresholdSignerTimeout       string   `json:"threshold_signer_timeout"`
+	// When Erc4337BundlerUrl is set, submissions are sent as user operations
+	// from the smart account Erc4337Account, which must be the aggregator the
+	// service manager accepts responses from. Operations are signed with the
+	// account owner key at Erc4337OwnerKeyPath, so submitting EOAs are rotated
+	// on the account alone. Gas is sponsored by the ERC-7677 paymaster service
+	// at Erc4337PaymasterUrl when set, which gets Erc4337PaymasterContext.
+	Erc4337BundlerUrl       string                 `json:"erc4337_bundler_url"`
+	Erc4337EntryPoint       string                 `json:"erc4337_entry_point"`
+	Erc4337Account          string                 `json:"erc4337_account"`
+	Erc4337OwnerKeyPath     string                 `json:"erc4337_owner_key_path"`
+	Erc4337PaymasterUrl     string                 `json:"erc4337_paymaster_url"`
+	Erc4337PaymasterContext map[string]interface{} `json:"erc4337_paymaster_context"`
+	Erc4337ReceiptTimeout   string                 `json:"erc4337_receipt_timeout"`
+	// Task indices are checked against the service manager's latestTaskNum and
+	// task hashes when ServiceManagerAddress is set, and tasks are opened from
+	// its NewAuctionTaskCreated events over EthWsUrl, falling back to polling
+	// every TaskPollInterval from the block in TaskCheckpointPath while the
+	// websocket is unavailable
+	ServiceManagerAddress string `json:"service_manager_address"`
+	TaskCheckpointPath    string `json:"task_checkpoint_path"`
+	TaskPollInterval      string `json:"task_poll_interval"`
+	// A WatchOnly aggregator collects, verifies and aggregates responses as
+	// usual but never submits; it compares its results with the responses
+	// the primary aggregator gets accepted by the service manager instead
+	WatchOnly bool `json:"watch_only"`
+	// Operators count as live on GET /operators if they responded within
+	// OperatorLivenessWindow
+	OperatorSetRefreshInterval string `json:"operator_set_refresh_interval"`
+	OperatorLivenessWindow     string `json:"operator_liveness_window"`
+	// Task metrics are labelled by pool. Only pools in MetricsPoolAllowlist get
+	// their own series when it is set, otherwise the first MetricsMaxPools do.
+	MetricsPoolAllowlist []string `json:"metrics_pool_allowlist"`
+	MetricsMaxPools      int      `json:"metrics_max_pools"`
+	// QuorumThresholds raises, per quorum number, the percentage of the
+	// quorum's stake that must sign before a response is aggregated above
+	// the task's own threshold
+	QuorumThresholds map[uint8]uint32 `json:"quorum_thresholds"`
+	// MinQuorumThresholds are, per quorum number, how low the threshold may
+	// go while the stake responding to the last ParticipationWindowTasks
+	// tasks can't reach it. Quorums without one are never lowered, and no
+	// quorum is lowered below the task's own threshold, so only thresholds
+	// raised by QuorumThresholds or pool overrides move.
+	MinQuorumThresholds      map[uint8]uint32 `json:"min_quorum_thresholds"`
+	ParticipationWindowTasks int              `json:"participation_window_tasks"`
+	// MinOperators, MinTotalStake and QuorumThresholds are the initial runtime
+	// params. Changes to them, whether through the admin API or this config,
+	// are announced and only apply ParamUpdateDelay later. The schedule is
+	// kept at ParamUpdatesPath, which then takes precedence over this config.
+	ParamUpdateDelay string `json:"param_update_delay"`
+	ParamUpdatesPath string `json:"param_updates_path"`
+	// Browser origins allowed to call the API ("*" for any), how long their
+	// preflights may be cached, and the HSTS max age (empty disables HSTS)
+	CorsAllowedOrigins []string `json:"cors_allowed_origins"`
+	CorsMaxAge         string   `json:"cors_max_age"`
+	HstsMaxAge         string   `json:"hsts_max_age"`
+	// The unversioned routes predating /v1 are served as deprecated aliases
+	// until DisableLegacyApi is set. LegacyApiSunset is the announced removal
+	// date (YYYY-MM-DD), sent to clients in the Sunset header.
+	DisableLegacyApi bool   `json:"disable_legacy_api"`
+	LegacyApiSunset  string `json:"legacy_api_sunset"`
+	// AggregationBackend is "builtin" or "blsagg". The blsagg backend verifies
+	// signatures and stake thresholds with eigensdk's BLS aggregation service
+	// at the task's reference block, expiring tasks after TaskExpiry.
+	// MinOperators and MinTotalStake only apply to the builtin backend.
+	AggregationBackend string `json:"aggregation_backend"`
+	EthWsUrl           string `json:"eth_ws_url"`
+	TaskExpiry         string `json:"task_expiry"`
+	// Tasks whose response window closes below their thresholds are marked
+	// expired. SubmitExpiredTasks also records each expiry on chain with
+	// expireAuctionTask, sent like submissions.
+	SubmitExpiredTasks bool `json:"submit_expired_tasks"`
+	// When IpfsApiUrl is set, each completed task's result bundle is pinned
+	// through the node's RPC API before the task is archived. IpfsAuthorization
+	// is sent as the Authorization header, e.g. "Bearer <token>".
+	IpfsApiUrl        string `json:"ipfs_api_url"`
+	IpfsAuthorization string `json:"ipfs_authorization"`
+	// When BlobInboxAddress is set, each completed task's result bundle is
+	// also published on chain in blob transactions to that address, signed
+	// with the aggregator key. While the blob base fee is above BlobMaxFeeGwei
+	// the bundle goes in calldata if BlobCalldataFallback is set, and is
+	// otherwise retried on a later cleanup pass.
+	BlobInboxAddress     string `json:"blob_inbox_address"`
+	BlobMaxFeeGwei       uint64 `json:"blob_max_fee_gwei"`
+	BlobCalldataFallback bool   `json:"blob_calldata_fallback"`
+	// A new aggregator takes over the open tasks and HTTP listener of the
+	// one serving HandoverSocketPath, which then exits, so upgrades don't
+	// refuse or lose any responses
+	HandoverSocketPath string `json:"handover_socket_path"`
+	// Task status responses link transactions and blocks through these
+	// templates, e.g. "https://etherscan.io/tx/{hash}" and
+	// "https://etherscan.io/block/{number}". The network preset fills them in.
+	ExplorerTxUrl    string `json:"explorer_tx_url"`
+	ExplorerBlockUrl string `json:"explorer_block_url"`
+	// Notifications are posted to each channel for the events it selects
+	Notifications []NotificationConfig `json:"notifications"`
+	// Results are only settled while the winner's bid is still covered by its
+	// deposit in the escrow at AuctionEscrowAddress
+	AuctionEscrowAddress string `json:"auction_escrow_address"`
+	// With CommitteeVrfExpectedSize set, only responses carrying a VRF ticket
+	// that draws the operator into a committee of about that many operators
+	// are accepted. Operators must use the same expected size. Thresholds
+	// are still measured against every quorum's full stake, so the
+	// aggregator refuses to start when such a committee isn't expected to
+	// hold them.
+	CommitteeVrfExpectedSize uint32 `json:"committee_vrf_expected_size"`
+	// With BidBookEnabled set, the aggregator runs a sealed-bid auction per
+	// pool and block: searchers commit to signed bids on POST /bid/commit
+	// until BidRevealBlocks before the block, then reveal them on POST /bid,
+	// and operators read the revealed bids from the bid book. An auction
+	// takes up to MaxBidsPerAuction bidders and is dropped BidRetentionBlocks
+	// after its block. Reveals must be covered by the bidder's deposit when
+	// AuctionEscrowAddress is set.
+	BidBookEnabled     bool   `json:"bid_book_enabled"`
+	BidRevealBlocks    uint64 `json:"bid_reveal_blocks"`
+	MaxBidsPerAuction  int    `json:"max_bids_per_auction"`
+	BidRetentionBlocks uint64 `json:"bid_retention_blocks"`
+	// PoolsFile lists the pools auctions run for, with their own auction
+	// duration, reserve price, fee recipient and quorum threshold. Bids for
+	// other pools are refused. Operators must load the same file.
+	PoolsFile string `json:"pools_file"`
+	// An aggregated response can be challenged through the API for
+	// ChallengeWindow after it is recorded, the service manager's 100 block
+	// TASK_CHALLENGE_WINDOW_BLOCK at 12 second blocks when unset.
+	// TaskChallenged events are followed when a service manager is
+	// configured, from the block in ChallengeCheckpointPath.
+	ChallengeWindow         string `json:"challenge_window"`
+	ChallengeCheckpointPath string `json:"challenge_checkpoint_path"`
 }
 
 type TaskInfo struct {
-	TaskIndex                 uint32                           `json:"taskIndex"`
-	PoolId                    common.Hash                      `json:"poolId"`
-	TaskCreatedBlock          uint32                           `json:"taskCreatedBlock"`
-	QuorumNumbers             types.QuorumNums                 `json:"quorumNumbers"`
-	QuorumThresholdPercentage types.ThresholdPercentage        `json:"quorumThresholdPercentage"`
-	TaskResponses             map[types.OperatorId]TaskResponse `json:"taskResponses"`
+	TaskIndex                 uint32                                `json:"taskIndex"`
+	TaskHash                  common.Hash                           `json:"taskHash"`
+	PoolId                    common.Hash                           `json:"poolId"`
+	TaskCreatedBlock          uint32                                `json:"taskCreatedBlock"`
+	QuorumNumbers             types.QuorumNums                      `json:"quorumNumbers"`
+	QuorumThresholdPercentage types.QuorumThresholdPercentage       `json:"quorumThresholdPercentage"`
+	TaskResponses             map[types.OperatorId]TaskResponse     `json:"taskResponses"`
 	TaskResponsesInfo         map[types.OperatorId]TaskResponseInfo `json:"taskResponsesInfo"`
-	IsCompleted               bool                             `json:"isCompleted"`
-	CreatedAt                 time.Time                        `json:"createdAt"`
+	IsCompleted               bool                                  `json:"isCompleted"`
+	CreatedAt                 time.Time                             `json:"createdAt"`
+	AggregatedResponse        *TaskResponse                         `json:"aggregatedResponse,omitempty"`
+	AggregatedDigest          *common.Hash                          `json:"aggregatedDigest,omitempty"`
+	Signers                   []types.OperatorId                    `json:"signers,omitempty"`
+	QuorumAggregates          []QuorumAggregate                     `json:"quorumAggregates,omitempty"`
+	OperatorSetSnapshot       *OperatorSetSnapshot                  `json:"-"`
+	SubmissionTxHash          *common.Hash                          `json:"submissionTxHash,omitempty"`
+	SubmissionBlockNumber     *uint64                               `json:"submissionBlockNumber,omitempty"`
+	ResultBundleCid           *string                               `json:"resultBundleCid,omitempty"`
+	ResultDataTx              *ResultDataTx                         `json:"resultDataTx,omitempty"`
+	PrimarySubmission         *PrimarySubmission                    `json:"primarySubmission,omitempty"`
+	ChallengeDeadline         *time.Time                            `json:"challengeDeadline,omitempty"`
+	Challenges                []Challenge                           `json:"challenges,omitempty"`
+	// ExpiredAt is set once the task's response window closed without an
+	// aggregate, and ExpiryTxHash when the expiry was submitted on chain
+	ExpiredAt    *time.Time   `json:"expiredAt,omitempty"`
+	ExpiryTxHash *common.Hash `json:"expiryTxHash,omitempty"`
+
+	// auctionTask is the task as the service manager created it, needed to
+	// submit its expiry. It is only known for tasks seen in a task event.
+	auctionTask *servicemanager.AuctionTask
+
+	// nonSignerStakesAndSignature is the checkSignatures argument submitted
+	// with the aggregated response
+	nonSignerStakesAndSignature *sigchecker.NonSignerStakesAndSignature
+
+	// referenceOperators is the operator set of the task's quorums at its
+	// creation block, which thresholds are measured against once loaded
+	referenceOperators []avsregistry.RegisteredOperator
+
+	// revision counts changes to the task's responses, result and
+	// submission, so checkpoints are only rewritten when the task has changed
+	revision             uint64
+	checkpointedRevision uint64
+	checkpointed         bool
+	// restored is set on tasks loaded from a checkpoint until they have been
+	// replayed
+	restored bool
+
+	// Result bundle publication to IPFS and on chain
+	bundlePublishing bool
+	bundleAttempts   int
+	blobPublishing   bool
+	blobAttempts     int
+
+	// challengeReviews counts the challenge reviews in progress, which keep
+	// the task in memory
+	challengeReviews int
+
+	// retryDigest is the bucket aggregated again on the next cleanup pass
+	// after its submission couldn't be built
+	retryDigest        *common.Hash
+	aggregationRetries int
 }
 
 type TaskResponse struct {
@@ -64,21 +484,47 @@ type TaskResponse struct {
 }
 
 type TaskResponseInfo struct {
-	TaskResponse TaskResponse        `json:"taskResponse"`
-	BlsSignature types.Signature     `json:"blsSignature"`
-	OperatorId   types.OperatorId    `json:"operatorId"`
+	TaskResponse   TaskResponse                 `json:"taskResponse"`
+	BlsSignature   bls.Signature                `json:"blsSignature"`
+	OperatorId     types.OperatorId             `json:"operatorId"`
+	Digest         common.Hash                  `json:"digest"`
+	StakePerQuorum map[types.QuorumNum]*big.Int `json:"stakePerQuorum,omitempty"`
+	CommitteeProof *bls.Signature               `json:"committeeProof,omitempty"`
 }
 
 type SignedTaskResponse struct {
-	TaskResponse TaskResponse        `json:"taskResponse"`
-	BlsSignature types.Signature     `json:"blsSignature"`
-	OperatorId   types.OperatorId    `json:"operatorId"`
+	TaskResponse TaskResponse     `json:"taskResponse"`
+	BlsSignature bls.Signature    `json:"blsSignature"`
+	OperatorId   types.OperatorId `json:"operatorId"`
+	// CommitteeProof is the operator's VRF ticket, required when committees
+	// are selected by VRF
+	CommitteeProof *bls.Signature `json:"committeeProof,omitempty"`
 }
 
 func NewAggregator(config Config, logger logging.Logger) (*Aggregator, error) {
+	config, err := applyNetworkPreset(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Keep recent errors for the diagnostics endpoint
+	errorRing := diagnostics.NewErrorRing(diagnostics.DefaultErrorRingSize)
+	logger = diagnostics.NewRecordingLogger(logger, errorRing)
 	logger = logger.With("component", "aggregator")
 
-	ethClient, err := eth.NewClient(config.EthRpcUrl)
+	rpcCache, err := newRpcCache(config)
+	if err != nil {
+		return nil, err
+	}
+	ethRpcUrl := config.EthRpcUrl
+	if rpcCache != nil {
+		ethRpcUrl = rpcCache.Url()
+	}
+
+	ethClient, err := eth.NewClient(ethRpcUrl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create eth client: %w", err)
 	}
@@ -94,9 +540,162 @@ func NewAggregator(config Config, logger logging.Logger) (*Aggregator, error) {
 		return nil, fmt.Errorf("failed to create avs registry chain reader: %w", err)
 	}
 
-	// For the writer, we'd need the aggregator's private key
-	// For now, we'll skip this as it requires key management
-	var avsWriter avsregistry.AvsRegistryChainWriter
+	params, scheduledParams, err := newParamSchedule(config)
+	if err != nil {
+		return nil, err
+	}
+
+	banList, err := NewBanList(config.BanListPath, config.AutoBanInvalidSignatures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ban list: %w", err)
+	}
+
+	taskRetention := defaultTaskRetention
+	if config.TaskRetention != "" {
+		taskRetention, err = time.ParseDuration(config.TaskRetention)
+		if err != nil {
+			return nil, fmt.Errorf("invalid task retention: %w", err)
+		}
+	}
+
+	operatorSetRefreshInterval := defaultOperatorSetRefreshInterval
+	if config.OperatorSetRefreshInterval != "" {
+		operatorSetRefreshInterval, err = time.ParseDuration(config.OperatorSetRefreshInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid operator set refresh interval: %w", err)
+		}
+	}
+
+	operatorLivenessWindow := defaultOperatorLivenessWindow
+	if config.OperatorLivenessWindow != "" {
+		operatorLivenessWindow, err = time.ParseDuration(config.OperatorLivenessWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid operator liveness window: %w", err)
+		}
+	}
+
+	apkTracker, err := quorumapk.NewTracker(
+		context.Background(),
+		avsReader,
+		common.HexToAddress(config.RegistryCoordinatorAddress),
+		ethClient,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create quorum apk tracker: %w", err)
+	}
+
+	sigBuilder, err := sigchecker.NewBuilder(
+		common.HexToAddress(config.OperatorStateRetrieverAddress),
+		common.HexToAddress(config.RegistryCoordinatorAddress),
+		ethClient,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	blsAggregation, err := newBlsAggregation(config, ethClient, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpointInterval := defaultCheckpointInterval
+	if config.CheckpointInterval != "" {
+		checkpointInterval, err = time.ParseDuration(config.CheckpointInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid checkpoint interval: %w", err)
+		}
+	}
+
+	// The running aggregator has to release the task store first
+	var inherited *inheritedState
+	if config.HandoverSocketPath != "" {
+		inherited, err = requestHandover(config.HandoverSocketPath, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var taskStore TaskStore
+	if config.TaskStorePath != "" {
+		taskStore, err = NewBoltTaskStore(config.TaskStorePath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var ipfsClient *ipfs.Client
+	if config.IpfsApiUrl != "" {
+		ipfsClient, err = ipfs.NewClient(config.IpfsApiUrl, config.IpfsAuthorization)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	notifier, err := newNotifier(config.Notifications, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	pools, err := loadPoolRegistry(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	submissionTxConfig, err := newSubmissionTxConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	chainIdCtx, cancelChainId := context.WithTimeout(context.Background(), chainIdTimeout)
+	chainId, err := networks.ResolveChainId(chainIdCtx, ethClient, config.Network, config.ChainId)
+	cancelChainId()
+	if err != nil {
+		return nil, err
+	}
+
+	aggregatorSigner, err := newAggregatorSigner(config)
+	if err != nil {
+		return nil, err
+	}
+
+	submissionSender, err := newSubmissionSender(config, ethClient, chainId, aggregatorSigner, submissionTxConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	userOpSender, err := newUserOpSender(config, ethClient, logger)
+	if err != nil {
+		return nil, err
+	}
+	ackSigner := aggregatorSigner
+	switch {
+	case aggregatorSigner == nil:
+		logger.Warn("No aggregator private key configured, task response acks are not signed")
+	case config.AggregatorSigner == remotesigner.KindWeb3Signer:
+		logger.Warn("Web3Signer can't sign acks or certificates, task response acks are not signed")
+		ackSigner = nil
+	}
+
+	blobs, err := newBlobPublisher(config, ethClient, aggregatorSigner, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var avsWriter *avsregistry.AvsRegistryChainWriter
+	if aggregatorSigner != nil {
+		avsWriter, err = avsregistry.NewAvsRegistryChainWriter(
+			common.HexToAddress(config.RegistryCoordinatorAddress),
+			common.HexToAddress(config.OperatorStateRetrieverAddress),
+			ethClient,
+			aggregatorSigner,
+			chainId,
+			registryWriterConfirmations,
+			logger,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create avs registry chain writer: %w", err)
+		}
+	}
 
 	// Create metrics registry
 	var metricsReg *prometheus.Registry
@@ -105,6 +704,62 @@ func NewAggregator(config Config, logger logging.Logger) (*Aggregator, error) {
 	} else {
 		metricsReg = prometheus.NewRegistry()
 	}
+	if rpcCache != nil {
+		metricsReg.MustRegister(rpcCache.Collector())
+	}
+
+	allowlist, err := newOperatorAllowlist(config, ethClient, metricsReg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	storeMaintenance, err := newStoreMaintenance(config, taskStore, taskRetention, metricsReg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	httpPolicy, err := newHttpPolicy(config)
+	if err != nil {
+		return nil, err
+	}
+
+	legacyApiSunset, err := parseLegacyApiSunset(config)
+	if err != nil {
+		return nil, err
+	}
+
+	poolLabeler, err := poolmetrics.NewLabeler(config.MetricsPoolAllowlist, config.MetricsMaxPools)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metrics pool allowlist: %w", err)
+	}
+
+	taskWatcher, err := newTaskWatcher(config, ethClient, metricsReg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	shadow, err := newShadowVerifier(config, ethClient, metricsReg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	challenges, err := newChallengeTracker(config, ethClient, metricsReg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	escrowReader, err := newEscrowReader(config, ethClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var syncer *taskSync
+	if config.ServiceManagerAddress != "" {
+		reader := servicemanager.NewReader(common.HexToAddress(config.ServiceManagerAddress), ethClient)
+		syncer = newTaskSync(reader, metricsReg)
+	} else {
+		logger.Warn("No service manager configured, task indices from operators are not verified")
+	}
 
 	aggregator := &Aggregator{
 		config:     config,
@@ -113,7 +768,75 @@ func NewAggregator(config Config, logger logging.Logger) (*Aggregator, error) {
 		metricsReg: metricsReg,
 		avsWriter:  avsWriter,
 		avsReader:  *avsReader,
-		tasks:      make(map[uint32]*TaskInfo),
+		params:     params,
+		banList:    banList,
+		allowlist:  allowlist,
+
+		participation: newParticipationTracker(config, metricsReg, logger),
+		shadow:        shadow,
+		challenges:    challenges,
+		taskWatcher:   taskWatcher,
+
+		signatureFailures: newSignatureFailures(metricsReg),
+		repeatedResponses: newRepeatedResponses(metricsReg),
+		taskSync:          syncer,
+
+		operators:                  newOperatorTracker(),
+		operatorSetRefreshInterval: operatorSetRefreshInterval,
+		operatorLivenessWindow:     operatorLivenessWindow,
+		apkTracker:                 apkTracker,
+		sigBuilder:                 sigBuilder,
+		blsAggregation:             blsAggregation,
+		operatorHub:                newOperatorHub(metricsReg),
+		eventHub:                   newEventHub(metricsReg),
+		submissionTxConfig:         submissionTxConfig,
+		submissionSender:           submissionSender,
+		userOpSender:               userOpSender,
+		ackSigner:                  ackSigner,
+		escrow:                     escrowReader,
+		bidBook:                    newBidBook(config, metricsReg),
+		pools:                      pools,
+
+		taskRetention:      taskRetention,
+		taskStore:          taskStore,
+		checkpointInterval: checkpointInterval,
+		storeMaintenance:   storeMaintenance,
+		ipfs:               ipfsClient,
+		blobs:              blobs,
+		notifier:           notifier,
+		diagnostics:        diagnostics.NewCollector("eigenlvr-aggregator", SemVer, errorRing),
+		watchdog:           sdnotify.NewWatchdog(),
+		metrics:            newTaskMetrics(poolLabeler, metricsReg),
+		httpPolicy:         httpPolicy,
+		legacyApiSunset:    legacyApiSunset,
+		tasks:              make(map[uint32]*TaskInfo),
+	}
+
+	aggregator.grpc = newGrpcServer(aggregator)
+
+	if taskStore != nil {
+		if err := aggregator.restoreCheckpoints(); err != nil {
+			taskStore.Close()
+			return nil, fmt.Errorf("failed to restore aggregation checkpoints: %w", err)
+		}
+	}
+	if inherited != nil {
+		aggregator.takeOver(inherited)
+	}
+
+	aggregator.diagnostics.RegisterDepth("tasks", func() diagnostics.Depth {
+		aggregator.tasksMutex.RLock()
+		defer aggregator.tasksMutex.RUnlock()
+		return diagnostics.Depth{Len: len(aggregator.tasks)}
+	})
+	aggregator.diagnostics.RegisterDepth("openTasks", func() diagnostics.Depth {
+		aggregator.tasksMutex.RLock()
+		defer aggregator.tasksMutex.RUnlock()
+		return diagnostics.Depth{Len: aggregator.openTaskCount(), Cap: aggregator.maxOpenTasks()}
+	})
+
+	if scheduledParams != nil {
+		aggregator.announceParamUpdate("scheduled", *scheduledParams)
 	}
 
 	return aggregator, nil
@@ -122,51 +845,254 @@ func NewAggregator(config Config, logger logging.Logger) (*Aggregator, error) {
 func (a *Aggregator) Start(ctx context.Context) error {
 	a.logger.Info("Starting aggregator")
 
+	// A handover stops the aggregator without the caller
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	// A VRF committee too small to meet the thresholds would miss nearly
+	// every task, so refuse to start with one
+	if a.config.CommitteeVrfExpectedSize > 0 {
+		if err := a.refreshOperatorSet(ctx); err != nil {
+			return fmt.Errorf("failed to read the operator set to size committees: %w", err)
+		}
+		if err := a.checkCommitteeCoverage(a.registeredOperators()); err != nil {
+			return err
+		}
+	}
+
 	// Start HTTP server for receiving operator responses
-	go a.startHttpServer()
+	listener, err := a.httpListener()
+	if err != nil {
+		return err
+	}
+	a.httpServer = a.newHttpServer()
+	go a.serveHttp(listener)
+
+	// Serve the task pipeline metrics for scraping
+	if a.config.EnableMetrics {
+		go a.serveMetrics(ctx)
+	}
 
 	// Start task processing
 	go a.processAggregatedTasks(ctx)
+	go a.replayRestoredTasks(ctx)
+	if a.blsAggregation != nil {
+		go a.blsAggregation.Run(ctx, a.recordBlsAggregation)
+	}
+
+	// Serve operators using the gRPC interface
+	if a.grpc != nil {
+		go func() {
+			if err := a.grpc.serve(ctx); err != nil {
+				a.logger.Error("gRPC server failed", "error", err)
+			}
+		}()
+	}
+
+	if a.notifier != nil {
+		go a.notifier.Run(ctx)
+	}
 
 	// Start listening for new tasks from the service manager
 	go a.listenForNewTasks(ctx)
 
+	// Keep the registered operator set up to date for GET /operators
+	go a.watchOperatorSet(ctx)
+
+	// Apply runtime param updates once their delay has passed
+	go a.watchParamUpdates(ctx)
+
+	// Compare results with the primary aggregator's when watch-only
+	if a.shadow != nil {
+		go a.watchPrimaryResponses(ctx)
+	}
+
+	// Review the challenges raised on chain against aggregated responses
+	if a.challenges.watcher != nil {
+		go a.watchChallenges(ctx)
+	}
+
+	// Checkpoint open tasks so a restart doesn't lose collected responses
+	checkpointDone := make(chan struct{})
+	if a.taskStore != nil {
+		go func() {
+			a.checkpointLoop(ctx)
+			close(checkpointDone)
+		}()
+	} else {
+		close(checkpointDone)
+	}
+
+	// Prune and compact the task store, which must be done before it closes
+	maintenanceDone := make(chan struct{})
+	if a.storeMaintenance != nil {
+		go func() {
+			a.storeMaintenance.run(ctx)
+			close(maintenanceDone)
+		}()
+	} else {
+		close(maintenanceDone)
+	}
+
+	// Pet the systemd watchdog while the task loop is healthy
+	go a.watchdog.Run(ctx, a.logger)
+
+	// Hand over to a new aggregator when one asks
+	storeClosed := make(chan struct{})
+	handoverDone := make(chan struct{})
+	if a.config.HandoverSocketPath != "" {
+		go func() {
+			a.serveHandover(ctx, stop, listener, storeClosed)
+			close(handoverDone)
+		}()
+	} else {
+		close(handoverDone)
+	}
+
 	// Keep the aggregator running
 	<-ctx.Done()
+	sdnotify.Stopping()
+	<-checkpointDone
+	<-maintenanceDone
+
+	if a.taskStore != nil {
+		if err := a.taskStore.Close(); err != nil {
+			a.logger.Error("Failed to close task store", "error", err)
+		}
+	}
+	close(storeClosed)
+	<-handoverDone
 	return nil
 }
 
-func (a *Aggregator) startHttpServer() {
+// httpListener returns the listener taken over from the previous aggregator,
+// or a new one on ServerIpPortAddr
+func (a *Aggregator) httpListener() (net.Listener, error) {
+	if a.inheritedListener != nil {
+		return a.inheritedListener, nil
+	}
+	listener, err := net.Listen("tcp", a.config.ServerIpPortAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", a.config.ServerIpPortAddr, err)
+	}
+	return listener, nil
+}
+
+func (a *Aggregator) newHttpServer() *http.Server {
 	router := mux.NewRouter()
-	
-	// Health check endpoint
+
+	// Accept gzip and zstd compressed request bodies from operators
+	router.Use(compression.NewMiddleware(a.maxRequestBodyBytes()))
+
+	// Health check and version discovery stay outside any version prefix
 	router.HandleFunc("/health", a.healthHandler).Methods("GET")
-	
-	// Task response endpoint
-	router.HandleFunc("/task-response", a.taskResponseHandler).Methods("POST")
-	
-	// Task status endpoint
-	router.HandleFunc("/task/{taskIndex}", a.taskStatusHandler).Methods("GET")
+	router.HandleFunc("/versions", a.versionsHandler).Methods("GET")
 
-	a.httpServer = &http.Server{
+	// Current API
+	a.registerApiRoutes(router.PathPrefix(apiversion.Path("")).Subrouter())
+
+	// The original unversioned routes, kept for operators that predate /v1
+	if !a.config.DisableLegacyApi {
+		legacy := router.NewRoute().Subrouter()
+		legacy.Use(a.deprecatedApi)
+		a.registerApiRoutes(legacy)
+	}
+
+	// Runtime diagnostics, behind the admin token when one is configured
+	var debugStatus http.Handler = http.HandlerFunc(a.diagnostics.Handler)
+	if a.config.AdminApiToken != "" {
+		debugStatus = a.requireAdminToken(debugStatus)
+	}
+	router.Handle("/debug/status", debugStatus).Methods("GET")
+
+	return &http.Server{
 		Addr:    a.config.ServerIpPortAddr,
-		Handler: router,
+		Handler: a.httpPolicy.Handler(withApiVersion(router)),
+	}
+}
+
+func (a *Aggregator) serveHttp(listener net.Listener) {
+	a.logger.Info("Starting HTTP server", "address", listener.Addr().String())
+
+	// Operators can reach the aggregator from here on
+	if err := sdnotify.Ready(); err != nil {
+		a.logger.Warn("Failed to notify systemd of readiness", "error", err)
 	}
 
-	a.logger.Info("Starting HTTP server", "address", a.config.ServerIpPortAddr)
-	if err := a.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if err := a.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
 		a.logger.Error("HTTP server error", "error", err)
 	}
 }
 
+// registerApiRoutes adds the versioned API to router
+func (a *Aggregator) registerApiRoutes(router *mux.Router) {
+	// Task response endpoint
+	router.HandleFunc("/task-response", a.taskResponseHandler).Methods("POST")
+
+	// Task status endpoint
+	router.HandleFunc("/task/{taskIndex}", a.taskStatusHandler).Methods("GET")
+
+	// Inclusion receipt of an operator's response
+	router.HandleFunc("/task/{taskIndex}/inclusion/{operatorId}", a.inclusionReceiptHandler).Methods("GET")
+
+	// Signed auction certificate of an aggregated task
+	router.HandleFunc("/task/{taskIndex}/certificate", a.certificateHandler).Methods("GET")
+
+	// The operator set, stakes and APKs a task's aggregate was evaluated against
+	router.HandleFunc("/task/{taskIndex}/operator-set", a.operatorSetSnapshotHandler).Methods("GET")
+
+	// Challenges against a task's aggregate, and raising one during its window
+	router.HandleFunc("/task/{taskIndex}/challenges", a.challengesHandler).Methods("GET")
+	router.HandleFunc("/task/{taskIndex}/challenge", a.challengeHandler).Methods("POST")
+
+	// Tasks in memory, filtered by status and pool
+	router.HandleFunc("/tasks", a.tasksHandler).Methods("GET")
+
+	// Completed tasks that have left memory, served from the task store
+	router.HandleFunc("/tasks/history", a.tasksHistoryHandler).Methods("GET")
+
+	// Sealed auction bids from searchers, their reveals, and each auction's bid book
+	router.HandleFunc("/bid/commit", a.bidCommitHandler).Methods("POST")
+	router.HandleFunc("/bid", a.bidHandler).Methods("POST")
+	router.HandleFunc("/bids/{poolId}/{blockNumber}", a.bidsHandler).Methods("GET")
+
+	// The pools auctions run for and their parameters
+	router.HandleFunc("/pools", a.poolsHandler).Methods("GET")
+
+	// Persistent operator connection for task pushes and responses
+	router.HandleFunc(wsproto.Path, a.operatorWsHandler).Methods("GET")
+
+	// Task lifecycle events pushed to dashboards, filtered by pool
+	router.HandleFunc(eventStreamPath, a.eventStreamHandler).Methods("GET")
+
+	// Operator set with registration and liveness info
+	router.HandleFunc("/operators", a.operatorsHandler).Methods("GET")
+
+	// Operators accepted while the aggregator is permissioned
+	router.HandleFunc("/allowlist", a.allowlistHandler).Methods("GET")
+
+	// Runtime params with the updates announced on them
+	router.HandleFunc("/params", a.paramsHandler).Methods("GET")
+
+	// Admin endpoints
+	a.registerAdminRoutes(router)
+}
+
 func (a *Aggregator) healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
 }
 
 func (a *Aggregator) taskResponseHandler(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, a.maxRequestBodyBytes())
+
 	var signedResponse SignedTaskResponse
 	if err := json.NewDecoder(r.Body).Decode(&signedResponse); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
 		a.logger.Error("Failed to decode task response", "error", err)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
@@ -174,181 +1100,584 @@ func (a *Aggregator) taskResponseHandler(w http.ResponseWriter, r *http.Request)
 
 	a.logger.Info("Received task response",
 		"taskIndex", signedResponse.TaskResponse.ReferenceTaskIndex,
-		"operatorId", signedResponse.OperatorId.String(),
+		"operatorId", formatOperatorId(signedResponse.OperatorId),
 		"winner", signedResponse.TaskResponse.Winner.Hex(),
 		"winningBid", signedResponse.TaskResponse.WinningBid.String(),
 	)
 
 	// Process the task response
-	if err := a.processTaskResponse(signedResponse); err != nil {
+	responseDigest, err := a.processTaskResponse(r.Context(), signedResponse, false)
+	if err != nil {
+		if errors.Is(err, ErrOperatorBanned) || errors.Is(err, ErrOperatorNotAllowlisted) || errors.Is(err, ErrOperatorNotRegistered) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, ErrUnknownTask) {
+			a.logger.Warn("Rejected task response", "error", err)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, ErrInvalidSignature) || errors.Is(err, ErrSignatureRejected) || errors.Is(err, ErrNotInCommittee) {
+			a.logger.Warn("Rejected task response", "error", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrConflictingResponse) || errors.Is(err, ErrTaskCompleted) || errors.Is(err, ErrTaskExpired) {
+			a.logger.Warn("Rejected task response", "error", err)
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, ErrTooManyOpenTasks) || errors.Is(err, ErrTooManyResponses) {
+			a.logger.Warn("Rejected task response", "error", err)
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
 		a.logger.Error("Failed to process task response", "error", err)
 		http.Error(w, "Failed to process response", http.StatusInternalServerError)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "accepted"})
-}
-
-func (a *Aggregator) taskStatusHandler(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	taskIndex := vars["taskIndex"]
+	signedAck, err := a.signAck(r.Context(), signedResponse, responseDigest)
+	if err != nil {
+		// The response is already accepted, so only the ack is lost
+		a.logger.Error("Failed to sign task response ack", "error", err)
+	}
 
-	// Convert taskIndex to uint32 and get task info
-	// For simplicity, we'll just return a status
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"taskIndex": taskIndex,
-		"status":    "processing",
+	json.NewEncoder(w).Encode(TaskResponseAccepted{
+		Status: "accepted",
+		Ack:    signedAck,
 	})
 }
 
-func (a *Aggregator) processTaskResponse(signedResponse SignedTaskResponse) error {
+// processTaskResponse adds the response to its task and returns the digest the
+// operator signed. authenticated is whether the sender proved it is the
+// response's operator, as WebSocket sessions do when they log in.
+func (a *Aggregator) processTaskResponse(ctx context.Context, signedResponse SignedTaskResponse, authenticated bool) (common.Hash, error) {
 	taskIndex := signedResponse.TaskResponse.ReferenceTaskIndex
 
+	if a.banList.IsBanned(signedResponse.OperatorId) {
+		return common.Hash{}, ErrOperatorBanned
+	}
+	if err := a.checkAllowlist(signedResponse.OperatorId); err != nil {
+		return common.Hash{}, err
+	}
+
+	// Responses are grouped by the exact digest the operator signed, so only
+	// byte-identical responses are ever aggregated together
+	responseDigest, err := digest.AuctionTaskResponseDigest(
+		signedResponse.TaskResponse.ReferenceTaskIndex,
+		signedResponse.TaskResponse.Winner,
+		signedResponse.TaskResponse.WinningBid,
+		signedResponse.TaskResponse.TotalBids,
+	)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to compute task response digest: %w", err)
+	}
+
+	// Only responses signed by the operator's registered key are recorded. The
+	// signature covers every field of the response, so it also authenticates
+	// the request.
+	if err := a.verifyResponseSignature(signedResponse, responseDigest, authenticated); err != nil {
+		return common.Hash{}, err
+	}
+
+	// Each operator answers a task once, before it completes
+	duplicate, err := a.precheckRepeatedResponse(taskIndex, signedResponse.OperatorId, responseDigest)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if duplicate {
+		return responseDigest, nil
+	}
+
+	// Only operators registered in the task's quorums when it was created may
+	// respond, and that stake is what counts toward the stake floor
+	stakePerQuorum, err := a.registeredStake(ctx, signedResponse.OperatorId, taskIndex)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	// Tasks are only opened for indices the service manager has created, so
+	// operators can't invent or collide task numbers
+	var taskHash common.Hash
+	if a.taskSync != nil {
+		a.tasksMutex.RLock()
+		task, exists := a.tasks[taskIndex]
+		var knownHash common.Hash
+		var quorums types.QuorumNums
+		if exists {
+			knownHash = task.TaskHash
+			quorums = a.taskQuorums(task)
+		}
+		a.tasksMutex.RUnlock()
+
+		if !exists {
+			taskHash, err = a.taskSync.verifyTask(ctx, taskIndex)
+			if err != nil {
+				return common.Hash{}, err
+			}
+			knownHash = taskHash
+			quorums = a.apkTracker.Quorums()
+		}
+
+		if err := a.checkCommitteeProof(signedResponse, knownHash, quorums); err != nil {
+			return common.Hash{}, err
+		}
+	}
+
+	// The blsagg backend verifies the signature against the operator's
+	// registered key before the response is recorded. The builtin one
+	// measures signed stake against the stake at the task's creation block.
+	if a.blsAggregation != nil {
+		if err := a.submitToBlsAggregation(ctx, signedResponse); err != nil {
+			return common.Hash{}, err
+		}
+	} else {
+		a.loadReferenceStakes(ctx, taskIndex)
+	}
+
 	a.tasksMutex.Lock()
 	defer a.tasksMutex.Unlock()
 
 	task, exists := a.tasks[taskIndex]
 	if !exists {
+		if a.openTaskCount() >= a.maxOpenTasks() {
+			return common.Hash{}, ErrTooManyOpenTasks
+		}
+		// The task was cleaned up between the check above and taking the lock
+		if a.taskSync != nil && taskHash == (common.Hash{}) {
+			return common.Hash{}, fmt.Errorf("%w: index %d is no longer tracked", ErrUnknownTask, taskIndex)
+		}
+
 		// Create new task if it doesn't exist
 		task = &TaskInfo{
 			TaskIndex:         taskIndex,
+			TaskHash:          taskHash,
 			TaskResponses:     make(map[types.OperatorId]TaskResponse),
 			TaskResponsesInfo: make(map[types.OperatorId]TaskResponseInfo),
 			IsCompleted:       false,
-			CreatedAt:        time.Now(),
+			CreatedAt:         time.Now(),
 		}
 		a.tasks[taskIndex] = task
+		a.metrics.taskOpened(task.PoolId)
+	}
+
+	if task.ExpiredAt != nil {
+		return common.Hash{}, fmt.Errorf("%w: task %d", ErrTaskExpired, taskIndex)
+	}
+	duplicate, err = a.checkRepeatedResponse(task, signedResponse.OperatorId, responseDigest)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if duplicate {
+		return responseDigest, nil
+	}
+	if len(task.TaskResponsesInfo) >= a.maxResponsesPerTask() {
+		return common.Hash{}, ErrTooManyResponses
 	}
 
 	// Add the response
 	task.TaskResponses[signedResponse.OperatorId] = signedResponse.TaskResponse
 	task.TaskResponsesInfo[signedResponse.OperatorId] = TaskResponseInfo{
-		TaskResponse: signedResponse.TaskResponse,
-		BlsSignature: signedResponse.BlsSignature,
-		OperatorId:   signedResponse.OperatorId,
+		TaskResponse:   signedResponse.TaskResponse,
+		BlsSignature:   signedResponse.BlsSignature,
+		OperatorId:     signedResponse.OperatorId,
+		Digest:         responseDigest,
+		StakePerQuorum: stakePerQuorum,
+		CommitteeProof: signedResponse.CommitteeProof,
 	}
+	task.revision++
+	a.metrics.responseReceived(task.PoolId)
+	digest := common.Hash(responseDigest)
+	a.publishTaskEvent(TaskEvent{
+		Type:       TaskEventResponseReceived,
+		TaskIndex:  taskIndex,
+		PoolId:     task.PoolId,
+		OperatorId: formatOperatorId(signedResponse.OperatorId),
+		Responses:  len(task.TaskResponsesInfo),
+		Digest:     &digest,
+	})
+
+	a.operators.recordResponse(signedResponse.OperatorId, taskIndex)
 
 	a.logger.Info("Task response added",
 		"taskIndex", taskIndex,
+		"digest", common.Hash(responseDigest).Hex(),
 		"totalResponses", len(task.TaskResponses),
 	)
 
-	// Check if we have enough responses to aggregate
-	if a.shouldAggregateTask(task) {
-		go a.aggregateAndSubmitTask(task)
+	if digests := task.responseDigests(); len(digests) > 1 {
+		a.logger.Warn("Conflicting task responses received",
+			"taskIndex", taskIndex,
+			"distinctDigests", len(digests),
+		)
 	}
 
-	return nil
+	// Check if the bucket this response landed in has enough responses to aggregate.
+	// The task is marked completed here, under the lock, so a bucket is only
+	// ever aggregated once. The blsagg backend reports completion on its own.
+	bucket := task.responsesWithDigest(responseDigest)
+	if a.blsAggregation == nil && a.shouldAggregateTask(task, bucket) {
+		task.IsCompleted = true
+		task.revision++
+		go a.aggregateAndSubmitTask(task, responseDigest, bucket)
+	}
+
+	return responseDigest, nil
+}
+
+// openTaskCount returns the number of tasks that have not completed yet.
+// Callers must hold tasksMutex.
+func (a *Aggregator) openTaskCount() int {
+	count := 0
+	for _, task := range a.tasks {
+		if !task.IsCompleted {
+			count++
+		}
+	}
+	return count
 }
 
-func (a *Aggregator) shouldAggregateTask(task *TaskInfo) bool {
-	// Simple threshold: aggregate when we have at least 2 responses
-	// In a real implementation, this would check against quorum requirements
-	return len(task.TaskResponses) >= 2 && !task.IsCompleted
+func (a *Aggregator) minOperators() int {
+	if minOperators := a.params.MinOperators(); minOperators > 0 {
+		return minOperators
+	}
+	return defaultMinOperators
+}
+
+func (a *Aggregator) maxOpenTasks() int {
+	if a.config.MaxOpenTasks > 0 {
+		return a.config.MaxOpenTasks
+	}
+	return defaultMaxOpenTasks
 }
 
-func (a *Aggregator) aggregateAndSubmitTask(task *TaskInfo) {
-	a.logger.Info("Aggregating task responses", "taskIndex", task.TaskIndex)
+func (a *Aggregator) maxResponsesPerTask() int {
+	if a.config.MaxResponsesPerTask > 0 {
+		return a.config.MaxResponsesPerTask
+	}
+	return defaultMaxResponsesPerTask
+}
 
-	// Simple aggregation: find the most common winner and highest bid
-	winnerVotes := make(map[common.Address]int)
-	highestBid := big.NewInt(0)
-	var finalWinner common.Address
-	totalBids := uint32(0)
+func (a *Aggregator) maxRequestBodyBytes() int64 {
+	if a.config.MaxRequestBodyBytes > 0 {
+		return a.config.MaxRequestBodyBytes
+	}
+	return defaultMaxRequestBodyBytes
+}
 
-	for _, response := range task.TaskResponses {
-		winnerVotes[response.Winner]++
-		if response.WinningBid.Cmp(highestBid) > 0 {
-			highestBid = response.WinningBid
+// responsesWithDigest returns the responses whose digest matches the given one
+func (t *TaskInfo) responsesWithDigest(responseDigest common.Hash) []TaskResponseInfo {
+	var responses []TaskResponseInfo
+	for _, responseInfo := range t.TaskResponsesInfo {
+		if responseInfo.Digest == responseDigest {
+			responses = append(responses, responseInfo)
 		}
-		totalBids += response.TotalBids
 	}
+	return responses
+}
+
+// responseDigests returns the number of responses received for each distinct digest
+func (t *TaskInfo) responseDigests() map[common.Hash]int {
+	digests := make(map[common.Hash]int)
+	for _, responseInfo := range t.TaskResponsesInfo {
+		digests[responseInfo.Digest]++
+	}
+	return digests
+}
+
+// shouldAggregateTask reports whether a bucket of responses over the same digest
+// meets the aggregation thresholds
+func (a *Aggregator) shouldAggregateTask(task *TaskInfo, bucket []TaskResponseInfo) bool {
+	if task.IsCompleted {
+		return false
+	}
+
+	// A recorded result is final: once its task is reopened to retry the
+	// submission, only the result's own bucket may be aggregated again
+	if task.AggregatedDigest != nil && len(bucket) > 0 && bucket[0].Digest != *task.AggregatedDigest {
+		return false
+	}
+
+	return a.meetsAggregationThresholds(task, bucket)
+}
+
+// meetsAggregationThresholds reports whether the bucket has enough operators
+// and stake behind it, whether or not its task is completed
+func (a *Aggregator) meetsAggregationThresholds(task *TaskInfo, bucket []TaskResponseInfo) bool {
+	// Require a minimum number of distinct operators so a small quorum can't be
+	// decided by one or two responders
+	if len(bucket) < a.minOperators() {
+		return false
+	}
+
+	// Require a minimum amount of stake behind the responses
+	if minTotalStake := a.params.MinTotalStake(); minTotalStake.Sign() > 0 && a.signedStake(task, bucket).Cmp(minTotalStake) < 0 {
+		return false
+	}
+
+	// Every quorum must reach its own stake threshold
+	return a.quorumThresholdsMet(task, bucket)
+}
 
-	// Find winner with most votes
-	maxVotes := 0
-	for winner, votes := range winnerVotes {
-		if votes > maxVotes {
-			maxVotes = votes
-			finalWinner = winner
+// signedStake sums the stake of the given responders across the task's quorums,
+// or across all of each operator's quorums if the task has none recorded
+func (a *Aggregator) signedStake(task *TaskInfo, responses []TaskResponseInfo) *big.Int {
+	total := big.NewInt(0)
+	for _, responseInfo := range responses {
+		for quorum, stake := range responseInfo.StakePerQuorum {
+			if len(task.QuorumNumbers) > 0 && !containsQuorum(task.QuorumNumbers, quorum) {
+				continue
+			}
+			total.Add(total, stake)
 		}
 	}
+	return total
+}
 
-	aggregatedResponse := TaskResponse{
-		ReferenceTaskIndex: task.TaskIndex,
-		Winner:             finalWinner,
-		WinningBid:         highestBid,
-		TotalBids:          totalBids / uint32(len(task.TaskResponses)), // Average
+func containsQuorum(quorumNumbers types.QuorumNums, quorum types.QuorumNum) bool {
+	for _, q := range quorumNumbers {
+		if q == quorum {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Aggregator) aggregateAndSubmitTask(task *TaskInfo, responseDigest common.Hash, responses []TaskResponseInfo) {
+	a.logger.Info("Aggregating task responses",
+		"taskIndex", task.TaskIndex,
+		"digest", responseDigest.Hex(),
+	)
+
+	// Every response in the bucket signed the same digest, so the aggregated
+	// response is exactly what each of the signers signed
+	aggregatedResponse := responses[0].TaskResponse
+
+	// Each quorum gets its own aggregate over its own members, as multi-quorum
+	// service managers check every quorum separately. The operator set is
+	// snapshotted so the decision can be reproduced later.
+	a.tasksMutex.RLock()
+	operatorSet := a.snapshotOperatorSet(task)
+	a.tasksMutex.RUnlock()
+	quorumAggregates := aggregateQuorums(operatorSet, responses)
+
+	signerSet := make(map[types.OperatorId]struct{}, len(responses))
+	for _, aggregate := range quorumAggregates {
+		for _, signer := range aggregate.Signers {
+			signerSet[signer] = struct{}{}
+		}
+	}
+	signers := make([]types.OperatorId, 0, len(signerSet))
+	for _, responseInfo := range responses {
+		if _, ok := signerSet[responseInfo.OperatorId]; ok {
+			signers = append(signers, responseInfo.OperatorId)
+		}
 	}
 
 	a.logger.Info("Aggregated task response",
 		"taskIndex", task.TaskIndex,
-		"winner", finalWinner.Hex(),
-		"winningBid", highestBid.String(),
-		"totalResponses", len(task.TaskResponses),
+		"winner", aggregatedResponse.Winner.Hex(),
+		"winningBid", aggregatedResponse.WinningBid.String(),
+		"totalBids", aggregatedResponse.TotalBids,
+		"signers", len(signers),
+		"quorums", len(quorumAggregates),
 	)
 
-	// Mark task as completed
+	// Check every quorum's aggregate the way the contract will, so an
+	// aggregate that would revert never costs gas
+	for _, aggregate := range quorumAggregates {
+		err := a.apkTracker.VerifyAggregate(
+			types.QuorumNums{aggregate.QuorumNumber},
+			aggregate.Signers,
+			&aggregate.AggregateSignature,
+			responseDigest,
+		)
+		if err != nil {
+			a.logger.Error("Quorum aggregate signature failed pre-verification, not submitting",
+				"taskIndex", task.TaskIndex,
+				"quorum", aggregate.QuorumNumber,
+				"digest", responseDigest.Hex(),
+				"error", err,
+			)
+			a.metrics.aggregated(task.PoolId, aggregationResultVerificationFailed, task.CreatedAt)
+			a.reopenTask(task, nil)
+			return
+		}
+	}
+
+	// checkSignatures also needs the non-signers and history indices, without
+	// which the aggregate can't be submitted
+	ctx, cancel := context.WithTimeout(context.Background(), nonSignerStakesTimeout)
+	nonSignerStakesAndSignature, err := a.buildNonSignerStakesAndSignature(ctx, task, signers, responses)
+	cancel()
+	if err != nil {
+		a.logger.Error("Failed to build non-signer stakes and signature, not submitting",
+			"taskIndex", task.TaskIndex,
+			"error", err,
+		)
+		a.metrics.aggregated(task.PoolId, aggregationResultCalldataFailed, task.CreatedAt)
+		// Registry reads fail transiently, so the same bucket is tried again
+		a.reopenTask(task, &responseDigest)
+		return
+	}
+	a.metrics.aggregated(task.PoolId, aggregationResultAggregated, task.CreatedAt)
+	a.metrics.quorumsSigned(task.PoolId, quorumAggregates)
+
+	// Record the final result and its signers so they stay in the task history
+	// and operators can query their inclusion
 	a.tasksMutex.Lock()
-	task.IsCompleted = true
+	// A resubmitted result has had its outliers reported already
+	firstResult := task.AggregatedDigest == nil
+	task.AggregatedResponse = &aggregatedResponse
+	task.AggregatedDigest = &responseDigest
+	task.Signers = signers
+	task.QuorumAggregates = quorumAggregates
+	task.OperatorSetSnapshot = operatorSet
+	task.nonSignerStakesAndSignature = &nonSignerStakesAndSignature
+	// The aggregate can be contested until its challenge window closes
+	challengeDeadline := time.Now().Add(a.challenges.window)
+	task.ChallengeDeadline = &challengeDeadline
+	task.revision++
+	a.compareWithPrimary(task)
+	if firstResult {
+		a.reportOutliers(task)
+		a.publishTaskEvent(TaskEvent{
+			Type:         TaskEventQuorumReached,
+			TaskIndex:    task.TaskIndex,
+			PoolId:       task.PoolId,
+			Digest:       &responseDigest,
+			TaskResponse: &aggregatedResponse,
+			Signers:      len(signers),
+		})
+	}
 	a.tasksMutex.Unlock()
 
-	// In a real implementation, this would:
-	// 1. Verify BLS signatures
-	// 2. Check quorum requirements
-	// 3. Submit aggregated response to service manager
-	// 4. Handle potential challenges
-
 	a.logger.Info("Task aggregation completed", "taskIndex", task.TaskIndex)
+	a.notifyAuctionOutcome(task, aggregatedResponse, len(signers))
+	a.submitTask(task)
+}
+
+// reopenTask undoes the completion of a task whose aggregate can't be
+// submitted, so it is aggregated again from later responses or expires once
+// its response window closes rather than staying completed without a result.
+// With a retry digest, that bucket is aggregated again on a later cleanup pass.
+func (a *Aggregator) reopenTask(task *TaskInfo, retryDigest *common.Hash) {
+	a.tasksMutex.Lock()
+	defer a.tasksMutex.Unlock()
+
+	task.IsCompleted = false
+	task.retryDigest = retryDigest
+	task.revision++
+	a.logger.Warn("Reopened task after a failed aggregation", "taskIndex", task.TaskIndex, "retry", retryDigest != nil)
+}
+
+// retryAggregations aggregates the buckets of reopened tasks again, each
+// task up to maxAggregationRetries times. Callers must hold the tasks lock.
+func (a *Aggregator) retryAggregations() {
+	for _, task := range a.tasks {
+		if task.retryDigest == nil || task.IsCompleted || task.ExpiredAt != nil {
+			continue
+		}
+		responseDigest := *task.retryDigest
+		task.retryDigest = nil
+		if task.aggregationRetries >= maxAggregationRetries {
+			a.logger.Warn("Not retrying task aggregation again, leaving the task to expire",
+				"taskIndex", task.TaskIndex,
+				"retries", task.aggregationRetries,
+			)
+			continue
+		}
+		bucket := task.responsesWithDigest(responseDigest)
+		if !a.shouldAggregateTask(task, bucket) {
+			continue
+		}
+
+		a.logger.Info("Retrying task aggregation", "taskIndex", task.TaskIndex, "digest", responseDigest.Hex())
+		task.aggregationRetries++
+		task.IsCompleted = true
+		task.revision++
+		go a.aggregateAndSubmitTask(task, responseDigest, bucket)
+	}
 }
 
 func (a *Aggregator) processAggregatedTasks(ctx context.Context) {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
+	a.watchdog.Beat("taskCleanup")
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			a.cleanupOldTasks()
+			// Cleanup takes the tasks lock, so a deadlock there stalls the watchdog
+			a.cleanupOldTasks(ctx)
+			a.watchdog.Beat("taskCleanup")
 		}
 	}
 }
 
-func (a *Aggregator) cleanupOldTasks() {
+// cleanupOldTasks removes tasks older than the retention period from memory.
+// Incomplete tasks still inside their response window are kept, and tasks
+// are archived rather than dropped when a task store is configured.
+func (a *Aggregator) cleanupOldTasks(ctx context.Context) {
+	// Without the current block the response windows are unknown, so only
+	// completed tasks can be removed safely
+	currentBlock, err := a.ethClient.BlockNumber(ctx)
+	if err != nil {
+		a.logger.Warn("Failed to get current block for task cleanup", "error", err)
+		currentBlock = 0
+	}
+
 	a.tasksMutex.Lock()
 	defer a.tasksMutex.Unlock()
 
-	cutoff := time.Now().Add(-1 * time.Hour) // Clean tasks older than 1 hour
-	
-	for taskIndex, task := range a.tasks {
-		if task.CreatedAt.Before(cutoff) {
-			delete(a.tasks, taskIndex)
-			a.logger.Debug("Cleaned up old task", "taskIndex", taskIndex)
-		}
+	if currentBlock != 0 {
+		a.expireTasks(currentBlock)
 	}
-}
-
-func (a *Aggregator) listenForNewTasks(ctx context.Context) {
-	a.logger.Info("Starting to listen for new tasks")
+	a.retryAggregations()
+	cutoff := time.Now().Add(-a.taskRetention)
 
-	// In a real implementation, this would:
-	// 1. Subscribe to NewAuctionTaskCreated events from service manager
-	// 2. Initialize task tracking
-	// 3. Set up timeouts for task responses
+	for taskIndex, task := range a.tasks {
+		if !task.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if !task.IsCompleted && (currentBlock == 0 || uint64(task.TaskCreatedBlock)+taskResponseWindowBlocks >= currentBlock) {
+			continue
+		}
+		// Both publications start on the same pass
+		heldForBundle := a.holdForBundle(task)
+		heldForBlobs := a.holdForBlobs(task)
+		if heldForBundle || heldForBlobs || task.challengeReviews > 0 {
+			continue
+		}
 
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
+		if a.taskStore != nil {
+			if err := a.taskStore.ArchiveTask(newArchivedTask(task)); err != nil {
+				a.logger.Error("Failed to archive task, keeping it in memory", "taskIndex", taskIndex, "error", err)
+				continue
+			}
+			if task.checkpointed {
+				if err := a.taskStore.DeleteCheckpoint(taskIndex); err != nil {
+					a.logger.Warn("Failed to delete task checkpoint", "taskIndex", taskIndex, "error", err)
+				}
+			}
+		}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			a.logger.Debug("Listening for new auction tasks...")
+		a.recordParticipation(task)
+		a.checkPrimarySubmitted(task)
+		delete(a.tasks, taskIndex)
+		if a.blsAggregation != nil {
+			a.blsAggregation.Forget(taskIndex)
 		}
+		a.logger.Debug("Cleaned up old task",
+			"taskIndex", taskIndex,
+			"completed", task.IsCompleted,
+			"expired", task.ExpiredAt != nil,
+			"archived", a.taskStore != nil,
+		)
 	}
 }
 
@@ -356,7 +1685,7 @@ func (a *Aggregator) listenForNewTasks(ctx context.Context) {
 func (a *Aggregator) GetTaskStatus(taskIndex uint32) (*TaskInfo, bool) {
 	a.tasksMutex.RLock()
 	defer a.tasksMutex.RUnlock()
-	
+
 	task, exists := a.tasks[taskIndex]
 	return task, exists
 }
@@ -365,13 +1694,13 @@ func (a *Aggregator) GetTaskStatus(taskIndex uint32) (*TaskInfo, bool) {
 func (a *Aggregator) GetActiveTasks() map[uint32]*TaskInfo {
 	a.tasksMutex.RLock()
 	defer a.tasksMutex.RUnlock()
-	
+
 	activeTasks := make(map[uint32]*TaskInfo)
 	for taskIndex, task := range a.tasks {
 		if !task.IsCompleted {
 			activeTasks[taskIndex] = task
 		}
 	}
-	
+
 	return activeTasks
-}
\ No newline at end of file
+}
@@ -0,0 +1,240 @@
+package aggregator
+
+import (
+	"math/big"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/avsregistry"
+	"github.com/eigenlvr/avs/pkg/testutils/fixtures"
+)
+
+// maxQuickOperators bounds the operator sets the property tests generate
+const maxQuickOperators = 16
+
+// newQuickAggregator returns an aggregator deciding with the default params
+func newQuickAggregator(t *testing.T) *Aggregator {
+	t.Helper()
+
+	a, _ := newTestAggregator(t)
+	params, _, err := newParamSchedule(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.params = params
+	return a
+}
+
+// newQuickTask returns a task over quorum 0 whose operators have the given
+// stakes, with a threshold between 1 and 100 percent
+func newQuickTask(stakes []uint16, threshold uint8) *TaskInfo {
+	task := &TaskInfo{
+		QuorumNumbers:             types.QuorumNums{0},
+		QuorumThresholdPercentage: types.QuorumThresholdPercentage(threshold%100 + 1),
+		TaskResponses:             make(map[types.OperatorId]TaskResponse),
+		TaskResponsesInfo:         make(map[types.OperatorId]TaskResponseInfo),
+		referenceOperators:        []avsregistry.RegisteredOperator{},
+	}
+	for i, stake := range stakes[:min(len(stakes), maxQuickOperators)] {
+		task.referenceOperators = append(task.referenceOperators, avsregistry.RegisteredOperator{
+			OperatorId:     types.OperatorId{byte(i + 1)},
+			StakePerQuorum: map[types.QuorumNum]*big.Int{0: big.NewInt(int64(stake))},
+		})
+	}
+	return task
+}
+
+// respond records a response over the digest from the task's i-th operator
+func respond(task *TaskInfo, i int, digest common.Hash) {
+	operatorId := task.referenceOperators[i].OperatorId
+	response := TaskResponse{ReferenceTaskIndex: task.TaskIndex, WinningBid: big.NewInt(0)}
+	task.TaskResponses[operatorId] = response
+	task.TaskResponsesInfo[operatorId] = TaskResponseInfo{TaskResponse: response, OperatorId: operatorId, Digest: digest}
+}
+
+// wantAggregated decides, independently of the aggregator, whether enough
+// operators and stake are behind the bucket
+func wantAggregated(task *TaskInfo, bucket []TaskResponseInfo) bool {
+	total, signed := big.NewInt(0), big.NewInt(0)
+	for _, operator := range task.referenceOperators {
+		stake := operator.StakePerQuorum[0]
+		total.Add(total, stake)
+		for _, responseInfo := range bucket {
+			if responseInfo.OperatorId == operator.OperatorId {
+				signed.Add(signed, stake)
+			}
+		}
+	}
+	if len(bucket) < defaultMinOperators || total.Sign() == 0 {
+		return false
+	}
+	lhs := new(big.Int).Mul(signed, big.NewInt(100))
+	rhs := new(big.Int).Mul(total, big.NewInt(int64(task.QuorumThresholdPercentage)))
+	return lhs.Cmp(rhs) >= 0
+}
+
+func TestAggregationNeverBelowThreshold(t *testing.T) {
+	a := newQuickAggregator(t)
+
+	property := func(stakes []uint16, signed []bool, threshold uint8) bool {
+		task := newQuickTask(stakes, threshold)
+		digest := common.HexToHash("0x01")
+		for i := range task.referenceOperators {
+			if i < len(signed) && signed[i] {
+				respond(task, i, digest)
+			}
+		}
+		bucket := task.responsesWithDigest(digest)
+		return a.shouldAggregateTask(task, bucket) == wantAggregated(task, bucket)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIdenticalDigestsAggregateTogether(t *testing.T) {
+	a := newQuickAggregator(t)
+
+	property := func(stakes []uint16, digests []uint8, threshold uint8) bool {
+		task := newQuickTask(stakes, threshold)
+		responders := make(map[common.Hash]int)
+		for i := range task.referenceOperators {
+			if i >= len(digests) {
+				break
+			}
+			digest := common.BigToHash(big.NewInt(int64(digests[i] % 3)))
+			respond(task, i, digest)
+			responders[digest]++
+		}
+
+		for digest, count := range responders {
+			bucket := task.responsesWithDigest(digest)
+			if len(bucket) != count {
+				return false
+			}
+			for _, responseInfo := range bucket {
+				if responseInfo.Digest != digest {
+					return false
+				}
+			}
+			// Responses over other digests never count towards the bucket
+			if a.shouldAggregateTask(task, bucket) != wantAggregated(task, bucket) {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLateResponsesNeverChangeResult(t *testing.T) {
+	a := newQuickAggregator(t)
+
+	property := func(stakes []uint16, late []bool, threshold uint8, reopened bool) bool {
+		task := newQuickTask(stakes, threshold)
+		result := common.HexToHash("0x01")
+		task.AggregatedDigest = &result
+		task.IsCompleted = !reopened
+
+		// Late responses, however much stake is behind them, over another digest
+		lateDigest := common.HexToHash("0x02")
+		for i := range task.referenceOperators {
+			if i < len(late) && late[i] {
+				respond(task, i, lateDigest)
+			}
+		}
+		return !a.shouldAggregateTask(task, task.responsesWithDigest(lateDigest))
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReopenedTaskIsRetriedUpToTheLimit(t *testing.T) {
+	a, _ := newTestAggregator(t)
+
+	responseDigest := common.HexToHash("0x02")
+	task := newTestTask(2, TaskResponse{ReferenceTaskIndex: 2, WinningBid: big.NewInt(0)}, responseDigest)
+	task.IsCompleted = true
+	task.aggregationRetries = maxAggregationRetries
+	a.tasks[2] = task
+
+	a.reopenTask(task, &responseDigest)
+	if task.IsCompleted {
+		t.Fatal("reopened task is still completed")
+	}
+	if task.retryDigest == nil || *task.retryDigest != responseDigest {
+		t.Fatalf("reopened task retries digest %v, want %s", task.retryDigest, responseDigest.Hex())
+	}
+
+	a.tasksMutex.Lock()
+	a.retryAggregations()
+	a.tasksMutex.Unlock()
+	if task.IsCompleted || task.retryDigest != nil || task.aggregationRetries != maxAggregationRetries {
+		t.Fatalf("task retried past the limit: completed %v, retries %d", task.IsCompleted, task.aggregationRetries)
+	}
+}
+
+func TestReopenedTaskUnderTheLimitIsAggregatedAgain(t *testing.T) {
+	operators := fixtures.New("retries").Operators(2)
+	a := newVerifyingAggregator(t, operators[:1], 0)
+	params, _, err := newParamSchedule(Config{MinOperators: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.params = params
+	a.operators = newOperatorTracker()
+	a.operators.registered = []avsregistry.RegisteredOperator{{
+		OperatorId:     operators[0].OperatorId,
+		StakePerQuorum: map[types.QuorumNum]*big.Int{0: big.NewInt(10)},
+	}}
+
+	// Signed with another key, so the retried aggregate fails pre-verification
+	// and the task is reopened once the retry is done
+	signed := signedBy(t, operators[0].OperatorId, operators[1])
+	responseDigest := common.HexToHash("0x03")
+	task := newTestTask(3, signed.TaskResponse, responseDigest)
+	task.QuorumNumbers = types.QuorumNums{0}
+	task.TaskResponses = map[types.OperatorId]TaskResponse{signed.OperatorId: signed.TaskResponse}
+	task.TaskResponsesInfo = map[types.OperatorId]TaskResponseInfo{signed.OperatorId: {
+		TaskResponse: signed.TaskResponse,
+		BlsSignature: signed.BlsSignature,
+		OperatorId:   signed.OperatorId,
+		Digest:       responseDigest,
+	}}
+	task.aggregationRetries = maxAggregationRetries - 1
+	a.tasks[3] = task
+
+	a.reopenTask(task, &responseDigest)
+	a.tasksMutex.Lock()
+	a.retryAggregations()
+	if !task.IsCompleted || task.retryDigest != nil || task.aggregationRetries != maxAggregationRetries {
+		a.tasksMutex.Unlock()
+		t.Fatalf("task under the retry limit not aggregated again: completed %v, retries %d", task.IsCompleted, task.aggregationRetries)
+	}
+	a.tasksMutex.Unlock()
+
+	// The retry runs the aggregation, which reopens the task on failure
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		a.tasksMutex.RLock()
+		completed := task.IsCompleted
+		a.tasksMutex.RUnlock()
+		if !completed {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("retried aggregation never finished")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if task.aggregationRetries != maxAggregationRetries {
+		t.Fatalf("task retried %d times, want %d", task.aggregationRetries, maxAggregationRetries)
+	}
+}
@@ -0,0 +1,296 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// Allowlist modes. In monitor mode responses from operators outside the
+	// allowlist are accepted but logged and counted, so the effect of
+	// enforcing, or of dropping, the allowlist can be checked beforehand.
+	AllowlistModeOff     = "off"
+	AllowlistModeMonitor = "monitor"
+	AllowlistModeEnforce = "enforce"
+)
+
+// ErrOperatorNotAllowlisted is returned when a response comes from an operator
+// outside the allowlist while it is enforced
+var ErrOperatorNotAllowlisted = errors.New("operator is not on the allowlist; the aggregator only accepts responses from allowlisted operators while permissioned")
+
+// operatorAllowlistAbi is the interface of the on-chain allowlist. Setting
+// allowlistEnabled to false moves every aggregator reading it to
+// permissionless mode without a restart.
+const operatorAllowlistAbi = `[
+	{"type":"function","name":"allowlistEnabled","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"getAllowlist","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address[]"}]}
+]`
+
+// AllowlistStatus is the allowlist as served on GET /allowlist
+type AllowlistStatus struct {
+	// Mode is the mode in effect, ConfiguredMode the one configured
+	Mode           string           `json:"mode"`
+	ConfiguredMode string           `json:"configuredMode"`
+	Contract       *common.Address  `json:"contract,omitempty"`
+	EnabledOnChain *bool            `json:"enabledOnChain,omitempty"`
+	OperatorIds    []string         `json:"operatorIds"`
+	Addresses      []common.Address `json:"addresses"`
+	RefreshedAt    *time.Time       `json:"refreshedAt,omitempty"`
+}
+
+// operatorAllowlist holds the operators accepted in permissioned mode: those
+// listed in the config by operator id or address, and those in the on-chain
+// allowlist contract when one is configured
+type operatorAllowlist struct {
+	mode            string
+	contractAddress *common.Address
+	contract        *bind.BoundContract
+	operatorIds     map[types.OperatorId]bool
+	addresses       map[common.Address]bool
+	logger          logging.Logger
+
+	mu               sync.RWMutex
+	onChainAddresses map[common.Address]bool
+	enabledOnChain   bool
+	refreshedAt      time.Time
+
+	rejections *prometheus.CounterVec
+}
+
+// newOperatorAllowlist returns nil when the allowlist mode is off
+func newOperatorAllowlist(config Config, backend bind.ContractCaller, reg prometheus.Registerer, logger logging.Logger) (*operatorAllowlist, error) {
+	switch config.OperatorAllowlistMode {
+	case "", AllowlistModeOff:
+		return nil, nil
+	case AllowlistModeMonitor, AllowlistModeEnforce:
+	default:
+		return nil, fmt.Errorf("unknown operator allowlist mode %q", config.OperatorAllowlistMode)
+	}
+
+	allowlist := &operatorAllowlist{
+		mode:           config.OperatorAllowlistMode,
+		operatorIds:    make(map[types.OperatorId]bool),
+		addresses:      make(map[common.Address]bool),
+		enabledOnChain: true,
+		logger:         logger.With("component", "operator-allowlist"),
+		rejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "allowlist_rejections_total",
+			Help:      "Responses from operators outside the allowlist, by the mode they arrived in",
+		}, []string{"mode"}),
+	}
+
+	for _, entry := range config.OperatorAllowlist {
+		if common.IsHexAddress(entry) && len(strings.TrimPrefix(entry, "0x")) == 2*common.AddressLength {
+			allowlist.addresses[common.HexToAddress(entry)] = true
+			continue
+		}
+		operatorId, err := parseOperatorId(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid operator allowlist entry, expected an address or operator id: %w", err)
+		}
+		allowlist.operatorIds[operatorId] = true
+	}
+
+	if config.OperatorAllowlistContract != "" {
+		if !common.IsHexAddress(config.OperatorAllowlistContract) {
+			return nil, fmt.Errorf("invalid operator allowlist contract: %q", config.OperatorAllowlistContract)
+		}
+		parsed, err := abi.JSON(strings.NewReader(operatorAllowlistAbi))
+		if err != nil {
+			return nil, fmt.Errorf("invalid operator allowlist abi: %w", err)
+		}
+		address := common.HexToAddress(config.OperatorAllowlistContract)
+		allowlist.contractAddress = &address
+		allowlist.contract = bind.NewBoundContract(address, parsed, backend, nil, nil)
+	} else if len(allowlist.operatorIds) == 0 && len(allowlist.addresses) == 0 {
+		return nil, errors.New("operator allowlist mode needs operator_allowlist or operator_allowlist_contract")
+	}
+
+	reg.MustRegister(allowlist.rejections)
+	return allowlist, nil
+}
+
+// effectiveMode is the configured mode, or off once the on-chain allowlist
+// has been disabled
+func (l *operatorAllowlist) effectiveMode() string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if !l.enabledOnChain {
+		return AllowlistModeOff
+	}
+	return l.mode
+}
+
+// allows reports whether the operator, with its registered address if known,
+// is on the allowlist
+func (l *operatorAllowlist) allows(operatorId types.OperatorId, address *common.Address) bool {
+	if l.operatorIds[operatorId] {
+		return true
+	}
+	if address == nil {
+		return false
+	}
+	if l.addresses[*address] {
+		return true
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.onChainAddresses[*address]
+}
+
+// refresh re-reads the on-chain allowlist. The last list read stays in effect
+// when the contract can't be read.
+func (l *operatorAllowlist) refresh(ctx context.Context) error {
+	if l.contract == nil {
+		return nil
+	}
+
+	var out []interface{}
+	if err := l.contract.Call(&bind.CallOpts{Context: ctx}, &out, "allowlistEnabled"); err != nil {
+		return fmt.Errorf("failed to read allowlistEnabled: %w", err)
+	}
+	enabled := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	out = nil
+	if err := l.contract.Call(&bind.CallOpts{Context: ctx}, &out, "getAllowlist"); err != nil {
+		return fmt.Errorf("failed to read allowlist: %w", err)
+	}
+	listed := *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+
+	addresses := make(map[common.Address]bool, len(listed))
+	for _, address := range listed {
+		addresses[address] = true
+	}
+
+	l.mu.Lock()
+	wasEnabled := l.enabledOnChain
+	l.onChainAddresses = addresses
+	l.enabledOnChain = enabled
+	l.refreshedAt = time.Now().UTC()
+	l.mu.Unlock()
+
+	switch {
+	case wasEnabled && !enabled:
+		l.logger.Info("Allowlist disabled on chain, accepting responses from every operator")
+	case !wasEnabled && enabled:
+		l.logger.Info("Allowlist enabled on chain", "mode", l.mode)
+	}
+	return nil
+}
+
+func (l *operatorAllowlist) status() AllowlistStatus {
+	status := AllowlistStatus{
+		Mode:           l.effectiveMode(),
+		ConfiguredMode: l.mode,
+		Contract:       l.contractAddress,
+		OperatorIds:    make([]string, 0, len(l.operatorIds)),
+		Addresses:      make([]common.Address, 0, len(l.addresses)),
+	}
+	for operatorId := range l.operatorIds {
+		status.OperatorIds = append(status.OperatorIds, formatOperatorId(operatorId))
+	}
+	sort.Strings(status.OperatorIds)
+
+	l.mu.RLock()
+	addresses := make(map[common.Address]bool, len(l.addresses)+len(l.onChainAddresses))
+	for address := range l.addresses {
+		addresses[address] = true
+	}
+	for address := range l.onChainAddresses {
+		addresses[address] = true
+	}
+	if l.contract != nil && !l.refreshedAt.IsZero() {
+		enabled := l.enabledOnChain
+		refreshedAt := l.refreshedAt
+		status.EnabledOnChain = &enabled
+		status.RefreshedAt = &refreshedAt
+	}
+	l.mu.RUnlock()
+
+	for address := range addresses {
+		status.Addresses = append(status.Addresses, address)
+	}
+	sort.Slice(status.Addresses, func(i, j int) bool {
+		return status.Addresses[i].Hex() < status.Addresses[j].Hex()
+	})
+	return status
+}
+
+// checkAllowlist rejects responses from operators outside the allowlist while
+// it is enforced. In monitor mode they are only logged and counted.
+func (a *Aggregator) checkAllowlist(operatorId types.OperatorId) error {
+	if a.allowlist == nil {
+		return nil
+	}
+	mode := a.allowlist.effectiveMode()
+	if mode == AllowlistModeOff {
+		return nil
+	}
+
+	var address *common.Address
+	a.operators.mu.RLock()
+	for _, operator := range a.operators.registered {
+		if operator.OperatorId == operatorId {
+			registered := operator.Address
+			address = &registered
+			break
+		}
+	}
+	a.operators.mu.RUnlock()
+
+	if a.allowlist.allows(operatorId, address) {
+		return nil
+	}
+
+	a.allowlist.rejections.WithLabelValues(mode).Inc()
+	if mode == AllowlistModeMonitor {
+		a.logger.Warn("Accepted response from operator outside the allowlist", "operatorId", formatOperatorId(operatorId))
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrOperatorNotAllowlisted, formatOperatorId(operatorId))
+}
+
+// refreshAllowlist re-reads the on-chain allowlist, if any
+func (a *Aggregator) refreshAllowlist(ctx context.Context) {
+	if a.allowlist == nil {
+		return
+	}
+	if err := a.allowlist.refresh(ctx); err != nil {
+		a.logger.Warn("Failed to refresh operator allowlist", "error", err)
+	}
+}
+
+// allowlistHandler serves the allowlist, so operators can tell whether they
+// are let in before responding
+func (a *Aggregator) allowlistHandler(w http.ResponseWriter, r *http.Request) {
+	status := AllowlistStatus{
+		Mode:           AllowlistModeOff,
+		ConfiguredMode: AllowlistModeOff,
+		OperatorIds:    []string{},
+		Addresses:      []common.Address{},
+	}
+	if a.allowlist != nil {
+		status = a.allowlist.status()
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,78 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/eigenlvr/avs/pkg/apiversion"
+)
+
+// ApiVersions is the body of GET /versions
+type ApiVersions struct {
+	Current   string   `json:"current"`
+	Supported []string `json:"supported"`
+	// LegacyApi describes the deprecated unversioned routes, omitted once they
+	// are disabled
+	LegacyApi *LegacyApiStatus `json:"legacyApi,omitempty"`
+}
+
+// LegacyApiStatus describes the unversioned routes
+type LegacyApiStatus struct {
+	Deprecated bool       `json:"deprecated"`
+	Successor  string     `json:"successor"`
+	Sunset     *time.Time `json:"sunset,omitempty"`
+}
+
+// parseLegacyApiSunset parses the YYYY-MM-DD sunset date, if any
+func parseLegacyApiSunset(config Config) (*time.Time, error) {
+	if config.LegacyApiSunset == "" {
+		return nil, nil
+	}
+	sunset, err := time.Parse(time.DateOnly, config.LegacyApiSunset)
+	if err != nil {
+		return nil, fmt.Errorf("invalid legacy api sunset: %w", err)
+	}
+	return &sunset, nil
+}
+
+// withApiVersion tags every response with the current API version, which is
+// how operators tell a missing route from an aggregator without versioning
+func withApiVersion(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(apiversion.Header, apiversion.Current)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// deprecatedApi marks responses from the unversioned routes as deprecated and
+// points clients at the versioned successor (RFC 8594)
+func (a *Aggregator) deprecatedApi(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Set(apiversion.DeprecationHeader, "true")
+		header.Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", apiversion.Path(r.URL.Path)))
+		if a.legacyApiSunset != nil {
+			header.Set("Sunset", a.legacyApiSunset.Format(http.TimeFormat))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (a *Aggregator) versionsHandler(w http.ResponseWriter, r *http.Request) {
+	versions := ApiVersions{
+		Current:   apiversion.Current,
+		Supported: apiversion.Supported,
+	}
+	if !a.config.DisableLegacyApi {
+		versions.LegacyApi = &LegacyApiStatus{
+			Deprecated: true,
+			Successor:  apiversion.Current,
+			Sunset:     a.legacyApiSunset,
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(versions)
+}
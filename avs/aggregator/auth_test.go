@@ -0,0 +1,153 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+
+	"github.com/eigenlvr/avs/pkg/avsregistry"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+	"github.com/eigenlvr/avs/pkg/testutils/fixtures"
+	"github.com/eigenlvr/avs/pkg/wsproto"
+)
+
+func TestAdminRoutesRequireBearerToken(t *testing.T) {
+	a := newVerifyingAggregator(t, nil, 0)
+	a.config.AdminApiToken = "admin-secret"
+	router := mux.NewRouter()
+	a.registerAdminRoutes(router)
+
+	for _, tc := range []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong", "Bearer not-the-secret", http.StatusUnauthorized},
+		{"prefix of the token", "Bearer admin", http.StatusUnauthorized},
+		{"other scheme", "Basic admin-secret", http.StatusUnauthorized},
+		{"token", "Bearer admin-secret", http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/admin/bans", nil)
+		if tc.authorization != "" {
+			req.Header.Set("Authorization", tc.authorization)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s authorization: status %d, want %d", tc.name, rec.Code, tc.want)
+		}
+	}
+
+	// Without a token the admin API isn't served at all
+	a.config.AdminApiToken = ""
+	router = mux.NewRouter()
+	a.registerAdminRoutes(router)
+	req := httptest.NewRequest(http.MethodGet, "/admin/bans", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("admin API without a token: status %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+// wsLogin answers the aggregator's websocket challenge claiming operatorId
+// with a signature by the signer's ECDSA key, and returns the aggregator's
+// verdict on the connection
+func wsLogin(t *testing.T, a *Aggregator, operatorId types.OperatorId, signer fixtures.Operator) (types.OperatorId, error) {
+	t.Helper()
+
+	type verdict struct {
+		operatorId types.OperatorId
+		err        error
+	}
+	verdicts := make(chan verdict, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			verdicts <- verdict{err: err}
+			return
+		}
+		defer conn.Close()
+		operatorId, err := a.authenticateOperatorConn(conn)
+		verdicts <- verdict{operatorId: operatorId, err: err}
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var message wsproto.Message
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatal(err)
+	}
+	var challenge wsproto.Challenge
+	if err := json.Unmarshal(message.Payload, &challenge); err != nil {
+		t.Fatal(err)
+	}
+	signature, err := wsproto.SignChallenge(context.Background(), challenge, remotesigner.NewLocal(signer.EcdsaKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := wsproto.NewMessage(wsproto.TypeAuth, 0, wsproto.Auth{OperatorId: common.Hash(operatorId), Signature: signature})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.WriteJSON(auth); err != nil {
+		t.Fatal(err)
+	}
+
+	result := <-verdicts
+	return result.operatorId, result.err
+}
+
+func TestWsLoginRequiresOperatorsRegisteredAddress(t *testing.T) {
+	operators := fixtures.New("wslogin").Operators(3)
+	registered, impostor, unregistered := operators[0], operators[1], operators[2]
+	a := newVerifyingAggregator(t, operators[:2], 0)
+	a.operators = newOperatorTracker()
+	for _, operator := range operators[:2] {
+		a.operators.registered = append(a.operators.registered, avsregistry.RegisteredOperator{
+			OperatorId: operator.OperatorId,
+			Address:    operator.Address,
+		})
+	}
+
+	// Another registered operator's key can't log in as the operator
+	if _, err := wsLogin(t, a, registered.OperatorId, impostor); !errors.Is(err, ErrOperatorNotRegistered) {
+		t.Fatalf("login signed by another operator: got %v, want %v", err, ErrOperatorNotRegistered)
+	}
+	// Nor can an operator that isn't registered, with its own key
+	if _, err := wsLogin(t, a, unregistered.OperatorId, unregistered); !errors.Is(err, ErrOperatorNotRegistered) {
+		t.Fatalf("login of an unregistered operator: got %v, want %v", err, ErrOperatorNotRegistered)
+	}
+
+	operatorId, err := wsLogin(t, a, registered.OperatorId, registered)
+	if err != nil {
+		t.Fatalf("login signed by the operator's registered address: %v", err)
+	}
+	if operatorId != registered.OperatorId {
+		t.Fatalf("logged in as %s, want %s", formatOperatorId(operatorId), formatOperatorId(registered.OperatorId))
+	}
+
+	// A banned operator can't log in even with its own key
+	if err := a.banList.Ban(registered.OperatorId, "test", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wsLogin(t, a, registered.OperatorId, registered); !errors.Is(err, ErrOperatorBanned) {
+		t.Fatalf("login of a banned operator: got %v, want %v", err, ErrOperatorBanned)
+	}
+}
@@ -0,0 +1,203 @@
+package aggregator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+)
+
+// BanEntry records why and when an operator was banned
+type BanEntry struct {
+	OperatorId string    `json:"operatorId"`
+	Reason     string    `json:"reason"`
+	Automatic  bool      `json:"automatic"`
+	BannedAt   time.Time `json:"bannedAt"`
+}
+
+// BanList tracks operators whose responses the aggregator refuses. Bans are
+// persisted to disk when a path is configured so they survive restarts.
+type BanList struct {
+	mu   sync.RWMutex
+	path string
+	bans map[types.OperatorId]BanEntry
+
+	// Operators are banned automatically once they reach this many invalid
+	// signatures. Zero disables automatic bans.
+	autoBanThreshold  int
+	invalidSignatures map[types.OperatorId]int
+}
+
+func NewBanList(path string, autoBanThreshold int) (*BanList, error) {
+	banList := &BanList{
+		path:              path,
+		bans:              make(map[types.OperatorId]BanEntry),
+		autoBanThreshold:  autoBanThreshold,
+		invalidSignatures: make(map[types.OperatorId]int),
+	}
+
+	if path == "" {
+		return banList, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return banList, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ban list: %w", err)
+	}
+
+	var entries []BanEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode ban list: %w", err)
+	}
+
+	for _, entry := range entries {
+		operatorId, err := parseOperatorId(entry.OperatorId)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ban list entry: %w", err)
+		}
+		banList.bans[operatorId] = entry
+	}
+
+	return banList, nil
+}
+
+// IsBanned returns whether the operator is currently banned
+func (b *BanList) IsBanned(operatorId types.OperatorId) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	_, banned := b.bans[operatorId]
+	return banned
+}
+
+// Ban adds the operator to the ban list, replacing any existing entry
+func (b *BanList) Ban(operatorId types.OperatorId, reason string, automatic bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bans[operatorId] = BanEntry{
+		OperatorId: formatOperatorId(operatorId),
+		Reason:     reason,
+		Automatic:  automatic,
+		BannedAt:   time.Now().UTC(),
+	}
+
+	return b.save()
+}
+
+// Unban removes the operator from the ban list and resets its invalid signature
+// count. It reports whether the operator was banned.
+func (b *BanList) Unban(operatorId types.OperatorId) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.invalidSignatures, operatorId)
+	if _, banned := b.bans[operatorId]; !banned {
+		return false, nil
+	}
+
+	delete(b.bans, operatorId)
+	return true, b.save()
+}
+
+// List returns all bans, oldest first
+func (b *BanList) List() []BanEntry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	entries := make([]BanEntry, 0, len(b.bans))
+	for _, entry := range b.bans {
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].BannedAt.Before(entries[j].BannedAt)
+	})
+
+	return entries
+}
+
+// RecordInvalidSignature counts an invalid signature from the operator and bans
+// it once the automatic ban threshold is reached. It reports whether the
+// operator was banned as a result.
+func (b *BanList) RecordInvalidSignature(operatorId types.OperatorId) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.invalidSignatures[operatorId]++
+	if b.autoBanThreshold <= 0 || b.invalidSignatures[operatorId] < b.autoBanThreshold {
+		return false, nil
+	}
+	if _, banned := b.bans[operatorId]; banned {
+		return false, nil
+	}
+
+	b.bans[operatorId] = BanEntry{
+		OperatorId: formatOperatorId(operatorId),
+		Reason:     fmt.Sprintf("%d invalid signatures", b.invalidSignatures[operatorId]),
+		Automatic:  true,
+		BannedAt:   time.Now().UTC(),
+	}
+
+	return true, b.save()
+}
+
+// save writes the ban list to disk. Callers must hold the write lock.
+func (b *BanList) save() error {
+	if b.path == "" {
+		return nil
+	}
+
+	entries := make([]BanEntry, 0, len(b.bans))
+	for _, entry := range b.bans {
+		entries = append(entries, entry)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode ban list: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated list
+	tmpPath := b.path + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create ban list directory: %w", err)
+	}
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write ban list: %w", err)
+	}
+	if err := os.Rename(tmpPath, b.path); err != nil {
+		return fmt.Errorf("failed to replace ban list: %w", err)
+	}
+
+	return nil
+}
+
+func parseOperatorId(s string) (types.OperatorId, error) {
+	var operatorId types.OperatorId
+
+	decoded, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return operatorId, fmt.Errorf("invalid operator id %q: %w", s, err)
+	}
+	if len(decoded) != len(operatorId) {
+		return operatorId, fmt.Errorf("invalid operator id %q: expected %d bytes, got %d", s, len(operatorId), len(decoded))
+	}
+
+	copy(operatorId[:], decoded)
+	return operatorId, nil
+}
+
+func formatOperatorId(operatorId types.OperatorId) string {
+	return "0x" + hex.EncodeToString(operatorId[:])
+}
@@ -0,0 +1,412 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/auction"
+	"github.com/eigenlvr/avs/pkg/bids"
+	"github.com/eigenlvr/avs/pkg/escrow"
+	"github.com/eigenlvr/avs/pkg/poolregistry"
+)
+
+const (
+	// Bid book limits applied when the corresponding config value is unset
+	defaultMaxBidsPerAuction  = 256
+	defaultBidRetentionBlocks = 64
+	defaultBidRevealBlocks    = 2
+
+	// Outcomes of submitted commitments and reveals, as labelled in the bids metric
+	bidAccepted    = "accepted"
+	bidInvalid     = "invalid"
+	bidWrongPhase  = "wrong_phase"
+	bidUnescrowed  = "unescrowed"
+	bidFull        = "full"
+	bidUnlisted    = "unlisted"
+	bidCheckFailed = "check_failed"
+)
+
+var (
+	// ErrBidBookDisabled is returned for bids sent while BidBookEnabled is off
+	ErrBidBookDisabled = errors.New("bid book is disabled")
+	// ErrAuctionNotOpen is returned for bids on a block further ahead than
+	// the bid book keeps auctions for
+	ErrAuctionNotOpen = errors.New("auction for the block is not open yet")
+	// ErrAuctionFull is returned for commitments from new bidders once an
+	// auction holds MaxBidsPerAuction of them
+	ErrAuctionFull = errors.New("auction has no room for more bidders")
+)
+
+// auctionKey identifies a pool's auction for a block
+type auctionKey struct {
+	poolId      common.Hash
+	blockNumber uint32
+}
+
+// bidBook runs the sealed-bid auction of each pool and block bidders commit
+// to. Each bidder has one commitment per auction, which a later one replaces
+// until commits close.
+type bidBook struct {
+	mu       sync.Mutex
+	auctions map[auctionKey]*auction.Auction
+
+	schedule        auction.Schedule
+	maxBids         int
+	retentionBlocks uint64
+	// Commitments and reveals, by kind and outcome
+	submitted *prometheus.CounterVec
+}
+
+// newBidBook returns nil unless BidBookEnabled is set
+func newBidBook(config Config, reg prometheus.Registerer) *bidBook {
+	if !config.BidBookEnabled {
+		return nil
+	}
+
+	book := &bidBook{
+		auctions:        make(map[auctionKey]*auction.Auction),
+		schedule:        auction.Schedule{RevealBlocks: config.BidRevealBlocks},
+		maxBids:         config.MaxBidsPerAuction,
+		retentionBlocks: config.BidRetentionBlocks,
+		submitted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "bids_total",
+			Help:      "Sealed bids and reveals submitted to the bid book, by kind and outcome",
+		}, []string{"kind", "outcome"}),
+	}
+	if book.schedule.RevealBlocks == 0 {
+		book.schedule.RevealBlocks = defaultBidRevealBlocks
+	}
+	if book.maxBids <= 0 {
+		book.maxBids = defaultMaxBidsPerAuction
+	}
+	if book.retentionBlocks == 0 {
+		book.retentionBlocks = defaultBidRetentionBlocks
+	}
+	reg.MustRegister(book.submitted)
+	return book
+}
+
+// auction returns the pool's auction for the block, opening it when create
+// is set, and drops auctions that ended more than the retention window
+// before head. Callers must hold the lock.
+func (b *bidBook) auction(poolId common.Hash, blockNumber uint32, head uint64, create bool) *auction.Auction {
+	for key := range b.auctions {
+		if uint64(key.blockNumber)+b.retentionBlocks < head {
+			delete(b.auctions, key)
+		}
+	}
+
+	key := auctionKey{poolId: poolId, blockNumber: blockNumber}
+	found := b.auctions[key]
+	if found == nil && create {
+		found = auction.New(poolId, blockNumber, b.schedule)
+		b.auctions[key] = found
+	}
+	return found
+}
+
+func (b *bidBook) commit(sealed auction.SignedSealedBid, head uint64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	found := b.auction(sealed.PoolId, sealed.BlockNumber, head, true)
+	if !found.Committed(sealed.Bidder) && found.Commitments() >= b.maxBids {
+		return fmt.Errorf("%w: %d bidders", ErrAuctionFull, found.Commitments())
+	}
+	return found.Commit(sealed, head)
+}
+
+func (b *bidBook) reveal(reveal auction.Reveal, head uint64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	found := b.auction(reveal.PoolId, reveal.BlockNumber, head, false)
+	if found == nil {
+		return fmt.Errorf("%w: %s", auction.ErrNoCommitment, reveal.Bidder.Hex())
+	}
+	return found.Reveal(reveal, head)
+}
+
+func (b *bidBook) book(poolId common.Hash, blockNumber uint32, head uint64) auction.Book {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	found := b.auction(poolId, blockNumber, head, false)
+	if found == nil {
+		return auction.Book{
+			PoolId:      poolId,
+			BlockNumber: blockNumber,
+			Phase:       b.schedule.Phase(blockNumber, head),
+			Revealed:    []auction.RevealedBid{},
+		}
+	}
+	return found.Book(head)
+}
+
+// CommitBid adds a bidder's sealed bid to its auction, returning the sealed
+// bid's digest. Commitments are taken until RevealBlocks before the auction's
+// block.
+func (a *Aggregator) CommitBid(ctx context.Context, sealed auction.SignedSealedBid) (common.Hash, error) {
+	if a.bidBook == nil {
+		return common.Hash{}, ErrBidBookDisabled
+	}
+
+	err := a.commitBid(ctx, sealed)
+	a.bidBook.submitted.WithLabelValues("commit", bidOutcome(err)).Inc()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	a.logger.Debug("Accepted sealed bid",
+		"poolId", sealed.PoolId.Hex(),
+		"blockNumber", sealed.BlockNumber,
+		"bidder", sealed.Bidder.Hex(),
+	)
+	return sealed.Digest(), nil
+}
+
+func (a *Aggregator) commitBid(ctx context.Context, sealed auction.SignedSealedBid) error {
+	head, err := a.openAuctionHead(ctx, sealed.PoolId, sealed.BlockNumber)
+	if err != nil {
+		return err
+	}
+	return a.bidBook.commit(sealed, head)
+}
+
+// RevealBid opens a bidder's sealed bid, returning the bid's digest. The bid
+// must be signed by its bidder, match the commitment and, with an escrow
+// configured, be covered by the bidder's deposit.
+func (a *Aggregator) RevealBid(ctx context.Context, reveal auction.Reveal) (common.Hash, error) {
+	if a.bidBook == nil {
+		return common.Hash{}, ErrBidBookDisabled
+	}
+
+	err := a.revealBid(ctx, reveal)
+	a.bidBook.submitted.WithLabelValues("reveal", bidOutcome(err)).Inc()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	a.logger.Debug("Accepted bid reveal",
+		"poolId", reveal.PoolId.Hex(),
+		"blockNumber", reveal.BlockNumber,
+		"bidder", reveal.Bidder.Hex(),
+		"amount", reveal.Amount.String(),
+	)
+	return reveal.Digest(), nil
+}
+
+func (a *Aggregator) revealBid(ctx context.Context, reveal auction.Reveal) error {
+	if err := reveal.SignedBid.Verify(); err != nil {
+		return err
+	}
+
+	head, err := a.openAuctionHead(ctx, reveal.PoolId, reveal.BlockNumber)
+	if err != nil {
+		return err
+	}
+
+	if a.escrow != nil {
+		if err := a.escrow.Verify(ctx, reveal.Bidder, reveal.Amount, nil); err != nil {
+			return err
+		}
+	}
+
+	return a.bidBook.reveal(reveal, head)
+}
+
+// openAuctionHead returns the chain head, refusing auctions of pools the
+// registry doesn't list and for blocks further ahead than the pool's auction
+// duration, or than the bid book keeps when the pool sets none
+func (a *Aggregator) openAuctionHead(ctx context.Context, poolId common.Hash, blockNumber uint32) (uint64, error) {
+	pool, err := a.pools.Pool(poolId)
+	if err != nil {
+		return 0, err
+	}
+	window := a.bidBook.retentionBlocks
+	if pool.AuctionDurationBlocks > 0 {
+		window = pool.AuctionDurationBlocks
+	}
+
+	head, err := a.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get current block number: %w", err)
+	}
+	if uint64(blockNumber) > head+window {
+		return 0, fmt.Errorf("%w: block %d, head is %d", ErrAuctionNotOpen, blockNumber, head)
+	}
+	return head, nil
+}
+
+// Bids returns the pool's auction for the block: its phase, how many bidders
+// committed and the reveals so far, highest bid first
+func (a *Aggregator) Bids(ctx context.Context, poolId common.Hash, blockNumber uint32) (auction.Book, error) {
+	if a.bidBook == nil {
+		return auction.Book{}, ErrBidBookDisabled
+	}
+	head, err := a.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return auction.Book{}, fmt.Errorf("failed to get current block number: %w", err)
+	}
+	return a.bidBook.book(poolId, blockNumber, head), nil
+}
+
+// bidOutcome is the metric label of a CommitBid or RevealBid result
+func bidOutcome(err error) string {
+	switch {
+	case err == nil:
+		return bidAccepted
+	case isInvalidBid(err):
+		return bidInvalid
+	case isWrongPhase(err):
+		return bidWrongPhase
+	case errors.Is(err, escrow.ErrInsufficientDeposit):
+		return bidUnescrowed
+	case errors.Is(err, ErrAuctionFull):
+		return bidFull
+	case errors.Is(err, poolregistry.ErrPoolNotListed):
+		return bidUnlisted
+	default:
+		return bidCheckFailed
+	}
+}
+
+// isInvalidBid reports whether a commitment or reveal was refused for its
+// contents, which resending it won't change
+func isInvalidBid(err error) bool {
+	for _, invalid := range []error{
+		bids.ErrInvalidBid,
+		bids.ErrInvalidSignature,
+		bids.ErrWrongSigner,
+		auction.ErrWrongAuction,
+		auction.ErrInvalidSignature,
+		auction.ErrWrongSigner,
+		auction.ErrNoCommitment,
+		auction.ErrCommitmentMismatch,
+	} {
+		if errors.Is(err, invalid) {
+			return true
+		}
+	}
+	return false
+}
+
+// isWrongPhase reports whether a commitment or reveal came outside its phase
+func isWrongPhase(err error) bool {
+	return errors.Is(err, auction.ErrCommitClosed) ||
+		errors.Is(err, auction.ErrRevealNotOpen) ||
+		errors.Is(err, auction.ErrClosed) ||
+		errors.Is(err, ErrAuctionNotOpen)
+}
+
+func (a *Aggregator) bidCommitHandler(w http.ResponseWriter, r *http.Request) {
+	var sealed auction.SignedSealedBid
+	if !a.decodeBidRequest(w, r, &sealed) {
+		return
+	}
+
+	digest, err := a.CommitBid(r.Context(), sealed)
+	if err != nil {
+		a.writeBidError(w, err, sealed.PoolId, sealed.Bidder)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":          "committed",
+		"sealedBidDigest": digest,
+	})
+}
+
+func (a *Aggregator) bidHandler(w http.ResponseWriter, r *http.Request) {
+	var reveal auction.Reveal
+	if !a.decodeBidRequest(w, r, &reveal) {
+		return
+	}
+
+	digest, err := a.RevealBid(r.Context(), reveal)
+	if err != nil {
+		a.writeBidError(w, err, reveal.PoolId, reveal.Bidder)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":    "revealed",
+		"bidDigest": digest,
+	})
+}
+
+// decodeBidRequest decodes the request body into v, answering the request
+// itself when it can't
+func (a *Aggregator) decodeBidRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, a.maxRequestBodyBytes())
+
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return false
+		}
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func (a *Aggregator) writeBidError(w http.ResponseWriter, err error, poolId common.Hash, bidder common.Address) {
+	switch {
+	case errors.Is(err, ErrBidBookDisabled):
+		http.Error(w, "Bids are not taken by this aggregator", http.StatusNotImplemented)
+	case isInvalidBid(err):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case isWrongPhase(err), errors.Is(err, ErrAuctionFull):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, escrow.ErrInsufficientDeposit):
+		http.Error(w, err.Error(), http.StatusPaymentRequired)
+	case errors.Is(err, poolregistry.ErrPoolNotListed):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		a.logger.Error("Failed to process bid", "poolId", poolId.Hex(), "bidder", bidder.Hex(), "error", err)
+		http.Error(w, "Failed to process bid", http.StatusInternalServerError)
+	}
+}
+
+func (a *Aggregator) bidsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	var poolId common.Hash
+	if err := poolId.UnmarshalText([]byte(vars["poolId"])); err != nil {
+		http.Error(w, "Invalid pool id", http.StatusBadRequest)
+		return
+	}
+	blockNumber, err := strconv.ParseUint(vars["blockNumber"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid block number", http.StatusBadRequest)
+		return
+	}
+
+	book, err := a.Bids(r.Context(), poolId, uint32(blockNumber))
+	switch {
+	case errors.Is(err, ErrBidBookDisabled):
+		http.Error(w, "Bids are not taken by this aggregator", http.StatusNotImplemented)
+		return
+	case err != nil:
+		a.logger.Error("Failed to read bid book", "poolId", poolId.Hex(), "blockNumber", blockNumber, "error", err)
+		http.Error(w, "Failed to read bid book", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(book)
+}
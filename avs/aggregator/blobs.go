@@ -0,0 +1,329 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+
+	"github.com/eigenlvr/avs/pkg/blobs"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+)
+
+const (
+	// Encodings of published result data
+	ResultDataEncodingBlob     = "blob"
+	ResultDataEncodingCalldata = "calldata"
+
+	// blobPublishTimeout bounds a publication, from fee lookup to receipt
+	blobPublishTimeout = 3 * time.Minute
+	// blobReceiptPollInterval is how often a sent publication is checked for
+	// its receipt
+	blobReceiptPollInterval = 6 * time.Second
+	// blobChainIdTimeout bounds the chain id read at startup
+	blobChainIdTimeout = 10 * time.Second
+)
+
+// errBlobFeeTooHigh is returned when the blob base fee is above the cap and
+// calldata fallback is off
+var errBlobFeeTooHigh = errors.New("blob base fee above cap")
+
+// ResultDataTx is the transaction a task's result bundle was published in.
+// Its calldata starts with the task hash and the big-endian task index, and
+// for the calldata encoding the bundle JSON follows. Blob encoded bundles are
+// in the transaction's blobs, packed as pkg/blobs describes.
+type ResultDataTx struct {
+	TxHash      common.Hash   `json:"txHash"`
+	BlockNumber uint64        `json:"blockNumber"`
+	Encoding    string        `json:"encoding"`
+	BlobHashes  []common.Hash `json:"blobHashes,omitempty"`
+}
+
+// blobPublisher publishes result bundles to the inbox address as blob
+// transactions signed with the aggregator key
+type blobPublisher struct {
+	client     eth.Client
+	signer     remotesigner.Signer
+	from       common.Address
+	inbox      common.Address
+	chainId    *big.Int
+	maxBlobFee *big.Int
+	fallback   bool
+	logger     logging.Logger
+
+	// mu serializes publications, which share the key's nonce
+	mu sync.Mutex
+}
+
+// newBlobPublisher returns nil when no inbox address is configured
+func newBlobPublisher(config Config, client eth.Client, signer remotesigner.Signer, logger logging.Logger) (*blobPublisher, error) {
+	if config.BlobInboxAddress == "" {
+		return nil, nil
+	}
+	if !common.IsHexAddress(config.BlobInboxAddress) {
+		return nil, fmt.Errorf("invalid blob inbox address: %q", config.BlobInboxAddress)
+	}
+	if signer == nil {
+		return nil, errors.New("blob publication requires aggregator_private_key_path or aggregator_signer")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), blobChainIdTimeout)
+	defer cancel()
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chain id: %w", err)
+	}
+
+	var maxBlobFee *big.Int
+	if config.BlobMaxFeeGwei > 0 {
+		maxBlobFee = new(big.Int).Mul(new(big.Int).SetUint64(config.BlobMaxFeeGwei), big.NewInt(params.GWei))
+	}
+
+	return &blobPublisher{
+		client:     client,
+		signer:     signer,
+		from:       signer.Address(),
+		inbox:      common.HexToAddress(config.BlobInboxAddress),
+		chainId:    chainId,
+		maxBlobFee: maxBlobFee,
+		fallback:   config.BlobCalldataFallback,
+		logger:     logger.With("component", "blob-publisher"),
+	}, nil
+}
+
+// holdForBlobs is holdForBundle for blob publication: it keeps a completed
+// task in memory until its bundle is published, or has failed to publish
+// maxBundlePublishAttempts times. Callers must hold the tasks lock.
+func (a *Aggregator) holdForBlobs(task *TaskInfo) bool {
+	if a.blobs == nil || !task.IsCompleted || task.ResultDataTx != nil {
+		return false
+	}
+	if task.blobPublishing {
+		return true
+	}
+	if task.blobAttempts >= maxBundlePublishAttempts {
+		return false
+	}
+
+	task.blobPublishing = true
+	task.blobAttempts++
+	bundle := newResultBundle(task)
+	go a.publishResultBlobs(task, bundle)
+	return true
+}
+
+// publishResultBlobs publishes the bundle and records its transaction on the task
+func (a *Aggregator) publishResultBlobs(task *TaskInfo, bundle ResultBundle) {
+	ctx, cancel := context.WithTimeout(context.Background(), blobPublishTimeout)
+	defer cancel()
+
+	published, err := a.blobs.publish(ctx, bundle)
+
+	a.tasksMutex.Lock()
+	defer a.tasksMutex.Unlock()
+	task.blobPublishing = false
+
+	if err != nil {
+		a.logger.Warn("Failed to publish task result data on chain",
+			"taskIndex", bundle.TaskIndex,
+			"attempt", task.blobAttempts,
+			"error", err,
+		)
+		return
+	}
+	task.ResultDataTx = published
+	a.logger.Info("Published task result data on chain",
+		"taskIndex", bundle.TaskIndex,
+		"txHash", published.TxHash.Hex(),
+		"encoding", published.Encoding,
+	)
+}
+
+// publish sends the bundle in blobs, or in calldata when the chain has no
+// blobs or their fee is above the cap and fallback is on, and waits for it
+// to be mined
+func (p *blobPublisher) publish(ctx context.Context, bundle ResultBundle) (*ResultDataTx, error) {
+	encoded, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result bundle: %w", err)
+	}
+	reference := make([]byte, common.HashLength+4)
+	copy(reference, bundle.TaskHash[:])
+	binary.BigEndian.PutUint32(reference[common.HashLength:], bundle.TaskIndex)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	header, err := p.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	tipCap, err := p.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas tip cap: %w", err)
+	}
+	feeCap := new(big.Int).Set(tipCap)
+	if header.BaseFee != nil {
+		// Leave room for the base fee to keep rising for a few blocks
+		feeCap.Add(feeCap, new(big.Int).Mul(header.BaseFee, big.NewInt(2)))
+	}
+	nonce, err := p.client.PendingNonceAt(ctx, p.from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	var tx *gethtypes.Transaction
+	var blobHashes []common.Hash
+	encoding := ResultDataEncodingBlob
+
+	blobFeeCap, err := p.blobFeeCap(header)
+	if err == nil {
+		tx, blobHashes, err = p.blobTx(ctx, nonce, tipCap, feeCap, blobFeeCap, reference, encoded)
+	}
+	if err != nil {
+		if !p.fallback || errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		p.logger.Info("Publishing result data in calldata instead of blobs", "taskIndex", bundle.TaskIndex, "reason", err)
+		encoding = ResultDataEncodingCalldata
+		tx, err = p.calldataTx(ctx, nonce, tipCap, feeCap, append(reference, encoded...))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.client.SendTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to send %s transaction: %w", encoding, err)
+	}
+	receipt, err := p.waitMined(ctx, tx.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("%s transaction %s reverted", encoding, tx.Hash().Hex())
+	}
+
+	return &ResultDataTx{
+		TxHash:      tx.Hash(),
+		BlockNumber: receipt.BlockNumber.Uint64(),
+		Encoding:    encoding,
+		BlobHashes:  blobHashes,
+	}, nil
+}
+
+// blobFeeCap returns the blob fee cap to offer: twice the current blob base
+// fee, limited by the configured cap
+func (p *blobPublisher) blobFeeCap(header *gethtypes.Header) (*big.Int, error) {
+	if header.ExcessBlobGas == nil {
+		return nil, errors.New("chain does not support blob transactions")
+	}
+	blobFee := eip4844.CalcBlobFee(*header.ExcessBlobGas)
+	if p.maxBlobFee != nil && blobFee.Cmp(p.maxBlobFee) > 0 {
+		return nil, fmt.Errorf("%w: %s wei, cap %s wei", errBlobFeeTooHigh, blobFee, p.maxBlobFee)
+	}
+
+	blobFeeCap := new(big.Int).Mul(blobFee, big.NewInt(2))
+	if p.maxBlobFee != nil && blobFeeCap.Cmp(p.maxBlobFee) > 0 {
+		blobFeeCap.Set(p.maxBlobFee)
+	}
+	return blobFeeCap, nil
+}
+
+func (p *blobPublisher) blobTx(ctx context.Context, nonce uint64, tipCap, feeCap, blobFeeCap *big.Int, reference, data []byte) (*gethtypes.Transaction, []common.Hash, error) {
+	encoded, err := blobs.Encode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	sidecar, err := blobs.NewSidecar(encoded)
+	if err != nil {
+		return nil, nil, err
+	}
+	blobHashes := blobs.VersionedHashes(sidecar)
+
+	gas, err := p.client.EstimateGas(ctx, ethereum.CallMsg{
+		From:       p.from,
+		To:         &p.inbox,
+		Data:       reference,
+		BlobHashes: blobHashes,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to estimate blob transaction gas: %w", err)
+	}
+
+	tx, err := p.signer.SignTx(ctx, gethtypes.NewTx(&gethtypes.BlobTx{
+		ChainID:    uint256.MustFromBig(p.chainId),
+		Nonce:      nonce,
+		GasTipCap:  uint256.MustFromBig(tipCap),
+		GasFeeCap:  uint256.MustFromBig(feeCap),
+		Gas:        gas,
+		To:         p.inbox,
+		Data:       reference,
+		BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+		BlobHashes: blobHashes,
+		Sidecar:    sidecar,
+	}), p.chainId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign blob transaction: %w", err)
+	}
+	return tx, blobHashes, nil
+}
+
+func (p *blobPublisher) calldataTx(ctx context.Context, nonce uint64, tipCap, feeCap *big.Int, data []byte) (*gethtypes.Transaction, error) {
+	gas, err := p.client.EstimateGas(ctx, ethereum.CallMsg{
+		From: p.from,
+		To:   &p.inbox,
+		Data: data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate calldata transaction gas: %w", err)
+	}
+
+	tx, err := p.signer.SignTx(ctx, gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+		ChainID:   p.chainId,
+		Nonce:     nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       gas,
+		To:        &p.inbox,
+		Data:      data,
+	}), p.chainId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign calldata transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// waitMined polls for the transaction's receipt until ctx is done
+func (p *blobPublisher) waitMined(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error) {
+	ticker := time.NewTicker(blobReceiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := p.client.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			p.logger.Debug("Failed to get result data receipt", "txHash", txHash.Hex(), "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("transaction %s not mined: %w", txHash.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/blsaggregation"
+	"github.com/eigenlvr/avs/pkg/digest"
+)
+
+// Aggregation backends
+const (
+	// aggregationBackendBuiltin buckets responses in the task map and checks
+	// the configured floors and thresholds itself
+	aggregationBackendBuiltin = "builtin"
+	// aggregationBackendBlsAgg hands signatures to eigensdk's BLS aggregation
+	// service, which checks thresholds against stake at the reference block
+	aggregationBackendBlsAgg = "blsagg"
+
+	// defaultTaskExpiry is the service manager's response window at 12 second blocks
+	defaultTaskExpiry = taskResponseWindowBlocks * 12 * time.Second
+)
+
+// newBlsAggregation returns the BLS aggregation service when the blsagg
+// backend is configured, and nil for the builtin one
+func newBlsAggregation(config Config, ethClient eth.Client, logger logging.Logger) (*blsaggregation.Service, error) {
+	switch config.AggregationBackend {
+	case "", aggregationBackendBuiltin:
+		return nil, nil
+	case aggregationBackendBlsAgg:
+	default:
+		return nil, fmt.Errorf("unknown aggregation backend %q", config.AggregationBackend)
+	}
+
+	if config.EthWsUrl == "" {
+		return nil, errors.New("the blsagg aggregation backend requires eth_ws_url")
+	}
+
+	taskExpiry := defaultTaskExpiry
+	if config.TaskExpiry != "" {
+		var err error
+		taskExpiry, err = time.ParseDuration(config.TaskExpiry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid task expiry: %w", err)
+		}
+	}
+
+	ethWsClient, err := eth.NewClient(config.EthWsUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create eth ws client: %w", err)
+	}
+
+	service, err := blsaggregation.New(context.Background(), blsaggregation.Config{
+		RegistryCoordinatorAddress:    common.HexToAddress(config.RegistryCoordinatorAddress),
+		OperatorStateRetrieverAddress: common.HexToAddress(config.OperatorStateRetrieverAddress),
+		EthClient:                     ethClient,
+		EthWsClient:                   ethWsClient,
+		HashFunction:                  taskResponseDigest,
+		TaskExpiry:                    taskExpiry,
+	}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bls aggregation service: %w", err)
+	}
+	return service, nil
+}
+
+// taskResponseDigest is the hash function the BLS aggregation service checks
+// signatures against, the same digest the builtin backend buckets by
+func taskResponseDigest(taskResponse types.TaskResponse) (types.TaskResponseDigest, error) {
+	response, ok := taskResponse.(TaskResponse)
+	if !ok {
+		return types.TaskResponseDigest{}, fmt.Errorf("unexpected task response type %T", taskResponse)
+	}
+	responseDigest, err := digest.AuctionTaskResponseDigest(
+		response.ReferenceTaskIndex,
+		response.Winner,
+		response.WinningBid,
+		response.TotalBids,
+	)
+	if err != nil {
+		return types.TaskResponseDigest{}, err
+	}
+	return types.TaskResponseDigest(responseDigest), nil
+}
+
+// submitToBlsAggregation verifies the response's signature and adds it to the
+// task's aggregate, initializing the task on its first response
+func (a *Aggregator) submitToBlsAggregation(ctx context.Context, signedResponse SignedTaskResponse) error {
+	taskIndex := signedResponse.TaskResponse.ReferenceTaskIndex
+
+	if !a.blsAggregation.Initialized(taskIndex) {
+		if err := a.initializeBlsAggregation(ctx, taskIndex); err != nil {
+			return fmt.Errorf("failed to initialize task aggregation: %w", err)
+		}
+	}
+
+	signature := signedResponse.BlsSignature
+	err := a.blsAggregation.ProcessSignature(ctx, taskIndex, signedResponse.TaskResponse, &signature, signedResponse.OperatorId)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, blsaggregation.ErrTaskClosed):
+		// Late responses are still recorded, as with the builtin backend
+		return nil
+	case errors.Is(err, blsaggregation.ErrIncorrectSignature):
+		a.recordInvalidSignature(signedResponse.OperatorId)
+		return ErrInvalidSignature
+	default:
+		return fmt.Errorf("%w: %v", ErrSignatureRejected, err)
+	}
+}
+
+// initializeBlsAggregation starts aggregating a task over its quorums and
+// thresholds, with the same reference block submissions use
+func (a *Aggregator) initializeBlsAggregation(ctx context.Context, taskIndex uint32) error {
+	a.tasksMutex.RLock()
+	task, exists := a.tasks[taskIndex]
+	if !exists {
+		task = &TaskInfo{TaskIndex: taskIndex}
+	}
+	createdBlock := task.TaskCreatedBlock
+	quorums := a.taskQuorums(task)
+	thresholds := make(types.QuorumThresholdPercentages, len(quorums))
+	for i, quorum := range quorums {
+		thresholds[i] = types.QuorumThresholdPercentage(a.quorumThreshold(task, quorum))
+	}
+	a.tasksMutex.RUnlock()
+
+	referenceBlock, err := a.referenceBlock(ctx, createdBlock)
+	if err != nil {
+		return err
+	}
+
+	return a.blsAggregation.Initialize(blsaggregation.Task{
+		Index:                      taskIndex,
+		ReferenceBlock:             referenceBlock,
+		QuorumNumbers:              quorums,
+		QuorumThresholdPercentages: thresholds,
+	})
+}
+
+// recordBlsAggregation records a task the BLS aggregation service finished
+func (a *Aggregator) recordBlsAggregation(result blsaggregation.Result) {
+	a.tasksMutex.Lock()
+	defer a.tasksMutex.Unlock()
+
+	task, exists := a.tasks[result.TaskIndex]
+	if !exists {
+		a.logger.Warn("Aggregation finished for a task no longer in memory", "taskIndex", result.TaskIndex)
+		return
+	}
+
+	if result.Err != nil {
+		a.logger.Warn("Task was not aggregated", "taskIndex", result.TaskIndex, "error", result.Err)
+		a.metrics.aggregated(task.PoolId, aggregationResultFailed, task.CreatedAt)
+		a.notifyMissedQuorum(task, result.Err.Error())
+		return
+	}
+
+	response, ok := result.TaskResponse.(TaskResponse)
+	if !ok {
+		a.logger.Error("Unexpected aggregated task response type", "taskIndex", result.TaskIndex, "type", fmt.Sprintf("%T", result.TaskResponse))
+		a.metrics.aggregated(task.PoolId, aggregationResultFailed, task.CreatedAt)
+		return
+	}
+
+	task.IsCompleted = true
+	task.AggregatedResponse = &response
+	task.AggregatedDigest = &result.Digest
+	task.Signers = result.Signers
+	task.nonSignerStakesAndSignature = &result.NonSignerStakesAndSignature
+	task.revision++
+	a.compareWithPrimary(task)
+	a.reportOutliers(task)
+	a.metrics.aggregated(task.PoolId, aggregationResultAggregated, task.CreatedAt)
+	a.publishTaskEvent(TaskEvent{
+		Type:         TaskEventQuorumReached,
+		TaskIndex:    task.TaskIndex,
+		PoolId:       task.PoolId,
+		Digest:       &result.Digest,
+		TaskResponse: &response,
+		Signers:      len(result.Signers),
+	})
+
+	a.logger.Info("Task aggregation completed",
+		"taskIndex", result.TaskIndex,
+		"digest", result.Digest.Hex(),
+		"winner", response.Winner.Hex(),
+		"signers", len(result.Signers),
+	)
+	a.notifyAuctionOutcome(task, response, len(result.Signers))
+	go a.submitTask(task)
+}
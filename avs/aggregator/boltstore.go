@@ -0,0 +1,393 @@
+package aggregator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	archivedTasksBucket = []byte("archived_tasks")
+	checkpointsBucket   = []byte("aggregation_checkpoints")
+)
+
+// compactTxMaxSize bounds how much a compaction copies per transaction
+const compactTxMaxSize = 64 << 20
+
+// BoltTaskStore is a TaskStore backed by a BoltDB file
+type BoltTaskStore struct {
+	path string
+
+	// mu guards db, which Compact replaces
+	mu sync.RWMutex
+	db *bolt.DB
+}
+
+var _ TaskStore = (*BoltTaskStore)(nil)
+
+func NewBoltTaskStore(path string) (*BoltTaskStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create task store directory: %w", err)
+	}
+
+	db, err := openBoltDb(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{archivedTasksBucket, checkpointsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize task store: %w", err)
+	}
+
+	return &BoltTaskStore{path: path, db: db}, nil
+}
+
+// OpenBoltTaskStoreReadOnly opens an existing task store for reading, e.g. to
+// build reports from it. The file is locked by the aggregator while it runs,
+// so point it at a stopped aggregator's store or a copy.
+func OpenBoltTaskStoreReadOnly(path string) (*BoltTaskStore, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open task store: %w", err)
+	}
+	db, err := bolt.Open(path, 0o400, &bolt.Options{Timeout: 5 * time.Second, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task store: %w", err)
+	}
+	return &BoltTaskStore{path: path, db: db}, nil
+}
+
+// ArchiveTask stores the task keyed by task index and creation time, so a
+// reused task index never overwrites an earlier archived task
+func (s *BoltTaskStore) ArchiveTask(task ArchivedTask) error {
+	value, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to encode archived task: %w", err)
+	}
+
+	return s.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(archivedTasksBucket).Put(archivedTaskKey(task), value)
+	})
+}
+
+// SaveCheckpoint stores the checkpoint keyed by task index, replacing any
+// earlier checkpoint of the same task
+func (s *BoltTaskStore) SaveCheckpoint(checkpoint AggregationCheckpoint) error {
+	value, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode aggregation checkpoint: %w", err)
+	}
+
+	return s.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(checkpointsBucket).Put(taskIndexKey(checkpoint.TaskIndex), value)
+	})
+}
+
+func (s *BoltTaskStore) DeleteCheckpoint(taskIndex uint32) error {
+	return s.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(checkpointsBucket).Delete(taskIndexKey(taskIndex))
+	})
+}
+
+func (s *BoltTaskStore) LoadCheckpoints() ([]AggregationCheckpoint, error) {
+	var checkpoints []AggregationCheckpoint
+	err := s.view(func(tx *bolt.Tx) error {
+		return tx.Bucket(checkpointsBucket).ForEach(func(key, value []byte) error {
+			var checkpoint AggregationCheckpoint
+			if err := json.Unmarshal(value, &checkpoint); err != nil {
+				return fmt.Errorf("failed to decode checkpoint %x: %w", key, err)
+			}
+			checkpoints = append(checkpoints, checkpoint)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+func (s *BoltTaskStore) ListArchivedTasks(filter HistoryFilter) ([]ArchivedTask, error) {
+	var tasks []ArchivedTask
+	err := s.view(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(archivedTasksBucket).Cursor()
+
+		// Keys sort by task index, so walk backwards from the first key past the page
+		var key, value []byte
+		if filter.BeforeTaskIndex != nil {
+			key, _ = cursor.Seek(taskIndexKey(*filter.BeforeTaskIndex))
+			if key == nil {
+				key, value = cursor.Last()
+			} else {
+				key, value = cursor.Prev()
+			}
+		} else {
+			key, value = cursor.Last()
+		}
+
+		for ; key != nil && (filter.Limit <= 0 || len(tasks) < filter.Limit); key, value = cursor.Prev() {
+			var task ArchivedTask
+			if err := json.Unmarshal(value, &task); err != nil {
+				return fmt.Errorf("failed to decode archived task %x: %w", key, err)
+			}
+			if task.DeletedAt != nil && !filter.IncludeDeleted {
+				continue
+			}
+			tasks = append(tasks, task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (s *BoltTaskStore) SoftDeleteArchivedTask(taskIndex uint32) (int, error) {
+	deleted := 0
+	err := s.update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(archivedTasksBucket)
+		cursor := bucket.Cursor()
+		prefix := taskIndexKey(taskIndex)
+
+		// Collect first, since the bucket can't be modified while iterating
+		updates := make(map[string][]byte)
+		for key, value := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+			var task ArchivedTask
+			if err := json.Unmarshal(value, &task); err != nil {
+				return fmt.Errorf("failed to decode archived task %x: %w", key, err)
+			}
+			if task.DeletedAt != nil {
+				continue
+			}
+
+			deletedAt := time.Now().UTC()
+			task.DeletedAt = &deletedAt
+			updated, err := json.Marshal(task)
+			if err != nil {
+				return fmt.Errorf("failed to encode archived task: %w", err)
+			}
+			updates[string(key)] = updated
+		}
+
+		for key, value := range updates {
+			if err := bucket.Put([]byte(key), value); err != nil {
+				return err
+			}
+		}
+		deleted = len(updates)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+func (s *BoltTaskStore) GetArchivedTask(taskIndex uint32) (*ArchivedTask, error) {
+	var found *ArchivedTask
+	err := s.view(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(archivedTasksBucket).Cursor()
+		prefix := taskIndexKey(taskIndex)
+
+		// Records of the same index sort by creation time, so the last one wins
+		for key, value := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+			var task ArchivedTask
+			if err := json.Unmarshal(value, &task); err != nil {
+				return fmt.Errorf("failed to decode archived task %x: %w", key, err)
+			}
+			if task.DeletedAt == nil {
+				found = &task
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// Prune deletes, in one transaction, every record the policy no longer keeps
+func (s *BoltTaskStore) Prune(policy RetentionPolicy, now time.Time) (PruneResult, error) {
+	var result PruneResult
+	err := s.update(func(tx *bolt.Tx) error {
+		archived := tx.Bucket(archivedTasksBucket)
+		var expired [][]byte
+		err := archived.ForEach(func(key, value []byte) error {
+			var task ArchivedTask
+			if err := json.Unmarshal(value, &task); err != nil {
+				return fmt.Errorf("failed to decode archived task %x: %w", key, err)
+			}
+			switch {
+			case task.DeletedAt != nil && policy.DeletedTasks > 0 && now.Sub(*task.DeletedAt) > policy.DeletedTasks:
+				result.DeletedTasks++
+			case policy.ArchivedTasks > 0 && now.Sub(task.ArchivedAt) > policy.ArchivedTasks:
+				result.ArchivedTasks++
+			default:
+				return nil
+			}
+			expired = append(expired, key)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range expired {
+			if err := archived.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		if policy.Checkpoints <= 0 {
+			return nil
+		}
+		checkpoints := tx.Bucket(checkpointsBucket)
+		expired = nil
+		err = checkpoints.ForEach(func(key, value []byte) error {
+			var checkpoint AggregationCheckpoint
+			if err := json.Unmarshal(value, &checkpoint); err != nil {
+				return fmt.Errorf("failed to decode checkpoint %x: %w", key, err)
+			}
+			if now.Sub(checkpoint.CreatedAt) > policy.Checkpoints {
+				expired = append(expired, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range expired {
+			if err := checkpoints.Delete(key); err != nil {
+				return err
+			}
+		}
+		result.Checkpoints = len(expired)
+		return nil
+	})
+	if err != nil {
+		return PruneResult{}, err
+	}
+	return result, nil
+}
+
+// Compact copies the store into a fresh file and swaps it in, since BoltDB
+// reuses the pages deletes free but never returns them to the filesystem.
+// Other calls block while it runs.
+func (s *BoltTaskStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	compactPath := s.path + ".compact"
+	os.Remove(compactPath)
+	compacted, err := openBoltDb(compactPath)
+	if err != nil {
+		return err
+	}
+	if err := bolt.Compact(compacted, s.db, compactTxMaxSize); err != nil {
+		compacted.Close()
+		os.Remove(compactPath)
+		return fmt.Errorf("failed to compact task store: %w", err)
+	}
+	if err := compacted.Close(); err != nil {
+		os.Remove(compactPath)
+		return fmt.Errorf("failed to close compacted task store: %w", err)
+	}
+
+	if err := s.db.Close(); err != nil {
+		os.Remove(compactPath)
+		return fmt.Errorf("failed to close task store: %w", err)
+	}
+	renameErr := os.Rename(compactPath, s.path)
+	if renameErr != nil {
+		os.Remove(compactPath)
+	}
+
+	// Reopen whichever file is now at the path, so a failed swap leaves the
+	// original store in use
+	db, err := openBoltDb(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to reopen task store after compaction: %w", err)
+	}
+	s.db = db
+	if renameErr != nil {
+		return fmt.Errorf("failed to replace task store with compacted copy: %w", renameErr)
+	}
+	return nil
+}
+
+func (s *BoltTaskStore) Usage() (StoreUsage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	usage := StoreUsage{
+		FreeBytes: int64(s.db.Stats().FreeAlloc),
+		Records:   make(map[string]int),
+	}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		usage.FileBytes = tx.Size()
+		for _, bucket := range [][]byte{archivedTasksBucket, checkpointsBucket} {
+			usage.Records[string(bucket)] = tx.Bucket(bucket).Stats().KeyN
+		}
+		return nil
+	})
+	if err != nil {
+		return StoreUsage{}, err
+	}
+	return usage, nil
+}
+
+func (s *BoltTaskStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.db.Close()
+}
+
+func (s *BoltTaskStore) view(fn func(*bolt.Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.View(fn)
+}
+
+func (s *BoltTaskStore) update(fn func(*bolt.Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.db.Update(fn)
+}
+
+func openBoltDb(path string) (*bolt.DB, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task store: %w", err)
+	}
+	return db, nil
+}
+
+func archivedTaskKey(task ArchivedTask) []byte {
+	key := make([]byte, 12)
+	binary.BigEndian.PutUint32(key[:4], task.TaskIndex)
+	binary.BigEndian.PutUint64(key[4:], uint64(task.CreatedAt.UnixNano()))
+	return key
+}
+
+// taskIndexKey is the key of a checkpoint, and the prefix of every archived task with the index
+func taskIndexKey(taskIndex uint32) []byte {
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, taskIndex)
+	return key
+}
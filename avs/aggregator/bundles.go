@@ -0,0 +1,163 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/sigchecker"
+)
+
+const (
+	// resultBundleVersion is bumped whenever the bundle layout changes
+	resultBundleVersion = 1
+
+	// maxBundlePublishAttempts is how many cleanup passes try to publish a
+	// task's bundle before it is archived without one
+	maxBundlePublishAttempts = 3
+
+	// bundlePublishTimeout bounds a single publication
+	bundlePublishTimeout = time.Minute
+)
+
+// ResultBundle is the public record of an auction task: every accepted
+// response with its signature, the aggregate and its settlement
+type ResultBundle struct {
+	Version                   int              `json:"version"`
+	TaskIndex                 uint32           `json:"taskIndex"`
+	TaskHash                  common.Hash      `json:"taskHash"`
+	PoolId                    common.Hash      `json:"poolId"`
+	TaskCreatedBlock          uint32           `json:"taskCreatedBlock"`
+	QuorumNumbers             types.QuorumNums `json:"quorumNumbers"`
+	QuorumThresholdPercentage uint32           `json:"quorumThresholdPercentage"`
+	CreatedAt                 time.Time        `json:"createdAt"`
+	Responses                 []BundleResponse `json:"responses"`
+	Aggregate                 *BundleAggregate `json:"aggregate,omitempty"`
+	Settlement                BundleSettlement `json:"settlement"`
+}
+
+// BundleResponse is an operator's signed response, with its committee
+// ticket when committees are selected by VRF
+type BundleResponse struct {
+	OperatorId     string         `json:"operatorId"`
+	TaskResponse   TaskResponse   `json:"taskResponse"`
+	Digest         common.Hash    `json:"digest"`
+	BlsSignature   bls.Signature  `json:"blsSignature"`
+	CommitteeProof *bls.Signature `json:"committeeProof,omitempty"`
+}
+
+// BundleAggregate is the aggregated response and the checkSignatures
+// argument submitted with it
+type BundleAggregate struct {
+	TaskResponse                TaskResponse                            `json:"taskResponse"`
+	Digest                      common.Hash                             `json:"digest"`
+	Signers                     []string                                `json:"signers"`
+	QuorumAggregates            []QuorumAggregate                       `json:"quorumAggregates,omitempty"`
+	NonSignerStakesAndSignature *sigchecker.NonSignerStakesAndSignature `json:"nonSignerStakesAndSignature,omitempty"`
+}
+
+// BundleSettlement is how the aggregate reached the chain
+type BundleSettlement struct {
+	SubmissionTxHash      *common.Hash `json:"submissionTxHash,omitempty"`
+	SubmissionBlockNumber *uint64      `json:"submissionBlockNumber,omitempty"`
+}
+
+// newResultBundle assembles a task's bundle. Callers must hold the tasks lock.
+func newResultBundle(task *TaskInfo) ResultBundle {
+	bundle := ResultBundle{
+		Version:                   resultBundleVersion,
+		TaskIndex:                 task.TaskIndex,
+		TaskHash:                  task.TaskHash,
+		PoolId:                    task.PoolId,
+		TaskCreatedBlock:          task.TaskCreatedBlock,
+		QuorumNumbers:             task.QuorumNumbers,
+		QuorumThresholdPercentage: uint32(task.QuorumThresholdPercentage),
+		CreatedAt:                 task.CreatedAt,
+		Responses:                 make([]BundleResponse, 0, len(task.TaskResponsesInfo)),
+		Settlement: BundleSettlement{
+			SubmissionTxHash:      task.SubmissionTxHash,
+			SubmissionBlockNumber: task.SubmissionBlockNumber,
+		},
+	}
+
+	for operatorId, info := range task.TaskResponsesInfo {
+		bundle.Responses = append(bundle.Responses, BundleResponse{
+			OperatorId:     hex.EncodeToString(operatorId[:]),
+			TaskResponse:   info.TaskResponse,
+			Digest:         info.Digest,
+			BlsSignature:   info.BlsSignature,
+			CommitteeProof: info.CommitteeProof,
+		})
+	}
+	sort.Slice(bundle.Responses, func(i, j int) bool {
+		return bundle.Responses[i].OperatorId < bundle.Responses[j].OperatorId
+	})
+
+	if task.AggregatedResponse != nil && task.AggregatedDigest != nil {
+		aggregate := &BundleAggregate{
+			TaskResponse:                *task.AggregatedResponse,
+			Digest:                      *task.AggregatedDigest,
+			Signers:                     make([]string, 0, len(task.Signers)),
+			QuorumAggregates:            task.QuorumAggregates,
+			NonSignerStakesAndSignature: task.nonSignerStakesAndSignature,
+		}
+		for _, signer := range task.Signers {
+			aggregate.Signers = append(aggregate.Signers, hex.EncodeToString(signer[:]))
+		}
+		bundle.Aggregate = aggregate
+	}
+
+	return bundle
+}
+
+// holdForBundle reports whether cleanup should keep the task in memory while
+// its result bundle is published, starting the publication if it hasn't
+// started yet. Bundles are only published once a task is about to leave
+// memory, so they include its final aggregate and settlement. Callers must
+// hold the tasks lock.
+func (a *Aggregator) holdForBundle(task *TaskInfo) bool {
+	if a.ipfs == nil || !task.IsCompleted || task.ResultBundleCid != nil {
+		return false
+	}
+	if task.bundlePublishing {
+		return true
+	}
+	if task.bundleAttempts >= maxBundlePublishAttempts {
+		return false
+	}
+
+	task.bundlePublishing = true
+	task.bundleAttempts++
+	bundle := newResultBundle(task)
+	go a.publishResultBundle(task, bundle)
+	return true
+}
+
+// publishResultBundle pins the bundle to IPFS and records its CID on the task
+func (a *Aggregator) publishResultBundle(task *TaskInfo, bundle ResultBundle) {
+	ctx, cancel := context.WithTimeout(context.Background(), bundlePublishTimeout)
+	defer cancel()
+
+	cid, err := a.ipfs.AddJSON(ctx, fmt.Sprintf("task-%d.json", bundle.TaskIndex), bundle)
+
+	a.tasksMutex.Lock()
+	defer a.tasksMutex.Unlock()
+	task.bundlePublishing = false
+
+	if err != nil {
+		a.logger.Warn("Failed to publish task result bundle",
+			"taskIndex", bundle.TaskIndex,
+			"attempt", task.bundleAttempts,
+			"error", err,
+		)
+		return
+	}
+	task.ResultBundleCid = &cid
+	a.logger.Info("Published task result bundle", "taskIndex", bundle.TaskIndex, "cid", cid)
+}
@@ -0,0 +1,117 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/eigenlvr/avs/pkg/certificate"
+)
+
+var (
+	// ErrNotCertified is returned for tasks without an aggregate to certify
+	ErrNotCertified = errors.New("task has no aggregate to certify")
+	// ErrNoCertificateKey is returned when certificates are requested without
+	// an aggregator key to sign them
+	ErrNoCertificateKey = errors.New("no aggregator key configured")
+)
+
+// GetCertificate returns the signed auction certificate of a task in memory.
+// Tasks aggregated by the BLS aggregation service certify no quorum tallies,
+// as the service checks thresholds without reporting stakes.
+func (a *Aggregator) GetCertificate(ctx context.Context, taskIndex uint32) (certificate.SignedCertificate, error) {
+	if a.ackSigner == nil {
+		return certificate.SignedCertificate{}, ErrNoCertificateKey
+	}
+
+	a.tasksMutex.RLock()
+	task, exists := a.tasks[taskIndex]
+	if !exists {
+		a.tasksMutex.RUnlock()
+		return certificate.SignedCertificate{}, ErrUnknownTask
+	}
+	unsigned, err := newCertificate(task)
+	a.tasksMutex.RUnlock()
+	if err != nil {
+		return certificate.SignedCertificate{}, err
+	}
+
+	return certificate.Sign(ctx, unsigned, a.ackSigner)
+}
+
+// newCertificate assembles a task's certificate. Callers must hold the tasks lock.
+func newCertificate(task *TaskInfo) (certificate.Certificate, error) {
+	if task.AggregatedResponse == nil || task.nonSignerStakesAndSignature == nil {
+		return certificate.Certificate{}, ErrNotCertified
+	}
+
+	response := task.AggregatedResponse
+	unsigned := certificate.Certificate{
+		TaskIndex:        task.TaskIndex,
+		PoolId:           task.PoolId,
+		TaskCreatedBlock: task.TaskCreatedBlock,
+		Winner:           response.Winner,
+		WinningBid:       response.WinningBid,
+		TotalBids:        response.TotalBids,
+		Signers:          uint16(min(len(task.Signers), math.MaxUint16)),
+		Quorums:          make([]certificate.Quorum, 0, len(task.QuorumAggregates)),
+		ApkG2:            task.nonSignerStakesAndSignature.ApkG2,
+		Sigma:            task.nonSignerStakesAndSignature.Sigma,
+	}
+	for _, aggregate := range task.QuorumAggregates {
+		unsigned.Quorums = append(unsigned.Quorums, certificate.Quorum{
+			Number:           uint8(aggregate.QuorumNumber),
+			ThresholdPercent: uint8(aggregate.ThresholdPercent),
+			SignedStake:      aggregate.SignedStake,
+			TotalStake:       aggregate.TotalStake,
+		})
+	}
+	return unsigned, nil
+}
+
+// certificateHandler serves GET /task/{taskIndex}/certificate, as JSON or,
+// with ?format=binary, in the compact layout
+func (a *Aggregator) certificateHandler(w http.ResponseWriter, r *http.Request) {
+	taskIndex, err := strconv.ParseUint(mux.Vars(r)["taskIndex"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid task index", http.StatusBadRequest)
+		return
+	}
+
+	signed, err := a.GetCertificate(r.Context(), uint32(taskIndex))
+	switch {
+	case errors.Is(err, ErrUnknownTask):
+		http.Error(w, "Unknown task", http.StatusNotFound)
+		return
+	case errors.Is(err, ErrNotCertified):
+		http.Error(w, "Task has not been aggregated", http.StatusNotFound)
+		return
+	case errors.Is(err, ErrNoCertificateKey):
+		http.Error(w, "Certificates are not signed by this aggregator", http.StatusNotImplemented)
+		return
+	case err != nil:
+		a.logger.Error("Failed to issue task certificate", "taskIndex", taskIndex, "error", err)
+		http.Error(w, "Failed to issue certificate", http.StatusInternalServerError)
+		return
+	}
+
+	if r.URL.Query().Get("format") == "binary" {
+		encoded, err := signed.MarshalBinary()
+		if err != nil {
+			a.logger.Error("Failed to encode task certificate", "taskIndex", taskIndex, "error", err)
+			http.Error(w, "Failed to encode certificate", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(encoded)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(signed)
+}
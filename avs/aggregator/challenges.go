@@ -0,0 +1,754 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/challengereport"
+	"github.com/eigenlvr/avs/pkg/digest"
+	"github.com/eigenlvr/avs/pkg/logwatcher"
+	"github.com/eigenlvr/avs/pkg/notify"
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+)
+
+const (
+	// taskChallengeWindowBlocks mirrors the service manager's TASK_CHALLENGE_WINDOW_BLOCK
+	taskChallengeWindowBlocks = 100
+	// defaultChallengeWindow is the service manager's challenge window at 12
+	// second blocks
+	defaultChallengeWindow = taskChallengeWindowBlocks * 12 * time.Second
+
+	// maxApiChallengesPerTask bounds the challenges a task takes through the
+	// API. Challenges raised on chain are staked and don't count against it.
+	maxApiChallengesPerTask = 16
+
+	// maxChallengeReasonLength bounds the reason a challenger gives
+	maxChallengeReasonLength = 1024
+
+	// challengeEvidenceVersion is bumped whenever the evidence layout changes
+	challengeEvidenceVersion = 1
+)
+
+// Where a challenge was raised
+const (
+	challengeSourceChain = "chain"
+	challengeSourceApi   = "api"
+)
+
+// Outcomes of reviewing a challenge
+const (
+	// ChallengeOutcomePending is a challenge still being reviewed
+	ChallengeOutcomePending = "pending"
+	// ChallengeOutcomeRefuted is a challenge against an aggregate the
+	// re-aggregation reproduced
+	ChallengeOutcomeRefuted = "refuted"
+	// ChallengeOutcomeUpheld is a challenge against an aggregate the
+	// re-aggregation found faults in
+	ChallengeOutcomeUpheld = "upheld"
+	// ChallengeOutcomeUnreviewable is a challenge against a task this
+	// aggregator holds no aggregate for
+	ChallengeOutcomeUnreviewable = "unreviewable"
+)
+
+var (
+	// ErrNotChallengeable is returned for tasks without an aggregate to challenge
+	ErrNotChallengeable = errors.New("task has no aggregated response to challenge")
+	// ErrChallengeWindowClosed is returned once a task's aggregate is final
+	ErrChallengeWindowClosed = errors.New("task's challenge window has closed")
+	// ErrAlreadyChallenged is returned when a challenger challenges a task twice
+	ErrAlreadyChallenged = errors.New("challenger has already challenged the task")
+	// ErrTooManyChallenges is returned once a task has maxApiChallengesPerTask
+	ErrTooManyChallenges = errors.New("task has too many challenges")
+	// ErrUnsignedChallenge is returned for API challenges whose signature
+	// doesn't recover to the challenger
+	ErrUnsignedChallenge = errors.New("challenge is not signed by its challenger")
+)
+
+// Challenge is a challenge against a task's aggregated response and the
+// outcome of re-aggregating the task from its stored responses
+type Challenge struct {
+	Challenger common.Address `json:"challenger"`
+	// Source is "chain" for TaskChallenged events and "api" for challenges
+	// raised through the aggregator
+	Source      string       `json:"source"`
+	Reason      string       `json:"reason,omitempty"`
+	TxHash      *common.Hash `json:"txHash,omitempty"`
+	BlockNumber *uint64      `json:"blockNumber,omitempty"`
+	RaisedAt    time.Time    `json:"raisedAt"`
+	Outcome     string       `json:"outcome"`
+	// Findings are the ways the recorded aggregate differs from the
+	// re-aggregation, empty when it was reproduced
+	Findings   []string   `json:"findings,omitempty"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+	// EvidenceCid is the IPFS CID of the published ChallengeEvidence
+	EvidenceCid *string `json:"evidenceCid,omitempty"`
+}
+
+// ChallengeRequest is a challenge raised through the API. Signature is the
+// challenger's signature over the challengereport.Report for the task.
+type ChallengeRequest struct {
+	Challenger common.Address `json:"challenger"`
+	Reason     string         `json:"reason"`
+	Signature  hexutil.Bytes  `json:"signature"`
+}
+
+// QuorumReview is a quorum's tally recomputed from the responses over the
+// aggregate's digest
+type QuorumReview struct {
+	QuorumNumber     types.QuorumNum `json:"quorumNumber"`
+	ThresholdPercent uint32          `json:"thresholdPercent"`
+	SignedStake      *big.Int        `json:"signedStake"`
+	TotalStake       *big.Int        `json:"totalStake"`
+	Met              bool            `json:"met"`
+}
+
+// ChallengeEvidence is the record published for a reviewed challenge: the
+// responses the aggregate was built from, the operator set it was evaluated
+// against and the re-aggregated tallies. Tasks reviewed from the task store
+// no longer have their signatures, so their evidence points at the result
+// bundle published before they were archived instead.
+type ChallengeEvidence struct {
+	Version         int                  `json:"version"`
+	TaskIndex       uint32               `json:"taskIndex"`
+	Challenge       Challenge            `json:"challenge"`
+	Bundle          *ResultBundle        `json:"bundle,omitempty"`
+	ResultBundleCid *string              `json:"resultBundleCid,omitempty"`
+	OperatorSet     *OperatorSetSnapshot `json:"operatorSet,omitempty"`
+	Quorums         []QuorumReview       `json:"quorums,omitempty"`
+}
+
+// challengeTracker holds the challenge window and follows the challenges
+// raised on chain
+type challengeTracker struct {
+	window time.Duration
+	// watcher follows TaskChallenged events, nil without a service manager
+	watcher *logwatcher.Watcher
+	reviews *prometheus.CounterVec
+}
+
+func newChallengeTracker(config Config, client eth.Client, reg prometheus.Registerer, logger logging.Logger) (*challengeTracker, error) {
+	tracker := &challengeTracker{
+		window: defaultChallengeWindow,
+		reviews: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "challenges_total",
+			Help:      "Challenges against aggregated responses, by where they were raised and the outcome of their review",
+		}, []string{"source", "outcome"}),
+	}
+	reg.MustRegister(tracker.reviews)
+
+	if config.ChallengeWindow != "" {
+		window, err := time.ParseDuration(config.ChallengeWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid challenge window: %w", err)
+		}
+		tracker.window = window
+	}
+
+	if config.ServiceManagerAddress != "" {
+		tracker.watcher = logwatcher.NewWatcher(
+			logwatcher.Config{
+				Name:      "challenges",
+				Addresses: []common.Address{common.HexToAddress(config.ServiceManagerAddress)},
+				Topics:    [][]common.Hash{{servicemanager.TaskChallengedTopic}},
+				WsUrl:     config.EthWsUrl,
+			},
+			client,
+			logwatcher.NewFileCheckpoint(config.ChallengeCheckpointPath),
+			reg,
+			logger,
+		)
+	}
+	return tracker, nil
+}
+
+// watchChallenges reviews every challenge raised on chain until the context
+// is done
+func (a *Aggregator) watchChallenges(ctx context.Context) {
+	err := a.challenges.watcher.Run(ctx, func(log gethtypes.Log) error {
+		event, err := servicemanager.ParseTaskChallenged(log)
+		if err != nil {
+			a.logger.Warn("Skipping undecodable task challenge", "txHash", log.TxHash.Hex(), "error", err)
+			return nil
+		}
+		a.recordChainChallenge(event)
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		a.logger.Error("Stopped following task challenges", "error", err)
+	}
+}
+
+// recordChainChallenge reviews a TaskChallenged event against the task in
+// memory, or against its archived record once it has left memory. Events
+// seen before are skipped.
+func (a *Aggregator) recordChainChallenge(event *servicemanager.TaskChallenged) {
+	txHash := event.Raw.TxHash
+	blockNumber := event.Raw.BlockNumber
+	challenge := Challenge{
+		Challenger:  event.Challenger,
+		Source:      challengeSourceChain,
+		TxHash:      &txHash,
+		BlockNumber: &blockNumber,
+		RaisedAt:    time.Now().UTC(),
+		Outcome:     ChallengeOutcomePending,
+	}
+	a.logger.Warn("Task challenged on chain",
+		"taskIndex", event.TaskIndex,
+		"challenger", event.Challenger.Hex(),
+		"txHash", txHash.Hex(),
+	)
+
+	a.tasksMutex.Lock()
+	task, exists := a.tasks[event.TaskIndex]
+	if !exists {
+		a.tasksMutex.Unlock()
+		a.reviewArchivedChallenge(event.TaskIndex, challenge)
+		return
+	}
+	if hasChallengeTx(task.Challenges, txHash) {
+		a.tasksMutex.Unlock()
+		return
+	}
+	index := addChallenge(task, challenge)
+	subject := newChallengeSubject(task)
+	a.tasksMutex.Unlock()
+
+	a.reviewChallenge(task, index, subject, challenge)
+}
+
+// ChallengeTask raises a challenge against the task's aggregate, which is
+// re-aggregated from the stored responses before the reviewed challenge is
+// returned. Challenges are taken until the task's challenge deadline, once
+// per challenger, and only when signed by the challenger.
+func (a *Aggregator) ChallengeTask(ctx context.Context, taskIndex uint32, request ChallengeRequest) (Challenge, error) {
+	report := challengereport.SignedReport{
+		Report: challengereport.Report{
+			TaskIndex:  taskIndex,
+			Challenger: request.Challenger,
+			Reason:     request.Reason,
+		},
+		Signature: request.Signature,
+	}
+	if err := report.Verify(); err != nil {
+		return Challenge{}, fmt.Errorf("%w: %v", ErrUnsignedChallenge, err)
+	}
+
+	a.tasksMutex.Lock()
+	task, exists := a.tasks[taskIndex]
+	if !exists {
+		a.tasksMutex.Unlock()
+		return Challenge{}, ErrUnknownTask
+	}
+	if task.AggregatedResponse == nil || task.ChallengeDeadline == nil {
+		a.tasksMutex.Unlock()
+		return Challenge{}, ErrNotChallengeable
+	}
+	if time.Now().After(*task.ChallengeDeadline) {
+		a.tasksMutex.Unlock()
+		return Challenge{}, ErrChallengeWindowClosed
+	}
+	apiChallenges := 0
+	for _, existing := range task.Challenges {
+		if existing.Source != challengeSourceApi {
+			continue
+		}
+		if existing.Challenger == request.Challenger {
+			a.tasksMutex.Unlock()
+			return Challenge{}, ErrAlreadyChallenged
+		}
+		apiChallenges++
+	}
+	if apiChallenges >= maxApiChallengesPerTask {
+		a.tasksMutex.Unlock()
+		return Challenge{}, ErrTooManyChallenges
+	}
+
+	challenge := Challenge{
+		Challenger: request.Challenger,
+		Source:     challengeSourceApi,
+		Reason:     request.Reason,
+		RaisedAt:   time.Now().UTC(),
+		Outcome:    ChallengeOutcomePending,
+	}
+	index := addChallenge(task, challenge)
+	subject := newChallengeSubject(task)
+	a.tasksMutex.Unlock()
+
+	a.logger.Warn("Task challenged through the API",
+		"taskIndex", taskIndex,
+		"challenger", request.Challenger.Hex(),
+		"reason", request.Reason,
+	)
+	return a.reviewChallenge(task, index, subject, challenge), nil
+}
+
+// challengeSubject is what a challenged task is re-aggregated from, copied
+// out of the task so the review runs without the tasks lock
+type challengeSubject struct {
+	taskIndex   uint32
+	poolId      common.Hash
+	aggregate   *TaskResponse
+	digest      *common.Hash
+	signers     []types.OperatorId
+	responses   []TaskResponseInfo
+	operatorSet *OperatorSetSnapshot
+	// signed is set when the responses still carry their BLS signatures
+	signed          bool
+	bundle          *ResultBundle
+	resultBundleCid *string
+}
+
+// newChallengeSubject copies a task in memory. Callers must hold the tasks lock.
+func newChallengeSubject(task *TaskInfo) challengeSubject {
+	bundle := newResultBundle(task)
+	subject := challengeSubject{
+		taskIndex:       task.TaskIndex,
+		poolId:          task.PoolId,
+		aggregate:       task.AggregatedResponse,
+		digest:          task.AggregatedDigest,
+		signers:         task.Signers,
+		responses:       make([]TaskResponseInfo, 0, len(task.TaskResponsesInfo)),
+		operatorSet:     task.OperatorSetSnapshot,
+		signed:          true,
+		bundle:          &bundle,
+		resultBundleCid: task.ResultBundleCid,
+	}
+	for _, info := range task.TaskResponsesInfo {
+		subject.responses = append(subject.responses, info)
+	}
+	return subject
+}
+
+// archivedChallengeSubject rebuilds the subject of an archived task, whose
+// responses are kept without their signatures
+func archivedChallengeSubject(archived ArchivedTask) (challengeSubject, error) {
+	subject := challengeSubject{
+		taskIndex:       archived.TaskIndex,
+		poolId:          archived.PoolId,
+		aggregate:       archived.AggregatedResponse,
+		digest:          archived.AggregatedDigest,
+		responses:       make([]TaskResponseInfo, 0, len(archived.Responses)),
+		operatorSet:     archived.OperatorSetSnapshot,
+		resultBundleCid: archived.ResultBundleCid,
+	}
+	for _, signer := range archived.Signers {
+		operatorId, err := parseOperatorId(signer)
+		if err != nil {
+			return challengeSubject{}, err
+		}
+		subject.signers = append(subject.signers, operatorId)
+	}
+	for _, response := range archived.Responses {
+		operatorId, err := parseOperatorId(response.OperatorId)
+		if err != nil {
+			return challengeSubject{}, err
+		}
+		subject.responses = append(subject.responses, TaskResponseInfo{
+			TaskResponse: response.TaskResponse,
+			OperatorId:   operatorId,
+			Digest:       response.Digest,
+		})
+	}
+	return subject, nil
+}
+
+// reviewChallenge re-aggregates the task, records the reviewed challenge at
+// its index and publishes its evidence
+func (a *Aggregator) reviewChallenge(task *TaskInfo, index int, subject challengeSubject, challenge Challenge) Challenge {
+	reviewed, evidence := a.reaggregateChallenge(subject, challenge)
+
+	a.tasksMutex.Lock()
+	task.challengeReviews--
+	setChallenge(task, index, reviewed)
+	publish := a.ipfs != nil && reviewed.Outcome != ChallengeOutcomeUnreviewable
+	if publish {
+		task.challengeReviews++
+	}
+	a.tasksMutex.Unlock()
+
+	a.reportChallenge(subject.taskIndex, subject.poolId, reviewed)
+	if publish {
+		go a.publishChallengeEvidence(task, index, evidence)
+	}
+	return reviewed
+}
+
+// reviewArchivedChallenge reviews an on-chain challenge against a task that
+// has left memory, and archives the task again with the challenge recorded
+func (a *Aggregator) reviewArchivedChallenge(taskIndex uint32, challenge Challenge) {
+	var archived *ArchivedTask
+	if a.taskStore != nil {
+		var err error
+		archived, err = a.taskStore.GetArchivedTask(taskIndex)
+		if err != nil {
+			a.logger.Error("Failed to look up challenged task", "taskIndex", taskIndex, "error", err)
+			return
+		}
+	}
+	if archived == nil {
+		challenge.Outcome = ChallengeOutcomeUnreviewable
+		a.reportChallenge(taskIndex, common.Hash{}, challenge)
+		return
+	}
+	if hasChallengeTx(archived.Challenges, *challenge.TxHash) {
+		return
+	}
+
+	subject, err := archivedChallengeSubject(*archived)
+	if err != nil {
+		a.logger.Error("Failed to read archived task for challenge review", "taskIndex", taskIndex, "error", err)
+		return
+	}
+	reviewed, evidence := a.reaggregateChallenge(subject, challenge)
+	if a.ipfs != nil && reviewed.Outcome != ChallengeOutcomeUnreviewable {
+		if cid, err := a.addChallengeEvidence(evidence); err != nil {
+			a.logger.Warn("Failed to publish challenge evidence", "taskIndex", taskIndex, "error", err)
+		} else {
+			reviewed.EvidenceCid = &cid
+		}
+	}
+
+	n := len(archived.Challenges)
+	archived.Challenges = append(archived.Challenges[:n:n], reviewed)
+	if err := a.taskStore.ArchiveTask(*archived); err != nil {
+		a.logger.Error("Failed to record challenge on archived task", "taskIndex", taskIndex, "error", err)
+	}
+	a.reportChallenge(taskIndex, archived.PoolId, reviewed)
+}
+
+// reaggregateChallenge re-aggregates the subject and returns the challenge
+// with its outcome, and the evidence to publish for it
+func (a *Aggregator) reaggregateChallenge(subject challengeSubject, challenge Challenge) (Challenge, ChallengeEvidence) {
+	reviewedAt := time.Now().UTC()
+	challenge.ReviewedAt = &reviewedAt
+
+	var quorums []QuorumReview
+	if subject.aggregate == nil || subject.digest == nil {
+		challenge.Outcome = ChallengeOutcomeUnreviewable
+	} else {
+		challenge.Findings, quorums = a.reaggregate(subject)
+		challenge.Outcome = ChallengeOutcomeRefuted
+		if len(challenge.Findings) > 0 {
+			challenge.Outcome = ChallengeOutcomeUpheld
+		}
+	}
+
+	evidence := ChallengeEvidence{
+		Version:         challengeEvidenceVersion,
+		TaskIndex:       subject.taskIndex,
+		Challenge:       challenge,
+		Bundle:          subject.bundle,
+		ResultBundleCid: subject.resultBundleCid,
+		OperatorSet:     subject.operatorSet,
+		Quorums:         quorums,
+	}
+	return challenge, evidence
+}
+
+// reaggregate rebuilds the aggregate from the subject's responses and returns
+// every way the recorded aggregate differs from it, with each quorum's
+// recomputed tally. Signatures are checked when the responses carry them.
+func (a *Aggregator) reaggregate(subject challengeSubject) ([]string, []QuorumReview) {
+	var findings []string
+
+	aggregate := subject.aggregate
+	if aggregate.ReferenceTaskIndex != subject.taskIndex {
+		findings = append(findings, fmt.Sprintf("aggregate references task %d", aggregate.ReferenceTaskIndex))
+	}
+	aggregateDigest, err := digest.AuctionTaskResponseDigest(aggregate.ReferenceTaskIndex, aggregate.Winner, aggregate.WinningBid, aggregate.TotalBids)
+	if err != nil {
+		findings = append(findings, fmt.Sprintf("aggregate can't be hashed: %v", err))
+	} else if common.Hash(aggregateDigest) != *subject.digest {
+		findings = append(findings, fmt.Sprintf("aggregate hashes to %s, not the recorded %s", common.Hash(aggregateDigest).Hex(), subject.digest.Hex()))
+	}
+
+	// Only responses that still hash to their digest, and verify when
+	// signed, count towards the re-aggregation
+	buckets := make(map[common.Hash][]TaskResponseInfo)
+	for _, response := range subject.responses {
+		operatorId := formatOperatorId(response.OperatorId)
+		responseDigest, err := digest.AuctionTaskResponseDigest(
+			response.TaskResponse.ReferenceTaskIndex,
+			response.TaskResponse.Winner,
+			response.TaskResponse.WinningBid,
+			response.TaskResponse.TotalBids,
+		)
+		if err != nil || common.Hash(responseDigest) != response.Digest {
+			findings = append(findings, fmt.Sprintf("response from %s doesn't hash to its recorded digest", operatorId))
+			continue
+		}
+		if subject.signed {
+			signature := response.BlsSignature
+			if err := a.apkTracker.VerifySignature(response.OperatorId, &signature, responseDigest); err != nil {
+				findings = append(findings, fmt.Sprintf("response from %s doesn't verify: %v", operatorId, err))
+				continue
+			}
+		}
+		buckets[response.Digest] = append(buckets[response.Digest], response)
+	}
+
+	bucket := buckets[*subject.digest]
+	responded := make(map[types.OperatorId]struct{}, len(bucket))
+	for _, response := range bucket {
+		responded[response.OperatorId] = struct{}{}
+	}
+	for _, signer := range subject.signers {
+		if _, ok := responded[signer]; !ok {
+			findings = append(findings, fmt.Sprintf("signer %s has no valid response over the aggregate's digest", formatOperatorId(signer)))
+		}
+	}
+
+	if subject.operatorSet == nil {
+		findings = append(findings, "no operator set snapshot to re-aggregate against")
+		return findings, nil
+	}
+	quorums := tallyQuorums(subject.operatorSet, bucket)
+	for _, quorum := range quorums {
+		if !quorum.Met {
+			findings = append(findings, fmt.Sprintf("quorum %d: %s of %s stake signed, below %d%%", quorum.QuorumNumber, quorum.SignedStake, quorum.TotalStake, quorum.ThresholdPercent))
+		}
+	}
+	// Another digest meeting every threshold means the task had two results
+	for responseDigest, other := range buckets {
+		if responseDigest == *subject.digest || !allQuorumsMet(tallyQuorums(subject.operatorSet, other)) {
+			continue
+		}
+		findings = append(findings, fmt.Sprintf("responses over %s also meet every quorum threshold", responseDigest.Hex()))
+	}
+	return findings, quorums
+}
+
+// tallyQuorums sums the bucket's stake in every quorum of the snapshot
+func tallyQuorums(snapshot *OperatorSetSnapshot, bucket []TaskResponseInfo) []QuorumReview {
+	stakes := snapshot.quorumStakes()
+
+	reviews := make([]QuorumReview, 0, len(snapshot.Quorums))
+	for _, quorum := range snapshot.Quorums {
+		stake := stakes[quorum.QuorumNumber]
+		review := QuorumReview{
+			QuorumNumber:     quorum.QuorumNumber,
+			ThresholdPercent: quorum.ThresholdPercent,
+			SignedStake:      big.NewInt(0),
+			TotalStake:       stake.total,
+		}
+		for _, response := range bucket {
+			if operatorStake, ok := stake.operators[response.OperatorId]; ok {
+				review.SignedStake.Add(review.SignedStake, operatorStake)
+			}
+		}
+		review.Met = review.TotalStake != nil && review.TotalStake.Sign() > 0 &&
+			meetsThreshold(review.SignedStake, review.TotalStake, review.ThresholdPercent)
+		reviews = append(reviews, review)
+	}
+	return reviews
+}
+
+func allQuorumsMet(quorums []QuorumReview) bool {
+	for _, quorum := range quorums {
+		if !quorum.Met {
+			return false
+		}
+	}
+	return len(quorums) > 0
+}
+
+// reportChallenge logs, counts and notifies a reviewed challenge
+func (a *Aggregator) reportChallenge(taskIndex uint32, poolId common.Hash, challenge Challenge) {
+	a.challenges.reviews.WithLabelValues(challenge.Source, challenge.Outcome).Inc()
+
+	logFields := []any{
+		"taskIndex", taskIndex,
+		"challenger", challenge.Challenger.Hex(),
+		"source", challenge.Source,
+		"outcome", challenge.Outcome,
+		"findings", challenge.Findings,
+	}
+	if challenge.Outcome == ChallengeOutcomeUpheld {
+		a.logger.Error("Challenge upheld, the aggregate doesn't reproduce", logFields...)
+	} else {
+		a.logger.Info("Challenge reviewed", logFields...)
+	}
+
+	text := fmt.Sprintf("Pool: %s\nChallenger: %s (%s)\nOutcome: %s", poolId.Hex(), challenge.Challenger.Hex(), challenge.Source, challenge.Outcome)
+	if challenge.TxHash != nil {
+		text += "\nTransaction: " + challenge.TxHash.Hex()
+	}
+	if len(challenge.Findings) > 0 {
+		text += "\nFindings:\n- " + strings.Join(challenge.Findings, "\n- ")
+	}
+	a.publishTaskEvent(TaskEvent{
+		Type:      TaskEventChallenged,
+		TaskIndex: taskIndex,
+		PoolId:    poolId,
+		Challenge: &challenge,
+	})
+	a.notify(notify.Message{
+		Event: notify.EventTaskChallenged,
+		Title: fmt.Sprintf("Auction %d challenged, %s", taskIndex, challenge.Outcome),
+		Text:  text,
+	})
+}
+
+// publishChallengeEvidence pins the evidence to IPFS and records its CID on
+// the task's challenge
+func (a *Aggregator) publishChallengeEvidence(task *TaskInfo, index int, evidence ChallengeEvidence) {
+	cid, err := a.addChallengeEvidence(evidence)
+
+	a.tasksMutex.Lock()
+	defer a.tasksMutex.Unlock()
+	task.challengeReviews--
+
+	if err != nil {
+		a.logger.Warn("Failed to publish challenge evidence", "taskIndex", evidence.TaskIndex, "error", err)
+		return
+	}
+	challenge := task.Challenges[index]
+	challenge.EvidenceCid = &cid
+	setChallenge(task, index, challenge)
+	a.logger.Info("Published challenge evidence", "taskIndex", evidence.TaskIndex, "cid", cid)
+}
+
+func (a *Aggregator) addChallengeEvidence(evidence ChallengeEvidence) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), bundlePublishTimeout)
+	defer cancel()
+
+	name := fmt.Sprintf("task-%d-challenge-%s.json", evidence.TaskIndex, strings.ToLower(evidence.Challenge.Challenger.Hex()))
+	return a.ipfs.AddJSON(ctx, name, evidence)
+}
+
+// addChallenge appends a pending challenge to the task and returns its index.
+// The review in progress keeps the task in memory. Callers must hold the
+// tasks lock.
+func addChallenge(task *TaskInfo, challenge Challenge) int {
+	index := len(task.Challenges)
+	// Status readers may hold the current slice, so it is never appended in place
+	task.Challenges = append(task.Challenges[:index:index], challenge)
+	task.challengeReviews++
+	task.revision++
+	return index
+}
+
+// setChallenge replaces the task's challenge at index. Callers must hold the
+// tasks lock.
+func setChallenge(task *TaskInfo, index int, challenge Challenge) {
+	challenges := make([]Challenge, len(task.Challenges))
+	copy(challenges, task.Challenges)
+	challenges[index] = challenge
+	task.Challenges = challenges
+	task.revision++
+}
+
+func hasChallengeTx(challenges []Challenge, txHash common.Hash) bool {
+	for _, challenge := range challenges {
+		if challenge.TxHash != nil && *challenge.TxHash == txHash {
+			return true
+		}
+	}
+	return false
+}
+
+// challengesResponse lists a task's challenges
+type challengesResponse struct {
+	TaskIndex         uint32      `json:"taskIndex"`
+	ChallengeDeadline *time.Time  `json:"challengeDeadline,omitempty"`
+	Challenges        []Challenge `json:"challenges"`
+}
+
+// challengesHandler serves GET /task/{taskIndex}/challenges from memory, or
+// from the task store once the task has left it
+func (a *Aggregator) challengesHandler(w http.ResponseWriter, r *http.Request) {
+	taskIndex, err := strconv.ParseUint(mux.Vars(r)["taskIndex"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid task index", http.StatusBadRequest)
+		return
+	}
+
+	status, err := a.GetTaskDetails(uint32(taskIndex))
+	if err != nil {
+		if errors.Is(err, ErrUnknownTask) {
+			http.Error(w, "Unknown task", http.StatusNotFound)
+			return
+		}
+		a.logger.Error("Failed to look up task challenges", "taskIndex", taskIndex, "error", err)
+		http.Error(w, "Failed to look up task challenges", http.StatusInternalServerError)
+		return
+	}
+
+	response := challengesResponse{
+		TaskIndex:         status.TaskIndex,
+		ChallengeDeadline: status.ChallengeDeadline,
+		Challenges:        status.Challenges,
+	}
+	if response.Challenges == nil {
+		response.Challenges = []Challenge{}
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// challengeHandler serves POST /task/{taskIndex}/challenge, which
+// re-aggregates the task and responds with the reviewed challenge. The
+// challenge must be signed by the challenger, see challengereport.
+func (a *Aggregator) challengeHandler(w http.ResponseWriter, r *http.Request) {
+	taskIndex, err := strconv.ParseUint(mux.Vars(r)["taskIndex"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid task index", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, a.maxRequestBodyBytes())
+	var request ChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if request.Challenger == (common.Address{}) {
+		http.Error(w, "Challenger address is required", http.StatusBadRequest)
+		return
+	}
+	if len(request.Reason) > maxChallengeReasonLength {
+		http.Error(w, fmt.Sprintf("Reason is longer than %d bytes", maxChallengeReasonLength), http.StatusBadRequest)
+		return
+	}
+
+	challenge, err := a.ChallengeTask(r.Context(), uint32(taskIndex), request)
+	switch {
+	case errors.Is(err, ErrUnsignedChallenge):
+		http.Error(w, "Challenge is not signed by the challenger", http.StatusUnauthorized)
+		return
+	case errors.Is(err, ErrUnknownTask):
+		http.Error(w, "Unknown task", http.StatusNotFound)
+		return
+	case errors.Is(err, ErrNotChallengeable):
+		http.Error(w, "Task has not been aggregated", http.StatusConflict)
+		return
+	case errors.Is(err, ErrChallengeWindowClosed), errors.Is(err, ErrAlreadyChallenged), errors.Is(err, ErrTooManyChallenges):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		a.logger.Error("Failed to review challenge", "taskIndex", taskIndex, "error", err)
+		http.Error(w, "Failed to review challenge", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(challenge)
+}
@@ -0,0 +1,214 @@
+package aggregator
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// defaultCheckpointInterval is how often tasks are checkpointed when
+// CheckpointInterval is unset
+const defaultCheckpointInterval = 15 * time.Second
+
+// AggregationCheckpoint is the aggregation state of a task in memory. It holds
+// everything needed to resume collecting responses after a restart, and once
+// the task has completed, its result and how far its submission got.
+type AggregationCheckpoint struct {
+	TaskIndex                 uint32                          `json:"taskIndex"`
+	TaskHash                  common.Hash                     `json:"taskHash"`
+	PoolId                    common.Hash                     `json:"poolId"`
+	TaskCreatedBlock          uint32                          `json:"taskCreatedBlock"`
+	QuorumNumbers             types.QuorumNums                `json:"quorumNumbers"`
+	QuorumThresholdPercentage types.QuorumThresholdPercentage `json:"quorumThresholdPercentage"`
+	CreatedAt                 time.Time                       `json:"createdAt"`
+	CheckpointedAt            time.Time                       `json:"checkpointedAt"`
+	// Responses are ordered by operator id; signer bitmaps index into them
+	Responses  []TaskResponseInfo    `json:"responses"`
+	Aggregates []CheckpointAggregate `json:"aggregates"`
+	// Completion state, unset while the task is open
+	IsCompleted           bool               `json:"isCompleted,omitempty"`
+	AggregatedResponse    *TaskResponse      `json:"aggregatedResponse,omitempty"`
+	AggregatedDigest      *common.Hash       `json:"aggregatedDigest,omitempty"`
+	Signers               []types.OperatorId `json:"signers,omitempty"`
+	SubmissionTxHash      *common.Hash       `json:"submissionTxHash,omitempty"`
+	SubmissionBlockNumber *uint64            `json:"submissionBlockNumber,omitempty"`
+	ChallengeDeadline     *time.Time         `json:"challengeDeadline,omitempty"`
+	Challenges            []Challenge        `json:"challenges,omitempty"`
+	ExpiredAt             *time.Time         `json:"expiredAt,omitempty"`
+	ExpiryTxHash          *common.Hash       `json:"expiryTxHash,omitempty"`
+}
+
+// CheckpointAggregate is the running aggregate of the responses over one digest
+type CheckpointAggregate struct {
+	Digest             common.Hash   `json:"digest"`
+	AggregateSignature bls.Signature `json:"aggregateSignature"`
+	// Bit i is set when Responses[i] signed Digest
+	SignerBitmap []byte `json:"signerBitmap"`
+}
+
+// newAggregationCheckpoint captures the task's current aggregation state.
+// Callers must hold the tasks lock.
+func newAggregationCheckpoint(task *TaskInfo) AggregationCheckpoint {
+	checkpoint := AggregationCheckpoint{
+		TaskIndex:                 task.TaskIndex,
+		TaskHash:                  task.TaskHash,
+		PoolId:                    task.PoolId,
+		TaskCreatedBlock:          task.TaskCreatedBlock,
+		QuorumNumbers:             task.QuorumNumbers,
+		QuorumThresholdPercentage: task.QuorumThresholdPercentage,
+		CreatedAt:                 task.CreatedAt,
+		CheckpointedAt:            time.Now().UTC(),
+		Responses:                 make([]TaskResponseInfo, 0, len(task.TaskResponsesInfo)),
+		IsCompleted:               task.IsCompleted,
+		AggregatedResponse:        task.AggregatedResponse,
+		AggregatedDigest:          task.AggregatedDigest,
+		Signers:                   task.Signers,
+		SubmissionTxHash:          task.SubmissionTxHash,
+		SubmissionBlockNumber:     task.SubmissionBlockNumber,
+		ChallengeDeadline:         task.ChallengeDeadline,
+		Challenges:                task.Challenges,
+		ExpiredAt:                 task.ExpiredAt,
+		ExpiryTxHash:              task.ExpiryTxHash,
+	}
+
+	for _, info := range task.TaskResponsesInfo {
+		checkpoint.Responses = append(checkpoint.Responses, info)
+	}
+	sort.Slice(checkpoint.Responses, func(i, j int) bool {
+		return formatOperatorId(checkpoint.Responses[i].OperatorId) < formatOperatorId(checkpoint.Responses[j].OperatorId)
+	})
+
+	aggregates := make(map[common.Hash]*CheckpointAggregate)
+	var digests []common.Hash
+	for i, info := range checkpoint.Responses {
+		aggregate, ok := aggregates[info.Digest]
+		if !ok {
+			aggregate = &CheckpointAggregate{
+				Digest:             info.Digest,
+				AggregateSignature: *bls.NewZeroSignature(),
+				SignerBitmap:       make([]byte, (len(checkpoint.Responses)+7)/8),
+			}
+			aggregates[info.Digest] = aggregate
+			digests = append(digests, info.Digest)
+		}
+
+		signature := info.BlsSignature
+		aggregate.AggregateSignature.Add(&signature)
+		aggregate.SignerBitmap[i/8] |= 1 << (i % 8)
+	}
+	for _, digest := range digests {
+		checkpoint.Aggregates = append(checkpoint.Aggregates, *aggregates[digest])
+	}
+
+	return checkpoint
+}
+
+// restoreTask rebuilds a task from its checkpoint
+func (c AggregationCheckpoint) restoreTask() *TaskInfo {
+	task := &TaskInfo{
+		TaskIndex:                 c.TaskIndex,
+		TaskHash:                  c.TaskHash,
+		PoolId:                    c.PoolId,
+		TaskCreatedBlock:          c.TaskCreatedBlock,
+		QuorumNumbers:             c.QuorumNumbers,
+		QuorumThresholdPercentage: c.QuorumThresholdPercentage,
+		TaskResponses:             make(map[types.OperatorId]TaskResponse, len(c.Responses)),
+		TaskResponsesInfo:         make(map[types.OperatorId]TaskResponseInfo, len(c.Responses)),
+		CreatedAt:                 c.CreatedAt,
+		IsCompleted:               c.IsCompleted,
+		AggregatedResponse:        c.AggregatedResponse,
+		AggregatedDigest:          c.AggregatedDigest,
+		Signers:                   c.Signers,
+		SubmissionTxHash:          c.SubmissionTxHash,
+		SubmissionBlockNumber:     c.SubmissionBlockNumber,
+		ChallengeDeadline:         c.ChallengeDeadline,
+		Challenges:                c.Challenges,
+		ExpiredAt:                 c.ExpiredAt,
+		ExpiryTxHash:              c.ExpiryTxHash,
+	}
+
+	for _, info := range c.Responses {
+		task.TaskResponses[info.OperatorId] = info.TaskResponse
+		task.TaskResponsesInfo[info.OperatorId] = info
+	}
+
+	// The restored state is exactly what is on disk
+	task.checkpointed = true
+	task.restored = true
+	return task
+}
+
+// restoreCheckpoints loads the tasks checkpointed before the last shutdown
+func (a *Aggregator) restoreCheckpoints() error {
+	checkpoints, err := a.taskStore.LoadCheckpoints()
+	if err != nil {
+		return err
+	}
+
+	a.tasksMutex.Lock()
+	defer a.tasksMutex.Unlock()
+
+	for _, checkpoint := range checkpoints {
+		a.tasks[checkpoint.TaskIndex] = checkpoint.restoreTask()
+		a.logger.Info("Restored task from checkpoint",
+			"taskIndex", checkpoint.TaskIndex,
+			"responses", len(checkpoint.Responses),
+			"completed", checkpoint.IsCompleted,
+			"checkpointedAt", checkpoint.CheckpointedAt,
+		)
+	}
+
+	return nil
+}
+
+// checkpointTasks persists every task whose responses or result changed since
+// its last checkpoint. A task's checkpoint is dropped once it is archived.
+func (a *Aggregator) checkpointTasks() {
+	type pending struct {
+		task       *TaskInfo
+		revision   uint64
+		checkpoint AggregationCheckpoint
+	}
+
+	a.tasksMutex.RLock()
+	var updates []pending
+	for _, task := range a.tasks {
+		if task.revision != task.checkpointedRevision {
+			updates = append(updates, pending{task: task, revision: task.revision, checkpoint: newAggregationCheckpoint(task)})
+		}
+	}
+	a.tasksMutex.RUnlock()
+
+	for _, update := range updates {
+		if err := a.taskStore.SaveCheckpoint(update.checkpoint); err != nil {
+			a.logger.Error("Failed to checkpoint task", "taskIndex", update.task.TaskIndex, "error", err)
+			continue
+		}
+
+		a.tasksMutex.Lock()
+		update.task.checkpointedRevision = update.revision
+		update.task.checkpointed = true
+		a.tasksMutex.Unlock()
+	}
+}
+
+// checkpointLoop periodically checkpoints tasks until ctx is done
+func (a *Aggregator) checkpointLoop(ctx context.Context) {
+	ticker := time.NewTicker(a.checkpointInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Capture anything received since the last tick before shutting down
+			a.checkpointTasks()
+			return
+		case <-ticker.C:
+			a.checkpointTasks()
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/avsregistry"
+	"github.com/eigenlvr/avs/pkg/committee"
+)
+
+// ErrNotInCommittee is returned when committees are selected by VRF and a
+// response doesn't carry a valid winning ticket
+var ErrNotInCommittee = errors.New("operator is not in the task committee")
+
+// validateCommitteeConfig checks that VRF tickets can be verified. Tickets
+// are drawn over the task hash, which only the service manager knows.
+func validateCommitteeConfig(config Config) error {
+	if config.CommitteeVrfExpectedSize > 0 && config.ServiceManagerAddress == "" {
+		return errors.New("committee_vrf_expected_size needs a service_manager_address to read task hashes from")
+	}
+	return nil
+}
+
+// checkCommitteeProof verifies the response's VRF ticket against the
+// operator's registered key and that it draws the operator into the task's
+// committee, weighed by the stake of the last operator set refresh. It
+// accepts every response when committees aren't selected by VRF.
+func (a *Aggregator) checkCommitteeProof(signedResponse SignedTaskResponse, taskHash common.Hash, quorums types.QuorumNums) error {
+	if a.config.CommitteeVrfExpectedSize == 0 {
+		return nil
+	}
+	if signedResponse.CommitteeProof == nil {
+		return fmt.Errorf("%w: no committee proof", ErrNotInCommittee)
+	}
+
+	pubkeys, err := a.apkTracker.Pubkeys(signedResponse.OperatorId)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotInCommittee, err)
+	}
+	if err := committee.VerifyProof(pubkeys.G2Pubkey, taskHash, signedResponse.CommitteeProof); err != nil {
+		return fmt.Errorf("%w: %v", ErrNotInCommittee, err)
+	}
+
+	stake := big.NewInt(0)
+	totalStake := big.NewInt(0)
+	for _, quorumStake := range a.quorumStakes(quorums) {
+		totalStake.Add(totalStake, quorumStake.total)
+		if operatorStake, ok := quorumStake.operators[signedResponse.OperatorId]; ok {
+			stake.Add(stake, operatorStake)
+		}
+	}
+
+	output := committee.Output(signedResponse.CommitteeProof)
+	if !committee.Selected(output, stake, totalStake, a.config.CommitteeVrfExpectedSize) {
+		return fmt.Errorf("%w: ticket %s doesn't draw stake %s of %s", ErrNotInCommittee, output.Hex(), stake, totalStake)
+	}
+	return nil
+}
+
+// checkCommitteeCoverage checks that a committee of CommitteeVrfExpectedSize
+// is expected to hold every quorum's threshold. Responses are measured
+// against the quorum's full stake, as the contract measures the aggregate,
+// so a committee expected to fall short would leave most tasks unaggregated.
+// Tasks without a threshold of their own are assumed to need the default.
+func (a *Aggregator) checkCommitteeCoverage(operators []avsregistry.RegisteredOperator) error {
+	if a.config.CommitteeVrfExpectedSize == 0 {
+		return nil
+	}
+
+	var quorums types.QuorumNums
+	for _, operator := range operators {
+		for quorum := range operator.StakePerQuorum {
+			if !containsQuorum(quorums, quorum) {
+				quorums = append(quorums, quorum)
+			}
+		}
+	}
+	sort.Slice(quorums, func(i, j int) bool { return quorums[i] < quorums[j] })
+
+	stakes := collectQuorumStakes(quorums, operators)
+	for _, quorum := range quorums {
+		threshold, ok := a.params.QuorumThreshold(uint8(quorum))
+		if !ok {
+			threshold = defaultQuorumThresholdPercentage
+		}
+		expected := committee.ExpectedStake(operators, quorums, quorum, a.config.CommitteeVrfExpectedSize)
+		if !meetsThreshold(expected, stakes[quorum].total, threshold) {
+			return fmt.Errorf("committee_vrf_expected_size %d is expected to hold %s of quorum %d's stake of %s, short of its %d%% threshold",
+				a.config.CommitteeVrfExpectedSize, expected, quorum, stakes[quorum].total, threshold)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package aggregator
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultCorsMaxAge is how long browsers may cache a preflight when CorsMaxAge is unset
+	defaultCorsMaxAge = 10 * time.Minute
+
+	corsAllowedMethods = "GET, POST, DELETE, OPTIONS"
+	corsAllowedHeaders = "Authorization, Content-Type, Content-Encoding"
+	corsExposedHeaders = "X-Api-Version, Deprecation, Sunset, Link"
+)
+
+// httpPolicy is the CORS and security header policy of the HTTP API
+type httpPolicy struct {
+	// allowAnyOrigin is set when "*" is among the allowed origins
+	allowAnyOrigin bool
+	origins        map[string]struct{}
+	maxAge         time.Duration
+	hstsMaxAge     time.Duration
+}
+
+func newHttpPolicy(config Config) (*httpPolicy, error) {
+	policy := &httpPolicy{
+		origins: make(map[string]struct{}),
+		maxAge:  defaultCorsMaxAge,
+	}
+
+	for _, origin := range config.CorsAllowedOrigins {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		switch {
+		case origin == "*":
+			policy.allowAnyOrigin = true
+		case strings.HasPrefix(origin, "http://") || strings.HasPrefix(origin, "https://"):
+			policy.origins[strings.ToLower(origin)] = struct{}{}
+		default:
+			return nil, fmt.Errorf("invalid cors origin %q", origin)
+		}
+	}
+
+	if config.CorsMaxAge != "" {
+		maxAge, err := time.ParseDuration(config.CorsMaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cors max age: %w", err)
+		}
+		policy.maxAge = maxAge
+	}
+
+	if config.HstsMaxAge != "" {
+		hstsMaxAge, err := time.ParseDuration(config.HstsMaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hsts max age: %w", err)
+		}
+		policy.hstsMaxAge = hstsMaxAge
+	}
+
+	return policy, nil
+}
+
+func (p *httpPolicy) originAllowed(origin string) bool {
+	if p.allowAnyOrigin {
+		return true
+	}
+	_, ok := p.origins[strings.ToLower(origin)]
+	return ok
+}
+
+// Handler wraps the whole router rather than being router middleware, since
+// preflight requests must be answered before mux rejects the OPTIONS method
+func (p *httpPolicy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+
+		// The API only serves JSON, so nothing it returns should be rendered,
+		// framed or sniffed by a browser
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "no-referrer")
+		header.Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+		if p.hstsMaxAge > 0 {
+			header.Set("Strict-Transport-Security", "max-age="+strconv.Itoa(int(p.hstsMaxAge.Seconds())))
+		}
+
+		origin := r.Header.Get("Origin")
+		allowed := origin != "" && p.originAllowed(origin)
+		if allowed {
+			if p.allowAnyOrigin {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Set("Access-Control-Allow-Origin", origin)
+			}
+			header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+		}
+		header.Add("Vary", "Origin")
+
+		if r.Method != http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Answer OPTIONS here, including preflights from origins that aren't
+		// allowed, which the browser then blocks for lack of CORS headers
+		header.Set("Allow", corsAllowedMethods)
+		if allowed && r.Header.Get("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(p.maxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
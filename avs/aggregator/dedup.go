@@ -0,0 +1,86 @@
+package aggregator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Kinds of repeated responses
+const (
+	// repeatedResponseDuplicate is the same response sent again, acked
+	// without being recorded twice
+	repeatedResponseDuplicate = "duplicate"
+	// repeatedResponseConflicting is a response over another digest from an
+	// operator that already answered the task
+	repeatedResponseConflicting = "conflicting"
+	// repeatedResponseLate is a first response to a task already aggregated
+	repeatedResponseLate = "late"
+)
+
+var (
+	// ErrConflictingResponse is returned when an operator that already answered
+	// a task sends a different response to it. Its first response stands.
+	ErrConflictingResponse = errors.New("operator already sent a different response to the task")
+
+	// ErrTaskCompleted is returned for a new response to a task whose
+	// responses have already been aggregated
+	ErrTaskCompleted = errors.New("task already completed")
+)
+
+func newRepeatedResponses(reg prometheus.Registerer) *prometheus.CounterVec {
+	repeated := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "eigenlvr",
+		Subsystem: "aggregator",
+		Name:      "repeated_task_responses_total",
+		Help:      "Responses from operators that already answered the task, or to tasks already completed, by kind",
+	}, []string{"kind"})
+	reg.MustRegister(repeated)
+	return repeated
+}
+
+// checkRepeatedResponse reports whether the operator already sent exactly this
+// response to the task, in which case it is acknowledged again but not
+// recorded. A different response from the same operator, or a first response
+// to a completed task, is refused. Callers must hold the tasks lock.
+func (a *Aggregator) checkRepeatedResponse(task *TaskInfo, operatorId types.OperatorId, responseDigest common.Hash) (bool, error) {
+	if previous, responded := task.TaskResponsesInfo[operatorId]; responded {
+		if previous.Digest == responseDigest {
+			a.repeatedResponses.WithLabelValues(repeatedResponseDuplicate).Inc()
+			a.logger.Debug("Duplicate task response", "taskIndex", task.TaskIndex, "operatorId", formatOperatorId(operatorId))
+			return true, nil
+		}
+
+		a.repeatedResponses.WithLabelValues(repeatedResponseConflicting).Inc()
+		a.logger.Warn("Operator sent a conflicting task response",
+			"taskIndex", task.TaskIndex,
+			"operatorId", formatOperatorId(operatorId),
+			"digest", responseDigest.Hex(),
+			"previousDigest", previous.Digest.Hex(),
+		)
+		return false, fmt.Errorf("%w: first response was over %s", ErrConflictingResponse, previous.Digest.Hex())
+	}
+
+	if task.IsCompleted {
+		a.repeatedResponses.WithLabelValues(repeatedResponseLate).Inc()
+		return false, fmt.Errorf("%w: task %d", ErrTaskCompleted, task.TaskIndex)
+	}
+	return false, nil
+}
+
+// precheckRepeatedResponse runs checkRepeatedResponse against the task as it
+// is now, so repeats are answered before any registry reads. The check is
+// repeated when the response is recorded.
+func (a *Aggregator) precheckRepeatedResponse(taskIndex uint32, operatorId types.OperatorId, responseDigest common.Hash) (bool, error) {
+	a.tasksMutex.RLock()
+	defer a.tasksMutex.RUnlock()
+
+	task, exists := a.tasks[taskIndex]
+	if !exists {
+		return false, nil
+	}
+	return a.checkRepeatedResponse(task, operatorId, responseDigest)
+}
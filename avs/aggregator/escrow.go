@@ -0,0 +1,43 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/escrow"
+)
+
+// newEscrowReader returns nil when no auction escrow is configured
+func newEscrowReader(config Config, ethClient eth.Client) (*escrow.Reader, error) {
+	if config.AuctionEscrowAddress == "" {
+		return nil, nil
+	}
+	if !common.IsHexAddress(config.AuctionEscrowAddress) {
+		return nil, fmt.Errorf("invalid auction escrow address %q", config.AuctionEscrowAddress)
+	}
+	return escrow.NewReader(common.HexToAddress(config.AuctionEscrowAddress), ethClient)
+}
+
+// checkSettlementEscrow re-checks the winner's deposit at the latest block.
+// Operators verified it at the reference block, but the searcher may have
+// withdrawn since, and settling an unpaid bid would leave the pool short.
+func (a *Aggregator) checkSettlementEscrow(ctx context.Context, task *TaskInfo) error {
+	if a.escrow == nil {
+		return nil
+	}
+
+	a.tasksMutex.RLock()
+	response := task.AggregatedResponse
+	a.tasksMutex.RUnlock()
+	if response == nil || response.Winner == (common.Address{}) || response.WinningBid == nil || response.WinningBid.Sign() == 0 {
+		return nil
+	}
+
+	if err := a.escrow.Verify(ctx, response.Winner, response.WinningBid, nil); err != nil {
+		return fmt.Errorf("not settling task %d: %w", task.TaskIndex, err)
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/wsproto"
+)
+
+// eventStreamPath is the task event WebSocket route under the API version
+// prefix, apart from the operator WebSocket at wsproto.Path
+const eventStreamPath = "/ws"
+
+const (
+	// eventSubscriberBuffer is how many events may be queued for one
+	// subscriber before it is disconnected as too slow
+	eventSubscriberBuffer = 256
+	// maxEventSubscribers bounds the connected subscribers
+	maxEventSubscribers = 1024
+	// maxSubscriptionBytes caps the size of a subscription message
+	maxSubscriptionBytes = 64 << 10
+)
+
+// Task event types
+const (
+	TaskEventCreated          = "taskCreated"
+	TaskEventResponseReceived = "responseReceived"
+	TaskEventQuorumReached    = "quorumReached"
+	TaskEventSubmitted        = "submitted"
+	TaskEventChallenged       = "challenged"
+	TaskEventExpired          = "expired"
+)
+
+// TaskEvent is a task lifecycle event pushed to event stream subscribers.
+// Fields beyond the task and pool are set for the event types they belong to.
+type TaskEvent struct {
+	Type      string      `json:"type"`
+	TaskIndex uint32      `json:"taskIndex"`
+	PoolId    common.Hash `json:"poolId"`
+	Time      time.Time   `json:"time"`
+
+	// taskCreated
+	TaskCreatedBlock uint32 `json:"taskCreatedBlock,omitempty"`
+	// responseReceived; Responses is the task's response count after it
+	OperatorId string `json:"operatorId,omitempty"`
+	Responses  int    `json:"responses,omitempty"`
+	// responseReceived and quorumReached
+	Digest *common.Hash `json:"digest,omitempty"`
+	// quorumReached
+	TaskResponse *TaskResponse `json:"taskResponse,omitempty"`
+	Signers      int           `json:"signers,omitempty"`
+	// submitted
+	TxHash      *common.Hash `json:"txHash,omitempty"`
+	BlockNumber *uint64      `json:"blockNumber,omitempty"`
+	// challenged, once the challenge was reviewed
+	Challenge *Challenge `json:"challenge,omitempty"`
+}
+
+// EventSubscription is sent by a subscriber to replace its pool filter. No
+// pools subscribes to every pool.
+type EventSubscription struct {
+	Pools []common.Hash `json:"pools"`
+}
+
+// eventSubscriber is one task event WebSocket
+type eventSubscriber struct {
+	conn      *websocket.Conn
+	send      chan TaskEvent
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// pools filters the events sent, nil for every pool
+	mu    sync.RWMutex
+	pools map[common.Hash]struct{}
+}
+
+func (s *eventSubscriber) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.conn.Close()
+	})
+}
+
+func (s *eventSubscriber) subscribe(pools []common.Hash) {
+	var filter map[common.Hash]struct{}
+	if len(pools) > 0 {
+		filter = make(map[common.Hash]struct{}, len(pools))
+		for _, poolId := range pools {
+			filter[poolId] = struct{}{}
+		}
+	}
+	s.mu.Lock()
+	s.pools = filter
+	s.mu.Unlock()
+}
+
+func (s *eventSubscriber) wants(poolId common.Hash) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.pools == nil {
+		return true
+	}
+	_, ok := s.pools[poolId]
+	return ok
+}
+
+// eventHub fans task events out to the connected subscribers
+type eventHub struct {
+	mu          sync.RWMutex
+	subscribers map[*eventSubscriber]struct{}
+	// closed is set once every subscriber was closed for a handover
+	closed bool
+
+	subscribersGauge prometheus.Gauge
+}
+
+func newEventHub(reg prometheus.Registerer) *eventHub {
+	subscribersGauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "eigenlvr",
+		Subsystem: "aggregator",
+		Name:      "websocket_event_subscribers",
+		Help:      "Clients currently subscribed to task events over WebSocket",
+	})
+	reg.MustRegister(subscribersGauge)
+
+	return &eventHub{
+		subscribers:      make(map[*eventSubscriber]struct{}),
+		subscribersGauge: subscribersGauge,
+	}
+}
+
+// full reports whether no further subscriber would be taken
+func (h *eventHub) full() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.closed || len(h.subscribers) >= maxEventSubscribers
+}
+
+// add registers the subscriber, returning false when the hub is closed or full
+func (h *eventHub) add(subscriber *eventSubscriber) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed || len(h.subscribers) >= maxEventSubscribers {
+		return false
+	}
+	h.subscribers[subscriber] = struct{}{}
+	h.subscribersGauge.Set(float64(len(h.subscribers)))
+	return true
+}
+
+func (h *eventHub) remove(subscriber *eventSubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subscribers, subscriber)
+	h.subscribersGauge.Set(float64(len(h.subscribers)))
+}
+
+// closeAll closes every subscriber; they reconnect to whoever serves the
+// listener next
+func (h *eventHub) closeAll() {
+	h.mu.Lock()
+	h.closed = true
+	subscribers := make([]*eventSubscriber, 0, len(h.subscribers))
+	for subscriber := range h.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	h.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber.close()
+	}
+}
+
+// publish queues the event for every subscriber of its pool without
+// blocking. A subscriber whose queue is full is disconnected rather than
+// silently missing events, so it can reconnect and catch up from /tasks.
+func (h *eventHub) publish(event TaskEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for subscriber := range h.subscribers {
+		if !subscriber.wants(event.PoolId) {
+			continue
+		}
+		select {
+		case subscriber.send <- event:
+		case <-subscriber.done:
+		default:
+			subscriber.close()
+		}
+	}
+}
+
+// publishTaskEvent stamps the event and pushes it to the event stream
+func (a *Aggregator) publishTaskEvent(event TaskEvent) {
+	event.Time = time.Now().UTC()
+	a.eventHub.publish(event)
+}
+
+// publishSubmitted pushes the task's mined submission. Callers must hold the
+// tasks lock.
+func (a *Aggregator) publishSubmitted(task *TaskInfo) {
+	a.publishTaskEvent(TaskEvent{
+		Type:        TaskEventSubmitted,
+		TaskIndex:   task.TaskIndex,
+		PoolId:      task.PoolId,
+		TxHash:      task.SubmissionTxHash,
+		BlockNumber: task.SubmissionBlockNumber,
+	})
+}
+
+// eventStreamHandler upgrades a connection that then receives the task events
+// of the pools given by pool query parameters, or of every pool without any.
+// The filter is replaced by each EventSubscription the client sends.
+func (a *Aggregator) eventStreamHandler(w http.ResponseWriter, r *http.Request) {
+	pools := make([]common.Hash, 0, len(r.URL.Query()["pool"]))
+	for _, pool := range r.URL.Query()["pool"] {
+		var poolId common.Hash
+		if err := poolId.UnmarshalText([]byte(pool)); err != nil {
+			http.Error(w, "Invalid pool id", http.StatusBadRequest)
+			return
+		}
+		pools = append(pools, poolId)
+	}
+	if a.eventHub.full() {
+		http.Error(w, "Too many event subscribers", http.StatusServiceUnavailable)
+		return
+	}
+
+	eventUpgrader := websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 4096,
+		// Dashboards are browsers, held to the API's CORS origins; other
+		// clients send no origin
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || a.httpPolicy.originAllowed(origin)
+		},
+	}
+	conn, err := eventUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already replied
+		a.logger.Warn("Failed to upgrade event stream websocket", "error", err)
+		return
+	}
+	conn.SetReadLimit(maxSubscriptionBytes)
+
+	subscriber := &eventSubscriber{
+		conn: conn,
+		send: make(chan TaskEvent, eventSubscriberBuffer),
+		done: make(chan struct{}),
+	}
+	subscriber.subscribe(pools)
+	if !a.eventHub.add(subscriber) {
+		conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too many event subscribers"),
+			time.Now().Add(time.Second),
+		)
+		conn.Close()
+		return
+	}
+	defer a.eventHub.remove(subscriber)
+	defer subscriber.close()
+
+	logger := a.logger.With("remoteAddr", r.RemoteAddr)
+	logger.Debug("Event stream subscriber connected", "pools", len(pools))
+
+	go a.writeEventSubscriber(subscriber, logger)
+	a.readEventSubscriber(subscriber, logger)
+
+	logger.Debug("Event stream subscriber disconnected")
+}
+
+// readEventSubscriber applies subscription messages until the connection fails
+func (a *Aggregator) readEventSubscriber(subscriber *eventSubscriber, logger logging.Logger) {
+	conn := subscriber.conn
+	conn.SetReadDeadline(time.Now().Add(wsproto.PongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsproto.PongTimeout))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Debug("Event stream read failed", "error", err)
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(wsproto.PongTimeout))
+
+		var subscription EventSubscription
+		if err := json.Unmarshal(data, &subscription); err != nil {
+			conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseUnsupportedData, "invalid subscription"),
+				time.Now().Add(time.Second),
+			)
+			return
+		}
+		subscriber.subscribe(subscription.Pools)
+	}
+}
+
+// writeEventSubscriber writes queued events and keepalive pings until the
+// subscriber closes
+func (a *Aggregator) writeEventSubscriber(subscriber *eventSubscriber, logger logging.Logger) {
+	ticker := time.NewTicker(wsproto.PingInterval)
+	defer ticker.Stop()
+	defer subscriber.close()
+
+	for {
+		select {
+		case <-subscriber.done:
+			return
+		case event := <-subscriber.send:
+			subscriber.conn.SetWriteDeadline(time.Now().Add(wsproto.PingInterval))
+			if err := subscriber.conn.WriteJSON(event); err != nil {
+				logger.Debug("Event stream write failed", "error", err)
+				return
+			}
+		case <-ticker.C:
+			if err := subscriber.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsproto.PingInterval)); err != nil {
+				logger.Debug("Event stream ping failed", "error", err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+)
+
+// expirySubmissionTimeout bounds sending a task's expiry and waiting for it
+const expirySubmissionTimeout = 5 * time.Minute
+
+// ErrTaskExpired is returned for a response to a task whose response window
+// has closed, which the service manager would no longer accept an aggregate for
+var ErrTaskExpired = errors.New("task response window has closed")
+
+// responseDeadline returns the last block the service manager accepts a
+// task's aggregate in, zero while the task's creation block is unknown
+func responseDeadline(taskCreatedBlock uint32) uint64 {
+	if taskCreatedBlock == 0 {
+		return 0
+	}
+	return uint64(taskCreatedBlock) + taskResponseWindowBlocks
+}
+
+// expireTasks marks open tasks whose response window closed before the
+// current block as expired, so they stop taking responses and are reported
+// as missing their quorum. Callers must hold the tasks lock.
+func (a *Aggregator) expireTasks(currentBlock uint64) {
+	for _, task := range a.tasks {
+		deadline := responseDeadline(task.TaskCreatedBlock)
+		if task.IsCompleted || task.ExpiredAt != nil || deadline == 0 || currentBlock <= deadline {
+			continue
+		}
+
+		expiredAt := time.Now().UTC()
+		task.ExpiredAt = &expiredAt
+		task.revision++
+		a.logger.Warn("Task expired without reaching quorum",
+			"taskIndex", task.TaskIndex,
+			"poolId", task.PoolId.Hex(),
+			"responses", len(task.TaskResponsesInfo),
+			"deadlineBlock", deadline,
+			"currentBlock", currentBlock,
+		)
+		a.publishTaskEvent(TaskEvent{
+			Type:      TaskEventExpired,
+			TaskIndex: task.TaskIndex,
+			PoolId:    task.PoolId,
+		})
+		// The BLS aggregation service reports its own expired tasks
+		if a.blsAggregation == nil {
+			a.metrics.aggregated(task.PoolId, aggregationResultExpired, task.CreatedAt)
+			a.notifyMissedQuorum(task, fmt.Sprintf("response window closed at block %d", deadline))
+		}
+		if a.config.SubmitExpiredTasks {
+			go a.submitExpiry(task)
+		}
+	}
+}
+
+// submitExpiry records the task's expiry on chain, so the hook and anyone
+// watching the service manager see the auction settled without a winner
+func (a *Aggregator) submitExpiry(task *TaskInfo) {
+	a.tasksMutex.RLock()
+	auctionTask := task.auctionTask
+	a.tasksMutex.RUnlock()
+	if auctionTask == nil {
+		// Tasks opened by a response or restored from a checkpoint were never
+		// seen in full
+		a.logger.Warn("Task parameters unknown, not submitting its expiry", "taskIndex", task.TaskIndex)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), expirySubmissionTimeout)
+	defer cancel()
+
+	receipt, err := a.sendExpiry(ctx, task, *auctionTask)
+	if err != nil {
+		a.logger.Error("Failed to submit task expiry", "taskIndex", task.TaskIndex, "error", err)
+		return
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		a.logger.Error("Task expiry reverted", "taskIndex", task.TaskIndex, "txHash", receipt.TxHash.Hex())
+		return
+	}
+	a.logger.Info("Submitted task expiry", "taskIndex", task.TaskIndex, "txHash", receipt.TxHash.Hex())
+}
+
+// sendExpiry signs and sends expireAuctionTask through the submission
+// sender, recording each broadcast hash on the task
+func (a *Aggregator) sendExpiry(ctx context.Context, task *TaskInfo, auctionTask servicemanager.AuctionTask) (*gethtypes.Receipt, error) {
+	if a.config.WatchOnly {
+		return nil, ErrWatchOnly
+	}
+	if a.submissionSender == nil {
+		return nil, ErrNoSubmissionSender
+	}
+
+	serviceManager := common.HexToAddress(a.config.ServiceManagerAddress)
+	tx, err := servicemanager.ExpireAuctionTask(a.submissionSender.TransactOpts(ctx), a.ethClient, serviceManager, auctionTask, task.TaskIndex)
+	if err != nil {
+		return nil, err
+	}
+	return a.submissionSender.Send(ctx, tx, func(sent *gethtypes.Transaction) {
+		txHash := sent.Hash()
+		a.tasksMutex.Lock()
+		task.ExpiryTxHash = &txHash
+		task.revision++
+		a.tasksMutex.Unlock()
+	})
+}
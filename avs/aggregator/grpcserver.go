@@ -0,0 +1,318 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/eigenlvr/avs/pkg/aggregatorpb"
+	"github.com/eigenlvr/avs/pkg/escrow"
+	"github.com/eigenlvr/avs/pkg/poolregistry"
+	"github.com/eigenlvr/avs/pkg/wsproto"
+)
+
+// grpcTaskBuffer is how many pushed tasks may be queued for one StreamTasks
+// client before pushes to it are dropped
+const grpcTaskBuffer = 64
+
+// grpcServer serves the gRPC interface on GrpcServerIpPortAddr, next to the
+// HTTP API. Responses go through the same checks as over HTTP.
+type grpcServer struct {
+	aggregatorpb.UnimplementedAggregatorServer
+
+	aggregator *Aggregator
+	server     *grpc.Server
+
+	mu      sync.Mutex
+	streams map[chan *aggregatorpb.Task]struct{}
+}
+
+// newGrpcServer returns nil unless GrpcServerIpPortAddr is set
+func newGrpcServer(a *Aggregator) *grpcServer {
+	if a.config.GrpcServerIpPortAddr == "" {
+		return nil
+	}
+
+	s := &grpcServer{
+		aggregator: a,
+		server:     grpc.NewServer(grpc.MaxRecvMsgSize(int(a.maxRequestBodyBytes()))),
+		streams:    make(map[chan *aggregatorpb.Task]struct{}),
+	}
+	aggregatorpb.RegisterAggregatorServer(s.server, s)
+	return s
+}
+
+// serve accepts gRPC connections until ctx is done, then stops gracefully
+func (s *grpcServer) serve(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.aggregator.config.GrpcServerIpPortAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.aggregator.config.GrpcServerIpPortAddr, err)
+	}
+
+	stop := context.AfterFunc(ctx, s.server.GracefulStop)
+	defer stop()
+
+	s.aggregator.logger.Info("gRPC server listening", "address", listener.Addr().String())
+	return s.server.Serve(listener)
+}
+
+func (s *grpcServer) SubmitTaskResponse(ctx context.Context, req *aggregatorpb.SubmitTaskResponseRequest) (*aggregatorpb.SubmitTaskResponseReply, error) {
+	signedResponse, err := decodeSignedTaskResponse(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s.aggregator.logger.Info("Received task response over gRPC",
+		"taskIndex", signedResponse.TaskResponse.ReferenceTaskIndex,
+		"operatorId", formatOperatorId(signedResponse.OperatorId),
+		"winner", signedResponse.TaskResponse.Winner.Hex(),
+		"winningBid", signedResponse.TaskResponse.WinningBid.String(),
+	)
+
+	responseDigest, err := s.aggregator.processTaskResponse(ctx, signedResponse, false)
+	if err != nil {
+		return nil, grpcResponseError(err)
+	}
+
+	reply := &aggregatorpb.SubmitTaskResponseReply{ResponseDigest: responseDigest.Bytes()}
+	signedAck, err := s.aggregator.signAck(ctx, signedResponse, responseDigest)
+	if err != nil {
+		// The response is already accepted, so only the ack is lost
+		s.aggregator.logger.Error("Failed to sign task response ack", "error", err)
+	}
+	if signedAck != nil {
+		reply.Ack = &aggregatorpb.Ack{
+			TaskIndex:      signedAck.TaskIndex,
+			OperatorId:     signedAck.OperatorId.Bytes(),
+			ResponseDigest: signedAck.ResponseDigest.Bytes(),
+			Timestamp:      signedAck.Timestamp,
+			Signer:         signedAck.Signer.Bytes(),
+			Signature:      signedAck.Signature,
+		}
+	}
+	return reply, nil
+}
+
+func (s *grpcServer) GetTaskStatus(ctx context.Context, req *aggregatorpb.GetTaskStatusRequest) (*aggregatorpb.TaskStatus, error) {
+	taskStatus, err := s.aggregator.GetTaskDetails(req.TaskIndex)
+	if errors.Is(err, ErrUnknownTask) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		s.aggregator.logger.Error("Failed to get task status", "taskIndex", req.TaskIndex, "error", err)
+		return nil, status.Error(codes.Internal, "failed to get task status")
+	}
+
+	reply := &aggregatorpb.TaskStatus{
+		TaskIndex:        taskStatus.TaskIndex,
+		Status:           taskStatus.Status,
+		PoolId:           taskStatus.PoolId.Bytes(),
+		CreatedAt:        taskStatus.CreatedAt.Unix(),
+		TaskCreatedBlock: uint32(taskStatus.TaskCreatedBlock.Number),
+		Responses:        uint32(taskStatus.Responses),
+		Signers:          uint32(taskStatus.Signers),
+		Archived:         taskStatus.Archived,
+	}
+	if taskStatus.AggregatedResponse != nil {
+		reply.AggregatedResponse = encodeTaskResponse(*taskStatus.AggregatedResponse)
+	}
+	if taskStatus.AggregatedDigest != nil {
+		reply.AggregatedDigest = taskStatus.AggregatedDigest.Bytes()
+	}
+	if taskStatus.Submission != nil {
+		reply.SubmissionTxHash = taskStatus.Submission.TxHash.Bytes()
+		if taskStatus.Submission.Block != nil {
+			reply.SubmissionBlockNumber = taskStatus.Submission.Block.Number
+		}
+	}
+	return reply, nil
+}
+
+func (s *grpcServer) StreamTasks(req *aggregatorpb.StreamTasksRequest, stream aggregatorpb.Aggregator_StreamTasksServer) error {
+	tasks := make(chan *aggregatorpb.Task, grpcTaskBuffer)
+	s.mu.Lock()
+	s.streams[tasks] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.streams, tasks)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case task := <-tasks:
+			if err := stream.Send(task); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *grpcServer) CommitBid(ctx context.Context, req *aggregatorpb.CommitBidRequest) (*aggregatorpb.CommitBidReply, error) {
+	sealed, err := aggregatorpb.DecodeSealedBid(req.SealedBid)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	digest, err := s.aggregator.CommitBid(ctx, sealed)
+	if err != nil {
+		return nil, s.bidError(err, sealed.PoolId, sealed.Bidder)
+	}
+	return &aggregatorpb.CommitBidReply{SealedBidDigest: digest.Bytes()}, nil
+}
+
+func (s *grpcServer) RevealBid(ctx context.Context, req *aggregatorpb.RevealBidRequest) (*aggregatorpb.RevealBidReply, error) {
+	reveal, err := aggregatorpb.DecodeReveal(req.Bid, req.Salt)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	digest, err := s.aggregator.RevealBid(ctx, reveal)
+	if err != nil {
+		return nil, s.bidError(err, reveal.PoolId, reveal.Bidder)
+	}
+	return &aggregatorpb.RevealBidReply{BidDigest: digest.Bytes()}, nil
+}
+
+func (s *grpcServer) GetBids(ctx context.Context, req *aggregatorpb.GetBidsRequest) (*aggregatorpb.BidBook, error) {
+	poolId, err := aggregatorpb.DecodeHash(req.PoolId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid pool id: %v", err))
+	}
+
+	book, err := s.aggregator.Bids(ctx, poolId, req.BlockNumber)
+	if errors.Is(err, ErrBidBookDisabled) {
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	}
+	if err != nil {
+		s.aggregator.logger.Error("Failed to read bid book", "poolId", poolId.Hex(), "blockNumber", req.BlockNumber, "error", err)
+		return nil, status.Error(codes.Internal, "failed to read bid book")
+	}
+	return aggregatorpb.EncodeBidBook(book), nil
+}
+
+// bidError maps a commitment or reveal error to the gRPC status the HTTP
+// API's status code corresponds to
+func (s *grpcServer) bidError(err error, poolId common.Hash, bidder common.Address) error {
+	switch {
+	case errors.Is(err, ErrBidBookDisabled):
+		return status.Error(codes.Unimplemented, err.Error())
+	case isInvalidBid(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case isWrongPhase(err), errors.Is(err, escrow.ErrInsufficientDeposit):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, ErrAuctionFull):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, poolregistry.ErrPoolNotListed):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		s.aggregator.logger.Error("Failed to process bid", "poolId", poolId.Hex(), "bidder", bidder.Hex(), "error", err)
+		return status.Error(codes.Internal, "failed to process bid")
+	}
+}
+
+// broadcast queues the task for every StreamTasks client and returns how many
+// had a full queue and missed it
+func (s *grpcServer) broadcast(task wsproto.Task) int {
+	pushed := &aggregatorpb.Task{
+		TaskIndex:                 task.TaskIndex,
+		PoolId:                    task.PoolId.Bytes(),
+		BlockNumber:               task.BlockNumber,
+		TaskCreatedBlock:          task.TaskCreatedBlock,
+		QuorumNumbers:             task.QuorumNumbers,
+		QuorumThresholdPercentage: task.QuorumThresholdPercentage,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dropped := 0
+	for tasks := range s.streams {
+		select {
+		case tasks <- pushed:
+		default:
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// grpcResponseError maps a processing error to the gRPC status the HTTP API's
+// status code corresponds to
+func grpcResponseError(err error) error {
+	switch {
+	case errors.Is(err, ErrOperatorBanned), errors.Is(err, ErrOperatorNotAllowlisted), errors.Is(err, ErrOperatorNotRegistered):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, ErrUnknownTask):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrSignatureRejected), errors.Is(err, ErrNotInCommittee):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, ErrConflictingResponse):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, ErrTaskCompleted), errors.Is(err, ErrTaskExpired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, ErrTooManyOpenTasks), errors.Is(err, ErrTooManyResponses):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, "failed to process response")
+	}
+}
+
+func decodeSignedTaskResponse(req *aggregatorpb.SubmitTaskResponseRequest) (SignedTaskResponse, error) {
+	var signedResponse SignedTaskResponse
+	if req.TaskResponse == nil {
+		return signedResponse, errors.New("missing task response")
+	}
+	winner, err := aggregatorpb.DecodeAddress(req.TaskResponse.Winner)
+	if err != nil {
+		return signedResponse, fmt.Errorf("invalid winner: %w", err)
+	}
+	operatorId, err := aggregatorpb.DecodeHash(req.OperatorId)
+	if err != nil {
+		return signedResponse, fmt.Errorf("invalid operator id: %w", err)
+	}
+	signature, err := aggregatorpb.DecodeG1(req.BlsSignature)
+	if err != nil {
+		return signedResponse, fmt.Errorf("invalid bls signature: %w", err)
+	}
+
+	signedResponse = SignedTaskResponse{
+		TaskResponse: TaskResponse{
+			ReferenceTaskIndex: req.TaskResponse.ReferenceTaskIndex,
+			Winner:             winner,
+			WinningBid:         aggregatorpb.DecodeBigInt(req.TaskResponse.WinningBid),
+			TotalBids:          req.TaskResponse.TotalBids,
+		},
+		BlsSignature: bls.Signature{G1Point: signature},
+		OperatorId:   types.OperatorId(operatorId),
+	}
+	if len(req.CommitteeProof) > 0 {
+		proof, err := aggregatorpb.DecodeG1(req.CommitteeProof)
+		if err != nil {
+			return signedResponse, fmt.Errorf("invalid committee proof: %w", err)
+		}
+		signedResponse.CommitteeProof = &bls.Signature{G1Point: proof}
+	}
+	return signedResponse, nil
+}
+
+func encodeTaskResponse(response TaskResponse) *aggregatorpb.TaskResponse {
+	return &aggregatorpb.TaskResponse{
+		ReferenceTaskIndex: response.ReferenceTaskIndex,
+		Winner:             response.Winner.Bytes(),
+		WinningBid:         aggregatorpb.EncodeBigInt(response.WinningBid),
+		TotalBids:          response.TotalBids,
+	}
+}
@@ -0,0 +1,235 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+const (
+	// handoverTimeout bounds a handover, from the request until the state is
+	// received
+	handoverTimeout = time.Minute
+	// handoverDialTimeout bounds connecting to the running aggregator
+	handoverDialTimeout = time.Second
+	// handoverDrainTimeout bounds waiting for in-flight HTTP requests before
+	// the state is captured
+	handoverDrainTimeout = 15 * time.Second
+)
+
+// HandoverState is what an aggregator hands to the one taking over from it.
+// The HTTP listener is passed along with it, so connections queue in the
+// kernel instead of being refused while neither aggregator accepts them.
+type HandoverState struct {
+	Version      string    `json:"version"`
+	HandedOverAt time.Time `json:"handedOverAt"`
+	// Tasks are the open tasks with their responses and running aggregates
+	Tasks []AggregationCheckpoint `json:"tasks"`
+	// LatestTaskNum is the service manager's task numbering as last synced
+	LatestTaskNum uint32 `json:"latestTaskNum"`
+}
+
+// handoverRequest opens a handover
+type handoverRequest struct {
+	Version string `json:"version"`
+}
+
+// inheritedState is what an aggregator took over at startup
+type inheritedState struct {
+	state    HandoverState
+	listener net.Listener
+}
+
+// requestHandover takes over from the aggregator serving the handover socket
+// at path. It returns nil when there is none. The running aggregator stops
+// accepting responses, closes its task store and exits, so this has to happen
+// before the task store is opened.
+func requestHandover(path string, logger logging.Logger) (*inheritedState, error) {
+	conn, err := net.DialTimeout("unix", path, handoverDialTimeout)
+	if err != nil {
+		logger.Info("No running aggregator to take over from", "socket", path)
+		return nil, nil
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(handoverTimeout))
+
+	logger.Info("Taking over from running aggregator", "socket", path)
+	if err := json.NewEncoder(conn).Encode(handoverRequest{Version: SemVer}); err != nil {
+		return nil, fmt.Errorf("failed to request handover: %w", err)
+	}
+
+	// The listener comes first, attached to a single byte
+	marker := make([]byte, 1)
+	oob := make([]byte, syscall.CmsgSpace(4))
+	_, oobn, _, _, err := conn.(*net.UnixConn).ReadMsgUnix(marker, oob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive handed over listener: %w", err)
+	}
+	listener, err := parseHandedOverListener(oob[:oobn])
+	if err != nil {
+		return nil, err
+	}
+
+	var state HandoverState
+	if err := json.NewDecoder(conn).Decode(&state); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to receive handover state: %w", err)
+	}
+
+	logger.Info("Took over from running aggregator",
+		"version", state.Version,
+		"openTasks", len(state.Tasks),
+		"latestTaskNum", state.LatestTaskNum,
+		"listener", listener.Addr().String(),
+	)
+	return &inheritedState{state: state, listener: listener}, nil
+}
+
+func parseHandedOverListener(oob []byte) (net.Listener, error) {
+	messages, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil || len(messages) != 1 {
+		return nil, errors.New("handover did not include the listener")
+	}
+	fds, err := syscall.ParseUnixRights(&messages[0])
+	if err != nil || len(fds) != 1 {
+		return nil, errors.New("handover did not include the listener")
+	}
+
+	file := os.NewFile(uintptr(fds[0]), "handover-listener")
+	defer file.Close()
+	listener, err := net.FileListener(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use handed over listener: %w", err)
+	}
+	return listener, nil
+}
+
+// takeOver installs the state handed over by the previous aggregator. Its
+// tasks are at least as recent as any checkpoint restored from the store.
+func (a *Aggregator) takeOver(inherited *inheritedState) {
+	a.tasksMutex.Lock()
+	for _, checkpoint := range inherited.state.Tasks {
+		a.tasks[checkpoint.TaskIndex] = checkpoint.restoreTask()
+	}
+	a.tasksMutex.Unlock()
+
+	if a.taskSync != nil {
+		a.taskSync.seed(inherited.state.LatestTaskNum)
+	}
+	a.inheritedListener = inherited.listener
+}
+
+// serveHandover serves the handover socket until ctx is done or the
+// aggregator has handed over. Handing over drains the HTTP server and
+// operator sessions, stops the aggregator through stop and, once storeClosed
+// is closed, sends the open tasks along with httpListener.
+func (a *Aggregator) serveHandover(ctx context.Context, stop context.CancelFunc, httpListener net.Listener, storeClosed <-chan struct{}) {
+	path := a.config.HandoverSocketPath
+
+	// Anything still at the path belongs to an aggregator that has exited
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		a.logger.Error("Failed to remove stale handover socket", "socket", path, "error", err)
+		return
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		a.logger.Error("Failed to listen on handover socket", "socket", path, "error", err)
+		return
+	}
+	// The successor may have bound the path again by the time this closes
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	defer listener.Close()
+	context.AfterFunc(ctx, func() { listener.Close() })
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				a.logger.Error("Handover socket failed", "error", err)
+			}
+			os.Remove(path)
+			return
+		}
+
+		handedOver, err := a.handOver(conn, stop, httpListener, storeClosed)
+		conn.Close()
+		if handedOver {
+			if err != nil {
+				a.logger.Error("Handover failed after stopping, open tasks are left to the task store", "error", err)
+			}
+			return
+		}
+		a.logger.Warn("Rejected handover request", "error", err)
+	}
+}
+
+// handOver runs one handover. It reports whether the aggregator was stopped,
+// after which it can't go back to serving.
+func (a *Aggregator) handOver(conn net.Conn, stop context.CancelFunc, httpListener net.Listener, storeClosed <-chan struct{}) (bool, error) {
+	conn.SetDeadline(time.Now().Add(handoverTimeout))
+
+	var request handoverRequest
+	if err := json.NewDecoder(conn).Decode(&request); err != nil {
+		return false, fmt.Errorf("failed to read handover request: %w", err)
+	}
+	tcpListener, ok := httpListener.(*net.TCPListener)
+	if !ok {
+		return false, errors.New("http listener can't be handed over")
+	}
+	// A duplicate of the listener keeps it open once the server closes its own
+	listenerFile, err := tcpListener.File()
+	if err != nil {
+		return false, fmt.Errorf("failed to duplicate http listener: %w", err)
+	}
+	defer listenerFile.Close()
+
+	a.logger.Info("Handing over to new aggregator", "version", request.Version)
+
+	// No response may arrive once the state is captured
+	drainCtx, cancel := context.WithTimeout(context.Background(), handoverDrainTimeout)
+	defer cancel()
+	if err := a.httpServer.Shutdown(drainCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		a.logger.Warn("HTTP requests still in flight at handover", "error", err)
+	}
+	a.operatorHub.closeAll()
+	a.eventHub.closeAll()
+
+	// The successor opens the task store as soon as it has the state
+	stop()
+	<-storeClosed
+
+	state := HandoverState{
+		Version:      SemVer,
+		HandedOverAt: time.Now().UTC(),
+		Tasks:        []AggregationCheckpoint{},
+	}
+	a.tasksMutex.RLock()
+	for _, task := range a.tasks {
+		if !task.IsCompleted {
+			state.Tasks = append(state.Tasks, newAggregationCheckpoint(task))
+		}
+	}
+	a.tasksMutex.RUnlock()
+	if a.taskSync != nil {
+		state.LatestTaskNum = a.taskSync.latest()
+	}
+
+	rights := syscall.UnixRights(int(listenerFile.Fd()))
+	if _, _, err := conn.(*net.UnixConn).WriteMsgUnix([]byte{0}, rights, nil); err != nil {
+		return true, fmt.Errorf("failed to send http listener: %w", err)
+	}
+	if err := json.NewEncoder(conn).Encode(state); err != nil {
+		return true, fmt.Errorf("failed to send handover state: %w", err)
+	}
+
+	a.logger.Info("Handed over to new aggregator", "version", request.Version, "openTasks", len(state.Tasks))
+	return true, nil
+}
@@ -0,0 +1,99 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// Page sizes for GET /tasks/history
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 500
+)
+
+// HistoryFilter selects archived tasks, newest first
+type HistoryFilter struct {
+	// BeforeTaskIndex only returns tasks with a lower index, for paging
+	BeforeTaskIndex *uint32
+	Limit           int
+	IncludeDeleted  bool
+}
+
+// tasksHistoryHandler serves archived tasks from the task store, newest first.
+// Pass the lowest returned task index as "before" to fetch the next page.
+func (a *Aggregator) tasksHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if a.taskStore == nil {
+		http.Error(w, "Task history requires a task store", http.StatusNotImplemented)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := HistoryFilter{Limit: defaultHistoryLimit}
+
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = min(parsed, maxHistoryLimit)
+	}
+
+	if before := query.Get("before"); before != "" {
+		parsed, err := strconv.ParseUint(before, 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid before task index", http.StatusBadRequest)
+			return
+		}
+		beforeTaskIndex := uint32(parsed)
+		filter.BeforeTaskIndex = &beforeTaskIndex
+	}
+
+	filter.IncludeDeleted = query.Get("includeDeleted") == "true"
+
+	tasks, err := a.taskStore.ListArchivedTasks(filter)
+	if err != nil {
+		a.logger.Error("Failed to list task history", "error", err)
+		http.Error(w, "Failed to list task history", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"tasks": tasks,
+	})
+}
+
+// deleteTaskHistoryHandler soft deletes an archived task. The record is kept in
+// the store but hidden from the history unless includeDeleted is set.
+func (a *Aggregator) deleteTaskHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if a.taskStore == nil {
+		http.Error(w, "Task history requires a task store", http.StatusNotImplemented)
+		return
+	}
+
+	taskIndex, err := strconv.ParseUint(mux.Vars(r)["taskIndex"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid task index", http.StatusBadRequest)
+		return
+	}
+
+	deleted, err := a.taskStore.SoftDeleteArchivedTask(uint32(taskIndex))
+	if err != nil {
+		a.logger.Error("Failed to delete archived task", "taskIndex", taskIndex, "error", err)
+		http.Error(w, "Failed to delete archived task", http.StatusInternalServerError)
+		return
+	}
+	if deleted == 0 {
+		http.Error(w, "Task is not in the history", http.StatusNotFound)
+		return
+	}
+
+	a.logger.Info("Archived task deleted", "taskIndex", taskIndex, "records", deleted)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+}
@@ -0,0 +1,208 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/eigenlvr/avs/pkg/poolmetrics"
+)
+
+const (
+	// metricsListenRetry is how often listening on the metrics address is
+	// retried, as an aggregator handing over may still hold it
+	metricsListenRetry = time.Second
+	// metricsShutdownTimeout bounds closing the metrics server
+	metricsShutdownTimeout = 5 * time.Second
+)
+
+// Results of an aggregation attempt
+const (
+	aggregationResultAggregated         = "aggregated"
+	aggregationResultVerificationFailed = "verification_failed"
+	aggregationResultCalldataFailed     = "calldata_failed"
+	// aggregationResultFailed covers blsagg tasks that expired or errored
+	aggregationResultFailed = "failed"
+	// aggregationResultExpired is a builtin task whose response window closed
+	// below its thresholds
+	aggregationResultExpired = "expired"
+)
+
+// Results of submitting an aggregated response
+const (
+	submissionResultSubmitted = "submitted"
+	submissionResultReverted  = "reverted"
+	submissionResultFailed    = "failed"
+)
+
+// taskMetrics records the task pipeline per pool. Pool labels go through the
+// labeler so the series count stays bounded as new pools appear.
+type taskMetrics struct {
+	pools *poolmetrics.Labeler
+
+	tasksOpened        *prometheus.CounterVec
+	responsesReceived  *prometheus.CounterVec
+	aggregations       *prometheus.CounterVec
+	aggregationLatency *prometheus.HistogramVec
+	quorumSigned       *prometheus.HistogramVec
+	outlierResponses   *prometheus.CounterVec
+	submissions        *prometheus.CounterVec
+	submissionsSent    *prometheus.CounterVec
+	submissionLatency  *prometheus.HistogramVec
+}
+
+func newTaskMetrics(pools *poolmetrics.Labeler, reg prometheus.Registerer) *taskMetrics {
+	m := &taskMetrics{
+		pools: pools,
+		tasksOpened: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "tasks_opened_total",
+			Help:      "Tasks opened on their creation event or an earlier first response",
+		}, []string{poolmetrics.LabelName}),
+		responsesReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "task_responses_total",
+			Help:      "Operator task responses accepted",
+		}, []string{poolmetrics.LabelName}),
+		aggregations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "aggregations_total",
+			Help:      "Aggregation attempts by result",
+		}, []string{poolmetrics.LabelName, "result"}),
+		aggregationLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "aggregation_latency_seconds",
+			Help:      "Time from a task being opened to its responses being aggregated",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2, 5, 10, 30, 60},
+		}, []string{poolmetrics.LabelName}),
+		quorumSigned: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "aggregation_quorum_signed_percent",
+			Help:      "Percentage of each quorum's stake behind an aggregate, with the builtin backend",
+			Buckets:   []float64{50, 60, 67, 75, 80, 90, 95, 99, 100},
+		}, []string{poolmetrics.LabelName, "quorum"}),
+		outlierResponses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "outlier_responses_total",
+			Help:      "Operator responses over another digest than their task's aggregate",
+		}, []string{poolmetrics.LabelName}),
+		submissions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "submissions_total",
+			Help:      "Aggregated responses submitted to the service manager by result",
+		}, []string{poolmetrics.LabelName, "result"}),
+		submissionsSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "submission_transactions_sent_total",
+			Help:      "Submission transactions and user operations broadcast, counting fee bump replacements",
+		}, []string{poolmetrics.LabelName}),
+		submissionLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "submission_latency_seconds",
+			Help:      "Time from a submission first being broadcast to it being mined",
+			Buckets:   []float64{1, 2, 5, 10, 15, 30, 60, 120, 300},
+		}, []string{poolmetrics.LabelName}),
+	}
+	reg.MustRegister(m.tasksOpened, m.responsesReceived, m.aggregations, m.aggregationLatency, m.quorumSigned, m.outlierResponses, m.submissions, m.submissionsSent, m.submissionLatency)
+	return m
+}
+
+func (m *taskMetrics) taskOpened(poolId common.Hash) {
+	m.tasksOpened.WithLabelValues(m.pools.Label(poolId)).Inc()
+}
+
+func (m *taskMetrics) responseReceived(poolId common.Hash) {
+	m.responsesReceived.WithLabelValues(m.pools.Label(poolId)).Inc()
+}
+
+func (m *taskMetrics) outlierResponse(poolId common.Hash) {
+	m.outlierResponses.WithLabelValues(m.pools.Label(poolId)).Inc()
+}
+
+func (m *taskMetrics) aggregated(poolId common.Hash, result string, createdAt time.Time) {
+	pool := m.pools.Label(poolId)
+	m.aggregations.WithLabelValues(pool, result).Inc()
+	if result == aggregationResultAggregated {
+		m.aggregationLatency.WithLabelValues(pool).Observe(time.Since(createdAt).Seconds())
+	}
+}
+
+// quorumsSigned records the share of each quorum's stake that signed the aggregate
+func (m *taskMetrics) quorumsSigned(poolId common.Hash, aggregates []QuorumAggregate) {
+	pool := m.pools.Label(poolId)
+	for _, aggregate := range aggregates {
+		if aggregate.SignedStake == nil || aggregate.TotalStake == nil || aggregate.TotalStake.Sign() <= 0 {
+			continue
+		}
+		signed, _ := new(big.Rat).SetFrac(aggregate.SignedStake, aggregate.TotalStake).Float64()
+		quorum := strconv.Itoa(int(aggregate.QuorumNumber))
+		m.quorumSigned.WithLabelValues(pool, quorum).Observe(signed * 100)
+	}
+}
+
+func (m *taskMetrics) submitted(poolId common.Hash, result string) {
+	m.submissions.WithLabelValues(m.pools.Label(poolId), result).Inc()
+}
+
+func (m *taskMetrics) submissionSent(poolId common.Hash) {
+	m.submissionsSent.WithLabelValues(m.pools.Label(poolId)).Inc()
+}
+
+// submissionMined records how long the submission first broadcast at sentAt
+// took to be mined
+func (m *taskMetrics) submissionMined(poolId common.Hash, sentAt time.Time) {
+	m.submissionLatency.WithLabelValues(m.pools.Label(poolId)).Observe(time.Since(sentAt).Seconds())
+}
+
+// serveMetrics serves the metrics registry on EigenMetricsIpPortAddress until
+// the context is done
+func (a *Aggregator) serveMetrics(ctx context.Context) {
+	var listener net.Listener
+	for attempt := 0; ; attempt++ {
+		var err error
+		listener, err = net.Listen("tcp", a.config.EigenMetricsIpPortAddress)
+		if err == nil {
+			break
+		}
+		if attempt == 0 {
+			a.logger.Warn("Failed to listen for metrics, retrying", "address", a.config.EigenMetricsIpPortAddress, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(metricsListenRetry):
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(a.metricsReg, promhttp.HandlerOpts{}))
+	server := &http.Server{Handler: mux}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	a.logger.Info("Serving metrics", "address", listener.Addr().String())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		a.logger.Error("Metrics server error", "error", err)
+	}
+}
@@ -0,0 +1,103 @@
+package aggregator
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/blsaggregation"
+)
+
+// gathered sums the counter values, or histogram sample counts, of the metric
+// family's series whose labels include the given ones
+func gathered(t *testing.T, reg prometheus.Gatherer, name string, labels map[string]string) float64 {
+	t.Helper()
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total float64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	series:
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if want, ok := labels[label.GetName()]; ok && want != label.GetValue() {
+					continue series
+				}
+			}
+			if metric.GetHistogram() != nil {
+				total += float64(metric.GetHistogram().GetSampleCount())
+			} else {
+				total += metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return total
+}
+
+func TestSubmissionMetrics(t *testing.T) {
+	a, client := newTestAggregator(t)
+
+	response := TaskResponse{ReferenceTaskIndex: 9, WinningBid: big.NewInt(0)}
+	responseDigest := common.HexToHash("0x09")
+	task := newTestTask(9, response, responseDigest)
+	a.tasks[9] = task
+
+	a.recordBlsAggregation(blsaggregation.Result{
+		TaskIndex:                   9,
+		TaskResponse:                response,
+		Digest:                      responseDigest,
+		Signers:                     []types.OperatorId{{1}},
+		NonSignerStakesAndSignature: emptyNonSignerStakesAndSignature(),
+	})
+	mineSubmission(t, a, client, task)
+
+	if sent := gathered(t, a.metricsReg, "eigenlvr_aggregator_submission_transactions_sent_total", nil); sent != 1 {
+		t.Fatalf("recorded %v submission transactions sent, want 1", sent)
+	}
+	if mined := gathered(t, a.metricsReg, "eigenlvr_aggregator_submission_latency_seconds", nil); mined != 1 {
+		t.Fatalf("recorded %v submission latencies, want 1", mined)
+	}
+	submitted := map[string]string{"result": submissionResultSubmitted}
+	if count := gathered(t, a.metricsReg, "eigenlvr_aggregator_submissions_total", submitted); count != 1 {
+		t.Fatalf("recorded %v successful submissions, want 1", count)
+	}
+}
+
+func TestRevertedSubmissionMetrics(t *testing.T) {
+	a, client := newTestAggregator(t)
+
+	response := TaskResponse{ReferenceTaskIndex: 10, WinningBid: big.NewInt(0)}
+	task := newTestTask(10, response, common.HexToHash("0x0a"))
+	nonSignerStakesAndSignature := emptyNonSignerStakesAndSignature()
+	task.AggregatedResponse = &response
+	task.nonSignerStakesAndSignature = &nonSignerStakesAndSignature
+	a.tasks[10] = task
+
+	go a.submitTask(task)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(client.Pending()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("task submission was not sent")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	client.FailTransaction(client.Pending()[0].Hash())
+	mineSubmission(t, a, client, task)
+
+	reverted := map[string]string{"result": submissionResultReverted}
+	if count := gathered(t, a.metricsReg, "eigenlvr_aggregator_submissions_total", reverted); count != 1 {
+		t.Fatalf("recorded %v reverted submissions, want 1", count)
+	}
+	if mined := gathered(t, a.metricsReg, "eigenlvr_aggregator_submission_latency_seconds", nil); mined != 1 {
+		t.Fatalf("recorded %v submission latencies, want 1", mined)
+	}
+}
@@ -0,0 +1,36 @@
+package aggregator
+
+import (
+	"github.com/eigenlvr/avs/pkg/networks"
+)
+
+// applyNetworkPreset fills unset contract addresses from the configured
+// network's preset. Addresses set in the config are kept.
+func applyNetworkPreset(config Config) (Config, error) {
+	if config.Network == "" {
+		return config, nil
+	}
+
+	preset, err := networks.Lookup(config.Network)
+	if err != nil {
+		return config, err
+	}
+
+	networks.Fill(&config.RegistryCoordinatorAddress, preset.RegistryCoordinatorAddress)
+	networks.Fill(&config.OperatorStateRetrieverAddress, preset.OperatorStateRetrieverAddress)
+	networks.Fill(&config.ServiceManagerAddress, preset.ServiceManagerAddress)
+	if config.ExplorerTxUrl == "" && preset.ExplorerUrl != "" {
+		config.ExplorerTxUrl = preset.ExplorerUrl + "/tx/" + explorerHashPlaceholder
+	}
+	if config.ExplorerBlockUrl == "" && preset.ExplorerUrl != "" {
+		config.ExplorerBlockUrl = preset.ExplorerUrl + "/block/" + explorerNumberPlaceholder
+	}
+
+	if err := preset.Require(config.RegistryCoordinatorAddress, "registry_coordinator_address"); err != nil {
+		return config, err
+	}
+	if err := preset.Require(config.OperatorStateRetrieverAddress, "operator_state_retriever_address"); err != nil {
+		return config, err
+	}
+	return config, nil
+}
@@ -0,0 +1,136 @@
+package aggregator
+
+import (
+	"fmt"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/types"
+
+	"github.com/eigenlvr/avs/pkg/notify"
+)
+
+// NotificationConfig is a chat channel notifications are posted to. Type is
+// "telegram", which needs BotToken and ChatId, or "discord", which needs
+// WebhookUrl. Events limits the channel to auction_outcome, missed_quorum,
+// operator_health, param_update, shadow_mismatch or task_challenged; it
+// receives every event when empty.
+type NotificationConfig struct {
+	Type       string   `json:"type"`
+	BotToken   string   `json:"bot_token"`
+	ChatId     string   `json:"chat_id"`
+	WebhookUrl string   `json:"webhook_url"`
+	Events     []string `json:"events"`
+}
+
+// newNotifier returns nil when no channels are configured
+func newNotifier(configs []NotificationConfig, logger logging.Logger) (*notify.Notifier, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+
+	notifier := notify.NewNotifier(logger)
+	for i, config := range configs {
+		var channel notify.Channel
+		var err error
+		switch config.Type {
+		case "telegram":
+			channel, err = notify.NewTelegram(config.BotToken, config.ChatId)
+		case "discord":
+			channel, err = notify.NewDiscord(config.WebhookUrl)
+		default:
+			err = fmt.Errorf("unknown type %q", config.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid notification channel %d: %w", i, err)
+		}
+
+		events := make([]notify.Event, 0, len(config.Events))
+		for _, name := range config.Events {
+			event, err := notify.ParseEvent(name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid notification channel %d: %w", i, err)
+			}
+			events = append(events, event)
+		}
+		notifier.AddChannel(channel, events)
+	}
+	return notifier, nil
+}
+
+func (a *Aggregator) notify(message notify.Message) {
+	if a.notifier != nil {
+		a.notifier.Notify(message)
+	}
+}
+
+// notifyAuctionOutcome announces an aggregated task
+func (a *Aggregator) notifyAuctionOutcome(task *TaskInfo, response TaskResponse, signers int) {
+	winningBid := "0"
+	if response.WinningBid != nil {
+		winningBid = response.WinningBid.String()
+	}
+	a.notify(notify.Message{
+		Event: notify.EventAuctionOutcome,
+		Title: fmt.Sprintf("Auction %d aggregated", task.TaskIndex),
+		Text: fmt.Sprintf("Pool: %s\nWinner: %s\nWinning bid: %s\nBids: %d\nSigners: %d",
+			task.PoolId.Hex(), response.Winner.Hex(), winningBid, response.TotalBids, signers),
+	})
+}
+
+// notifyMissedQuorum announces a task that will never be aggregated
+func (a *Aggregator) notifyMissedQuorum(task *TaskInfo, reason string) {
+	a.notify(notify.Message{
+		Event: notify.EventMissedQuorum,
+		Title: fmt.Sprintf("Auction %d missed quorum", task.TaskIndex),
+		Text: fmt.Sprintf("Pool: %s\nResponses: %d\nReason: %s",
+			task.PoolId.Hex(), len(task.TaskResponsesInfo), reason),
+	})
+}
+
+// notifyOperatorBanned announces an operator banned for invalid signatures
+func (a *Aggregator) notifyOperatorBanned(operatorId types.OperatorId) {
+	a.notify(notify.Message{
+		Event: notify.EventOperatorHealth,
+		Title: "Operator banned",
+		Text:  fmt.Sprintf("Operator %s was banned for repeated invalid signatures", formatOperatorId(operatorId)),
+	})
+}
+
+// checkOperatorHealth announces registered operators that stopped responding
+// within the liveness window, or started again. Operators are only compared
+// with the previous check, so nothing is announced on the first one. It is
+// only called from watchOperatorSet.
+func (a *Aggregator) checkOperatorHealth() {
+	if a.notifier == nil {
+		return
+	}
+
+	first := a.operatorLive == nil
+	live := make(map[string]bool)
+	for _, operator := range a.GetOperators() {
+		if !operator.Registered || operator.LastResponseAt == nil {
+			continue
+		}
+		live[operator.OperatorId] = operator.Live
+
+		wasLive, known := a.operatorLive[operator.OperatorId]
+		if first || !known || wasLive == operator.Live {
+			continue
+		}
+		if operator.Live {
+			a.notify(notify.Message{
+				Event: notify.EventOperatorHealth,
+				Title: "Operator recovered",
+				Text:  fmt.Sprintf("Operator %s is responding again", operator.OperatorId),
+			})
+		} else {
+			a.notify(notify.Message{
+				Event: notify.EventOperatorHealth,
+				Title: "Operator unresponsive",
+				Text: fmt.Sprintf("Operator %s has not responded since %s",
+					operator.OperatorId, operator.LastResponseAt.UTC().Format("2006-01-02 15:04:05 MST")),
+			})
+		}
+	}
+	a.operatorLive = live
+}
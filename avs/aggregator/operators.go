@@ -0,0 +1,288 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/avsregistry"
+	"github.com/eigenlvr/avs/pkg/quorumapk"
+)
+
+const (
+	// defaultOperatorSetRefreshInterval is how often the registered operator set
+	// is re-read when OperatorSetRefreshInterval is unset
+	defaultOperatorSetRefreshInterval = time.Minute
+
+	// defaultOperatorLivenessWindow is how recently an operator must have
+	// responded to count as live when OperatorLivenessWindow is unset
+	defaultOperatorLivenessWindow = 10 * time.Minute
+)
+
+// OperatorStatus describes an operator as seen by the aggregator, combining its
+// on-chain registration with the responses received from it
+type OperatorStatus struct {
+	OperatorId     string                       `json:"operatorId"`
+	Address        *common.Address              `json:"address,omitempty"`
+	Socket         string                       `json:"socket,omitempty"`
+	Registered     bool                         `json:"registered"`
+	StakePerQuorum map[types.QuorumNum]*big.Int `json:"stakePerQuorum,omitempty"`
+	TotalStake     *big.Int                     `json:"totalStake"`
+	Banned         bool                         `json:"banned"`
+	Live           bool                         `json:"live"`
+	LastResponseAt *time.Time                   `json:"lastResponseAt,omitempty"`
+	LastTaskIndex  *uint32                      `json:"lastTaskIndex,omitempty"`
+	ResponseCount  uint64                       `json:"responseCount"`
+	// OutlierCount is how many of its responses disagreed with their task's aggregate
+	OutlierCount uint64 `json:"outlierCount"`
+}
+
+type operatorActivity struct {
+	lastResponseAt time.Time
+	lastTaskIndex  uint32
+	responseCount  uint64
+	outlierCount   uint64
+}
+
+// operatorTracker keeps the registered operator set, refreshed periodically from
+// the registry, alongside locally observed activity for each operator
+type operatorTracker struct {
+	mu         sync.RWMutex
+	registered []avsregistry.RegisteredOperator
+	sockets    map[types.OperatorId]types.Socket
+	activity   map[types.OperatorId]*operatorActivity
+
+	// The block socket updates have been read up to, so each refresh only scans new blocks
+	socketsSyncedBlock uint64
+	refreshedAt        time.Time
+}
+
+func newOperatorTracker() *operatorTracker {
+	return &operatorTracker{
+		sockets:  make(map[types.OperatorId]types.Socket),
+		activity: make(map[types.OperatorId]*operatorActivity),
+	}
+}
+
+// recordResponse notes that a response from the operator was accepted
+func (t *operatorTracker) recordResponse(operatorId types.OperatorId, taskIndex uint32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	activity, ok := t.activity[operatorId]
+	if !ok {
+		activity = &operatorActivity{}
+		t.activity[operatorId] = activity
+	}
+	activity.lastResponseAt = time.Now()
+	activity.lastTaskIndex = taskIndex
+	activity.responseCount++
+}
+
+// recordOutlier notes that a response from the operator disagreed with its
+// task's aggregate
+func (t *operatorTracker) recordOutlier(operatorId types.OperatorId) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	activity, ok := t.activity[operatorId]
+	if !ok {
+		activity = &operatorActivity{}
+		t.activity[operatorId] = activity
+	}
+	activity.outlierCount++
+}
+
+// refreshOperatorSet re-reads the registered operators and any socket updates
+// since the last refresh
+func (a *Aggregator) refreshOperatorSet(ctx context.Context) error {
+	registered, err := a.avsReader.GetOperatorSetAtCurrentBlock(ctx)
+	if err != nil {
+		return err
+	}
+
+	currentBlock, err := a.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.operators.mu.RLock()
+	fromBlock := a.operators.socketsSyncedBlock
+	a.operators.mu.RUnlock()
+
+	var sockets map[types.OperatorId]types.Socket
+	if currentBlock >= fromBlock {
+		sockets, err = a.avsReader.QueryExistingRegisteredOperatorSockets(
+			ctx,
+			new(big.Int).SetUint64(fromBlock),
+			new(big.Int).SetUint64(currentBlock),
+			nil,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	a.operators.mu.Lock()
+	defer a.operators.mu.Unlock()
+
+	a.operators.registered = registered
+	for operatorId, socket := range sockets {
+		a.operators.sockets[operatorId] = socket
+	}
+	a.operators.socketsSyncedBlock = currentBlock + 1
+	a.operators.refreshedAt = time.Now()
+
+	return nil
+}
+
+// refreshQuorumApks updates the tracked quorum APKs to match the registered operators
+func (a *Aggregator) refreshQuorumApks(ctx context.Context) error {
+	currentBlock, err := a.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	a.operators.mu.RLock()
+	operators := make([]quorumapk.Operator, 0, len(a.operators.registered))
+	for _, operator := range a.operators.registered {
+		quorums := make([]types.QuorumNum, 0, len(operator.StakePerQuorum))
+		for quorum := range operator.StakePerQuorum {
+			quorums = append(quorums, quorum)
+		}
+		operators = append(operators, quorumapk.Operator{
+			OperatorId: operator.OperatorId,
+			Address:    operator.Address,
+			Quorums:    quorums,
+		})
+	}
+	a.operators.mu.RUnlock()
+
+	return a.apkTracker.Refresh(ctx, operators, currentBlock)
+}
+
+// registeredOperators returns the operator set of the last refresh. Refreshes
+// replace the set rather than change it, so it can be read unlocked.
+func (a *Aggregator) registeredOperators() []avsregistry.RegisteredOperator {
+	a.operators.mu.RLock()
+	defer a.operators.mu.RUnlock()
+	return a.operators.registered
+}
+
+// watchOperatorSet keeps the registered operator set and quorum APKs up to date
+func (a *Aggregator) watchOperatorSet(ctx context.Context) {
+	interval := a.operatorSetRefreshInterval
+
+	refresh := func() {
+		a.refreshAllowlist(ctx)
+		if err := a.refreshOperatorSet(ctx); err != nil {
+			a.logger.Warn("Failed to refresh operator set", "error", err)
+			return
+		}
+		if err := a.refreshQuorumApks(ctx); err != nil {
+			a.logger.Warn("Failed to refresh quorum apks", "error", err)
+		}
+		if err := a.checkCommitteeCoverage(a.registeredOperators()); err != nil {
+			a.logger.Warn("Committees no longer cover the quorum thresholds", "error", err)
+		}
+		a.checkOperatorHealth()
+	}
+
+	refresh()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
+
+// GetOperators returns every registered operator, plus any unregistered operator
+// that has sent responses, ordered by total stake
+func (a *Aggregator) GetOperators() []OperatorStatus {
+	a.operators.mu.RLock()
+	defer a.operators.mu.RUnlock()
+
+	livenessCutoff := time.Now().Add(-a.operatorLivenessWindow)
+	statuses := make(map[types.OperatorId]*OperatorStatus)
+
+	for _, operator := range a.operators.registered {
+		address := operator.Address
+		totalStake := big.NewInt(0)
+		for _, stake := range operator.StakePerQuorum {
+			totalStake.Add(totalStake, stake)
+		}
+
+		statuses[operator.OperatorId] = &OperatorStatus{
+			OperatorId:     formatOperatorId(operator.OperatorId),
+			Address:        &address,
+			Socket:         string(a.operators.sockets[operator.OperatorId]),
+			Registered:     true,
+			StakePerQuorum: operator.StakePerQuorum,
+			TotalStake:     totalStake,
+		}
+	}
+
+	for operatorId, activity := range a.operators.activity {
+		status, ok := statuses[operatorId]
+		if !ok {
+			status = &OperatorStatus{
+				OperatorId: formatOperatorId(operatorId),
+				TotalStake: big.NewInt(0),
+			}
+			statuses[operatorId] = status
+		}
+
+		lastResponseAt := activity.lastResponseAt
+		lastTaskIndex := activity.lastTaskIndex
+		status.LastResponseAt = &lastResponseAt
+		status.LastTaskIndex = &lastTaskIndex
+		status.ResponseCount = activity.responseCount
+		status.OutlierCount = activity.outlierCount
+		status.Live = lastResponseAt.After(livenessCutoff)
+	}
+
+	operators := make([]OperatorStatus, 0, len(statuses))
+	for operatorId, status := range statuses {
+		status.Banned = a.banList.IsBanned(operatorId)
+		operators = append(operators, *status)
+	}
+
+	sort.Slice(operators, func(i, j int) bool {
+		if c := operators[i].TotalStake.Cmp(operators[j].TotalStake); c != 0 {
+			return c > 0
+		}
+		return operators[i].OperatorId < operators[j].OperatorId
+	})
+
+	return operators
+}
+
+func (a *Aggregator) operatorsHandler(w http.ResponseWriter, r *http.Request) {
+	a.operators.mu.RLock()
+	refreshedAt := a.operators.refreshedAt
+	a.operators.mu.RUnlock()
+
+	response := map[string]interface{}{
+		"operators": a.GetOperators(),
+	}
+	if !refreshedAt.IsZero() {
+		response["refreshedAt"] = refreshedAt
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,110 @@
+package aggregator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/notify"
+)
+
+// maxNotifiedOutliers bounds the operators listed in one outlier notification
+const maxNotifiedOutliers = 10
+
+// Outlier is an operator's response over another digest than the task's
+// aggregate. Signatures are only aggregated over one digest, so outliers are
+// never part of the aggregate; they are reported so operators running a
+// different bid book, rule set or build can be found.
+type Outlier struct {
+	OperatorId   string       `json:"operatorId"`
+	TaskResponse TaskResponse `json:"taskResponse"`
+	Digest       common.Hash  `json:"digest"`
+}
+
+// outliers returns the responses that disagree with the task's aggregate,
+// ordered by operator, and nil before the task is aggregated. Callers must
+// hold the tasks lock.
+func (t *TaskInfo) outliers() []Outlier {
+	if t.AggregatedDigest == nil {
+		return nil
+	}
+	var outliers []Outlier
+	for operatorId, info := range t.TaskResponsesInfo {
+		if info.Digest != *t.AggregatedDigest {
+			outliers = append(outliers, newOutlier(operatorId, info))
+		}
+	}
+	sort.Slice(outliers, func(i, j int) bool {
+		return outliers[i].OperatorId < outliers[j].OperatorId
+	})
+	return outliers
+}
+
+// outliers returns the archived responses that disagree with the aggregate
+func (t *ArchivedTask) outliers() []Outlier {
+	if t.AggregatedDigest == nil {
+		return nil
+	}
+	var outliers []Outlier
+	for _, response := range t.Responses {
+		if response.Digest != *t.AggregatedDigest {
+			outliers = append(outliers, Outlier{
+				OperatorId:   "0x" + response.OperatorId,
+				TaskResponse: response.TaskResponse,
+				Digest:       response.Digest,
+			})
+		}
+	}
+	return outliers
+}
+
+func newOutlier(operatorId types.OperatorId, info TaskResponseInfo) Outlier {
+	return Outlier{
+		OperatorId:   formatOperatorId(operatorId),
+		TaskResponse: info.TaskResponse,
+		Digest:       info.Digest,
+	}
+}
+
+// reportOutliers flags every response that disagrees with the task's newly
+// recorded aggregate. Completed tasks take no further responses, so these are
+// all the outliers the task will have. Callers must hold the tasks lock.
+func (a *Aggregator) reportOutliers(task *TaskInfo) {
+	for operatorId, info := range task.TaskResponsesInfo {
+		if info.Digest == *task.AggregatedDigest {
+			continue
+		}
+		a.logger.Warn("Operator response disagrees with the aggregate",
+			"taskIndex", task.TaskIndex,
+			"operatorId", formatOperatorId(operatorId),
+			"digest", info.Digest.Hex(),
+			"aggregatedDigest", task.AggregatedDigest.Hex(),
+			"winner", info.TaskResponse.Winner.Hex(),
+			"totalBids", info.TaskResponse.TotalBids,
+		)
+		a.metrics.outlierResponse(task.PoolId)
+		a.operators.recordOutlier(operatorId)
+	}
+
+	outliers := task.outliers()
+	if len(outliers) == 0 {
+		return
+	}
+
+	lines := make([]string, 0, maxNotifiedOutliers+1)
+	for i, outlier := range outliers {
+		if i == maxNotifiedOutliers {
+			lines = append(lines, fmt.Sprintf("and %d more", len(outliers)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s: winner %s, %d bids", outlier.OperatorId, outlier.TaskResponse.Winner.Hex(), outlier.TaskResponse.TotalBids))
+	}
+	a.notify(notify.Message{
+		Event: notify.EventOperatorHealth,
+		Title: fmt.Sprintf("Auction %d has %d outlier responses", task.TaskIndex, len(outliers)),
+		Text:  fmt.Sprintf("Pool: %s\nAggregate: %s\n%s", task.PoolId.Hex(), task.AggregatedDigest.Hex(), strings.Join(lines, "\n")),
+	})
+}
@@ -0,0 +1,498 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/eigenlvr/avs/pkg/notify"
+)
+
+const (
+	// defaultParamUpdateDelay is how long a parameter update is announced
+	// before it applies when ParamUpdateDelay is unset
+	defaultParamUpdateDelay = 24 * time.Hour
+
+	// paramApplyInterval is how often due parameter updates are applied
+	paramApplyInterval = 10 * time.Second
+)
+
+// ErrUnknownParamUpdate is returned when cancelling an update that isn't pending
+var ErrUnknownParamUpdate = errors.New("unknown parameter update")
+
+// RuntimeParams are the aggregation rules operators are held to. They only
+// change through timelocked updates.
+type RuntimeParams struct {
+	MinOperators  int      `json:"minOperators"`
+	MinTotalStake *big.Int `json:"minTotalStake"`
+	// QuorumThresholds raise, per quorum number, the signed stake
+	// percentage the task was created with; lower ones don't apply
+	QuorumThresholds map[uint8]uint32 `json:"quorumThresholds"`
+	// MinQuorumThresholds are, per quorum number, the lowest the threshold
+	// may be lowered to while operator participation is low, never below the
+	// task's own threshold. Quorums without one keep their threshold
+	// whatever the participation.
+	MinQuorumThresholds map[uint8]uint32 `json:"minQuorumThresholds"`
+}
+
+// ParamUpdate changes the fields that are set. QuorumThresholds and
+// MinQuorumThresholds replace the whole map.
+type ParamUpdate struct {
+	MinOperators        *int             `json:"minOperators,omitempty"`
+	MinTotalStake       *big.Int         `json:"minTotalStake,omitempty"`
+	QuorumThresholds    map[uint8]uint32 `json:"quorumThresholds,omitempty"`
+	MinQuorumThresholds map[uint8]uint32 `json:"minQuorumThresholds,omitempty"`
+}
+
+// PendingParamUpdate is an announced update and when it applies
+type PendingParamUpdate struct {
+	Id          uint64      `json:"id"`
+	Update      ParamUpdate `json:"update"`
+	Reason      string      `json:"reason"`
+	ProposedAt  time.Time   `json:"proposedAt"`
+	EffectiveAt time.Time   `json:"effectiveAt"`
+}
+
+// ParamsStatus is the current rules, the updates waiting to apply and the
+// delay new updates wait for
+type ParamsStatus struct {
+	Current RuntimeParams        `json:"current"`
+	Pending []PendingParamUpdate `json:"pending"`
+	Delay   string               `json:"delay"`
+}
+
+// paramsFile is the persisted schedule
+type paramsFile struct {
+	Current RuntimeParams        `json:"current"`
+	Pending []PendingParamUpdate `json:"pending"`
+	NextId  uint64               `json:"nextId"`
+}
+
+// paramSchedule holds the runtime params and the updates pending on them.
+// The schedule is persisted when a path is configured, so neither a restart
+// nor an edited config file changes the rules without the delay.
+type paramSchedule struct {
+	mu      sync.RWMutex
+	path    string
+	delay   time.Duration
+	current RuntimeParams
+	pending []PendingParamUpdate
+	nextId  uint64
+}
+
+// newParamSchedule starts from the configured params, or from the persisted
+// ones if there are any. Configured params that differ from the persisted
+// ones are scheduled as an update rather than applied, and returned so they
+// can be announced.
+func newParamSchedule(config Config) (*paramSchedule, *PendingParamUpdate, error) {
+	delay := defaultParamUpdateDelay
+	if config.ParamUpdateDelay != "" {
+		parsed, err := time.ParseDuration(config.ParamUpdateDelay)
+		if err != nil || parsed < 0 {
+			return nil, nil, fmt.Errorf("invalid param update delay: %q", config.ParamUpdateDelay)
+		}
+		delay = parsed
+	}
+
+	configured := RuntimeParams{
+		MinOperators:        config.MinOperators,
+		MinTotalStake:       big.NewInt(0),
+		QuorumThresholds:    config.QuorumThresholds,
+		MinQuorumThresholds: config.MinQuorumThresholds,
+	}
+	if config.MinTotalStake != "" {
+		if _, ok := configured.MinTotalStake.SetString(config.MinTotalStake, 10); !ok || configured.MinTotalStake.Sign() < 0 {
+			return nil, nil, fmt.Errorf("invalid min total stake: %q", config.MinTotalStake)
+		}
+	}
+	if err := validateParams(configured); err != nil {
+		return nil, nil, err
+	}
+
+	schedule := &paramSchedule{
+		path:    config.ParamUpdatesPath,
+		delay:   delay,
+		current: configured,
+		nextId:  1,
+	}
+	if schedule.path == "" {
+		return schedule, nil, nil
+	}
+
+	data, err := os.ReadFile(schedule.path)
+	if os.IsNotExist(err) {
+		return schedule, nil, schedule.save()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read param updates: %w", err)
+	}
+	var file paramsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode param updates: %w", err)
+	}
+	if file.Current.MinTotalStake == nil {
+		file.Current.MinTotalStake = big.NewInt(0)
+	}
+	schedule.current = file.Current
+	schedule.pending = file.Pending
+	schedule.nextId = max(file.NextId, 1)
+
+	update, changed := diffParams(schedule.current, configured)
+	if !changed || schedule.hasPending(update) {
+		return schedule, nil, nil
+	}
+	scheduled, err := schedule.Propose(update, "configuration changed")
+	if err != nil {
+		return nil, nil, err
+	}
+	return schedule, &scheduled, nil
+}
+
+// MinOperators returns the current minimum number of distinct responders, 0
+// when unset
+func (s *paramSchedule) MinOperators() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current.MinOperators
+}
+
+// MinTotalStake returns the current minimum signed stake
+func (s *paramSchedule) MinTotalStake() *big.Int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return new(big.Int).Set(s.current.MinTotalStake)
+}
+
+// QuorumThreshold returns the current threshold override of the quorum
+func (s *paramSchedule) QuorumThreshold(quorum uint8) (uint32, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	threshold, ok := s.current.QuorumThresholds[quorum]
+	return threshold, ok
+}
+
+// MinQuorumThreshold returns the lowest the quorum's threshold may be
+// lowered to, if it may be lowered at all
+func (s *paramSchedule) MinQuorumThreshold(quorum uint8) (uint32, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	threshold, ok := s.current.MinQuorumThresholds[quorum]
+	return threshold, ok
+}
+
+// Status returns the current params and pending updates, soonest first
+func (s *paramSchedule) Status() ParamsStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return ParamsStatus{
+		Current: s.current,
+		Pending: append([]PendingParamUpdate{}, s.pending...),
+		Delay:   s.delay.String(),
+	}
+}
+
+// Propose schedules an update to apply once the delay has passed
+func (s *paramSchedule) Propose(update ParamUpdate, reason string) (PendingParamUpdate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := validateParams(applyParamUpdate(s.current, update)); err != nil {
+		return PendingParamUpdate{}, err
+	}
+
+	now := time.Now().UTC()
+	pending := PendingParamUpdate{
+		Id:          s.nextId,
+		Update:      update,
+		Reason:      reason,
+		ProposedAt:  now,
+		EffectiveAt: now.Add(s.delay),
+	}
+	s.nextId++
+	s.pending = append(s.pending, pending)
+	sort.SliceStable(s.pending, func(i, j int) bool {
+		return s.pending[i].EffectiveAt.Before(s.pending[j].EffectiveAt)
+	})
+
+	return pending, s.save()
+}
+
+// Cancel drops a pending update
+func (s *paramSchedule) Cancel(id uint64) (PendingParamUpdate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, pending := range s.pending {
+		if pending.Id == id {
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			return pending, s.save()
+		}
+	}
+	return PendingParamUpdate{}, fmt.Errorf("%w: %d", ErrUnknownParamUpdate, id)
+}
+
+// ApplyDue applies the updates whose time has come, in order, and returns them
+func (s *paramSchedule) ApplyDue(now time.Time) ([]PendingParamUpdate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var applied []PendingParamUpdate
+	for len(s.pending) > 0 && !s.pending[0].EffectiveAt.After(now) {
+		s.current = applyParamUpdate(s.current, s.pending[0].Update)
+		applied = append(applied, s.pending[0])
+		s.pending = s.pending[1:]
+	}
+	if len(applied) == 0 {
+		return nil, nil
+	}
+	return applied, s.save()
+}
+
+// hasPending reports whether an identical update is already pending. Callers
+// must hold the lock or own the schedule.
+func (s *paramSchedule) hasPending(update ParamUpdate) bool {
+	for _, pending := range s.pending {
+		if _, differs := diffParams(applyParamUpdate(s.current, pending.Update), applyParamUpdate(s.current, update)); !differs {
+			return true
+		}
+	}
+	return false
+}
+
+// save writes the schedule to disk. Callers must hold the write lock.
+func (s *paramSchedule) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(paramsFile{
+		Current: s.current,
+		Pending: s.pending,
+		NextId:  s.nextId,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode param updates: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated schedule
+	tmpPath := s.path + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create param updates directory: %w", err)
+	}
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write param updates: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace param updates: %w", err)
+	}
+	return nil
+}
+
+// applyParamUpdate returns params with the update's fields replaced
+func applyParamUpdate(params RuntimeParams, update ParamUpdate) RuntimeParams {
+	if update.MinOperators != nil {
+		params.MinOperators = *update.MinOperators
+	}
+	if update.MinTotalStake != nil {
+		params.MinTotalStake = new(big.Int).Set(update.MinTotalStake)
+	}
+	if update.QuorumThresholds != nil {
+		params.QuorumThresholds = maps.Clone(update.QuorumThresholds)
+	}
+	if update.MinQuorumThresholds != nil {
+		params.MinQuorumThresholds = maps.Clone(update.MinQuorumThresholds)
+	}
+	return params
+}
+
+// diffParams returns the update turning from into to, and whether there is one
+func diffParams(from RuntimeParams, to RuntimeParams) (ParamUpdate, bool) {
+	var update ParamUpdate
+	changed := false
+	if from.MinOperators != to.MinOperators {
+		minOperators := to.MinOperators
+		update.MinOperators = &minOperators
+		changed = true
+	}
+	if from.MinTotalStake.Cmp(to.MinTotalStake) != 0 {
+		update.MinTotalStake = new(big.Int).Set(to.MinTotalStake)
+		changed = true
+	}
+	if !maps.Equal(from.QuorumThresholds, to.QuorumThresholds) {
+		update.QuorumThresholds = maps.Clone(to.QuorumThresholds)
+		if update.QuorumThresholds == nil {
+			update.QuorumThresholds = map[uint8]uint32{}
+		}
+		changed = true
+	}
+	if !maps.Equal(from.MinQuorumThresholds, to.MinQuorumThresholds) {
+		update.MinQuorumThresholds = maps.Clone(to.MinQuorumThresholds)
+		if update.MinQuorumThresholds == nil {
+			update.MinQuorumThresholds = map[uint8]uint32{}
+		}
+		changed = true
+	}
+	return update, changed
+}
+
+func validateParams(params RuntimeParams) error {
+	if params.MinOperators < 0 {
+		return fmt.Errorf("invalid min operators: %d", params.MinOperators)
+	}
+	if params.MinTotalStake == nil || params.MinTotalStake.Sign() < 0 {
+		return fmt.Errorf("invalid min total stake: %v", params.MinTotalStake)
+	}
+	for quorum, threshold := range params.QuorumThresholds {
+		if threshold == 0 || threshold > 100 {
+			return fmt.Errorf("invalid threshold %d for quorum %d", threshold, quorum)
+		}
+	}
+	for quorum, threshold := range params.MinQuorumThresholds {
+		if threshold == 0 || threshold > 100 {
+			return fmt.Errorf("invalid min threshold %d for quorum %d", threshold, quorum)
+		}
+		if override, ok := params.QuorumThresholds[quorum]; ok && threshold > override {
+			return fmt.Errorf("min threshold %d for quorum %d is above its threshold %d", threshold, quorum, override)
+		}
+	}
+	return nil
+}
+
+// watchParamUpdates applies pending updates as they come due
+func (a *Aggregator) watchParamUpdates(ctx context.Context) {
+	ticker := time.NewTicker(paramApplyInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			applied, err := a.params.ApplyDue(now)
+			if err != nil {
+				a.logger.Error("Failed to persist applied param updates", "error", err)
+			}
+			for _, update := range applied {
+				a.announceParamUpdate("applied", update)
+			}
+		}
+	}
+}
+
+// announceParamUpdate logs and notifies a change to the schedule
+func (a *Aggregator) announceParamUpdate(action string, update PendingParamUpdate) {
+	description := describeParamUpdate(update.Update)
+	a.logger.Info("Runtime param update "+action,
+		"id", update.Id,
+		"update", description,
+		"reason", update.Reason,
+		"effectiveAt", update.EffectiveAt,
+	)
+	a.notify(notify.Message{
+		Event: notify.EventParamUpdate,
+		Title: fmt.Sprintf("Param update %d %s", update.Id, action),
+		Text: fmt.Sprintf("Change: %s\nReason: %s\nEffective: %s",
+			description, update.Reason, update.EffectiveAt.Format(time.RFC3339)),
+	})
+}
+
+func describeParamUpdate(update ParamUpdate) string {
+	var changes []string
+	if update.MinOperators != nil {
+		changes = append(changes, fmt.Sprintf("min operators %d", *update.MinOperators))
+	}
+	if update.MinTotalStake != nil {
+		changes = append(changes, fmt.Sprintf("min total stake %s", update.MinTotalStake))
+	}
+	if update.QuorumThresholds != nil {
+		changes = append(changes, fmt.Sprintf("quorum thresholds %s", describeQuorumThresholds(update.QuorumThresholds)))
+	}
+	if update.MinQuorumThresholds != nil {
+		changes = append(changes, fmt.Sprintf("min quorum thresholds %s", describeQuorumThresholds(update.MinQuorumThresholds)))
+	}
+	return strings.Join(changes, ", ")
+}
+
+func describeQuorumThresholds(thresholds map[uint8]uint32) string {
+	quorums := make([]int, 0, len(thresholds))
+	for quorum := range thresholds {
+		quorums = append(quorums, int(quorum))
+	}
+	sort.Ints(quorums)
+	described := make([]string, 0, len(quorums))
+	for _, quorum := range quorums {
+		described = append(described, fmt.Sprintf("%d:%d%%", quorum, thresholds[uint8(quorum)]))
+	}
+	return "{" + strings.Join(described, " ") + "}"
+}
+
+// paramUpdateRequest proposes an update through the admin API
+type paramUpdateRequest struct {
+	ParamUpdate
+	Reason string `json:"reason"`
+}
+
+// paramsHandler serves GET /params, so operators can see rule changes coming
+func (a *Aggregator) paramsHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(a.params.Status())
+}
+
+func (a *Aggregator) proposeParamUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	var request paramUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if request.MinOperators == nil && request.MinTotalStake == nil && request.QuorumThresholds == nil && request.MinQuorumThresholds == nil {
+		http.Error(w, "Update changes nothing", http.StatusBadRequest)
+		return
+	}
+
+	pending, err := a.params.Propose(request.ParamUpdate, request.Reason)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	a.announceParamUpdate("scheduled", pending)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(pending)
+}
+
+func (a *Aggregator) cancelParamUpdateHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid update id", http.StatusBadRequest)
+		return
+	}
+
+	cancelled, err := a.params.Cancel(id)
+	if err != nil {
+		if errors.Is(err, ErrUnknownParamUpdate) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		a.logger.Error("Failed to cancel param update", "id", id, "error", err)
+		http.Error(w, "Failed to cancel update", http.StatusInternalServerError)
+		return
+	}
+	a.announceParamUpdate("cancelled", cancelled)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "cancelled"})
+}
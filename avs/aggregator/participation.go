@@ -0,0 +1,167 @@
+package aggregator
+
+import (
+	"math/big"
+	"strconv"
+	"sync"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defaultParticipationWindowTasks is how many recent tasks participation is
+// averaged over when ParticipationWindowTasks is unset
+const defaultParticipationWindowTasks = 20
+
+// participationTracker keeps, per quorum, the percentage of stake that
+// responded to each of the most recent tasks
+type participationTracker struct {
+	window int
+	logger logging.Logger
+
+	mu      sync.Mutex
+	samples map[types.QuorumNum][]uint32
+	// lowered is the threshold each quorum was last lowered to, to log changes
+	lowered map[types.QuorumNum]uint32
+
+	participationGauge *prometheus.GaugeVec
+	thresholdGauge     *prometheus.GaugeVec
+}
+
+func newParticipationTracker(config Config, reg prometheus.Registerer, logger logging.Logger) *participationTracker {
+	window := config.ParticipationWindowTasks
+	if window <= 0 {
+		window = defaultParticipationWindowTasks
+	}
+
+	tracker := &participationTracker{
+		window:  window,
+		logger:  logger,
+		samples: make(map[types.QuorumNum][]uint32),
+		lowered: make(map[types.QuorumNum]uint32),
+		participationGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "quorum_participation_percent",
+			Help:      "Percentage of each quorum's stake that responded, averaged over recent tasks",
+		}, []string{"quorum"}),
+		thresholdGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "quorum_threshold_lowered_percent",
+			Help:      "Threshold each quorum is lowered to for low participation, 0 when it isn't",
+		}, []string{"quorum"}),
+	}
+	reg.MustRegister(tracker.participationGauge, tracker.thresholdGauge)
+	return tracker
+}
+
+// record adds the participation of a task that has left its response window
+func (t *participationTracker) record(quorum types.QuorumNum, percent uint32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	samples := append(t.samples[quorum], percent)
+	if len(samples) > t.window {
+		samples = samples[len(samples)-t.window:]
+	}
+	t.samples[quorum] = samples
+
+	if mean, ok := t.meanLocked(quorum); ok {
+		t.participationGauge.WithLabelValues(strconv.Itoa(int(quorum))).Set(float64(mean))
+	}
+}
+
+// rolling returns the quorum's participation averaged over the window, once
+// the window is full
+func (t *participationTracker) rolling(quorum types.QuorumNum) (uint32, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.meanLocked(quorum)
+}
+
+func (t *participationTracker) meanLocked(quorum types.QuorumNum) (uint32, bool) {
+	samples := t.samples[quorum]
+	if len(samples) < t.window {
+		return 0, false
+	}
+	var sum uint64
+	for _, sample := range samples {
+		sum += uint64(sample)
+	}
+	return uint32(sum / uint64(len(samples))), true
+}
+
+// noteThreshold logs and exports the threshold a quorum is lowered to, 0 when
+// it is back to normal, whenever that changes
+func (t *participationTracker) noteThreshold(quorum types.QuorumNum, lowered uint32, threshold uint32) {
+	t.mu.Lock()
+	previous := t.lowered[quorum]
+	t.lowered[quorum] = lowered
+	t.mu.Unlock()
+
+	if previous == lowered {
+		return
+	}
+	t.thresholdGauge.WithLabelValues(strconv.Itoa(int(quorum))).Set(float64(lowered))
+	if lowered == 0 {
+		t.logger.Info("Quorum threshold restored as participation recovered", "quorum", quorum, "threshold", threshold)
+	} else {
+		t.logger.Warn("Quorum threshold lowered for low participation", "quorum", quorum, "threshold", threshold, "loweredTo", lowered)
+	}
+}
+
+// adjustThreshold lowers a quorum's threshold to what recent participation can
+// reach, when the params allow lowering it at all. It never goes below the
+// quorum's MinQuorumThreshold, nor below the threshold the task was created
+// with, which the service manager checks on its own. Only a threshold raised
+// by QuorumThresholds or the task's pool can be lowered, back towards the
+// task's. Response windows aren't extended either, as responses past the
+// service manager's TASK_RESPONSE_WINDOW_BLOCK are rejected on chain.
+func (a *Aggregator) adjustThreshold(task *TaskInfo, quorum types.QuorumNum, threshold uint32) uint32 {
+	floor, ok := a.params.MinQuorumThreshold(uint8(quorum))
+	if !ok || a.participation == nil {
+		return threshold
+	}
+	floor = max(floor, uint32(task.QuorumThresholdPercentage))
+
+	participation, ok := a.participation.rolling(quorum)
+	if !ok || participation >= threshold || floor >= threshold {
+		a.participation.noteThreshold(quorum, 0, threshold)
+		return threshold
+	}
+
+	lowered := max(floor, participation)
+	a.participation.noteThreshold(quorum, lowered, threshold)
+	return lowered
+}
+
+// recordParticipation records the stake share of every quorum that responded
+// to a task leaving memory. Callers must hold the tasks lock.
+func (a *Aggregator) recordParticipation(task *TaskInfo) {
+	if a.participation == nil {
+		return
+	}
+
+	var stakes map[types.QuorumNum]*quorumStake
+	if task.OperatorSetSnapshot != nil {
+		stakes = task.OperatorSetSnapshot.quorumStakes()
+	} else {
+		stakes = a.taskStakes(task)
+	}
+
+	for quorum, stake := range stakes {
+		if stake.total.Sign() == 0 {
+			continue
+		}
+		responded := big.NewInt(0)
+		for operatorId := range task.TaskResponsesInfo {
+			if operatorStake, ok := stake.operators[operatorId]; ok {
+				responded.Add(responded, operatorStake)
+			}
+		}
+		percent := new(big.Int).Div(new(big.Int).Mul(responded, big.NewInt(100)), stake.total)
+		a.participation.record(quorum, uint32(percent.Uint64()))
+	}
+}
@@ -0,0 +1,54 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/eigenlvr/avs/pkg/poolregistry"
+)
+
+// loadPoolRegistry returns nil when no pools file is configured
+func loadPoolRegistry(config Config, logger logging.Logger) (*poolregistry.Registry, error) {
+	if config.PoolsFile == "" {
+		return nil, nil
+	}
+	pools, unknown, err := poolregistry.Load(config.PoolsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pools file: %w", err)
+	}
+	for _, field := range unknown {
+		logger.Warn("Ignoring unknown pools file field", "file", config.PoolsFile, "field", field.Path, "line", field.Line, "column", field.Column)
+	}
+	logger.Info("Loaded pool registry", "file", config.PoolsFile, "pools", len(pools.Pools()))
+	return pools, nil
+}
+
+// poolQuorumThreshold returns the task's pool's threshold override, if any
+func (a *Aggregator) poolQuorumThreshold(task *TaskInfo) (uint32, bool) {
+	pool, err := a.pools.Pool(task.PoolId)
+	if err != nil || pool.QuorumThresholdPercentage == 0 {
+		return 0, false
+	}
+	return pool.QuorumThresholdPercentage, true
+}
+
+// poolsResponse lists the registered pools. Open is set when no pools file
+// is configured and every pool is accepted.
+type poolsResponse struct {
+	Open  bool                `json:"open"`
+	Pools []poolregistry.Pool `json:"pools"`
+}
+
+// poolsHandler serves GET /pools, so searchers and operators can see which
+// pools auctions run for and with which parameters
+func (a *Aggregator) poolsHandler(w http.ResponseWriter, r *http.Request) {
+	response := poolsResponse{Open: a.pools == nil, Pools: a.pools.Pools()}
+	if response.Pools == nil {
+		response.Pools = []poolregistry.Pool{}
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
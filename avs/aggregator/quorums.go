@@ -0,0 +1,226 @@
+package aggregator
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+
+	"github.com/eigenlvr/avs/pkg/avsregistry"
+)
+
+// referenceStakesTimeout bounds reading a task's operator set at its reference block
+const referenceStakesTimeout = 10 * time.Second
+
+// defaultQuorumThresholdPercentage applies to quorums with no threshold from
+// the task or from QuorumThresholds
+const defaultQuorumThresholdPercentage = 67
+
+// QuorumAggregate is the aggregate of one quorum's signers over a response
+type QuorumAggregate struct {
+	QuorumNumber       types.QuorumNum    `json:"quorumNumber"`
+	ThresholdPercent   uint32             `json:"thresholdPercent"`
+	Signers            []types.OperatorId `json:"signers"`
+	SignedStake        *big.Int           `json:"signedStake"`
+	TotalStake         *big.Int           `json:"totalStake"`
+	AggregateSignature bls.Signature      `json:"aggregateSignature"`
+}
+
+// quorumStake is one quorum's registered stake, total and per operator
+type quorumStake struct {
+	total     *big.Int
+	operators map[types.OperatorId]*big.Int
+}
+
+// taskQuorums returns the quorums a task is aggregated over. Tasks that don't
+// name their quorums use every quorum the APK tracker knows.
+func (a *Aggregator) taskQuorums(task *TaskInfo) types.QuorumNums {
+	if len(task.QuorumNumbers) > 0 {
+		return task.QuorumNumbers
+	}
+	return a.apkTracker.Quorums()
+}
+
+// quorumThreshold returns the signed stake percentage a quorum needs. The
+// runtime per-quorum threshold wins over the pool registry's override for
+// the task's pool, which wins over the one the task was created with. Both
+// overrides only raise the task's threshold, which the service manager
+// checks on its own. It is lowered while participation is low, see
+// adjustThreshold.
+func (a *Aggregator) quorumThreshold(task *TaskInfo, quorum types.QuorumNum) uint32 {
+	return a.adjustThreshold(task, quorum, a.configuredQuorumThreshold(task, quorum))
+}
+
+func (a *Aggregator) configuredQuorumThreshold(task *TaskInfo, quorum types.QuorumNum) uint32 {
+	if threshold, ok := a.params.QuorumThreshold(uint8(quorum)); ok {
+		return max(threshold, uint32(task.QuorumThresholdPercentage))
+	}
+	if threshold, ok := a.poolQuorumThreshold(task); ok {
+		return max(threshold, uint32(task.QuorumThresholdPercentage))
+	}
+	if task.QuorumThresholdPercentage > 0 {
+		return uint32(task.QuorumThresholdPercentage)
+	}
+	return defaultQuorumThresholdPercentage
+}
+
+// loadReferenceStakes reads the task's operator set from the
+// OperatorStateRetriever at its creation block, which is the stake
+// checkSignatures measures the aggregate against. It does nothing for tasks
+// whose creation block isn't known yet or whose stakes are already loaded.
+// On failure the task keeps being measured against the last operator set
+// refresh, and the next response tries again.
+func (a *Aggregator) loadReferenceStakes(ctx context.Context, taskIndex uint32) {
+	a.tasksMutex.RLock()
+	task, exists := a.tasks[taskIndex]
+	if !exists || task.TaskCreatedBlock == 0 || task.referenceOperators != nil {
+		a.tasksMutex.RUnlock()
+		return
+	}
+	block := task.TaskCreatedBlock
+	quorums := a.taskQuorums(task)
+	a.tasksMutex.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, referenceStakesTimeout)
+	defer cancel()
+	operators, err := a.avsReader.GetOperatorSetAtBlock(ctx, quorums, block)
+	if err != nil {
+		a.logger.Warn("Failed to read operator stakes at task creation block, using the last operator set",
+			"taskIndex", taskIndex,
+			"block", block,
+			"error", err,
+		)
+		return
+	}
+	if operators == nil {
+		operators = []avsregistry.RegisteredOperator{}
+	}
+
+	a.tasksMutex.Lock()
+	if task.referenceOperators == nil {
+		task.referenceOperators = operators
+	}
+	a.tasksMutex.Unlock()
+}
+
+// taskStakes returns each of the task's quorums' stake at the task's creation
+// block once loaded, or from the last operator set refresh until then.
+// Callers must hold the tasks lock.
+func (a *Aggregator) taskStakes(task *TaskInfo) map[types.QuorumNum]*quorumStake {
+	quorums := a.taskQuorums(task)
+	if task.referenceOperators == nil {
+		return a.quorumStakes(quorums)
+	}
+	return collectQuorumStakes(quorums, task.referenceOperators)
+}
+
+// quorumStakes reads each quorum's stake from the last operator set refresh
+func (a *Aggregator) quorumStakes(quorums types.QuorumNums) map[types.QuorumNum]*quorumStake {
+	a.operators.mu.RLock()
+	defer a.operators.mu.RUnlock()
+	return collectQuorumStakes(quorums, a.operators.registered)
+}
+
+// collectQuorumStakes sums the stake of the operators in each of the quorums
+func collectQuorumStakes(quorums types.QuorumNums, operators []avsregistry.RegisteredOperator) map[types.QuorumNum]*quorumStake {
+	stakes := make(map[types.QuorumNum]*quorumStake, len(quorums))
+	for _, quorum := range quorums {
+		stakes[quorum] = &quorumStake{
+			total:     big.NewInt(0),
+			operators: make(map[types.OperatorId]*big.Int),
+		}
+	}
+
+	for _, operator := range operators {
+		for quorum, stake := range operator.StakePerQuorum {
+			quorumStake, ok := stakes[quorum]
+			if !ok || stake == nil {
+				continue
+			}
+			quorumStake.total.Add(quorumStake.total, stake)
+			quorumStake.operators[operator.OperatorId] = stake
+		}
+	}
+	return stakes
+}
+
+// quorumThresholdsMet reports whether the bucket's signers hold each quorum's
+// threshold of stake. Every quorum is checked on its own, so a heavily signed
+// quorum can't make up for one that is short, and a task without quorums is
+// never met.
+func (a *Aggregator) quorumThresholdsMet(task *TaskInfo, bucket []TaskResponseInfo) bool {
+	quorums := a.taskQuorums(task)
+	if len(quorums) == 0 {
+		// A task with no quorums, before the quorum apks are loaded, has no
+		// stake to be signed by
+		return false
+	}
+	stakes := a.taskStakes(task)
+
+	for _, quorum := range quorums {
+		stake := stakes[quorum]
+		if stake.total.Sign() == 0 {
+			// Nothing to measure against until the operator set is loaded
+			return false
+		}
+
+		signed := big.NewInt(0)
+		for _, responseInfo := range bucket {
+			if operatorStake, ok := stake.operators[responseInfo.OperatorId]; ok {
+				signed.Add(signed, operatorStake)
+			}
+		}
+
+		if !meetsThreshold(signed, stake.total, a.quorumThreshold(task, quorum)) {
+			return false
+		}
+	}
+	return true
+}
+
+// aggregateQuorums aggregates the bucket separately for every quorum of the
+// snapshot, counting only the signers registered in that quorum
+func aggregateQuorums(snapshot *OperatorSetSnapshot, bucket []TaskResponseInfo) []QuorumAggregate {
+	stakes := snapshot.quorumStakes()
+
+	aggregates := make([]QuorumAggregate, 0, len(snapshot.Quorums))
+	for _, quorum := range snapshot.Quorums {
+		stake := stakes[quorum.QuorumNumber]
+		aggregate := QuorumAggregate{
+			QuorumNumber:       quorum.QuorumNumber,
+			ThresholdPercent:   quorum.ThresholdPercent,
+			SignedStake:        big.NewInt(0),
+			TotalStake:         stake.total,
+			AggregateSignature: *bls.NewZeroSignature(),
+		}
+
+		for _, responseInfo := range bucket {
+			operatorStake, ok := stake.operators[responseInfo.OperatorId]
+			if !ok {
+				continue
+			}
+			signature := responseInfo.BlsSignature
+			aggregate.AggregateSignature.Add(&signature)
+			aggregate.Signers = append(aggregate.Signers, responseInfo.OperatorId)
+			aggregate.SignedStake.Add(aggregate.SignedStake, operatorStake)
+		}
+
+		// Signers are ordered so the same bucket always aggregates identically
+		sort.Slice(aggregate.Signers, func(i, j int) bool {
+			return string(aggregate.Signers[i][:]) < string(aggregate.Signers[j][:])
+		})
+		aggregates = append(aggregates, aggregate)
+	}
+	return aggregates
+}
+
+// meetsThreshold reports whether signed is at least thresholdPercent of total,
+// compared the way the BLSSignatureChecker does: signed*100 >= total*threshold
+func meetsThreshold(signed, total *big.Int, thresholdPercent uint32) bool {
+	lhs := new(big.Int).Mul(signed, big.NewInt(100))
+	rhs := new(big.Int).Mul(total, big.NewInt(int64(thresholdPercent)))
+	return lhs.Cmp(rhs) >= 0
+}
@@ -0,0 +1,160 @@
+package aggregator
+
+import (
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/poolregistry"
+	"github.com/eigenlvr/avs/pkg/quorumapk"
+)
+
+func TestPoolThresholdOnlyRaisesTaskThreshold(t *testing.T) {
+	a, _ := newTestAggregator(t)
+	params, _, err := newParamSchedule(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.params = params
+
+	lenient, strict := common.HexToHash("0x1e"), common.HexToHash("0x57")
+	a.pools, err = poolregistry.New([]poolregistry.Pool{
+		{PoolId: lenient, QuorumThresholdPercentage: 51},
+		{PoolId: strict, QuorumThresholdPercentage: 90},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		pool common.Hash
+		want uint32
+	}{
+		{pool: lenient, want: 67},
+		{pool: strict, want: 90},
+	} {
+		task := &TaskInfo{PoolId: tc.pool, QuorumThresholdPercentage: 67}
+		if threshold := a.configuredQuorumThreshold(task, 0); threshold != tc.want {
+			t.Errorf("pool %s: threshold %d, want %d", tc.pool.Hex(), threshold, tc.want)
+		}
+	}
+}
+
+func TestRuntimeThresholdOnlyRaisesTaskThreshold(t *testing.T) {
+	a, _ := newTestAggregator(t)
+	params, _, err := newParamSchedule(Config{QuorumThresholds: map[uint8]uint32{0: 51, 1: 90}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.params = params
+
+	task := &TaskInfo{QuorumThresholdPercentage: 67}
+	if threshold := a.configuredQuorumThreshold(task, 0); threshold != 67 {
+		t.Errorf("override below the task's threshold: threshold %d, want 67", threshold)
+	}
+	if threshold := a.configuredQuorumThreshold(task, 1); threshold != 90 {
+		t.Errorf("override above the task's threshold: threshold %d, want 90", threshold)
+	}
+}
+
+func TestLowParticipationLowersRaisedThreshold(t *testing.T) {
+	a, _ := newTestAggregator(t)
+	params, _, err := newParamSchedule(Config{
+		QuorumThresholds:    map[uint8]uint32{0: 90},
+		MinQuorumThresholds: map[uint8]uint32{0: 50},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.params = params
+	a.participation = newParticipationTracker(Config{ParticipationWindowTasks: 3}, a.metricsReg, a.logger)
+
+	// Four operators of equal stake, three of which respond: 75% of the stake
+	task := newQuickTask([]uint16{25, 25, 25, 25}, 66)
+	digest := common.HexToHash("0x01")
+	for i := 0; i < 3; i++ {
+		respond(task, i, digest)
+	}
+	if a.shouldAggregateTask(task, task.responsesWithDigest(digest)) {
+		t.Fatal("aggregated 75% of the stake below the raised 90% threshold")
+	}
+
+	for i := 0; i < 3; i++ {
+		a.participation.record(0, 75)
+	}
+	if !a.shouldAggregateTask(task, task.responsesWithDigest(digest)) {
+		t.Fatal("75% of the stake didn't aggregate once participation settled at 75%")
+	}
+
+	// However low participation gets, the task's own 67% still applies
+	for i := 0; i < 3; i++ {
+		a.participation.record(0, 40)
+	}
+	lowered := newQuickTask([]uint16{25, 25, 25, 25}, 66)
+	for i := 0; i < 2; i++ {
+		respond(lowered, i, digest)
+	}
+	if threshold := a.quorumThreshold(lowered, 0); threshold != 67 {
+		t.Fatalf("threshold lowered to %d, want the task's 67", threshold)
+	}
+	if a.shouldAggregateTask(lowered, lowered.responsesWithDigest(digest)) {
+		t.Fatal("aggregated 50% of the stake below the task's threshold")
+	}
+}
+
+func TestCommitteeMustCoverQuorumThreshold(t *testing.T) {
+	a := newQuickAggregator(t)
+	stakes := make([]uint16, 10)
+	for i := range stakes {
+		stakes[i] = 10
+	}
+	operators := newQuickTask(stakes, 0).referenceOperators
+
+	// Ten equal operators: a committee of three holds 30% of the stake on
+	// average, short of the default 67% measured against the full quorum
+	a.config.CommitteeVrfExpectedSize = 3
+	if err := a.checkCommitteeCoverage(operators); err == nil {
+		t.Fatal("accepted a committee expected to hold 30% of a 67% quorum")
+	}
+
+	a.config.CommitteeVrfExpectedSize = 7
+	if err := a.checkCommitteeCoverage(operators); err != nil {
+		t.Fatalf("rejected a committee expected to hold 70%% of a 67%% quorum: %v", err)
+	}
+
+	// A raised threshold needs a larger committee
+	params, _, err := newParamSchedule(Config{QuorumThresholds: map[uint8]uint32{0: 80}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.params = params
+	if err := a.checkCommitteeCoverage(operators); err == nil {
+		t.Fatal("accepted a committee expected to hold 70% of an 80% quorum")
+	}
+
+	a.config.CommitteeVrfExpectedSize = 0
+	if err := a.checkCommitteeCoverage(operators); err != nil {
+		t.Fatalf("checked coverage without committees: %v", err)
+	}
+}
+
+func TestTaskWithoutQuorumsIsNeverMet(t *testing.T) {
+	a := newQuickAggregator(t)
+	a.apkTracker = &quorumapk.Tracker{}
+	task := newQuickTask([]uint16{10, 10}, 0)
+	task.QuorumNumbers = nil
+
+	respond(task, 0, common.Hash{1})
+	respond(task, 1, common.Hash{1})
+	bucket := []TaskResponseInfo{task.TaskResponsesInfo[task.referenceOperators[0].OperatorId], task.TaskResponsesInfo[task.referenceOperators[1].OperatorId]}
+	if a.quorumThresholdsMet(task, bucket) {
+		t.Fatal("met the thresholds of a task without quorums")
+	}
+
+	// The same responses meet the task's threshold once it has a quorum
+	task.QuorumNumbers = types.QuorumNums{0}
+	if !a.quorumThresholdsMet(task, bucket) {
+		t.Fatal("unanimous responses didn't meet the quorum's threshold")
+	}
+}
@@ -0,0 +1,123 @@
+package aggregator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+)
+
+// InclusionReceipt tells an operator whether its response to a task was part
+// of the aggregate the aggregator submitted
+type InclusionReceipt struct {
+	TaskIndex  uint32 `json:"taskIndex"`
+	OperatorId string `json:"operatorId"`
+	// Responded is set when the aggregator accepted a response from the operator
+	Responded      bool         `json:"responded"`
+	ResponseDigest *common.Hash `json:"responseDigest,omitempty"`
+	// Included is set when the operator's signature is in the aggregate
+	Included         bool         `json:"included"`
+	AggregatedDigest *common.Hash `json:"aggregatedDigest,omitempty"`
+	SubmissionTxHash *common.Hash `json:"submissionTxHash,omitempty"`
+	// Archived is set when the receipt was read from the task store
+	Archived bool `json:"archived"`
+}
+
+// GetInclusionReceipt looks the task up in memory, then in the task store.
+// It returns ErrUnknownTask if the task is in neither.
+func (a *Aggregator) GetInclusionReceipt(taskIndex uint32, operatorId types.OperatorId) (InclusionReceipt, error) {
+	receipt := InclusionReceipt{
+		TaskIndex:  taskIndex,
+		OperatorId: formatOperatorId(operatorId),
+	}
+
+	a.tasksMutex.RLock()
+	task, exists := a.tasks[taskIndex]
+	if exists {
+		if info, responded := task.TaskResponsesInfo[operatorId]; responded {
+			receipt.Responded = true
+			receipt.ResponseDigest = &info.Digest
+		}
+		for _, signer := range task.Signers {
+			if signer == operatorId {
+				receipt.Included = true
+				break
+			}
+		}
+		receipt.AggregatedDigest = task.AggregatedDigest
+		receipt.SubmissionTxHash = task.SubmissionTxHash
+	}
+	a.tasksMutex.RUnlock()
+
+	if exists {
+		return receipt, nil
+	}
+	if a.taskStore == nil {
+		return receipt, ErrUnknownTask
+	}
+
+	archived, err := a.taskStore.GetArchivedTask(taskIndex)
+	if err != nil {
+		return receipt, err
+	}
+	if archived == nil {
+		return receipt, ErrUnknownTask
+	}
+
+	// Archived records key operators by unprefixed hex
+	storedId := hex.EncodeToString(operatorId[:])
+	receipt.Archived = true
+	for _, response := range archived.Responses {
+		if response.OperatorId == storedId {
+			digest := response.Digest
+			receipt.Responded = true
+			receipt.ResponseDigest = &digest
+			break
+		}
+	}
+	for _, signer := range archived.Signers {
+		if signer == storedId {
+			receipt.Included = true
+			break
+		}
+	}
+	receipt.AggregatedDigest = archived.AggregatedDigest
+	receipt.SubmissionTxHash = archived.SubmissionTxHash
+
+	return receipt, nil
+}
+
+// inclusionReceiptHandler serves GET /task/{taskIndex}/inclusion/{operatorId}
+func (a *Aggregator) inclusionReceiptHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	taskIndex, err := strconv.ParseUint(vars["taskIndex"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid task index", http.StatusBadRequest)
+		return
+	}
+	operatorId, err := parseOperatorId(vars["operatorId"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	receipt, err := a.GetInclusionReceipt(uint32(taskIndex), operatorId)
+	if err != nil {
+		if errors.Is(err, ErrUnknownTask) {
+			http.Error(w, "Unknown task", http.StatusNotFound)
+			return
+		}
+		a.logger.Error("Failed to look up inclusion receipt", "taskIndex", taskIndex, "error", err)
+		http.Error(w, "Failed to look up inclusion receipt", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(receipt)
+}
@@ -0,0 +1,57 @@
+package aggregator
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/blsaggregation"
+)
+
+func TestInclusionReceiptAfterSubmission(t *testing.T) {
+	a, client := newTestAggregator(t)
+
+	response := TaskResponse{ReferenceTaskIndex: 11, WinningBid: big.NewInt(0)}
+	responseDigest := common.HexToHash("0x0b")
+	task := newTestTask(11, response, responseDigest)
+	a.tasks[11] = task
+	signer := types.OperatorId{1}
+
+	a.recordBlsAggregation(blsaggregation.Result{
+		TaskIndex:                   11,
+		TaskResponse:                response,
+		Digest:                      responseDigest,
+		Signers:                     []types.OperatorId{signer},
+		NonSignerStakesAndSignature: emptyNonSignerStakesAndSignature(),
+	})
+	mineSubmission(t, a, client, task)
+
+	sent := client.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d transactions, want 1", len(sent))
+	}
+
+	receipt, err := a.GetInclusionReceipt(11, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !receipt.Responded || !receipt.Included {
+		t.Fatalf("signer responded %v included %v, want both", receipt.Responded, receipt.Included)
+	}
+	if receipt.AggregatedDigest == nil || *receipt.AggregatedDigest != responseDigest {
+		t.Fatalf("receipt aggregated digest %v, want %s", receipt.AggregatedDigest, responseDigest.Hex())
+	}
+	if receipt.SubmissionTxHash == nil || *receipt.SubmissionTxHash != sent[0].Hash() {
+		t.Fatalf("receipt submission %v, want %s", receipt.SubmissionTxHash, sent[0].Hash().Hex())
+	}
+
+	absent, err := a.GetInclusionReceipt(11, types.OperatorId{2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if absent.Responded || absent.Included {
+		t.Fatalf("operator without a response responded %v included %v", absent.Responded, absent.Included)
+	}
+}
@@ -0,0 +1,80 @@
+package aggregator
+
+import (
+	"context"
+)
+
+// replayRestoredTasks finishes what a crash interrupted on the tasks restored
+// from checkpoints. Open tasks whose responses already meet the thresholds are
+// aggregated. Completed tasks whose result was never sent are aggregated and
+// submitted again. Tasks with a submission transaction are left alone, since
+// sending another could settle the task twice. The blsagg backend loses its
+// state on restart, so its restored tasks only keep collecting responses.
+func (a *Aggregator) replayRestoredTasks(ctx context.Context) {
+	a.tasksMutex.RLock()
+	var restored []uint32
+	for taskIndex, task := range a.tasks {
+		if task.restored {
+			restored = append(restored, taskIndex)
+		}
+	}
+	a.tasksMutex.RUnlock()
+
+	for _, taskIndex := range restored {
+		if ctx.Err() != nil {
+			return
+		}
+		if a.blsAggregation == nil {
+			a.loadReferenceStakes(ctx, taskIndex)
+		}
+		a.replayTask(taskIndex)
+	}
+}
+
+func (a *Aggregator) replayTask(taskIndex uint32) {
+	a.tasksMutex.Lock()
+	defer a.tasksMutex.Unlock()
+
+	task, exists := a.tasks[taskIndex]
+	if !exists || !task.restored {
+		return
+	}
+	task.restored = false
+	// A response received since the restart has already handled the task
+	if task.revision != 0 || a.blsAggregation != nil || task.SubmissionTxHash != nil || task.ExpiredAt != nil {
+		return
+	}
+
+	// A task completed without a recorded result was still aggregating, so
+	// it is reopened and its buckets checked again
+	if task.IsCompleted && task.AggregatedDigest == nil {
+		task.IsCompleted = false
+	}
+
+	if task.IsCompleted {
+		digest := *task.AggregatedDigest
+		bucket := task.responsesWithDigest(digest)
+		if !a.meetsAggregationThresholds(task, bucket) {
+			a.logger.Error("Restored result no longer meets the aggregation thresholds, not resubmitting",
+				"taskIndex", taskIndex,
+				"digest", digest.Hex(),
+			)
+			return
+		}
+		a.logger.Info("Resubmitting restored task result", "taskIndex", taskIndex, "digest", digest.Hex())
+		go a.aggregateAndSubmitTask(task, digest, bucket)
+		return
+	}
+
+	for digest := range task.responseDigests() {
+		bucket := task.responsesWithDigest(digest)
+		if !a.shouldAggregateTask(task, bucket) {
+			continue
+		}
+		a.logger.Info("Aggregating restored task", "taskIndex", taskIndex, "digest", digest.Hex())
+		task.IsCompleted = true
+		task.revision++
+		go a.aggregateAndSubmitTask(task, digest, bucket)
+		return
+	}
+}
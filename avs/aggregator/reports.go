@@ -0,0 +1,223 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultReportEpochBlocks is the epoch length of LVR reports, about a day of
+// mainnet blocks
+const DefaultReportEpochBlocks = 7200
+
+// ReportChain is the chain data an LVR report is checked against. Submission
+// receipts tell which auctions actually settled, and block headers date the
+// epochs.
+type ReportChain interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+}
+
+// ReportOptions selects the tasks an LVR report covers and how they are grouped
+type ReportOptions struct {
+	// EpochBlocks is how many blocks, by task creation block, each epoch spans
+	EpochBlocks uint32
+	// Pools, when set, limits the report to these pools
+	Pools []common.Hash
+	// FromBlock and ToBlock bound task creation blocks, inclusively; zero
+	// ToBlock has no upper bound
+	FromBlock uint32
+	ToBlock   uint32
+}
+
+// PoolEpochReport is the LVR captured in one pool during one epoch. Amounts
+// are in wei. Only auctions whose result settled on chain count as captured;
+// without chain data, any submitted result counts.
+type PoolEpochReport struct {
+	PoolId    common.Hash `json:"poolId"`
+	Epoch     uint32      `json:"epoch"`
+	FromBlock uint32      `json:"fromBlock"`
+	ToBlock   uint32      `json:"toBlock"`
+	// StartTime and EndTime are the timestamps of the epoch's first and last
+	// blocks, when chain data was available
+	StartTime *time.Time `json:"startTime,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+
+	Tasks          int `json:"tasks"`
+	CompletedTasks int `json:"completedTasks"`
+	SettledTasks   int `json:"settledTasks"`
+	// FailedSettlements are submissions that reverted or never landed
+	FailedSettlements int `json:"failedSettlements"`
+	// CapturedAuctions are settled tasks with a winning bid
+	CapturedAuctions  int      `json:"capturedAuctions"`
+	TotalBids         uint64   `json:"totalBids"`
+	UniqueWinners     int      `json:"uniqueWinners"`
+	LvrCaptured       *big.Int `json:"lvrCapturedWei"`
+	AverageWinningBid *big.Int `json:"averageWinningBidWei"`
+	MaxWinningBid     *big.Int `json:"maxWinningBidWei"`
+}
+
+// LvrReport is the LVR captured per pool and epoch, sorted by epoch then pool
+type LvrReport struct {
+	GeneratedAt time.Time         `json:"generatedAt"`
+	EpochBlocks uint32            `json:"epochBlocks"`
+	ChainData   bool              `json:"chainData"`
+	Epochs      []PoolEpochReport `json:"epochs"`
+}
+
+type poolEpoch struct {
+	pool  common.Hash
+	epoch uint32
+}
+
+// BuildLvrReport groups archived tasks by pool and epoch. chain may be nil to
+// build the report from the task store alone.
+func BuildLvrReport(ctx context.Context, tasks []ArchivedTask, options ReportOptions, chain ReportChain) (*LvrReport, error) {
+	if options.EpochBlocks == 0 {
+		options.EpochBlocks = DefaultReportEpochBlocks
+	}
+	pools := make(map[common.Hash]bool, len(options.Pools))
+	for _, pool := range options.Pools {
+		pools[pool] = true
+	}
+
+	report := &LvrReport{
+		GeneratedAt: time.Now().UTC(),
+		EpochBlocks: options.EpochBlocks,
+		ChainData:   chain != nil,
+	}
+	entries := make(map[poolEpoch]*PoolEpochReport)
+	winners := make(map[poolEpoch]map[common.Address]bool)
+	// A task can be archived more than once, e.g. after a restore; the most
+	// recently archived copy wins
+	latest := make(map[uint32]ArchivedTask, len(tasks))
+	for _, task := range tasks {
+		if previous, ok := latest[task.TaskIndex]; !ok || task.ArchivedAt.After(previous.ArchivedAt) {
+			latest[task.TaskIndex] = task
+		}
+	}
+
+	for _, task := range latest {
+		if task.DeletedAt != nil {
+			continue
+		}
+		if len(pools) > 0 && !pools[task.PoolId] {
+			continue
+		}
+		if task.TaskCreatedBlock < options.FromBlock || (options.ToBlock > 0 && task.TaskCreatedBlock > options.ToBlock) {
+			continue
+		}
+
+		key := poolEpoch{pool: task.PoolId, epoch: task.TaskCreatedBlock / options.EpochBlocks}
+		entry, ok := entries[key]
+		if !ok {
+			entry = &PoolEpochReport{
+				PoolId:            key.pool,
+				Epoch:             key.epoch,
+				FromBlock:         key.epoch * options.EpochBlocks,
+				ToBlock:           key.epoch*options.EpochBlocks + options.EpochBlocks - 1,
+				LvrCaptured:       big.NewInt(0),
+				AverageWinningBid: big.NewInt(0),
+				MaxWinningBid:     big.NewInt(0),
+			}
+			entries[key] = entry
+			winners[key] = make(map[common.Address]bool)
+		}
+
+		entry.Tasks++
+		if !task.IsCompleted || task.AggregatedResponse == nil {
+			continue
+		}
+		entry.CompletedTasks++
+		entry.TotalBids += uint64(task.AggregatedResponse.TotalBids)
+
+		settled, err := taskSettled(ctx, task, chain)
+		if err != nil {
+			return nil, err
+		}
+		if !settled {
+			if task.SubmissionTxHash != nil {
+				entry.FailedSettlements++
+			}
+			continue
+		}
+		entry.SettledTasks++
+
+		bid := task.AggregatedResponse.WinningBid
+		if bid == nil || bid.Sign() <= 0 || task.AggregatedResponse.Winner == (common.Address{}) {
+			continue
+		}
+		entry.CapturedAuctions++
+		entry.LvrCaptured.Add(entry.LvrCaptured, bid)
+		if bid.Cmp(entry.MaxWinningBid) > 0 {
+			entry.MaxWinningBid = new(big.Int).Set(bid)
+		}
+		winners[key][task.AggregatedResponse.Winner] = true
+	}
+
+	for key, entry := range entries {
+		entry.UniqueWinners = len(winners[key])
+		if entry.CapturedAuctions > 0 {
+			entry.AverageWinningBid = new(big.Int).Div(entry.LvrCaptured, big.NewInt(int64(entry.CapturedAuctions)))
+		}
+		if chain != nil {
+			start, err := blockTime(ctx, chain, entry.FromBlock)
+			if err != nil {
+				return nil, err
+			}
+			end, err := blockTime(ctx, chain, entry.ToBlock)
+			if err != nil {
+				return nil, err
+			}
+			entry.StartTime, entry.EndTime = start, end
+		}
+		report.Epochs = append(report.Epochs, *entry)
+	}
+
+	sort.Slice(report.Epochs, func(i, j int) bool {
+		if report.Epochs[i].Epoch != report.Epochs[j].Epoch {
+			return report.Epochs[i].Epoch < report.Epochs[j].Epoch
+		}
+		return report.Epochs[i].PoolId.Hex() < report.Epochs[j].PoolId.Hex()
+	})
+	return report, nil
+}
+
+// taskSettled reports whether a completed task's result landed on chain. With
+// no chain data a submitted result is taken as settled.
+func taskSettled(ctx context.Context, task ArchivedTask, chain ReportChain) (bool, error) {
+	if task.SubmissionTxHash == nil {
+		return false, nil
+	}
+	if chain == nil {
+		return true, nil
+	}
+	receipt, err := chain.TransactionReceipt(ctx, *task.SubmissionTxHash)
+	if errors.Is(err, ethereum.NotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get submission receipt of task %d: %w", task.TaskIndex, err)
+	}
+	return receipt.Status == gethtypes.ReceiptStatusSuccessful, nil
+}
+
+// blockTime returns the timestamp of a block, or nil when it isn't mined yet
+func blockTime(ctx context.Context, chain ReportChain, number uint32) (*time.Time, error) {
+	header, err := chain.HeaderByNumber(ctx, new(big.Int).SetUint64(uint64(number)))
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", number, err)
+	}
+	timestamp := time.Unix(int64(header.Time), 0).UTC()
+	return &timestamp, nil
+}
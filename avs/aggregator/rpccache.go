@@ -0,0 +1,31 @@
+package aggregator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eigenlvr/avs/pkg/rpccache"
+)
+
+// newRpcCache returns the process's caching proxy in front of EthRpcUrl, or
+// nil when RpcCacheSize is unset
+func newRpcCache(config Config) (*rpccache.Proxy, error) {
+	if config.RpcCacheSize <= 0 {
+		return nil, nil
+	}
+
+	cacheConfig := rpccache.Config{Size: config.RpcCacheSize}
+	if config.RpcCacheHeadTtl != "" {
+		headTtl, err := time.ParseDuration(config.RpcCacheHeadTtl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rpc cache head ttl: %w", err)
+		}
+		cacheConfig.HeadTtl = headTtl
+	}
+
+	proxy, err := rpccache.Shared(config.EthRpcUrl, cacheConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start rpc cache: %w", err)
+	}
+	return proxy, nil
+}
@@ -0,0 +1,72 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+
+	"github.com/eigenlvr/avs/pkg/sigchecker"
+)
+
+// buildNonSignerStakesAndSignature assembles the checkSignatures argument for
+// the signers' aggregate over the task's quorums. The task's creation block is
+// the reference block, as the service manager checks stakes as of then.
+func (a *Aggregator) buildNonSignerStakesAndSignature(
+	ctx context.Context,
+	task *TaskInfo,
+	signers []types.OperatorId,
+	responses []TaskResponseInfo,
+) (sigchecker.NonSignerStakesAndSignature, error) {
+	a.tasksMutex.RLock()
+	quorums := a.taskQuorums(task)
+	referenceBlock := task.TaskCreatedBlock
+	a.tasksMutex.RUnlock()
+
+	referenceBlock, err := a.referenceBlock(ctx, referenceBlock)
+	if err != nil {
+		return sigchecker.NonSignerStakesAndSignature{}, err
+	}
+
+	signerSet, err := a.apkTracker.SignerSet(quorums, signers)
+	if err != nil {
+		return sigchecker.NonSignerStakesAndSignature{}, err
+	}
+
+	// Sigma covers every signer once, however many quorums it is in
+	signed := make(map[types.OperatorId]struct{}, len(signers))
+	for _, signer := range signers {
+		signed[signer] = struct{}{}
+	}
+	sigma := bls.NewZeroSignature()
+	for _, responseInfo := range responses {
+		if _, ok := signed[responseInfo.OperatorId]; !ok {
+			continue
+		}
+		signature := responseInfo.BlsSignature
+		sigma.Add(&signature)
+	}
+
+	return a.sigBuilder.Build(ctx, referenceBlock, sigchecker.Signature{
+		QuorumNumbers: quorums,
+		QuorumApks:    signerSet.QuorumApks,
+		NonSigners:    signerSet.NonSigners,
+		SignersApkG2:  signerSet.SignersApkG2,
+		Sigma:         sigma,
+	})
+}
+
+// referenceBlock returns the block a task's stakes are checked at: its creation
+// block, or the last block for tasks whose creation block isn't known
+func (a *Aggregator) referenceBlock(ctx context.Context, taskCreatedBlock uint32) (uint32, error) {
+	if taskCreatedBlock != 0 {
+		return taskCreatedBlock, nil
+	}
+	currentBlock, err := a.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get reference block: %w", err)
+	}
+	// checkSignatures only accepts reference blocks in the past
+	return uint32(currentBlock - 1), nil
+}
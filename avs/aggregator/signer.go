@@ -0,0 +1,42 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/ecdsakey"
+	"github.com/eigenlvr/avs/pkg/passphrase"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+)
+
+// newAggregatorSigner returns the signer for the aggregator's key: the remote
+// signer AggregatorSigner selects, or the local key file. It returns nil when
+// neither is configured.
+func newAggregatorSigner(config Config) (remotesigner.Signer, error) {
+	if config.AggregatorSigner == "" || config.AggregatorSigner == remotesigner.KindLocal {
+		if config.AggregatorPrivateKeyPath == "" {
+			return nil, nil
+		}
+		key, err := ecdsakey.Load(config.AggregatorPrivateKeyPath, passphrase.Lookup(config.AggregatorPrivateKeyPasswordFile, passphrase.EcdsaEnv))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load aggregator private key: %w", err)
+		}
+		return remotesigner.NewLocal(key), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remotesigner.DefaultTimeout)
+	defer cancel()
+
+	signer, err := remotesigner.New(ctx, remotesigner.Config{
+		Kind:    config.AggregatorSigner,
+		Url:     config.AggregatorSignerUrl,
+		KeyId:   config.AggregatorSignerKeyId,
+		Address: common.HexToAddress(config.AggregatorSignerAddress),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to aggregator signer: %w", err)
+	}
+	return signer, nil
+}
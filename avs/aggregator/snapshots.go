@@ -0,0 +1,213 @@
+package aggregator
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+
+	"github.com/eigenlvr/avs/pkg/sigchecker"
+)
+
+// ErrNoSnapshot is returned for tasks that were never evaluated against an
+// operator set
+var ErrNoSnapshot = errors.New("task has no operator set snapshot")
+
+// OperatorSetSnapshot is the operator set a task's aggregate was evaluated
+// against. Together with the task's responses it is everything needed to
+// recompute each quorum's signed stake and threshold decision.
+type OperatorSetSnapshot struct {
+	TaskIndex        uint32    `json:"taskIndex"`
+	TaskCreatedBlock uint32    `json:"taskCreatedBlock"`
+	TakenAt          time.Time `json:"takenAt"`
+	// StakeBlock is the block the stakes were read at, normally the task's
+	// creation block. When it is unset, they are from the operator set
+	// refresh at OperatorSetRefreshedAt. ApkBlock is the block the quorum
+	// APKs were read at.
+	StakeBlock             uint32             `json:"stakeBlock,omitempty"`
+	OperatorSetRefreshedAt time.Time          `json:"operatorSetRefreshedAt"`
+	ApkBlock               uint64             `json:"apkBlock"`
+	Quorums                []SnapshotQuorum   `json:"quorums"`
+	Operators              []SnapshotOperator `json:"operators"`
+}
+
+// SnapshotQuorum is one quorum of the task as it was evaluated
+type SnapshotQuorum struct {
+	QuorumNumber     types.QuorumNum     `json:"quorumNumber"`
+	ThresholdPercent uint32              `json:"thresholdPercent"`
+	TotalStake       *big.Int            `json:"totalStake"`
+	Apk              *sigchecker.G1Point `json:"apk,omitempty"`
+}
+
+// SnapshotOperator is an operator registered in any of the task's quorums,
+// with its stake in each of them
+type SnapshotOperator struct {
+	OperatorId     string                       `json:"operatorId"`
+	Address        common.Address               `json:"address"`
+	StakePerQuorum map[types.QuorumNum]*big.Int `json:"stakePerQuorum"`
+	PubkeyG1       *sigchecker.G1Point          `json:"pubkeyG1,omitempty"`
+	PubkeyG2       *sigchecker.G2Point          `json:"pubkeyG2,omitempty"`
+}
+
+// snapshotOperatorSet captures the task's quorums with the stakes they were
+// measured against, and the APKs of the last refresh. Operators and quorums
+// are ordered so the same state always gives the same snapshot.
+func (a *Aggregator) snapshotOperatorSet(task *TaskInfo) *OperatorSetSnapshot {
+	quorums := a.taskQuorums(task)
+	snapshot := &OperatorSetSnapshot{
+		TaskIndex:        task.TaskIndex,
+		TaskCreatedBlock: task.TaskCreatedBlock,
+		TakenAt:          time.Now().UTC(),
+		ApkBlock:         a.apkTracker.RefreshedBlock(),
+		Quorums:          make([]SnapshotQuorum, 0, len(quorums)),
+	}
+
+	totals := make(map[types.QuorumNum]*big.Int, len(quorums))
+	for _, quorum := range quorums {
+		totals[quorum] = big.NewInt(0)
+	}
+
+	a.operators.mu.RLock()
+	snapshot.OperatorSetRefreshedAt = a.operators.refreshedAt
+	operators := a.operators.registered
+	if task.referenceOperators != nil {
+		operators = task.referenceOperators
+		snapshot.StakeBlock = task.TaskCreatedBlock
+	}
+	for _, operator := range operators {
+		stakes := make(map[types.QuorumNum]*big.Int)
+		for quorum, stake := range operator.StakePerQuorum {
+			total, ok := totals[quorum]
+			if !ok || stake == nil {
+				continue
+			}
+			total.Add(total, stake)
+			stakes[quorum] = new(big.Int).Set(stake)
+		}
+		if len(stakes) == 0 {
+			continue
+		}
+
+		entry := SnapshotOperator{
+			OperatorId:     hex.EncodeToString(operator.OperatorId[:]),
+			Address:        operator.Address,
+			StakePerQuorum: stakes,
+		}
+		if pubkeys, err := a.apkTracker.Pubkeys(operator.OperatorId); err == nil {
+			g1 := sigchecker.ToG1Point(pubkeys.G1Pubkey)
+			g2 := sigchecker.ToG2Point(pubkeys.G2Pubkey)
+			entry.PubkeyG1 = &g1
+			entry.PubkeyG2 = &g2
+		}
+		snapshot.Operators = append(snapshot.Operators, entry)
+	}
+	a.operators.mu.RUnlock()
+
+	sort.Slice(snapshot.Operators, func(i, j int) bool {
+		return snapshot.Operators[i].OperatorId < snapshot.Operators[j].OperatorId
+	})
+
+	for _, quorum := range quorums {
+		entry := SnapshotQuorum{
+			QuorumNumber:     quorum,
+			ThresholdPercent: a.quorumThreshold(task, quorum),
+			TotalStake:       totals[quorum],
+		}
+		if apk, ok := a.apkTracker.QuorumApk(quorum); ok {
+			point := sigchecker.ToG1Point(apk)
+			entry.Apk = &point
+		}
+		snapshot.Quorums = append(snapshot.Quorums, entry)
+	}
+	return snapshot
+}
+
+// quorumStakes indexes the snapshot's stakes by quorum
+func (s *OperatorSetSnapshot) quorumStakes() map[types.QuorumNum]*quorumStake {
+	stakes := make(map[types.QuorumNum]*quorumStake, len(s.Quorums))
+	for _, quorum := range s.Quorums {
+		stakes[quorum.QuorumNumber] = &quorumStake{
+			total:     quorum.TotalStake,
+			operators: make(map[types.OperatorId]*big.Int),
+		}
+	}
+	for _, operator := range s.Operators {
+		var operatorId types.OperatorId
+		decoded, err := hex.DecodeString(operator.OperatorId)
+		if err != nil || len(decoded) != len(operatorId) {
+			continue
+		}
+		copy(operatorId[:], decoded)
+		for quorum, stake := range operator.StakePerQuorum {
+			if quorumStake, ok := stakes[quorum]; ok {
+				quorumStake.operators[operatorId] = stake
+			}
+		}
+	}
+	return stakes
+}
+
+// GetOperatorSetSnapshot returns the operator set snapshot of a task in
+// memory or, failing that, in the archive
+func (a *Aggregator) GetOperatorSetSnapshot(taskIndex uint32) (*OperatorSetSnapshot, error) {
+	a.tasksMutex.RLock()
+	task, exists := a.tasks[taskIndex]
+	var snapshot *OperatorSetSnapshot
+	if exists {
+		snapshot = task.OperatorSetSnapshot
+	}
+	a.tasksMutex.RUnlock()
+
+	if !exists {
+		if a.taskStore == nil {
+			return nil, ErrUnknownTask
+		}
+		archived, err := a.taskStore.GetArchivedTask(taskIndex)
+		if err != nil {
+			return nil, err
+		}
+		if archived == nil {
+			return nil, ErrUnknownTask
+		}
+		snapshot = archived.OperatorSetSnapshot
+	}
+
+	if snapshot == nil {
+		return nil, ErrNoSnapshot
+	}
+	return snapshot, nil
+}
+
+// operatorSetSnapshotHandler serves GET /task/{taskIndex}/operator-set
+func (a *Aggregator) operatorSetSnapshotHandler(w http.ResponseWriter, r *http.Request) {
+	taskIndex, err := strconv.ParseUint(mux.Vars(r)["taskIndex"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid task index", http.StatusBadRequest)
+		return
+	}
+
+	snapshot, err := a.GetOperatorSetSnapshot(uint32(taskIndex))
+	switch {
+	case errors.Is(err, ErrUnknownTask):
+		http.Error(w, "Unknown task", http.StatusNotFound)
+		return
+	case errors.Is(err, ErrNoSnapshot):
+		http.Error(w, "Task has not been aggregated", http.StatusNotFound)
+		return
+	case err != nil:
+		a.logger.Error("Failed to read operator set snapshot", "taskIndex", taskIndex, "error", err)
+		http.Error(w, "Failed to read operator set snapshot", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(snapshot)
+}
@@ -0,0 +1,194 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// defaultStoreMaintenanceInterval is how often the task store is pruned
+	// when StoreMaintenanceInterval is unset
+	defaultStoreMaintenanceInterval = 6 * time.Hour
+	// storeUsageInterval is how often the disk usage metrics are refreshed
+	storeUsageInterval = time.Minute
+	// compactFreePercent is the share of the file that must be free pages
+	// before a maintenance run compacts it
+	compactFreePercent = 25
+)
+
+// storeMaintenance prunes the task store by its retention policy, compacts it
+// once enough of it is free space and exports its disk usage
+type storeMaintenance struct {
+	store    TaskStore
+	policy   RetentionPolicy
+	interval time.Duration
+	logger   logging.Logger
+
+	fileBytesGauge    prometheus.Gauge
+	freeBytesGauge    prometheus.Gauge
+	recordsGauge      *prometheus.GaugeVec
+	prunedCounter     *prometheus.CounterVec
+	compactionCounter prometheus.Counter
+}
+
+// newStoreMaintenance returns nil when there is no task store
+func newStoreMaintenance(config Config, store TaskStore, taskRetention time.Duration, reg prometheus.Registerer, logger logging.Logger) (*storeMaintenance, error) {
+	if store == nil {
+		return nil, nil
+	}
+
+	interval := defaultStoreMaintenanceInterval
+	if config.StoreMaintenanceInterval != "" {
+		parsed, err := time.ParseDuration(config.StoreMaintenanceInterval)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid store maintenance interval: %q", config.StoreMaintenanceInterval)
+		}
+		interval = parsed
+	}
+
+	var policy RetentionPolicy
+	for _, retention := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"archive retention", config.ArchiveRetention, &policy.ArchivedTasks},
+		{"deleted task retention", config.DeletedTaskRetention, &policy.DeletedTasks},
+		{"checkpoint retention", config.CheckpointRetention, &policy.Checkpoints},
+	} {
+		if retention.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(retention.value)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid %s: %q", retention.name, retention.value)
+		}
+		*retention.dest = parsed
+	}
+	// A checkpoint is only read back for a task still in memory, but pruning
+	// one that is would lose its responses on the next restart
+	if policy.Checkpoints > 0 && policy.Checkpoints <= taskRetention {
+		return nil, fmt.Errorf("checkpoint retention %s must exceed task retention %s", policy.Checkpoints, taskRetention)
+	}
+
+	m := &storeMaintenance{
+		store:    store,
+		policy:   policy,
+		interval: interval,
+		logger:   logger,
+		fileBytesGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "task_store_file_bytes",
+			Help:      "Size of the task store file",
+		}),
+		freeBytesGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "task_store_free_bytes",
+			Help:      "Bytes of the task store file held by free pages a compaction would reclaim",
+		}),
+		recordsGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "task_store_records",
+			Help:      "Records in each task store table",
+		}, []string{"table"}),
+		prunedCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "task_store_pruned_total",
+			Help:      "Records removed from the task store by its retention policy",
+		}, []string{"table"}),
+		compactionCounter: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "task_store_compactions_total",
+			Help:      "Task store compactions",
+		}),
+	}
+	reg.MustRegister(m.fileBytesGauge, m.freeBytesGauge, m.recordsGauge, m.prunedCounter, m.compactionCounter)
+	return m, nil
+}
+
+// run refreshes the usage metrics every storeUsageInterval and maintains the
+// store every interval, starting with a run at startup
+func (m *storeMaintenance) run(ctx context.Context) {
+	m.maintain(time.Now().UTC())
+
+	usageTicker := time.NewTicker(storeUsageInterval)
+	defer usageTicker.Stop()
+	maintenanceTicker := time.NewTicker(m.interval)
+	defer maintenanceTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-usageTicker.C:
+			m.refreshUsage()
+		case <-maintenanceTicker.C:
+			m.maintain(time.Now().UTC())
+		}
+	}
+}
+
+// maintain prunes the store and compacts it when pruning has left enough of
+// the file free
+func (m *storeMaintenance) maintain(now time.Time) {
+	pruned, err := m.store.Prune(m.policy, now)
+	if err != nil {
+		m.logger.Error("Failed to prune task store", "error", err)
+	} else {
+		m.prunedCounter.WithLabelValues("archived_tasks").Add(float64(pruned.ArchivedTasks))
+		m.prunedCounter.WithLabelValues("deleted_tasks").Add(float64(pruned.DeletedTasks))
+		m.prunedCounter.WithLabelValues("checkpoints").Add(float64(pruned.Checkpoints))
+		if pruned != (PruneResult{}) {
+			m.logger.Info("Pruned task store",
+				"archivedTasks", pruned.ArchivedTasks,
+				"deletedTasks", pruned.DeletedTasks,
+				"checkpoints", pruned.Checkpoints,
+			)
+		}
+	}
+
+	usage := m.refreshUsage()
+	if usage == nil || usage.FileBytes == 0 || usage.FreeBytes*100 < usage.FileBytes*compactFreePercent {
+		return
+	}
+
+	started := time.Now()
+	if err := m.store.Compact(); err != nil {
+		m.logger.Error("Failed to compact task store", "error", err)
+		return
+	}
+	m.compactionCounter.Inc()
+	after := m.refreshUsage()
+	if after != nil {
+		m.logger.Info("Compacted task store",
+			"bytesBefore", usage.FileBytes,
+			"bytesAfter", after.FileBytes,
+			"duration", time.Since(started),
+		)
+	}
+}
+
+// refreshUsage updates the usage metrics, returning nil when the usage can't
+// be read
+func (m *storeMaintenance) refreshUsage() *StoreUsage {
+	usage, err := m.store.Usage()
+	if err != nil {
+		m.logger.Warn("Failed to read task store usage", "error", err)
+		return nil
+	}
+	m.fileBytesGauge.Set(float64(usage.FileBytes))
+	m.freeBytesGauge.Set(float64(usage.FreeBytes))
+	for table, records := range usage.Records {
+		m.recordsGauge.WithLabelValues(table).Set(float64(records))
+	}
+	return &usage
+}
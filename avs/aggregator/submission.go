@@ -0,0 +1,220 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+	"github.com/eigenlvr/avs/pkg/tss"
+	"github.com/eigenlvr/avs/pkg/txbump"
+)
+
+// submissionTimeout bounds sending a task's aggregate and waiting for it to be mined
+const submissionTimeout = 5 * time.Minute
+
+// ErrNoSubmissionSender is returned when a submission is attempted without a signing key
+var ErrNoSubmissionSender = errors.New("no submission sender configured")
+
+// newSubmissionTxConfig builds the stuck transaction settings from the config
+func newSubmissionTxConfig(config Config) (txbump.Config, error) {
+	txConfig := txbump.Config{
+		BumpPercent: config.SubmissionGasBumpPercent,
+		MaxBumps:    config.SubmissionMaxGasBumps,
+	}
+
+	if config.SubmissionStuckAfter != "" {
+		stuckAfter, err := time.ParseDuration(config.SubmissionStuckAfter)
+		if err != nil {
+			return txConfig, fmt.Errorf("invalid submission stuck after: %w", err)
+		}
+		txConfig.StuckAfter = stuckAfter
+	}
+
+	if config.SubmissionMaxGasPriceGwei > 0 {
+		txConfig.MaxGasPrice = new(big.Int).Mul(new(big.Int).SetUint64(config.SubmissionMaxGasPriceGwei), big.NewInt(params.GWei))
+	}
+
+	return txConfig, nil
+}
+
+// newSubmissionSender returns a sender signing with the threshold key when
+// signing parties are configured, else with the aggregator key, or nil
+// without either
+func newSubmissionSender(config Config, client eth.Client, chainId *big.Int, signer remotesigner.Signer, txConfig txbump.Config, logger logging.Logger) (*txbump.Sender, error) {
+	if len(config.ThresholdSignerParties) > 0 {
+		return newThresholdSubmissionSender(config, client, chainId, txConfig, logger)
+	}
+	if signer == nil {
+		return nil, nil
+	}
+
+	logger.Info("Submissions are signed by the aggregator key", "address", signer.Address().Hex())
+	// Signing is bounded by the signer's own request timeout
+	return txbump.NewSender(client, signer.Address(), remotesigner.SignerFn(context.Background(), signer, chainId), txConfig, logger), nil
+}
+
+// newThresholdSubmissionSender returns a sender signing with the threshold key
+func newThresholdSubmissionSender(config Config, client eth.Client, chainId *big.Int, txConfig txbump.Config, logger logging.Logger) (*txbump.Sender, error) {
+	if !common.IsHexAddress(config.ThresholdSignerAddress) {
+		return nil, fmt.Errorf("invalid threshold signer address: %q", config.ThresholdSignerAddress)
+	}
+
+	tssConfig := tss.Config{
+		Parties:       config.ThresholdSignerParties,
+		Threshold:     config.ThresholdSignerThreshold,
+		Address:       common.HexToAddress(config.ThresholdSignerAddress),
+		Authorization: config.ThresholdSignerAuthorization,
+	}
+	if config.ThresholdSignerTimeout != "" {
+		timeout, err := time.ParseDuration(config.ThresholdSignerTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold signer timeout: %w", err)
+		}
+		tssConfig.Timeout = timeout
+	}
+	signer, err := tss.NewSigner(tssConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid threshold signer: %w", err)
+	}
+
+	logger.Info("Submissions are signed by threshold ECDSA",
+		"address", signer.Address().Hex(),
+		"parties", len(tssConfig.Parties),
+		"threshold", tssConfig.Threshold,
+	)
+	return txbump.NewSender(client, signer.Address(), signer.SignerFn(chainId), txConfig, logger), nil
+}
+
+// submitTask sends the task's aggregated response to the service manager
+// with respondToAuctionTask, as a user operation when a bundler is
+// configured, and waits for it to be mined. Watch-only
+// aggregators never submit, and tasks whose parameters were never seen in a
+// task event can't be. A task whose calldata can't be built is reopened to be
+// aggregated again.
+func (a *Aggregator) submitTask(task *TaskInfo) {
+	if a.config.WatchOnly {
+		return
+	}
+
+	a.tasksMutex.RLock()
+	auctionTask := task.auctionTask
+	aggregated := task.AggregatedResponse
+	responseDigest := task.AggregatedDigest
+	nonSignerStakesAndSignature := task.nonSignerStakesAndSignature
+	mined := task.SubmissionBlockNumber != nil
+	a.tasksMutex.RUnlock()
+	if mined || aggregated == nil || nonSignerStakesAndSignature == nil {
+		return
+	}
+	if auctionTask == nil {
+		a.logger.Warn("Task parameters unknown, not submitting its aggregate", "taskIndex", task.TaskIndex)
+		return
+	}
+
+	response := servicemanager.AuctionTaskResponse{
+		ReferenceTaskIndex: aggregated.ReferenceTaskIndex,
+		Winner:             aggregated.Winner,
+		WinningBid:         bidOrZero(aggregated.WinningBid),
+		TotalBids:          new(big.Int).SetUint64(uint64(aggregated.TotalBids)),
+	}
+	calldata, err := servicemanager.PackRespondToAuctionTask(*auctionTask, response, *nonSignerStakesAndSignature)
+	if err != nil {
+		a.logger.Error("Failed to build submission calldata", "taskIndex", task.TaskIndex, "error", err)
+		a.reopenTask(task, responseDigest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), submissionTimeout)
+	defer cancel()
+
+	// The smart account submits instead of the aggregator's own key when a
+	// bundler is configured
+	if a.userOpSender != nil {
+		if _, err := a.sendUserOpSubmission(ctx, task, calldata); err != nil {
+			a.logger.Error("Failed to submit task aggregate through bundler", "taskIndex", task.TaskIndex, "error", err)
+		}
+		return
+	}
+
+	receipt, err := a.sendSubmission(ctx, task, calldata)
+	if err != nil {
+		a.logger.Error("Failed to submit task aggregate", "taskIndex", task.TaskIndex, "error", err)
+		return
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		a.logger.Error("Task aggregate submission reverted", "taskIndex", task.TaskIndex, "txHash", receipt.TxHash.Hex())
+		return
+	}
+	a.logger.Info("Submitted task aggregate",
+		"taskIndex", task.TaskIndex,
+		"txHash", receipt.TxHash.Hex(),
+		"blockNumber", receipt.BlockNumber,
+	)
+}
+
+// sendSubmission signs the respondToAuctionTask calldata, broadcasts it and
+// waits for it to be mined, replacing it with bumped fees whenever it stays
+// pending for longer than SubmissionStuckAfter. The task records the latest
+// broadcast hash. Nothing is sent unless the winner's bid is still escrowed.
+func (a *Aggregator) sendSubmission(ctx context.Context, task *TaskInfo, calldata []byte) (*gethtypes.Receipt, error) {
+	if a.config.WatchOnly {
+		return nil, ErrWatchOnly
+	}
+	if a.submissionSender == nil {
+		return nil, ErrNoSubmissionSender
+	}
+	if err := a.checkSettlementEscrow(ctx, task); err != nil {
+		return nil, err
+	}
+
+	serviceManager := common.HexToAddress(a.config.ServiceManagerAddress)
+	tx, err := servicemanager.RespondToAuctionTask(a.submissionSender.TransactOpts(ctx), a.ethClient, serviceManager, calldata)
+	if err != nil {
+		a.metrics.submitted(task.PoolId, submissionResultFailed)
+		return nil, fmt.Errorf("failed to sign submission of task %d: %w", task.TaskIndex, err)
+	}
+
+	var sentAt time.Time
+	receipt, err := a.submissionSender.Send(ctx, tx, func(sent *gethtypes.Transaction) {
+		if sentAt.IsZero() {
+			sentAt = time.Now()
+		}
+		a.metrics.submissionSent(task.PoolId)
+		txHash := sent.Hash()
+		a.tasksMutex.Lock()
+		task.SubmissionTxHash = &txHash
+		task.revision++
+		a.tasksMutex.Unlock()
+	})
+	if err != nil {
+		a.metrics.submitted(task.PoolId, submissionResultFailed)
+		return nil, fmt.Errorf("failed to submit task %d: %w", task.TaskIndex, err)
+	}
+	a.metrics.submissionMined(task.PoolId, sentAt)
+	if receipt.Status == gethtypes.ReceiptStatusSuccessful {
+		a.metrics.submitted(task.PoolId, submissionResultSubmitted)
+	} else {
+		a.metrics.submitted(task.PoolId, submissionResultReverted)
+	}
+
+	// The mined transaction may be an earlier version than the last one sent
+	blockNumber := receipt.BlockNumber.Uint64()
+	a.tasksMutex.Lock()
+	task.SubmissionTxHash = &receipt.TxHash
+	task.SubmissionBlockNumber = &blockNumber
+	task.revision++
+	a.publishSubmitted(task)
+	a.tasksMutex.Unlock()
+
+	return receipt, nil
+}
@@ -0,0 +1,318 @@
+package aggregator
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/blsaggregation"
+	"github.com/eigenlvr/avs/pkg/poolmetrics"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+	"github.com/eigenlvr/avs/pkg/sigchecker"
+	"github.com/eigenlvr/avs/pkg/testutils/fakeeth"
+	"github.com/eigenlvr/avs/pkg/testutils/fixtures"
+	"github.com/eigenlvr/avs/pkg/tss"
+	"github.com/eigenlvr/avs/pkg/txbump"
+)
+
+const testChainId = 31337
+
+var testServiceManager = common.HexToAddress("0x5ea1ed0000000000000000000000000000000001")
+
+// newTestAggregator returns an aggregator submitting to a fake chain with the
+// aggregator key of fixture operator 0
+func newTestAggregator(t *testing.T) (*Aggregator, *fakeeth.Client) {
+	t.Helper()
+
+	client := fakeeth.New(testChainId)
+	client.SetCode(testServiceManager, []byte{0x01})
+
+	logger := logging.NewNoopLogger()
+	reg := prometheus.NewRegistry()
+	labeler, err := poolmetrics.NewLabeler(nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenges, err := newChallengeTracker(Config{}, client, reg, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &Aggregator{
+		config:           Config{ServiceManagerAddress: testServiceManager.Hex()},
+		logger:           logger,
+		ethClient:        client,
+		metricsReg:       reg,
+		challenges:       challenges,
+		submissionSender: newTestSubmissionSender(client, txbump.Config{PollInterval: 10 * time.Millisecond}),
+		eventHub:         newEventHub(reg),
+		metrics:          newTaskMetrics(labeler, reg),
+		tasks:            make(map[uint32]*TaskInfo),
+	}
+	return a, client
+}
+
+// newTestSubmissionSender returns a sender signing with the aggregator key of
+// fixture operator 0
+func newTestSubmissionSender(client *fakeeth.Client, txConfig txbump.Config) *txbump.Sender {
+	signer := remotesigner.NewLocal(fixtures.New("aggregator").Operator(0).EcdsaKey)
+	return txbump.NewSender(client, signer.Address(), remotesigner.SignerFn(context.Background(), signer, big.NewInt(testChainId)), txConfig, logging.NewNoopLogger())
+}
+
+// newTestTask returns a task seen in its creation event, with one response
+// over the given digest
+func newTestTask(taskIndex uint32, response TaskResponse, responseDigest common.Hash) *TaskInfo {
+	operatorId := types.OperatorId{1}
+	return &TaskInfo{
+		TaskIndex:         taskIndex,
+		TaskCreatedBlock:  1,
+		CreatedAt:         time.Now(),
+		TaskResponses:     map[types.OperatorId]TaskResponse{operatorId: response},
+		TaskResponsesInfo: map[types.OperatorId]TaskResponseInfo{operatorId: {TaskResponse: response, OperatorId: operatorId, Digest: responseDigest}},
+		auctionTask: &servicemanager.AuctionTask{
+			BlockNumber:               big.NewInt(1),
+			TaskCreatedBlock:          big.NewInt(1),
+			QuorumNumbers:             []byte{0},
+			QuorumThresholdPercentage: fixtures.DefaultQuorumThresholdPercentage,
+		},
+	}
+}
+
+// emptyNonSignerStakesAndSignature returns a signature with no non-signers
+// that packs into calldata
+func emptyNonSignerStakesAndSignature() sigchecker.NonSignerStakesAndSignature {
+	zero := func() *big.Int { return new(big.Int) }
+	return sigchecker.NonSignerStakesAndSignature{
+		QuorumApks: []sigchecker.G1Point{{X: zero(), Y: zero()}},
+		ApkG2:      sigchecker.G2Point{X: [2]*big.Int{zero(), zero()}, Y: [2]*big.Int{zero(), zero()}},
+		Sigma:      sigchecker.G1Point{X: zero(), Y: zero()},
+	}
+}
+
+// mineSubmission mines the task's submission once it is sent and waits for
+// the aggregator to see it mined
+func mineSubmission(t *testing.T, a *Aggregator, client *fakeeth.Client, task *TaskInfo) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(client.Pending()) > 0 {
+			client.MineBlock()
+		}
+		a.tasksMutex.RLock()
+		mined := task.SubmissionBlockNumber != nil
+		a.tasksMutex.RUnlock()
+		if mined {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("task submission was not mined")
+}
+
+func TestQuorumReachedSubmitsRespondToAuctionTask(t *testing.T) {
+	a, client := newTestAggregator(t)
+
+	response := TaskResponse{ReferenceTaskIndex: 7, Winner: common.HexToAddress("0xb1d"), WinningBid: big.NewInt(5), TotalBids: 2}
+	responseDigest := common.HexToHash("0x01")
+	task := newTestTask(7, response, responseDigest)
+	a.tasks[7] = task
+
+	a.recordBlsAggregation(blsaggregation.Result{
+		TaskIndex:                   7,
+		TaskResponse:                response,
+		Digest:                      responseDigest,
+		Signers:                     []types.OperatorId{{1}},
+		NonSignerStakesAndSignature: emptyNonSignerStakesAndSignature(),
+	})
+	mineSubmission(t, a, client, task)
+
+	sent := client.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d transactions, want 1", len(sent))
+	}
+	tx := sent[0]
+	if tx.To() == nil || *tx.To() != testServiceManager {
+		t.Fatalf("submission sent to %v, want the service manager", tx.To())
+	}
+	if !bytes.Equal(tx.Data()[:4], servicemanager.ABI.Methods["respondToAuctionTask"].ID) {
+		t.Fatalf("submission calls %x, want respondToAuctionTask", tx.Data()[:4])
+	}
+	_, submitted, _, err := servicemanager.UnpackRespondToAuctionTask(tx.Data())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if submitted.ReferenceTaskIndex != 7 || submitted.Winner != response.Winner || submitted.WinningBid.Cmp(response.WinningBid) != 0 || submitted.TotalBids.Uint64() != 2 {
+		t.Fatalf("submitted response %+v, want %+v", submitted, response)
+	}
+}
+
+func TestWatchOnlyAggregatorDoesNotSubmit(t *testing.T) {
+	a, client := newTestAggregator(t)
+	a.config.WatchOnly = true
+
+	response := TaskResponse{ReferenceTaskIndex: 3, WinningBid: big.NewInt(0)}
+	task := newTestTask(3, response, common.HexToHash("0x03"))
+	nonSignerStakesAndSignature := emptyNonSignerStakesAndSignature()
+	task.AggregatedResponse = &response
+	task.nonSignerStakesAndSignature = &nonSignerStakesAndSignature
+
+	a.submitTask(task)
+	if sent := client.Sent(); len(sent) != 0 {
+		t.Fatalf("watch-only aggregator sent %d transactions", len(sent))
+	}
+}
+
+func TestStuckSubmissionIsReplaced(t *testing.T) {
+	a, client := newTestAggregator(t)
+	a.submissionSender = newTestSubmissionSender(client, txbump.Config{
+		StuckAfter:   20 * time.Millisecond,
+		MaxBumps:     3,
+		PollInterval: 5 * time.Millisecond,
+	})
+
+	response := TaskResponse{ReferenceTaskIndex: 5, WinningBid: big.NewInt(0)}
+	task := newTestTask(5, response, common.HexToHash("0x05"))
+	nonSignerStakesAndSignature := emptyNonSignerStakesAndSignature()
+	task.AggregatedResponse = &response
+	task.nonSignerStakesAndSignature = &nonSignerStakesAndSignature
+	a.tasks[5] = task
+
+	go a.submitTask(task)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(client.Pending()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("stuck submission was not replaced")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	pending := client.Pending()
+	original, replacement := pending[0], pending[1]
+	if replacement.Nonce() != original.Nonce() || replacement.GasFeeCap().Cmp(original.GasFeeCap()) <= 0 {
+		t.Fatalf("replacement nonce %d fee cap %s doesn't replace nonce %d fee cap %s",
+			replacement.Nonce(), replacement.GasFeeCap(), original.Nonce(), original.GasFeeCap())
+	}
+	a.tasksMutex.RLock()
+	tracked := task.SubmissionTxHash
+	a.tasksMutex.RUnlock()
+	if tracked == nil || *tracked == original.Hash() {
+		t.Fatalf("task tracks submission %v, want a replacement of %s", tracked, original.Hash().Hex())
+	}
+
+	mineSubmission(t, a, client, task)
+	a.tasksMutex.RLock()
+	defer a.tasksMutex.RUnlock()
+	if *task.SubmissionTxHash != original.Hash() && *task.SubmissionTxHash != replacement.Hash() {
+		t.Fatalf("task records submission %s, which was never sent", task.SubmissionTxHash.Hex())
+	}
+}
+
+// fakeSigningParty signs every session with the whole shared key, standing in
+// for the parties completing the protocol, and records what it was asked to sign
+type fakeSigningParty struct {
+	key *ecdsa.PrivateKey
+
+	mu           sync.Mutex
+	transactions []tss.Transaction
+}
+
+func (p *fakeSigningParty) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Digest      common.Hash      `json:"digest"`
+		Transaction *tss.Transaction `json:"transaction"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Transaction == nil {
+		http.Error(w, "bad signing request", http.StatusBadRequest)
+		return
+	}
+	signature, err := crypto.Sign(request.Digest[:], p.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	p.mu.Lock()
+	p.transactions = append(p.transactions, *request.Transaction)
+	p.mu.Unlock()
+	_ = json.NewEncoder(w).Encode(map[string]hexutil.Bytes{"signature": signature})
+}
+
+func TestThresholdSignerSignsTaskSubmissions(t *testing.T) {
+	a, client := newTestAggregator(t)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	party := &fakeSigningParty{key: key}
+	parties := make([]string, 2)
+	for i := range parties {
+		server := httptest.NewServer(party)
+		defer server.Close()
+		parties[i] = server.URL
+	}
+	sharedKey := crypto.PubkeyToAddress(key.PublicKey)
+
+	config := Config{
+		ServiceManagerAddress:    testServiceManager.Hex(),
+		ThresholdSignerParties:   parties,
+		ThresholdSignerThreshold: 2,
+		ThresholdSignerAddress:   sharedKey.Hex(),
+	}
+	// The aggregator key must not be used once signing parties are configured
+	aggregatorKey := remotesigner.NewLocal(fixtures.New("aggregator").Operator(0).EcdsaKey)
+	a.submissionSender, err = newSubmissionSender(config, client, big.NewInt(testChainId), aggregatorKey, txbump.Config{PollInterval: 10 * time.Millisecond}, a.logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := TaskResponse{ReferenceTaskIndex: 6, WinningBid: big.NewInt(0)}
+	task := newTestTask(6, response, common.HexToHash("0x06"))
+	nonSignerStakesAndSignature := emptyNonSignerStakesAndSignature()
+	task.AggregatedResponse = &response
+	task.nonSignerStakesAndSignature = &nonSignerStakesAndSignature
+	a.tasks[6] = task
+
+	go a.submitTask(task)
+	mineSubmission(t, a, client, task)
+
+	sent := client.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d transactions, want 1", len(sent))
+	}
+	from, err := gethtypes.Sender(gethtypes.LatestSignerForChainID(big.NewInt(testChainId)), sent[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != sharedKey {
+		t.Fatalf("submission signed by %s, want the shared key %s", from.Hex(), sharedKey.Hex())
+	}
+
+	party.mu.Lock()
+	defer party.mu.Unlock()
+	if len(party.transactions) == 0 {
+		t.Fatal("signing parties were never asked to sign")
+	}
+	for _, tx := range party.transactions {
+		if !bytes.Equal(tx.Data[:4], servicemanager.ABI.Methods["respondToAuctionTask"].ID) {
+			t.Fatalf("parties were asked to sign %x, want respondToAuctionTask", tx.Data[:4])
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/logwatcher"
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+	"github.com/eigenlvr/avs/pkg/wsproto"
+)
+
+// newTaskWatcher follows the service manager's NewAuctionTaskCreated events.
+// It returns nil without a service manager, in which case tasks are opened
+// on their first response.
+func newTaskWatcher(config Config, client eth.Client, reg prometheus.Registerer, logger logging.Logger) (*logwatcher.Watcher, error) {
+	if config.ServiceManagerAddress == "" {
+		return nil, nil
+	}
+
+	var pollInterval time.Duration
+	if config.TaskPollInterval != "" {
+		var err error
+		pollInterval, err = time.ParseDuration(config.TaskPollInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid task poll interval: %w", err)
+		}
+	}
+
+	return logwatcher.NewWatcher(
+		logwatcher.Config{
+			Name:         "tasks",
+			Addresses:    []common.Address{common.HexToAddress(config.ServiceManagerAddress)},
+			Topics:       [][]common.Hash{{servicemanager.NewAuctionTaskCreatedTopic}},
+			WsUrl:        config.EthWsUrl,
+			PollInterval: pollInterval,
+		},
+		client,
+		logwatcher.NewFileCheckpoint(config.TaskCheckpointPath),
+		reg,
+		logger,
+	), nil
+}
+
+// listenForNewTasks opens a task for every NewAuctionTaskCreated event. The
+// watcher subscribes over EthWsUrl, resubscribing with backoff, and polls
+// from its checkpoint while the websocket is down.
+func (a *Aggregator) listenForNewTasks(ctx context.Context) {
+	if a.taskWatcher == nil {
+		a.logger.Info("No service manager configured, tasks are opened on their first response")
+		return
+	}
+	a.logger.Info("Starting to listen for new tasks")
+	a.syncLatestTaskNum(ctx)
+
+	err := a.taskWatcher.Run(ctx, func(log gethtypes.Log) error {
+		a.handleNewTaskLog(log)
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		a.logger.Error("Task watcher stopped", "error", err)
+	}
+}
+
+// handleNewTaskLog opens the task of a NewAuctionTaskCreated log, or fills in
+// a task already opened by an early response, and pushes it to the operators
+// connected over WebSocket. Malformed logs are skipped rather than stopping
+// the watcher.
+func (a *Aggregator) handleNewTaskLog(log gethtypes.Log) {
+	event, err := servicemanager.ParseNewAuctionTaskCreated(log)
+	if err != nil {
+		a.logger.Warn("Failed to decode task event", "txHash", log.TxHash.Hex(), "error", err)
+		return
+	}
+	taskHash, err := servicemanager.HashAuctionTask(event.Task)
+	if err != nil {
+		a.logger.Warn("Failed to hash task", "taskIndex", event.TaskIndex, "error", err)
+		return
+	}
+	if a.taskSync != nil {
+		a.taskSync.seed(event.TaskIndex + 1)
+	}
+
+	quorumNumbers := make(types.QuorumNums, len(event.Task.QuorumNumbers))
+	for i, quorum := range event.Task.QuorumNumbers {
+		quorumNumbers[i] = types.QuorumNum(quorum)
+	}
+	taskCreatedBlock := uint32(event.Task.TaskCreatedBlock.Uint64())
+
+	a.tasksMutex.Lock()
+	task, exists := a.tasks[event.TaskIndex]
+	if !exists {
+		if a.openTaskCount() >= a.maxOpenTasks() {
+			a.tasksMutex.Unlock()
+			a.logger.Warn("Too many open tasks, not opening task from event", "taskIndex", event.TaskIndex)
+			return
+		}
+		task = &TaskInfo{
+			TaskIndex:         event.TaskIndex,
+			TaskResponses:     make(map[types.OperatorId]TaskResponse),
+			TaskResponsesInfo: make(map[types.OperatorId]TaskResponseInfo),
+			CreatedAt:         time.Now(),
+		}
+		a.tasks[event.TaskIndex] = task
+	}
+	task.TaskHash = taskHash
+	task.PoolId = common.Hash(event.Task.PoolId)
+	task.TaskCreatedBlock = taskCreatedBlock
+	task.QuorumNumbers = quorumNumbers
+	task.QuorumThresholdPercentage = types.QuorumThresholdPercentage(event.Task.QuorumThresholdPercentage)
+	task.auctionTask = &event.Task
+	if !exists {
+		a.metrics.taskOpened(task.PoolId)
+	}
+	a.tasksMutex.Unlock()
+
+	a.logger.Info("New task created",
+		"taskIndex", event.TaskIndex,
+		"poolId", common.Hash(event.Task.PoolId).Hex(),
+		"taskCreatedBlock", taskCreatedBlock,
+		"openedEarly", exists,
+	)
+	a.publishTaskEvent(TaskEvent{
+		Type:             TaskEventCreated,
+		TaskIndex:        event.TaskIndex,
+		PoolId:           common.Hash(event.Task.PoolId),
+		TaskCreatedBlock: taskCreatedBlock,
+	})
+
+	err = a.BroadcastTask(wsproto.Task{
+		TaskIndex:                 event.TaskIndex,
+		PoolId:                    common.Hash(event.Task.PoolId),
+		BlockNumber:               uint32(event.Task.BlockNumber.Uint64()),
+		TaskCreatedBlock:          taskCreatedBlock,
+		QuorumNumbers:             event.Task.QuorumNumbers,
+		QuorumThresholdPercentage: event.Task.QuorumThresholdPercentage,
+	})
+	if err != nil {
+		a.logger.Warn("Failed to push task to operators", "taskIndex", event.TaskIndex, "error", err)
+	}
+}
+
+// syncLatestTaskNum refreshes the task numbering from the service manager
+func (a *Aggregator) syncLatestTaskNum(ctx context.Context) {
+	if a.taskSync == nil {
+		return
+	}
+
+	latestTaskNum, err := a.taskSync.refresh(ctx)
+	if err != nil {
+		a.logger.Warn("Failed to sync task numbering", "error", err)
+		return
+	}
+	a.logger.Debug("Synced task numbering", "latestTaskNum", latestTaskNum)
+}
@@ -0,0 +1,136 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// Page sizes for GET /tasks
+	defaultTasksLimit = 50
+	maxTasksLimit     = 500
+)
+
+// TaskFilter selects tasks in memory, newest first
+type TaskFilter struct {
+	// Status is processing, completed or expired; empty matches every task
+	Status string
+	// PoolId, when set, only matches the pool's tasks
+	PoolId *common.Hash
+	// Page counts from 1, each holding Limit tasks
+	Page  int
+	Limit int
+}
+
+// TaskPage is one page of tasks and the number of tasks the filter matched
+type TaskPage struct {
+	Tasks []TaskStatus `json:"tasks"`
+	Page  int          `json:"page"`
+	Limit int          `json:"limit"`
+	Total int          `json:"total"`
+}
+
+// ListTasks returns the page of tasks in memory the filter selects, newest
+// first. Tasks that have left memory are served by the task history.
+func (a *Aggregator) ListTasks(filter TaskFilter) TaskPage {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultTasksLimit
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	a.tasksMutex.RLock()
+	defer a.tasksMutex.RUnlock()
+
+	var matched []*TaskInfo
+	for _, task := range a.tasks {
+		if filter.PoolId != nil && task.PoolId != *filter.PoolId {
+			continue
+		}
+		if filter.Status != "" && taskStatusOf(task) != filter.Status {
+			continue
+		}
+		matched = append(matched, task)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].TaskIndex > matched[j].TaskIndex
+	})
+
+	page := TaskPage{
+		Tasks: []TaskStatus{},
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: len(matched),
+	}
+	start := (filter.Page - 1) * filter.Limit
+	if start >= len(matched) {
+		return page
+	}
+	end := min(start+filter.Limit, len(matched))
+	for _, task := range matched[start:end] {
+		page.Tasks = append(page.Tasks, a.taskStatus(task))
+	}
+	return page
+}
+
+// taskStatusOf returns the task's status name. Callers must hold the tasks lock.
+func taskStatusOf(task *TaskInfo) string {
+	switch {
+	case task.IsCompleted:
+		return taskStatusCompleted
+	case task.ExpiredAt != nil:
+		return taskStatusExpired
+	default:
+		return taskStatusProcessing
+	}
+}
+
+// tasksHandler serves GET /tasks, the tasks in memory filtered by status and
+// pool, newest first. Pass page to fetch the pages after the first.
+func (a *Aggregator) tasksHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := TaskFilter{Limit: defaultTasksLimit, Page: 1}
+
+	switch status := query.Get("status"); status {
+	case "", taskStatusProcessing, taskStatusCompleted, taskStatusExpired:
+		filter.Status = status
+	default:
+		http.Error(w, "Invalid status", http.StatusBadRequest)
+		return
+	}
+
+	if pool := query.Get("pool"); pool != "" {
+		var poolId common.Hash
+		if err := poolId.UnmarshalText([]byte(pool)); err != nil {
+			http.Error(w, "Invalid pool id", http.StatusBadRequest)
+			return
+		}
+		filter.PoolId = &poolId
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = min(parsed, maxTasksLimit)
+	}
+
+	if page := query.Get("page"); page != "" {
+		parsed, err := strconv.Atoi(page)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "Invalid page", http.StatusBadRequest)
+			return
+		}
+		filter.Page = parsed
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(a.ListTasks(filter))
+}
@@ -0,0 +1,248 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+)
+
+// Placeholders in the explorer URL templates
+const (
+	explorerHashPlaceholder   = "{hash}"
+	explorerNumberPlaceholder = "{number}"
+)
+
+// Task statuses
+const (
+	taskStatusProcessing = "processing"
+	taskStatusCompleted  = "completed"
+	taskStatusExpired    = "expired"
+)
+
+// TaskStatus is a task's outcome with the transactions and blocks behind it,
+// linked to the configured block explorer so UIs needn't look them up
+type TaskStatus struct {
+	TaskIndex        uint32      `json:"taskIndex"`
+	Status           string      `json:"status"`
+	PoolId           common.Hash `json:"poolId"`
+	CreatedAt        time.Time   `json:"createdAt"`
+	TaskCreatedBlock BlockLink   `json:"taskCreatedBlock"`
+	// ResponseDeadline is the last block an aggregate is accepted in
+	ResponseDeadline *BlockLink `json:"responseDeadline,omitempty"`
+	Responses        int        `json:"responses"`
+	// Digests counts the responses over each distinct digest
+	Digests            []DigestCount `json:"digests"`
+	AggregatedResponse *TaskResponse `json:"aggregatedResponse,omitempty"`
+	AggregatedDigest   *common.Hash  `json:"aggregatedDigest,omitempty"`
+	Signers            int           `json:"signers"`
+	// Submission is the respondToAuctionTask transaction carrying the aggregate
+	Submission      *TransactionLink `json:"submission,omitempty"`
+	ResultBundleCid *string          `json:"resultBundleCid,omitempty"`
+	ResultDataTx    *ResultDataTx    `json:"resultDataTx,omitempty"`
+	// PrimarySubmission is what the primary aggregator submitted, when watch-only
+	PrimarySubmission *PrimarySubmission `json:"primarySubmission,omitempty"`
+	// Challenges raised against the aggregate, and until when the API takes them
+	ChallengeDeadline *time.Time  `json:"challengeDeadline,omitempty"`
+	Challenges        []Challenge `json:"challenges,omitempty"`
+	// Outliers are the responses over another digest than the aggregate
+	Outliers []Outlier `json:"outliers,omitempty"`
+	// ExpiredAt is set when the response window closed without an aggregate,
+	// and Expiry is the transaction recording it on chain
+	ExpiredAt *time.Time       `json:"expiredAt,omitempty"`
+	Expiry    *TransactionLink `json:"expiry,omitempty"`
+	// Archived is set when the status was read from the task store
+	Archived bool `json:"archived"`
+}
+
+// DigestCount is how many responses were over one digest
+type DigestCount struct {
+	Digest    common.Hash `json:"digest"`
+	Responses int         `json:"responses"`
+}
+
+// BlockLink is a block number and its explorer page
+type BlockLink struct {
+	Number uint64 `json:"number"`
+	Url    string `json:"url,omitempty"`
+}
+
+// TransactionLink is a transaction, the block it was mined in once known, and
+// their explorer pages
+type TransactionLink struct {
+	TxHash common.Hash `json:"txHash"`
+	TxUrl  string      `json:"txUrl,omitempty"`
+	Block  *BlockLink  `json:"block,omitempty"`
+}
+
+// GetTaskDetails looks the task up in memory, then in the task store. It
+// returns ErrUnknownTask if the task is in neither.
+func (a *Aggregator) GetTaskDetails(taskIndex uint32) (TaskStatus, error) {
+	a.tasksMutex.RLock()
+	task, exists := a.tasks[taskIndex]
+	var status TaskStatus
+	if exists {
+		status = a.taskStatus(task)
+	}
+	a.tasksMutex.RUnlock()
+
+	if exists {
+		return status, nil
+	}
+	if a.taskStore == nil {
+		return TaskStatus{}, ErrUnknownTask
+	}
+
+	archived, err := a.taskStore.GetArchivedTask(taskIndex)
+	if err != nil {
+		return TaskStatus{}, err
+	}
+	if archived == nil {
+		return TaskStatus{}, ErrUnknownTask
+	}
+
+	digests := make(map[common.Hash]int)
+	for _, response := range archived.Responses {
+		digests[response.Digest]++
+	}
+
+	status = TaskStatus{
+		TaskIndex:          archived.TaskIndex,
+		Status:             taskStatusProcessing,
+		PoolId:             archived.PoolId,
+		CreatedAt:          archived.CreatedAt,
+		TaskCreatedBlock:   a.blockLink(uint64(archived.TaskCreatedBlock)),
+		ResponseDeadline:   a.responseDeadlineLink(archived.TaskCreatedBlock),
+		Responses:          len(archived.Responses),
+		Digests:            newDigestCounts(digests),
+		AggregatedResponse: archived.AggregatedResponse,
+		AggregatedDigest:   archived.AggregatedDigest,
+		Signers:            len(archived.Signers),
+		Submission:         a.transactionLink(archived.SubmissionTxHash, archived.SubmissionBlockNumber),
+		ResultBundleCid:    archived.ResultBundleCid,
+		ResultDataTx:       archived.ResultDataTx,
+		PrimarySubmission:  archived.PrimarySubmission,
+		ChallengeDeadline:  archived.ChallengeDeadline,
+		Challenges:         archived.Challenges,
+		Outliers:           archived.outliers(),
+		ExpiredAt:          archived.ExpiredAt,
+		Expiry:             a.transactionLink(archived.ExpiryTxHash, nil),
+		Archived:           true,
+	}
+	if archived.IsCompleted {
+		status.Status = taskStatusCompleted
+	} else if archived.ExpiredAt != nil {
+		status.Status = taskStatusExpired
+	}
+	return status, nil
+}
+
+// taskStatus summarizes a task in memory. Callers must hold the tasks lock.
+func (a *Aggregator) taskStatus(task *TaskInfo) TaskStatus {
+	status := TaskStatus{
+		TaskIndex:          task.TaskIndex,
+		Status:             taskStatusProcessing,
+		PoolId:             task.PoolId,
+		CreatedAt:          task.CreatedAt,
+		TaskCreatedBlock:   a.blockLink(uint64(task.TaskCreatedBlock)),
+		ResponseDeadline:   a.responseDeadlineLink(task.TaskCreatedBlock),
+		Responses:          len(task.TaskResponsesInfo),
+		Digests:            newDigestCounts(task.responseDigests()),
+		AggregatedResponse: task.AggregatedResponse,
+		AggregatedDigest:   task.AggregatedDigest,
+		Signers:            len(task.Signers),
+		Submission:         a.transactionLink(task.SubmissionTxHash, task.SubmissionBlockNumber),
+		ResultBundleCid:    task.ResultBundleCid,
+		ResultDataTx:       task.ResultDataTx,
+		PrimarySubmission:  task.PrimarySubmission,
+		ChallengeDeadline:  task.ChallengeDeadline,
+		Challenges:         task.Challenges,
+		Outliers:           task.outliers(),
+		ExpiredAt:          task.ExpiredAt,
+		Expiry:             a.transactionLink(task.ExpiryTxHash, nil),
+	}
+	if task.IsCompleted {
+		status.Status = taskStatusCompleted
+	} else if task.ExpiredAt != nil {
+		status.Status = taskStatusExpired
+	}
+	return status
+}
+
+// newDigestCounts orders the responses per digest, most responses first
+func newDigestCounts(digests map[common.Hash]int) []DigestCount {
+	counts := make([]DigestCount, 0, len(digests))
+	for digest, responses := range digests {
+		counts = append(counts, DigestCount{Digest: digest, Responses: responses})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Responses != counts[j].Responses {
+			return counts[i].Responses > counts[j].Responses
+		}
+		return counts[i].Digest.Hex() < counts[j].Digest.Hex()
+	})
+	return counts
+}
+
+func (a *Aggregator) blockLink(number uint64) BlockLink {
+	link := BlockLink{Number: number}
+	if a.config.ExplorerBlockUrl != "" {
+		link.Url = strings.ReplaceAll(a.config.ExplorerBlockUrl, explorerNumberPlaceholder, strconv.FormatUint(number, 10))
+	}
+	return link
+}
+
+// responseDeadlineLink returns nil while the task's creation block is unknown
+func (a *Aggregator) responseDeadlineLink(taskCreatedBlock uint32) *BlockLink {
+	deadline := responseDeadline(taskCreatedBlock)
+	if deadline == 0 {
+		return nil
+	}
+	link := a.blockLink(deadline)
+	return &link
+}
+
+// transactionLink returns nil until a transaction has been sent
+func (a *Aggregator) transactionLink(txHash *common.Hash, blockNumber *uint64) *TransactionLink {
+	if txHash == nil {
+		return nil
+	}
+	link := &TransactionLink{TxHash: *txHash}
+	if a.config.ExplorerTxUrl != "" {
+		link.TxUrl = strings.ReplaceAll(a.config.ExplorerTxUrl, explorerHashPlaceholder, txHash.Hex())
+	}
+	if blockNumber != nil {
+		block := a.blockLink(*blockNumber)
+		link.Block = &block
+	}
+	return link
+}
+
+// taskStatusHandler serves GET /task/{taskIndex}
+func (a *Aggregator) taskStatusHandler(w http.ResponseWriter, r *http.Request) {
+	taskIndex, err := strconv.ParseUint(mux.Vars(r)["taskIndex"], 10, 32)
+	if err != nil {
+		http.Error(w, "Invalid task index", http.StatusBadRequest)
+		return
+	}
+
+	status, err := a.GetTaskDetails(uint32(taskIndex))
+	if err != nil {
+		if errors.Is(err, ErrUnknownTask) {
+			http.Error(w, "Unknown task", http.StatusNotFound)
+			return
+		}
+		a.logger.Error("Failed to look up task status", "taskIndex", taskIndex, "error", err)
+		http.Error(w, "Failed to look up task status", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,71 @@
+package aggregator
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/blsaggregation"
+)
+
+func TestTaskStatusLinksSubmission(t *testing.T) {
+	a, client := newTestAggregator(t)
+	a.config.ExplorerTxUrl = "https://explorer.test/tx/{hash}"
+	a.config.ExplorerBlockUrl = "https://explorer.test/block/{number}"
+
+	response := TaskResponse{ReferenceTaskIndex: 8, WinningBid: big.NewInt(0)}
+	responseDigest := common.HexToHash("0x08")
+	task := newTestTask(8, response, responseDigest)
+	a.tasks[8] = task
+
+	status, err := a.GetTaskDetails(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Submission != nil {
+		t.Fatalf("task links submission %+v before one was sent", status.Submission)
+	}
+
+	a.recordBlsAggregation(blsaggregation.Result{
+		TaskIndex:                   8,
+		TaskResponse:                response,
+		Digest:                      responseDigest,
+		Signers:                     []types.OperatorId{{1}},
+		NonSignerStakesAndSignature: emptyNonSignerStakesAndSignature(),
+	})
+	mineSubmission(t, a, client, task)
+
+	status, err = a.GetTaskDetails(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := client.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("sent %d transactions, want 1", len(sent))
+	}
+	txHash := sent[0].Hash()
+	receipt, err := client.TransactionReceipt(context.Background(), txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status.Status != taskStatusCompleted {
+		t.Fatalf("task status %q, want %q", status.Status, taskStatusCompleted)
+	}
+	submission := status.Submission
+	if submission == nil || submission.TxHash != txHash {
+		t.Fatalf("task links submission %+v, want %s", submission, txHash.Hex())
+	}
+	if want := "https://explorer.test/tx/" + txHash.Hex(); submission.TxUrl != want {
+		t.Fatalf("submission url %q, want %q", submission.TxUrl, want)
+	}
+	if submission.Block == nil || submission.Block.Number != receipt.BlockNumber.Uint64() {
+		t.Fatalf("submission block %+v, want %d", submission.Block, receipt.BlockNumber)
+	}
+	if want := "https://explorer.test/block/" + receipt.BlockNumber.String(); submission.Block.Url != want {
+		t.Fatalf("submission block url %q, want %q", submission.Block.Url, want)
+	}
+}
@@ -0,0 +1,150 @@
+package aggregator
+
+import (
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TaskStore persists checkpoints of the tasks in the aggregator's in-memory
+// working set, so they survive a restart, and keeps tasks after they leave it
+type TaskStore interface {
+	// ArchiveTask stores a task that is being removed from memory
+	ArchiveTask(task ArchivedTask) error
+
+	// SaveCheckpoint replaces the checkpoint of a task in memory
+	SaveCheckpoint(checkpoint AggregationCheckpoint) error
+	// DeleteCheckpoint removes a task's checkpoint, if any
+	DeleteCheckpoint(taskIndex uint32) error
+	// LoadCheckpoints returns every stored checkpoint
+	LoadCheckpoints() ([]AggregationCheckpoint, error)
+
+	// ListArchivedTasks returns archived tasks matching the filter, newest first
+	ListArchivedTasks(filter HistoryFilter) ([]ArchivedTask, error)
+	// SoftDeleteArchivedTask marks every archived task with the index as
+	// deleted and returns how many were marked
+	SoftDeleteArchivedTask(taskIndex uint32) (int, error)
+	// GetArchivedTask returns the most recently archived, undeleted task with
+	// the index, or nil if there is none
+	GetArchivedTask(taskIndex uint32) (*ArchivedTask, error)
+
+	// Prune removes the records the policy no longer keeps
+	Prune(policy RetentionPolicy, now time.Time) (PruneResult, error)
+	// Compact returns the space freed by deletes to the filesystem
+	Compact() error
+	// Usage reports the store's size and how many records each table holds
+	Usage() (StoreUsage, error)
+
+	Close() error
+}
+
+// RetentionPolicy is how long each table keeps its records. Zero keeps them
+// forever.
+type RetentionPolicy struct {
+	// ArchivedTasks are kept this long after they were archived
+	ArchivedTasks time.Duration
+	// DeletedTasks, soft-deleted archived tasks, are kept this long after
+	// their deletion, whatever ArchivedTasks allows
+	DeletedTasks time.Duration
+	// Checkpoints are kept this long after their task was created
+	Checkpoints time.Duration
+}
+
+// PruneResult counts the records a prune removed
+type PruneResult struct {
+	ArchivedTasks int
+	DeletedTasks  int
+	Checkpoints   int
+}
+
+// StoreUsage is the disk usage of a task store
+type StoreUsage struct {
+	FileBytes int64
+	// FreeBytes of the file are free pages a compaction would reclaim
+	FreeBytes int64
+	// Records per table
+	Records map[string]int
+}
+
+// ArchivedTask is the stored form of a TaskInfo
+type ArchivedTask struct {
+	TaskIndex                 uint32             `json:"taskIndex"`
+	PoolId                    common.Hash        `json:"poolId"`
+	TaskCreatedBlock          uint32             `json:"taskCreatedBlock"`
+	QuorumNumbers             types.QuorumNums   `json:"quorumNumbers"`
+	QuorumThresholdPercentage uint32             `json:"quorumThresholdPercentage"`
+	IsCompleted               bool               `json:"isCompleted"`
+	CreatedAt                 time.Time          `json:"createdAt"`
+	ArchivedAt                time.Time          `json:"archivedAt"`
+	AggregatedResponse        *TaskResponse      `json:"aggregatedResponse,omitempty"`
+	AggregatedDigest          *common.Hash       `json:"aggregatedDigest,omitempty"`
+	Signers                   []string           `json:"signers,omitempty"`
+	SubmissionTxHash          *common.Hash       `json:"submissionTxHash,omitempty"`
+	SubmissionBlockNumber     *uint64            `json:"submissionBlockNumber,omitempty"`
+	ResultBundleCid           *string            `json:"resultBundleCid,omitempty"`
+	ResultDataTx              *ResultDataTx      `json:"resultDataTx,omitempty"`
+	PrimarySubmission         *PrimarySubmission `json:"primarySubmission,omitempty"`
+	ChallengeDeadline         *time.Time         `json:"challengeDeadline,omitempty"`
+	Challenges                []Challenge        `json:"challenges,omitempty"`
+	ExpiredAt                 *time.Time         `json:"expiredAt,omitempty"`
+	ExpiryTxHash              *common.Hash       `json:"expiryTxHash,omitempty"`
+	DeletedAt                 *time.Time         `json:"deletedAt,omitempty"`
+	Responses                 []ArchivedResponse `json:"responses"`
+	// OperatorSetSnapshot is what the aggregate was evaluated against
+	OperatorSetSnapshot *OperatorSetSnapshot `json:"operatorSetSnapshot,omitempty"`
+}
+
+// ArchivedResponse is a stored operator response. Signatures aren't kept once
+// the task has left memory.
+type ArchivedResponse struct {
+	OperatorId   string       `json:"operatorId"`
+	TaskResponse TaskResponse `json:"taskResponse"`
+	Digest       common.Hash  `json:"digest"`
+}
+
+// newArchivedTask converts a task for storage. Callers must hold the tasks lock.
+func newArchivedTask(task *TaskInfo) ArchivedTask {
+	archived := ArchivedTask{
+		TaskIndex:                 task.TaskIndex,
+		PoolId:                    task.PoolId,
+		TaskCreatedBlock:          task.TaskCreatedBlock,
+		QuorumNumbers:             task.QuorumNumbers,
+		QuorumThresholdPercentage: uint32(task.QuorumThresholdPercentage),
+		IsCompleted:               task.IsCompleted,
+		CreatedAt:                 task.CreatedAt,
+		ArchivedAt:                time.Now().UTC(),
+		AggregatedResponse:        task.AggregatedResponse,
+		AggregatedDigest:          task.AggregatedDigest,
+		SubmissionTxHash:          task.SubmissionTxHash,
+		SubmissionBlockNumber:     task.SubmissionBlockNumber,
+		ResultBundleCid:           task.ResultBundleCid,
+		ResultDataTx:              task.ResultDataTx,
+		PrimarySubmission:         task.PrimarySubmission,
+		ChallengeDeadline:         task.ChallengeDeadline,
+		Challenges:                task.Challenges,
+		ExpiredAt:                 task.ExpiredAt,
+		ExpiryTxHash:              task.ExpiryTxHash,
+		OperatorSetSnapshot:       task.OperatorSetSnapshot,
+		Responses:                 make([]ArchivedResponse, 0, len(task.TaskResponsesInfo)),
+	}
+
+	for _, signer := range task.Signers {
+		archived.Signers = append(archived.Signers, hex.EncodeToString(signer[:]))
+	}
+
+	for operatorId, info := range task.TaskResponsesInfo {
+		archived.Responses = append(archived.Responses, ArchivedResponse{
+			OperatorId:   hex.EncodeToString(operatorId[:]),
+			TaskResponse: info.TaskResponse,
+			Digest:       info.Digest,
+		})
+	}
+	sort.Slice(archived.Responses, func(i, j int) bool {
+		return archived.Responses[i].OperatorId < archived.Responses[j].OperatorId
+	})
+
+	return archived
+}
@@ -0,0 +1,104 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+)
+
+// taskSync tracks the service manager's task numbering so the aggregator only
+// opens tasks that were actually created on chain
+type taskSync struct {
+	reader *servicemanager.Reader
+
+	mu            sync.Mutex
+	latestTaskNum uint32
+
+	latestTaskNumGauge prometheus.Gauge
+}
+
+func newTaskSync(reader *servicemanager.Reader, reg prometheus.Registerer) *taskSync {
+	latestTaskNumGauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "eigenlvr",
+		Subsystem: "aggregator",
+		Name:      "latest_task_num",
+		Help:      "The service manager's latestTaskNum as last observed by the aggregator",
+	})
+	reg.MustRegister(latestTaskNumGauge)
+
+	return &taskSync{
+		reader:             reader,
+		latestTaskNumGauge: latestTaskNumGauge,
+	}
+}
+
+// refresh reads latestTaskNum from the service manager. The cached value never
+// moves backwards, so a lagging RPC node can't shrink the known task range.
+func (s *taskSync) refresh(ctx context.Context) (uint32, error) {
+	latestTaskNum, err := s.reader.LatestTaskNum(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read latest task number: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if latestTaskNum > s.latestTaskNum {
+		s.latestTaskNum = latestTaskNum
+		s.latestTaskNumGauge.Set(float64(latestTaskNum))
+	}
+	return s.latestTaskNum, nil
+}
+
+// latest returns the cached latestTaskNum
+func (s *taskSync) latest() uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latestTaskNum
+}
+
+// seed raises the cached latestTaskNum to one observed by another aggregator
+func (s *taskSync) seed(latestTaskNum uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if latestTaskNum > s.latestTaskNum {
+		s.latestTaskNum = latestTaskNum
+		s.latestTaskNumGauge.Set(float64(latestTaskNum))
+	}
+}
+
+// verifyTask checks that taskIndex refers to a task created on chain and returns
+// the hash the service manager stored for it. Only indices at or beyond the
+// cached latestTaskNum cost an extra round trip to refresh it.
+func (s *taskSync) verifyTask(ctx context.Context, taskIndex uint32) (common.Hash, error) {
+	s.mu.Lock()
+	latestTaskNum := s.latestTaskNum
+	s.mu.Unlock()
+
+	if taskIndex >= latestTaskNum {
+		var err error
+		latestTaskNum, err = s.refresh(ctx)
+		if err != nil {
+			return common.Hash{}, err
+		}
+	}
+	if taskIndex >= latestTaskNum {
+		return common.Hash{}, fmt.Errorf("%w: index %d, latest task number %d", ErrUnknownTask, taskIndex, latestTaskNum)
+	}
+
+	taskHash, err := s.reader.TaskHash(ctx, taskIndex)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to read task hash: %w", err)
+	}
+	if taskHash == (common.Hash{}) {
+		return common.Hash{}, fmt.Errorf("%w: index %d has no task hash", ErrUnknownTask, taskIndex)
+	}
+
+	return taskHash, nil
+}
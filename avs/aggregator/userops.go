@@ -0,0 +1,128 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/erc4337"
+)
+
+// userOpSenderSetupTimeout bounds dialing the bundler and reading the chain id
+const userOpSenderSetupTimeout = 10 * time.Second
+
+// newUserOpSender returns a sender submitting through the bundler, or nil when
+// no bundler is configured
+func newUserOpSender(config Config, client eth.Client, logger logging.Logger) (*erc4337.Sender, error) {
+	if config.Erc4337BundlerUrl == "" {
+		return nil, nil
+	}
+	if len(config.ThresholdSignerParties) > 0 {
+		return nil, errors.New("erc4337_bundler_url and threshold_signer_parties are mutually exclusive")
+	}
+	if !common.IsHexAddress(config.Erc4337Account) {
+		return nil, fmt.Errorf("invalid erc4337 account: %q", config.Erc4337Account)
+	}
+	if config.ServiceManagerAddress == "" {
+		return nil, errors.New("erc4337 submission requires service_manager_address")
+	}
+
+	senderConfig := erc4337.Config{
+		BundlerUrl:       config.Erc4337BundlerUrl,
+		Account:          common.HexToAddress(config.Erc4337Account),
+		PaymasterUrl:     config.Erc4337PaymasterUrl,
+		PaymasterContext: config.Erc4337PaymasterContext,
+	}
+	if config.Erc4337EntryPoint != "" {
+		if !common.IsHexAddress(config.Erc4337EntryPoint) {
+			return nil, fmt.Errorf("invalid erc4337 entry point: %q", config.Erc4337EntryPoint)
+		}
+		senderConfig.EntryPoint = common.HexToAddress(config.Erc4337EntryPoint)
+	}
+	if config.Erc4337ReceiptTimeout != "" {
+		timeout, err := time.ParseDuration(config.Erc4337ReceiptTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid erc4337 receipt timeout: %w", err)
+		}
+		senderConfig.ReceiptTimeout = timeout
+	}
+
+	owner, err := crypto.LoadECDSA(config.Erc4337OwnerKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load erc4337 owner key: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), userOpSenderSetupTimeout)
+	defer cancel()
+	sender, err := erc4337.NewSender(ctx, senderConfig, client, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up erc4337 submission: %w", err)
+	}
+
+	logger.Info("Submissions are sent as user operations",
+		"account", sender.Account().Hex(),
+		"owner", sender.Owner().Hex(),
+		"sponsored", config.Erc4337PaymasterUrl != "",
+	)
+	return sender, nil
+}
+
+// sendUserOpSubmission calls the service manager with the respondToAuctionTask
+// calldata from the smart account and waits for the operation to be
+// included. Until then the task records the operation hash as its submission;
+// afterwards, the bundle transaction the operation landed in. Nothing is sent
+// unless the winner's bid is still escrowed.
+func (a *Aggregator) sendUserOpSubmission(ctx context.Context, task *TaskInfo, callData []byte) (*erc4337.Receipt, error) {
+	if a.config.WatchOnly {
+		return nil, ErrWatchOnly
+	}
+	if a.userOpSender == nil {
+		return nil, ErrNoSubmissionSender
+	}
+	if err := a.checkSettlementEscrow(ctx, task); err != nil {
+		return nil, err
+	}
+
+	serviceManager := common.HexToAddress(a.config.ServiceManagerAddress)
+	var sentAt time.Time
+	receipt, err := a.userOpSender.Send(ctx, serviceManager, callData, func(userOpHash common.Hash) {
+		sentAt = time.Now()
+		a.metrics.submissionSent(task.PoolId)
+		a.tasksMutex.Lock()
+		task.SubmissionTxHash = &userOpHash
+		task.revision++
+		a.tasksMutex.Unlock()
+	})
+	if err != nil {
+		if errors.Is(err, erc4337.ErrOperationFailed) {
+			a.metrics.submissionMined(task.PoolId, sentAt)
+			a.metrics.submitted(task.PoolId, submissionResultReverted)
+		} else {
+			a.metrics.submitted(task.PoolId, submissionResultFailed)
+		}
+		return receipt, fmt.Errorf("failed to submit task %d: %w", task.TaskIndex, err)
+	}
+	a.metrics.submissionMined(task.PoolId, sentAt)
+	a.metrics.submitted(task.PoolId, submissionResultSubmitted)
+
+	a.tasksMutex.Lock()
+	task.SubmissionTxHash = &receipt.TxHash
+	task.SubmissionBlockNumber = &receipt.BlockNumber
+	task.revision++
+	a.publishSubmitted(task)
+	a.tasksMutex.Unlock()
+
+	a.logger.Info("Submitted task response through bundler",
+		"taskIndex", task.TaskIndex,
+		"userOpHash", receipt.UserOpHash.Hex(),
+		"txHash", receipt.TxHash.Hex(),
+		"gasCost", receipt.ActualGasCost,
+	)
+	return receipt, nil
+}
@@ -0,0 +1,101 @@
+package aggregator
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/eigenlvr/avs/pkg/erc4337"
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+)
+
+// fakeBundler includes every operation it is sent in bundleTx
+type fakeBundler struct {
+	chainId  *big.Int
+	bundleTx common.Hash
+
+	mu   sync.Mutex
+	sent []erc4337.UserOperation
+}
+
+func (b *fakeBundler) EstimateUserOperationGas(op erc4337.UserOperation, entryPoint common.Address) map[string]*hexutil.Big {
+	gas := (*hexutil.Big)(big.NewInt(100_000))
+	return map[string]*hexutil.Big{"preVerificationGas": gas, "verificationGasLimit": gas, "callGasLimit": gas}
+}
+
+func (b *fakeBundler) SendUserOperation(op erc4337.UserOperation, entryPoint common.Address) common.Hash {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = append(b.sent, op)
+	return op.Hash(entryPoint, b.chainId)
+}
+
+func (b *fakeBundler) GetUserOperationReceipt(hash common.Hash) map[string]interface{} {
+	return map[string]interface{}{
+		"success":       true,
+		"actualGasCost": (*hexutil.Big)(big.NewInt(1)),
+		"receipt":       map[string]interface{}{"transactionHash": b.bundleTx, "blockNumber": hexutil.Uint64(9)},
+	}
+}
+
+func TestSubmitTaskThroughBundler(t *testing.T) {
+	a, client := newTestAggregator(t)
+
+	bundler := &fakeBundler{chainId: big.NewInt(testChainId), bundleTx: common.HexToHash("0xb0")}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", bundler); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	// Fresh account, nonce zero
+	client.SetCallResult(erc4337.EntryPointV07, crypto.Keccak256([]byte("getNonce(address,uint192)"))[:4], make([]byte, 32))
+	owner, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.userOpSender, err = erc4337.NewSender(context.Background(), erc4337.Config{
+		BundlerUrl: httpServer.URL,
+		Account:    common.HexToAddress("0xacc0"),
+	}, client, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.userOpSender.Close()
+
+	response := TaskResponse{ReferenceTaskIndex: 4, WinningBid: big.NewInt(0)}
+	task := newTestTask(4, response, common.HexToHash("0x04"))
+	nonSignerStakesAndSignature := emptyNonSignerStakesAndSignature()
+	task.AggregatedResponse = &response
+	task.nonSignerStakesAndSignature = &nonSignerStakesAndSignature
+	a.tasks[4] = task
+
+	a.submitTask(task)
+
+	if sent := client.Sent(); len(sent) != 0 {
+		t.Fatalf("aggregator key sent %d transactions, want the submission as a user operation", len(sent))
+	}
+	bundler.mu.Lock()
+	defer bundler.mu.Unlock()
+	if len(bundler.sent) != 1 {
+		t.Fatalf("bundler was sent %d operations, want 1", len(bundler.sent))
+	}
+	if !bytes.Contains(bundler.sent[0].CallData, servicemanager.ABI.Methods["respondToAuctionTask"].ID) {
+		t.Fatal("user operation doesn't call respondToAuctionTask")
+	}
+	if task.SubmissionTxHash == nil || *task.SubmissionTxHash != bundler.bundleTx {
+		t.Fatalf("task submission %v, want bundle transaction %s", task.SubmissionTxHash, bundler.bundleTx.Hex())
+	}
+	if task.SubmissionBlockNumber == nil || *task.SubmissionBlockNumber != 9 {
+		t.Fatalf("task submission block %v, want 9", task.SubmissionBlockNumber)
+	}
+}
@@ -0,0 +1,101 @@
+package aggregator
+
+import (
+	"github.com/eigenlvr/avs/pkg/configfile"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+)
+
+// Validate checks the config before anything is started, returning a
+// *configfile.ValidationError that lists every problem found. Addresses a
+// network preset fills in must be applied first.
+func (config Config) Validate() error {
+	var problems configfile.Problems
+
+	if problems.Required("server_ip_port_address", config.ServerIpPortAddr) {
+		problems.HostPort("server_ip_port_address", config.ServerIpPortAddr)
+	}
+	problems.HostPort("grpc_server_ip_port_address", config.GrpcServerIpPortAddr)
+	if config.EnableMetrics && problems.Required("eigen_metrics_ip_port_address", config.EigenMetricsIpPortAddress) {
+		problems.HostPort("eigen_metrics_ip_port_address", config.EigenMetricsIpPortAddress)
+	}
+
+	if problems.Required("eth_rpc_url", config.EthRpcUrl) {
+		problems.URL("eth_rpc_url", config.EthRpcUrl, "http", "https", "ws", "wss")
+	}
+	problems.URL("eth_ws_url", config.EthWsUrl, "ws", "wss")
+	problems.URL("erc4337_bundler_url", config.Erc4337BundlerUrl, "http", "https")
+	problems.URL("erc4337_paymaster_url", config.Erc4337PaymasterUrl, "http", "https")
+	problems.URL("ipfs_api_url", config.IpfsApiUrl, "http", "https")
+
+	problems.RequiredAddress("registry_coordinator_address", config.RegistryCoordinatorAddress)
+	problems.RequiredAddress("operator_state_retriever_address", config.OperatorStateRetrieverAddress)
+	problems.Address("service_manager_address", config.ServiceManagerAddress)
+	problems.Address("operator_allowlist_contract", config.OperatorAllowlistContract)
+	problems.Address("threshold_signer_address", config.ThresholdSignerAddress)
+	problems.Address("erc4337_entry_point", config.Erc4337EntryPoint)
+	problems.Address("erc4337_account", config.Erc4337Account)
+	problems.Address("blob_inbox_address", config.BlobInboxAddress)
+	problems.Address("auction_escrow_address", config.AuctionEscrowAddress)
+
+	switch config.AggregatorSigner {
+	case "", remotesigner.KindLocal:
+		problems.File("aggregator_private_key_path", config.AggregatorPrivateKeyPath)
+		problems.File("aggregator_private_key_password_file", config.AggregatorPrivateKeyPasswordFile)
+	case remotesigner.KindWeb3Signer:
+		if problems.Required("aggregator_signer_url", config.AggregatorSignerUrl) {
+			problems.URL("aggregator_signer_url", config.AggregatorSignerUrl, "http", "https")
+		}
+		problems.RequiredAddress("aggregator_signer_address", config.AggregatorSignerAddress)
+	case remotesigner.KindAwsKms, remotesigner.KindGcpKms:
+		problems.Required("aggregator_signer_key_id", config.AggregatorSignerKeyId)
+		problems.Address("aggregator_signer_address", config.AggregatorSignerAddress)
+	default:
+		problems.OneOf("aggregator_signer", config.AggregatorSigner, remotesigner.KindLocal, remotesigner.KindWeb3Signer, remotesigner.KindAwsKms, remotesigner.KindGcpKms)
+	}
+	if config.Erc4337BundlerUrl != "" && problems.Required("erc4337_owner_key_path", config.Erc4337OwnerKeyPath) {
+		problems.File("erc4337_owner_key_path", config.Erc4337OwnerKeyPath)
+	}
+
+	for _, duration := range []struct{ key, value string }{
+		{"rpc_cache_head_ttl", config.RpcCacheHeadTtl},
+		{"task_retention", config.TaskRetention},
+		{"checkpoint_interval", config.CheckpointInterval},
+		{"store_maintenance_interval", config.StoreMaintenanceInterval},
+		{"archive_retention", config.ArchiveRetention},
+		{"deleted_task_retention", config.DeletedTaskRetention},
+		{"checkpoint_retention", config.CheckpointRetention},
+		{"submission_stuck_after", config.SubmissionStuckAfter},
+		{"threshold_signer_timeout", config.ThresholdSignerTimeout},
+		{"erc4337_receipt_timeout", config.Erc4337ReceiptTimeout},
+		{"task_poll_interval", config.TaskPollInterval},
+		{"operator_set_refresh_interval", config.OperatorSetRefreshInterval},
+		{"operator_liveness_window", config.OperatorLivenessWindow},
+		{"param_update_delay", config.ParamUpdateDelay},
+		{"cors_max_age", config.CorsMaxAge},
+		{"hsts_max_age", config.HstsMaxAge},
+		{"task_expiry", config.TaskExpiry},
+		{"challenge_window", config.ChallengeWindow},
+	} {
+		problems.Duration(duration.key, duration.value)
+	}
+
+	if config.SubmitExpiredTasks {
+		problems.Required("service_manager_address", config.ServiceManagerAddress)
+		if config.WatchOnly {
+			problems.Addf("submit_expired_tasks can't submit in watch_only")
+		}
+	}
+
+	problems.File("pools_file", config.PoolsFile)
+	if config.BidRetentionBlocks != 0 && config.BidRetentionBlocks <= config.BidRevealBlocks {
+		problems.Addf("bid_retention_blocks must exceed bid_reveal_blocks, or no auction has a commit phase")
+	}
+
+	problems.OneOf("operator_allowlist_mode", config.OperatorAllowlistMode, AllowlistModeOff, AllowlistModeMonitor, AllowlistModeEnforce)
+	problems.OneOf("aggregation_backend", config.AggregationBackend, aggregationBackendBuiltin, aggregationBackendBlsAgg)
+	if err := validateCommitteeConfig(config); err != nil {
+		problems.Addf("%v", err)
+	}
+
+	return problems.Err()
+}
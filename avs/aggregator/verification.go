@@ -0,0 +1,126 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/quorumapk"
+)
+
+// Reasons a response signature is refused
+const (
+	signatureFailureInvalid = "invalid"
+	// signatureFailureUnknownKey is an operator without registered pubkeys,
+	// or whose G1 and G2 pubkeys don't match
+	signatureFailureUnknownKey = "unknown_key"
+)
+
+// unknownOperatorLabel stands in for ids without registered keys, which the
+// sender is free to choose, so they can't grow the failure metric unbounded
+const unknownOperatorLabel = "unknown"
+
+func newSignatureFailures(reg prometheus.Registerer) *prometheus.CounterVec {
+	failures := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "eigenlvr",
+		Subsystem: "aggregator",
+		Name:      "response_signature_failures_total",
+		Help:      "Task responses refused because their BLS signature didn't verify, per registered operator and reason",
+	}, []string{"operator", "reason"})
+	reg.MustRegister(failures)
+	return failures
+}
+
+// verifyResponseSignature checks that the response is signed over its digest
+// by the operator's registered BLS key, before it is recorded. The blsagg
+// backend verifies signatures itself when they are submitted to it. Anyone
+// can send a response carrying another operator's id, so invalid signatures
+// only count towards an automatic ban when the sender authenticated as the
+// operator.
+func (a *Aggregator) verifyResponseSignature(signedResponse SignedTaskResponse, responseDigest common.Hash, authenticated bool) error {
+	if a.blsAggregation != nil {
+		return nil
+	}
+
+	signature := signedResponse.BlsSignature
+	err := a.apkTracker.VerifySignature(signedResponse.OperatorId, &signature, responseDigest)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, quorumapk.ErrInvalidSignature):
+		a.signatureFailures.WithLabelValues(formatOperatorId(signedResponse.OperatorId), signatureFailureInvalid).Inc()
+		if authenticated {
+			a.recordInvalidSignature(signedResponse.OperatorId)
+		}
+		return ErrInvalidSignature
+	case errors.Is(err, quorumapk.ErrUnknownSigner):
+		a.signatureFailures.WithLabelValues(unknownOperatorLabel, signatureFailureUnknownKey).Inc()
+		return fmt.Errorf("%w: no registered BLS key", ErrOperatorNotRegistered)
+	default:
+		// The operator has registered keys, but they can't verify
+		a.signatureFailures.WithLabelValues(formatOperatorId(signedResponse.OperatorId), signatureFailureUnknownKey).Inc()
+		return fmt.Errorf("%w: %v", ErrSignatureRejected, err)
+	}
+}
+
+// recordInvalidSignature counts an invalid signature from the authenticated
+// operator, and bans it once it has sent AutoBanInvalidSignatures of them
+func (a *Aggregator) recordInvalidSignature(operatorId types.OperatorId) {
+	banned, err := a.banList.RecordInvalidSignature(operatorId)
+	if err != nil {
+		a.logger.Error("Failed to save ban list", "error", err)
+	}
+	if banned {
+		a.logger.Warn("Operator banned for invalid signatures", "operatorId", formatOperatorId(operatorId))
+		a.notifyOperatorBanned(operatorId)
+	}
+}
+
+// registeredStake returns the operator's stake in each of the task's quorums
+// at the task's creation block. Responses to tasks the aggregator hasn't seen
+// created get ErrUnknownTask, as there is no block to read the stake at.
+// Operators the registry coordinator doesn't list as registered then, or
+// without stake in any of the quorums, get ErrOperatorNotRegistered.
+// Failures to read the registry are returned as they are, so they aren't
+// mistaken for unregistered operators.
+func (a *Aggregator) registeredStake(ctx context.Context, operatorId types.OperatorId, taskIndex uint32) (map[types.QuorumNum]*big.Int, error) {
+	a.tasksMutex.RLock()
+	var taskCreatedBlock uint32
+	var quorums types.QuorumNums
+	if task, exists := a.tasks[taskIndex]; exists {
+		taskCreatedBlock = task.TaskCreatedBlock
+		quorums = a.taskQuorums(task)
+	}
+	a.tasksMutex.RUnlock()
+
+	// Block 0 would read the stake at the latest block instead
+	if taskCreatedBlock == 0 {
+		return nil, fmt.Errorf("%w: index %d has no known creation block", ErrUnknownTask, taskIndex)
+	}
+
+	registered, err := a.avsReader.IsOperatorIdRegisteredAtBlock(ctx, operatorId, taskCreatedBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check operator registration: %w", err)
+	}
+	if !registered {
+		return nil, fmt.Errorf("%w: %s at block %d", ErrOperatorNotRegistered, formatOperatorId(operatorId), taskCreatedBlock)
+	}
+
+	stakes, err := a.avsReader.GetOperatorStakeInQuorumsAtBlock(ctx, operatorId, taskCreatedBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch operator stake: %w", err)
+	}
+
+	for _, quorum := range quorums {
+		if stake, ok := stakes[quorum]; ok && stake.Sign() > 0 {
+			return stakes, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s has no stake in quorums %v at block %d",
+		ErrOperatorNotRegistered, formatOperatorId(operatorId), quorums, taskCreatedBlock)
+}
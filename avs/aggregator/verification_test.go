@@ -0,0 +1,281 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/avsregistry"
+	"github.com/eigenlvr/avs/pkg/committee"
+	"github.com/eigenlvr/avs/pkg/digest"
+	"github.com/eigenlvr/avs/pkg/quorumapk"
+	"github.com/eigenlvr/avs/pkg/testutils/fakeeth"
+	"github.com/eigenlvr/avs/pkg/testutils/fixtures"
+)
+
+var (
+	testRegistryCoordinator = common.HexToAddress("0x5ea1ed0000000000000000000000000000000002")
+	testBlsApkRegistry      = common.HexToAddress("0x5ea1ed0000000000000000000000000000000003")
+)
+
+// fixturePubkeys serves the pubkeys of fixture operators as registered
+type fixturePubkeys []fixtures.Operator
+
+func (p fixturePubkeys) QueryExistingRegisteredOperatorPubKeys(
+	ctx context.Context,
+	startBlock *big.Int,
+	stopBlock *big.Int,
+	blockRange *big.Int,
+) ([]types.OperatorAddr, []types.OperatorPubkeys, error) {
+	addresses := make([]types.OperatorAddr, len(p))
+	pubkeys := make([]types.OperatorPubkeys, len(p))
+	for i, operator := range p {
+		addresses[i] = operator.Address
+		pubkeys[i] = types.OperatorPubkeys{G1Pubkey: operator.BlsKeyPair.GetPubKeyG1(), G2Pubkey: operator.PubkeyG2}
+	}
+	return addresses, pubkeys, nil
+}
+
+// newTestApkTracker returns a tracker with the operators registered in quorum 0
+func newTestApkTracker(t *testing.T, client *fakeeth.Client, operators []fixtures.Operator) *quorumapk.Tracker {
+	t.Helper()
+
+	apk := bls.NewZeroG1Point()
+	members := make([]quorumapk.Operator, len(operators))
+	for i, operator := range operators {
+		apk = apk.Add(operator.BlsKeyPair.GetPubKeyG1())
+		members[i] = quorumapk.Operator{OperatorId: operator.OperatorId, Address: operator.Address, Quorums: []types.QuorumNum{0}}
+	}
+	client.SetCallResult(testRegistryCoordinator, crypto.Keccak256([]byte("blsApkRegistry()"))[:4],
+		common.LeftPadBytes(testBlsApkRegistry.Bytes(), 32))
+	client.SetCallResult(testBlsApkRegistry, crypto.Keccak256([]byte("getApk(uint8)"))[:4],
+		append(common.LeftPadBytes(apk.X.BigInt(new(big.Int)).Bytes(), 32), common.LeftPadBytes(apk.Y.BigInt(new(big.Int)).Bytes(), 32)...))
+
+	tracker, err := quorumapk.NewTracker(context.Background(), fixturePubkeys(operators), testRegistryCoordinator, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.Refresh(context.Background(), members, client.Head().Number.Uint64()); err != nil {
+		t.Fatal(err)
+	}
+	return tracker
+}
+
+// newVerifyingAggregator returns an aggregator checking response signatures
+// against the operators' keys, and banning after autoBan invalid ones
+func newVerifyingAggregator(t *testing.T, operators []fixtures.Operator, autoBan int) *Aggregator {
+	t.Helper()
+
+	a, client := newTestAggregator(t)
+	a.apkTracker = newTestApkTracker(t, client, operators)
+	a.signatureFailures = newSignatureFailures(a.metricsReg)
+	banList, err := NewBanList("", autoBan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.banList = banList
+	return a
+}
+
+// signedBy returns a response claiming to come from operatorId, signed by the
+// signer's key
+func signedBy(t *testing.T, operatorId types.OperatorId, signer fixtures.Operator) SignedTaskResponse {
+	t.Helper()
+
+	response := TaskResponse{ReferenceTaskIndex: 1, Winner: common.HexToAddress("0x01"), WinningBid: big.NewInt(1), TotalBids: 1}
+	responseDigest, err := digest.AuctionTaskResponseDigest(response.ReferenceTaskIndex, response.Winner, response.WinningBid, response.TotalBids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return SignedTaskResponse{
+		TaskResponse: response,
+		BlsSignature: *signer.BlsKeyPair.SignMessage(responseDigest),
+		OperatorId:   operatorId,
+	}
+}
+
+func TestForgedResponsesNeverBanOperator(t *testing.T) {
+	operators := fixtures.New("banlist").Operators(2)
+	victim, forger := operators[0], operators[1]
+	a := newVerifyingAggregator(t, operators, 3)
+
+	forged := signedBy(t, victim.OperatorId, forger)
+	for i := 0; i < 10; i++ {
+		if _, err := a.processTaskResponse(context.Background(), forged, false); !errors.Is(err, ErrInvalidSignature) {
+			t.Fatalf("forged response %d: got %v, want %v", i, err, ErrInvalidSignature)
+		}
+	}
+	if a.banList.IsBanned(victim.OperatorId) {
+		t.Fatal("unauthenticated forged responses banned the operator")
+	}
+
+	// The operator's own session sending invalid signatures is what bans it
+	for i := 0; i < 3; i++ {
+		a.processTaskResponse(context.Background(), forged, true)
+	}
+	if !a.banList.IsBanned(victim.OperatorId) {
+		t.Fatal("operator not banned after invalid signatures over its authenticated session")
+	}
+}
+
+func TestUnregisteredIdsShareFailureLabel(t *testing.T) {
+	operators := fixtures.New("failures").Operators(2)
+	a := newVerifyingAggregator(t, operators[:1], 0)
+
+	for i := byte(1); i <= 5; i++ {
+		unregistered := signedBy(t, types.OperatorId{i}, operators[1])
+		if _, err := a.processTaskResponse(context.Background(), unregistered, false); !errors.Is(err, ErrOperatorNotRegistered) {
+			t.Fatalf("unregistered id %d: got %v, want %v", i, err, ErrOperatorNotRegistered)
+		}
+	}
+	forged := signedBy(t, operators[0].OperatorId, operators[1])
+	a.processTaskResponse(context.Background(), forged, false)
+
+	families, err := a.metricsReg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := make(map[string]bool)
+	for _, family := range families {
+		if family.GetName() != "eigenlvr_aggregator_response_signature_failures_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "operator" {
+					labels[label.GetValue()] = true
+				}
+			}
+		}
+	}
+	if len(labels) != 2 || !labels[unknownOperatorLabel] || !labels[formatOperatorId(operators[0].OperatorId)] {
+		t.Fatalf("failure metric operator labels %v, want %q and the registered operator", labels, unknownOperatorLabel)
+	}
+	unknown := map[string]string{"operator": unknownOperatorLabel}
+	if count := gathered(t, a.metricsReg, "eigenlvr_aggregator_response_signature_failures_total", unknown); count != 5 {
+		t.Fatalf("recorded %v failures of unregistered ids, want 5", count)
+	}
+}
+
+func TestResponsesToUnknownTasksAreRejected(t *testing.T) {
+	operators := fixtures.New("unknown").Operators(1)
+	a := newVerifyingAggregator(t, operators, 0)
+	signed := signedBy(t, operators[0].OperatorId, operators[0])
+
+	if _, err := a.processTaskResponse(context.Background(), signed, true); !errors.Is(err, ErrUnknownTask) {
+		t.Fatalf("response to an untracked task: got %v, want %v", err, ErrUnknownTask)
+	}
+	if len(a.tasks) != 0 {
+		t.Fatalf("opened %d tasks for a response to an untracked one", len(a.tasks))
+	}
+
+	// A task opened before its creation event has no block to read stake at
+	a.tasks[1] = &TaskInfo{
+		TaskIndex:         1,
+		TaskResponses:     make(map[types.OperatorId]TaskResponse),
+		TaskResponsesInfo: make(map[types.OperatorId]TaskResponseInfo),
+	}
+	if _, err := a.processTaskResponse(context.Background(), signed, true); !errors.Is(err, ErrUnknownTask) {
+		t.Fatalf("response to a task without a creation block: got %v, want %v", err, ErrUnknownTask)
+	}
+	if len(a.tasks[1].TaskResponsesInfo) != 0 {
+		t.Fatal("recorded a response without the operator's stake at the task's creation block")
+	}
+}
+
+func TestRepeatedResponsesAreCheckedBeforeRegistryReads(t *testing.T) {
+	operators := fixtures.New("dedup").Operators(2)
+	operator, forger := operators[0], operators[1]
+	a := newVerifyingAggregator(t, operators[:1], 0)
+	a.repeatedResponses = newRepeatedResponses(a.metricsReg)
+
+	first := signedBy(t, operator.OperatorId, operator)
+	firstDigest, err := digest.AuctionTaskResponseDigest(first.TaskResponse.ReferenceTaskIndex, first.TaskResponse.Winner, first.TaskResponse.WinningBid, first.TaskResponse.TotalBids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := newTestTask(1, first.TaskResponse, firstDigest)
+	task.TaskResponses = map[types.OperatorId]TaskResponse{operator.OperatorId: first.TaskResponse}
+	task.TaskResponsesInfo = map[types.OperatorId]TaskResponseInfo{operator.OperatorId: {
+		TaskResponse: first.TaskResponse,
+		BlsSignature: first.BlsSignature,
+		OperatorId:   operator.OperatorId,
+		Digest:       firstDigest,
+	}}
+	a.tasks[1] = task
+
+	// Answered from the task, as the aggregator has no registry to read here
+	if responseDigest, err := a.processTaskResponse(context.Background(), first, false); err != nil || responseDigest != common.Hash(firstDigest) {
+		t.Fatalf("repeated response: got digest %s and %v, want the first digest", responseDigest.Hex(), err)
+	}
+
+	other := first.TaskResponse
+	other.WinningBid = big.NewInt(2)
+	otherDigest, err := digest.AuctionTaskResponseDigest(other.ReferenceTaskIndex, other.Winner, other.WinningBid, other.TotalBids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conflicting := SignedTaskResponse{TaskResponse: other, BlsSignature: *operator.BlsKeyPair.SignMessage(otherDigest), OperatorId: operator.OperatorId}
+	if _, err := a.processTaskResponse(context.Background(), conflicting, false); !errors.Is(err, ErrConflictingResponse) {
+		t.Fatalf("conflicting response: got %v, want %v", err, ErrConflictingResponse)
+	}
+
+	// Nobody else can make the operator look like it changed its answer
+	forged := SignedTaskResponse{TaskResponse: other, BlsSignature: *forger.BlsKeyPair.SignMessage(otherDigest), OperatorId: operator.OperatorId}
+	if _, err := a.processTaskResponse(context.Background(), forged, false); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("forged conflicting response: got %v, want %v", err, ErrInvalidSignature)
+	}
+	if count := gathered(t, a.metricsReg, "eigenlvr_aggregator_repeated_task_responses_total", map[string]string{"kind": repeatedResponseConflicting}); count != 1 {
+		t.Fatalf("counted %v conflicting responses, want 1", count)
+	}
+	if len(task.TaskResponsesInfo) != 1 || task.TaskResponsesInfo[operator.OperatorId].Digest != firstDigest {
+		t.Fatal("a repeated response replaced the operator's first")
+	}
+}
+
+func TestCommitteeProofsMustDrawTheOperator(t *testing.T) {
+	operators := fixtures.New("committee").Operators(3)
+	member, other, unstaked := operators[0], operators[1], operators[2]
+	a := newVerifyingAggregator(t, operators, 0)
+	a.config.CommitteeVrfExpectedSize = uint32(len(operators))
+	a.operators = newOperatorTracker()
+	for _, operator := range operators[:2] {
+		a.operators.registered = append(a.operators.registered, avsregistry.RegisteredOperator{
+			OperatorId:     operator.OperatorId,
+			Address:        operator.Address,
+			StakePerQuorum: map[types.QuorumNum]*big.Int{0: big.NewInt(10)},
+		})
+	}
+	taskHash := common.HexToHash("0x7a5c")
+	quorums := types.QuorumNums{0}
+
+	withProof := func(operator fixtures.Operator, proof *bls.Signature) SignedTaskResponse {
+		response := signedBy(t, operator.OperatorId, operator)
+		response.CommitteeProof = proof
+		return response
+	}
+	for _, tc := range []struct {
+		name     string
+		response SignedTaskResponse
+	}{
+		{"no proof", withProof(member, nil)},
+		{"another operator's ticket", withProof(member, committee.Prove(other.BlsKeyPair, taskHash))},
+		{"ticket for another task", withProof(member, committee.Prove(member.BlsKeyPair, common.HexToHash("0x07")))},
+		{"ticket without stake", withProof(unstaked, committee.Prove(unstaked.BlsKeyPair, taskHash))},
+	} {
+		if err := a.checkCommitteeProof(tc.response, taskHash, quorums); !errors.Is(err, ErrNotInCommittee) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, ErrNotInCommittee)
+		}
+	}
+
+	// With a committee as large as the operator set every staked ticket wins
+	if err := a.checkCommitteeProof(withProof(member, committee.Prove(member.BlsKeyPair, taskHash)), taskHash, quorums); err != nil {
+		t.Fatalf("operator's own ticket: %v", err)
+	}
+}
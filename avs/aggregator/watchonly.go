@@ -0,0 +1,214 @@
+package aggregator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/logwatcher"
+	"github.com/eigenlvr/avs/pkg/notify"
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+)
+
+// Results of comparing with the primary aggregator
+const (
+	shadowResultMatch    = "match"
+	shadowResultMismatch = "mismatch"
+	// shadowResultMissing is a task this aggregator aggregated but the
+	// primary never submitted before it left memory
+	shadowResultMissing = "missing"
+	// shadowResultUnknown is a primary submission for a task not in memory
+	shadowResultUnknown = "unknown_task"
+)
+
+// ErrWatchOnly is returned when a watch-only aggregator is asked to submit
+var ErrWatchOnly = errors.New("aggregator is watch-only and never submits on chain")
+
+// PrimarySubmission is the response the primary aggregator got accepted for a
+// task, as seen by a watch-only aggregator
+type PrimarySubmission struct {
+	TxHash       common.Hash  `json:"txHash"`
+	BlockNumber  uint64       `json:"blockNumber"`
+	TaskResponse TaskResponse `json:"taskResponse"`
+	// Matches is whether this aggregator's result agrees, unset until it has one
+	Matches *bool `json:"matches,omitempty"`
+}
+
+// shadowVerifier follows the responses the primary aggregator submits to the
+// service manager, so a watch-only aggregator can check its own results
+// against them
+type shadowVerifier struct {
+	watcher     *logwatcher.Watcher
+	comparisons *prometheus.CounterVec
+}
+
+// newShadowVerifier returns nil unless the aggregator is watch-only
+func newShadowVerifier(config Config, client eth.Client, reg prometheus.Registerer, logger logging.Logger) (*shadowVerifier, error) {
+	if !config.WatchOnly {
+		return nil, nil
+	}
+	if config.ServiceManagerAddress == "" {
+		return nil, errors.New("watch-only mode requires service_manager_address to follow the primary aggregator")
+	}
+	if config.BlobInboxAddress != "" {
+		return nil, errors.New("watch-only mode can't publish result data on chain, unset blob_inbox_address")
+	}
+
+	verifier := &shadowVerifier{
+		watcher: logwatcher.NewWatcher(
+			logwatcher.Config{
+				Name:      "primary-responses",
+				Addresses: []common.Address{common.HexToAddress(config.ServiceManagerAddress)},
+				Topics:    [][]common.Hash{{servicemanager.AuctionTaskRespondedTopic}},
+				WsUrl:     config.EthWsUrl,
+			},
+			client,
+			logwatcher.NewFileCheckpoint(""),
+			reg,
+			logger,
+		),
+		comparisons: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "aggregator",
+			Name:      "shadow_comparisons_total",
+			Help:      "Results of comparing this watch-only aggregator's results with the primary's submissions",
+		}, []string{"result"}),
+	}
+	reg.MustRegister(verifier.comparisons)
+	return verifier, nil
+}
+
+// watchPrimaryResponses records every response the primary gets accepted on
+// the task it answers, until the context is done
+func (a *Aggregator) watchPrimaryResponses(ctx context.Context) {
+	a.logger.Info("Watch-only mode, comparing results with the primary aggregator's submissions")
+
+	err := a.shadow.watcher.Run(ctx, func(log gethtypes.Log) error {
+		event, err := servicemanager.ParseAuctionTaskResponded(log)
+		if err != nil {
+			a.logger.Warn("Skipping undecodable primary response", "txHash", log.TxHash.Hex(), "error", err)
+			return nil
+		}
+		a.recordPrimarySubmission(event)
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		a.logger.Error("Stopped following the primary aggregator", "error", err)
+	}
+}
+
+func (a *Aggregator) recordPrimarySubmission(event *servicemanager.AuctionTaskResponded) {
+	taskIndex := event.TaskResponse.ReferenceTaskIndex
+
+	a.tasksMutex.Lock()
+	defer a.tasksMutex.Unlock()
+
+	task, exists := a.tasks[taskIndex]
+	if !exists {
+		a.shadow.comparisons.WithLabelValues(shadowResultUnknown).Inc()
+		a.logger.Debug("Primary responded to a task not in memory", "taskIndex", taskIndex)
+		return
+	}
+
+	response := TaskResponse{
+		ReferenceTaskIndex: taskIndex,
+		Winner:             event.TaskResponse.Winner,
+		WinningBid:         event.TaskResponse.WinningBid,
+	}
+	if event.TaskResponse.TotalBids != nil {
+		response.TotalBids = uint32(event.TaskResponse.TotalBids.Uint64())
+	}
+	task.PrimarySubmission = &PrimarySubmission{
+		TxHash:       event.Raw.TxHash,
+		BlockNumber:  event.Raw.BlockNumber,
+		TaskResponse: response,
+	}
+	a.compareWithPrimary(task)
+	// Watch-only aggregators never submit, so subscribers learn of the
+	// primary's submission instead
+	blockNumber := event.Raw.BlockNumber
+	a.publishTaskEvent(TaskEvent{
+		Type:        TaskEventSubmitted,
+		TaskIndex:   taskIndex,
+		PoolId:      task.PoolId,
+		TxHash:      &task.PrimarySubmission.TxHash,
+		BlockNumber: &blockNumber,
+	})
+}
+
+// compareWithPrimary checks the task's result against the primary's once
+// both are known, and alerts on a mismatch. Callers must hold the tasks lock.
+func (a *Aggregator) compareWithPrimary(task *TaskInfo) {
+	primary := task.PrimarySubmission
+	if a.shadow == nil || primary == nil || primary.Matches != nil || task.AggregatedResponse == nil {
+		return
+	}
+
+	ours := *task.AggregatedResponse
+	matches := ours.ReferenceTaskIndex == primary.TaskResponse.ReferenceTaskIndex &&
+		ours.Winner == primary.TaskResponse.Winner &&
+		ours.TotalBids == primary.TaskResponse.TotalBids &&
+		bidOrZero(ours.WinningBid).Cmp(bidOrZero(primary.TaskResponse.WinningBid)) == 0
+	// Status readers may hold the previous value, so it is replaced, not changed
+	compared := *primary
+	compared.Matches = &matches
+	task.PrimarySubmission = &compared
+
+	if matches {
+		a.shadow.comparisons.WithLabelValues(shadowResultMatch).Inc()
+		a.logger.Info("Result matches the primary aggregator's", "taskIndex", task.TaskIndex, "txHash", primary.TxHash.Hex())
+		return
+	}
+
+	a.shadow.comparisons.WithLabelValues(shadowResultMismatch).Inc()
+	a.logger.Error("Result differs from the primary aggregator's",
+		"taskIndex", task.TaskIndex,
+		"txHash", primary.TxHash.Hex(),
+		"winner", ours.Winner.Hex(),
+		"primaryWinner", primary.TaskResponse.Winner.Hex(),
+		"winningBid", bidOrZero(ours.WinningBid).String(),
+		"primaryWinningBid", bidOrZero(primary.TaskResponse.WinningBid).String(),
+		"totalBids", ours.TotalBids,
+		"primaryTotalBids", primary.TaskResponse.TotalBids,
+	)
+	a.notify(notify.Message{
+		Event: notify.EventShadowMismatch,
+		Title: fmt.Sprintf("Auction %d differs from the primary aggregator", task.TaskIndex),
+		Text: fmt.Sprintf("Pool: %s\nTransaction: %s\nWinner: %s (primary %s)\nWinning bid: %s (primary %s)\nBids: %d (primary %d)",
+			task.PoolId.Hex(), primary.TxHash.Hex(),
+			ours.Winner.Hex(), primary.TaskResponse.Winner.Hex(),
+			bidOrZero(ours.WinningBid).String(), bidOrZero(primary.TaskResponse.WinningBid).String(),
+			ours.TotalBids, primary.TaskResponse.TotalBids),
+	})
+}
+
+// checkPrimarySubmitted alerts on a task leaving memory with a result of
+// ours but nothing from the primary. Callers must hold the tasks lock.
+func (a *Aggregator) checkPrimarySubmitted(task *TaskInfo) {
+	if a.shadow == nil || task.AggregatedResponse == nil || task.PrimarySubmission != nil {
+		return
+	}
+
+	a.shadow.comparisons.WithLabelValues(shadowResultMissing).Inc()
+	a.logger.Warn("Primary aggregator never submitted a task this aggregator aggregated", "taskIndex", task.TaskIndex)
+	a.notify(notify.Message{
+		Event: notify.EventShadowMismatch,
+		Title: fmt.Sprintf("Auction %d was never submitted by the primary aggregator", task.TaskIndex),
+		Text: fmt.Sprintf("Pool: %s\nWinner: %s\nWinning bid: %s",
+			task.PoolId.Hex(), task.AggregatedResponse.Winner.Hex(), bidOrZero(task.AggregatedResponse.WinningBid).String()),
+	})
+}
+
+func bidOrZero(bid *big.Int) *big.Int {
+	if bid == nil {
+		return new(big.Int)
+	}
+	return bid
+}
@@ -0,0 +1,390 @@
+package aggregator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/wsproto"
+)
+
+// operatorSessionBuffer is how many outgoing messages may be queued for one
+// operator before pushes to it are dropped
+const operatorSessionBuffer = 64
+
+var (
+	// ErrOperatorNotRegistered is returned when a WebSocket login comes from an
+	// address that isn't the registered address of the claimed operator, or a
+	// response from an operator that wasn't registered in the task's quorums
+	// when the task was created
+	ErrOperatorNotRegistered = errors.New("operator is not registered")
+)
+
+// operatorSession is one authenticated operator WebSocket
+type operatorSession struct {
+	operatorId types.OperatorId
+	conn       *websocket.Conn
+	send       chan wsproto.Message
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+func (s *operatorSession) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.conn.Close()
+	})
+}
+
+// queue hands a message to the session's writer without blocking
+func (s *operatorSession) queue(message wsproto.Message) bool {
+	select {
+	case s.send <- message:
+		return true
+	case <-s.done:
+		return false
+	default:
+		return false
+	}
+}
+
+// operatorHub tracks the operators connected over WebSocket, at most one
+// session per operator
+type operatorHub struct {
+	mu       sync.RWMutex
+	sessions map[types.OperatorId]*operatorSession
+	// closed is set once every session was closed for a handover; sessions
+	// added later are closed right away
+	closed bool
+	// serving counts sessions that may still be handling messages
+	serving sync.WaitGroup
+
+	connectedGauge prometheus.Gauge
+}
+
+func newOperatorHub(reg prometheus.Registerer) *operatorHub {
+	connectedGauge := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "eigenlvr",
+		Subsystem: "aggregator",
+		Name:      "websocket_operators",
+		Help:      "Operators currently connected over WebSocket",
+	})
+	reg.MustRegister(connectedGauge)
+
+	return &operatorHub{
+		sessions:       make(map[types.OperatorId]*operatorSession),
+		connectedGauge: connectedGauge,
+	}
+}
+
+// add registers the session, closing any earlier session of the same operator
+func (h *operatorHub) add(session *operatorSession) {
+	h.mu.Lock()
+	h.serving.Add(1)
+	if h.closed {
+		h.mu.Unlock()
+		session.close()
+		return
+	}
+	previous := h.sessions[session.operatorId]
+	h.sessions[session.operatorId] = session
+	h.connectedGauge.Set(float64(len(h.sessions)))
+	h.mu.Unlock()
+
+	if previous != nil {
+		previous.close()
+	}
+}
+
+func (h *operatorHub) remove(session *operatorSession) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	defer h.serving.Done()
+
+	if h.sessions[session.operatorId] == session {
+		delete(h.sessions, session.operatorId)
+		h.connectedGauge.Set(float64(len(h.sessions)))
+	}
+}
+
+// closeAll closes every session and waits until none is handling a message.
+// Operators reconnect, and from then on reach whoever serves the listener.
+func (h *operatorHub) closeAll() {
+	h.mu.Lock()
+	h.closed = true
+	sessions := make([]*operatorSession, 0, len(h.sessions))
+	for _, session := range h.sessions {
+		sessions = append(sessions, session)
+	}
+	h.mu.Unlock()
+
+	for _, session := range sessions {
+		session.close()
+	}
+	h.serving.Wait()
+}
+
+// broadcast queues the message for every connected operator and returns the
+// operators whose queue was full
+func (h *operatorHub) broadcast(message wsproto.Message) []types.OperatorId {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var dropped []types.OperatorId
+	for operatorId, session := range h.sessions {
+		if !session.queue(message) {
+			dropped = append(dropped, operatorId)
+		}
+	}
+	return dropped
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	// Operators aren't browsers; authentication is by signed challenge
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// operatorWsHandler upgrades an operator connection, authenticates it and
+// serves it until it closes
+func (a *Aggregator) operatorWsHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already replied
+		a.logger.Warn("Failed to upgrade operator websocket", "error", err)
+		return
+	}
+	conn.SetReadLimit(wsproto.MaxMessageBytes)
+
+	operatorId, err := a.authenticateOperatorConn(conn)
+	if err != nil {
+		a.logger.Warn("Operator websocket authentication failed", "remoteAddr", r.RemoteAddr, "error", err)
+		conn.WriteControl(
+			websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error()),
+			time.Now().Add(time.Second),
+		)
+		conn.Close()
+		return
+	}
+
+	session := &operatorSession{
+		operatorId: operatorId,
+		conn:       conn,
+		send:       make(chan wsproto.Message, operatorSessionBuffer),
+		done:       make(chan struct{}),
+	}
+	a.operatorHub.add(session)
+	defer a.operatorHub.remove(session)
+	defer session.close()
+
+	logger := a.logger.With("operatorId", formatOperatorId(operatorId))
+	logger.Info("Operator connected over websocket", "remoteAddr", r.RemoteAddr)
+
+	go a.writeOperatorSession(session, logger)
+	a.readOperatorSession(r.Context(), session, logger)
+
+	logger.Info("Operator websocket closed")
+}
+
+// authenticateOperatorConn runs the challenge exchange and returns the
+// operator the connection belongs to
+func (a *Aggregator) authenticateOperatorConn(conn *websocket.Conn) (types.OperatorId, error) {
+	var operatorId types.OperatorId
+
+	challenge, err := wsproto.NewChallenge()
+	if err != nil {
+		return operatorId, err
+	}
+	message, err := wsproto.NewMessage(wsproto.TypeChallenge, 0, challenge)
+	if err != nil {
+		return operatorId, err
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(wsproto.AuthTimeout))
+	if err := conn.WriteJSON(message); err != nil {
+		return operatorId, fmt.Errorf("failed to send challenge: %w", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(wsproto.AuthTimeout))
+	var reply wsproto.Message
+	if err := conn.ReadJSON(&reply); err != nil {
+		return operatorId, fmt.Errorf("failed to read auth message: %w", err)
+	}
+	if reply.Type != wsproto.TypeAuth {
+		return operatorId, fmt.Errorf("expected %s message, got %q", wsproto.TypeAuth, reply.Type)
+	}
+
+	var auth wsproto.Auth
+	if err := json.Unmarshal(reply.Payload, &auth); err != nil {
+		return operatorId, fmt.Errorf("invalid auth payload: %w", err)
+	}
+	operatorId = types.OperatorId(auth.OperatorId)
+
+	signer, err := wsproto.RecoverSigner(challenge, auth.Signature)
+	if err != nil {
+		return operatorId, err
+	}
+
+	// The signer must be the operator's registered address
+	a.operators.mu.RLock()
+	registered := false
+	for _, operator := range a.operators.registered {
+		if operator.OperatorId == operatorId && operator.Address == signer {
+			registered = true
+			break
+		}
+	}
+	a.operators.mu.RUnlock()
+
+	if !registered {
+		return operatorId, fmt.Errorf("%w: %s signed by %s", ErrOperatorNotRegistered, formatOperatorId(operatorId), signer.Hex())
+	}
+	if a.banList.IsBanned(operatorId) {
+		return operatorId, ErrOperatorBanned
+	}
+	if err := a.checkAllowlist(operatorId); err != nil {
+		return operatorId, err
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(wsproto.AuthTimeout))
+	if err := conn.WriteJSON(wsproto.Message{Type: wsproto.TypeAuthenticated}); err != nil {
+		return operatorId, fmt.Errorf("failed to confirm authentication: %w", err)
+	}
+
+	return operatorId, nil
+}
+
+// readOperatorSession handles task responses until the connection fails
+func (a *Aggregator) readOperatorSession(ctx context.Context, session *operatorSession, logger logging.Logger) {
+	conn := session.conn
+	conn.SetReadDeadline(time.Now().Add(wsproto.PongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsproto.PongTimeout))
+	})
+
+	for {
+		var message wsproto.Message
+		if err := conn.ReadJSON(&message); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Debug("Operator websocket read failed", "error", err)
+			}
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(wsproto.PongTimeout))
+
+		if message.Type != wsproto.TypeTaskResponse {
+			logger.Warn("Ignoring unexpected websocket message", "type", message.Type)
+			continue
+		}
+
+		reply := a.handleOperatorTaskResponse(ctx, session, message)
+		if !session.queue(reply) {
+			logger.Warn("Dropped task response reply, operator send queue is full", "id", message.Id)
+		}
+	}
+}
+
+// handleOperatorTaskResponse processes a task response received over the
+// WebSocket and returns the ack or error to send back
+func (a *Aggregator) handleOperatorTaskResponse(ctx context.Context, session *operatorSession, message wsproto.Message) wsproto.Message {
+	reply := wsproto.Message{Type: wsproto.TypeError, Id: message.Id}
+
+	var signedResponse SignedTaskResponse
+	if err := json.Unmarshal(message.Payload, &signedResponse); err != nil {
+		reply.Error = "invalid task response"
+		reply.Rejected = true
+		return reply
+	}
+
+	// A session may only submit its own responses
+	if signedResponse.OperatorId != session.operatorId {
+		reply.Error = "operator id does not match the authenticated operator"
+		reply.Rejected = true
+		return reply
+	}
+
+	responseDigest, err := a.processTaskResponse(ctx, signedResponse, true)
+	if err != nil {
+		reply.Error = err.Error()
+		// Mirror the HTTP API: capacity errors are worth retrying, the rest aren't
+		reply.Rejected = errors.Is(err, ErrOperatorBanned) || errors.Is(err, ErrOperatorNotRegistered) || errors.Is(err, ErrUnknownTask) ||
+			errors.Is(err, ErrInvalidSignature) || errors.Is(err, ErrSignatureRejected) ||
+			errors.Is(err, ErrNotInCommittee) || errors.Is(err, ErrConflictingResponse) || errors.Is(err, ErrTaskCompleted) ||
+			errors.Is(err, ErrTaskExpired)
+		return reply
+	}
+
+	reply.Type = wsproto.TypeAck
+
+	signedAck, err := a.signAck(ctx, signedResponse, responseDigest)
+	if err != nil {
+		a.logger.Error("Failed to sign task response ack", "error", err)
+		return reply
+	}
+	if signedAck != nil {
+		if payload, err := json.Marshal(signedAck); err == nil {
+			reply.Payload = payload
+		}
+	}
+	return reply
+}
+
+// writeOperatorSession writes queued messages and keepalive pings until the session closes
+func (a *Aggregator) writeOperatorSession(session *operatorSession, logger logging.Logger) {
+	ticker := time.NewTicker(wsproto.PingInterval)
+	defer ticker.Stop()
+	defer session.close()
+
+	for {
+		select {
+		case <-session.done:
+			return
+		case message := <-session.send:
+			session.conn.SetWriteDeadline(time.Now().Add(wsproto.PingInterval))
+			if err := session.conn.WriteJSON(message); err != nil {
+				logger.Debug("Operator websocket write failed", "error", err)
+				return
+			}
+		case <-ticker.C:
+			if err := session.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsproto.PingInterval)); err != nil {
+				logger.Debug("Operator websocket ping failed", "error", err)
+				return
+			}
+		}
+	}
+}
+
+// BroadcastTask pushes a new task to every operator connected over WebSocket
+func (a *Aggregator) BroadcastTask(task wsproto.Task) error {
+	message, err := wsproto.NewMessage(wsproto.TypeTask, 0, task)
+	if err != nil {
+		return err
+	}
+
+	for _, operatorId := range a.operatorHub.broadcast(message) {
+		a.logger.Warn("Dropped task push, operator send queue is full",
+			"taskIndex", task.TaskIndex,
+			"operatorId", formatOperatorId(operatorId),
+		)
+	}
+	if a.grpc != nil {
+		if dropped := a.grpc.broadcast(task); dropped > 0 {
+			a.logger.Warn("Dropped task push to gRPC streams with full queues",
+				"taskIndex", task.TaskIndex,
+				"streams", dropped,
+			)
+		}
+	}
+	return nil
+}
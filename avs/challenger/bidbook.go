@@ -0,0 +1,108 @@
+package challenger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/eigenlvr/avs/pkg/apiversion"
+	"github.com/eigenlvr/avs/pkg/auction"
+	"github.com/eigenlvr/avs/pkg/challengereport"
+)
+
+const defaultAggregatorTimeout = 10 * time.Second
+
+// ErrBidBookUnavailable is returned when the aggregator doesn't run auctions
+var ErrBidBookUnavailable = errors.New("aggregator does not run auctions")
+
+// bidBookClient reads bid books from the aggregator's HTTP API and reports
+// challenges to it
+type bidBookClient struct {
+	baseUrl    string
+	httpClient *http.Client
+}
+
+func newBidBookClient(serverIpPortAddr string, timeout time.Duration) *bidBookClient {
+	if timeout == 0 {
+		timeout = defaultAggregatorTimeout
+	}
+	baseUrl := serverIpPortAddr
+	if !strings.HasPrefix(baseUrl, "http://") && !strings.HasPrefix(baseUrl, "https://") {
+		baseUrl = "http://" + baseUrl
+	}
+	return &bidBookClient{
+		baseUrl:    strings.TrimRight(baseUrl, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Bids reads the pool's auction for the block from the aggregator's bid book.
+// It returns ErrBidBookUnavailable when the aggregator doesn't run auctions.
+func (c *bidBookClient) Bids(ctx context.Context, poolId common.Hash, blockNumber uint32) (auction.Book, error) {
+	path := fmt.Sprintf("/bids/%s/%d", poolId.Hex(), blockNumber)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseUrl+apiversion.Path(path), nil)
+	if err != nil {
+		return auction.Book{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return auction.Book{}, fmt.Errorf("failed to reach aggregator: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotImplemented || (resp.StatusCode == http.StatusNotFound && apiversion.IsUnversioned(resp)) {
+		return auction.Book{}, ErrBidBookUnavailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return auction.Book{}, fmt.Errorf("aggregator returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	var book auction.Book
+	if err := json.NewDecoder(resp.Body).Decode(&book); err != nil {
+		return auction.Book{}, fmt.Errorf("failed to decode bid book: %w", err)
+	}
+	return book, nil
+}
+
+// ReportChallenge records the signed challenge and its reason with the
+// aggregator, which reviews it against the responses it aggregated
+func (c *bidBookClient) ReportChallenge(ctx context.Context, report challengereport.SignedReport) error {
+	body, err := json.Marshal(struct {
+		Challenger common.Address `json:"challenger"`
+		Reason     string         `json:"reason"`
+		Signature  hexutil.Bytes  `json:"signature"`
+	}{report.Challenger, report.Reason, report.Signature})
+	if err != nil {
+		return fmt.Errorf("failed to encode challenge: %w", err)
+	}
+
+	path := fmt.Sprintf("/task/%d/challenge", report.TaskIndex)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseUrl+apiversion.Path(path), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach aggregator: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("aggregator returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
@@ -0,0 +1,519 @@
+// Package challenger watches aggregated responses land on chain and
+// challenges those whose auction outcome doesn't match the one recomputed
+// from the aggregator's bid book. It settles auctions with the same engine
+// operators sign from, so an honest quorum and the challenger always agree.
+package challenger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/metrics"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/auction"
+	"github.com/eigenlvr/avs/pkg/challengereport"
+	"github.com/eigenlvr/avs/pkg/ecdsakey"
+	"github.com/eigenlvr/avs/pkg/escrow"
+	"github.com/eigenlvr/avs/pkg/logwatcher"
+	"github.com/eigenlvr/avs/pkg/networks"
+	"github.com/eigenlvr/avs/pkg/passphrase"
+	"github.com/eigenlvr/avs/pkg/poolregistry"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+	"github.com/eigenlvr/avs/pkg/sigchecker"
+)
+
+const (
+	// maxTrackedTasks bounds the created tasks kept for their responses
+	maxTrackedTasks = 4096
+	// checkTimeout bounds recomputing one response's auction
+	checkTimeout = 30 * time.Second
+	// challengeTimeout bounds sending a challenge and waiting for it to be mined
+	challengeTimeout = 5 * time.Minute
+	chainIdTimeout   = 10 * time.Second
+)
+
+// Results of checking a response
+const (
+	ResultMatched     = "matched"
+	ResultDiverged    = "diverged"
+	ResultUnknownTask = "unknown_task"
+	ResultNoBidBook   = "no_bid_book"
+	ResultError       = "error"
+)
+
+// Results of raising a challenge
+const (
+	ChallengeSent         = "sent"
+	ChallengeDryRun       = "dry_run"
+	ChallengeWindowClosed = "window_closed"
+	ChallengeFailed       = "failed"
+	ChallengeReverted     = "reverted"
+)
+
+// Config configures the challenger
+type Config struct {
+	// Chain
+	ChainId               uint64 `json:"chain_id"`
+	EthRpcUrl             string `json:"eth_rpc_url"`
+	EthWsUrl              string `json:"eth_ws_url"`
+	ServiceManagerAddress string `json:"service_manager_address"`
+	CheckpointPath        string `json:"checkpoint_path"`
+	PollInterval          string `json:"poll_interval"`
+
+	// Bids are read from the aggregator's bid book
+	AggregatorServerIpPortAddr string `json:"aggregator_server_ip_port_address"`
+	AggregatorTimeout          string `json:"aggregator_timeout"`
+
+	// Auction engine, configured as the operators are
+	AuctionEscrowAddress string `json:"auction_escrow_address"`
+	AuctionRulesVersion  uint32 `json:"auction_rules_version"`
+	AuctionMinBid        string `json:"auction_min_bid"`
+	AuctionReservePrice  string `json:"auction_reserve_price"`
+	PoolsFile            string `json:"pools_file"`
+
+	// Challenging
+	DryRun             bool `json:"dry_run"`
+	ReportToAggregator bool `json:"report_to_aggregator"`
+
+	// Signer sending challenges; not needed in dry run
+	EcdsaSigner                 string `json:"ecdsa_signer"`
+	EcdsaPrivateKeyStorePath    string `json:"ecdsa_private_key_store_path"`
+	EcdsaPrivateKeyPasswordFile string `json:"ecdsa_private_key_password_file"`
+	EcdsaSignerUrl              string `json:"ecdsa_signer_url"`
+	EcdsaSignerKeyId            string `json:"ecdsa_signer_key_id"`
+	EcdsaSignerAddress          string `json:"ecdsa_signer_address"`
+
+	// Metrics
+	EnableMetrics             bool   `json:"enable_metrics"`
+	EigenMetricsIpPortAddress string `json:"eigen_metrics_ip_port_address"`
+}
+
+// Challenger recomputes auctions behind aggregated responses and challenges
+// the ones that diverge
+type Challenger struct {
+	config    Config
+	logger    logging.Logger
+	ethClient eth.Client
+	watcher   *logwatcher.Watcher
+	reader    *servicemanager.Reader
+	bidBook   *bidBookClient
+	escrow    *escrow.Reader
+	rules     auction.Rules
+	pools     *poolregistry.Registry
+	signer    remotesigner.Signer
+	chainId   *big.Int
+
+	// tasks are the created tasks awaiting their responses, oldest first in
+	// order. Only the watcher's handler touches them.
+	tasks map[uint32]servicemanager.AuctionTask
+	order []uint32
+
+	registry     *prometheus.Registry
+	eigenMetrics *metrics.EigenMetrics
+	checked      *prometheus.CounterVec
+	challenges   *prometheus.CounterVec
+}
+
+// NewChallenger creates a challenger from a validated config
+func NewChallenger(config Config, logger logging.Logger) (*Challenger, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	logger = logger.With("component", "challenger")
+
+	ethClient, err := eth.NewClient(config.EthRpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create eth client: %w", err)
+	}
+
+	rules, err := auction.ParseRules(config.AuctionRulesVersion, config.AuctionMinBid, config.AuctionReservePrice)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auction rules: %w", err)
+	}
+	pools, err := loadPoolRegistry(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var escrowReader *escrow.Reader
+	if config.AuctionEscrowAddress != "" {
+		escrowReader, err = escrow.NewReader(common.HexToAddress(config.AuctionEscrowAddress), ethClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create escrow reader: %w", err)
+		}
+	}
+
+	var aggregatorTimeout time.Duration
+	if config.AggregatorTimeout != "" {
+		aggregatorTimeout, err = time.ParseDuration(config.AggregatorTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid aggregator timeout: %w", err)
+		}
+	}
+
+	var pollInterval time.Duration
+	if config.PollInterval != "" {
+		pollInterval, err = time.ParseDuration(config.PollInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid poll interval: %w", err)
+		}
+	}
+
+	c := &Challenger{
+		config:    config,
+		logger:    logger,
+		ethClient: ethClient,
+		reader:    servicemanager.NewReader(common.HexToAddress(config.ServiceManagerAddress), ethClient),
+		bidBook:   newBidBookClient(config.AggregatorServerIpPortAddr, aggregatorTimeout),
+		escrow:    escrowReader,
+		rules:     rules,
+		pools:     pools,
+		tasks:     make(map[uint32]servicemanager.AuctionTask),
+		checked: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "challenger",
+			Name:      "responses_checked_total",
+			Help:      "Aggregated responses checked against the recomputed auction, by result",
+		}, []string{"result"}),
+		challenges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "challenger",
+			Name:      "challenges_total",
+			Help:      "Challenges raised against diverging responses, by result",
+		}, []string{"result"}),
+	}
+
+	if !config.DryRun {
+		c.signer, err = newEcdsaSigner(config)
+		if err != nil {
+			return nil, err
+		}
+		chainIdCtx, cancel := context.WithTimeout(context.Background(), chainIdTimeout)
+		c.chainId, err = networks.ResolveChainId(chainIdCtx, ethClient, "", config.ChainId)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Challenger address", "address", c.signer.Address().Hex())
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(c.checked, c.challenges)
+	c.registry = reg
+	if config.EnableMetrics {
+		c.eigenMetrics = metrics.NewEigenMetrics("eigenlvr", config.EigenMetricsIpPortAddress, reg, logger)
+	}
+
+	c.watcher = logwatcher.NewWatcher(
+		logwatcher.Config{
+			Name:         "responses",
+			Addresses:    []common.Address{common.HexToAddress(config.ServiceManagerAddress)},
+			Topics:       [][]common.Hash{{servicemanager.NewAuctionTaskCreatedTopic, servicemanager.AuctionTaskRespondedTopic}},
+			WsUrl:        config.EthWsUrl,
+			PollInterval: pollInterval,
+		},
+		ethClient,
+		logwatcher.NewFileCheckpoint(config.CheckpointPath),
+		reg,
+		logger,
+	)
+	return c, nil
+}
+
+// loadPoolRegistry returns nil when no pools file is configured
+func loadPoolRegistry(config Config, logger logging.Logger) (*poolregistry.Registry, error) {
+	if config.PoolsFile == "" {
+		return nil, nil
+	}
+	pools, unknown, err := poolregistry.Load(config.PoolsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pools file: %w", err)
+	}
+	for _, field := range unknown {
+		logger.Warn("Ignoring unknown pools file field", "file", config.PoolsFile, "field", field.Path, "line", field.Line, "column", field.Column)
+	}
+	return pools, nil
+}
+
+// newEcdsaSigner returns the signer challenges are sent from: the remote
+// signer EcdsaSigner selects, or the local key file
+func newEcdsaSigner(config Config) (remotesigner.Signer, error) {
+	if config.EcdsaSigner == "" || config.EcdsaSigner == remotesigner.KindLocal {
+		key, err := ecdsakey.Load(config.EcdsaPrivateKeyStorePath, passphrase.Lookup(config.EcdsaPrivateKeyPasswordFile, passphrase.EcdsaEnv))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load challenger ecdsa private key: %w", err)
+		}
+		return remotesigner.NewLocal(key), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remotesigner.DefaultTimeout)
+	defer cancel()
+
+	signer, err := remotesigner.New(ctx, remotesigner.Config{
+		Kind:    config.EcdsaSigner,
+		Url:     config.EcdsaSignerUrl,
+		KeyId:   config.EcdsaSignerKeyId,
+		Address: common.HexToAddress(config.EcdsaSignerAddress),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to challenger ecdsa signer: %w", err)
+	}
+	return signer, nil
+}
+
+// Start checks every aggregated response until the context is done
+func (c *Challenger) Start(ctx context.Context) error {
+	if c.eigenMetrics != nil {
+		metricsErrs := c.eigenMetrics.Start(ctx, c.registry)
+		go func() {
+			if err, ok := <-metricsErrs; ok && err != nil {
+				c.logger.Error("Metrics server stopped", "error", err)
+			}
+		}()
+	}
+	c.logger.Info("Watching aggregated responses",
+		"serviceManager", c.config.ServiceManagerAddress,
+		"aggregator", c.config.AggregatorServerIpPortAddr,
+		"dryRun", c.config.DryRun,
+	)
+
+	err := c.watcher.Run(ctx, func(log gethtypes.Log) error {
+		switch log.Topics[0] {
+		case servicemanager.NewAuctionTaskCreatedTopic:
+			event, err := servicemanager.ParseNewAuctionTaskCreated(log)
+			if err != nil {
+				c.logger.Warn("Skipping undecodable task", "txHash", log.TxHash.Hex(), "error", err)
+				return nil
+			}
+			c.trackTask(event.TaskIndex, event.Task)
+		case servicemanager.AuctionTaskRespondedTopic:
+			event, err := servicemanager.ParseAuctionTaskResponded(log)
+			if err != nil {
+				c.logger.Warn("Skipping undecodable response", "txHash", log.TxHash.Hex(), "error", err)
+				return nil
+			}
+			c.checkResponse(ctx, event)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("response watcher stopped: %w", err)
+	}
+	return nil
+}
+
+// trackTask keeps the task for its response, forgetting the oldest task
+// once maxTrackedTasks are kept
+func (c *Challenger) trackTask(taskIndex uint32, task servicemanager.AuctionTask) {
+	if _, ok := c.tasks[taskIndex]; !ok {
+		c.order = append(c.order, taskIndex)
+	}
+	c.tasks[taskIndex] = task
+	for len(c.order) > maxTrackedTasks {
+		delete(c.tasks, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+// checkResponse recomputes the response's auction and challenges it when
+// the outcomes differ
+func (c *Challenger) checkResponse(ctx context.Context, event *servicemanager.AuctionTaskResponded) {
+	response := event.TaskResponse
+	taskIndex := response.ReferenceTaskIndex
+	task, ok := c.tasks[taskIndex]
+	if !ok {
+		// Tasks created before the checkpoint can't be recomputed
+		c.logger.Debug("Skipping response to an untracked task", "taskIndex", taskIndex)
+		c.checked.WithLabelValues(ResultUnknownTask).Inc()
+		return
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+	outcome, err := c.recompute(checkCtx, task)
+	cancel()
+	if errors.Is(err, ErrBidBookUnavailable) {
+		c.logger.Debug("Aggregator runs no auctions, skipping response", "taskIndex", taskIndex)
+		c.checked.WithLabelValues(ResultNoBidBook).Inc()
+		return
+	}
+	if err != nil {
+		c.logger.Error("Failed to recompute auction", "taskIndex", taskIndex, "error", err)
+		c.checked.WithLabelValues(ResultError).Inc()
+		return
+	}
+
+	reason := divergence(response, outcome)
+	if reason == "" {
+		c.checked.WithLabelValues(ResultMatched).Inc()
+		return
+	}
+	c.checked.WithLabelValues(ResultDiverged).Inc()
+	c.logger.Warn("Aggregated response diverges from the recomputed auction",
+		"taskIndex", taskIndex,
+		"reason", reason,
+		"txHash", event.Raw.TxHash.Hex(),
+	)
+	c.challenge(ctx, task, event, reason)
+	delete(c.tasks, taskIndex)
+}
+
+// recompute settles the task's auction from the aggregator's bid book with
+// the pool's rules and escrow as of the task's creation, as operators do
+func (c *Challenger) recompute(ctx context.Context, task servicemanager.AuctionTask) (auction.Settlement, error) {
+	poolId := common.Hash(task.PoolId)
+	blockNumber := uint32(task.BlockNumber.Uint64())
+
+	book, err := c.bidBook.Bids(ctx, poolId, blockNumber)
+	if err != nil {
+		return auction.Settlement{}, err
+	}
+
+	rules := c.rules
+	if pool, err := c.pools.Pool(poolId); err == nil && pool.ReservePrice != nil {
+		rules.ReservePrice = pool.ReservePrice
+	}
+
+	var escrowed auction.EscrowCheck
+	if c.escrow != nil {
+		escrowed = func(ctx context.Context, bidder common.Address, amount *big.Int) error {
+			if amount == nil || amount.Sign() == 0 {
+				return nil
+			}
+			return c.escrow.Verify(ctx, bidder, amount, task.TaskCreatedBlock)
+		}
+	}
+	return auction.Settle(ctx, poolId, blockNumber, book, rules, escrowed)
+}
+
+// divergence describes how the response differs from the outcome, empty
+// when they match
+func divergence(response servicemanager.AuctionTaskResponse, outcome auction.Settlement) string {
+	if response.Winner != outcome.Winner {
+		return fmt.Sprintf("winner %s, recomputed %s", response.Winner.Hex(), outcome.Winner.Hex())
+	}
+	if response.WinningBid == nil || response.WinningBid.Cmp(outcome.WinningBid) != 0 {
+		return fmt.Sprintf("winning bid %s, recomputed %s", response.WinningBid, outcome.WinningBid)
+	}
+	if response.TotalBids == nil || !response.TotalBids.IsUint64() || response.TotalBids.Uint64() != uint64(outcome.TotalBids) {
+		return fmt.Sprintf("total bids %s, recomputed %d", response.TotalBids, outcome.TotalBids)
+	}
+	return ""
+}
+
+// challenge raises a challenge against the task's recorded response on
+// chain while its challenge window is open, unless this is a dry run, and
+// reports it to the aggregator when configured
+func (c *Challenger) challenge(ctx context.Context, task servicemanager.AuctionTask, event *servicemanager.AuctionTaskResponded, reason string) {
+	ctx, cancel := context.WithTimeout(ctx, challengeTimeout)
+	defer cancel()
+	taskIndex := event.TaskResponse.ReferenceTaskIndex
+
+	open, err := c.challengeWindowOpen(ctx, event.TaskResponseMetadata)
+	if err != nil {
+		c.logger.Error("Failed to read the task's challenge window", "taskIndex", taskIndex, "error", err)
+		c.challenges.WithLabelValues(ChallengeFailed).Inc()
+		return
+	}
+	if !open {
+		c.logger.Info("Task's challenge window has closed", "taskIndex", taskIndex, "respondedBlock", event.TaskResponseMetadata.TaskResponsedBlock)
+		c.challenges.WithLabelValues(ChallengeWindowClosed).Inc()
+		return
+	}
+	if c.config.DryRun {
+		c.logger.Info("Dry run, not challenging task", "taskIndex", taskIndex, "reason", reason)
+		c.challenges.WithLabelValues(ChallengeDryRun).Inc()
+		return
+	}
+
+	// The challenge carries the non-signers the response was checked with,
+	// read back from the response transaction's calldata
+	nonSigners, err := c.nonSignerPubkeys(ctx, event.Raw.TxHash)
+	if err != nil {
+		c.logger.Error("Failed to read the response's non-signers", "taskIndex", taskIndex, "txHash", event.Raw.TxHash.Hex(), "error", err)
+		c.challenges.WithLabelValues(ChallengeFailed).Inc()
+		return
+	}
+
+	opts := remotesigner.TransactOpts(ctx, c.signer, c.chainId)
+	tx, err := servicemanager.RaiseAndResolveChallenge(opts, c.ethClient, c.reader.Address(), task, event.TaskResponse, event.TaskResponseMetadata, nonSigners)
+	if err != nil {
+		c.logger.Error("Failed to challenge task", "taskIndex", taskIndex, "error", err)
+		c.challenges.WithLabelValues(ChallengeFailed).Inc()
+		return
+	}
+	receipt, err := bind.WaitMined(ctx, c.ethClient, tx)
+	if err != nil {
+		c.logger.Error("Failed to wait for challenge", "taskIndex", taskIndex, "txHash", tx.Hash().Hex(), "error", err)
+		c.challenges.WithLabelValues(ChallengeFailed).Inc()
+		return
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		c.logger.Error("Challenge reverted", "taskIndex", taskIndex, "txHash", tx.Hash().Hex())
+		c.challenges.WithLabelValues(ChallengeReverted).Inc()
+		return
+	}
+	c.logger.Info("Challenged task", "taskIndex", taskIndex, "txHash", tx.Hash().Hex())
+	c.challenges.WithLabelValues(ChallengeSent).Inc()
+
+	if c.config.ReportToAggregator {
+		c.reportChallenge(ctx, taskIndex, reason)
+	}
+}
+
+// challengeWindowOpen reports whether the current block is within the
+// service manager's TASK_CHALLENGE_WINDOW_BLOCK of the response
+func (c *Challenger) challengeWindowOpen(ctx context.Context, metadata servicemanager.TaskResponseMetadata) (bool, error) {
+	windowBlocks, err := c.reader.ChallengeWindowBlocks(ctx)
+	if err != nil {
+		return false, err
+	}
+	currentBlock, err := c.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get current block: %w", err)
+	}
+	// The challenge is mined in a later block than the current one at best
+	return currentBlock+1 <= uint64(metadata.TaskResponsedBlock)+uint64(windowBlocks), nil
+}
+
+// nonSignerPubkeys decodes the non-signer public keys from the
+// respondToAuctionTask transaction that recorded a response
+func (c *Challenger) nonSignerPubkeys(ctx context.Context, txHash common.Hash) ([]sigchecker.G1Point, error) {
+	tx, _, err := c.ethClient.TransactionByHash(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response transaction: %w", err)
+	}
+	if tx.To() == nil || *tx.To() != c.reader.Address() {
+		return nil, servicemanager.ErrNotResponse
+	}
+	_, _, nonSignerStakesAndSignature, err := servicemanager.UnpackRespondToAuctionTask(tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	return nonSignerStakesAndSignature.NonSignerPubkeys, nil
+}
+
+// reportChallenge signs the challenge and reports it to the aggregator
+func (c *Challenger) reportChallenge(ctx context.Context, taskIndex uint32, reason string) {
+	report, err := challengereport.Sign(ctx, challengereport.Report{
+		TaskIndex:  taskIndex,
+		Challenger: c.signer.Address(),
+		Reason:     reason,
+	}, c.signer)
+	if err != nil {
+		c.logger.Warn("Failed to sign challenge report", "taskIndex", taskIndex, "error", err)
+		return
+	}
+	if err := c.bidBook.ReportChallenge(ctx, report); err != nil {
+		c.logger.Warn("Failed to report challenge to aggregator", "taskIndex", taskIndex, "error", err)
+	}
+}
@@ -0,0 +1,73 @@
+package challenger
+
+import (
+	"strings"
+
+	"github.com/eigenlvr/avs/pkg/auction"
+	"github.com/eigenlvr/avs/pkg/configfile"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+)
+
+// Validate checks the config before anything is started, returning a
+// *configfile.ValidationError that lists every problem found
+func (config Config) Validate() error {
+	var problems configfile.Problems
+
+	if problems.Required("eth_rpc_url", config.EthRpcUrl) {
+		problems.URL("eth_rpc_url", config.EthRpcUrl, "http", "https", "ws", "wss")
+	}
+	problems.URL("eth_ws_url", config.EthWsUrl, "ws", "wss")
+	problems.RequiredAddress("service_manager_address", config.ServiceManagerAddress)
+	problems.Required("checkpoint_path", config.CheckpointPath)
+
+	// The client also takes a full URL
+	if problems.Required("aggregator_server_ip_port_address", config.AggregatorServerIpPortAddr) {
+		if strings.Contains(config.AggregatorServerIpPortAddr, "://") {
+			problems.URL("aggregator_server_ip_port_address", config.AggregatorServerIpPortAddr, "http", "https")
+		} else {
+			problems.HostPort("aggregator_server_ip_port_address", config.AggregatorServerIpPortAddr)
+		}
+	}
+
+	problems.Address("auction_escrow_address", config.AuctionEscrowAddress)
+	problems.File("pools_file", config.PoolsFile)
+	if _, err := auction.ParseRules(config.AuctionRulesVersion, config.AuctionMinBid, config.AuctionReservePrice); err != nil {
+		problems.Addf("invalid auction rules: %v", err)
+	}
+
+	if !config.DryRun {
+		switch config.EcdsaSigner {
+		case "", remotesigner.KindLocal:
+			if problems.Required("ecdsa_private_key_store_path", config.EcdsaPrivateKeyStorePath) {
+				problems.File("ecdsa_private_key_store_path", config.EcdsaPrivateKeyStorePath)
+			}
+		case remotesigner.KindWeb3Signer:
+			if problems.Required("ecdsa_signer_url", config.EcdsaSignerUrl) {
+				problems.URL("ecdsa_signer_url", config.EcdsaSignerUrl, "http", "https")
+			}
+			problems.RequiredAddress("ecdsa_signer_address", config.EcdsaSignerAddress)
+		case remotesigner.KindAwsKms, remotesigner.KindGcpKms:
+			problems.Required("ecdsa_signer_key_id", config.EcdsaSignerKeyId)
+			problems.Address("ecdsa_signer_address", config.EcdsaSignerAddress)
+		default:
+			problems.OneOf("ecdsa_signer", config.EcdsaSigner, remotesigner.KindLocal, remotesigner.KindWeb3Signer, remotesigner.KindAwsKms, remotesigner.KindGcpKms)
+		}
+		problems.File("ecdsa_private_key_password_file", config.EcdsaPrivateKeyPasswordFile)
+	}
+	if config.ReportToAggregator && config.DryRun {
+		problems.Addf("report_to_aggregator has nothing to report in dry_run")
+	}
+
+	if config.EnableMetrics && problems.Required("eigen_metrics_ip_port_address", config.EigenMetricsIpPortAddress) {
+		problems.HostPort("eigen_metrics_ip_port_address", config.EigenMetricsIpPortAddress)
+	}
+
+	for _, duration := range []struct{ key, value string }{
+		{"poll_interval", config.PollInterval},
+		{"aggregator_timeout", config.AggregatorTimeout},
+	} {
+		problems.Duration(duration.key, duration.value)
+	}
+
+	return problems.Err()
+}
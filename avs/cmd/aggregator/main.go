@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
@@ -12,6 +11,8 @@ import (
 
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/eigenlvr/avs/aggregator"
+	"github.com/eigenlvr/avs/pkg/configfile"
+	"github.com/eigenlvr/avs/pkg/redact"
 )
 
 var (
@@ -19,7 +20,15 @@ var (
 	help       = flag.Bool("help", false, "Show help")
 )
 
+// configFlags override any config key, over the file and the environment
+var configFlags = configfile.RegisterFlags(flag.CommandLine, &aggregator.Config{})
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "reports" {
+		runReports(os.Args[2:])
+		return
+	}
+
 	flag.Parse()
 
 	if *help {
@@ -31,14 +40,18 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create logger: %v", err)
 	}
+	logger = redact.NewLogger(logger)
 
 	logger.Info("Starting EigenLVR Aggregator")
 
 	// Load configuration
-	config, err := loadConfig(*configFile)
+	config, unknownFields, err := loadConfig(*configFile)
 	if err != nil {
 		logger.Fatal("Failed to load config", "error", err)
 	}
+	for _, field := range unknownFields {
+		logger.Warn("Ignoring unknown config field", "file", *configFile, "field", field.Path, "line", field.Line, "column", field.Column)
+	}
 
 	// Create aggregator
 	agg, err := aggregator.NewAggregator(config, logger)
@@ -74,36 +87,63 @@ func main() {
 	logger.Info("Aggregator stopped gracefully")
 }
 
-func loadConfig(configPath string) (aggregator.Config, error) {
-	var config aggregator.Config
-
-	// Check if config file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		// Use default config if file doesn't exist
-		config = aggregator.Config{
-			ServerIpPortAddr:              "localhost:8090",
-			EthRpcUrl:                     "http://localhost:8545",
-			RegistryCoordinatorAddress:    "0x0000000000000000000000000000000000000000",
-			OperatorStateRetrieverAddress: "0x0000000000000000000000000000000000000000",
-			AggregatorPrivateKeyPath:      "./keys/aggregator.ecdsa.key.json",
-			EigenMetricsIpPortAddress:     "localhost:9092",
-			EnableMetrics:                 true,
+// loadConfig builds the config from the defaults, then the YAML or JSON file
+// at configPath if it exists, then EIGENLVR_* environment variables, then the
+// config flags given on the command line. Keys in the file that match no
+// field are returned.
+func loadConfig(configPath string) (aggregator.Config, []configfile.UnknownField, error) {
+	config := defaultConfig()
+
+	var unknown []configfile.UnknownField
+	if _, err := os.Stat(configPath); err == nil {
+		unknown, err = configfile.Load(configPath, "aggregator", &config)
+		if err != nil {
+			return config, unknown, fmt.Errorf("failed to load config: %w", err)
 		}
-		
-		return config, nil
+	} else if !os.IsNotExist(err) {
+		return config, nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Load from file
-	file, err := os.Open(configPath)
-	if err != nil {
-		return config, fmt.Errorf("failed to open config file: %w", err)
+	if _, err := configfile.ApplyEnv(&config); err != nil {
+		return config, unknown, fmt.Errorf("failed to apply environment overrides: %w", err)
 	}
-	defer file.Close()
-
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&config); err != nil {
-		return config, fmt.Errorf("failed to decode config: %w", err)
+	if _, err := configFlags.Apply(&config); err != nil {
+		return config, unknown, fmt.Errorf("failed to apply flag overrides: %w", err)
 	}
+	return config, unknown, nil
+}
 
-	return config, nil
-}
\ No newline at end of file
+// defaultConfig is the config used for any key neither the file nor the
+// environment sets
+func defaultConfig() aggregator.Config {
+	return aggregator.Config{
+		ServerIpPortAddr:              "localhost:8090",
+		EthRpcUrl:                     "http://localhost:8545",
+		RegistryCoordinatorAddress:    "0x0000000000000000000000000000000000000000",
+		OperatorStateRetrieverAddress: "0x0000000000000000000000000000000000000000",
+		AggregatorPrivateKeyPath:      "./keys/aggregator.ecdsa.key.json",
+		EigenMetricsIpPortAddress:     "localhost:9092",
+		EnableMetrics:                 true,
+		MinOperators:                  2,
+		MinTotalStake:                 "0",
+		BanListPath:                   "./data/banlist.json",
+		AutoBanInvalidSignatures:      3,
+		MaxOpenTasks:                  1000,
+		MaxResponsesPerTask:           256,
+		MaxRequestBodyBytes:           1 << 20,
+		TaskRetention:                 "1h",
+		TaskStorePath:                 "./data/tasks.db",
+		CheckpointInterval:            "15s",
+		SubmissionStuckAfter:          "30s",
+		SubmissionGasBumpPercent:      12,
+		SubmissionMaxGasBumps:         5,
+		ServiceManagerAddress:         "",
+		OperatorSetRefreshInterval:    "1m",
+		OperatorLivenessWindow:        "10m",
+		MetricsMaxPools:               20,
+		CorsMaxAge:                    "10m",
+		AggregationBackend:            "blsagg",
+		EthWsUrl:                      "ws://localhost:8546",
+		TaskExpiry:                    "6m",
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/eigenlvr/avs/aggregator"
+)
+
+// reportCsvHeader are the columns of CSV reports
+var reportCsvHeader = []string{
+	"pool_id", "epoch", "from_block", "to_block", "start_time", "end_time",
+	"tasks", "completed_tasks", "settled_tasks", "failed_settlements", "captured_auctions",
+	"total_bids", "unique_winners", "lvr_captured_wei", "average_winning_bid_wei", "max_winning_bid_wei",
+}
+
+// runReports implements the reports subcommand, which writes the LVR captured
+// per pool and epoch from the task store
+func runReports(args []string) {
+	flags := flag.NewFlagSet("reports", flag.ExitOnError)
+	configFile := flags.String("config", "config/aggregator.yaml", "Path to aggregator config file")
+	storePath := flags.String("store", "", "Task store to read, from a stopped aggregator or a copy (defaults to task_store_path)")
+	rpcUrl := flags.String("rpc", "", "RPC endpoint to check settlements and date epochs against (defaults to eth_rpc_url)")
+	offline := flags.Bool("offline", false, "Build the report from the task store alone, counting every submitted result as settled")
+	format := flags.String("format", "csv", "Output format, csv or json")
+	outPath := flags.String("out", "", "File to write the report to (defaults to stdout)")
+	epochBlocks := flags.Uint("epoch-blocks", aggregator.DefaultReportEpochBlocks, "Blocks per epoch, by task creation block")
+	pools := flags.String("pools", "", "Comma-separated pool ids to report on (defaults to every pool)")
+	fromBlock := flags.Uint("from-block", 0, "Only report tasks created at or after this block")
+	toBlock := flags.Uint("to-block", 0, "Only report tasks created at or before this block; 0 for no bound")
+	timeout := flags.Duration("timeout", 5*time.Minute, "Give up on chain lookups after this long")
+	flags.Parse(args)
+
+	if *format != "csv" && *format != "json" {
+		log.Fatalf("Unknown report format %q, expected csv or json", *format)
+	}
+
+	config, unknownFields, err := loadConfig(*configFile)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	for _, field := range unknownFields {
+		log.Printf("Ignoring unknown config field in %s: %s", *configFile, field)
+	}
+	if *storePath == "" {
+		*storePath = config.TaskStorePath
+	}
+	if *storePath == "" {
+		log.Fatalf("No task store to report on, set -store or task_store_path")
+	}
+
+	options := aggregator.ReportOptions{
+		EpochBlocks: uint32(*epochBlocks),
+		FromBlock:   uint32(*fromBlock),
+		ToBlock:     uint32(*toBlock),
+	}
+	if *pools != "" {
+		for _, pool := range strings.Split(*pools, ",") {
+			pool = strings.TrimSpace(pool)
+			if len(strings.TrimPrefix(pool, "0x")) != 2*common.HashLength {
+				log.Fatalf("Invalid pool id %q", pool)
+			}
+			options.Pools = append(options.Pools, common.HexToHash(pool))
+		}
+	}
+
+	store, err := aggregator.OpenBoltTaskStoreReadOnly(*storePath)
+	if err != nil {
+		log.Fatalf("Failed to open task store: %v", err)
+	}
+	defer store.Close()
+
+	tasks, err := store.ListArchivedTasks(aggregator.HistoryFilter{})
+	if err != nil {
+		log.Fatalf("Failed to read archived tasks: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var chain aggregator.ReportChain
+	if !*offline {
+		if *rpcUrl == "" {
+			*rpcUrl = config.EthRpcUrl
+		}
+		client, err := ethclient.DialContext(ctx, *rpcUrl)
+		if err != nil {
+			log.Fatalf("Failed to connect to %s, or pass -offline: %v", *rpcUrl, err)
+		}
+		defer client.Close()
+		chain = client
+	}
+
+	report, err := aggregator.BuildLvrReport(ctx, tasks, options, chain)
+	if err != nil {
+		log.Fatalf("Failed to build report: %v", err)
+	}
+
+	out := io.Writer(os.Stdout)
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			log.Fatalf("Failed to create report file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = writeReportCsv(out, report)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+}
+
+func writeReportCsv(out io.Writer, report *aggregator.LvrReport) error {
+	writer := csv.NewWriter(out)
+	if err := writer.Write(reportCsvHeader); err != nil {
+		return err
+	}
+
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+	for _, epoch := range report.Epochs {
+		err := writer.Write([]string{
+			epoch.PoolId.Hex(),
+			strconv.FormatUint(uint64(epoch.Epoch), 10),
+			strconv.FormatUint(uint64(epoch.FromBlock), 10),
+			strconv.FormatUint(uint64(epoch.ToBlock), 10),
+			formatTime(epoch.StartTime),
+			formatTime(epoch.EndTime),
+			strconv.Itoa(epoch.Tasks),
+			strconv.Itoa(epoch.CompletedTasks),
+			strconv.Itoa(epoch.SettledTasks),
+			strconv.Itoa(epoch.FailedSettlements),
+			strconv.Itoa(epoch.CapturedAuctions),
+			strconv.FormatUint(epoch.TotalBids, 10),
+			strconv.Itoa(epoch.UniqueWinners),
+			epoch.LvrCaptured.String(),
+			epoch.AverageWinningBid.String(),
+			epoch.MaxWinningBid.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/eigenlvr/avs/challenger"
+	"github.com/eigenlvr/avs/pkg/configfile"
+	"github.com/eigenlvr/avs/pkg/redact"
+)
+
+var (
+	configFile = flag.String("config", "config/challenger.yaml", "Path to challenger config file")
+	dryRun     = flag.Bool("dry-run", false, "Log diverging responses without challenging them")
+	help       = flag.Bool("help", false, "Show help")
+)
+
+// configFlags override any config key, over the file and the environment
+var configFlags = configfile.RegisterFlags(flag.CommandLine, &challenger.Config{})
+
+func main() {
+	flag.Parse()
+
+	if *help {
+		flag.Usage()
+		os.Exit(0)
+	}
+
+	logger, err := logging.NewZapLogger(logging.Development)
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	logger = redact.NewLogger(logger)
+
+	logger.Info("Starting EigenLVR Challenger")
+
+	config, unknownFields, err := loadConfig(*configFile)
+	if err != nil {
+		logger.Fatal("Failed to load config", "error", err)
+	}
+	for _, field := range unknownFields {
+		logger.Warn("Ignoring unknown config field", "file", *configFile, "field", field.Path, "line", field.Line, "column", field.Column)
+	}
+	if *dryRun {
+		config.DryRun = true
+		config.ReportToAggregator = false
+	}
+
+	c, err := challenger.NewChallenger(config, logger)
+	if err != nil {
+		logger.Fatal("Failed to create challenger", "error", err)
+	}
+
+	// Set up context for graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		sig := <-sigChan
+		logger.Info("Received shutdown signal", "signal", sig)
+		cancel()
+	}()
+
+	if err := c.Start(ctx); err != nil {
+		logger.Fatal("Challenger failed", "error", err)
+	}
+
+	logger.Info("Challenger stopped gracefully")
+}
+
+// loadConfig builds the config from the defaults, then the YAML or JSON file
+// at configPath if it exists, then EIGENLVR_* environment variables, then the
+// config flags given on the command line. Keys in the file that match no
+// field are returned.
+func loadConfig(configPath string) (challenger.Config, []configfile.UnknownField, error) {
+	config := defaultConfig()
+
+	var unknown []configfile.UnknownField
+	if _, err := os.Stat(configPath); err == nil {
+		unknown, err = configfile.Load(configPath, "challenger", &config)
+		if err != nil {
+			return config, unknown, fmt.Errorf("failed to load config: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return config, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if _, err := configfile.ApplyEnv(&config); err != nil {
+		return config, unknown, fmt.Errorf("failed to apply environment overrides: %w", err)
+	}
+	if _, err := configFlags.Apply(&config); err != nil {
+		return config, unknown, fmt.Errorf("failed to apply flag overrides: %w", err)
+	}
+	return config, unknown, nil
+}
+
+// defaultConfig is the config used for any key neither the file nor the
+// environment sets
+func defaultConfig() challenger.Config {
+	return challenger.Config{
+		EthRpcUrl:                  "http://localhost:8545",
+		CheckpointPath:             "./data/challenger.checkpoint",
+		AggregatorServerIpPortAddr: "localhost:8090",
+		AggregatorTimeout:          "10s",
+		AuctionRulesVersion:        1,
+		DryRun:                     true,
+		EcdsaPrivateKeyStorePath:   "./keys/challenger.ecdsa.key.json",
+		EnableMetrics:              true,
+		EigenMetricsIpPortAddress:  "localhost:9095",
+	}
+}
@@ -0,0 +1,222 @@
+// Command loadgen posts signed task responses from fixture operators to an
+// aggregator at a steady rate. With -soak it runs for hours while sampling
+// the aggregator's /debug/status, and fails if heap, goroutines or the task
+// map keep growing once the warmup is over.
+//
+// Fixture operators aren't registered on chain, so run it against an
+// aggregator using the builtin backend without a service_manager_address,
+// which opens tasks on their first response.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/eigenlvr/avs/pkg/apiversion"
+	"github.com/eigenlvr/avs/pkg/testutils/fixtures"
+)
+
+var (
+	aggregatorUrl = flag.String("aggregator", "http://localhost:8090", "Base URL of the aggregator")
+	seed          = flag.String("seed", "loadgen", "Seed the operators and tasks are derived from")
+	operatorCount = flag.Int("operators", 4, "Number of operators responding to each task")
+	rate          = flag.Float64("rate", 20, "Responses sent per second")
+	concurrency   = flag.Int("concurrency", 8, "Requests in flight at once")
+	duration      = flag.Duration("duration", time.Minute, "How long to generate load")
+	startTask     = flag.Uint("start-task", 0, "Index of the first task")
+
+	soak           = flag.Bool("soak", false, "Monitor the aggregator for leaks while generating load")
+	adminToken     = flag.String("admin-token", "", "Aggregator admin token, if /debug/status requires one")
+	sampleInterval = flag.Duration("sample-interval", 30*time.Second, "How often the aggregator's status is sampled")
+	warmup         = flag.Duration("warmup", 75*time.Minute, "Samples taken before this are ignored; keep it above the aggregator's task_retention, until which tasks legitimately accumulate")
+	maxHeapGrowth  = flag.Float64("max-heap-growth-mb", 32, "Largest tolerated heap growth, in MiB per hour")
+	maxGoroutines  = flag.Float64("max-goroutine-growth", 60, "Largest tolerated goroutine growth, per hour")
+	maxTaskGrowth  = flag.Float64("max-task-growth", 100, "Largest tolerated growth of tasks held in memory, per hour")
+)
+
+// requestTimeout bounds each response post
+const requestTimeout = 10 * time.Second
+
+// counters tallies the outcome of every request
+type counters struct {
+	sent     atomic.Uint64
+	accepted atomic.Uint64
+	rejected atomic.Uint64
+	failed   atomic.Uint64
+}
+
+func (c *counters) String() string {
+	return fmt.Sprintf("sent=%d accepted=%d rejected=%d failed=%d",
+		c.sent.Load(), c.accepted.Load(), c.rejected.Load(), c.failed.Load())
+}
+
+func main() {
+	flag.Parse()
+
+	logger, err := logging.NewZapLogger(logging.Development)
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	if *rate <= 0 || *concurrency <= 0 || *operatorCount <= 0 {
+		logger.Fatal("rate, concurrency and operators must be positive")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *duration)
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigChan
+		logger.Info("Received shutdown signal", "signal", sig)
+		cancel()
+	}()
+
+	baseUrl := strings.TrimSuffix(*aggregatorUrl, "/")
+	stats := &counters{}
+
+	var monitor *soakMonitor
+	if *soak {
+		if *duration < *warmup+3**sampleInterval {
+			logger.Fatal("Soak duration must leave at least three samples after the warmup",
+				"duration", *duration,
+				"warmup", *warmup,
+				"sampleInterval", *sampleInterval,
+			)
+		}
+		monitor = newSoakMonitor(baseUrl, *adminToken, soakLimits{
+			HeapBytesPerHour:  *maxHeapGrowth * (1 << 20),
+			GoroutinesPerHour: *maxGoroutines,
+			TasksPerHour:      *maxTaskGrowth,
+		}, logger)
+	}
+
+	logger.Info("Generating load",
+		"aggregator", baseUrl,
+		"operators", *operatorCount,
+		"rate", *rate,
+		"duration", *duration,
+		"soak", *soak,
+	)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		generate(ctx, baseUrl, stats, logger)
+	}()
+	if monitor != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			monitor.Run(ctx, *sampleInterval, *warmup, stats)
+		}()
+	}
+	wg.Wait()
+
+	logger.Info("Load finished", "results", stats.String())
+
+	if monitor != nil {
+		if err := monitor.Verdict(*warmup); err != nil {
+			logger.Error("Soak test failed", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Soak test passed")
+	}
+}
+
+// generate sends every operator's response to consecutive tasks at the
+// configured rate until ctx is done
+func generate(ctx context.Context, baseUrl string, stats *counters, logger logging.Logger) {
+	generator := fixtures.New(*seed)
+	operators := generator.Operators(*operatorCount)
+	client := &http.Client{Timeout: requestTimeout}
+	endpoint := baseUrl + apiversion.Path("/task-response")
+
+	requests := make(chan fixtures.SignedResponse)
+	var workers sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for response := range requests {
+				post(ctx, client, endpoint, response, stats, logger)
+			}
+		}()
+	}
+	defer workers.Wait()
+	defer close(requests)
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+	defer ticker.Stop()
+
+	for taskIndex := uint32(*startTask); ; taskIndex++ {
+		signed, err := generator.SignedResponses(generator.Task(taskIndex), operators)
+		if err != nil {
+			logger.Error("Failed to sign responses", "taskIndex", taskIndex, "error", err)
+			return
+		}
+
+		for _, response := range signed {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case requests <- response:
+			}
+		}
+	}
+}
+
+func post(ctx context.Context, client *http.Client, endpoint string, response fixtures.SignedResponse, stats *counters, logger logging.Logger) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		logger.Error("Failed to encode response", "error", err)
+		stats.failed.Add(1)
+		return
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		stats.failed.Add(1)
+		return
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	stats.sent.Add(1)
+	resp, err := client.Do(request)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("Request failed", "taskIndex", response.TaskResponse.ReferenceTaskIndex, "error", err)
+		}
+		stats.failed.Add(1)
+		return
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		stats.accepted.Add(1)
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		stats.failed.Add(1)
+	default:
+		stats.rejected.Add(1)
+	}
+}
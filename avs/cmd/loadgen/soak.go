@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/eigenlvr/avs/pkg/diagnostics"
+)
+
+// soakLimits are the largest growth rates tolerated after the warmup
+type soakLimits struct {
+	HeapBytesPerHour  float64
+	GoroutinesPerHour float64
+	TasksPerHour      float64
+}
+
+// sample is one reading of the aggregator's status
+type sample struct {
+	At         time.Time
+	HeapBytes  uint64
+	Goroutines int
+	Tasks      int
+}
+
+// soakMonitor samples the aggregator's /debug/status while load runs
+type soakMonitor struct {
+	statusUrl  string
+	adminToken string
+	limits     soakLimits
+	logger     logging.Logger
+	client     *http.Client
+
+	mu      sync.Mutex
+	started time.Time
+	samples []sample
+}
+
+func newSoakMonitor(baseUrl string, adminToken string, limits soakLimits, logger logging.Logger) *soakMonitor {
+	return &soakMonitor{
+		statusUrl:  baseUrl + "/debug/status",
+		adminToken: adminToken,
+		limits:     limits,
+		logger:     logger,
+		client:     &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Run samples the status every interval until ctx is done, logging the
+// current trend once the warmup is over
+func (m *soakMonitor) Run(ctx context.Context, interval time.Duration, warmup time.Duration, stats *counters) {
+	m.mu.Lock()
+	m.started = time.Now()
+	m.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := m.sample(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				m.logger.Warn("Failed to sample aggregator status", "error", err)
+			}
+			continue
+		}
+
+		m.mu.Lock()
+		m.samples = append(m.samples, current)
+		m.mu.Unlock()
+
+		m.logger.Info("Soak sample",
+			"elapsed", time.Since(m.started).Round(time.Second),
+			"heapBytes", current.HeapBytes,
+			"goroutines", current.Goroutines,
+			"tasks", current.Tasks,
+			"load", stats.String(),
+		)
+		if growth, ok := m.trend(warmup); ok {
+			m.logger.Info("Soak trend per hour",
+				"heapBytes", int64(growth.heapBytes),
+				"goroutines", growth.goroutines,
+				"tasks", growth.tasks,
+			)
+		}
+	}
+}
+
+func (m *soakMonitor) sample(ctx context.Context) (sample, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, m.statusUrl, nil)
+	if err != nil {
+		return sample{}, err
+	}
+	if m.adminToken != "" {
+		request.Header.Set("Authorization", "Bearer "+m.adminToken)
+	}
+
+	resp, err := m.client.Do(request)
+	if err != nil {
+		return sample{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return sample{}, fmt.Errorf("status endpoint returned %s", resp.Status)
+	}
+
+	var status diagnostics.Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return sample{}, fmt.Errorf("failed to decode status: %w", err)
+	}
+	return sample{
+		At:         time.Now(),
+		HeapBytes:  status.Memory.HeapInuseBytes,
+		Goroutines: status.Goroutines,
+		Tasks:      status.Depths["tasks"].Len,
+	}, nil
+}
+
+// trend is the growth per hour of each sampled value
+type trend struct {
+	heapBytes  float64
+	goroutines float64
+	tasks      float64
+}
+
+// trend fits a line through the samples taken after the warmup. It needs at
+// least three of them.
+func (m *soakMonitor) trend(warmup time.Duration) (trend, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cutoff := m.started.Add(warmup)
+	var hours, heap, goroutines, tasks []float64
+	for _, s := range m.samples {
+		if s.At.Before(cutoff) {
+			continue
+		}
+		hours = append(hours, s.At.Sub(cutoff).Hours())
+		heap = append(heap, float64(s.HeapBytes))
+		goroutines = append(goroutines, float64(s.Goroutines))
+		tasks = append(tasks, float64(s.Tasks))
+	}
+	if len(hours) < 3 {
+		return trend{}, false
+	}
+	return trend{
+		heapBytes:  slope(hours, heap),
+		goroutines: slope(hours, goroutines),
+		tasks:      slope(hours, tasks),
+	}, true
+}
+
+// Verdict fails the soak test if any value grew faster than its limit
+func (m *soakMonitor) Verdict(warmup time.Duration) error {
+	growth, ok := m.trend(warmup)
+	if !ok {
+		return errors.New("too few samples after the warmup to judge a trend")
+	}
+
+	var leaks []string
+	if growth.heapBytes > m.limits.HeapBytesPerHour {
+		leaks = append(leaks, fmt.Sprintf("heap grows %.1f MiB/h (limit %.1f)", growth.heapBytes/(1<<20), m.limits.HeapBytesPerHour/(1<<20)))
+	}
+	if growth.goroutines > m.limits.GoroutinesPerHour {
+		leaks = append(leaks, fmt.Sprintf("goroutines grow %.1f/h (limit %.1f)", growth.goroutines, m.limits.GoroutinesPerHour))
+	}
+	if growth.tasks > m.limits.TasksPerHour {
+		leaks = append(leaks, fmt.Sprintf("tasks in memory grow %.1f/h (limit %.1f)", growth.tasks, m.limits.TasksPerHour))
+	}
+	if len(leaks) > 0 {
+		return fmt.Errorf("leak trend: %s", strings.Join(leaks, "; "))
+	}
+	return nil
+}
+
+// slope is the least-squares slope of ys over xs
+func slope(xs []float64, ys []float64) float64 {
+	n := float64(len(xs))
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
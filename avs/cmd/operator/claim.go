@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/eigenlvr/avs/operator"
+	"github.com/eigenlvr/avs/pkg/pushmetrics"
+	"github.com/eigenlvr/avs/pkg/redact"
+	"github.com/eigenlvr/avs/pkg/rewards"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// runClaimRewards implements the claim-rewards subcommand
+func runClaimRewards(args []string) {
+	flags := flag.NewFlagSet("claim-rewards", flag.ExitOnError)
+	configFile := flags.String("config", "config/operator.yaml", "Path to operator config file")
+	recipient := flags.String("recipient", "", "Address to receive claimed rewards (defaults to rewards_claim_recipient or the operator address)")
+	maxGasPriceGwei := flags.Uint64("max-gas-price-gwei", 0, "Abort if the gas price is above this value (defaults to max_claim_gas_price_gwei)")
+	pushgatewayUrl := flags.String("pushgateway", "", "Pushgateway to report the run's metrics to (defaults to pushgateway_url)")
+	flags.Parse(args)
+
+	logger, err := logging.NewZapLogger(logging.Development)
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	logger = redact.NewLogger(logger)
+
+	config, unknownFields, err := loadConfig(*configFile)
+	if err != nil {
+		logger.Fatal("Failed to load config", "error", err)
+	}
+	for _, field := range unknownFields {
+		logger.Warn("Ignoring unknown config field", "file", *configFile, "field", field.Path, "line", field.Line, "column", field.Column)
+	}
+	if *recipient != "" {
+		config.RewardsClaimRecipient = *recipient
+	}
+	if *maxGasPriceGwei > 0 {
+		config.MaxClaimGasPriceGwei = *maxGasPriceGwei
+	}
+	if *pushgatewayUrl != "" {
+		config.PushgatewayUrl = *pushgatewayUrl
+	}
+
+	job := pushmetrics.NewJob("eigenlvr_claim_rewards")
+	claimedTokens := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "eigenlvr",
+		Name:      "rewards_claimed_tokens",
+		Help:      "Number of tokens claimed by the last claim-rewards run",
+	})
+	claimGasUsed := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "eigenlvr",
+		Name:      "rewards_claim_gas_used",
+		Help:      "Gas used by the last claim-rewards transaction",
+	})
+	job.Registry().MustRegister(claimedTokens, claimGasUsed)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	result, err := operator.ClaimRewards(ctx, config, logger)
+	if result != nil {
+		claimedTokens.Set(float64(len(result.Tokens)))
+		claimGasUsed.Set(float64(result.GasUsed))
+	}
+	if config.PushgatewayUrl != "" {
+		// Having nothing to claim is a successful run
+		jobErr := err
+		if errors.Is(err, rewards.ErrNothingToClaim) {
+			jobErr = nil
+		}
+		if pushErr := job.Push(context.Background(), config.PushgatewayUrl, jobErr); pushErr != nil {
+			logger.Warn("Failed to push metrics", "error", pushErr)
+		}
+	}
+
+	if errors.Is(err, rewards.ErrNothingToClaim) {
+		logger.Info("No rewards to claim")
+		os.Exit(0)
+	}
+	if err != nil {
+		logger.Fatal("Failed to claim rewards", "error", err)
+	}
+
+	logger.Info("Claimed rewards",
+		"txHash", result.TxHash.Hex(),
+		"recipient", result.Recipient.Hex(),
+		"tokens", len(result.Tokens),
+		"gasUsed", result.GasUsed,
+	)
+}
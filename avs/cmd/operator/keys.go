@@ -0,0 +1,323 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/blskey"
+	"github.com/eigenlvr/avs/pkg/ecdsakey"
+	"github.com/eigenlvr/avs/pkg/passphrase"
+)
+
+const (
+	// Key types the keys subcommand manages
+	keyTypeBls   = "bls"
+	keyTypeEcdsa = "ecdsa"
+)
+
+const keysUsage = `Usage: operator keys <command> [flags]
+
+Commands:
+  generate  create a new encrypted key
+  encrypt   encrypt a raw key, or re-encrypt a keystore with a new passphrase
+  inspect   print a key's address, or public keys and operator id
+  rotate    replace a key with a new one, keeping the old as a backup
+
+New passphrases are read from -password-file, else EIGENLVR_ECDSA_KEY_PASSWORD
+or EIGENLVR_BLS_KEY_PASSWORD, else prompted for. Run a command with -h for its
+flags.
+`
+
+// operatorKey is a decrypted key of either type
+type operatorKey struct {
+	bls   *bls.KeyPair
+	ecdsa *ecdsa.PrivateKey
+}
+
+// runKeys implements the keys subcommand
+func runKeys(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, keysUsage)
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "generate":
+		runKeysGenerate(args[1:])
+	case "encrypt":
+		runKeysEncrypt(args[1:])
+	case "inspect":
+		runKeysInspect(args[1:])
+	case "rotate":
+		runKeysRotate(args[1:])
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(os.Stdout, keysUsage)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown keys command %q\n\n%s", args[0], keysUsage)
+		os.Exit(2)
+	}
+}
+
+// keyFlags are the flags every keys command shares
+type keyFlags struct {
+	flags        *flag.FlagSet
+	configFile   *string
+	keyType      *string
+	passwordFile *string
+}
+
+func newKeyFlags(name string) keyFlags {
+	flags := flag.NewFlagSet("keys "+name, flag.ExitOnError)
+	return keyFlags{
+		flags:        flags,
+		configFile:   flags.String("config", "config/operator.yaml", "Operator config file the key paths and passphrase files default to"),
+		keyType:      flags.String("type", "", "Key type, bls or ecdsa"),
+		passwordFile: flags.String("password-file", "", "File holding the key's passphrase (defaults to the config's passphrase file)"),
+	}
+}
+
+// parse parses the flags and fills in the key path and passphrase file the
+// config sets for the key type
+func (f keyFlags) parse(args []string, keyPath *string) {
+	f.flags.Parse(args)
+
+	if *f.keyType != keyTypeBls && *f.keyType != keyTypeEcdsa {
+		log.Fatalf("Unknown key type %q, expected bls or ecdsa", *f.keyType)
+	}
+	if *keyPath != "" && *f.passwordFile != "" {
+		return
+	}
+
+	config, _, err := loadConfig(*f.configFile)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	defaultPath, defaultPasswordFile := config.EcdsaPrivateKeyStorePath, config.EcdsaPrivateKeyPasswordFile
+	if *f.keyType == keyTypeBls {
+		defaultPath, defaultPasswordFile = config.BlsPrivateKeyStorePath, config.BlsPrivateKeyPasswordFile
+	}
+	if *keyPath == "" {
+		*keyPath = defaultPath
+	}
+	if *f.passwordFile == "" {
+		*f.passwordFile = defaultPasswordFile
+	}
+}
+
+// passphraseEnv is the environment variable holding passphrases of the type
+func (f keyFlags) passphraseEnv() string {
+	if *f.keyType == keyTypeBls {
+		return passphrase.BlsEnv
+	}
+	return passphrase.EcdsaEnv
+}
+
+// newPassphrase returns the passphrase to encrypt a new keystore with. Empty
+// passphrases are only taken from a file or the environment, never a prompt.
+func (f keyFlags) newPassphrase() (string, error) {
+	if *f.passwordFile != "" {
+		return passphrase.ReadFile(*f.passwordFile)
+	}
+	if password, ok := os.LookupEnv(f.passphraseEnv()); ok {
+		return password, nil
+	}
+	if !passphrase.IsTerminal() {
+		return "", fmt.Errorf("%w, set %s or -password-file", passphrase.ErrUnavailable, f.passphraseEnv())
+	}
+	password, err := passphrase.PromptNew("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", errors.New("passphrase must not be empty")
+	}
+	return password, nil
+}
+
+func runKeysGenerate(args []string) {
+	f := newKeyFlags("generate")
+	outPath := f.flags.String("out", "", "Keystore to write (defaults to the config's key path)")
+	force := f.flags.Bool("force", false, "Overwrite an existing key")
+	f.parse(args, outPath)
+
+	if _, err := os.Stat(*outPath); err == nil && !*force {
+		log.Fatalf("%s already exists, use rotate to replace it", *outPath)
+	}
+
+	key, err := generateKey(*f.keyType)
+	if err != nil {
+		log.Fatalf("Failed to generate key: %v", err)
+	}
+	password, err := f.newPassphrase()
+	if err != nil {
+		log.Fatalf("Failed to get passphrase: %v", err)
+	}
+	if err := key.save(*outPath, password); err != nil {
+		log.Fatalf("Failed to save key: %v", err)
+	}
+
+	fmt.Printf("Wrote %s key to %s\n", *f.keyType, *outPath)
+	key.print()
+}
+
+func runKeysEncrypt(args []string) {
+	f := newKeyFlags("encrypt")
+	inPath := f.flags.String("in", "", "Raw key, hex for ECDSA or a decimal or 0x-hex scalar for BLS, or a keystore to re-encrypt")
+	outPath := f.flags.String("out", "", "Keystore to write (defaults to -in, replacing it)")
+	oldPasswordFile := f.flags.String("old-password-file", "", "File holding the passphrase of a keystore given as -in")
+	f.parse(args, inPath)
+	if *outPath == "" {
+		*outPath = *inPath
+	}
+
+	data, err := os.ReadFile(*inPath)
+	if err != nil {
+		log.Fatalf("Failed to read key: %v", err)
+	}
+	var key operatorKey
+	if ecdsakey.IsKeystore(data) {
+		key, err = loadKey(*f.keyType, *inPath, passphrase.Lookup(*oldPasswordFile, f.passphraseEnv()))
+	} else {
+		key, err = parseRawKey(*f.keyType, strings.TrimSpace(string(data)))
+	}
+	if err != nil {
+		log.Fatalf("Failed to read key: %v", err)
+	}
+
+	password, err := f.newPassphrase()
+	if err != nil {
+		log.Fatalf("Failed to get passphrase: %v", err)
+	}
+	if err := key.save(*outPath, password); err != nil {
+		log.Fatalf("Failed to save key: %v", err)
+	}
+
+	fmt.Printf("Wrote encrypted %s key to %s\n", *f.keyType, *outPath)
+	key.print()
+}
+
+func runKeysInspect(args []string) {
+	f := newKeyFlags("inspect")
+	keyPath := f.flags.String("key", "", "Key to inspect (defaults to the config's key path)")
+	f.parse(args, keyPath)
+
+	key, err := loadKey(*f.keyType, *keyPath, passphrase.Lookup(*f.passwordFile, f.passphraseEnv()))
+	if err != nil {
+		log.Fatalf("Failed to read key: %v", err)
+	}
+	key.print()
+}
+
+func runKeysRotate(args []string) {
+	f := newKeyFlags("rotate")
+	keyPath := f.flags.String("key", "", "Key to replace (defaults to the config's key path)")
+	f.parse(args, keyPath)
+
+	old, err := loadKey(*f.keyType, *keyPath, passphrase.Lookup(*f.passwordFile, f.passphraseEnv()))
+	if err != nil {
+		log.Fatalf("Failed to read current key: %v", err)
+	}
+	key, err := generateKey(*f.keyType)
+	if err != nil {
+		log.Fatalf("Failed to generate key: %v", err)
+	}
+	password, err := f.newPassphrase()
+	if err != nil {
+		log.Fatalf("Failed to get passphrase: %v", err)
+	}
+
+	backupPath := fmt.Sprintf("%s.%d.bak", *keyPath, time.Now().Unix())
+	if err := os.Rename(*keyPath, backupPath); err != nil {
+		log.Fatalf("Failed to back up current key: %v", err)
+	}
+	if err := key.save(*keyPath, password); err != nil {
+		log.Fatalf("Failed to save key, the current one is at %s: %v", backupPath, err)
+	}
+
+	fmt.Printf("Moved the current %s key to %s\n", *f.keyType, backupPath)
+	old.print()
+	fmt.Printf("Wrote the new key to %s\n", *keyPath)
+	key.print()
+	if *f.keyType == keyTypeBls {
+		fmt.Println("The operator id changes with the BLS key: register the new public key before restarting the operator.")
+	} else {
+		fmt.Println("The operator address changes with the ECDSA key: register it as an operator before restarting.")
+	}
+}
+
+func generateKey(keyType string) (operatorKey, error) {
+	if keyType == keyTypeBls {
+		keyPair, err := bls.GenRandomBlsKeys()
+		return operatorKey{bls: keyPair}, err
+	}
+	key, err := crypto.GenerateKey()
+	return operatorKey{ecdsa: key}, err
+}
+
+// loadKey decrypts a keystore. BLS keystores saved before passphrases were
+// supported are tried with the empty passphrase when none is configured.
+func loadKey(keyType, path string, lookup passphrase.Func) (operatorKey, error) {
+	if keyType == keyTypeEcdsa {
+		key, err := ecdsakey.Load(path, lookup)
+		return operatorKey{ecdsa: key}, err
+	}
+
+	// Check it is a BLS keystore before asking for its passphrase
+	if _, err := blskey.PublicKey(path); err != nil {
+		return operatorKey{}, err
+	}
+	password, err := lookup(path)
+	if errors.Is(err, passphrase.ErrUnavailable) {
+		password, err = "", nil
+	}
+	if err != nil {
+		return operatorKey{}, err
+	}
+	keyPair, err := blskey.Load(path, password)
+	return operatorKey{bls: keyPair}, err
+}
+
+func parseRawKey(keyType, raw string) (operatorKey, error) {
+	if keyType == keyTypeBls {
+		keyPair, err := bls.NewKeyPairFromString(raw)
+		if err != nil {
+			return operatorKey{}, fmt.Errorf("invalid bls private key: %w", err)
+		}
+		return operatorKey{bls: keyPair}, nil
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(raw, "0x"))
+	if err != nil {
+		return operatorKey{}, fmt.Errorf("invalid ecdsa private key: %w", err)
+	}
+	return operatorKey{ecdsa: key}, nil
+}
+
+func (k operatorKey) save(path, password string) error {
+	if k.bls != nil {
+		return blskey.Save(path, k.bls, password)
+	}
+	return ecdsakey.Save(path, k.ecdsa, password)
+}
+
+// print writes what identifies the key on chain
+func (k operatorKey) print() {
+	if k.bls != nil {
+		operatorId := types.OperatorIdFromG1Pubkey(k.bls.PubKey)
+		fmt.Printf("  operator id: 0x%s\n", hex.EncodeToString(operatorId[:]))
+		fmt.Printf("  g1 public key: %s\n", k.bls.PubKey.String())
+		fmt.Printf("  g2 public key: %s\n", k.bls.GetPubKeyG2().String())
+		return
+	}
+	fmt.Printf("  address: %s\n", crypto.PubkeyToAddress(k.ecdsa.PublicKey).Hex())
+}
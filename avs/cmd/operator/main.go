@@ -2,7 +2,6 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
@@ -12,6 +11,8 @@ import (
 
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/eigenlvr/avs/operator"
+	"github.com/eigenlvr/avs/pkg/configfile"
+	"github.com/eigenlvr/avs/pkg/redact"
 )
 
 var (
@@ -19,7 +20,21 @@ var (
 	help       = flag.Bool("help", false, "Show help")
 )
 
+// configFlags override any config key, over the file and the environment
+var configFlags = configfile.RegisterFlags(flag.CommandLine, &operator.Config{})
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "claim-rewards":
+			runClaimRewards(os.Args[2:])
+			return
+		case "keys":
+			runKeys(os.Args[2:])
+			return
+		}
+	}
+
 	flag.Parse()
 
 	if *help {
@@ -31,14 +46,18 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create logger: %v", err)
 	}
+	logger = redact.NewLogger(logger)
 
 	logger.Info("Starting EigenLVR Operator")
 
 	// Load configuration
-	config, err := loadConfig(*configFile)
+	config, unknownFields, err := loadConfig(*configFile)
 	if err != nil {
 		logger.Fatal("Failed to load config", "error", err)
 	}
+	for _, field := range unknownFields {
+		logger.Warn("Ignoring unknown config field", "file", *configFile, "field", field.Path, "line", field.Line, "column", field.Column)
+	}
 
 	// Create operator
 	op, err := operator.NewOperator(config, logger)
@@ -61,7 +80,7 @@ func main() {
 	}()
 
 	// Start operator
-	logger.Info("Starting operator with config", 
+	logger.Info("Starting operator with config",
 		"ethRpcUrl", config.EthRpcUrl,
 		"registryCoordinator", config.RegistryCoordinatorAddress,
 		"aggregatorAddr", config.AggregatorServerIpPortAddr,
@@ -74,41 +93,51 @@ func main() {
 	logger.Info("Operator stopped gracefully")
 }
 
-func loadConfig(configPath string) (operator.Config, error) {
-	var config operator.Config
-
-	// Check if config file exists
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		// Use default config if file doesn't exist
-		config = operator.Config{
-			EcdsaPrivateKeyStorePath:      "./keys/operator.ecdsa.key.json",
-			BlsPrivateKeyStorePath:        "./keys/operator.bls.key.json",
-			EthRpcUrl:                     "http://localhost:8545",
-			EthWsUrl:                      "ws://localhost:8546",
-			RegistryCoordinatorAddress:    "0x0000000000000000000000000000000000000000",
-			OperatorStateRetrieverAddress: "0x0000000000000000000000000000000000000000",
-			AggregatorServerIpPortAddr:    "localhost:8090",
-			RegisterOperatorOnStartup:     true,
-			EigenMetricsIpPortAddress:     "localhost:9090",
-			EnableMetrics:                 true,
-			NodeApiIpPortAddress:          "localhost:9091",
-			EnableNodeApi:                 true,
+// loadConfig builds the config from the defaults, then the YAML or JSON file
+// at configPath if it exists, then EIGENLVR_* environment variables, then the
+// config flags given on the command line. Keys in the file that match no
+// field are returned.
+func loadConfig(configPath string) (operator.Config, []configfile.UnknownField, error) {
+	config := defaultConfig()
+
+	var unknown []configfile.UnknownField
+	if _, err := os.Stat(configPath); err == nil {
+		unknown, err = configfile.Load(configPath, "operator", &config)
+		if err != nil {
+			return config, unknown, fmt.Errorf("failed to load config: %w", err)
 		}
-		
-		return config, nil
+	} else if !os.IsNotExist(err) {
+		return config, nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Load from file
-	file, err := os.Open(configPath)
-	if err != nil {
-		return config, fmt.Errorf("failed to open config file: %w", err)
+	if _, err := configfile.ApplyEnv(&config); err != nil {
+		return config, unknown, fmt.Errorf("failed to apply environment overrides: %w", err)
 	}
-	defer file.Close()
-
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&config); err != nil {
-		return config, fmt.Errorf("failed to decode config: %w", err)
+	if _, err := configFlags.Apply(&config); err != nil {
+		return config, unknown, fmt.Errorf("failed to apply flag overrides: %w", err)
 	}
+	return config, unknown, nil
+}
 
-	return config, nil
-}
\ No newline at end of file
+// defaultConfig is the config used for any key neither the file nor the
+// environment sets
+func defaultConfig() operator.Config {
+	return operator.Config{
+		EcdsaPrivateKeyStorePath:      "./keys/operator.ecdsa.key.json",
+		BlsPrivateKeyStorePath:        "./keys/operator.bls.key.json",
+		EthRpcUrl:                     "http://localhost:8545",
+		EthWsUrl:                      "ws://localhost:8546",
+		RegistryCoordinatorAddress:    "0x0000000000000000000000000000000000000000",
+		OperatorStateRetrieverAddress: "0x0000000000000000000000000000000000000000",
+		AggregatorServerIpPortAddr:    "localhost:8090",
+		AggregatorTransport:           operator.AggregatorTransportHttp,
+		RegisterOperatorOnStartup:     true,
+		EigenMetricsIpPortAddress:     "localhost:9090",
+		EnableMetrics:                 true,
+		NodeApiIpPortAddress:          "localhost:9091",
+		EnableNodeApi:                 true,
+		ResponseOutboxPath:            "./data/response-outbox.json",
+		ResponseDeadLetterPath:        "./data/response-dead-letters.jsonl",
+		AckLogPath:                    "./data/aggregator-acks.jsonl",
+	}
+}
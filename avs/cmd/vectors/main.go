@@ -0,0 +1,124 @@
+// Command vectors emits canonical test vectors for sample tasks: the encoded
+// response, its digest, the point signed, and each aggregate signature with
+// its APKs and gamma. Searcher clients in other languages and contract tests
+// validate against them.
+//
+//	vectors -tasks 8 -out vectors.json
+//	vectors -format hex
+//	vectors -check    # verify the committed golden vectors still hold
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/eigenlvr/avs/pkg/testutils/golden"
+)
+
+var (
+	seed      = flag.String("seed", golden.Seed, "Seed the operators and tasks are derived from")
+	operators = flag.Int("operators", golden.Operators, "Number of operators")
+	tasks     = flag.Int("tasks", golden.Tasks, "Number of sample tasks")
+	format    = flag.String("format", "json", "Output format: json, or hex for one name=value line per field")
+	out       = flag.String("out", "", "File to write the vectors to (defaults to stdout)")
+	check     = flag.Bool("check", false, "Verify the committed golden vectors instead of emitting any")
+)
+
+func main() {
+	flag.Parse()
+
+	if *check {
+		file, err := golden.Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := golden.Verify(file); err != nil {
+			log.Fatalf("Golden vectors don't hold: %v", err)
+		}
+		fmt.Printf("%d golden vectors hold\n", len(file.Vectors))
+		return
+	}
+
+	if *operators <= 0 || *tasks <= 0 {
+		log.Fatal("operators and tasks must be positive")
+	}
+	file, err := golden.Generate(*seed, *operators, *tasks)
+	if err != nil {
+		log.Fatalf("Failed to generate vectors: %v", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("Failed to create %s: %v", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(file)
+	case "hex":
+		err = writeHex(w, file)
+	default:
+		log.Fatalf("Unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write vectors: %v", err)
+	}
+}
+
+// writeHex writes every field as task<i>.<field>=<value>, values in 0x hex
+// except indices and counts
+func writeHex(w io.Writer, file golden.File) error {
+	if _, err := fmt.Fprintf(w, "seed=%s\noperators=%d\n", file.Seed, file.Operators); err != nil {
+		return err
+	}
+	for _, vector := range file.Vectors {
+		prefix := fmt.Sprintf("task%d.", vector.Task.TaskIndex)
+		lines := [][2]string{
+			{"poolId", vector.Task.PoolId.Hex()},
+			{"winner", vector.Response.Winner.Hex()},
+			{"winningBid", fmt.Sprintf("%#x", vector.Response.WinningBid)},
+			{"totalBids", fmt.Sprint(vector.Response.TotalBids)},
+			{"encoded", vector.Encoded.String()},
+			{"digest", vector.Digest.Hex()},
+			{"messageG1.x", vector.MessageG1.X.String()},
+			{"messageG1.y", vector.MessageG1.Y.String()},
+			{"signers", joinInts(vector.Signers)},
+			{"apk.x", vector.Apk.X.String()},
+			{"apk.y", vector.Apk.Y.String()},
+			{"apkG2.x0", vector.ApkG2.X[0].String()},
+			{"apkG2.x1", vector.ApkG2.X[1].String()},
+			{"apkG2.y0", vector.ApkG2.Y[0].String()},
+			{"apkG2.y1", vector.ApkG2.Y[1].String()},
+			{"sigma.x", vector.Sigma.X.String()},
+			{"sigma.y", vector.Sigma.Y.String()},
+			{"gamma", vector.Gamma.String()},
+		}
+		for _, line := range lines {
+			if _, err := fmt.Fprintf(w, "%s%s=%s\n", prefix, line[0], line[1]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = strconv.Itoa(value)
+	}
+	return strings.Join(parts, ",")
+}
@@ -54,6 +54,9 @@ contract EigenLVRAVSServiceManager is ServiceManagerBase, BLSSignatureChecker, R
     /// @notice Mapping from task index to task response hash
     mapping(uint32 => bytes32) public allTaskResponses;
     
+    /// @notice Tasks whose response window closed without an aggregated response
+    mapping(uint32 => bool) public taskExpired;
+    
     /// @notice Task index counter
     uint32 public latestTaskNum;
     
@@ -77,6 +80,7 @@ contract EigenLVRAVSServiceManager is ServiceManagerBase, BLSSignatureChecker, R
     );
     event TaskCompleted(uint32 indexed taskIndex);
     event TaskChallenged(uint32 indexed taskIndex, address challenger);
+    event AuctionTaskExpired(uint32 indexed taskIndex);
 
     /*//////////////////////////////////////////////////////////////
                                MODIFIERS
@@ -215,6 +219,34 @@ contract EigenLVRAVSServiceManager is ServiceManagerBase, BLSSignatureChecker, R
         emit TaskCompleted(taskResponse.referenceTaskIndex);
     }
 
+    /**
+     * @notice Record that a task's response window closed without a quorum
+     * @param task The expired task
+     * @param taskIndex The index of the expired task
+     */
+    function expireAuctionTask(
+        AuctionTask calldata task,
+        uint32 taskIndex
+    ) external onlyAggregator {
+        require(
+            keccak256(abi.encode(task)) == allTaskHashes[taskIndex],
+            "EigenLVRAVS: Task hash does not match"
+        );
+        require(
+            allTaskResponses[taskIndex] == bytes32(0),
+            "EigenLVRAVS: Aggregator has already responded to the task"
+        );
+        require(
+            block.number > task.taskCreatedBlock + TASK_RESPONSE_WINDOW_BLOCK,
+            "EigenLVRAVS: Task response window is still open"
+        );
+        require(!taskExpired[taskIndex], "EigenLVRAVS: Task has already expired");
+
+        taskExpired[taskIndex] = true;
+
+        emit AuctionTaskExpired(taskIndex);
+    }
+
     /**
      * @notice Challenge a task response
      * @param task The original task
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "forge-std/Test.sol";
+import "../src/EigenLVRAVSServiceManager.sol";
+
+/**
+ * @notice Exposes the response record, so tasks can be marked responded
+ * without an aggregate signature
+ */
+contract EigenLVRAVSServiceManagerHarness is EigenLVRAVSServiceManager {
+    constructor(
+        IAVSDirectory _avsDirectory,
+        IRegistryCoordinator _registryCoordinator,
+        IStakeRegistry _stakeRegistry,
+        IBLSApkRegistry _blsApkRegistry,
+        address _eigenLVRHook
+    ) EigenLVRAVSServiceManager(_avsDirectory, _registryCoordinator, _stakeRegistry, _blsApkRegistry, _eigenLVRHook) {}
+
+    function setTaskResponse(uint32 taskIndex, bytes32 responseHash) external {
+        allTaskResponses[taskIndex] = responseHash;
+    }
+}
+
+/**
+ * @title EigenLVRAVSServiceManagerTest
+ * @notice Covers expiring tasks whose response window closed without an
+ * aggregated response
+ */
+contract EigenLVRAVSServiceManagerTest is Test {
+    EigenLVRAVSServiceManagerHarness public serviceManager;
+
+    address public avsDirectory = address(0xA1);
+    address public registryCoordinator = address(0xA2);
+    address public stakeRegistry = address(0xA3);
+    address public blsApkRegistry = address(0xA4);
+    address public delegation = address(0xA5);
+    address public hook = address(0xB1);
+    address public aggregator = address(0xB2);
+
+    bytes32 public constant POOL_ID = keccak256("test_pool");
+    uint32 public constant QUORUM_THRESHOLD_PERCENTAGE = 67;
+
+    event AuctionTaskExpired(uint32 indexed taskIndex);
+
+    function setUp() public {
+        // The middleware constructors read the registries off the coordinator
+        vm.mockCall(registryCoordinator, abi.encodeWithSignature("stakeRegistry()"), abi.encode(stakeRegistry));
+        vm.mockCall(registryCoordinator, abi.encodeWithSignature("blsApkRegistry()"), abi.encode(blsApkRegistry));
+        vm.mockCall(stakeRegistry, abi.encodeWithSignature("delegation()"), abi.encode(delegation));
+
+        serviceManager = new EigenLVRAVSServiceManagerHarness(
+            IAVSDirectory(avsDirectory),
+            IRegistryCoordinator(registryCoordinator),
+            IStakeRegistry(stakeRegistry),
+            IBLSApkRegistry(blsApkRegistry),
+            hook
+        );
+
+        vm.prank(serviceManager.owner());
+        serviceManager.setAggregator(aggregator);
+    }
+
+    function test_ExpireAuctionTask() public {
+        (EigenLVRAVSServiceManager.AuctionTask memory task, uint32 taskIndex) = _createTask();
+        vm.roll(task.taskCreatedBlock + serviceManager.TASK_RESPONSE_WINDOW_BLOCK() + 1);
+
+        vm.expectEmit(true, false, false, true, address(serviceManager));
+        emit AuctionTaskExpired(taskIndex);
+
+        vm.prank(aggregator);
+        serviceManager.expireAuctionTask(task, taskIndex);
+
+        assertTrue(serviceManager.taskExpired(taskIndex));
+    }
+
+    function test_ExpireAuctionTask_WindowStillOpen() public {
+        (EigenLVRAVSServiceManager.AuctionTask memory task, uint32 taskIndex) = _createTask();
+
+        // The last block of the window still accepts a response
+        vm.roll(task.taskCreatedBlock + serviceManager.TASK_RESPONSE_WINDOW_BLOCK());
+
+        vm.prank(aggregator);
+        vm.expectRevert("EigenLVRAVS: Task response window is still open");
+        serviceManager.expireAuctionTask(task, taskIndex);
+
+        assertFalse(serviceManager.taskExpired(taskIndex));
+    }
+
+    function test_ExpireAuctionTask_AlreadyResponded() public {
+        (EigenLVRAVSServiceManager.AuctionTask memory task, uint32 taskIndex) = _createTask();
+        serviceManager.setTaskResponse(taskIndex, keccak256("response"));
+        vm.roll(task.taskCreatedBlock + serviceManager.TASK_RESPONSE_WINDOW_BLOCK() + 1);
+
+        vm.prank(aggregator);
+        vm.expectRevert("EigenLVRAVS: Aggregator has already responded to the task");
+        serviceManager.expireAuctionTask(task, taskIndex);
+    }
+
+    function test_ExpireAuctionTask_AlreadyExpired() public {
+        (EigenLVRAVSServiceManager.AuctionTask memory task, uint32 taskIndex) = _createTask();
+        vm.roll(task.taskCreatedBlock + serviceManager.TASK_RESPONSE_WINDOW_BLOCK() + 1);
+
+        vm.startPrank(aggregator);
+        serviceManager.expireAuctionTask(task, taskIndex);
+
+        vm.expectRevert("EigenLVRAVS: Task has already expired");
+        serviceManager.expireAuctionTask(task, taskIndex);
+        vm.stopPrank();
+    }
+
+    function test_ExpireAuctionTask_TaskMismatch() public {
+        (EigenLVRAVSServiceManager.AuctionTask memory task, uint32 taskIndex) = _createTask();
+        vm.roll(task.taskCreatedBlock + serviceManager.TASK_RESPONSE_WINDOW_BLOCK() + 1);
+        task.quorumThresholdPercentage = 1;
+
+        vm.prank(aggregator);
+        vm.expectRevert("EigenLVRAVS: Task hash does not match");
+        serviceManager.expireAuctionTask(task, taskIndex);
+    }
+
+    function test_ExpireAuctionTask_OnlyAggregator() public {
+        (EigenLVRAVSServiceManager.AuctionTask memory task, uint32 taskIndex) = _createTask();
+        vm.roll(task.taskCreatedBlock + serviceManager.TASK_RESPONSE_WINDOW_BLOCK() + 1);
+
+        vm.prank(hook);
+        vm.expectRevert("Only aggregator can call this function");
+        serviceManager.expireAuctionTask(task, taskIndex);
+
+        assertFalse(serviceManager.taskExpired(taskIndex));
+    }
+
+    function test_RespondToAuctionTask_AfterExpiry() public {
+        (EigenLVRAVSServiceManager.AuctionTask memory task, uint32 taskIndex) = _createTask();
+        vm.roll(task.taskCreatedBlock + serviceManager.TASK_RESPONSE_WINDOW_BLOCK() + 1);
+
+        vm.prank(aggregator);
+        serviceManager.expireAuctionTask(task, taskIndex);
+
+        EigenLVRAVSServiceManager.AuctionTaskResponse memory taskResponse = EigenLVRAVSServiceManager.AuctionTaskResponse({
+            referenceTaskIndex: taskIndex,
+            winner: address(0xC1),
+            winningBid: 1 ether,
+            totalBids: 1
+        });
+        EigenLVRAVSServiceManager.NonSignerStakesAndSignature memory nonSignerStakesAndSignature;
+
+        // Rejected before its signature is checked, so none is needed
+        vm.prank(aggregator);
+        vm.expectRevert("EigenLVRAVS: Aggregator has responded to the task too late");
+        serviceManager.respondToAuctionTask(task, taskResponse, nonSignerStakesAndSignature);
+
+        assertEq(serviceManager.getTaskResponseHash(taskIndex), bytes32(0));
+    }
+
+    /// @notice Has the hook create a task in the current block, returning it
+    /// as the service manager hashed it
+    function _createTask() internal returns (EigenLVRAVSServiceManager.AuctionTask memory task, uint32 taskIndex) {
+        bytes memory quorumNumbers = hex"00";
+        taskIndex = serviceManager.latestTaskNum();
+
+        vm.prank(hook);
+        serviceManager.createNewAuctionTask(POOL_ID, QUORUM_THRESHOLD_PERCENTAGE, quorumNumbers);
+
+        task = EigenLVRAVSServiceManager.AuctionTask({
+            poolId: POOL_ID,
+            blockNumber: block.number,
+            taskCreatedBlock: block.number,
+            quorumNumbers: quorumNumbers,
+            quorumThresholdPercentage: QUORUM_THRESHOLD_PERCENTAGE
+        });
+        assertEq(keccak256(abi.encode(task)), serviceManager.getTaskHash(taskIndex), "task hash");
+    }
+}
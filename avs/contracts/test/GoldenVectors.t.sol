@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.20;
+
+import "forge-std/Test.sol";
+import "../src/EigenLVRAVSServiceManager.sol";
+
+/**
+ * @title GoldenVectorsTest
+ * @notice Asserts the golden vectors in avs/pkg/testutils/golden/vectors.json,
+ * which the Go operators and aggregator are verified against. Together they
+ * lock in that both sides encode, hash and check aggregate signatures alike.
+ * Digests, message points and gammas are recomputed from each response and
+ * aggregate; the values Go produced are only what they are compared against.
+ * Regenerate this file whenever vectors.json changes.
+ */
+contract GoldenVectorsTest is Test {
+    using BN254 for BN254.G1Point;
+
+    /// @notice Gas BLSSignatureChecker allows the pairing check
+    uint256 internal constant PAIRING_EQUALITY_CHECK_GAS = 120000;
+
+    struct Vector {
+        EigenLVRAVSServiceManager.AuctionTaskResponse response;
+        bytes encoded;
+        bytes32 digest;
+        BN254.G1Point messageG1;
+        BN254.G1Point apk;
+        BN254.G2Point apkG2;
+        BN254.G1Point sigma;
+        uint256 gamma;
+    }
+
+    function test_ResponseEncodingMatchesGo() public pure {
+        Vector[] memory vectors = _vectors();
+        for (uint256 i = 0; i < vectors.length; i++) {
+            assertEq(abi.encode(vectors[i].response), vectors[i].encoded, "abi.encode(taskResponse)");
+            assertEq(_digest(vectors[i]), vectors[i].digest, "response digest");
+        }
+    }
+
+    function test_HashToG1MatchesGo() public view {
+        Vector[] memory vectors = _vectors();
+        for (uint256 i = 0; i < vectors.length; i++) {
+            BN254.G1Point memory message = BN254.hashToG1(_digest(vectors[i]));
+            assertEq(message.X, vectors[i].messageG1.X, "hashToG1 X");
+            assertEq(message.Y, vectors[i].messageG1.Y, "hashToG1 Y");
+        }
+    }
+
+    function test_GammaMatchesGo() public pure {
+        Vector[] memory vectors = _vectors();
+        for (uint256 i = 0; i < vectors.length; i++) {
+            assertEq(_gamma(vectors[i], _digest(vectors[i])), vectors[i].gamma, "gamma");
+        }
+    }
+
+    function test_AggregateSignatureVerifies() public view {
+        Vector[] memory vectors = _vectors();
+        for (uint256 i = 0; i < vectors.length; i++) {
+            Vector memory v = vectors[i];
+            bytes32 digest = _digest(v);
+            uint256 gamma = _gamma(v, digest);
+            (bool pairingSuccessful, bool signatureIsValid) = BN254.safePairing(
+                v.sigma.plus(v.apk.scalar_mul(gamma)),
+                BN254.negGeneratorG2(),
+                BN254.hashToG1(digest).plus(BN254.generatorG1().scalar_mul(gamma)),
+                v.apkG2,
+                PAIRING_EQUALITY_CHECK_GAS
+            );
+            assertTrue(pairingSuccessful, "pairing precompile call failed");
+            assertTrue(signatureIsValid, "aggregate signature is invalid");
+        }
+    }
+
+    /// @dev The message checkSignatures is passed, recomputed from the response
+    function _digest(Vector memory v) internal pure returns (bytes32) {
+        return keccak256(abi.encode(v.response));
+    }
+
+    /// @dev Mirrors BLSSignatureChecker.trySignatureAndApkVerification
+    function _gamma(Vector memory v, bytes32 digest) internal pure returns (uint256) {
+        return uint256(
+            keccak256(
+                abi.encodePacked(
+                    digest,
+                    v.apk.X,
+                    v.apk.Y,
+                    v.apkG2.X[0],
+                    v.apkG2.X[1],
+                    v.apkG2.Y[0],
+                    v.apkG2.Y[1],
+                    v.sigma.X,
+                    v.sigma.Y
+                )
+            )
+        ) % BN254.FR_MODULUS;
+    }
+
+    /// @dev Generated from vectors.json (seed "eigenlvr golden vectors", 4 operators)
+    function _vectors() internal pure returns (Vector[] memory vectors) {
+        vectors = new Vector[](4);
+        vectors[0] = Vector({
+            response: EigenLVRAVSServiceManager.AuctionTaskResponse({
+                referenceTaskIndex: 0,
+                winner: 0x9722B0769863B0BC247244C57705718Ce43B5370,
+                winningBid: 654385529377548960,
+                totalBids: 7
+            }),
+            encoded: hex"00000000000000000000000000000000000000000000000000000000000000000000000000000000000000009722b0769863b0bc247244c57705718ce43b53700000000000000000000000000000000000000000000000000914d82c00b46aa00000000000000000000000000000000000000000000000000000000000000007",
+            digest: 0x976a6b4a45484b3309abccfff195675d206a963cd2c8ff3bb5216b4ddc820749,
+            messageG1: BN254.G1Point(0x63d7ff1a1b36ab5e0bafbdc6d115e4459e6568899739f9400bfc709530b0f75, 0x1220c2ac406e96accb3ac2607941e9867646c0865e665976488b3c2558b433f3),
+            apk: BN254.G1Point(0x2ca1cde15403593c9c8dd598f772f7febd45ecc3726d03dd490acbf90f318276, 0x16e14022a1a8a1815368aef8749f04c1784ab7170a095288a2cd1187984b9099),
+            apkG2: BN254.G2Point(
+                [uint256(0x21a4026de3be9f5eddcd5964e3bbe265dff1e76ca0fa8be0cd765d794d06537b), 0x2e31288800b0c66260a126b3162f7f10a219616bc0acb8c7603960780ff069b9],
+                [uint256(0x11606dc5d1f02e441a41117c579c48115a54f83393e712e83c6c06cd12393205), 0x4be414cd6837e1d5690bf0b088f4e5a3be628fb806f06586b6919213ce0fad1]
+            ),
+            sigma: BN254.G1Point(0x2c052eb1f71b2eff7b4f32f03046b5307f74eaa8a1518be2ef48ce381acc2427, 0x1e3af13c95c6a3e89074422e99c5b6531c6d9a4499b87a55478b5ba1834ab02b),
+            gamma: 0x23d1cfc172fb033ddf24f3a1e4b515410bd0cd13b0c2c767829b86ee348a9aa2
+        });
+        vectors[1] = Vector({
+            response: EigenLVRAVSServiceManager.AuctionTaskResponse({
+                referenceTaskIndex: 1,
+                winner: 0x86d9f9b154afF5267b0C455E8cc8Eabf61D7eEa3,
+                winningBid: 540352688335341951,
+                totalBids: 14
+            }),
+            encoded: hex"000000000000000000000000000000000000000000000000000000000000000100000000000000000000000086d9f9b154aff5267b0c455e8cc8eabf61d7eea3000000000000000000000000000000000000000000000000077fb7e839a7c97f000000000000000000000000000000000000000000000000000000000000000e",
+            digest: 0x4eb9fa82a76df5be725e66541af316ae6390fb7c9c2a3c2c6237c976a85b3b78,
+            messageG1: BN254.G1Point(0x1e55ac0fc63c5594ba0e209d9971be50cc0f90eb33b8719f26173d5fcfde3e33, 0x2f2033bd6153cfc016d738e7075e4e3227d5bee44d6934a45d6544676b607d65),
+            apk: BN254.G1Point(0x1370fae118a634e921f06d4dbe3c12df825e3fba01e582d86a7277aa1affaa33, 0xf05112943ff2749783b0224f9d5f03562bf2fe76a5333fc1eefdaa5ffd9a746),
+            apkG2: BN254.G2Point(
+                [uint256(0x12bac6c2bff7b1bc1e61f2129e3a6db2d3bde853eb4679c712ee049e861ef8a6), 0xc7a8524e764f7f29172d7b79016ef9d57f1a710544ea770c7dd401858cab9b8],
+                [uint256(0x2599ff5ff820a2cfb624f55a10485e69b78a8746eb226250bd0d7df70f7c2adb), 0x1847187f5b89244d8bbce759282cc4d037629834cc270b6944867d573ca0ce57]
+            ),
+            sigma: BN254.G1Point(0x2405dd224be55665375483ea97e7ec60c7878b59cf86a2843bc07a7129268f66, 0x22e55a1af2849867a4f0e3fb61d323bd1b43836eda0a9c8ec69b1a5dadf2beac),
+            gamma: 0x1f909b97547303c5494f098436093bb416e35abb57bebe31b81bc0dd9fee9f5b
+        });
+        vectors[2] = Vector({
+            response: EigenLVRAVSServiceManager.AuctionTaskResponse({
+                referenceTaskIndex: 2,
+                winner: 0xD60c576006De198fb19046A0059f52Ef268Cd19A,
+                winningBid: 57648291876334117,
+                totalBids: 15
+            }),
+            encoded: hex"0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000d60c576006de198fb19046a0059f52ef268cd19a00000000000000000000000000000000000000000000000000ccced0e7326a25000000000000000000000000000000000000000000000000000000000000000f",
+            digest: 0x7bfe8dbdfa971feb9c876d0fcd3fb59b2bebb15cf45d1ff21b819fca44991222,
+            messageG1: BN254.G1Point(0x1b35f0d83833df982be6e1a2ca3d04dffce8dc3a23798ad7a340879c939f1794, 0x11331b9842c49b3d54e49cdbe437788bce6817fab003fe6ed11938f746672bc6),
+            apk: BN254.G1Point(0x81c3628009d58d6a3eee0a22e6649c4b3ec36f3b38e5d83609fb04ac1c8cfae, 0x29674fdb46fe950f67b216e67ededfcd303e8c13be97826d50ee928a8cf7cce2),
+            apkG2: BN254.G2Point(
+                [uint256(0x15dab7a3df6f34109c86b1cf1dc8282f712732b7b519a9b56fbc01b757c5832c), 0x2c619d5781cd846890b81120b14968063a3b921a1ee6037e048d9ad3015f53dc],
+                [uint256(0x2c471e4802c53601c03d972069b2d84b09d3cd0132b464e81dd743f88b104ec), 0x28ec635edb3ffc97b2e9a7f4bb537846f32e7d75a66622dba274a6705e114abd]
+            ),
+            sigma: BN254.G1Point(0x70bdf47176016f66c0c0129518f58c7c969e12d8a5bdf2100afe713aef596e6, 0x8496663123df218d6a2a8ad3ea0454ccbc470b571f9b46677c42ae9d4f803f9),
+            gamma: 0x15c541dd9913d8978f63adae4600dde17e46134ff31aa9a32590db03271f91c6
+        });
+        vectors[3] = Vector({
+            response: EigenLVRAVSServiceManager.AuctionTaskResponse({
+                referenceTaskIndex: 3,
+                winner: 0x2f8d749da39d9Da5A6E400eDA5821B5afd2A9c97,
+                winningBid: 60843251407719766,
+                totalBids: 15
+            }),
+            encoded: hex"00000000000000000000000000000000000000000000000000000000000000030000000000000000000000002f8d749da39d9da5a6e400eda5821b5afd2a9c9700000000000000000000000000000000000000000000000000d8289d63230d56000000000000000000000000000000000000000000000000000000000000000f",
+            digest: 0x40b43ee94b7991149749c7484e67df55f6d8ddb04f357df4a5d890b4bad4a27d,
+            messageG1: BN254.G1Point(0x104ff0766a47f0eadef98191cce686f85f57731ee6c3b36769b8049de257a537, 0x2bca817cc5cc4daf1b582d3c477de13367c56668548c34811372c5bb6453c976),
+            apk: BN254.G1Point(0xbb6d8ac034615d185a93f89f3ff1fba43b74141fce38b7bb9500374cd339eeb, 0x100594defcc540a8c61155508eae4de2fe3116fa6af630a6e444d75bcfecda41),
+            apkG2: BN254.G2Point(
+                [uint256(0xcefe289a4ad924e56468e0afd8642552e65bb6063be9b3469a0c2119f488490), 0xfb4e0759632012f7885c7ab3b885dff13a7690108e4cb4107626b0c713752ba],
+                [uint256(0x2c18c74e5c031dbcd26ab3c64ef1272649f0a644d02e9a79b15e83a1f6faa52b), 0x3f577bbf4695aa917633e7970f03a5f7c27efacc52c85429797a7063e8696cf]
+            ),
+            sigma: BN254.G1Point(0x19fcd7c2360a818c0c2159bfec8ebad02663fdfbaf8a50099f2bbcd4eb5aed2, 0x931283276cdea518eba529ed21be69eddf0797b8d24c74883c07f9a98d213a3),
+            gamma: 0x304a80112cc220454bd9ee5d27d2b2db8b56d2680027dc4bf118c90d7c4d04e9
+        });
+    }
+}
@@ -0,0 +1,94 @@
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/ack"
+)
+
+// ackLog checks the aggregator's signed acks and appends them, one JSON object
+// per line, to a file the operator can produce in a dispute
+type ackLog struct {
+	path           string
+	expectedSigner common.Address
+	logger         logging.Logger
+
+	mu sync.Mutex
+}
+
+func newAckLog(path string, expectedSigner common.Address, logger logging.Logger) (*ackLog, error) {
+	if path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create ack log directory: %w", err)
+		}
+	}
+	return &ackLog{
+		path:           path,
+		expectedSigner: expectedSigner,
+		logger:         logger,
+	}, nil
+}
+
+// record verifies that the ack covers the response and was signed by the
+// expected aggregator, then stores it. A missing ack is only logged, since
+// aggregators without a key don't sign them.
+func (l *ackLog) record(signedResponse SignedAuctionTaskResponse, responseDigest common.Hash, signedAck *ack.SignedAck) error {
+	taskIndex := signedResponse.TaskResponse.ReferenceTaskIndex
+	if signedAck == nil {
+		l.logger.Debug("Aggregator returned no signed ack", "taskIndex", taskIndex)
+		return nil
+	}
+
+	if err := signedAck.Verify(l.expectedSigner); err != nil {
+		return err
+	}
+	if err := signedAck.Matches(taskIndex, common.Hash(signedResponse.OperatorId), responseDigest); err != nil {
+		return err
+	}
+
+	if l.path == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(signedAck)
+	if err != nil {
+		return fmt.Errorf("failed to encode ack: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open ack log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write ack log: %w", err)
+	}
+	return nil
+}
+
+// recordAck stores the ack for an accepted response, logging any problem with it
+func (o *Operator) recordAck(signedResponse SignedAuctionTaskResponse, signedAck *ack.SignedAck) {
+	responseDigest, err := o.hashTaskResponse(&signedResponse.TaskResponse)
+	if err != nil {
+		o.logger.Error("Failed to hash task response for ack", "error", err)
+		return
+	}
+
+	if err := o.ackLog.record(signedResponse, responseDigest, signedAck); err != nil {
+		o.logger.Warn("Invalid or unrecorded aggregator ack",
+			"taskIndex", signedResponse.TaskResponse.ReferenceTaskIndex,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,184 @@
+package operator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/ack"
+	"github.com/eigenlvr/avs/pkg/apiversion"
+	"github.com/eigenlvr/avs/pkg/auction"
+	"github.com/eigenlvr/avs/pkg/compression"
+)
+
+const (
+	defaultAggregatorRequestTimeout = 10 * time.Second
+)
+
+var (
+	// ErrResponseRejected is returned when the aggregator refuses a task response.
+	// Resending the same response won't change the outcome.
+	ErrResponseRejected = errors.New("aggregator rejected task response")
+)
+
+// aggregatorClient posts signed task responses to the aggregator's HTTP API.
+// It uses the current API version, falling back to the unversioned routes
+// when the aggregator predates them.
+type aggregatorClient struct {
+	baseUrl    string
+	httpClient *http.Client
+	compressor *compression.Negotiator
+	logger     logging.Logger
+
+	legacy            atomic.Bool
+	deprecationWarned atomic.Bool
+}
+
+func newAggregatorClient(serverIpPortAddr string, timeout time.Duration, compressor *compression.Negotiator, logger logging.Logger) *aggregatorClient {
+	baseUrl := serverIpPortAddr
+	if !strings.HasPrefix(baseUrl, "http://") && !strings.HasPrefix(baseUrl, "https://") {
+		baseUrl = "http://" + baseUrl
+	}
+
+	return &aggregatorClient{
+		baseUrl:    strings.TrimRight(baseUrl, "/"),
+		httpClient: &http.Client{Timeout: timeout},
+		compressor: compressor,
+		logger:     logger,
+	}
+}
+
+func (c *aggregatorClient) url(path string) string {
+	if c.legacy.Load() {
+		return c.baseUrl + path
+	}
+	return c.baseUrl + apiversion.Path(path)
+}
+
+// SendTaskResponse posts the response and returns the aggregator's signed ack,
+// if it sent one. Errors wrapping ErrResponseRejected are permanent; any other
+// error means the aggregator could not be reached or failed to process the
+// request and the response may be resent.
+func (c *aggregatorClient) SendTaskResponse(ctx context.Context, signedResponse SignedAuctionTaskResponse) (*ack.SignedAck, error) {
+	body, err := json.Marshal(signedResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode task response: %w", err)
+	}
+
+	body, encoding, err := c.compressor.Encode(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress task response: %w", err)
+	}
+
+	resp, err := c.post(ctx, "/task-response", body, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	// An aggregator that predates versioned routes answers 404 without a
+	// version header, so switch to its unversioned routes and resend
+	if resp.StatusCode == http.StatusNotFound && apiversion.IsUnversioned(resp) && !c.legacy.Load() {
+		resp.Body.Close()
+		c.legacy.Store(true)
+		c.logger.Warn("Aggregator does not serve the versioned API, using unversioned routes",
+			"version", apiversion.Current,
+		)
+
+		resp, err = c.post(ctx, "/task-response", body, encoding)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if apiversion.IsDeprecated(resp) && c.deprecationWarned.CompareAndSwap(false, true) {
+		c.logger.Warn("Aggregator reports the task response route as deprecated",
+			"url", resp.Request.URL.String(),
+			"sunset", resp.Header.Get("Sunset"),
+		)
+	}
+
+	// Learn which codings the aggregator accepts for the next request
+	c.compressor.Observe(resp)
+
+	// The aggregator dropped support for the coding we used; the next attempt
+	// goes out uncompressed, so this one is worth retrying
+	if resp.StatusCode == http.StatusUnsupportedMediaType && encoding != "" {
+		return nil, fmt.Errorf("aggregator does not accept %s request bodies", encoding)
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		var accepted struct {
+			Ack *ack.SignedAck `json:"ack"`
+		}
+		// The response is accepted either way, so a malformed body only loses the ack
+		if err := json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&accepted); err != nil {
+			return nil, nil
+		}
+		return accepted.Ack, nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	// 429 means the aggregator is temporarily at capacity, so it is worth retrying
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: %s: %s", ErrResponseRejected, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil, fmt.Errorf("aggregator returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+}
+
+// Bids reads the pool's auction for the block from the aggregator's bid book.
+// It returns ErrBidBookUnavailable when the aggregator doesn't run auctions.
+func (c *aggregatorClient) Bids(ctx context.Context, poolId common.Hash, blockNumber uint32) (auction.Book, error) {
+	path := fmt.Sprintf("/bids/%s/%d", poolId.Hex(), blockNumber)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(path), nil)
+	if err != nil {
+		return auction.Book{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return auction.Book{}, fmt.Errorf("failed to reach aggregator: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotImplemented || (resp.StatusCode == http.StatusNotFound && apiversion.IsUnversioned(resp)) {
+		return auction.Book{}, ErrBidBookUnavailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return auction.Book{}, fmt.Errorf("aggregator returned %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	var book auction.Book
+	if err := json.NewDecoder(resp.Body).Decode(&book); err != nil {
+		return auction.Book{}, fmt.Errorf("failed to decode bid book: %w", err)
+	}
+	return book, nil
+}
+
+func (c *aggregatorClient) post(ctx context.Context, path string, body []byte, encoding string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(path), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach aggregator: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,175 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/eigenlvr/avs/pkg/ack"
+	"github.com/eigenlvr/avs/pkg/aggregatorpb"
+	"github.com/eigenlvr/avs/pkg/auction"
+	"github.com/eigenlvr/avs/pkg/wsproto"
+)
+
+// aggregatorGrpcClient sends task responses over the aggregator's gRPC
+// interface and keeps a StreamTasks call open, handing pushed tasks to onTask
+type aggregatorGrpcClient struct {
+	conn    *grpc.ClientConn
+	client  aggregatorpb.AggregatorClient
+	timeout time.Duration
+	onTask  func(context.Context, wsproto.Task)
+	logger  logging.Logger
+}
+
+var _ taskResponseSender = (*aggregatorGrpcClient)(nil)
+
+func newAggregatorGrpcClient(
+	address string,
+	timeout time.Duration,
+	onTask func(context.Context, wsproto.Task),
+	logger logging.Logger,
+) (*aggregatorGrpcClient, error) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create aggregator gRPC client: %w", err)
+	}
+
+	return &aggregatorGrpcClient{
+		conn:    conn,
+		client:  aggregatorpb.NewAggregatorClient(conn),
+		timeout: timeout,
+		onTask:  onTask,
+		logger:  logger.With("component", "aggregator-grpc"),
+	}, nil
+}
+
+// SendTaskResponse submits the response and returns the aggregator's signed
+// ack, if it sent one. Statuses the aggregator uses for responses it refuses
+// wrap ErrResponseRejected; any other error may be retried.
+func (c *aggregatorGrpcClient) SendTaskResponse(ctx context.Context, signedResponse SignedAuctionTaskResponse) (*ack.SignedAck, error) {
+	req := &aggregatorpb.SubmitTaskResponseRequest{
+		TaskResponse: &aggregatorpb.TaskResponse{
+			ReferenceTaskIndex: signedResponse.TaskResponse.ReferenceTaskIndex,
+			Winner:             signedResponse.TaskResponse.Winner.Bytes(),
+			WinningBid:         aggregatorpb.EncodeBigInt(signedResponse.TaskResponse.WinningBid),
+			TotalBids:          signedResponse.TaskResponse.TotalBids,
+		},
+		BlsSignature: aggregatorpb.EncodeG1(signedResponse.BlsSignature.G1Point),
+		OperatorId:   signedResponse.OperatorId[:],
+	}
+	if signedResponse.CommitteeProof != nil {
+		req.CommitteeProof = aggregatorpb.EncodeG1(signedResponse.CommitteeProof.G1Point)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	reply, err := c.client.SubmitTaskResponse(ctx, req)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.InvalidArgument, codes.NotFound, codes.PermissionDenied, codes.AlreadyExists, codes.FailedPrecondition:
+			return nil, fmt.Errorf("%w: %s", ErrResponseRejected, status.Convert(err).Message())
+		default:
+			return nil, fmt.Errorf("failed to reach aggregator: %w", err)
+		}
+	}
+
+	// The response is accepted either way, so a malformed ack is only lost
+	if reply.Ack == nil || len(reply.Ack.Signer) != common.AddressLength {
+		return nil, nil
+	}
+	return &ack.SignedAck{
+		Ack: ack.Ack{
+			TaskIndex:      reply.Ack.TaskIndex,
+			OperatorId:     common.BytesToHash(reply.Ack.OperatorId),
+			ResponseDigest: common.BytesToHash(reply.Ack.ResponseDigest),
+			Timestamp:      reply.Ack.Timestamp,
+		},
+		Signer:    common.BytesToAddress(reply.Ack.Signer),
+		Signature: reply.Ack.Signature,
+	}, nil
+}
+
+// Bids reads the pool's auction for the block from the aggregator's bid book.
+// It returns ErrBidBookUnavailable when the aggregator doesn't run auctions.
+func (c *aggregatorGrpcClient) Bids(ctx context.Context, poolId common.Hash, blockNumber uint32) (auction.Book, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	reply, err := c.client.GetBids(ctx, &aggregatorpb.GetBidsRequest{PoolId: poolId.Bytes(), BlockNumber: blockNumber})
+	if status.Code(err) == codes.Unimplemented {
+		return auction.Book{}, ErrBidBookUnavailable
+	}
+	if err != nil {
+		return auction.Book{}, fmt.Errorf("failed to read bid book: %w", err)
+	}
+	return aggregatorpb.DecodeBidBook(reply)
+}
+
+// Run keeps a StreamTasks call open until ctx is done, reopening it with
+// exponential backoff whenever it fails
+func (c *aggregatorGrpcClient) Run(ctx context.Context) {
+	defer c.conn.Close()
+	backoff := aggregatorWsMinBackoff
+
+	for {
+		connectedAt := time.Now()
+		err := c.streamTasks(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A stream that stayed up for a while resets the backoff
+		if time.Since(connectedAt) > aggregatorWsMaxBackoff {
+			backoff = aggregatorWsMinBackoff
+		}
+		c.logger.Warn("Aggregator task stream closed", "error", err, "retryIn", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, aggregatorWsMaxBackoff)
+	}
+}
+
+func (c *aggregatorGrpcClient) streamTasks(ctx context.Context) error {
+	stream, err := c.client.StreamTasks(ctx, &aggregatorpb.StreamTasksRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to open task stream: %w", err)
+	}
+	c.logger.Info("Streaming tasks from aggregator", "target", c.conn.Target())
+
+	for {
+		task, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return errors.New("aggregator ended the task stream")
+		}
+		if err != nil {
+			return err
+		}
+		if len(task.PoolId) != common.HashLength {
+			c.logger.Warn("Ignoring pushed task with an invalid pool id", "taskIndex", task.TaskIndex)
+			continue
+		}
+
+		c.onTask(ctx, wsproto.Task{
+			TaskIndex:                 task.TaskIndex,
+			PoolId:                    common.BytesToHash(task.PoolId),
+			BlockNumber:               task.BlockNumber,
+			TaskCreatedBlock:          task.TaskCreatedBlock,
+			QuorumNumbers:             task.QuorumNumbers,
+			QuorumThresholdPercentage: task.QuorumThresholdPercentage,
+		})
+	}
+}
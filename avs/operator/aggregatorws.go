@@ -0,0 +1,331 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+
+	"github.com/eigenlvr/avs/pkg/ack"
+	"github.com/eigenlvr/avs/pkg/apiversion"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+	"github.com/eigenlvr/avs/pkg/wsproto"
+)
+
+const (
+	// Transports for delivering task responses to the aggregator
+	AggregatorTransportHttp      = "http"
+	AggregatorTransportWebsocket = "websocket"
+	AggregatorTransportGrpc      = "grpc"
+
+	// Reconnect backoff for the aggregator websocket and gRPC task stream
+	aggregatorWsMinBackoff = time.Second
+	aggregatorWsMaxBackoff = 30 * time.Second
+)
+
+// ErrAggregatorNotConnected is returned when a response is sent while the
+// aggregator websocket is down. The response may be resent once it reconnects.
+var ErrAggregatorNotConnected = errors.New("aggregator websocket not connected")
+
+// taskResponseSender delivers signed task responses to the aggregator. Errors
+// wrapping ErrResponseRejected are permanent; any other error may be retried.
+type taskResponseSender interface {
+	SendTaskResponse(ctx context.Context, signedResponse SignedAuctionTaskResponse) (*ack.SignedAck, error)
+}
+
+// aggregatorStream keeps an authenticated websocket open to the aggregator.
+// Tasks pushed by the aggregator are handed to onTask and task responses are
+// sent back over the same connection.
+type aggregatorStream struct {
+	baseUrl    string
+	signer     remotesigner.Signer
+	operatorId types.OperatorId
+	onTask     func(context.Context, wsproto.Task)
+	logger     logging.Logger
+
+	// legacy is set once the aggregator turns out to predate versioned routes
+	legacy atomic.Bool
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	nextId  uint64
+	pending map[uint64]chan wsproto.Message
+
+	// gorilla/websocket allows one concurrent writer
+	writeMu sync.Mutex
+}
+
+var _ taskResponseSender = (*aggregatorStream)(nil)
+
+func newAggregatorStream(
+	serverIpPortAddr string,
+	signer remotesigner.Signer,
+	operatorId types.OperatorId,
+	onTask func(context.Context, wsproto.Task),
+	logger logging.Logger,
+) *aggregatorStream {
+	baseUrl := serverIpPortAddr
+	switch {
+	case strings.HasPrefix(baseUrl, "https://"):
+		baseUrl = "wss://" + strings.TrimPrefix(baseUrl, "https://")
+	case strings.HasPrefix(baseUrl, "http://"):
+		baseUrl = "ws://" + strings.TrimPrefix(baseUrl, "http://")
+	case !strings.HasPrefix(baseUrl, "ws://") && !strings.HasPrefix(baseUrl, "wss://"):
+		baseUrl = "ws://" + baseUrl
+	}
+
+	return &aggregatorStream{
+		baseUrl:    strings.TrimRight(baseUrl, "/"),
+		signer:     signer,
+		operatorId: operatorId,
+		onTask:     onTask,
+		logger:     logger.With("component", "aggregator-stream"),
+		pending:    make(map[uint64]chan wsproto.Message),
+	}
+}
+
+func (s *aggregatorStream) url() string {
+	if s.legacy.Load() {
+		return s.baseUrl + wsproto.Path
+	}
+	return s.baseUrl + apiversion.Path(wsproto.Path)
+}
+
+// Run keeps the websocket connected until ctx is done, reconnecting with
+// exponential backoff whenever it drops
+func (s *aggregatorStream) Run(ctx context.Context) {
+	backoff := aggregatorWsMinBackoff
+
+	for {
+		connectedAt := time.Now()
+		err := s.serve(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A connection that stayed up for a while resets the backoff
+		if time.Since(connectedAt) > aggregatorWsMaxBackoff {
+			backoff = aggregatorWsMinBackoff
+		}
+		s.logger.Warn("Aggregator websocket disconnected", "error", err, "retryIn", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, aggregatorWsMaxBackoff)
+	}
+}
+
+// serve connects, authenticates and reads messages until the connection fails
+func (s *aggregatorStream) serve(ctx context.Context) error {
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.conn = conn
+	s.mu.Unlock()
+	s.logger.Info("Connected to aggregator websocket", "url", s.url())
+
+	// Close the connection when ctx is done to unblock the read below
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	defer s.disconnect(conn)
+
+	conn.SetReadDeadline(time.Now().Add(wsproto.PongTimeout))
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(wsproto.PongTimeout))
+		s.writeMu.Lock()
+		defer s.writeMu.Unlock()
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+
+	for {
+		var message wsproto.Message
+		if err := conn.ReadJSON(&message); err != nil {
+			return fmt.Errorf("failed to read from aggregator: %w", err)
+		}
+		conn.SetReadDeadline(time.Now().Add(wsproto.PongTimeout))
+
+		switch message.Type {
+		case wsproto.TypeTask:
+			var task wsproto.Task
+			if err := json.Unmarshal(message.Payload, &task); err != nil {
+				s.logger.Warn("Failed to decode pushed task", "error", err)
+				continue
+			}
+			s.onTask(ctx, task)
+
+		case wsproto.TypeAck, wsproto.TypeError:
+			s.mu.Lock()
+			reply, ok := s.pending[message.Id]
+			delete(s.pending, message.Id)
+			s.mu.Unlock()
+			if ok {
+				reply <- message
+			}
+
+		default:
+			s.logger.Debug("Ignoring unexpected websocket message", "type", message.Type)
+		}
+	}
+}
+
+// connect dials the aggregator and answers its challenge
+func (s *aggregatorStream) connect(ctx context.Context) (*websocket.Conn, error) {
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, s.url(), nil)
+	if errors.Is(err, websocket.ErrBadHandshake) && resp.StatusCode == http.StatusNotFound && apiversion.IsUnversioned(resp) && !s.legacy.Load() {
+		s.legacy.Store(true)
+		s.logger.Warn("Aggregator does not serve the versioned API, using unversioned routes",
+			"version", apiversion.Current,
+		)
+		conn, _, err = websocket.DefaultDialer.DialContext(ctx, s.url(), nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial aggregator: %w", err)
+	}
+	conn.SetReadLimit(wsproto.MaxMessageBytes)
+
+	if err := s.authenticate(ctx, conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (s *aggregatorStream) authenticate(ctx context.Context, conn *websocket.Conn) error {
+	conn.SetReadDeadline(time.Now().Add(wsproto.AuthTimeout))
+	var message wsproto.Message
+	if err := conn.ReadJSON(&message); err != nil {
+		return fmt.Errorf("failed to read challenge: %w", err)
+	}
+	if message.Type != wsproto.TypeChallenge {
+		return fmt.Errorf("expected %s message, got %q", wsproto.TypeChallenge, message.Type)
+	}
+
+	var challenge wsproto.Challenge
+	if err := json.Unmarshal(message.Payload, &challenge); err != nil {
+		return fmt.Errorf("invalid challenge: %w", err)
+	}
+
+	signature, err := wsproto.SignChallenge(ctx, challenge, s.signer)
+	if err != nil {
+		return fmt.Errorf("failed to sign challenge: %w", err)
+	}
+
+	auth, err := wsproto.NewMessage(wsproto.TypeAuth, 0, wsproto.Auth{
+		OperatorId: common.Hash(s.operatorId),
+		Signature:  signature,
+	})
+	if err != nil {
+		return err
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(wsproto.AuthTimeout))
+	if err := conn.WriteJSON(auth); err != nil {
+		return fmt.Errorf("failed to send auth: %w", err)
+	}
+
+	if err := conn.ReadJSON(&message); err != nil {
+		return fmt.Errorf("aggregator refused authentication: %w", err)
+	}
+	if message.Type != wsproto.TypeAuthenticated {
+		return fmt.Errorf("expected %s message, got %q", wsproto.TypeAuthenticated, message.Type)
+	}
+
+	conn.SetWriteDeadline(time.Time{})
+	return nil
+}
+
+// disconnect forgets the connection and fails every response awaiting a reply
+func (s *aggregatorStream) disconnect(conn *websocket.Conn) {
+	conn.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == conn {
+		s.conn = nil
+	}
+	for id, reply := range s.pending {
+		reply <- wsproto.Message{Type: wsproto.TypeError, Id: id, Error: ErrAggregatorNotConnected.Error()}
+		delete(s.pending, id)
+	}
+}
+
+// SendTaskResponse sends the response over the websocket and waits for the
+// aggregator's ack, returning its signed form if the aggregator sent one
+func (s *aggregatorStream) SendTaskResponse(ctx context.Context, signedResponse SignedAuctionTaskResponse) (*ack.SignedAck, error) {
+	s.mu.Lock()
+	conn := s.conn
+	if conn == nil {
+		s.mu.Unlock()
+		return nil, ErrAggregatorNotConnected
+	}
+	s.nextId++
+	id := s.nextId
+	reply := make(chan wsproto.Message, 1)
+	s.pending[id] = reply
+	s.mu.Unlock()
+
+	message, err := wsproto.NewMessage(wsproto.TypeTaskResponse, id, signedResponse)
+	if err != nil {
+		s.forget(id)
+		return nil, err
+	}
+
+	s.writeMu.Lock()
+	conn.SetWriteDeadline(time.Now().Add(defaultAggregatorRequestTimeout))
+	err = conn.WriteJSON(message)
+	s.writeMu.Unlock()
+	if err != nil {
+		s.forget(id)
+		return nil, fmt.Errorf("failed to send task response: %w", err)
+	}
+
+	timeout := time.NewTimer(defaultAggregatorRequestTimeout)
+	defer timeout.Stop()
+
+	select {
+	case <-ctx.Done():
+		s.forget(id)
+		return nil, ctx.Err()
+	case <-timeout.C:
+		s.forget(id)
+		return nil, errors.New("timed out waiting for aggregator ack")
+	case message := <-reply:
+		if message.Type == wsproto.TypeAck {
+			if len(message.Payload) == 0 {
+				return nil, nil
+			}
+			var signedAck ack.SignedAck
+			if err := json.Unmarshal(message.Payload, &signedAck); err != nil {
+				return nil, nil
+			}
+			return &signedAck, nil
+		}
+		if message.Rejected {
+			return nil, fmt.Errorf("%w: %s", ErrResponseRejected, message.Error)
+		}
+		return nil, fmt.Errorf("aggregator returned error: %s", message.Error)
+	}
+}
+
+func (s *aggregatorStream) forget(id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, id)
+}
@@ -0,0 +1,113 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/auction"
+	"github.com/eigenlvr/avs/pkg/poolregistry"
+)
+
+// bidBookTimeout bounds reading a task's auction from the aggregator
+const bidBookTimeout = 10 * time.Second
+
+// ErrBidBookUnavailable is returned when the aggregator doesn't run auctions
+var ErrBidBookUnavailable = errors.New("aggregator does not run auctions")
+
+// bidBookReader reads a pool's auction for a block from the aggregator
+type bidBookReader interface {
+	Bids(ctx context.Context, poolId common.Hash, blockNumber uint32) (auction.Book, error)
+}
+
+// newAuctionRules returns the configured rules winners are determined by
+func newAuctionRules(config Config) (auction.Rules, error) {
+	rules, err := auction.ParseRules(config.AuctionRulesVersion, config.AuctionMinBid, config.AuctionReservePrice)
+	if err != nil {
+		return auction.Rules{}, fmt.Errorf("invalid auction rules: %w", err)
+	}
+	return rules, nil
+}
+
+// poolAuctionRules returns the auction rules with the pool's reserve price
+// from the registry, when it sets one
+func (o *Operator) poolAuctionRules(poolId common.Hash) auction.Rules {
+	rules := o.auctionRules
+	if pool, err := o.pools.Pool(poolId); err == nil && pool.ReservePrice != nil {
+		rules.ReservePrice = pool.ReservePrice
+	}
+	return rules
+}
+
+// loadPoolRegistry returns nil when no pools file is configured
+func loadPoolRegistry(config Config, logger logging.Logger) (*poolregistry.Registry, error) {
+	if config.PoolsFile == "" {
+		return nil, nil
+	}
+	pools, unknown, err := poolregistry.Load(config.PoolsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pools file: %w", err)
+	}
+	for _, field := range unknown {
+		logger.Warn("Ignoring unknown pools file field", "file", config.PoolsFile, "field", field.Path, "line", field.Line, "column", field.Column)
+	}
+	logger.Info("Loaded pool registry", "file", config.PoolsFile, "pools", len(pools.Pools()))
+	return pools, nil
+}
+
+// runAuction settles the task's auction from the bids revealed to the
+// aggregator. Every reveal is checked against its bidder's signed commitment
+// and escrow deposit here, so the aggregator can withhold bids but can't
+// forge or alter them, and honest operators reach the same winner from the
+// same bid book.
+func (o *Operator) runAuction(ctx context.Context, task *AuctionTask) (*AuctionTaskResponse, error) {
+	started := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, bidBookTimeout)
+	defer cancel()
+
+	response := &AuctionTaskResponse{
+		ReferenceTaskIndex: task.TaskIndex,
+		WinningBid:         big.NewInt(0),
+	}
+
+	book, err := o.bidBook.Bids(ctx, task.PoolId, task.BlockNumber)
+	if errors.Is(err, ErrBidBookUnavailable) {
+		o.logger.Debug("Aggregator runs no auctions, responding without a winner", "taskIndex", task.TaskIndex)
+		return response, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auction bids: %w", err)
+	}
+	if book.Phase != auction.PhaseClosed {
+		o.logger.Warn("Aggregator reports the task's auction as still open",
+			"taskIndex", task.TaskIndex,
+			"blockNumber", task.BlockNumber,
+			"phase", book.Phase,
+		)
+	}
+
+	escrowed := func(ctx context.Context, bidder common.Address, amount *big.Int) error {
+		return o.bidEscrowed(ctx, task, bidder, amount)
+	}
+	outcome, err := auction.Settle(ctx, task.PoolId, task.BlockNumber, book, o.poolAuctionRules(task.PoolId), escrowed)
+	if err != nil {
+		return nil, err
+	}
+	for _, err := range outcome.Dropped {
+		o.logger.Warn("Dropping revealed bid", "taskIndex", task.TaskIndex, "error", err)
+	}
+	if outcome.BelowReserve {
+		o.logger.Debug("Highest bid is below the reserve price, responding without a winner", "taskIndex", task.TaskIndex)
+	}
+	response.Winner = outcome.Winner
+	response.WinningBid = outcome.WinningBid
+	response.TotalBids = outcome.TotalBids
+	o.taskMetrics.auctionSettled(started)
+	o.logPoolGap(ctx, task)
+	return response, nil
+}
@@ -0,0 +1,166 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/avsregistry"
+	"github.com/eigenlvr/avs/pkg/committee"
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+)
+
+const (
+	// defaultCommitteeMinSize matches the aggregator's default minimum of
+	// distinct responders
+	defaultCommitteeMinSize = 2
+
+	// committeeMarginPercent is how far above a task's threshold the committee's
+	// stake must reach, so a few absent members don't stall the task
+	committeeMarginPercent = 10
+
+	// committeeOperatorSetTtl is how long the operator set sampled from is reused
+	committeeOperatorSetTtl = time.Minute
+
+	// committeeLookupTimeout bounds the reads made to sample a committee
+	committeeLookupTimeout = 5 * time.Second
+)
+
+// committeeSampler decides whether the operator is in a task's committee,
+// either by the seeded draw or, when vrfExpectedSize is set, by its own VRF
+// ticket
+type committeeSampler struct {
+	serviceManager  *servicemanager.Reader
+	avsReader       *avsregistry.AvsRegistryChainReader
+	stakePercent    uint32
+	minSize         int
+	vrfExpectedSize uint32
+	logger          logging.Logger
+
+	mu          sync.Mutex
+	operators   []avsregistry.RegisteredOperator
+	refreshedAt time.Time
+}
+
+// newCommitteeSampler returns nil when committee sampling is disabled
+func newCommitteeSampler(
+	config Config,
+	serviceManager *servicemanager.Reader,
+	avsReader *avsregistry.AvsRegistryChainReader,
+	logger logging.Logger,
+) (*committeeSampler, error) {
+	if config.CommitteeStakePercent == 0 && config.CommitteeVrfExpectedSize == 0 {
+		return nil, nil
+	}
+	if config.CommitteeStakePercent != 0 && config.CommitteeVrfExpectedSize != 0 {
+		return nil, errors.New("committee_stake_percent and committee_vrf_expected_size select committees differently, set only one")
+	}
+	if config.CommitteeStakePercent > 100 {
+		return nil, fmt.Errorf("invalid committee stake percent %d", config.CommitteeStakePercent)
+	}
+	if serviceManager == nil {
+		return nil, errors.New("committee selection needs a service_manager_address to read task hashes from")
+	}
+
+	minSize := config.CommitteeMinSize
+	if minSize <= 0 {
+		minSize = defaultCommitteeMinSize
+	}
+	return &committeeSampler{
+		serviceManager:  serviceManager,
+		avsReader:       avsReader,
+		stakePercent:    config.CommitteeStakePercent,
+		minSize:         minSize,
+		vrfExpectedSize: config.CommitteeVrfExpectedSize,
+		logger:          logger,
+	}, nil
+}
+
+// registeredOperators returns the operator set, re-read once it is older
+// than committeeOperatorSetTtl
+func (s *committeeSampler) registeredOperators(ctx context.Context) ([]avsregistry.RegisteredOperator, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.operators != nil && time.Since(s.refreshedAt) < committeeOperatorSetTtl {
+		return s.operators, nil
+	}
+	operators, err := s.avsReader.GetOperatorSetAtCurrentBlock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operator set: %w", err)
+	}
+	s.operators = operators
+	s.refreshedAt = time.Now()
+	return operators, nil
+}
+
+// inCommittee reports whether the operator should respond to the task, with
+// the proof of membership to send along when selection is by VRF. Every
+// operator responds when selection is off, and whenever the committee can't
+// be worked out, as a missing response costs more than a redundant one.
+func (o *Operator) inCommittee(task *AuctionTask) (bool, *bls.Signature) {
+	s := o.committee
+	if s == nil {
+		return true, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), committeeLookupTimeout)
+	defer cancel()
+
+	taskHash, err := s.serviceManager.TaskHash(ctx, task.TaskIndex)
+	if err != nil {
+		o.logger.Warn("Failed to read task hash, responding outside the committee", "taskIndex", task.TaskIndex, "error", err)
+		return true, nil
+	}
+	operators, err := s.registeredOperators(ctx)
+	if err != nil {
+		o.logger.Warn("Failed to sample committee, responding outside it", "taskIndex", task.TaskIndex, "error", err)
+		return true, nil
+	}
+
+	if s.vrfExpectedSize > 0 {
+		return o.inVrfCommittee(task, taskHash, operators)
+	}
+
+	// Stake coverage below the threshold could never aggregate, so cover at
+	// least the threshold plus a margin
+	coverage := min(max(s.stakePercent, uint32(task.QuorumThresholdPercentage)+committeeMarginPercent), 100)
+
+	members := committee.Sample(committee.Seed(taskHash), operators, task.QuorumNumbers, committee.Params{
+		CoveragePercent: coverage,
+		MinSize:         s.minSize,
+	})
+	selected := committee.Contains(members, o.operatorId)
+	o.logger.Debug("Sampled task committee",
+		"taskIndex", task.TaskIndex,
+		"committeeSize", len(members),
+		"operators", len(operators),
+		"selected", selected,
+	)
+	return selected, nil
+}
+
+// inVrfCommittee draws the operator's ticket for the task. Aggregators that
+// select by VRF reject responses without a winning ticket, so no response
+// is sent without one.
+func (o *Operator) inVrfCommittee(task *AuctionTask, taskHash common.Hash, operators []avsregistry.RegisteredOperator) (bool, *bls.Signature) {
+	proof := committee.Prove(o.blsKeypair, taskHash)
+	stake, totalStake := committee.StakeOf(operators, task.QuorumNumbers, o.operatorId)
+	selected := committee.Selected(committee.Output(proof), stake, totalStake, o.committee.vrfExpectedSize)
+	o.logger.Debug("Drew task committee ticket",
+		"taskIndex", task.TaskIndex,
+		"stake", stake,
+		"totalStake", totalStake,
+		"selected", selected,
+	)
+	if !selected {
+		return false, nil
+	}
+	return true, proof
+}
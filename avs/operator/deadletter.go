@@ -0,0 +1,104 @@
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Reasons a response ends up in the dead-letter queue
+const (
+	deadLetterRejected = "rejected"
+	deadLetterExpired  = "expired"
+)
+
+// deadLetter is a signed response that was never delivered to the aggregator
+type deadLetter struct {
+	Response  SignedAuctionTaskResponse `json:"response"`
+	Reason    string                    `json:"reason"`
+	QueuedAt  time.Time                 `json:"queuedAt"`
+	DroppedAt time.Time                 `json:"droppedAt"`
+	Attempts  int                       `json:"attempts"`
+	LastError string                    `json:"lastError"`
+}
+
+// deadLetterQueue appends responses the aggregator rejected or that outlived
+// their task window, one JSON object per line, so they can be inspected or
+// submitted by hand. Without a path they are only counted.
+type deadLetterQueue struct {
+	path string
+
+	mu      sync.Mutex
+	dropped *prometheus.CounterVec
+}
+
+func newDeadLetterQueue(path string, reg prometheus.Registerer) (*deadLetterQueue, error) {
+	if path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create dead-letter directory: %w", err)
+		}
+	}
+
+	queue := &deadLetterQueue{
+		path: path,
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator",
+			Name:      "dead_letter_task_responses_total",
+			Help:      "Signed task responses that were never delivered to the aggregator, per reason",
+		}, []string{"reason"}),
+	}
+	reg.MustRegister(queue.dropped)
+	return queue, nil
+}
+
+// Add records an undelivered response
+func (q *deadLetterQueue) Add(letter deadLetter) error {
+	q.dropped.WithLabelValues(letter.Reason).Inc()
+	if q.path == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letter: %w", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	file, err := os.OpenFile(q.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open dead-letter queue: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write dead-letter queue: %w", err)
+	}
+	return nil
+}
+
+// deadLetter moves an undelivered response to the dead-letter queue, logging
+// any problem storing it
+func (o *Operator) deadLetter(entry queuedResponse, reason string) {
+	err := o.deadLetters.Add(deadLetter{
+		Response:  entry.Response,
+		Reason:    reason,
+		QueuedAt:  entry.QueuedAt,
+		DroppedAt: time.Now(),
+		Attempts:  entry.Attempts,
+		LastError: entry.LastError,
+	})
+	if err != nil {
+		o.logger.Error("Failed to record undelivered task response",
+			"taskIndex", entry.Response.TaskResponse.ReferenceTaskIndex,
+			"error", err,
+		)
+	}
+}
@@ -0,0 +1,51 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/eigenlvr/avs/pkg/diagnostics"
+)
+
+// registerDiagnostics adds the operator's queue depth probes
+func (o *Operator) registerDiagnostics() {
+	o.diagnostics.RegisterDepth("taskResponseChan", func() diagnostics.Depth {
+		return diagnostics.Depth{Len: len(o.taskResponseChan), Cap: cap(o.taskResponseChan)}
+	})
+	o.diagnostics.RegisterDepth("taskQueue", func() diagnostics.Depth {
+		return diagnostics.Depth{Len: o.taskQueue.len()}
+	})
+	o.diagnostics.RegisterDepth("responseOutbox", func() diagnostics.Depth {
+		return diagnostics.Depth{Len: o.responseOutbox.Len()}
+	})
+	if o.delegationMonitor != nil {
+		events := o.delegationMonitor.Events()
+		o.diagnostics.RegisterDepth("delegationEvents", func() diagnostics.Depth {
+			return diagnostics.Depth{Len: len(events), Cap: cap(events)}
+		})
+	}
+}
+
+// startDebugServer serves /debug/status until ctx is done
+func (o *Operator) startDebugServer(ctx context.Context) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/status", o.diagnostics.Handler)
+
+	server := &http.Server{
+		Addr:              o.config.DebugIpPortAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	o.logger.Info("Starting debug server", "address", o.config.DebugIpPortAddress)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		o.logger.Error("Debug server error", "error", err)
+	}
+}
@@ -0,0 +1,54 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/escrow"
+)
+
+// escrowCheckTimeout bounds the deposit read made before signing
+const escrowCheckTimeout = 5 * time.Second
+
+// newEscrowReader returns nil when no auction escrow is configured
+func newEscrowReader(config Config, ethClient eth.Client) (*escrow.Reader, error) {
+	if config.AuctionEscrowAddress == "" {
+		return nil, nil
+	}
+	if !common.IsHexAddress(config.AuctionEscrowAddress) {
+		return nil, fmt.Errorf("invalid auction escrow address %q", config.AuctionEscrowAddress)
+	}
+	return escrow.NewReader(common.HexToAddress(config.AuctionEscrowAddress), ethClient)
+}
+
+// bidEscrowed reports whether the bidder had the bid escrowed at the task's
+// reference block, so a bid the searcher can't pay never becomes the winner.
+// Every bid counts as escrowed when no escrow is configured.
+func (o *Operator) bidEscrowed(ctx context.Context, task *AuctionTask, bidder common.Address, bid *big.Int) error {
+	if o.escrow == nil || bid == nil || bid.Sign() == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, escrowCheckTimeout)
+	defer cancel()
+
+	referenceBlock := new(big.Int).SetUint64(uint64(task.TaskCreatedBlock))
+	return o.escrow.Verify(ctx, bidder, bid, referenceBlock)
+}
+
+// checkEscrow refuses to sign a winner whose bid isn't escrowed, including
+// when the deposit can't be read
+func (o *Operator) checkEscrow(task *AuctionTask, response *AuctionTaskResponse) error {
+	if response.Winner == (common.Address{}) {
+		return nil
+	}
+	if err := o.bidEscrowed(context.Background(), task, response.Winner, response.WinningBid); err != nil {
+		return fmt.Errorf("refusing to sign task response: %w", err)
+	}
+	return nil
+}
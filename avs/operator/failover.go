@@ -0,0 +1,234 @@
+package operator
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// FailoverRolePrimary serves heartbeats and always responds to tasks
+	FailoverRolePrimary = "primary"
+	// FailoverRoleStandby follows tasks passively and only responds while the
+	// primary's heartbeats are missing
+	FailoverRoleStandby = "standby"
+
+	defaultFailoverHeartbeatInterval = 2 * time.Second
+	defaultFailoverTimeout           = 10 * time.Second
+)
+
+// Heartbeat is served by a primary operator so a standby can tell it is alive
+type Heartbeat struct {
+	OperatorId string    `json:"operatorId"`
+	Version    string    `json:"version"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// failover tracks whether this operator instance should currently respond to
+// tasks. Instances without a failover role are always active.
+type failover struct {
+	role              string
+	operatorId        types.OperatorId
+	listenAddr        string
+	peerUrl           string
+	heartbeatInterval time.Duration
+	timeout           time.Duration
+	httpClient        *http.Client
+	logger            logging.Logger
+
+	active        atomic.Bool
+	lastHeartbeat atomic.Int64
+
+	activeGauge prometheus.Gauge
+}
+
+func newFailover(config Config, operatorId types.OperatorId, reg prometheus.Registerer, logger logging.Logger) (*failover, error) {
+	f := &failover{
+		role:              config.FailoverRole,
+		operatorId:        operatorId,
+		listenAddr:        config.FailoverListenAddr,
+		peerUrl:           strings.TrimRight(config.FailoverPeerUrl, "/"),
+		heartbeatInterval: defaultFailoverHeartbeatInterval,
+		timeout:           defaultFailoverTimeout,
+		logger:            logger.With("component", "failover"),
+		activeGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator",
+			Name:      "failover_active",
+			Help:      "1 if this operator instance is responding to tasks, 0 if it is a passive standby",
+		}),
+	}
+
+	switch f.role {
+	case "", FailoverRolePrimary:
+		if f.role == FailoverRolePrimary && f.listenAddr == "" {
+			return nil, errors.New("failover_listen_address is required for a primary operator")
+		}
+		f.setActive(true)
+	case FailoverRoleStandby:
+		if f.peerUrl == "" {
+			return nil, errors.New("failover_peer_url is required for a standby operator")
+		}
+		f.setActive(false)
+	default:
+		return nil, fmt.Errorf("invalid failover role %q", f.role)
+	}
+
+	var err error
+	if config.FailoverHeartbeatInterval != "" {
+		f.heartbeatInterval, err = time.ParseDuration(config.FailoverHeartbeatInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid failover heartbeat interval: %w", err)
+		}
+	}
+	if config.FailoverTimeout != "" {
+		f.timeout, err = time.ParseDuration(config.FailoverTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid failover timeout: %w", err)
+		}
+	}
+	if f.timeout <= f.heartbeatInterval {
+		return nil, fmt.Errorf("failover timeout %s must be longer than the heartbeat interval %s", f.timeout, f.heartbeatInterval)
+	}
+	f.httpClient = &http.Client{Timeout: f.heartbeatInterval}
+
+	reg.MustRegister(f.activeGauge)
+
+	return f, nil
+}
+
+// IsActive returns whether this instance should respond to tasks
+func (f *failover) IsActive() bool {
+	return f.active.Load()
+}
+
+func (f *failover) setActive(active bool) {
+	f.active.Store(active)
+	if active {
+		f.activeGauge.Set(1)
+	} else {
+		f.activeGauge.Set(0)
+	}
+}
+
+// Start runs the heartbeat server on a primary, or the heartbeat monitor on a
+// standby, until ctx is done
+func (f *failover) Start(ctx context.Context) {
+	switch f.role {
+	case FailoverRolePrimary:
+		f.serveHeartbeats(ctx)
+	case FailoverRoleStandby:
+		f.monitorPrimary(ctx)
+	}
+}
+
+func (f *failover) serveHeartbeats(ctx context.Context) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/heartbeat", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Heartbeat{
+			OperatorId: hex.EncodeToString(f.operatorId[:]),
+			Version:    SemVer,
+			Timestamp:  time.Now().UTC(),
+		})
+	})
+
+	server := &http.Server{
+		Addr:              f.listenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	f.logger.Info("Serving failover heartbeats", "address", f.listenAddr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		f.logger.Error("Failover heartbeat server failed", "error", err)
+	}
+}
+
+// monitorPrimary polls the primary's heartbeat. The standby takes over once no
+// heartbeat has been received for the failover timeout, and steps back as soon
+// as the primary is reachable again so only one instance responds.
+func (f *failover) monitorPrimary(ctx context.Context) {
+	f.logger.Info("Monitoring primary operator",
+		"peerUrl", f.peerUrl,
+		"heartbeatInterval", f.heartbeatInterval,
+		"timeout", f.timeout,
+	)
+
+	// Give the primary a full timeout before taking over after a restart
+	f.lastHeartbeat.Store(time.Now().UnixNano())
+
+	ticker := time.NewTicker(f.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := f.checkHeartbeat(ctx)
+			if err == nil {
+				f.lastHeartbeat.Store(time.Now().UnixNano())
+				if f.IsActive() {
+					f.logger.Info("Primary operator is back, returning to standby")
+					f.setActive(false)
+				}
+				continue
+			}
+
+			silence := time.Since(time.Unix(0, f.lastHeartbeat.Load()))
+			if silence >= f.timeout && !f.IsActive() {
+				f.logger.Warn("Primary operator went silent, taking over task responses",
+					"silence", silence,
+					"error", err,
+				)
+				f.setActive(true)
+			}
+		}
+	}
+}
+
+func (f *failover) checkHeartbeat(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.peerUrl+"/heartbeat", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("heartbeat returned %s", resp.Status)
+	}
+
+	var heartbeat Heartbeat
+	if err := json.NewDecoder(resp.Body).Decode(&heartbeat); err != nil {
+		return fmt.Errorf("failed to decode heartbeat: %w", err)
+	}
+
+	// A heartbeat from a different operator means the standby is misconfigured;
+	// treating it as missing is safer than staying passive forever
+	if heartbeat.OperatorId != hex.EncodeToString(f.operatorId[:]) {
+		return fmt.Errorf("heartbeat is from operator %s, expected %s", heartbeat.OperatorId, hex.EncodeToString(f.operatorId[:]))
+	}
+
+	return nil
+}
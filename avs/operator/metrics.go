@@ -0,0 +1,113 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/ack"
+	"github.com/eigenlvr/avs/pkg/poolmetrics"
+	"github.com/eigenlvr/avs/pkg/pricefeed"
+)
+
+// Results of sending a signed task response to the aggregator
+const (
+	submissionResultAccepted    = "accepted"
+	submissionResultRejected    = "rejected"
+	submissionResultUnreachable = "unreachable"
+)
+
+// taskMetrics records the operator's task pipeline, from receiving a task
+// to the aggregator accepting its signed response
+type taskMetrics struct {
+	tasksReceived     *prometheus.CounterVec
+	responsesSigned   prometheus.Counter
+	submissions       *prometheus.CounterVec
+	submissionLatency *prometheus.HistogramVec
+	auctionDuration   prometheus.Histogram
+	priceFeedAge      *prometheus.GaugeVec
+}
+
+func newTaskMetrics(reg prometheus.Registerer) *taskMetrics {
+	m := &taskMetrics{
+		tasksReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator",
+			Name:      "tasks_received_total",
+			Help:      "New auction tasks by where they were first seen",
+		}, []string{"source"}),
+		responsesSigned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator",
+			Name:      "task_responses_signed_total",
+			Help:      "Task responses signed for the aggregator",
+		}),
+		submissions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator",
+			Name:      "task_response_submissions_total",
+			Help:      "Attempts to send a signed task response to the aggregator, resends included, by result",
+		}, []string{"result"}),
+		submissionLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator",
+			Name:      "task_response_submission_seconds",
+			Help:      "Time to send a signed task response to the aggregator, by result",
+			Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"result"}),
+		auctionDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator",
+			Name:      "auction_duration_seconds",
+			Help:      "Time to fetch a task's bid book and settle its auction",
+			Buckets:   []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+		}),
+		priceFeedAge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator",
+			Name:      "price_feed_age_seconds",
+			Help:      "Age of the oldest source quote in the last mid-price served for each pool",
+		}, []string{poolmetrics.LabelName}),
+	}
+	reg.MustRegister(m.tasksReceived, m.responsesSigned, m.submissions, m.submissionLatency, m.auctionDuration, m.priceFeedAge)
+	return m
+}
+
+func (m *taskMetrics) taskReceived(source string) {
+	m.tasksReceived.WithLabelValues(source).Inc()
+}
+
+func (m *taskMetrics) responseSigned() {
+	m.responsesSigned.Inc()
+}
+
+func (m *taskMetrics) submitted(err error, started time.Time) {
+	result := submissionResultAccepted
+	switch {
+	case errors.Is(err, ErrResponseRejected):
+		result = submissionResultRejected
+	case err != nil:
+		result = submissionResultUnreachable
+	}
+	m.submissions.WithLabelValues(result).Inc()
+	m.submissionLatency.WithLabelValues(result).Observe(time.Since(started).Seconds())
+}
+
+func (m *taskMetrics) auctionSettled(started time.Time) {
+	m.auctionDuration.Observe(time.Since(started).Seconds())
+}
+
+func (m *taskMetrics) midPriceServed(mid pricefeed.MidPrice) {
+	m.priceFeedAge.WithLabelValues(mid.PoolId.Hex()).Set(time.Since(mid.ObservedAt).Seconds())
+}
+
+// sendTaskResponse sends a signed response through the response sender,
+// timing and counting the attempt
+func (o *Operator) sendTaskResponse(ctx context.Context, signedResponse SignedAuctionTaskResponse) (*ack.SignedAck, error) {
+	started := time.Now()
+	signedAck, err := o.responseSender.SendTaskResponse(ctx, signedResponse)
+	o.taskMetrics.submitted(err, started)
+	return signedAck, err
+}
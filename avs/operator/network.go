@@ -0,0 +1,33 @@
+package operator
+
+import (
+	"github.com/eigenlvr/avs/pkg/networks"
+)
+
+// applyNetworkPreset fills unset contract addresses from the configured
+// network's preset. Addresses set in the config are kept.
+func applyNetworkPreset(config Config) (Config, error) {
+	if config.Network == "" {
+		return config, nil
+	}
+
+	preset, err := networks.Lookup(config.Network)
+	if err != nil {
+		return config, err
+	}
+
+	networks.Fill(&config.RegistryCoordinatorAddress, preset.RegistryCoordinatorAddress)
+	networks.Fill(&config.OperatorStateRetrieverAddress, preset.OperatorStateRetrieverAddress)
+	networks.Fill(&config.ServiceManagerAddress, preset.ServiceManagerAddress)
+	networks.Fill(&config.DelegationManagerAddress, preset.DelegationManagerAddress)
+	networks.Fill(&config.RewardsCoordinatorAddress, preset.RewardsCoordinatorAddress)
+	networks.Fill(&config.UniswapV3FactoryAddress, preset.UniswapV3FactoryAddress)
+
+	if err := preset.Require(config.RegistryCoordinatorAddress, "registry_coordinator_address"); err != nil {
+		return config, err
+	}
+	if err := preset.Require(config.OperatorStateRetrieverAddress, "operator_state_retriever_address"); err != nil {
+		return config, err
+	}
+	return config, nil
+}
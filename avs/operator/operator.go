@@ -2,76 +2,401 @@ package operator
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"crypto/rand"
 	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
 	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/Layr-Labs/eigensdk-go/metrics"
 	"github.com/Layr-Labs/eigensdk-go/nodeapi"
-	"github.com/Layr-Labs/eigensdk-go/signerv2"
 	"github.com/Layr-Labs/eigensdk-go/types"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/eigenlvr/avs/pkg/auction"
 	"github.com/eigenlvr/avs/pkg/avsregistry"
+	"github.com/eigenlvr/avs/pkg/clockdrift"
+	"github.com/eigenlvr/avs/pkg/compression"
+	"github.com/eigenlvr/avs/pkg/delegation"
+	"github.com/eigenlvr/avs/pkg/diagnostics"
+	"github.com/eigenlvr/avs/pkg/digest"
+	"github.com/eigenlvr/avs/pkg/escrow"
+	"github.com/eigenlvr/avs/pkg/logwatcher"
+	"github.com/eigenlvr/avs/pkg/networks"
+	"github.com/eigenlvr/avs/pkg/poolregistry"
+	"github.com/eigenlvr/avs/pkg/poolwatcher"
+	"github.com/eigenlvr/avs/pkg/pricefeed"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+	"github.com/eigenlvr/avs/pkg/rewards"
+	"github.com/eigenlvr/avs/pkg/sdnotify"
+	"github.com/eigenlvr/avs/pkg/seqfeed"
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+	"github.com/eigenlvr/avs/pkg/subgraph"
+	"github.com/eigenlvr/avs/pkg/venues"
+	"github.com/eigenlvr/avs/pkg/wsproto"
 )
 
 const (
 	// SemVer is the semantic version of the operator
 	SemVer = "0.0.1"
+
+	// defaultLargeDelegationChangeBps reports share changes of 10% or more
+	defaultLargeDelegationChangeBps = 1000
+
+	// defaultResponseResendWindow matches the service manager's 30 block
+	// response window at 12s blocks
+	defaultResponseResendWindow   = 6 * time.Minute
+	defaultResponseResendInterval = 5 * time.Second
+	// defaultResponseResendMaxInterval caps the backoff between resends of
+	// a queued response
+	defaultResponseResendMaxInterval = time.Minute
+
+	// chainIdTimeout bounds the chain id read at startup
+	chainIdTimeout = 10 * time.Second
+	// registrationTimeout bounds startup registration, confirmations
+	// included
+	registrationTimeout = 10 * time.Minute
+	// registrationSignatureValidity is how long the operator's registration
+	// signature stays valid
+	registrationSignatureValidity = time.Hour
+
+	defaultClockDriftThreshold     = 2 * time.Second
+	defaultClockDriftMaxBlockLag   = 24 * time.Second
+	defaultClockDriftCheckInterval = time.Minute
+)
+
+var (
+	// ErrSignatureSelfCheckFailed is returned when a freshly produced signature
+	// does not verify against the operator's own public key
+	ErrSignatureSelfCheckFailed = errors.New("bls signature failed self-verification")
 )
 
 type Operator struct {
-	config    Config
-	logger    logging.Logger
-	ethClient eth.Client
+	config     Config
+	logger     logging.Logger
+	ethClient  eth.Client
 	metricsReg *prometheus.Registry
-	metrics   metrics.Metrics
-	nodeApi   *nodeapi.NodeApi
+	metrics    metrics.Metrics
+	nodeApi    *nodeapi.NodeApi
+	// AVS task pipeline metrics, registered on metricsReg
+	taskMetrics *taskMetrics
 
 	avsWriter avsregistry.AvsRegistryChainWriter
 	avsReader avsregistry.AvsRegistryChainReader
 
-	blsKeypair         *types.BlsKeyPair
-	operatorId         types.OperatorId
-	operatorAddr       common.Address
-	operatorEcdsaPrivateKey *ecdsa.PrivateKey
+	blsKeypair   *bls.KeyPair
+	operatorId   types.OperatorId
+	operatorAddr common.Address
+	ecdsaSigner  remotesigner.Signer
 
 	// AVS specific fields
-	auctionTasks       map[uint32]*AuctionTask
-	auctionTasksMutex  sync.RWMutex
-	taskResponseChan   chan TaskResponseInfo
+	auctionTasks      map[uint32]*AuctionTask
+	auctionTasksMutex sync.RWMutex
+	taskResponseChan  chan TaskResponseInfo
+
+	// Task prioritization
+	taskQueue   *taskQueue
+	poolWeights map[common.Hash]uint64
+	poolValuer  PoolValuer
+	subgraph    *subgraph.Client
+
+	rewardsTracker    *rewards.Tracker
+	rewardsClaimer    *rewards.Claimer
+	autoClaimInterval time.Duration
+
+	delegationMonitor *delegation.Monitor
+
+	// Response delivery, over HTTP or the aggregator websocket
+	responseSender            taskResponseSender
+	aggregatorStream          *aggregatorStream
+	aggregatorGrpc            *aggregatorGrpcClient
+	ackLog                    *ackLog
+	responseOutbox            *responseOutbox
+	deadLetters               *deadLetterQueue
+	responseResendWindow      time.Duration
+	responseResendInterval    time.Duration
+	responseResendMaxInterval time.Duration
+
+	failover *failover
+
+	venueSampler *venues.Sampler
+	// Streams CEX tickers for the sampler, nil when no exchange is configured
+	exchange *venues.Exchange
+	// Per-pool mid-prices from the sampler
+	priceFeed *pricefeed.Feed
+	// Tracks the watched pools' price and liquidity, nil when no pool
+	// manager is configured
+	poolWatcher *poolwatcher.Watcher
+
+	clockDrift         *clockdrift.Monitor
+	refuseOnClockDrift bool
+
+	// The aggregator's auctions, which responses settle
+	bidBook bidBookReader
+	// How each auction's winner is determined from its bids
+	auctionRules auction.Rules
+	// Pools auctions run for, nil when every pool is responded to
+	pools *poolregistry.Registry
+
+	// Dry-runs respondToAuctionTask before signing, nil when disabled
+	responseSimulator *responseSimulator
+	// Searcher deposits winning bids are checked against, nil when no
+	// escrow is configured
+	escrow *escrow.Reader
+	// Samples the operators responding to each task, nil when every
+	// operator responds
+	committee *committeeSampler
+
+	taskWatcher *logwatcher.Watcher
+	// Preconfirmed blocks and task events, nil without a sequencer feed
+	sequencerFeed *seqfeed.Feed
+
+	diagnostics *diagnostics.Collector
+
+	// Pets the systemd watchdog while the main loops keep beating
+	watchdog *sdnotify.Watchdog
 }
 
 type Config struct {
-	EcdsaPrivateKeyStorePath   string `json:"ecdsa_private_key_store_path"`
-	BlsPrivateKeyStorePath     string `json:"bls_private_key_store_path"`
-	EthRpcUrl                  string `json:"eth_rpc_url"`
-	EthWsUrl                   string `json:"eth_ws_url"`
-	RegistryCoordinatorAddress string `json:"registry_coordinator_address"`
+	// Network selects a preset (mainnet, holesky, sepolia) that fills in any
+	// contract address left unset
+	Network                       string `json:"network"`
+	EcdsaPrivateKeyStorePath      string `json:"ecdsa_private_key_store_path"`
+	BlsPrivateKeyStorePath        string `json:"bls_private_key_store_path"`
+	EthRpcUrl                     string `json:"eth_rpc_url"`
+	EthWsUrl                      string `json:"eth_ws_url"`
+	RegistryCoordinatorAddress    string `json:"registry_coordinator_address"`
 	OperatorStateRetrieverAddress string `json:"operator_state_retriever_address"`
-	AggregatorServerIpPortAddr string `json:"aggregator_server_ip_port_address"`
-	RegisterOperatorOnStartup  bool   `json:"register_operator_on_startup"`
-	EigenMetricsIpPortAddress  string `json:"eigen_metrics_ip_port_address"`
-	EnableMetrics              bool   `json:"enable_metrics"`
-	NodeApiIpPortAddress       string `json:"node_api_ip_port_address"`
-	EnableNodeApi              bool   `json:"enable_node_api"`
+	AggregatorServerIpPortAddr    string `json:"aggregator_server_ip_port_address"`
+	RegisterOperatorOnStartup     bool   `json:"register_operator_on_startup"`
+	RegistrationConfirmations     uint64 `json:"registration_confirmations"`
+	EigenMetricsIpPortAddress     string `json:"eigen_metrics_ip_port_address"`
+	EnableMetrics                 bool   `json:"enable_metrics"`
+	NodeApiIpPortAddress          string `json:"node_api_ip_port_address"`
+	EnableNodeApi                 bool   `json:"enable_node_api"`
+	// ChainId is the chain transactions are signed for. It is read from
+	// EthRpcUrl at startup, and must match ChainId and Network when they are
+	// set.
+	ChainId uint64 `json:"chain_id"`
+	// Passphrases of encrypted key files are read from these files when set,
+	// else from EIGENLVR_ECDSA_KEY_PASSWORD and EIGENLVR_BLS_KEY_PASSWORD, else
+	// prompted for on a terminal. BLS keys saved without one still load.
+	EcdsaPrivateKeyPasswordFile string `json:"ecdsa_private_key_password_file"`
+	BlsPrivateKeyPasswordFile   string `json:"bls_private_key_password_file"`
+	// EcdsaSigner is "local" (default) to sign with the key at
+	// EcdsaPrivateKeyStorePath, or "web3signer", "aws-kms" or "gcp-kms" to
+	// have the key at EcdsaSignerUrl or EcdsaSignerKeyId sign instead.
+	// Web3Signer only signs transactions, so it can't be used with the
+	// websocket aggregator transport.
+	EcdsaSigner        string `json:"ecdsa_signer"`
+	EcdsaSignerUrl     string `json:"ecdsa_signer_url"`
+	EcdsaSignerKeyId   string `json:"ecdsa_signer_key_id"`
+	EcdsaSignerAddress string `json:"ecdsa_signer_address"`
+	// With RpcCacheSize set, read calls to EthRpcUrl go through a caching
+	// proxy shared by every component of the process. Calls against the
+	// latest block are reused for RpcCacheHeadTtl.
+	RpcCacheSize    int    `json:"rpc_cache_size"`
+	RpcCacheHeadTtl string `json:"rpc_cache_head_ttl"`
+	// One-shot commands such as claim-rewards push their metrics to
+	// PushgatewayUrl when it is set, as they exit before being scraped
+	PushgatewayUrl string `json:"pushgateway_url"`
+	// PoolWeights maps pool IDs to a priority weight. Tasks for higher-weight
+	// pools are processed first; unlisted pools get a weight of 1.
+	PoolWeights map[string]uint64 `json:"pool_weights"`
+	// When SubgraphUrl is set, tasks are also ranked by the TVL a Uniswap
+	// subgraph reports for their pool. Pool data is cached for SubgraphCacheTtl
+	// and queries are at least SubgraphMinRequestInterval apart.
+	SubgraphUrl                string `json:"subgraph_url"`
+	SubgraphApiKey             string `json:"subgraph_api_key"`
+	SubgraphCacheTtl           string `json:"subgraph_cache_ttl"`
+	SubgraphMinRequestInterval string `json:"subgraph_min_request_interval"`
+	// Rewards tracking is enabled when a RewardsCoordinator address is set
+	RewardsCoordinatorAddress string `json:"rewards_coordinator_address"`
+	RewardsApiUrl             string `json:"rewards_api_url"`
+	RewardsPollInterval       string `json:"rewards_poll_interval"`
+	// RewardsClaimRecipient receives claimed rewards; defaults to the operator address
+	RewardsClaimRecipient string `json:"rewards_claim_recipient"`
+	// AutoClaimRewardsInterval enables scheduled claiming when set
+	AutoClaimRewardsInterval string `json:"auto_claim_rewards_interval"`
+	// MaxClaimGasPriceGwei skips claims while gas is above this price; 0 disables the check
+	MaxClaimGasPriceGwei uint64 `json:"max_claim_gas_price_gwei"`
+	// Delegation monitoring is enabled when a DelegationManager address is set
+	DelegationManagerAddress string   `json:"delegation_manager_address"`
+	DelegationStrategies     []string `json:"delegation_strategies"`
+	DelegationPollInterval   string   `json:"delegation_poll_interval"`
+	// LargeDelegationChangeBps is the share change, in basis points of the
+	// previous balance, reported as a large delegation or undelegation
+	LargeDelegationChangeBps uint64 `json:"large_delegation_change_bps"`
+	// Responses the aggregator can't be reached for are queued at
+	// ResponseOutboxPath and resent every ResponseResendInterval until
+	// ResponseResendWindow has passed since the task was created. The wait
+	// between resends of a response doubles after every failed attempt, up
+	// to ResponseResendMaxInterval. Responses that are rejected or outlive
+	// the window are appended to ResponseDeadLetterPath.
+	ResponseOutboxPath        string `json:"response_outbox_path"`
+	ResponseResendWindow      string `json:"response_resend_window"`
+	ResponseResendInterval    string `json:"response_resend_interval"`
+	ResponseResendMaxInterval string `json:"response_resend_max_interval"`
+	ResponseDeadLetterPath    string `json:"response_dead_letter_path"`
+	// AggregatorRequestTimeout bounds each HTTP request to the aggregator
+	AggregatorRequestTimeout string `json:"aggregator_request_timeout"`
+	// FailoverRole is "primary", "standby" or empty to run without failover.
+	// A primary serves heartbeats on FailoverListenAddr; a standby polls
+	// FailoverPeerUrl and takes over when heartbeats stop for FailoverTimeout.
+	// Both instances must sign with the same operator keys, e.g. through a
+	// shared remote signer.
+	FailoverRole              string `json:"failover_role"`
+	FailoverListenAddr        string `json:"failover_listen_address"`
+	FailoverPeerUrl           string `json:"failover_peer_url"`
+	FailoverHeartbeatInterval string `json:"failover_heartbeat_interval"`
+	FailoverTimeout           string `json:"failover_timeout"`
+	// Other on-chain venues sampled for a cross-venue reference price
+	UniswapV3FactoryAddress string            `json:"uniswap_v3_factory_address"`
+	UniswapV3FeeTiers       []uint32          `json:"uniswap_v3_fee_tiers"`
+	CurvePools              []CurvePoolConfig `json:"curve_pools"`
+	// Chainlink Data Streams feeds sampled alongside the on-chain venues. A
+	// report is used once DataStreamsMinSigners of DataStreamsSigners signed
+	// it, while its observation is within DataStreamsMaxReportAge.
+	DataStreamsApiUrl       string            `json:"data_streams_api_url"`
+	DataStreamsClientId     string            `json:"data_streams_client_id"`
+	DataStreamsClientSecret string            `json:"data_streams_client_secret"`
+	DataStreamsSigners      []string          `json:"data_streams_signers"`
+	DataStreamsMinSigners   int               `json:"data_streams_min_signers"`
+	DataStreamsMaxReportAge string            `json:"data_streams_max_report_age"`
+	DataStreamsFeeds        []PriceFeedConfig `json:"data_streams_feeds"`
+	// Pyth prices from a Hermes service. Prices older than PythMaxPriceAge, or
+	// whose confidence interval exceeds PythMaxConfidenceBps of the price, are
+	// rejected.
+	PythHermesUrl        string            `json:"pyth_hermes_url"`
+	PythMaxPriceAge      string            `json:"pyth_max_price_age"`
+	PythMaxConfidenceBps int64             `json:"pyth_max_confidence_bps"`
+	PythFeeds            []PriceFeedConfig `json:"pyth_feeds"`
+	// On-chain Chainlink feeds, whose FeedId is the aggregator proxy address.
+	// Answers older than ChainlinkMaxPriceAge are rejected.
+	ChainlinkFeeds       []PriceFeedConfig `json:"chainlink_feeds"`
+	ChainlinkMaxPriceAge string            `json:"chainlink_max_price_age"`
+	// Best bid and ask streamed from ExchangeKind ("binance" or "coinbase"),
+	// whose FeedId is the market symbol. Tickers older than
+	// ExchangeMaxTickAge are rejected.
+	ExchangeKind       string            `json:"exchange_kind"`
+	ExchangeUrl        string            `json:"exchange_url"`
+	ExchangeMaxTickAge string            `json:"exchange_max_tick_age"`
+	ExchangeMarkets    []PriceFeedConfig `json:"exchange_markets"`
+	// Pools mid-prices are aggregated for across the venues above. A pool's
+	// mid-price is reused for PriceMaxAge.
+	PricePools  []PricePoolConfig `json:"price_pools"`
+	PriceMaxAge string            `json:"price_max_age"`
+	// The Uniswap v4 pools in WatchedPools are tracked through
+	// PoolManagerAddress: their state is read from its storage and kept
+	// current from its Swap events, polled every PoolPollInterval while the
+	// websocket is unavailable
+	PoolManagerAddress string   `json:"pool_manager_address"`
+	WatchedPools       []string `json:"watched_pools"`
+	PoolPollInterval   string   `json:"pool_poll_interval"`
+	// Clock drift is measured against block timestamps and ClockDriftNtpServer.
+	// ClockDriftPolicy is "warn" (default) or "refuse" to stop signing while
+	// drift exceeds ClockDriftThreshold.
+	ClockDriftThreshold     string `json:"clock_drift_threshold"`
+	ClockDriftMaxBlockLag   string `json:"clock_drift_max_block_lag"`
+	ClockDriftNtpServer     string `json:"clock_drift_ntp_server"`
+	ClockDriftCheckInterval string `json:"clock_drift_check_interval"`
+	ClockDriftPolicy        string `json:"clock_drift_policy"`
+	// Tasks are read from the service manager's NewAuctionTaskCreated events
+	// over EthWsUrl, falling back to polling EthRpcUrl every TaskPollInterval
+	// from the block in TaskCheckpointPath while the websocket is unavailable
+	ServiceManagerAddress string `json:"service_manager_address"`
+	TaskCheckpointPath    string `json:"task_checkpoint_path"`
+	TaskPollInterval      string `json:"task_poll_interval"`
+	// On L2s, SequencerFeedUrl is the sequencer's feed, of SequencerFeedKind
+	// "arbitrum" or "flashblocks", followed to learn of new blocks and task
+	// events before RPC nodes have them
+	SequencerFeedKind string `json:"sequencer_feed_kind"`
+	SequencerFeedUrl  string `json:"sequencer_feed_url"`
+	// RequestCompression is "auto" (default) to compress request bodies with
+	// whatever the aggregator advertises, "none", "gzip" or "zstd". Bodies
+	// smaller than RequestCompressionMinBytes are always sent as is.
+	RequestCompression         string `json:"request_compression"`
+	RequestCompressionMinBytes int    `json:"request_compression_min_bytes"`
+	// DebugIpPortAddress serves /debug/status when set
+	DebugIpPortAddress string `json:"debug_ip_port_address"`
+	// AggregatorTransport is "http" (default) to post responses to the
+	// aggregator, "websocket" to keep an authenticated connection open over
+	// which the aggregator also pushes new tasks, or "grpc" to use the
+	// aggregator's gRPC interface at AggregatorGrpcAddr, which pushes tasks too
+	AggregatorTransport string `json:"aggregator_transport"`
+	AggregatorGrpcAddr  string `json:"aggregator_grpc_address"`
+	// Signed acks of accepted responses are appended to AckLogPath. When
+	// AggregatorAckSigner is set, acks signed by any other address are refused.
+	AckLogPath          string `json:"ack_log_path"`
+	AggregatorAckSigner string `json:"aggregator_ack_signer"`
+	// ResponseSimulationPolicy is "off" (default), "warn" or "refuse". Unless
+	// off, every response is run through the service manager's respondToAuctionTask
+	// as an eth_call from AggregatorAckSigner before it is signed, and "refuse"
+	// drops responses the contract would reject.
+	ResponseSimulationPolicy string `json:"response_simulation_policy"`
+	// Winners are only signed when their bid was deposited in the escrow at
+	// AuctionEscrowAddress as of the task's reference block
+	AuctionEscrowAddress string `json:"auction_escrow_address"`
+	// Winners are determined by version AuctionRulesVersion of the auction
+	// rules (default the latest). Bids below AuctionMinBid wei aren't
+	// counted, and no bid wins below AuctionReservePrice wei. Every operator
+	// must run the same rules, or their responses won't aggregate.
+	AuctionRulesVersion uint32 `json:"auction_rules_version"`
+	AuctionMinBid       string `json:"auction_min_bid"`
+	AuctionReservePrice string `json:"auction_reserve_price"`
+	// PoolsFile lists the pools auctions run for. Tasks for other pools are
+	// not responded to, and a listed pool's reserve price replaces
+	// AuctionReservePrice. It must match the aggregator's.
+	PoolsFile string `json:"pools_file"`
+	// With CommitteeStakePercent set, only a committee sampled per task in
+	// proportion to stake responds. The committee holds at least that
+	// percentage of every quorum's stake, and 10 points above the task's
+	// threshold, with no fewer than CommitteeMinSize operators (default 2).
+	CommitteeStakePercent uint32 `json:"committee_stake_percent"`
+	CommitteeMinSize      int    `json:"committee_min_size"`
+	// With CommitteeVrfExpectedSize set instead, the operator responds when
+	// its VRF ticket, a BLS signature over the task hash, puts it among about
+	// that many operators drawn by stake, and sends the ticket as proof
+	CommitteeVrfExpectedSize uint32 `json:"committee_vrf_expected_size"`
+}
+
+type CurvePoolConfig struct {
+	Address    string `json:"address"`
+	Cryptoswap bool   `json:"cryptoswap"`
+}
+
+// PriceFeedConfig maps an oracle feed or exchange market onto the tokens
+// standing in for its base and quote assets
+type PriceFeedConfig struct {
+	FeedId        string `json:"feed_id"`
+	BaseToken     string `json:"base_token"`
+	BaseDecimals  uint8  `json:"base_decimals"`
+	QuoteToken    string `json:"quote_token"`
+	QuoteDecimals uint8  `json:"quote_decimals"`
+}
+
+// PricePoolConfig maps a pool onto its token pair
+type PricePoolConfig struct {
+	PoolId string `json:"pool_id"`
+	TokenA string `json:"token_a"`
+	TokenB string `json:"token_b"`
 }
 
 type AuctionTask struct {
-	PoolId                      common.Hash    `json:"poolId"`
-	BlockNumber                 uint32         `json:"blockNumber"`
-	TaskCreatedBlock            uint32         `json:"taskCreatedBlock"`
-	QuorumNumbers               types.QuorumNums `json:"quorumNumbers"`
-	QuorumThresholdPercentage   types.ThresholdPercentage `json:"quorumThresholdPercentage"`
+	TaskIndex                 uint32                          `json:"taskIndex"`
+	PoolId                    common.Hash                     `json:"poolId"`
+	BlockNumber               uint32                          `json:"blockNumber"`
+	TaskCreatedBlock          uint32                          `json:"taskCreatedBlock"`
+	QuorumNumbers             types.QuorumNums                `json:"quorumNumbers"`
+	QuorumThresholdPercentage types.QuorumThresholdPercentage `json:"quorumThresholdPercentage"`
 }
 
 type AuctionTaskResponse struct {
@@ -82,46 +407,72 @@ type AuctionTaskResponse struct {
 }
 
 type SignedAuctionTaskResponse struct {
-	AuctionTaskResponse
-	BlsSignature               types.Signature `json:"blsSignature"`
-	OperatorId                 types.OperatorId `json:"operatorId"`
+	TaskResponse AuctionTaskResponse `json:"taskResponse"`
+	BlsSignature bls.Signature       `json:"blsSignature"`
+	OperatorId   types.OperatorId    `json:"operatorId"`
+	// CommitteeProof is the operator's VRF ticket when committees are
+	// selected by VRF
+	CommitteeProof *bls.Signature `json:"committeeProof,omitempty"`
 }
 
 type TaskResponseInfo struct {
-	TaskResponse *AuctionTaskResponse
-	BlsSignature types.Signature
-	OperatorId   types.OperatorId
+	TaskResponse   *AuctionTaskResponse
+	BlsSignature   bls.Signature
+	OperatorId     types.OperatorId
+	CommitteeProof *bls.Signature
+	// TaskCreatedBlock opens the task's response window
+	TaskCreatedBlock uint32
 }
 
 func NewOperator(config Config, logger logging.Logger) (*Operator, error) {
-	var logLevel logging.LogLevel
-	if config.EnableMetrics {
-		logLevel = logging.Development
-	} else {
-		logLevel = logging.Production
+	config, err := applyNetworkPreset(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid network: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
 
+	// Keep recent errors for the diagnostics endpoint
+	errorRing := diagnostics.NewErrorRing(diagnostics.DefaultErrorRingSize)
+	logger = diagnostics.NewRecordingLogger(logger, errorRing)
 	logger = logger.With("component", "operator")
 
-	ethClient, err := eth.NewClient(config.EthRpcUrl)
+	rpcCache, err := newRpcCache(config)
+	if err != nil {
+		return nil, err
+	}
+	ethRpcUrl := config.EthRpcUrl
+	if rpcCache != nil {
+		ethRpcUrl = rpcCache.Url()
+	}
+
+	ethClient, err := eth.NewClient(ethRpcUrl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create eth client: %w", err)
 	}
 
-	operatorEcdsaPrivateKey, err := crypto.LoadECDSA(config.EcdsaPrivateKeyStorePath)
+	ecdsaSigner, err := newEcdsaSigner(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load operator ecdsa private key: %w", err)
+		return nil, err
 	}
 
-	operatorAddr := crypto.PubkeyToAddress(operatorEcdsaPrivateKey.PublicKey)
+	operatorAddr := ecdsaSigner.Address()
 	logger.Info("Operator address", "address", operatorAddr.Hex())
 
-	blsKeyPair, err := types.ReadBlsPrivateKeyFromFile(config.BlsPrivateKeyStorePath, "")
+	chainIdCtx, cancelChainId := context.WithTimeout(context.Background(), chainIdTimeout)
+	chainId, err := networks.ResolveChainId(chainIdCtx, ethClient, config.Network, config.ChainId)
+	cancelChainId()
+	if err != nil {
+		return nil, err
+	}
+
+	blsKeyPair, err := readBlsKey(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read bls private key: %w", err)
 	}
 
-	operatorId := types.OperatorIdFromG1Pubkey(blsKeyPair.PubkeyG1)
+	operatorId := types.OperatorIdFromG1Pubkey(blsKeyPair.GetPubKeyG1())
 	logger.Info("Operator ID", "operatorId", hex.EncodeToString(operatorId[:]))
 
 	// Create AVS clients
@@ -139,24 +490,341 @@ func NewOperator(config Config, logger logging.Logger) (*Operator, error) {
 		common.HexToAddress(config.RegistryCoordinatorAddress),
 		common.HexToAddress(config.OperatorStateRetrieverAddress),
 		ethClient,
-		operatorEcdsaPrivateKey,
+		ecdsaSigner,
+		chainId,
+		config.RegistrationConfirmations,
 		logger,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create avs registry chain writer: %w", err)
 	}
 
+	poolWeights := make(map[common.Hash]uint64, len(config.PoolWeights))
+	for poolId, weight := range config.PoolWeights {
+		poolWeights[common.HexToHash(poolId)] = weight
+	}
+	var subgraphClient *subgraph.Client
+	if config.SubgraphUrl != "" {
+		subgraphClient, err = newSubgraphClient(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create subgraph client: %w", err)
+		}
+	}
+
 	// Create metrics registry
 	var metricsReg *prometheus.Registry
 	var eigenMetrics metrics.Metrics
 	if config.EnableMetrics {
 		metricsReg = prometheus.NewRegistry()
-		eigenMetrics = metrics.NewPrometheusMetrics(metricsReg, "eigenlvr", logger)
-		eigenMetrics.Start(context.Background(), config.EigenMetricsIpPortAddress)
+		eigenMetrics = metrics.NewEigenMetrics("eigenlvr", config.EigenMetricsIpPortAddress, metricsReg, logger)
+		eigenMetrics.Start(context.Background(), metricsReg)
 	} else {
 		metricsReg = prometheus.NewRegistry()
 		eigenMetrics = metrics.NewNoopMetrics()
 	}
+	if rpcCache != nil {
+		metricsReg.MustRegister(rpcCache.Collector())
+	}
+
+	// Create rewards tracker
+	var rewardsTracker *rewards.Tracker
+	if config.RewardsCoordinatorAddress != "" {
+		pollInterval := time.Hour
+		if config.RewardsPollInterval != "" {
+			pollInterval, err = time.ParseDuration(config.RewardsPollInterval)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rewards poll interval: %w", err)
+			}
+		}
+
+		coordinator, err := rewards.NewCoordinator(common.HexToAddress(config.RewardsCoordinatorAddress), ethClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create rewards coordinator client: %w", err)
+		}
+
+		rewardsTracker = rewards.NewTracker(
+			operatorAddr,
+			coordinator,
+			rewards.NewApiClient(config.RewardsApiUrl),
+			pollInterval,
+			metricsReg,
+			logger,
+		)
+	}
+
+	// Create scheduled rewards claimer
+	var rewardsClaimer *rewards.Claimer
+	var autoClaimInterval time.Duration
+	if config.RewardsCoordinatorAddress != "" && config.AutoClaimRewardsInterval != "" {
+		autoClaimInterval, err = time.ParseDuration(config.AutoClaimRewardsInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auto claim rewards interval: %w", err)
+		}
+
+		rewardsClaimer, err = newRewardsClaimer(config, ethClient, ecdsaSigner, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Create delegation monitor
+	var delegationMonitor *delegation.Monitor
+	if config.DelegationManagerAddress != "" {
+		pollInterval := time.Minute
+		if config.DelegationPollInterval != "" {
+			pollInterval, err = time.ParseDuration(config.DelegationPollInterval)
+			if err != nil {
+				return nil, fmt.Errorf("invalid delegation poll interval: %w", err)
+			}
+		}
+
+		largeChangeBps := config.LargeDelegationChangeBps
+		if largeChangeBps == 0 {
+			largeChangeBps = defaultLargeDelegationChangeBps
+		}
+
+		contracts, err := delegation.NewContracts(
+			context.Background(),
+			common.HexToAddress(config.DelegationManagerAddress),
+			common.HexToAddress(config.RegistryCoordinatorAddress),
+			ethClient,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create delegation contracts: %w", err)
+		}
+
+		strategies := make([]common.Address, 0, len(config.DelegationStrategies))
+		for _, strategy := range config.DelegationStrategies {
+			strategies = append(strategies, common.HexToAddress(strategy))
+		}
+
+		delegationMonitor = delegation.NewMonitor(
+			operatorAddr,
+			operatorId,
+			strategies,
+			contracts,
+			pollInterval,
+			largeChangeBps,
+			metricsReg,
+			logger,
+		)
+	}
+
+	// Create response outbox
+	responseResendWindow := defaultResponseResendWindow
+	if config.ResponseResendWindow != "" {
+		responseResendWindow, err = time.ParseDuration(config.ResponseResendWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response resend window: %w", err)
+		}
+	}
+	responseResendInterval := defaultResponseResendInterval
+	if config.ResponseResendInterval != "" {
+		responseResendInterval, err = time.ParseDuration(config.ResponseResendInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response resend interval: %w", err)
+		}
+	}
+
+	responseResendMaxInterval := defaultResponseResendMaxInterval
+	if config.ResponseResendMaxInterval != "" {
+		responseResendMaxInterval, err = time.ParseDuration(config.ResponseResendMaxInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response resend max interval: %w", err)
+		}
+	}
+	if responseResendMaxInterval < responseResendInterval {
+		responseResendMaxInterval = responseResendInterval
+	}
+
+	responseOutbox, err := newResponseOutbox(config.ResponseOutboxPath, metricsReg)
+	if err != nil {
+		return nil, err
+	}
+	deadLetters, err := newDeadLetterQueue(config.ResponseDeadLetterPath, metricsReg)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregatorRequestTimeout := defaultAggregatorRequestTimeout
+	if config.AggregatorRequestTimeout != "" {
+		aggregatorRequestTimeout, err = time.ParseDuration(config.AggregatorRequestTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid aggregator request timeout: %w", err)
+		}
+	}
+
+	var ackSigner common.Address
+	if config.AggregatorAckSigner != "" {
+		if !common.IsHexAddress(config.AggregatorAckSigner) {
+			return nil, fmt.Errorf("invalid aggregator ack signer %q", config.AggregatorAckSigner)
+		}
+		ackSigner = common.HexToAddress(config.AggregatorAckSigner)
+	}
+	ackLog, err := newAckLog(config.AckLogPath, ackSigner, logger)
+	if err != nil {
+		return nil, err
+	}
+	if queued := responseOutbox.Len(); queued > 0 {
+		logger.Info("Loaded queued task responses", "queued", queued)
+	}
+
+	// Set up hot-standby failover
+	failover, err := newFailover(config, operatorId, metricsReg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create cross-venue price sampler
+	var priceVenues []venues.Venue
+	if config.UniswapV3FactoryAddress != "" {
+		uniswapV3, err := venues.NewUniswapV3(common.HexToAddress(config.UniswapV3FactoryAddress), config.UniswapV3FeeTiers, ethClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create uniswap v3 venue: %w", err)
+		}
+		priceVenues = append(priceVenues, uniswapV3)
+	}
+	if len(config.CurvePools) > 0 {
+		curvePools := make([]venues.CurvePool, 0, len(config.CurvePools))
+		for _, pool := range config.CurvePools {
+			curvePools = append(curvePools, venues.CurvePool{
+				Address:    common.HexToAddress(pool.Address),
+				Cryptoswap: pool.Cryptoswap,
+			})
+		}
+		priceVenues = append(priceVenues, venues.NewCurve(curvePools, ethClient))
+	}
+	if config.DataStreamsApiUrl != "" {
+		dataStreams, err := newDataStreamsVenue(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create data streams venue: %w", err)
+		}
+		priceVenues = append(priceVenues, dataStreams)
+	}
+	if config.PythHermesUrl != "" {
+		pyth, err := newPythVenue(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create pyth venue: %w", err)
+		}
+		priceVenues = append(priceVenues, pyth)
+	}
+	if len(config.ChainlinkFeeds) > 0 {
+		chainlink, err := newChainlinkVenue(config, ethClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create chainlink venue: %w", err)
+		}
+		priceVenues = append(priceVenues, chainlink)
+	}
+	var exchange *venues.Exchange
+	if config.ExchangeKind != "" {
+		exchange, err = newExchangeVenue(config, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create exchange venue: %w", err)
+		}
+		priceVenues = append(priceVenues, exchange)
+	}
+	venueSampler := venues.NewSampler(priceVenues, logger)
+	priceFeed, err := newPriceFeed(config, venueSampler)
+	if err != nil {
+		return nil, err
+	}
+	poolWatcher, err := newPoolWatcher(config, ethClient, metricsReg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create clock drift monitor
+	clockDriftConfig := clockdrift.Config{
+		Threshold:   defaultClockDriftThreshold,
+		MaxBlockLag: defaultClockDriftMaxBlockLag,
+		NtpServer:   config.ClockDriftNtpServer,
+		Interval:    defaultClockDriftCheckInterval,
+	}
+	if config.ClockDriftThreshold != "" {
+		clockDriftConfig.Threshold, err = time.ParseDuration(config.ClockDriftThreshold)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clock drift threshold: %w", err)
+		}
+	}
+	if config.ClockDriftMaxBlockLag != "" {
+		clockDriftConfig.MaxBlockLag, err = time.ParseDuration(config.ClockDriftMaxBlockLag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clock drift max block lag: %w", err)
+		}
+	}
+	if config.ClockDriftCheckInterval != "" {
+		clockDriftConfig.Interval, err = time.ParseDuration(config.ClockDriftCheckInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid clock drift check interval: %w", err)
+		}
+	}
+	switch config.ClockDriftPolicy {
+	case "", "warn", "refuse":
+	default:
+		return nil, fmt.Errorf("invalid clock drift policy %q", config.ClockDriftPolicy)
+	}
+
+	// Create task event watcher
+	var taskWatcher *logwatcher.Watcher
+	if config.ServiceManagerAddress != "" {
+		var pollInterval time.Duration
+		if config.TaskPollInterval != "" {
+			pollInterval, err = time.ParseDuration(config.TaskPollInterval)
+			if err != nil {
+				return nil, fmt.Errorf("invalid task poll interval: %w", err)
+			}
+		}
+
+		taskWatcher = logwatcher.NewWatcher(
+			logwatcher.Config{
+				Name:         "tasks",
+				Addresses:    []common.Address{common.HexToAddress(config.ServiceManagerAddress)},
+				Topics:       [][]common.Hash{{servicemanager.NewAuctionTaskCreatedTopic}},
+				WsUrl:        config.EthWsUrl,
+				PollInterval: pollInterval,
+			},
+			ethClient,
+			logwatcher.NewFileCheckpoint(config.TaskCheckpointPath),
+			metricsReg,
+			logger,
+		)
+	}
+
+	sequencerFeed, err := newSequencerFeed(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var serviceManager *servicemanager.Reader
+	if config.ServiceManagerAddress != "" {
+		serviceManager = servicemanager.NewReader(common.HexToAddress(config.ServiceManagerAddress), ethClient)
+	}
+	responseSimulator, err := newResponseSimulator(config, serviceManager)
+	if err != nil {
+		return nil, err
+	}
+	escrowReader, err := newEscrowReader(config, ethClient)
+	if err != nil {
+		return nil, err
+	}
+	auctionRules, err := newAuctionRules(config)
+	if err != nil {
+		return nil, err
+	}
+	pools, err := loadPoolRegistry(config, logger)
+	if err != nil {
+		return nil, err
+	}
+	committeeSampler, err := newCommitteeSampler(config, serviceManager, avsReader, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Negotiate request body compression with the aggregator
+	requestCompressor, err := compression.NewNegotiator(config.RequestCompression, config.RequestCompressionMinBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request compression: %w", err)
+	}
 
 	// Create node API
 	var nodeApi *nodeapi.NodeApi
@@ -166,24 +834,96 @@ func NewOperator(config Config, logger logging.Logger) (*Operator, error) {
 	}
 
 	operator := &Operator{
-		config:                  config,
-		logger:                  logger,
-		ethClient:              ethClient,
-		metricsReg:             metricsReg,
-		metrics:                eigenMetrics,
-		nodeApi:                nodeApi,
-		avsWriter:              *avsWriter,
-		avsReader:              *avsReader,
-		blsKeypair:             blsKeyPair,
-		operatorId:             operatorId,
-		operatorAddr:           operatorAddr,
-		operatorEcdsaPrivateKey: operatorEcdsaPrivateKey,
-		auctionTasks:           make(map[uint32]*AuctionTask),
-		taskResponseChan:       make(chan TaskResponseInfo, 100),
-	}
-
-	if config.RegisterOperatorOnStartup {
-		operator.registerOperatorOnStartup()
+		config:                    config,
+		logger:                    logger,
+		ethClient:                 ethClient,
+		metricsReg:                metricsReg,
+		metrics:                   eigenMetrics,
+		taskMetrics:               newTaskMetrics(metricsReg),
+		nodeApi:                   nodeApi,
+		avsWriter:                 *avsWriter,
+		avsReader:                 *avsReader,
+		blsKeypair:                blsKeyPair,
+		operatorId:                operatorId,
+		operatorAddr:              operatorAddr,
+		ecdsaSigner:               ecdsaSigner,
+		auctionTasks:              make(map[uint32]*AuctionTask),
+		taskResponseChan:          make(chan TaskResponseInfo, 100),
+		taskQueue:                 newTaskQueue(),
+		poolWeights:               poolWeights,
+		subgraph:                  subgraphClient,
+		rewardsTracker:            rewardsTracker,
+		rewardsClaimer:            rewardsClaimer,
+		autoClaimInterval:         autoClaimInterval,
+		delegationMonitor:         delegationMonitor,
+		responseOutbox:            responseOutbox,
+		deadLetters:               deadLetters,
+		ackLog:                    ackLog,
+		responseResendWindow:      responseResendWindow,
+		responseResendInterval:    responseResendInterval,
+		responseResendMaxInterval: responseResendMaxInterval,
+		failover:                  failover,
+		venueSampler:              venueSampler,
+		exchange:                  exchange,
+		priceFeed:                 priceFeed,
+		poolWatcher:               poolWatcher,
+		clockDrift:                clockdrift.NewMonitor(clockDriftConfig, ethClient, metricsReg, logger),
+		refuseOnClockDrift:        config.ClockDriftPolicy == "refuse",
+		responseSimulator:         responseSimulator,
+		escrow:                    escrowReader,
+		auctionRules:              auctionRules,
+		pools:                     pools,
+		committee:                 committeeSampler,
+		taskWatcher:               taskWatcher,
+		sequencerFeed:             sequencerFeed,
+		diagnostics:               diagnostics.NewCollector("eigenlvr-operator", SemVer, errorRing),
+		watchdog:                  sdnotify.NewWatchdog(),
+	}
+	// Bid books are read over HTTP unless the transport is gRPC
+	httpClient := newAggregatorClient(config.AggregatorServerIpPortAddr, aggregatorRequestTimeout, requestCompressor, logger)
+	operator.bidBook = httpClient
+	switch config.AggregatorTransport {
+	case "", AggregatorTransportHttp:
+		operator.responseSender = httpClient
+	case AggregatorTransportWebsocket:
+		operator.aggregatorStream = newAggregatorStream(
+			config.AggregatorServerIpPortAddr,
+			ecdsaSigner,
+			operatorId,
+			operator.handlePushedTask,
+			logger,
+		)
+		operator.responseSender = operator.aggregatorStream
+	case AggregatorTransportGrpc:
+		if config.AggregatorGrpcAddr == "" {
+			return nil, errors.New("the grpc aggregator transport requires aggregator_grpc_address")
+		}
+		operator.aggregatorGrpc, err = newAggregatorGrpcClient(
+			config.AggregatorGrpcAddr,
+			aggregatorRequestTimeout,
+			operator.handlePushedTask,
+			logger,
+		)
+		if err != nil {
+			return nil, err
+		}
+		operator.responseSender = operator.aggregatorGrpc
+		operator.bidBook = operator.aggregatorGrpc
+	default:
+		return nil, fmt.Errorf("invalid aggregator transport %q", config.AggregatorTransport)
+	}
+
+	if subgraphClient != nil {
+		operator.poolValuer = subgraphClient
+	}
+
+	operator.registerDiagnostics()
+
+	// A standby shares the primary's registration
+	if config.RegisterOperatorOnStartup && config.FailoverRole != FailoverRoleStandby {
+		if err := operator.registerOperatorOnStartup(); err != nil {
+			return nil, fmt.Errorf("failed to register operator: %w", err)
+		}
 	}
 
 	return operator, nil
@@ -192,42 +932,137 @@ func NewOperator(config Config, logger logging.Logger) (*Operator, error) {
 func (o *Operator) Start(ctx context.Context) error {
 	o.logger.Info("Starting operator")
 
+	// Serve runtime diagnostics
+	if o.config.DebugIpPortAddress != "" {
+		go o.startDebugServer(ctx)
+	}
+
+	// Start checking the local clock against the chain and ntp
+	go o.clockDrift.Start(ctx)
+
+	// Start failover heartbeats or primary monitoring
+	go o.failover.Start(ctx)
+
+	// Keep the aggregator websocket or gRPC task stream connected
+	if o.aggregatorStream != nil {
+		go o.aggregatorStream.Run(ctx)
+	}
+	if o.aggregatorGrpc != nil {
+		go o.aggregatorGrpc.Run(ctx)
+	}
+
 	// Start task response processing
 	go o.processTaskResponses(ctx)
 
+	// Resend responses queued while the aggregator was unreachable
+	go o.resendQueuedResponses(ctx)
+
+	// Start processing queued tasks in priority order
+	go o.processTaskQueue(ctx)
+
+	// Start tracking rewards
+	if o.rewardsTracker != nil {
+		go o.rewardsTracker.Start(ctx)
+	}
+	if o.rewardsClaimer != nil {
+		go o.autoClaimRewards(ctx, o.autoClaimInterval)
+	}
+
+	// Start monitoring delegated stake
+	if o.delegationMonitor != nil {
+		go o.delegationMonitor.Start(ctx)
+		go o.handleDelegationEvents(ctx)
+	}
+
+	// Start streaming exchange tickers and tracking pool state
+	if o.exchange != nil {
+		go o.exchange.Run(ctx)
+	}
+	if o.poolWatcher != nil {
+		go o.watchPools(ctx)
+	}
+
 	// Start listening for new tasks
 	go o.listenForNewTasks(ctx)
+	if o.sequencerFeed != nil {
+		go o.followSequencerFeed(ctx)
+	}
+
+	// Report readiness and liveness to systemd
+	go o.notifyReady(ctx)
+	go o.watchdog.Run(ctx, o.logger)
 
 	// Keep the operator running
 	<-ctx.Done()
+	sdnotify.Stopping()
 	return nil
 }
 
-func (o *Operator) registerOperatorOnStartup() {
-	o.logger.Info("Registering operator on startup")
+// registerOperatorOnStartup registers the operator in quorum 0 unless it is
+// already registered, waiting for the registration to be confirmed
+func (o *Operator) registerOperatorOnStartup() error {
+	ctx, cancel := context.WithTimeout(context.Background(), registrationTimeout)
+	defer cancel()
 
 	quorumNumbers := types.QuorumNums{0} // Join quorum 0
 	socket := "localhost:9090"
 
-	// In a real implementation, you would:
-	// 1. Generate BLS signature for registration
-	// 2. Call the actual registration function
-	// For now, we'll simulate this
-	
+	registered, err := o.avsReader.IsOperatorRegistered(&bind.CallOpts{Context: ctx}, o.operatorAddr)
+	if err != nil {
+		return fmt.Errorf("failed to check operator registration: %w", err)
+	}
+	if registered {
+		o.logger.Info("Operator already registered", "operatorId", hex.EncodeToString(o.operatorId[:]))
+		return nil
+	}
+
+	o.logger.Info("Registering operator on startup")
+
+	// The salt makes the registration signature single use
+	var salt [32]byte
+	if _, err := rand.Read(salt[:]); err != nil {
+		return fmt.Errorf("failed to generate registration salt: %w", err)
+	}
+	expiry := big.NewInt(time.Now().Add(registrationSignatureValidity).Unix())
+
+	txHash, err := o.avsWriter.RegisterOperatorInQuorumWithAVSRegistryCoordinator(
+		ctx,
+		o.ecdsaSigner,
+		salt,
+		expiry,
+		o.blsKeypair,
+		quorumNumbers.UnderlyingType(),
+		socket,
+	)
+	if err != nil {
+		return err
+	}
+
 	o.logger.Info("Operator registration completed",
+		"txHash", txHash.Hex(),
 		"quorumNumbers", quorumNumbers,
 		"socket", socket,
 		"operatorId", hex.EncodeToString(o.operatorId[:]),
 	)
+	return nil
 }
 
 func (o *Operator) listenForNewTasks(ctx context.Context) {
 	o.logger.Info("Starting to listen for new tasks")
 
-	// In a real implementation, this would:
-	// 1. Subscribe to NewAuctionTaskCreated events
-	// 2. Process incoming tasks
-	// 3. Send responses to aggregator
+	if o.taskWatcher != nil {
+		if err := o.taskWatcher.Run(ctx, func(log gethtypes.Log) error {
+			o.handleNewTaskLog(ctx, log, "event")
+			return nil
+		}); err != nil {
+			o.logger.Error("Task watcher stopped", "error", err)
+		}
+		return
+	}
+
+	// Without a service manager there are no task events to follow, so
+	// simulate tasks for local development
+	o.logger.Warn("No service_manager_address configured, simulating tasks for local development")
 
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
@@ -238,42 +1073,146 @@ func (o *Operator) listenForNewTasks(ctx context.Context) {
 			return
 		case <-ticker.C:
 			// Simulate receiving a task
-			o.simulateTaskProcessing()
+			o.enqueueTask(ctx, o.simulateTask())
 		}
 	}
 }
 
-func (o *Operator) simulateTaskProcessing() {
-	// This is a simplified simulation of auction task processing
-	task := &AuctionTask{
+// handleNewTaskLog decodes a NewAuctionTaskCreated log and queues the task.
+// Malformed logs are skipped rather than stopping the watcher.
+func (o *Operator) handleNewTaskLog(ctx context.Context, log gethtypes.Log, source string) {
+	event, err := servicemanager.ParseNewAuctionTaskCreated(log)
+	if err != nil {
+		o.logger.Warn("Failed to decode task event", "txHash", log.TxHash.Hex(), "error", err)
+		return
+	}
+
+	quorumNumbers := make(types.QuorumNums, len(event.Task.QuorumNumbers))
+	for i, quorum := range event.Task.QuorumNumbers {
+		quorumNumbers[i] = types.QuorumNum(quorum)
+	}
+
+	o.addTask(ctx, &AuctionTask{
+		TaskIndex:                 event.TaskIndex,
+		PoolId:                    event.Task.PoolId,
+		BlockNumber:               uint32(event.Task.BlockNumber.Uint64()),
+		TaskCreatedBlock:          uint32(event.Task.TaskCreatedBlock.Uint64()),
+		QuorumNumbers:             quorumNumbers,
+		QuorumThresholdPercentage: types.QuorumThresholdPercentage(event.Task.QuorumThresholdPercentage),
+	}, source)
+}
+
+// handlePushedTask queues a task pushed by the aggregator over the websocket
+func (o *Operator) handlePushedTask(ctx context.Context, pushed wsproto.Task) {
+	quorumNumbers := make(types.QuorumNums, len(pushed.QuorumNumbers))
+	for i, quorum := range pushed.QuorumNumbers {
+		quorumNumbers[i] = types.QuorumNum(quorum)
+	}
+
+	o.addTask(ctx, &AuctionTask{
+		TaskIndex:                 pushed.TaskIndex,
+		PoolId:                    pushed.PoolId,
+		BlockNumber:               pushed.BlockNumber,
+		TaskCreatedBlock:          pushed.TaskCreatedBlock,
+		QuorumNumbers:             quorumNumbers,
+		QuorumThresholdPercentage: types.QuorumThresholdPercentage(pushed.QuorumThresholdPercentage),
+	}, "aggregator")
+}
+
+// addTask records and queues a new task. The same task can arrive both from
+// chain events and from the aggregator, so tasks already known are skipped.
+func (o *Operator) addTask(ctx context.Context, task *AuctionTask, source string) {
+	o.auctionTasksMutex.Lock()
+	if _, known := o.auctionTasks[task.TaskIndex]; known {
+		o.auctionTasksMutex.Unlock()
+		return
+	}
+	o.auctionTasks[task.TaskIndex] = task
+	o.auctionTasksMutex.Unlock()
+	o.taskMetrics.taskReceived(source)
+
+	o.logger.Info("New auction task",
+		"taskIndex", task.TaskIndex,
+		"poolId", task.PoolId.Hex(),
+		"taskCreatedBlock", task.TaskCreatedBlock,
+		"source", source,
+	)
+
+	o.enqueueTask(ctx, task)
+}
+
+func (o *Operator) simulateTask() *AuctionTask {
+	// This is a simplified simulation of an incoming auction task
+	return &AuctionTask{
 		PoolId:                    common.HexToHash("0x123456789abcdef"),
 		BlockNumber:               uint32(time.Now().Unix()),
 		TaskCreatedBlock:          uint32(time.Now().Unix()),
 		QuorumNumbers:             types.QuorumNums{0},
 		QuorumThresholdPercentage: 67, // 67% threshold
 	}
+}
 
+func (o *Operator) processTask(task *AuctionTask) error {
 	o.logger.Info("Processing auction task",
 		"poolId", task.PoolId.Hex(),
 		"blockNumber", task.BlockNumber,
 	)
 
-	// Simulate auction logic
-	response := &AuctionTaskResponse{
-		ReferenceTaskIndex: 0,
-		Winner:             common.HexToAddress("0x742d35Cc6608C8B29a1b8d9c0f6f8aD5b7c8b0A1"),
-		WinningBid:         big.NewInt(1000000000000000000), // 1 ETH
-		TotalBids:          5,
+	// Auctions only run for the registered pools
+	if _, err := o.pools.Pool(task.PoolId); err != nil {
+		o.logger.Debug("Pool is not registered, skipping task response", "taskIndex", task.TaskIndex, "poolId", task.PoolId.Hex())
+		return nil
+	}
+
+	// A passive standby follows tasks but leaves responding to the primary
+	if !o.failover.IsActive() {
+		o.logger.Debug("Standby is passive, skipping task response", "poolId", task.PoolId.Hex())
+		return nil
+	}
+
+	// Operators outside the task's committee leave it to the members
+	selected, committeeProof := o.inCommittee(task)
+	if !selected {
+		o.logger.Debug("Not in task committee, skipping task response", "taskIndex", task.TaskIndex)
+		return nil
+	}
+
+	// Settle the auction from the bids revealed for the task's block
+	response, err := o.runAuction(context.Background(), task)
+	if err != nil {
+		return err
+	}
+
+	// Auction windows are time sensitive, don't sign with a drifting clock
+	if o.refuseOnClockDrift {
+		if err := o.clockDrift.Err(); err != nil {
+			return fmt.Errorf("refusing to sign task response: %w", err)
+		}
+	}
+
+	// Never vouch for a winner that can't pay its bid
+	if err := o.checkEscrow(task, response); err != nil {
+		return err
+	}
+
+	// Catch responses the service manager would reject before signing them
+	if err := o.checkResponse(task, response); err != nil {
+		return err
 	}
 
 	// Sign the response
-	responseHash := o.hashTaskResponse(response)
-	blsSignature := o.blsKeypair.SignMessage(responseHash)
+	blsSignature, err := o.signTaskResponse(response)
+	if err != nil {
+		return err
+	}
+	o.taskMetrics.responseSigned()
 
 	taskResponseInfo := TaskResponseInfo{
-		TaskResponse: response,
-		BlsSignature: *blsSignature,
-		OperatorId:   o.operatorId,
+		TaskResponse:     response,
+		BlsSignature:     *blsSignature,
+		OperatorId:       o.operatorId,
+		CommitteeProof:   committeeProof,
+		TaskCreatedBlock: task.TaskCreatedBlock,
 	}
 
 	// Send to response channel
@@ -283,46 +1222,158 @@ func (o *Operator) simulateTaskProcessing() {
 	default:
 		o.logger.Warn("Task response channel is full, dropping response")
 	}
+
+	return nil
+}
+
+// signTaskResponse signs the response digest and verifies the signature against
+// the operator's own G2 public key before it is sent anywhere, so a key or
+// digest mismatch fails here rather than at the aggregator
+func (o *Operator) signTaskResponse(response *AuctionTaskResponse) (*bls.Signature, error) {
+	responseHash, err := o.hashTaskResponse(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash task response: %w", err)
+	}
+
+	blsSignature := o.blsKeypair.SignMessage(responseHash)
+
+	valid, err := blsSignature.Verify(o.blsKeypair.GetPubKeyG2(), responseHash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSignatureSelfCheckFailed, err)
+	}
+	if !valid {
+		return nil, fmt.Errorf("%w: signature over digest %s does not verify against operator pubkey",
+			ErrSignatureSelfCheckFailed, common.Hash(responseHash).Hex())
+	}
+
+	return blsSignature, nil
 }
 
 func (o *Operator) processTaskResponses(ctx context.Context) {
 	o.logger.Info("Starting task response processor")
 
+	heartbeat := time.NewTicker(watchdogBeatInterval)
+	defer heartbeat.Stop()
+	o.watchdog.Beat("taskResponses")
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-heartbeat.C:
+			o.watchdog.Beat("taskResponses")
 		case taskResponseInfo := <-o.taskResponseChan:
-			o.sendTaskResponseToAggregator(taskResponseInfo)
+			o.sendTaskResponseToAggregator(ctx, taskResponseInfo)
+			o.watchdog.Beat("taskResponses")
 		}
 	}
 }
 
-func (o *Operator) sendTaskResponseToAggregator(taskResponseInfo TaskResponseInfo) {
+func (o *Operator) sendTaskResponseToAggregator(ctx context.Context, taskResponseInfo TaskResponseInfo) {
 	o.logger.Info("Sending task response to aggregator",
 		"taskIndex", taskResponseInfo.TaskResponse.ReferenceTaskIndex,
 		"winner", taskResponseInfo.TaskResponse.Winner.Hex(),
 		"winningBid", taskResponseInfo.TaskResponse.WinningBid.String(),
 	)
 
-	// In a real implementation, this would send the response to the aggregator
-	// via HTTP/gRPC/WebSocket connection
-	
 	signedTaskResponse := SignedAuctionTaskResponse{
-		AuctionTaskResponse: *taskResponseInfo.TaskResponse,
-		BlsSignature:        taskResponseInfo.BlsSignature,
-		OperatorId:          taskResponseInfo.OperatorId,
+		TaskResponse:   *taskResponseInfo.TaskResponse,
+		BlsSignature:   taskResponseInfo.BlsSignature,
+		OperatorId:     taskResponseInfo.OperatorId,
+		CommitteeProof: taskResponseInfo.CommitteeProof,
+	}
+	o.logSignedTaskResponse(signedTaskResponse)
+
+	signedAck, err := o.sendTaskResponse(ctx, signedTaskResponse)
+	if err == nil {
+		o.logger.Info("Task response accepted by aggregator",
+			"taskIndex", signedTaskResponse.TaskResponse.ReferenceTaskIndex,
+		)
+		o.recordAck(signedTaskResponse, signedAck)
+		return
+	}
+	now := time.Now()
+	entry := queuedResponse{
+		Response:    signedTaskResponse,
+		QueuedAt:    now,
+		Deadline:    o.responseDeadline(ctx, taskResponseInfo.TaskCreatedBlock),
+		Attempts:    1,
+		LastAttempt: now,
+		LastError:   err.Error(),
+	}
+	if errors.Is(err, ErrResponseRejected) {
+		o.logger.Error("Aggregator rejected task response",
+			"taskIndex", signedTaskResponse.TaskResponse.ReferenceTaskIndex,
+			"error", err,
+		)
+		o.deadLetter(entry, deadLetterRejected)
+		return
 	}
 
-	// Simulate sending to aggregator
-	responseJson, _ := json.MarshalIndent(signedTaskResponse, "", "  ")
-	o.logger.Info("Signed task response", "response", string(responseJson))
+	// The aggregator is unreachable, keep the response until the task window closes
+	if err := o.responseOutbox.Add(entry); err != nil {
+		o.logger.Error("Failed to queue task response", "error", err)
+	}
+
+	o.logger.Warn("Aggregator unreachable, queued task response for resend",
+		"taskIndex", signedTaskResponse.TaskResponse.ReferenceTaskIndex,
+		"queued", o.responseOutbox.Len(),
+		"error", err,
+	)
 }
 
-func (o *Operator) hashTaskResponse(taskResponse *AuctionTaskResponse) [32]byte {
-	// Create hash of the task response for signing
-	responseBytes, _ := json.Marshal(taskResponse)
-	return crypto.Keccak256Hash(responseBytes)
+// responseDeadline is when the task's response window closes,
+// ResponseResendWindow after its creation block. Without the block's time,
+// the window is counted from now.
+func (o *Operator) responseDeadline(ctx context.Context, taskCreatedBlock uint32) time.Time {
+	header, err := o.ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(uint64(taskCreatedBlock)))
+	if err != nil {
+		o.logger.Warn("Failed to read task creation block, counting the response window from now",
+			"taskCreatedBlock", taskCreatedBlock,
+			"error", err,
+		)
+		return time.Now().Add(o.responseResendWindow)
+	}
+	return time.Unix(int64(header.Time), 0).Add(o.responseResendWindow)
+}
+
+// logSignedTaskResponse logs the whole signed response for debugging. The
+// redacting logger replaces its signatures.
+func (o *Operator) logSignedTaskResponse(signedTaskResponse SignedAuctionTaskResponse) {
+	o.logger.Debug("Signed task response", "response", signedTaskResponse)
+}
+
+func (o *Operator) hashTaskResponse(taskResponse *AuctionTaskResponse) ([32]byte, error) {
+	// Sign the same digest the service manager checks: keccak256(abi.encode(taskResponse))
+	return digest.AuctionTaskResponseDigest(
+		taskResponse.ReferenceTaskIndex,
+		taskResponse.Winner,
+		taskResponse.WinningBid,
+		taskResponse.TotalBids,
+	)
+}
+
+// ReferencePrice returns the median spot price of the pair across the
+// configured Uniswap v3 and Curve venues and oracle feeds
+func (o *Operator) ReferencePrice(ctx context.Context, tokenA, tokenB common.Address) (venues.Reference, error) {
+	return o.venueSampler.Reference(ctx, venues.NewPair(tokenA, tokenB))
+}
+
+// MidPrice returns the pool's mid-price aggregated across the configured
+// venues, for pools listed in price_pools
+func (o *Operator) MidPrice(ctx context.Context, poolId common.Hash) (pricefeed.MidPrice, error) {
+	mid, err := o.priceFeed.MidPrice(ctx, poolId)
+	if err != nil {
+		return mid, err
+	}
+	o.taskMetrics.midPriceServed(mid)
+	return mid, nil
+}
+
+// GetQueuedResponseCount returns the number of task responses waiting to be
+// resent to the aggregator
+func (o *Operator) GetQueuedResponseCount() int {
+	return o.responseOutbox.Len()
 }
 
 // GetOperatorId returns the operator's ID
@@ -336,6 +1387,57 @@ func (o *Operator) GetOperatorAddress() common.Address {
 }
 
 // GetBlsPublicKey returns the operator's BLS public key
-func (o *Operator) GetBlsPublicKey() *types.G1Point {
-	return o.blsKeypair.PubkeyG1
-}
\ No newline at end of file
+func (o *Operator) GetBlsPublicKey() *bls.G1Point {
+	return o.blsKeypair.GetPubKeyG1()
+}
+
+// GetEarnings returns the operator's latest rewards snapshot, or false if
+// rewards tracking is not enabled
+func (o *Operator) GetEarnings() (rewards.Earnings, bool) {
+	if o.rewardsTracker == nil {
+		return rewards.Earnings{}, false
+	}
+	return o.rewardsTracker.Earnings(), true
+}
+
+// GetDelegation returns the latest delegated stake snapshot, or false if
+// delegation monitoring is not enabled
+func (o *Operator) GetDelegation() (delegation.Snapshot, bool) {
+	if o.delegationMonitor == nil {
+		return delegation.Snapshot{}, false
+	}
+	return o.delegationMonitor.Snapshot(), true
+}
+
+func (o *Operator) handleDelegationEvents(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-o.delegationMonitor.Events():
+			switch event.Kind {
+			case delegation.EventQuorumStandingChanged:
+				if event.MeetsMinimum {
+					o.logger.Info("Operator stake now meets quorum minimum",
+						"quorum", event.Quorum,
+						"previous", event.Previous.String(),
+						"current", event.Current.String(),
+					)
+				} else {
+					o.logger.Warn("Operator stake fell below quorum minimum",
+						"quorum", event.Quorum,
+						"previous", event.Previous.String(),
+						"current", event.Current.String(),
+					)
+				}
+			default:
+				o.logger.Info("Large delegation change",
+					"kind", event.Kind,
+					"strategy", event.Strategy.Hex(),
+					"previous", event.Previous.String(),
+					"current", event.Current.String(),
+				)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/redact"
+	"github.com/eigenlvr/avs/pkg/testutils/fakeeth"
+	"github.com/eigenlvr/avs/pkg/testutils/fixtures"
+)
+
+// capturingLogger keeps the tags of the debug messages it's given
+type capturingLogger struct {
+	logging.Logger
+	debug [][]any
+}
+
+func (l *capturingLogger) Debug(msg string, tags ...any) {
+	l.debug = append(l.debug, append([]any{msg}, tags...))
+}
+
+func TestSignedTaskResponseLogIsRedacted(t *testing.T) {
+	signer := fixtures.New("redact").Operator(0)
+	response := AuctionTaskResponse{ReferenceTaskIndex: 4, Winner: common.HexToAddress("0x01"), WinningBid: big.NewInt(5), TotalBids: 2}
+	signature := signer.BlsKeyPair.SignMessage([32]byte{4})
+	committeeProof := signer.BlsKeyPair.SignMessage([32]byte{5})
+
+	captured := &capturingLogger{Logger: logging.NewNoopLogger()}
+	o := &Operator{logger: redact.NewLogger(captured)}
+	o.logSignedTaskResponse(SignedAuctionTaskResponse{
+		TaskResponse:   response,
+		BlsSignature:   *signature,
+		OperatorId:     signer.OperatorId,
+		CommitteeProof: committeeProof,
+	})
+
+	if len(captured.debug) != 1 {
+		t.Fatalf("logged %d debug messages, want 1", len(captured.debug))
+	}
+	logged := fmt.Sprint(captured.debug[0]...)
+	for name, raw := range map[string]string{"signature": signature.String(), "committee proof": committeeProof.String()} {
+		if strings.Contains(logged, raw) {
+			t.Fatalf("logged the raw %s: %s", name, logged)
+		}
+	}
+	if !strings.Contains(logged, redact.Redacted) || !strings.Contains(logged, "taskResponse:{4 ") {
+		t.Fatalf("logged response %s, want its fields with the signatures redacted", logged)
+	}
+}
+
+func TestResponseDeadlineFollowsTaskCreation(t *testing.T) {
+	client := fakeeth.New(31337)
+	created := client.MineBlock()
+	client.MineBlocks(40)
+	o := &Operator{logger: logging.NewNoopLogger(), ethClient: client, responseResendWindow: 6 * time.Minute}
+
+	// A response queued late in the window only has the rest of it left
+	want := time.Unix(int64(created.Time), 0).Add(6 * time.Minute)
+	if deadline := o.responseDeadline(context.Background(), uint32(created.Number.Uint64())); !deadline.Equal(want) {
+		t.Fatalf("deadline %v, want %v, the window after the task's creation block", deadline, want)
+	}
+
+	// Without the creation block, the whole window is counted from now
+	before := time.Now()
+	deadline := o.responseDeadline(context.Background(), uint32(client.Head().Number.Uint64()+100))
+	if deadline.Before(before.Add(6*time.Minute)) || deadline.After(time.Now().Add(6*time.Minute)) {
+		t.Fatalf("deadline %v for an unknown block, want the window from now", deadline)
+	}
+}
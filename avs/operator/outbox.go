@@ -0,0 +1,259 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// queuedResponse is a signed response that could not be delivered yet
+type queuedResponse struct {
+	Id          uint64                    `json:"id"`
+	Response    SignedAuctionTaskResponse `json:"response"`
+	QueuedAt    time.Time                 `json:"queuedAt"`
+	Deadline    time.Time                 `json:"deadline"`
+	Attempts    int                       `json:"attempts"`
+	LastAttempt time.Time                 `json:"lastAttempt"`
+	LastError   string                    `json:"lastError"`
+}
+
+// responseOutbox holds responses the aggregator didn't accept because it was
+// unreachable. Entries are persisted to disk when a path is configured so
+// queued responses survive an operator restart.
+type responseOutbox struct {
+	mu      sync.Mutex
+	path    string
+	entries []queuedResponse
+	nextId  uint64
+
+	queuedGauge prometheus.Gauge
+}
+
+func newResponseOutbox(path string, reg prometheus.Registerer) (*responseOutbox, error) {
+	outbox := &responseOutbox{
+		path: path,
+		queuedGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator",
+			Name:      "queued_task_responses",
+			Help:      "Signed task responses waiting to be resent to the aggregator",
+		}),
+	}
+	reg.MustRegister(outbox.queuedGauge)
+
+	if path == "" {
+		return outbox, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return outbox, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response outbox: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &outbox.entries); err != nil {
+		return nil, fmt.Errorf("failed to decode response outbox: %w", err)
+	}
+	for _, entry := range outbox.entries {
+		if entry.Id >= outbox.nextId {
+			outbox.nextId = entry.Id + 1
+		}
+	}
+	outbox.queuedGauge.Set(float64(len(outbox.entries)))
+
+	return outbox, nil
+}
+
+// Add queues a response for resending until its deadline
+func (o *responseOutbox) Add(entry queuedResponse) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entry.Id = o.nextId
+	o.nextId++
+	o.entries = append(o.entries, entry)
+	return o.save()
+}
+
+// Len returns the number of queued responses
+func (o *responseOutbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return len(o.entries)
+}
+
+// Pending returns a copy of the queued responses, oldest first
+func (o *responseOutbox) Pending() []queuedResponse {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entries := make([]queuedResponse, len(o.entries))
+	copy(entries, o.entries)
+	return entries
+}
+
+// Update replaces the queued response with the same id
+func (o *responseOutbox) Update(entry queuedResponse) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i := range o.entries {
+		if o.entries[i].Id == entry.Id {
+			o.entries[i] = entry
+			return o.save()
+		}
+	}
+	return nil
+}
+
+// Remove drops the queued response with the given id
+func (o *responseOutbox) Remove(id uint64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i := range o.entries {
+		if o.entries[i].Id == id {
+			o.entries = append(o.entries[:i], o.entries[i+1:]...)
+			return o.save()
+		}
+	}
+	return nil
+}
+
+// save writes the outbox to disk and updates the queue gauge. Callers must
+// hold the lock.
+func (o *responseOutbox) save() error {
+	o.queuedGauge.Set(float64(len(o.entries)))
+
+	if o.path == "" {
+		return nil
+	}
+
+	entries := o.entries
+	if entries == nil {
+		entries = []queuedResponse{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode response outbox: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated outbox
+	tmpPath := o.path + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(o.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create response outbox directory: %w", err)
+	}
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write response outbox: %w", err)
+	}
+	if err := os.Rename(tmpPath, o.path); err != nil {
+		return fmt.Errorf("failed to replace response outbox: %w", err)
+	}
+
+	return nil
+}
+
+// resendQueuedResponses periodically retries queued responses until they are
+// delivered, rejected, or their task window closes. Each response backs off
+// exponentially from the resend interval.
+func (o *Operator) resendQueuedResponses(ctx context.Context) {
+	ticker := time.NewTicker(o.responseResendInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			o.resendPendingResponses(ctx)
+		}
+	}
+}
+
+func (o *Operator) resendPendingResponses(ctx context.Context) {
+	pending := o.responseOutbox.Pending()
+	if len(pending) == 0 {
+		return
+	}
+
+	delivered, expired := 0, 0
+	for _, entry := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+
+		taskIndex := entry.Response.TaskResponse.ReferenceTaskIndex
+		now := time.Now()
+		if now.After(entry.Deadline) {
+			expired++
+			o.logger.Warn("Dropping queued task response, task window closed",
+				"taskIndex", taskIndex,
+				"attempts", entry.Attempts,
+				"lastError", entry.LastError,
+			)
+			o.deadLetter(entry, deadLetterExpired)
+			o.removeQueuedResponse(entry.Id)
+			continue
+		}
+		if now.Before(entry.LastAttempt.Add(o.resendBackoff(entry.Attempts))) {
+			continue
+		}
+
+		signedAck, err := o.sendTaskResponse(ctx, entry.Response)
+		switch {
+		case err == nil:
+			delivered++
+			o.logger.Info("Resent queued task response", "taskIndex", taskIndex, "attempts", entry.Attempts+1)
+			o.recordAck(entry.Response, signedAck)
+			o.removeQueuedResponse(entry.Id)
+		case errors.Is(err, ErrResponseRejected):
+			o.logger.Error("Aggregator rejected queued task response", "taskIndex", taskIndex, "error", err)
+			entry.Attempts++
+			entry.LastError = err.Error()
+			o.deadLetter(entry, deadLetterRejected)
+			o.removeQueuedResponse(entry.Id)
+		default:
+			entry.Attempts++
+			entry.LastAttempt = now
+			entry.LastError = err.Error()
+			if err := o.responseOutbox.Update(entry); err != nil {
+				o.logger.Error("Failed to update queued task response", "error", err)
+			}
+		}
+	}
+
+	o.logger.Info("Resent queued task responses",
+		"delivered", delivered,
+		"expired", expired,
+		"queued", o.responseOutbox.Len(),
+	)
+}
+
+// resendBackoff is how long to wait after a response's last attempt before
+// resending it, doubling from the resend interval up to its maximum
+func (o *Operator) resendBackoff(attempts int) time.Duration {
+	backoff := o.responseResendInterval
+	for i := 1; i < attempts && backoff < o.responseResendMaxInterval; i++ {
+		backoff *= 2
+	}
+	if backoff > o.responseResendMaxInterval {
+		backoff = o.responseResendMaxInterval
+	}
+	return backoff
+}
+
+func (o *Operator) removeQueuedResponse(id uint64) {
+	if err := o.responseOutbox.Remove(id); err != nil {
+		o.logger.Error("Failed to remove queued task response", "error", err)
+	}
+}
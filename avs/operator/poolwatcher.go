@@ -0,0 +1,91 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/poolwatcher"
+)
+
+// ErrPoolWatcherDisabled is returned for pool state when no pool manager is
+// configured
+var ErrPoolWatcherDisabled = errors.New("pool watcher is disabled")
+
+// newPoolWatcher returns nil when no pool manager is configured
+func newPoolWatcher(config Config, backend poolwatcher.Backend, reg prometheus.Registerer, logger logging.Logger) (*poolwatcher.Watcher, error) {
+	if config.PoolManagerAddress == "" {
+		return nil, nil
+	}
+
+	var pollInterval time.Duration
+	if config.PoolPollInterval != "" {
+		var err error
+		pollInterval, err = time.ParseDuration(config.PoolPollInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pool poll interval: %w", err)
+		}
+	}
+
+	pools := make([]common.Hash, 0, len(config.WatchedPools))
+	for _, pool := range config.WatchedPools {
+		poolId, err := parseFeedId(pool)
+		if err != nil {
+			return nil, fmt.Errorf("invalid watched pool id %q", pool)
+		}
+		pools = append(pools, poolId)
+	}
+
+	watcher, err := poolwatcher.NewWatcher(poolwatcher.Config{
+		PoolManager:  common.HexToAddress(config.PoolManagerAddress),
+		Pools:        pools,
+		WsUrl:        config.EthWsUrl,
+		PollInterval: pollInterval,
+	}, backend, reg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pool watcher: %w", err)
+	}
+	return watcher, nil
+}
+
+func (o *Operator) watchPools(ctx context.Context) {
+	if err := o.poolWatcher.Run(ctx); err != nil {
+		o.logger.Error("Pool watcher stopped", "error", err)
+	}
+}
+
+// PoolState returns the watched pool's latest price, tick and liquidity
+func (o *Operator) PoolState(poolId common.Hash) (poolwatcher.State, error) {
+	if o.poolWatcher == nil {
+		return poolwatcher.State{}, ErrPoolWatcherDisabled
+	}
+	return o.poolWatcher.State(poolId)
+}
+
+// logPoolGap logs how far the task's pool is priced from its off-pool
+// mid-price, the arbitrage its auction sells. Pools that aren't watched or
+// have no mid-price are skipped.
+func (o *Operator) logPoolGap(ctx context.Context, task *AuctionTask) {
+	state, err := o.PoolState(task.PoolId)
+	if err != nil {
+		return
+	}
+	mid, err := o.MidPrice(ctx, task.PoolId)
+	if err != nil {
+		return
+	}
+	o.logger.Debug("Task pool priced against mid-price",
+		"taskIndex", task.TaskIndex,
+		"poolId", task.PoolId.Hex(),
+		"tick", state.Tick,
+		"poolPrice", state.Price.String(),
+		"midPrice", mid.Price.String(),
+		"divergenceBps", mid.DivergenceBps(state.Price),
+		"stateBlock", state.Block,
+	)
+}
@@ -0,0 +1,213 @@
+package operator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/pricefeed"
+	"github.com/eigenlvr/avs/pkg/venues"
+)
+
+// newDataStreamsVenue builds the Chainlink Data Streams venue from the config
+func newDataStreamsVenue(config Config) (*venues.DataStreams, error) {
+	maxReportAge := venues.DefaultDataStreamsMaxReportAge
+	if config.DataStreamsMaxReportAge != "" {
+		var err error
+		maxReportAge, err = time.ParseDuration(config.DataStreamsMaxReportAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data streams max report age: %w", err)
+		}
+	}
+
+	signers := make([]common.Address, 0, len(config.DataStreamsSigners))
+	for _, signer := range config.DataStreamsSigners {
+		if !common.IsHexAddress(signer) {
+			return nil, fmt.Errorf("invalid data streams signer %q", signer)
+		}
+		signers = append(signers, common.HexToAddress(signer))
+	}
+
+	feeds, err := feedPairs(config.DataStreamsFeeds)
+	if err != nil {
+		return nil, err
+	}
+
+	return venues.NewDataStreams(venues.DataStreamsConfig{
+		ApiUrl:       config.DataStreamsApiUrl,
+		ClientId:     config.DataStreamsClientId,
+		ClientSecret: config.DataStreamsClientSecret,
+		Signers:      signers,
+		MinSigners:   config.DataStreamsMinSigners,
+		MaxReportAge: maxReportAge,
+		Feeds:        feeds,
+	})
+}
+
+// newPythVenue builds the Pyth venue from the config
+func newPythVenue(config Config) (*venues.Pyth, error) {
+	maxPriceAge := venues.DefaultPythMaxPriceAge
+	if config.PythMaxPriceAge != "" {
+		var err error
+		maxPriceAge, err = time.ParseDuration(config.PythMaxPriceAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pyth max price age: %w", err)
+		}
+	}
+
+	feeds, err := feedPairs(config.PythFeeds)
+	if err != nil {
+		return nil, err
+	}
+
+	return venues.NewPyth(venues.PythConfig{
+		HermesUrl:        config.PythHermesUrl,
+		MaxPriceAge:      maxPriceAge,
+		MaxConfidenceBps: config.PythMaxConfidenceBps,
+		Feeds:            feeds,
+	})
+}
+
+// newChainlinkVenue builds the on-chain Chainlink venue from the config
+func newChainlinkVenue(config Config, backend bind.ContractCaller) (*venues.Chainlink, error) {
+	maxPriceAge := venues.DefaultChainlinkMaxPriceAge
+	if config.ChainlinkMaxPriceAge != "" {
+		var err error
+		maxPriceAge, err = time.ParseDuration(config.ChainlinkMaxPriceAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chainlink max price age: %w", err)
+		}
+	}
+
+	feeds := make([]venues.ChainlinkFeed, 0, len(config.ChainlinkFeeds))
+	for _, feed := range config.ChainlinkFeeds {
+		if !common.IsHexAddress(feed.FeedId) {
+			return nil, fmt.Errorf("invalid chainlink aggregator address %q", feed.FeedId)
+		}
+		base, quote, err := feedTokens(feed)
+		if err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, venues.ChainlinkFeed{
+			Aggregator:    common.HexToAddress(feed.FeedId),
+			Base:          base,
+			BaseDecimals:  feed.BaseDecimals,
+			Quote:         quote,
+			QuoteDecimals: feed.QuoteDecimals,
+		})
+	}
+
+	return venues.NewChainlink(venues.ChainlinkConfig{
+		MaxPriceAge: maxPriceAge,
+		Feeds:       feeds,
+	}, backend), nil
+}
+
+// newExchangeVenue builds the CEX websocket venue from the config
+func newExchangeVenue(config Config, logger logging.Logger) (*venues.Exchange, error) {
+	maxTickAge := venues.DefaultExchangeMaxTickAge
+	if config.ExchangeMaxTickAge != "" {
+		var err error
+		maxTickAge, err = time.ParseDuration(config.ExchangeMaxTickAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange max tick age: %w", err)
+		}
+	}
+
+	markets := make([]venues.ExchangeMarket, 0, len(config.ExchangeMarkets))
+	for _, market := range config.ExchangeMarkets {
+		if market.FeedId == "" {
+			return nil, errors.New("exchange market symbol is required")
+		}
+		base, quote, err := feedTokens(market)
+		if err != nil {
+			return nil, err
+		}
+		markets = append(markets, venues.ExchangeMarket{
+			Symbol:        market.FeedId,
+			Base:          base,
+			BaseDecimals:  market.BaseDecimals,
+			Quote:         quote,
+			QuoteDecimals: market.QuoteDecimals,
+		})
+	}
+
+	return venues.NewExchange(venues.ExchangeConfig{
+		Kind:       config.ExchangeKind,
+		Url:        config.ExchangeUrl,
+		MaxTickAge: maxTickAge,
+		Markets:    markets,
+	}, logger)
+}
+
+// newPriceFeed maps the configured pools onto their pairs for per-pool
+// mid-prices
+func newPriceFeed(config Config, sampler *venues.Sampler) (*pricefeed.Feed, error) {
+	maxAge := pricefeed.DefaultMaxAge
+	if config.PriceMaxAge != "" {
+		var err error
+		maxAge, err = time.ParseDuration(config.PriceMaxAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price max age: %w", err)
+		}
+	}
+
+	pools := make([]pricefeed.Pool, 0, len(config.PricePools))
+	for _, pool := range config.PricePools {
+		poolId, err := parseFeedId(pool.PoolId)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price pool id %q", pool.PoolId)
+		}
+		if !common.IsHexAddress(pool.TokenA) || !common.IsHexAddress(pool.TokenB) {
+			return nil, fmt.Errorf("price pool %s: tokens must be addresses", pool.PoolId)
+		}
+		pools = append(pools, pricefeed.Pool{
+			PoolId: poolId,
+			Pair:   venues.NewPair(common.HexToAddress(pool.TokenA), common.HexToAddress(pool.TokenB)),
+		})
+	}
+
+	return pricefeed.NewFeed(pricefeed.Config{Pools: pools, MaxAge: maxAge}, sampler), nil
+}
+
+// feedTokens validates a feed's base and quote tokens
+func feedTokens(feed PriceFeedConfig) (common.Address, common.Address, error) {
+	if !common.IsHexAddress(feed.BaseToken) || !common.IsHexAddress(feed.QuoteToken) {
+		return common.Address{}, common.Address{}, fmt.Errorf("feed %s: base and quote tokens must be addresses", feed.FeedId)
+	}
+	return common.HexToAddress(feed.BaseToken), common.HexToAddress(feed.QuoteToken), nil
+}
+
+// feedPairs validates the configured feeds and maps them onto their tokens
+func feedPairs(feeds []PriceFeedConfig) ([]venues.FeedPair, error) {
+	pairs := make([]venues.FeedPair, 0, len(feeds))
+	for _, feed := range feeds {
+		feedId, err := parseFeedId(feed.FeedId)
+		if err != nil {
+			return nil, err
+		}
+		if !common.IsHexAddress(feed.BaseToken) || !common.IsHexAddress(feed.QuoteToken) {
+			return nil, fmt.Errorf("feed %s: base and quote tokens must be addresses", feed.FeedId)
+		}
+		pairs = append(pairs, venues.FeedPair{
+			FeedId:        feedId,
+			Base:          common.HexToAddress(feed.BaseToken),
+			BaseDecimals:  feed.BaseDecimals,
+			Quote:         common.HexToAddress(feed.QuoteToken),
+			QuoteDecimals: feed.QuoteDecimals,
+		})
+	}
+	return pairs, nil
+}
+
+func parseFeedId(feedId string) (common.Hash, error) {
+	raw := common.FromHex(feedId)
+	if len(raw) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid feed id %q", feedId)
+	}
+	return common.BytesToHash(raw), nil
+}
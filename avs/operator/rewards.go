@@ -0,0 +1,102 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+	"github.com/eigenlvr/avs/pkg/rewards"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ClaimRewards claims all of the operator's claimable rewards once. It is
+// used by the claim-rewards subcommand and doesn't require a running operator.
+func ClaimRewards(ctx context.Context, config Config, logger logging.Logger) (*rewards.ClaimResult, error) {
+	if config.RewardsCoordinatorAddress == "" {
+		return nil, errors.New("rewards_coordinator_address is not configured")
+	}
+
+	ethClient, err := eth.NewClient(config.EthRpcUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create eth client: %w", err)
+	}
+
+	ecdsaSigner, err := newEcdsaSigner(config)
+	if err != nil {
+		return nil, err
+	}
+
+	claimer, err := newRewardsClaimer(config, ethClient, ecdsaSigner, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return claimer.Claim(ctx)
+}
+
+func newRewardsClaimer(config Config, ethClient eth.Client, signer remotesigner.Signer, logger logging.Logger) (*rewards.Claimer, error) {
+	coordinator, err := rewards.NewCoordinator(common.HexToAddress(config.RewardsCoordinatorAddress), ethClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rewards coordinator client: %w", err)
+	}
+
+	var recipient common.Address
+	if config.RewardsClaimRecipient != "" {
+		if !common.IsHexAddress(config.RewardsClaimRecipient) {
+			return nil, fmt.Errorf("invalid rewards claim recipient: %s", config.RewardsClaimRecipient)
+		}
+		recipient = common.HexToAddress(config.RewardsClaimRecipient)
+	}
+
+	var maxGasPrice *big.Int
+	if config.MaxClaimGasPriceGwei > 0 {
+		maxGasPrice = new(big.Int).Mul(new(big.Int).SetUint64(config.MaxClaimGasPriceGwei), big.NewInt(params.GWei))
+	}
+
+	return rewards.NewClaimer(
+		coordinator,
+		rewards.NewApiClient(config.RewardsApiUrl),
+		ethClient,
+		signer,
+		recipient,
+		maxGasPrice,
+		logger,
+	), nil
+}
+
+// autoClaimRewards periodically claims rewards until ctx is cancelled. Claims
+// skipped because of gas prices are retried on the next tick.
+func (o *Operator) autoClaimRewards(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := o.rewardsClaimer.Claim(ctx)
+			switch {
+			case errors.Is(err, rewards.ErrNothingToClaim):
+				o.logger.Debug("No rewards to claim")
+			case errors.Is(err, rewards.ErrGasPriceTooHigh):
+				o.logger.Info("Skipping rewards claim", "reason", err)
+			case err != nil:
+				o.logger.Error("Failed to claim rewards", "error", err)
+			default:
+				o.logger.Info("Claimed rewards",
+					"txHash", result.TxHash.Hex(),
+					"recipient", result.Recipient.Hex(),
+					"tokens", len(result.Tokens),
+					"gasUsed", result.GasUsed,
+				)
+			}
+		}
+	}
+}
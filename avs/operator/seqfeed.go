@@ -0,0 +1,44 @@
+package operator
+
+import (
+	"context"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/eigenlvr/avs/pkg/seqfeed"
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+)
+
+// newSequencerFeed returns nil when no sequencer feed is configured. The feed
+// only speeds up what the task watcher finds anyway, so it needs one.
+func newSequencerFeed(config Config, logger logging.Logger) (*seqfeed.Feed, error) {
+	if config.SequencerFeedUrl == "" || config.ServiceManagerAddress == "" {
+		return nil, nil
+	}
+	return seqfeed.NewFeed(seqfeed.Config{
+		Kind:      config.SequencerFeedKind,
+		Url:       config.SequencerFeedUrl,
+		Addresses: []common.Address{common.HexToAddress(config.ServiceManagerAddress)},
+		Topics:    [][]common.Hash{{servicemanager.NewAuctionTaskCreatedTopic}},
+	}, logger)
+}
+
+// followSequencerFeed queues tasks from preconfirmed events and makes the
+// task watcher poll as soon as the sequencer publishes a block. A task seen
+// early is the same task the watcher delivers later, so it is only queued
+// once. Aggregators only accept responses to tasks created on chain, so a
+// preconfirmed task the sequencer drops costs a wasted response at most.
+func (o *Operator) followSequencerFeed(ctx context.Context) {
+	o.sequencerFeed.Run(ctx, seqfeed.Handler{
+		Block: func(uint64) {
+			if o.taskWatcher != nil {
+				o.taskWatcher.Poke()
+			}
+		},
+		Log: func(log gethtypes.Log) {
+			o.handleNewTaskLog(ctx, log, "sequencer")
+		},
+	})
+}
@@ -0,0 +1,54 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/ecdsakey"
+	"github.com/eigenlvr/avs/pkg/passphrase"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+)
+
+// newEcdsaSigner returns the signer for the operator's ECDSA key: the remote
+// signer EcdsaSigner selects, or the local key file
+func newEcdsaSigner(config Config) (remotesigner.Signer, error) {
+	if config.EcdsaSigner == "" || config.EcdsaSigner == remotesigner.KindLocal {
+		key, err := ecdsakey.Load(config.EcdsaPrivateKeyStorePath, passphrase.Lookup(config.EcdsaPrivateKeyPasswordFile, passphrase.EcdsaEnv))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load operator ecdsa private key: %w", err)
+		}
+		return remotesigner.NewLocal(key), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remotesigner.DefaultTimeout)
+	defer cancel()
+
+	signer, err := remotesigner.New(ctx, remotesigner.Config{
+		Kind:    config.EcdsaSigner,
+		Url:     config.EcdsaSignerUrl,
+		KeyId:   config.EcdsaSignerKeyId,
+		Address: common.HexToAddress(config.EcdsaSignerAddress),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to operator ecdsa signer: %w", err)
+	}
+	return signer, nil
+}
+
+// readBlsKey decrypts the operator's BLS keystore. Without a passphrase
+// configured or a terminal to prompt on, the empty passphrase keys were saved
+// with before passphrases were supported is used.
+func readBlsKey(config Config) (*bls.KeyPair, error) {
+	password, err := passphrase.Lookup(config.BlsPrivateKeyPasswordFile, passphrase.BlsEnv)(config.BlsPrivateKeyStorePath)
+	if errors.Is(err, passphrase.ErrUnavailable) {
+		password, err = "", nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bls.ReadPrivateKeyFromFile(config.BlsPrivateKeyStorePath, password)
+}
@@ -0,0 +1,163 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/servicemanager"
+	"github.com/eigenlvr/avs/pkg/sigchecker"
+)
+
+// Response simulation policies
+const (
+	responseSimulationOff    = "off"
+	responseSimulationWarn   = "warn"
+	responseSimulationRefuse = "refuse"
+
+	// responseSimulationTimeout bounds the eth_call made before signing
+	responseSimulationTimeout = 5 * time.Second
+)
+
+// ErrResponseSimulationFailed is returned when the service manager would
+// reject a response for a reason other than its signatures
+var ErrResponseSimulationFailed = errors.New("task response simulation failed")
+
+// signatureStageReverts are revert reasons from checking the aggregate
+// signature. The simulated aggregate only carries this operator's view of the
+// task, so reaching signature checks means everything before them passed.
+var signatureStageReverts = []string{
+	"BLSSignatureChecker",
+	"threshold percentage",
+}
+
+// responseSimulator dry-runs respondToAuctionTask with a response before it is signed,
+// catching responses the contract would refuse no matter who signs them, such
+// as a task that doesn't match the one recorded on chain or a closed window
+type responseSimulator struct {
+	serviceManager *servicemanager.Reader
+	// aggregator is the caller respondToAuctionTask is restricted to
+	aggregator common.Address
+	refuse     bool
+}
+
+// newResponseSimulator returns nil when simulation is off or can't run
+func newResponseSimulator(config Config, serviceManager *servicemanager.Reader) (*responseSimulator, error) {
+	switch config.ResponseSimulationPolicy {
+	case "", responseSimulationOff:
+		return nil, nil
+	case responseSimulationWarn, responseSimulationRefuse:
+	default:
+		return nil, fmt.Errorf("invalid response simulation policy %q", config.ResponseSimulationPolicy)
+	}
+
+	if serviceManager == nil {
+		return nil, errors.New("response simulation requires service_manager_address")
+	}
+	if !common.IsHexAddress(config.AggregatorAckSigner) {
+		return nil, errors.New("response simulation requires aggregator_ack_signer, the address respondToAuctionTask is called from")
+	}
+
+	return &responseSimulator{
+		serviceManager: serviceManager,
+		aggregator:     common.HexToAddress(config.AggregatorAckSigner),
+		refuse:         config.ResponseSimulationPolicy == responseSimulationRefuse,
+	}, nil
+}
+
+// simulate returns ErrResponseSimulationFailed if the service manager would
+// reject the response before checking its signatures
+func (s *responseSimulator) simulate(ctx context.Context, task *AuctionTask, response *AuctionTaskResponse) error {
+	ctx, cancel := context.WithTimeout(ctx, responseSimulationTimeout)
+	defer cancel()
+
+	quorumNumbers := make([]byte, len(task.QuorumNumbers))
+	for i, quorum := range task.QuorumNumbers {
+		quorumNumbers[i] = byte(quorum)
+	}
+	winningBid := response.WinningBid
+	if winningBid == nil {
+		winningBid = big.NewInt(0)
+	}
+
+	err := s.serviceManager.SimulateRespondToAuctionTask(ctx, s.aggregator,
+		servicemanager.AuctionTask{
+			PoolId:                    task.PoolId,
+			BlockNumber:               new(big.Int).SetUint64(uint64(task.BlockNumber)),
+			TaskCreatedBlock:          new(big.Int).SetUint64(uint64(task.TaskCreatedBlock)),
+			QuorumNumbers:             quorumNumbers,
+			QuorumThresholdPercentage: uint32(task.QuorumThresholdPercentage),
+		},
+		servicemanager.AuctionTaskResponse{
+			ReferenceTaskIndex: response.ReferenceTaskIndex,
+			Winner:             response.Winner,
+			WinningBid:         winningBid,
+			TotalBids:          new(big.Int).SetUint64(uint64(response.TotalBids)),
+		},
+		emptyAggregate(len(quorumNumbers)),
+	)
+	if err == nil {
+		return nil
+	}
+
+	reason, ok := servicemanager.RevertReason(err)
+	if !ok {
+		// Not a revert, e.g. the node is unreachable, which says nothing
+		// about the response
+		return fmt.Errorf("failed to simulate task response: %w", err)
+	}
+	for _, expected := range signatureStageReverts {
+		if strings.Contains(reason, expected) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrResponseSimulationFailed, reason)
+}
+
+// emptyAggregate is a well-formed aggregate with no signatures in it
+func emptyAggregate(quorums int) sigchecker.NonSignerStakesAndSignature {
+	zero := sigchecker.G1Point{X: big.NewInt(0), Y: big.NewInt(0)}
+	quorumApks := make([]sigchecker.G1Point, quorums)
+	for i := range quorumApks {
+		quorumApks[i] = zero
+	}
+	return sigchecker.NonSignerStakesAndSignature{
+		NonSignerQuorumBitmapIndices: []uint32{},
+		NonSignerPubkeys:             []sigchecker.G1Point{},
+		QuorumApks:                   quorumApks,
+		ApkG2: sigchecker.G2Point{
+			X: [2]*big.Int{big.NewInt(0), big.NewInt(0)},
+			Y: [2]*big.Int{big.NewInt(0), big.NewInt(0)},
+		},
+		Sigma:                 zero,
+		QuorumApkIndices:      make([]uint32, quorums),
+		TotalStakeIndices:     make([]uint32, quorums),
+		NonSignerStakeIndices: make([][]uint32, quorums),
+	}
+}
+
+// checkResponse simulates the response and decides whether it may be signed
+func (o *Operator) checkResponse(task *AuctionTask, response *AuctionTaskResponse) error {
+	if o.responseSimulator == nil {
+		return nil
+	}
+
+	err := o.responseSimulator.simulate(context.Background(), task, response)
+	if err == nil {
+		return nil
+	}
+	if o.responseSimulator.refuse && errors.Is(err, ErrResponseSimulationFailed) {
+		return fmt.Errorf("refusing to sign task response: %w", err)
+	}
+	o.logger.Warn("Task response simulation did not pass",
+		"taskIndex", task.TaskIndex,
+		"poolId", task.PoolId.Hex(),
+		"error", err,
+	)
+	return nil
+}
@@ -0,0 +1,50 @@
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/subgraph"
+)
+
+// ErrSubgraphDisabled is returned by PoolStats when no subgraph is configured
+var ErrSubgraphDisabled = errors.New("subgraph is not configured")
+
+func newSubgraphClient(config Config) (*subgraph.Client, error) {
+	cacheTtl := subgraph.DefaultCacheTtl
+	if config.SubgraphCacheTtl != "" {
+		var err error
+		cacheTtl, err = time.ParseDuration(config.SubgraphCacheTtl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subgraph cache ttl: %w", err)
+		}
+	}
+	minRequestInterval := subgraph.DefaultMinRequestInterval
+	if config.SubgraphMinRequestInterval != "" {
+		var err error
+		minRequestInterval, err = time.ParseDuration(config.SubgraphMinRequestInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subgraph min request interval: %w", err)
+		}
+	}
+
+	return subgraph.NewClient(subgraph.Config{
+		Url:                config.SubgraphUrl,
+		ApiKey:             config.SubgraphApiKey,
+		CacheTtl:           cacheTtl,
+		MinRequestInterval: minRequestInterval,
+	})
+}
+
+// PoolStats returns the pool's TVL, volume and recent daily history from the
+// configured subgraph
+func (o *Operator) PoolStats(ctx context.Context, poolId common.Hash) (subgraph.PoolStats, error) {
+	if o.subgraph == nil {
+		return subgraph.PoolStats{}, ErrSubgraphDisabled
+	}
+	return o.subgraph.PoolStats(ctx, poolId)
+}
@@ -0,0 +1,28 @@
+package operator
+
+import (
+	"context"
+	"time"
+
+	"github.com/eigenlvr/avs/pkg/sdnotify"
+)
+
+// watchdogBeatInterval is how often idle loops report to the watchdog. It must
+// stay well below the unit's WatchdogSec.
+const watchdogBeatInterval = 5 * time.Second
+
+// notifyReady tells systemd the operator is up once it is following task
+// events, or straight away when tasks aren't read from the chain
+func (o *Operator) notifyReady(ctx context.Context) {
+	if o.taskWatcher != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.taskWatcher.Live():
+		}
+	}
+
+	if err := sdnotify.Ready(); err != nil {
+		o.logger.Warn("Failed to notify systemd of readiness", "error", err)
+	}
+}
@@ -0,0 +1,182 @@
+package operator
+
+import (
+	"container/heap"
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// defaultPoolWeight is used for pools without a configured weight
+	defaultPoolWeight = 1
+)
+
+// PoolValuer reports the value locked in a pool. It is used to rank tasks for
+// pools that share the same configured weight.
+type PoolValuer interface {
+	PoolValue(ctx context.Context, poolId common.Hash) (*big.Int, error)
+}
+
+// queuedTask is a task waiting to be processed along with its priority
+type queuedTask struct {
+	task      *AuctionTask
+	weight    uint64
+	poolValue *big.Int
+	seq       uint64
+}
+
+// taskHeap orders tasks by pool weight, then pool value, then block number
+// (oldest first), then arrival order
+type taskHeap []*queuedTask
+
+func (h taskHeap) Len() int { return len(h) }
+
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].weight != h[j].weight {
+		return h[i].weight > h[j].weight
+	}
+	if cmp := h[i].poolValue.Cmp(h[j].poolValue); cmp != 0 {
+		return cmp > 0
+	}
+	if h[i].task.BlockNumber != h[j].task.BlockNumber {
+		return h[i].task.BlockNumber < h[j].task.BlockNumber
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h taskHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *taskHeap) Push(x any) { *h = append(*h, x.(*queuedTask)) }
+
+func (h *taskHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// taskQueue is a priority queue of pending auction tasks so that high-value
+// pools are responded to first when many tasks arrive at once
+type taskQueue struct {
+	mu     sync.Mutex
+	tasks  taskHeap
+	seq    uint64
+	signal chan struct{}
+}
+
+func newTaskQueue() *taskQueue {
+	return &taskQueue{
+		signal: make(chan struct{}, 1),
+	}
+}
+
+func (q *taskQueue) push(task *AuctionTask, weight uint64, poolValue *big.Int) {
+	q.mu.Lock()
+	q.seq++
+	heap.Push(&q.tasks, &queuedTask{
+		task:      task,
+		weight:    weight,
+		poolValue: poolValue,
+		seq:       q.seq,
+	})
+	q.mu.Unlock()
+
+	// Wake up the worker without blocking if it's already been signalled
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (q *taskQueue) pop() (*AuctionTask, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.tasks) == 0 {
+		return nil, false
+	}
+	return heap.Pop(&q.tasks).(*queuedTask).task, true
+}
+
+func (q *taskQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.tasks)
+}
+
+// SetPoolValuer sets the source of pool values used to prioritize tasks
+func (o *Operator) SetPoolValuer(poolValuer PoolValuer) {
+	o.poolValuer = poolValuer
+}
+
+// enqueueTask adds a task to the queue, ranked by its pool's configured weight
+// and, when a pool valuer is set, by the value locked in the pool
+func (o *Operator) enqueueTask(ctx context.Context, task *AuctionTask) {
+	weight := uint64(defaultPoolWeight)
+	if poolWeight, ok := o.poolWeights[task.PoolId]; ok {
+		weight = poolWeight
+	}
+
+	poolValue := big.NewInt(0)
+	if o.poolValuer != nil {
+		value, err := o.poolValuer.PoolValue(ctx, task.PoolId)
+		if err != nil {
+			o.logger.Warn("Failed to get pool value, using zero", "poolId", task.PoolId.Hex(), "error", err)
+		} else if value != nil {
+			poolValue = value
+		}
+	}
+
+	o.taskQueue.push(task, weight, poolValue)
+
+	o.logger.Debug("Task queued",
+		"poolId", task.PoolId.Hex(),
+		"weight", weight,
+		"poolValue", poolValue.String(),
+		"queueLength", o.taskQueue.len(),
+	)
+}
+
+// processTaskQueue processes queued tasks in priority order until ctx is done
+func (o *Operator) processTaskQueue(ctx context.Context) {
+	o.logger.Info("Starting task queue processor")
+
+	heartbeat := time.NewTicker(watchdogBeatInterval)
+	defer heartbeat.Stop()
+	o.watchdog.Beat("taskQueue")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			o.watchdog.Beat("taskQueue")
+		case <-o.taskQueue.signal:
+			for {
+				task, ok := o.taskQueue.pop()
+				if !ok {
+					break
+				}
+				if err := o.processTask(task); err != nil {
+					o.logger.Error("Failed to process auction task",
+						"poolId", task.PoolId.Hex(),
+						"blockNumber", task.BlockNumber,
+						"error", err,
+					)
+				}
+				o.watchdog.Beat("taskQueue")
+
+				if ctx.Err() != nil {
+					return
+				}
+			}
+		}
+	}
+}
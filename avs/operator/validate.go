@@ -0,0 +1,163 @@
+package operator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eigenlvr/avs/pkg/compression"
+	"github.com/eigenlvr/avs/pkg/configfile"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+	"github.com/eigenlvr/avs/pkg/seqfeed"
+	"github.com/eigenlvr/avs/pkg/venues"
+)
+
+// Validate checks the config before anything is started, returning a
+// *configfile.ValidationError that lists every problem found. Addresses a
+// network preset fills in must be applied first.
+func (config Config) Validate() error {
+	var problems configfile.Problems
+
+	switch config.EcdsaSigner {
+	case "", remotesigner.KindLocal:
+		if problems.Required("ecdsa_private_key_store_path", config.EcdsaPrivateKeyStorePath) {
+			problems.File("ecdsa_private_key_store_path", config.EcdsaPrivateKeyStorePath)
+		}
+	case remotesigner.KindWeb3Signer:
+		if problems.Required("ecdsa_signer_url", config.EcdsaSignerUrl) {
+			problems.URL("ecdsa_signer_url", config.EcdsaSignerUrl, "http", "https")
+		}
+		problems.RequiredAddress("ecdsa_signer_address", config.EcdsaSignerAddress)
+		if config.AggregatorTransport == AggregatorTransportWebsocket {
+			problems.Addf("ecdsa_signer %q can't sign the websocket transport's challenges", config.EcdsaSigner)
+		}
+	case remotesigner.KindAwsKms, remotesigner.KindGcpKms:
+		problems.Required("ecdsa_signer_key_id", config.EcdsaSignerKeyId)
+		problems.Address("ecdsa_signer_address", config.EcdsaSignerAddress)
+	default:
+		problems.OneOf("ecdsa_signer", config.EcdsaSigner, remotesigner.KindLocal, remotesigner.KindWeb3Signer, remotesigner.KindAwsKms, remotesigner.KindGcpKms)
+	}
+	if problems.Required("bls_private_key_store_path", config.BlsPrivateKeyStorePath) {
+		problems.File("bls_private_key_store_path", config.BlsPrivateKeyStorePath)
+	}
+	problems.File("ecdsa_private_key_password_file", config.EcdsaPrivateKeyPasswordFile)
+	problems.File("bls_private_key_password_file", config.BlsPrivateKeyPasswordFile)
+
+	if problems.Required("eth_rpc_url", config.EthRpcUrl) {
+		problems.URL("eth_rpc_url", config.EthRpcUrl, "http", "https", "ws", "wss")
+	}
+	problems.URL("eth_ws_url", config.EthWsUrl, "ws", "wss")
+	problems.URL("pushgateway_url", config.PushgatewayUrl, "http", "https")
+	problems.URL("subgraph_url", config.SubgraphUrl, "http", "https")
+	problems.URL("rewards_api_url", config.RewardsApiUrl, "http", "https")
+	problems.URL("failover_peer_url", config.FailoverPeerUrl, "http", "https")
+	problems.URL("data_streams_api_url", config.DataStreamsApiUrl, "http", "https")
+	problems.URL("pyth_hermes_url", config.PythHermesUrl, "http", "https")
+	problems.URL("sequencer_feed_url", config.SequencerFeedUrl, "ws", "wss")
+	problems.URL("exchange_url", config.ExchangeUrl, "ws", "wss")
+
+	switch config.AggregatorTransport {
+	case "", AggregatorTransportHttp, AggregatorTransportWebsocket:
+		// The HTTP client also takes a full URL
+		if !problems.Required("aggregator_server_ip_port_address", config.AggregatorServerIpPortAddr) {
+			break
+		}
+		if strings.Contains(config.AggregatorServerIpPortAddr, "://") {
+			problems.URL("aggregator_server_ip_port_address", config.AggregatorServerIpPortAddr, "http", "https")
+		} else {
+			problems.HostPort("aggregator_server_ip_port_address", config.AggregatorServerIpPortAddr)
+		}
+	case AggregatorTransportGrpc:
+		if problems.Required("aggregator_grpc_address", config.AggregatorGrpcAddr) {
+			problems.HostPort("aggregator_grpc_address", config.AggregatorGrpcAddr)
+		}
+	default:
+		problems.OneOf("aggregator_transport", config.AggregatorTransport, AggregatorTransportHttp, AggregatorTransportWebsocket, AggregatorTransportGrpc)
+	}
+	if config.EnableMetrics && problems.Required("eigen_metrics_ip_port_address", config.EigenMetricsIpPortAddress) {
+		problems.HostPort("eigen_metrics_ip_port_address", config.EigenMetricsIpPortAddress)
+	}
+	if config.EnableNodeApi && problems.Required("node_api_ip_port_address", config.NodeApiIpPortAddress) {
+		problems.HostPort("node_api_ip_port_address", config.NodeApiIpPortAddress)
+	}
+	problems.HostPort("debug_ip_port_address", config.DebugIpPortAddress)
+	problems.HostPort("failover_listen_address", config.FailoverListenAddr)
+
+	problems.RequiredAddress("registry_coordinator_address", config.RegistryCoordinatorAddress)
+	problems.RequiredAddress("operator_state_retriever_address", config.OperatorStateRetrieverAddress)
+	problems.Address("service_manager_address", config.ServiceManagerAddress)
+	problems.Address("rewards_coordinator_address", config.RewardsCoordinatorAddress)
+	problems.Address("rewards_claim_recipient", config.RewardsClaimRecipient)
+	problems.Address("delegation_manager_address", config.DelegationManagerAddress)
+	problems.Address("uniswap_v3_factory_address", config.UniswapV3FactoryAddress)
+	problems.Address("aggregator_ack_signer", config.AggregatorAckSigner)
+	problems.Address("auction_escrow_address", config.AuctionEscrowAddress)
+	problems.Address("pool_manager_address", config.PoolManagerAddress)
+	problems.File("pools_file", config.PoolsFile)
+	if _, err := newAuctionRules(config); err != nil {
+		problems.Addf("%v", err)
+	}
+	for i, strategy := range config.DelegationStrategies {
+		problems.Address(fmt.Sprintf("delegation_strategies[%d]", i), strategy)
+	}
+	for i, signer := range config.DataStreamsSigners {
+		problems.Address(fmt.Sprintf("data_streams_signers[%d]", i), signer)
+	}
+	for i, pool := range config.CurvePools {
+		problems.RequiredAddress(fmt.Sprintf("curve_pools[%d].address", i), pool.Address)
+	}
+	for i, feed := range config.ChainlinkFeeds {
+		problems.RequiredAddress(fmt.Sprintf("chainlink_feeds[%d].feed_id", i), feed.FeedId)
+	}
+	for i, market := range config.ExchangeMarkets {
+		problems.Required(fmt.Sprintf("exchange_markets[%d].feed_id", i), market.FeedId)
+	}
+	if len(config.ExchangeMarkets) > 0 && config.ExchangeKind == "" {
+		problems.Addf("exchange_markets needs exchange_kind")
+	}
+	if len(config.WatchedPools) > 0 && config.PoolManagerAddress == "" {
+		problems.Addf("watched_pools needs pool_manager_address")
+	}
+	if config.PoolManagerAddress != "" && len(config.WatchedPools) == 0 {
+		problems.Addf("pool_manager_address needs watched_pools")
+	}
+	for i, pool := range config.PricePools {
+		problems.RequiredAddress(fmt.Sprintf("price_pools[%d].token_a", i), pool.TokenA)
+		problems.RequiredAddress(fmt.Sprintf("price_pools[%d].token_b", i), pool.TokenB)
+	}
+
+	for _, duration := range []struct{ key, value string }{
+		{"rpc_cache_head_ttl", config.RpcCacheHeadTtl},
+		{"subgraph_cache_ttl", config.SubgraphCacheTtl},
+		{"subgraph_min_request_interval", config.SubgraphMinRequestInterval},
+		{"rewards_poll_interval", config.RewardsPollInterval},
+		{"auto_claim_rewards_interval", config.AutoClaimRewardsInterval},
+		{"delegation_poll_interval", config.DelegationPollInterval},
+		{"response_resend_window", config.ResponseResendWindow},
+		{"response_resend_interval", config.ResponseResendInterval},
+		{"response_resend_max_interval", config.ResponseResendMaxInterval},
+		{"aggregator_request_timeout", config.AggregatorRequestTimeout},
+		{"failover_heartbeat_interval", config.FailoverHeartbeatInterval},
+		{"failover_timeout", config.FailoverTimeout},
+		{"data_streams_max_report_age", config.DataStreamsMaxReportAge},
+		{"pyth_max_price_age", config.PythMaxPriceAge},
+		{"chainlink_max_price_age", config.ChainlinkMaxPriceAge},
+		{"exchange_max_tick_age", config.ExchangeMaxTickAge},
+		{"price_max_age", config.PriceMaxAge},
+		{"pool_poll_interval", config.PoolPollInterval},
+		{"clock_drift_threshold", config.ClockDriftThreshold},
+		{"clock_drift_max_block_lag", config.ClockDriftMaxBlockLag},
+		{"clock_drift_check_interval", config.ClockDriftCheckInterval},
+		{"task_poll_interval", config.TaskPollInterval},
+	} {
+		problems.Duration(duration.key, duration.value)
+	}
+
+	problems.OneOf("failover_role", config.FailoverRole, FailoverRolePrimary, FailoverRoleStandby)
+	problems.OneOf("clock_drift_policy", config.ClockDriftPolicy, "warn", "refuse")
+	problems.OneOf("exchange_kind", config.ExchangeKind, venues.ExchangeBinance, venues.ExchangeCoinbase)
+	problems.OneOf("sequencer_feed_kind", config.SequencerFeedKind, seqfeed.KindArbitrum, seqfeed.KindFlashblocks)
+	problems.OneOf("request_compression", config.RequestCompression, compression.Auto, compression.None, compression.Gzip, compression.Zstd)
+	problems.OneOf("response_simulation_policy", config.ResponseSimulationPolicy, responseSimulationOff, responseSimulationWarn, responseSimulationRefuse)
+
+	return problems.Err()
+}
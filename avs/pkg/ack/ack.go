@@ -0,0 +1,107 @@
+// Package ack defines the acknowledgment the aggregator signs when it accepts
+// an operator's task response. An operator holding a signed ack can prove the
+// aggregator received its response, and when, even if the response is later
+// missing from the aggregate.
+package ack
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+)
+
+// domain separates ack signatures from any other signature made with the
+// aggregator's key
+const domain = "eigenlvr task response ack"
+
+var (
+	// ErrWrongSigner is returned when an ack was signed by an unexpected key
+	ErrWrongSigner = errors.New("ack signed by unexpected address")
+	// ErrMismatch is returned when an ack doesn't cover the response it was returned for
+	ErrMismatch = errors.New("ack does not match response")
+)
+
+// Ack is what the aggregator attests to
+type Ack struct {
+	TaskIndex      uint32      `json:"taskIndex"`
+	OperatorId     common.Hash `json:"operatorId"`
+	ResponseDigest common.Hash `json:"responseDigest"`
+	// Timestamp is when the response was accepted, in unix seconds
+	Timestamp uint64 `json:"timestamp"`
+}
+
+// SignedAck is an Ack with the aggregator's signature over its digest
+type SignedAck struct {
+	Ack
+	Signer    common.Address `json:"signer"`
+	Signature hexutil.Bytes  `json:"signature"`
+}
+
+// New returns an ack for a response accepted now
+func New(taskIndex uint32, operatorId common.Hash, responseDigest common.Hash) Ack {
+	return Ack{
+		TaskIndex:      taskIndex,
+		OperatorId:     operatorId,
+		ResponseDigest: responseDigest,
+		Timestamp:      uint64(time.Now().Unix()),
+	}
+}
+
+// Digest is the hash the aggregator signs
+func (a Ack) Digest() common.Hash {
+	buf := make([]byte, 0, len(domain)+4+2*common.HashLength+8)
+	buf = append(buf, domain...)
+	buf = binary.BigEndian.AppendUint32(buf, a.TaskIndex)
+	buf = append(buf, a.OperatorId[:]...)
+	buf = append(buf, a.ResponseDigest[:]...)
+	buf = binary.BigEndian.AppendUint64(buf, a.Timestamp)
+	return crypto.Keccak256Hash(buf)
+}
+
+// Sign signs the ack with the aggregator's key
+func Sign(ctx context.Context, a Ack, signer remotesigner.Signer) (SignedAck, error) {
+	signature, err := signer.SignHash(ctx, a.Digest())
+	if err != nil {
+		return SignedAck{}, fmt.Errorf("failed to sign ack: %w", err)
+	}
+	return SignedAck{
+		Ack:       a,
+		Signer:    signer.Address(),
+		Signature: signature,
+	}, nil
+}
+
+// Verify checks that the signature recovers to the claimed signer, and to
+// expectedSigner when that is set
+func (s SignedAck) Verify(expectedSigner common.Address) error {
+	digest := s.Digest()
+	publicKey, err := crypto.SigToPub(digest[:], s.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid ack signature: %w", err)
+	}
+
+	signer := crypto.PubkeyToAddress(*publicKey)
+	if signer != s.Signer {
+		return fmt.Errorf("%w: recovered %s, claimed %s", ErrWrongSigner, signer.Hex(), s.Signer.Hex())
+	}
+	if expectedSigner != (common.Address{}) && signer != expectedSigner {
+		return fmt.Errorf("%w: %s, expected %s", ErrWrongSigner, signer.Hex(), expectedSigner.Hex())
+	}
+	return nil
+}
+
+// Matches checks that the ack covers the given response
+func (s SignedAck) Matches(taskIndex uint32, operatorId common.Hash, responseDigest common.Hash) error {
+	if s.TaskIndex != taskIndex || s.OperatorId != operatorId || s.ResponseDigest != responseDigest {
+		return fmt.Errorf("%w: task %d", ErrMismatch, taskIndex)
+	}
+	return nil
+}
@@ -0,0 +1,1662 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        v4.25.3
+// source: eigenlvr/v1/aggregator.proto
+
+package aggregatorpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TaskResponse mirrors the service manager's AuctionTaskResponse
+type TaskResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReferenceTaskIndex uint32 `protobuf:"varint,1,opt,name=reference_task_index,json=referenceTaskIndex,proto3" json:"reference_task_index,omitempty"`
+	Winner             []byte `protobuf:"bytes,2,opt,name=winner,proto3" json:"winner,omitempty"`
+	WinningBid         []byte `protobuf:"bytes,3,opt,name=winning_bid,json=winningBid,proto3" json:"winning_bid,omitempty"`
+	TotalBids          uint32 `protobuf:"varint,4,opt,name=total_bids,json=totalBids,proto3" json:"total_bids,omitempty"`
+}
+
+func (x *TaskResponse) Reset() {
+	*x = TaskResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskResponse) ProtoMessage() {}
+
+func (x *TaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskResponse.ProtoReflect.Descriptor instead.
+func (*TaskResponse) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TaskResponse) GetReferenceTaskIndex() uint32 {
+	if x != nil {
+		return x.ReferenceTaskIndex
+	}
+	return 0
+}
+
+func (x *TaskResponse) GetWinner() []byte {
+	if x != nil {
+		return x.Winner
+	}
+	return nil
+}
+
+func (x *TaskResponse) GetWinningBid() []byte {
+	if x != nil {
+		return x.WinningBid
+	}
+	return nil
+}
+
+func (x *TaskResponse) GetTotalBids() uint32 {
+	if x != nil {
+		return x.TotalBids
+	}
+	return 0
+}
+
+type SubmitTaskResponseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TaskResponse *TaskResponse `protobuf:"bytes,1,opt,name=task_response,json=taskResponse,proto3" json:"task_response,omitempty"`
+	BlsSignature []byte        `protobuf:"bytes,2,opt,name=bls_signature,json=blsSignature,proto3" json:"bls_signature,omitempty"`
+	OperatorId   []byte        `protobuf:"bytes,3,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
+	// committee_proof is the operator's VRF ticket, required when committees
+	// are selected by VRF
+	CommitteeProof []byte `protobuf:"bytes,4,opt,name=committee_proof,json=committeeProof,proto3" json:"committee_proof,omitempty"`
+}
+
+func (x *SubmitTaskResponseRequest) Reset() {
+	*x = SubmitTaskResponseRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitTaskResponseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitTaskResponseRequest) ProtoMessage() {}
+
+func (x *SubmitTaskResponseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitTaskResponseRequest.ProtoReflect.Descriptor instead.
+func (*SubmitTaskResponseRequest) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitTaskResponseRequest) GetTaskResponse() *TaskResponse {
+	if x != nil {
+		return x.TaskResponse
+	}
+	return nil
+}
+
+func (x *SubmitTaskResponseRequest) GetBlsSignature() []byte {
+	if x != nil {
+		return x.BlsSignature
+	}
+	return nil
+}
+
+func (x *SubmitTaskResponseRequest) GetOperatorId() []byte {
+	if x != nil {
+		return x.OperatorId
+	}
+	return nil
+}
+
+func (x *SubmitTaskResponseRequest) GetCommitteeProof() []byte {
+	if x != nil {
+		return x.CommitteeProof
+	}
+	return nil
+}
+
+type SubmitTaskResponseReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ResponseDigest []byte `protobuf:"bytes,1,opt,name=response_digest,json=responseDigest,proto3" json:"response_digest,omitempty"`
+	Ack            *Ack   `protobuf:"bytes,2,opt,name=ack,proto3" json:"ack,omitempty"`
+}
+
+func (x *SubmitTaskResponseReply) Reset() {
+	*x = SubmitTaskResponseReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitTaskResponseReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitTaskResponseReply) ProtoMessage() {}
+
+func (x *SubmitTaskResponseReply) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitTaskResponseReply.ProtoReflect.Descriptor instead.
+func (*SubmitTaskResponseReply) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubmitTaskResponseReply) GetResponseDigest() []byte {
+	if x != nil {
+		return x.ResponseDigest
+	}
+	return nil
+}
+
+func (x *SubmitTaskResponseReply) GetAck() *Ack {
+	if x != nil {
+		return x.Ack
+	}
+	return nil
+}
+
+// Ack is the aggregator's signed acknowledgment of an accepted response
+type Ack struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TaskIndex      uint32 `protobuf:"varint,1,opt,name=task_index,json=taskIndex,proto3" json:"task_index,omitempty"`
+	OperatorId     []byte `protobuf:"bytes,2,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
+	ResponseDigest []byte `protobuf:"bytes,3,opt,name=response_digest,json=responseDigest,proto3" json:"response_digest,omitempty"`
+	// timestamp is when the response was accepted, in unix seconds
+	Timestamp uint64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Signer    []byte `protobuf:"bytes,5,opt,name=signer,proto3" json:"signer,omitempty"`
+	Signature []byte `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *Ack) Reset() {
+	*x = Ack{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Ack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ack) ProtoMessage() {}
+
+func (x *Ack) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ack.ProtoReflect.Descriptor instead.
+func (*Ack) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Ack) GetTaskIndex() uint32 {
+	if x != nil {
+		return x.TaskIndex
+	}
+	return 0
+}
+
+func (x *Ack) GetOperatorId() []byte {
+	if x != nil {
+		return x.OperatorId
+	}
+	return nil
+}
+
+func (x *Ack) GetResponseDigest() []byte {
+	if x != nil {
+		return x.ResponseDigest
+	}
+	return nil
+}
+
+func (x *Ack) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Ack) GetSigner() []byte {
+	if x != nil {
+		return x.Signer
+	}
+	return nil
+}
+
+func (x *Ack) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type GetTaskStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TaskIndex uint32 `protobuf:"varint,1,opt,name=task_index,json=taskIndex,proto3" json:"task_index,omitempty"`
+}
+
+func (x *GetTaskStatusRequest) Reset() {
+	*x = GetTaskStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTaskStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaskStatusRequest) ProtoMessage() {}
+
+func (x *GetTaskStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaskStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetTaskStatusRequest) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetTaskStatusRequest) GetTaskIndex() uint32 {
+	if x != nil {
+		return x.TaskIndex
+	}
+	return 0
+}
+
+type TaskStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TaskIndex uint32 `protobuf:"varint,1,opt,name=task_index,json=taskIndex,proto3" json:"task_index,omitempty"`
+	// status is "processing" or "completed"
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	PoolId []byte `protobuf:"bytes,3,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	// created_at is when the aggregator opened the task, in unix seconds
+	CreatedAt             int64         `protobuf:"varint,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	TaskCreatedBlock      uint32        `protobuf:"varint,5,opt,name=task_created_block,json=taskCreatedBlock,proto3" json:"task_created_block,omitempty"`
+	Responses             uint32        `protobuf:"varint,6,opt,name=responses,proto3" json:"responses,omitempty"`
+	AggregatedResponse    *TaskResponse `protobuf:"bytes,7,opt,name=aggregated_response,json=aggregatedResponse,proto3" json:"aggregated_response,omitempty"`
+	AggregatedDigest      []byte        `protobuf:"bytes,8,opt,name=aggregated_digest,json=aggregatedDigest,proto3" json:"aggregated_digest,omitempty"`
+	Signers               uint32        `protobuf:"varint,9,opt,name=signers,proto3" json:"signers,omitempty"`
+	SubmissionTxHash      []byte        `protobuf:"bytes,10,opt,name=submission_tx_hash,json=submissionTxHash,proto3" json:"submission_tx_hash,omitempty"`
+	SubmissionBlockNumber uint64        `protobuf:"varint,11,opt,name=submission_block_number,json=submissionBlockNumber,proto3" json:"submission_block_number,omitempty"`
+	// archived is set when the status was read from the task store
+	Archived bool `protobuf:"varint,12,opt,name=archived,proto3" json:"archived,omitempty"`
+}
+
+func (x *TaskStatus) Reset() {
+	*x = TaskStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaskStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskStatus) ProtoMessage() {}
+
+func (x *TaskStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskStatus.ProtoReflect.Descriptor instead.
+func (*TaskStatus) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TaskStatus) GetTaskIndex() uint32 {
+	if x != nil {
+		return x.TaskIndex
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TaskStatus) GetPoolId() []byte {
+	if x != nil {
+		return x.PoolId
+	}
+	return nil
+}
+
+func (x *TaskStatus) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetTaskCreatedBlock() uint32 {
+	if x != nil {
+		return x.TaskCreatedBlock
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetResponses() uint32 {
+	if x != nil {
+		return x.Responses
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetAggregatedResponse() *TaskResponse {
+	if x != nil {
+		return x.AggregatedResponse
+	}
+	return nil
+}
+
+func (x *TaskStatus) GetAggregatedDigest() []byte {
+	if x != nil {
+		return x.AggregatedDigest
+	}
+	return nil
+}
+
+func (x *TaskStatus) GetSigners() uint32 {
+	if x != nil {
+		return x.Signers
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetSubmissionTxHash() []byte {
+	if x != nil {
+		return x.SubmissionTxHash
+	}
+	return nil
+}
+
+func (x *TaskStatus) GetSubmissionBlockNumber() uint64 {
+	if x != nil {
+		return x.SubmissionBlockNumber
+	}
+	return 0
+}
+
+func (x *TaskStatus) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+type StreamTasksRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *StreamTasksRequest) Reset() {
+	*x = StreamTasksRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTasksRequest) ProtoMessage() {}
+
+func (x *StreamTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTasksRequest.ProtoReflect.Descriptor instead.
+func (*StreamTasksRequest) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{6}
+}
+
+// Task mirrors the service manager's AuctionTask
+type Task struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TaskIndex                 uint32 `protobuf:"varint,1,opt,name=task_index,json=taskIndex,proto3" json:"task_index,omitempty"`
+	PoolId                    []byte `protobuf:"bytes,2,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	BlockNumber               uint32 `protobuf:"varint,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	TaskCreatedBlock          uint32 `protobuf:"varint,4,opt,name=task_created_block,json=taskCreatedBlock,proto3" json:"task_created_block,omitempty"`
+	QuorumNumbers             []byte `protobuf:"bytes,5,opt,name=quorum_numbers,json=quorumNumbers,proto3" json:"quorum_numbers,omitempty"`
+	QuorumThresholdPercentage uint32 `protobuf:"varint,6,opt,name=quorum_threshold_percentage,json=quorumThresholdPercentage,proto3" json:"quorum_threshold_percentage,omitempty"`
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Task) GetTaskIndex() uint32 {
+	if x != nil {
+		return x.TaskIndex
+	}
+	return 0
+}
+
+func (x *Task) GetPoolId() []byte {
+	if x != nil {
+		return x.PoolId
+	}
+	return nil
+}
+
+func (x *Task) GetBlockNumber() uint32 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *Task) GetTaskCreatedBlock() uint32 {
+	if x != nil {
+		return x.TaskCreatedBlock
+	}
+	return 0
+}
+
+func (x *Task) GetQuorumNumbers() []byte {
+	if x != nil {
+		return x.QuorumNumbers
+	}
+	return nil
+}
+
+func (x *Task) GetQuorumThresholdPercentage() uint32 {
+	if x != nil {
+		return x.QuorumThresholdPercentage
+	}
+	return 0
+}
+
+// Bid is a searcher's offer in a pool's auction for a block
+type Bid struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PoolId      []byte `protobuf:"bytes,1,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	BlockNumber uint32 `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Bidder      []byte `protobuf:"bytes,3,opt,name=bidder,proto3" json:"bidder,omitempty"`
+	Amount      []byte `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	// signature is the bidder's signature over the bid digest, 65 bytes R, S, V
+	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *Bid) Reset() {
+	*x = Bid{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Bid) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bid) ProtoMessage() {}
+
+func (x *Bid) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bid.ProtoReflect.Descriptor instead.
+func (*Bid) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Bid) GetPoolId() []byte {
+	if x != nil {
+		return x.PoolId
+	}
+	return nil
+}
+
+func (x *Bid) GetBlockNumber() uint32 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *Bid) GetBidder() []byte {
+	if x != nil {
+		return x.Bidder
+	}
+	return nil
+}
+
+func (x *Bid) GetAmount() []byte {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+func (x *Bid) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+// SealedBid commits a bidder to a bid without disclosing it
+type SealedBid struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PoolId      []byte `protobuf:"bytes,1,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	BlockNumber uint32 `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Bidder      []byte `protobuf:"bytes,3,opt,name=bidder,proto3" json:"bidder,omitempty"`
+	// commitment hashes the bid digest with a salt the bidder keeps until the
+	// reveal
+	Commitment []byte `protobuf:"bytes,4,opt,name=commitment,proto3" json:"commitment,omitempty"`
+	// signature is the bidder's signature over the sealed bid digest
+	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *SealedBid) Reset() {
+	*x = SealedBid{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SealedBid) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SealedBid) ProtoMessage() {}
+
+func (x *SealedBid) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SealedBid.ProtoReflect.Descriptor instead.
+func (*SealedBid) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SealedBid) GetPoolId() []byte {
+	if x != nil {
+		return x.PoolId
+	}
+	return nil
+}
+
+func (x *SealedBid) GetBlockNumber() uint32 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *SealedBid) GetBidder() []byte {
+	if x != nil {
+		return x.Bidder
+	}
+	return nil
+}
+
+func (x *SealedBid) GetCommitment() []byte {
+	if x != nil {
+		return x.Commitment
+	}
+	return nil
+}
+
+func (x *SealedBid) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type CommitBidRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SealedBid *SealedBid `protobuf:"bytes,1,opt,name=sealed_bid,json=sealedBid,proto3" json:"sealed_bid,omitempty"`
+}
+
+func (x *CommitBidRequest) Reset() {
+	*x = CommitBidRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommitBidRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitBidRequest) ProtoMessage() {}
+
+func (x *CommitBidRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitBidRequest.ProtoReflect.Descriptor instead.
+func (*CommitBidRequest) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CommitBidRequest) GetSealedBid() *SealedBid {
+	if x != nil {
+		return x.SealedBid
+	}
+	return nil
+}
+
+type CommitBidReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SealedBidDigest []byte `protobuf:"bytes,1,opt,name=sealed_bid_digest,json=sealedBidDigest,proto3" json:"sealed_bid_digest,omitempty"`
+}
+
+func (x *CommitBidReply) Reset() {
+	*x = CommitBidReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommitBidReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitBidReply) ProtoMessage() {}
+
+func (x *CommitBidReply) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitBidReply.ProtoReflect.Descriptor instead.
+func (*CommitBidReply) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CommitBidReply) GetSealedBidDigest() []byte {
+	if x != nil {
+		return x.SealedBidDigest
+	}
+	return nil
+}
+
+type RevealBidRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bid  *Bid   `protobuf:"bytes,1,opt,name=bid,proto3" json:"bid,omitempty"`
+	Salt []byte `protobuf:"bytes,2,opt,name=salt,proto3" json:"salt,omitempty"`
+}
+
+func (x *RevealBidRequest) Reset() {
+	*x = RevealBidRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevealBidRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevealBidRequest) ProtoMessage() {}
+
+func (x *RevealBidRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevealBidRequest.ProtoReflect.Descriptor instead.
+func (*RevealBidRequest) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RevealBidRequest) GetBid() *Bid {
+	if x != nil {
+		return x.Bid
+	}
+	return nil
+}
+
+func (x *RevealBidRequest) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+type RevealBidReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BidDigest []byte `protobuf:"bytes,1,opt,name=bid_digest,json=bidDigest,proto3" json:"bid_digest,omitempty"`
+}
+
+func (x *RevealBidReply) Reset() {
+	*x = RevealBidReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevealBidReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevealBidReply) ProtoMessage() {}
+
+func (x *RevealBidReply) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevealBidReply.ProtoReflect.Descriptor instead.
+func (*RevealBidReply) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RevealBidReply) GetBidDigest() []byte {
+	if x != nil {
+		return x.BidDigest
+	}
+	return nil
+}
+
+type GetBidsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PoolId      []byte `protobuf:"bytes,1,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	BlockNumber uint32 `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+}
+
+func (x *GetBidsRequest) Reset() {
+	*x = GetBidsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetBidsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBidsRequest) ProtoMessage() {}
+
+func (x *GetBidsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBidsRequest.ProtoReflect.Descriptor instead.
+func (*GetBidsRequest) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetBidsRequest) GetPoolId() []byte {
+	if x != nil {
+		return x.PoolId
+	}
+	return nil
+}
+
+func (x *GetBidsRequest) GetBlockNumber() uint32 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+// RevealedBid is a commitment with the bid and salt that open it
+type RevealedBid struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SealedBid *SealedBid `protobuf:"bytes,1,opt,name=sealed_bid,json=sealedBid,proto3" json:"sealed_bid,omitempty"`
+	Bid       *Bid       `protobuf:"bytes,2,opt,name=bid,proto3" json:"bid,omitempty"`
+	Salt      []byte     `protobuf:"bytes,3,opt,name=salt,proto3" json:"salt,omitempty"`
+}
+
+func (x *RevealedBid) Reset() {
+	*x = RevealedBid{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevealedBid) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevealedBid) ProtoMessage() {}
+
+func (x *RevealedBid) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevealedBid.ProtoReflect.Descriptor instead.
+func (*RevealedBid) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RevealedBid) GetSealedBid() *SealedBid {
+	if x != nil {
+		return x.SealedBid
+	}
+	return nil
+}
+
+func (x *RevealedBid) GetBid() *Bid {
+	if x != nil {
+		return x.Bid
+	}
+	return nil
+}
+
+func (x *RevealedBid) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+type BidBook struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PoolId      []byte `protobuf:"bytes,1,opt,name=pool_id,json=poolId,proto3" json:"pool_id,omitempty"`
+	BlockNumber uint32 `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	// phase is "commit", "reveal" or "closed"
+	Phase       string         `protobuf:"bytes,3,opt,name=phase,proto3" json:"phase,omitempty"`
+	Commitments uint32         `protobuf:"varint,4,opt,name=commitments,proto3" json:"commitments,omitempty"`
+	Bids        []*RevealedBid `protobuf:"bytes,5,rep,name=bids,proto3" json:"bids,omitempty"`
+}
+
+func (x *BidBook) Reset() {
+	*x = BidBook{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BidBook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BidBook) ProtoMessage() {}
+
+func (x *BidBook) ProtoReflect() protoreflect.Message {
+	mi := &file_eigenlvr_v1_aggregator_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BidBook.ProtoReflect.Descriptor instead.
+func (*BidBook) Descriptor() ([]byte, []int) {
+	return file_eigenlvr_v1_aggregator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BidBook) GetPoolId() []byte {
+	if x != nil {
+		return x.PoolId
+	}
+	return nil
+}
+
+func (x *BidBook) GetBlockNumber() uint32 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *BidBook) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *BidBook) GetCommitments() uint32 {
+	if x != nil {
+		return x.Commitments
+	}
+	return 0
+}
+
+func (x *BidBook) GetBids() []*RevealedBid {
+	if x != nil {
+		return x.Bids
+	}
+	return nil
+}
+
+var File_eigenlvr_v1_aggregator_proto protoreflect.FileDescriptor
+
+var file_eigenlvr_v1_aggregator_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x61, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b,
+	0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x22, 0x98, 0x01, 0x0a, 0x0c,
+	0x54, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x14,
+	0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x5f, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x72, 0x65, 0x66, 0x65,
+	0x72, 0x65, 0x6e, 0x63, 0x65, 0x54, 0x61, 0x73, 0x6b, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16,
+	0x0a, 0x06, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x77, 0x69, 0x6e, 0x6e, 0x69, 0x6e,
+	0x67, 0x5f, 0x62, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x77, 0x69, 0x6e,
+	0x6e, 0x69, 0x6e, 0x67, 0x42, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x5f, 0x62, 0x69, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x6f, 0x74,
+	0x61, 0x6c, 0x42, 0x69, 0x64, 0x73, 0x22, 0xca, 0x01, 0x0a, 0x19, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x3e, 0x0a, 0x0d, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x65, 0x69,
+	0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x0c, 0x74, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x6c, 0x73, 0x5f, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x62, 0x6c, 0x73,
+	0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x65, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x22, 0x66, 0x0a, 0x17, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x61, 0x73,
+	0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x27,
+	0x0a, 0x0f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x03, 0x61, 0x63, 0x6b, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x63, 0x6b, 0x52, 0x03, 0x61, 0x63, 0x6b, 0x22, 0xc2, 0x01, 0x0a, 0x03,
+	0x41, 0x63, 0x6b, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x61, 0x73, 0x6b, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x5f, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f,
+	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x72, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x69,
+	0x67, 0x6e, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x69, 0x67, 0x6e,
+	0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x22, 0x35, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x54, 0x61, 0x73, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x73, 0x6b,
+	0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x61,
+	0x73, 0x6b, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x22, 0xdc, 0x03, 0x0a, 0x0a, 0x54, 0x61, 0x73, 0x6b,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x61, 0x73, 0x6b,
+	0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x17, 0x0a,
+	0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x2c, 0x0a, 0x12, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x10, 0x74, 0x61, 0x73, 0x6b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x73, 0x12, 0x4a, 0x0a, 0x13, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73,
+	0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x12, 0x61, 0x67, 0x67, 0x72, 0x65,
+	0x67, 0x61, 0x74, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2b, 0x0a,
+	0x11, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x10, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67,
+	0x61, 0x74, 0x65, 0x64, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x69,
+	0x67, 0x6e, 0x65, 0x72, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x73, 0x69, 0x67,
+	0x6e, 0x65, 0x72, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x5f, 0x74, 0x78, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x10, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x78, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x36, 0x0a, 0x17, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x15, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x72,
+	0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x61, 0x72,
+	0x63, 0x68, 0x69, 0x76, 0x65, 0x64, 0x22, 0x14, 0x0a, 0x12, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x54, 0x61, 0x73, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xf6, 0x01, 0x0a,
+	0x04, 0x54, 0x61, 0x73, 0x6b, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x74, 0x61, 0x73, 0x6b, 0x49,
+	0x6e, 0x64, 0x65, 0x78, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x21, 0x0a,
+	0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x12, 0x2c, 0x0a, 0x12, 0x74, 0x61, 0x73, 0x6b, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x74, 0x61,
+	0x73, 0x6b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x25,
+	0x0a, 0x0e, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x4e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x12, 0x3e, 0x0a, 0x1b, 0x71, 0x75, 0x6f, 0x72, 0x75, 0x6d, 0x5f,
+	0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x61, 0x67, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x19, 0x71, 0x75, 0x6f, 0x72,
+	0x75, 0x6d, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x50, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x61, 0x67, 0x65, 0x22, 0x8f, 0x01, 0x0a, 0x03, 0x42, 0x69, 0x64, 0x12, 0x17, 0x0a,
+	0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x69, 0x64,
+	0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x62, 0x69, 0x64, 0x64, 0x65,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x9d, 0x01, 0x0a, 0x09, 0x53, 0x65, 0x61, 0x6c,
+	0x65, 0x64, 0x42, 0x69, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x21,
+	0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x69, 0x64, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x06, 0x62, 0x69, 0x64, 0x64, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d,
+	0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x49, 0x0a, 0x10, 0x43, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x42, 0x69, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x0a, 0x73,
+	0x65, 0x61, 0x6c, 0x65, 0x64, 0x5f, 0x62, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65,
+	0x61, 0x6c, 0x65, 0x64, 0x42, 0x69, 0x64, 0x52, 0x09, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x64, 0x42,
+	0x69, 0x64, 0x22, 0x3c, 0x0a, 0x0e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x42, 0x69, 0x64, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x64, 0x5f, 0x62,
+	0x69, 0x64, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0f, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x64, 0x42, 0x69, 0x64, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74,
+	0x22, 0x4a, 0x0a, 0x10, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x42, 0x69, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x03, 0x62, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x42, 0x69, 0x64, 0x52, 0x03, 0x62, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x61, 0x6c, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x22, 0x2f, 0x0a, 0x0e,
+	0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x42, 0x69, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1d,
+	0x0a, 0x0a, 0x62, 0x69, 0x64, 0x5f, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x62, 0x69, 0x64, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0x4c, 0x0a,
+	0x0e, 0x47, 0x65, 0x74, 0x42, 0x69, 0x64, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b,
+	0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x7c, 0x0a, 0x0b, 0x52,
+	0x65, 0x76, 0x65, 0x61, 0x6c, 0x65, 0x64, 0x42, 0x69, 0x64, 0x12, 0x35, 0x0a, 0x0a, 0x73, 0x65,
+	0x61, 0x6c, 0x65, 0x64, 0x5f, 0x62, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16,
+	0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61,
+	0x6c, 0x65, 0x64, 0x42, 0x69, 0x64, 0x52, 0x09, 0x73, 0x65, 0x61, 0x6c, 0x65, 0x64, 0x42, 0x69,
+	0x64, 0x12, 0x22, 0x0a, 0x03, 0x62, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x69, 0x64,
+	0x52, 0x03, 0x62, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x73, 0x61, 0x6c, 0x74, 0x22, 0xab, 0x01, 0x0a, 0x07, 0x42, 0x69,
+	0x64, 0x42, 0x6f, 0x6f, 0x6b, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x6f, 0x6f, 0x6c, 0x49, 0x64, 0x12, 0x21,
+	0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x63, 0x6f,
+	0x6d, 0x6d, 0x69, 0x74, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x2c, 0x0a, 0x04, 0x62, 0x69, 0x64,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c,
+	0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x65, 0x64, 0x42, 0x69,
+	0x64, 0x52, 0x04, 0x62, 0x69, 0x64, 0x73, 0x32, 0xd2, 0x03, 0x0a, 0x0a, 0x41, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x62, 0x0a, 0x12, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x54, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x2e, 0x65,
+	0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x4b, 0x0a, 0x0d, 0x47, 0x65,
+	0x74, 0x54, 0x61, 0x73, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x21, 0x2e, 0x65, 0x69,
+	0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x61, 0x73,
+	0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17,
+	0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73,
+	0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x43, 0x0a, 0x0b, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x54, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x1f, 0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x54, 0x61, 0x73, 0x6b, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c,
+	0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x30, 0x01, 0x12, 0x47, 0x0a, 0x09,
+	0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x42, 0x69, 0x64, 0x12, 0x1d, 0x2e, 0x65, 0x69, 0x67, 0x65,
+	0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x42, 0x69,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e,
+	0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x42, 0x69, 0x64,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x47, 0x0a, 0x09, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x42,
+	0x69, 0x64, 0x12, 0x1d, 0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x42, 0x69, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1b, 0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x42, 0x69, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x3c,
+	0x0a, 0x07, 0x47, 0x65, 0x74, 0x42, 0x69, 0x64, 0x73, 0x12, 0x1b, 0x2e, 0x65, 0x69, 0x67, 0x65,
+	0x6e, 0x6c, 0x76, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x42, 0x69, 0x64, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x65, 0x69, 0x67, 0x65, 0x6e, 0x6c, 0x76,
+	0x72, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x69, 0x64, 0x42, 0x6f, 0x6f, 0x6b, 0x42, 0x2a, 0x5a, 0x28,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x65, 0x69, 0x67, 0x65, 0x6e,
+	0x6c, 0x76, 0x72, 0x2f, 0x61, 0x76, 0x73, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x6f, 0x72, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_eigenlvr_v1_aggregator_proto_rawDescOnce sync.Once
+	file_eigenlvr_v1_aggregator_proto_rawDescData = file_eigenlvr_v1_aggregator_proto_rawDesc
+)
+
+func file_eigenlvr_v1_aggregator_proto_rawDescGZIP() []byte {
+	file_eigenlvr_v1_aggregator_proto_rawDescOnce.Do(func() {
+		file_eigenlvr_v1_aggregator_proto_rawDescData = protoimpl.X.CompressGZIP(file_eigenlvr_v1_aggregator_proto_rawDescData)
+	})
+	return file_eigenlvr_v1_aggregator_proto_rawDescData
+}
+
+var file_eigenlvr_v1_aggregator_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_eigenlvr_v1_aggregator_proto_goTypes = []interface{}{
+	(*TaskResponse)(nil),              // 0: eigenlvr.v1.TaskResponse
+	(*SubmitTaskResponseRequest)(nil), // 1: eigenlvr.v1.SubmitTaskResponseRequest
+	(*SubmitTaskResponseReply)(nil),   // 2: eigenlvr.v1.SubmitTaskResponseReply
+	(*Ack)(nil),                       // 3: eigenlvr.v1.Ack
+	(*GetTaskStatusRequest)(nil),      // 4: eigenlvr.v1.GetTaskStatusRequest
+	(*TaskStatus)(nil),                // 5: eigenlvr.v1.TaskStatus
+	(*StreamTasksRequest)(nil),        // 6: eigenlvr.v1.StreamTasksRequest
+	(*Task)(nil),                      // 7: eigenlvr.v1.Task
+	(*Bid)(nil),                       // 8: eigenlvr.v1.Bid
+	(*SealedBid)(nil),                 // 9: eigenlvr.v1.SealedBid
+	(*CommitBidRequest)(nil),          // 10: eigenlvr.v1.CommitBidRequest
+	(*CommitBidReply)(nil),            // 11: eigenlvr.v1.CommitBidReply
+	(*RevealBidRequest)(nil),          // 12: eigenlvr.v1.RevealBidRequest
+	(*RevealBidReply)(nil),            // 13: eigenlvr.v1.RevealBidReply
+	(*GetBidsRequest)(nil),            // 14: eigenlvr.v1.GetBidsRequest
+	(*RevealedBid)(nil),               // 15: eigenlvr.v1.RevealedBid
+	(*BidBook)(nil),                   // 16: eigenlvr.v1.BidBook
+}
+var file_eigenlvr_v1_aggregator_proto_depIdxs = []int32{
+	0,  // 0: eigenlvr.v1.SubmitTaskResponseRequest.task_response:type_name -> eigenlvr.v1.TaskResponse
+	3,  // 1: eigenlvr.v1.SubmitTaskResponseReply.ack:type_name -> eigenlvr.v1.Ack
+	0,  // 2: eigenlvr.v1.TaskStatus.aggregated_response:type_name -> eigenlvr.v1.TaskResponse
+	9,  // 3: eigenlvr.v1.CommitBidRequest.sealed_bid:type_name -> eigenlvr.v1.SealedBid
+	8,  // 4: eigenlvr.v1.RevealBidRequest.bid:type_name -> eigenlvr.v1.Bid
+	9,  // 5: eigenlvr.v1.RevealedBid.sealed_bid:type_name -> eigenlvr.v1.SealedBid
+	8,  // 6: eigenlvr.v1.RevealedBid.bid:type_name -> eigenlvr.v1.Bid
+	15, // 7: eigenlvr.v1.BidBook.bids:type_name -> eigenlvr.v1.RevealedBid
+	1,  // 8: eigenlvr.v1.Aggregator.SubmitTaskResponse:input_type -> eigenlvr.v1.SubmitTaskResponseRequest
+	4,  // 9: eigenlvr.v1.Aggregator.GetTaskStatus:input_type -> eigenlvr.v1.GetTaskStatusRequest
+	6,  // 10: eigenlvr.v1.Aggregator.StreamTasks:input_type -> eigenlvr.v1.StreamTasksRequest
+	10, // 11: eigenlvr.v1.Aggregator.CommitBid:input_type -> eigenlvr.v1.CommitBidRequest
+	12, // 12: eigenlvr.v1.Aggregator.RevealBid:input_type -> eigenlvr.v1.RevealBidRequest
+	14, // 13: eigenlvr.v1.Aggregator.GetBids:input_type -> eigenlvr.v1.GetBidsRequest
+	2,  // 14: eigenlvr.v1.Aggregator.SubmitTaskResponse:output_type -> eigenlvr.v1.SubmitTaskResponseReply
+	5,  // 15: eigenlvr.v1.Aggregator.GetTaskStatus:output_type -> eigenlvr.v1.TaskStatus
+	7,  // 16: eigenlvr.v1.Aggregator.StreamTasks:output_type -> eigenlvr.v1.Task
+	11, // 17: eigenlvr.v1.Aggregator.CommitBid:output_type -> eigenlvr.v1.CommitBidReply
+	13, // 18: eigenlvr.v1.Aggregator.RevealBid:output_type -> eigenlvr.v1.RevealBidReply
+	16, // 19: eigenlvr.v1.Aggregator.GetBids:output_type -> eigenlvr.v1.BidBook
+	14, // [14:20] is the sub-list for method output_type
+	8,  // [8:14] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_eigenlvr_v1_aggregator_proto_init() }
+func file_eigenlvr_v1_aggregator_proto_init() {
+	if File_eigenlvr_v1_aggregator_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_eigenlvr_v1_aggregator_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TaskResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitTaskResponseRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitTaskResponseReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Ack); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTaskStatusRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TaskStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamTasksRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Task); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Bid); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SealedBid); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommitBidRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommitBidReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevealBidRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevealBidReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetBidsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevealedBid); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_eigenlvr_v1_aggregator_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BidBook); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_eigenlvr_v1_aggregator_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_eigenlvr_v1_aggregator_proto_goTypes,
+		DependencyIndexes: file_eigenlvr_v1_aggregator_proto_depIdxs,
+		MessageInfos:      file_eigenlvr_v1_aggregator_proto_msgTypes,
+	}.Build()
+	File_eigenlvr_v1_aggregator_proto = out.File
+	file_eigenlvr_v1_aggregator_proto_rawDesc = nil
+	file_eigenlvr_v1_aggregator_proto_goTypes = nil
+	file_eigenlvr_v1_aggregator_proto_depIdxs = nil
+}
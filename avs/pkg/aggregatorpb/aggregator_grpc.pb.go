@@ -0,0 +1,344 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.3
+// source: eigenlvr/v1/aggregator.proto
+
+package aggregatorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Aggregator_SubmitTaskResponse_FullMethodName = "/eigenlvr.v1.Aggregator/SubmitTaskResponse"
+	Aggregator_GetTaskStatus_FullMethodName      = "/eigenlvr.v1.Aggregator/GetTaskStatus"
+	Aggregator_StreamTasks_FullMethodName        = "/eigenlvr.v1.Aggregator/StreamTasks"
+	Aggregator_CommitBid_FullMethodName          = "/eigenlvr.v1.Aggregator/CommitBid"
+	Aggregator_RevealBid_FullMethodName          = "/eigenlvr.v1.Aggregator/RevealBid"
+	Aggregator_GetBids_FullMethodName            = "/eigenlvr.v1.Aggregator/GetBids"
+)
+
+// AggregatorClient is the client API for Aggregator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AggregatorClient interface {
+	// SubmitTaskResponse hands a signed task response to the aggregator and
+	// returns the digest it was accepted over, with a signed ack when the
+	// aggregator has a key
+	SubmitTaskResponse(ctx context.Context, in *SubmitTaskResponseRequest, opts ...grpc.CallOption) (*SubmitTaskResponseReply, error)
+	// GetTaskStatus returns a task's outcome, from memory or the task store
+	GetTaskStatus(ctx context.Context, in *GetTaskStatusRequest, opts ...grpc.CallOption) (*TaskStatus, error)
+	// StreamTasks pushes every new task until the client disconnects
+	StreamTasks(ctx context.Context, in *StreamTasksRequest, opts ...grpc.CallOption) (Aggregator_StreamTasksClient, error)
+	// CommitBid adds a searcher's sealed bid to its auction during the commit
+	// phase and returns the sealed bid's digest
+	CommitBid(ctx context.Context, in *CommitBidRequest, opts ...grpc.CallOption) (*CommitBidReply, error)
+	// RevealBid opens a sealed bid during the reveal phase and returns the
+	// bid's digest
+	RevealBid(ctx context.Context, in *RevealBidRequest, opts ...grpc.CallOption) (*RevealBidReply, error)
+	// GetBids returns a pool's auction for a block, with the revealed bids
+	// highest first
+	GetBids(ctx context.Context, in *GetBidsRequest, opts ...grpc.CallOption) (*BidBook, error)
+}
+
+type aggregatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAggregatorClient(cc grpc.ClientConnInterface) AggregatorClient {
+	return &aggregatorClient{cc}
+}
+
+func (c *aggregatorClient) SubmitTaskResponse(ctx context.Context, in *SubmitTaskResponseRequest, opts ...grpc.CallOption) (*SubmitTaskResponseReply, error) {
+	out := new(SubmitTaskResponseReply)
+	err := c.cc.Invoke(ctx, Aggregator_SubmitTaskResponse_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aggregatorClient) GetTaskStatus(ctx context.Context, in *GetTaskStatusRequest, opts ...grpc.CallOption) (*TaskStatus, error) {
+	out := new(TaskStatus)
+	err := c.cc.Invoke(ctx, Aggregator_GetTaskStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aggregatorClient) StreamTasks(ctx context.Context, in *StreamTasksRequest, opts ...grpc.CallOption) (Aggregator_StreamTasksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Aggregator_ServiceDesc.Streams[0], Aggregator_StreamTasks_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aggregatorStreamTasksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Aggregator_StreamTasksClient interface {
+	Recv() (*Task, error)
+	grpc.ClientStream
+}
+
+type aggregatorStreamTasksClient struct {
+	grpc.ClientStream
+}
+
+func (x *aggregatorStreamTasksClient) Recv() (*Task, error) {
+	m := new(Task)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aggregatorClient) CommitBid(ctx context.Context, in *CommitBidRequest, opts ...grpc.CallOption) (*CommitBidReply, error) {
+	out := new(CommitBidReply)
+	err := c.cc.Invoke(ctx, Aggregator_CommitBid_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aggregatorClient) RevealBid(ctx context.Context, in *RevealBidRequest, opts ...grpc.CallOption) (*RevealBidReply, error) {
+	out := new(RevealBidReply)
+	err := c.cc.Invoke(ctx, Aggregator_RevealBid_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aggregatorClient) GetBids(ctx context.Context, in *GetBidsRequest, opts ...grpc.CallOption) (*BidBook, error) {
+	out := new(BidBook)
+	err := c.cc.Invoke(ctx, Aggregator_GetBids_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AggregatorServer is the server API for Aggregator service.
+// All implementations must embed UnimplementedAggregatorServer
+// for forward compatibility
+type AggregatorServer interface {
+	// SubmitTaskResponse hands a signed task response to the aggregator and
+	// returns the digest it was accepted over, with a signed ack when the
+	// aggregator has a key
+	SubmitTaskResponse(context.Context, *SubmitTaskResponseRequest) (*SubmitTaskResponseReply, error)
+	// GetTaskStatus returns a task's outcome, from memory or the task store
+	GetTaskStatus(context.Context, *GetTaskStatusRequest) (*TaskStatus, error)
+	// StreamTasks pushes every new task until the client disconnects
+	StreamTasks(*StreamTasksRequest, Aggregator_StreamTasksServer) error
+	// CommitBid adds a searcher's sealed bid to its auction during the commit
+	// phase and returns the sealed bid's digest
+	CommitBid(context.Context, *CommitBidRequest) (*CommitBidReply, error)
+	// RevealBid opens a sealed bid during the reveal phase and returns the
+	// bid's digest
+	RevealBid(context.Context, *RevealBidRequest) (*RevealBidReply, error)
+	// GetBids returns a pool's auction for a block, with the revealed bids
+	// highest first
+	GetBids(context.Context, *GetBidsRequest) (*BidBook, error)
+	mustEmbedUnimplementedAggregatorServer()
+}
+
+// UnimplementedAggregatorServer must be embedded to have forward compatible implementations.
+type UnimplementedAggregatorServer struct {
+}
+
+func (UnimplementedAggregatorServer) SubmitTaskResponse(context.Context, *SubmitTaskResponseRequest) (*SubmitTaskResponseReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitTaskResponse not implemented")
+}
+func (UnimplementedAggregatorServer) GetTaskStatus(context.Context, *GetTaskStatusRequest) (*TaskStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTaskStatus not implemented")
+}
+func (UnimplementedAggregatorServer) StreamTasks(*StreamTasksRequest, Aggregator_StreamTasksServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTasks not implemented")
+}
+func (UnimplementedAggregatorServer) CommitBid(context.Context, *CommitBidRequest) (*CommitBidReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitBid not implemented")
+}
+func (UnimplementedAggregatorServer) RevealBid(context.Context, *RevealBidRequest) (*RevealBidReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevealBid not implemented")
+}
+func (UnimplementedAggregatorServer) GetBids(context.Context, *GetBidsRequest) (*BidBook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBids not implemented")
+}
+func (UnimplementedAggregatorServer) mustEmbedUnimplementedAggregatorServer() {}
+
+// UnsafeAggregatorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AggregatorServer will
+// result in compilation errors.
+type UnsafeAggregatorServer interface {
+	mustEmbedUnimplementedAggregatorServer()
+}
+
+func RegisterAggregatorServer(s grpc.ServiceRegistrar, srv AggregatorServer) {
+	s.RegisterService(&Aggregator_ServiceDesc, srv)
+}
+
+func _Aggregator_SubmitTaskResponse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitTaskResponseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AggregatorServer).SubmitTaskResponse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Aggregator_SubmitTaskResponse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AggregatorServer).SubmitTaskResponse(ctx, req.(*SubmitTaskResponseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Aggregator_GetTaskStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaskStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AggregatorServer).GetTaskStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Aggregator_GetTaskStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AggregatorServer).GetTaskStatus(ctx, req.(*GetTaskStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Aggregator_StreamTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamTasksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AggregatorServer).StreamTasks(m, &aggregatorStreamTasksServer{stream})
+}
+
+type Aggregator_StreamTasksServer interface {
+	Send(*Task) error
+	grpc.ServerStream
+}
+
+type aggregatorStreamTasksServer struct {
+	grpc.ServerStream
+}
+
+func (x *aggregatorStreamTasksServer) Send(m *Task) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Aggregator_CommitBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AggregatorServer).CommitBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Aggregator_CommitBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AggregatorServer).CommitBid(ctx, req.(*CommitBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Aggregator_RevealBid_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevealBidRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AggregatorServer).RevealBid(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Aggregator_RevealBid_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AggregatorServer).RevealBid(ctx, req.(*RevealBidRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Aggregator_GetBids_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBidsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AggregatorServer).GetBids(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Aggregator_GetBids_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AggregatorServer).GetBids(ctx, req.(*GetBidsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Aggregator_ServiceDesc is the grpc.ServiceDesc for Aggregator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Aggregator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "eigenlvr.v1.Aggregator",
+	HandlerType: (*AggregatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitTaskResponse",
+			Handler:    _Aggregator_SubmitTaskResponse_Handler,
+		},
+		{
+			MethodName: "GetTaskStatus",
+			Handler:    _Aggregator_GetTaskStatus_Handler,
+		},
+		{
+			MethodName: "CommitBid",
+			Handler:    _Aggregator_CommitBid_Handler,
+		},
+		{
+			MethodName: "RevealBid",
+			Handler:    _Aggregator_RevealBid_Handler,
+		},
+		{
+			MethodName: "GetBids",
+			Handler:    _Aggregator_GetBids_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamTasks",
+			Handler:       _Aggregator_StreamTasks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "eigenlvr/v1/aggregator.proto",
+}
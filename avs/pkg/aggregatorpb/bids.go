@@ -0,0 +1,146 @@
+package aggregatorpb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/auction"
+	"github.com/eigenlvr/avs/pkg/bids"
+)
+
+// EncodeBid returns the signed bid as its message
+func EncodeBid(bid bids.SignedBid) *Bid {
+	return &Bid{
+		PoolId:      bid.PoolId.Bytes(),
+		BlockNumber: bid.BlockNumber,
+		Bidder:      bid.Bidder.Bytes(),
+		Amount:      EncodeBigInt(bid.Amount),
+		Signature:   bid.Signature,
+	}
+}
+
+// DecodeBid is the inverse of EncodeBid. It checks field sizes only, not the
+// signature.
+func DecodeBid(pb *Bid) (bids.SignedBid, error) {
+	if pb == nil {
+		return bids.SignedBid{}, errors.New("missing bid")
+	}
+	poolId, err := DecodeHash(pb.PoolId)
+	if err != nil {
+		return bids.SignedBid{}, fmt.Errorf("invalid pool id: %w", err)
+	}
+	bidder, err := DecodeAddress(pb.Bidder)
+	if err != nil {
+		return bids.SignedBid{}, fmt.Errorf("invalid bidder: %w", err)
+	}
+	return bids.SignedBid{
+		Bid: bids.Bid{
+			PoolId:      poolId,
+			BlockNumber: pb.BlockNumber,
+			Bidder:      bidder,
+			Amount:      DecodeBigInt(pb.Amount),
+		},
+		Signature: pb.Signature,
+	}, nil
+}
+
+// EncodeSealedBid returns the signed sealed bid as its message
+func EncodeSealedBid(sealed auction.SignedSealedBid) *SealedBid {
+	return &SealedBid{
+		PoolId:      sealed.PoolId.Bytes(),
+		BlockNumber: sealed.BlockNumber,
+		Bidder:      sealed.Bidder.Bytes(),
+		Commitment:  sealed.Commitment.Bytes(),
+		Signature:   sealed.Signature,
+	}
+}
+
+// DecodeSealedBid is the inverse of EncodeSealedBid. It checks field sizes
+// only, not the signature.
+func DecodeSealedBid(pb *SealedBid) (auction.SignedSealedBid, error) {
+	if pb == nil {
+		return auction.SignedSealedBid{}, errors.New("missing sealed bid")
+	}
+	poolId, err := DecodeHash(pb.PoolId)
+	if err != nil {
+		return auction.SignedSealedBid{}, fmt.Errorf("invalid pool id: %w", err)
+	}
+	bidder, err := DecodeAddress(pb.Bidder)
+	if err != nil {
+		return auction.SignedSealedBid{}, fmt.Errorf("invalid bidder: %w", err)
+	}
+	commitment, err := DecodeHash(pb.Commitment)
+	if err != nil {
+		return auction.SignedSealedBid{}, fmt.Errorf("invalid commitment: %w", err)
+	}
+	return auction.SignedSealedBid{
+		SealedBid: auction.SealedBid{
+			PoolId:      poolId,
+			BlockNumber: pb.BlockNumber,
+			Bidder:      bidder,
+			Commitment:  commitment,
+		},
+		Signature: pb.Signature,
+	}, nil
+}
+
+// DecodeReveal returns the bid and salt a reveal request carries
+func DecodeReveal(bid *Bid, salt []byte) (auction.Reveal, error) {
+	signedBid, err := DecodeBid(bid)
+	if err != nil {
+		return auction.Reveal{}, err
+	}
+	if len(salt) != common.HashLength {
+		return auction.Reveal{}, fmt.Errorf("invalid salt of %d bytes", len(salt))
+	}
+	return auction.Reveal{SignedBid: signedBid, Salt: common.BytesToHash(salt)}, nil
+}
+
+// EncodeBidBook returns the auction's bid book as its message
+func EncodeBidBook(book auction.Book) *BidBook {
+	pb := &BidBook{
+		PoolId:      book.PoolId.Bytes(),
+		BlockNumber: book.BlockNumber,
+		Phase:       string(book.Phase),
+		Commitments: uint32(book.Commitments),
+		Bids:        make([]*RevealedBid, len(book.Revealed)),
+	}
+	for i, revealed := range book.Revealed {
+		pb.Bids[i] = &RevealedBid{
+			SealedBid: EncodeSealedBid(revealed.Sealed),
+			Bid:       EncodeBid(revealed.Reveal.SignedBid),
+			Salt:      revealed.Reveal.Salt.Bytes(),
+		}
+	}
+	return pb
+}
+
+// DecodeBidBook is the inverse of EncodeBidBook. Revealed bids are not
+// verified.
+func DecodeBidBook(pb *BidBook) (auction.Book, error) {
+	poolId, err := DecodeHash(pb.PoolId)
+	if err != nil {
+		return auction.Book{}, fmt.Errorf("invalid pool id: %w", err)
+	}
+	book := auction.Book{
+		PoolId:      poolId,
+		BlockNumber: pb.BlockNumber,
+		Phase:       auction.Phase(pb.Phase),
+		Commitments: int(pb.Commitments),
+		Revealed:    make([]auction.RevealedBid, len(pb.Bids)),
+	}
+	for i, revealed := range pb.Bids {
+		sealed, err := DecodeSealedBid(revealed.SealedBid)
+		if err != nil {
+			return auction.Book{}, err
+		}
+		reveal, err := DecodeReveal(revealed.Bid, revealed.Salt)
+		if err != nil {
+			return auction.Book{}, err
+		}
+		book.Revealed[i] = auction.RevealedBid{Sealed: sealed, Reveal: reveal}
+	}
+	return book, nil
+}
@@ -0,0 +1,75 @@
+// Package aggregatorpb holds the gRPC interface between operators and the
+// aggregator, generated from proto/eigenlvr/v1/aggregator.proto, and the
+// conversions between its byte fields and the values they carry.
+package aggregatorpb
+
+//go:generate protoc -I ../../proto --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative eigenlvr/v1/aggregator.proto
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fieldElementBytes is the size of a bn254 base field element
+const fieldElementBytes = 32
+
+// ErrInvalidPoint is returned for G1 bytes that aren't a point of the bn254
+// G1 subgroup
+var ErrInvalidPoint = errors.New("invalid G1 point")
+
+// EncodeBigInt returns the unsigned big-endian bytes of value, empty for nil
+func EncodeBigInt(value *big.Int) []byte {
+	if value == nil {
+		return nil
+	}
+	return value.Bytes()
+}
+
+// DecodeBigInt is the inverse of EncodeBigInt
+func DecodeBigInt(data []byte) *big.Int {
+	return new(big.Int).SetBytes(data)
+}
+
+// EncodeG1 returns the point as X then Y, each a 32-byte big-endian field element
+func EncodeG1(point *bls.G1Point) []byte {
+	data := make([]byte, 2*fieldElementBytes)
+	point.X.BigInt(new(big.Int)).FillBytes(data[:fieldElementBytes])
+	point.Y.BigInt(new(big.Int)).FillBytes(data[fieldElementBytes:])
+	return data
+}
+
+// DecodeG1 is the inverse of EncodeG1. It refuses points off the curve or
+// outside the G1 subgroup.
+func DecodeG1(data []byte) (*bls.G1Point, error) {
+	if len(data) != 2*fieldElementBytes {
+		return nil, fmt.Errorf("%w: %d bytes, expected %d", ErrInvalidPoint, len(data), 2*fieldElementBytes)
+	}
+	point := bls.NewG1Point(
+		new(big.Int).SetBytes(data[:fieldElementBytes]),
+		new(big.Int).SetBytes(data[fieldElementBytes:]),
+	)
+	if !point.IsOnCurve() || !point.IsInSubGroup() {
+		return nil, ErrInvalidPoint
+	}
+	return point, nil
+}
+
+// DecodeHash returns the 32 bytes as a hash
+func DecodeHash(data []byte) (common.Hash, error) {
+	if len(data) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid hash of %d bytes", len(data))
+	}
+	return common.BytesToHash(data), nil
+}
+
+// DecodeAddress returns the 20 bytes as an address
+func DecodeAddress(data []byte) (common.Address, error) {
+	if len(data) != common.AddressLength {
+		return common.Address{}, fmt.Errorf("invalid address of %d bytes", len(data))
+	}
+	return common.BytesToAddress(data), nil
+}
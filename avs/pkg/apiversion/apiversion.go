@@ -0,0 +1,41 @@
+// Package apiversion holds the versioning scheme of the aggregator API shared
+// by the aggregator and the operators calling it.
+//
+// Every route is served under a version prefix such as /v1. A breaking change
+// to a wire format gets a new prefix while the previous one keeps being
+// served, so operators can upgrade on their own schedule. Routes without a
+// prefix are the original API; they behave like v1 but are deprecated.
+package apiversion
+
+import "net/http"
+
+const (
+	// Current is the version this build speaks
+	Current = "v1"
+
+	// Header carries the API version on every aggregator response. Its absence
+	// identifies an aggregator that predates versioning.
+	Header = "X-Api-Version"
+
+	// DeprecationHeader is set on responses from deprecated routes
+	DeprecationHeader = "Deprecation"
+)
+
+// Supported lists the versions served, oldest first
+var Supported = []string{Current}
+
+// Path returns path under the current version prefix
+func Path(path string) string {
+	return "/" + Current + path
+}
+
+// IsUnversioned reports whether the response came from an aggregator that
+// predates versioned routes
+func IsUnversioned(resp *http.Response) bool {
+	return resp.Header.Get(Header) == ""
+}
+
+// IsDeprecated reports whether the response came from a deprecated route
+func IsDeprecated(resp *http.Response) bool {
+	return resp.Header.Get(DeprecationHeader) != ""
+}
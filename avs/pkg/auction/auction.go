@@ -0,0 +1,317 @@
+// Package auction runs a pool's sealed-bid LVR auction for a block by commit
+// and reveal. During the commit phase bidders submit signed commitments that
+// hide their bids. Once commits close, bidders reveal each bid with the salt
+// it was sealed with, and the auction only counts reveals that open a
+// commitment. Operators recompute the outcome from the revealed bids, so no
+// bidder could see a rival's bid before committing to its own.
+package auction
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/bids"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+)
+
+const (
+	// sealDomain separates sealed bid signatures from any other signature
+	// made with the bidder's key
+	sealDomain = "eigenlvr sealed auction bid"
+	// commitmentDomain separates commitments from other hashes of a bid
+	commitmentDomain = "eigenlvr auction bid commitment"
+)
+
+var (
+	// ErrWrongAuction is returned for bids and reveals of another pool or block
+	ErrWrongAuction = errors.New("bid is for another auction")
+	// ErrInvalidSignature is returned when no signer can be recovered from a
+	// sealed bid
+	ErrInvalidSignature = errors.New("invalid sealed bid signature")
+	// ErrWrongSigner is returned when a sealed bid wasn't signed by its bidder
+	ErrWrongSigner = errors.New("sealed bid not signed by its bidder")
+	// ErrCommitClosed is returned for commitments after the commit phase
+	ErrCommitClosed = errors.New("commit phase is over")
+	// ErrRevealNotOpen is returned for reveals during the commit phase
+	ErrRevealNotOpen = errors.New("reveal phase has not started")
+	// ErrClosed is returned for reveals once the auction's block is on chain
+	ErrClosed = errors.New("auction has closed")
+	// ErrNoCommitment is returned for reveals from bidders that didn't commit
+	ErrNoCommitment = errors.New("bidder has no commitment")
+	// ErrCommitmentMismatch is returned for reveals that don't open the
+	// bidder's commitment
+	ErrCommitmentMismatch = errors.New("reveal does not match commitment")
+)
+
+// Phase is where an auction stands relative to the chain head
+type Phase string
+
+const (
+	PhaseCommit Phase = "commit"
+	PhaseReveal Phase = "reveal"
+	PhaseClosed Phase = "closed"
+)
+
+// Schedule sets when an auction's phases turn over. The auction for block N
+// takes commitments while the head is before N-RevealBlocks and reveals from
+// then until block N is on chain.
+type Schedule struct {
+	RevealBlocks uint64
+}
+
+// Phase returns the phase of the auction for blockNumber with the chain at head
+func (s Schedule) Phase(blockNumber uint32, head uint64) Phase {
+	switch {
+	case head >= uint64(blockNumber):
+		return PhaseClosed
+	case head+s.RevealBlocks >= uint64(blockNumber):
+		return PhaseReveal
+	default:
+		return PhaseCommit
+	}
+}
+
+// Commitment is the hash a bid is sealed as. The salt keeps bids of a few
+// likely amounts from being found by hashing each of them.
+func Commitment(bid bids.Bid, salt common.Hash) common.Hash {
+	digest := bid.Digest()
+	return crypto.Keccak256Hash([]byte(commitmentDomain), digest[:], salt[:])
+}
+
+// SealedBid commits a bidder to a bid in an auction without disclosing it
+type SealedBid struct {
+	PoolId      common.Hash    `json:"poolId"`
+	BlockNumber uint32         `json:"blockNumber"`
+	Bidder      common.Address `json:"bidder"`
+	Commitment  common.Hash    `json:"commitment"`
+}
+
+// SignedSealedBid is a SealedBid with the bidder's signature over its digest.
+// The signature keeps anyone else from replacing a bidder's commitment.
+type SignedSealedBid struct {
+	SealedBid
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// Digest is the hash the bidder signs
+func (s SealedBid) Digest() common.Hash {
+	buf := make([]byte, 0, len(sealDomain)+common.HashLength+4+common.AddressLength+common.HashLength)
+	buf = append(buf, sealDomain...)
+	buf = append(buf, s.PoolId[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, s.BlockNumber)
+	buf = append(buf, s.Bidder[:]...)
+	buf = append(buf, s.Commitment[:]...)
+	return crypto.Keccak256Hash(buf)
+}
+
+// Seal commits the bidder to bid with salt, signed by the bidder's key
+func Seal(ctx context.Context, bid bids.Bid, salt common.Hash, signer remotesigner.Signer) (SignedSealedBid, error) {
+	if signer.Address() != bid.Bidder {
+		return SignedSealedBid{}, fmt.Errorf("%w: signer %s, bidder %s", ErrWrongSigner, signer.Address().Hex(), bid.Bidder.Hex())
+	}
+	sealed := SealedBid{
+		PoolId:      bid.PoolId,
+		BlockNumber: bid.BlockNumber,
+		Bidder:      bid.Bidder,
+		Commitment:  Commitment(bid, salt),
+	}
+	signature, err := signer.SignHash(ctx, sealed.Digest())
+	if err != nil {
+		return SignedSealedBid{}, fmt.Errorf("failed to sign sealed bid: %w", err)
+	}
+	return SignedSealedBid{SealedBid: sealed, Signature: signature}, nil
+}
+
+// Verify checks that the signature recovers to the bidder
+func (s SignedSealedBid) Verify() error {
+	digest := s.Digest()
+	publicKey, err := crypto.SigToPub(digest[:], s.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if signer := crypto.PubkeyToAddress(*publicKey); signer != s.Bidder {
+		return fmt.Errorf("%w: recovered %s, bidder %s", ErrWrongSigner, signer.Hex(), s.Bidder.Hex())
+	}
+	return nil
+}
+
+// Reveal discloses a sealed bid: the signed bid and the salt it was sealed with
+type Reveal struct {
+	bids.SignedBid
+	Salt common.Hash `json:"salt"`
+}
+
+// Opens checks that the reveal is a correctly signed bid matching the sealed
+// bid's commitment
+func (r Reveal) Opens(sealed SealedBid) error {
+	if err := r.Verify(); err != nil {
+		return err
+	}
+	if r.PoolId != sealed.PoolId || r.BlockNumber != sealed.BlockNumber || r.Bidder != sealed.Bidder {
+		return ErrWrongAuction
+	}
+	if Commitment(r.Bid, r.Salt) != sealed.Commitment {
+		return fmt.Errorf("%w: bidder %s", ErrCommitmentMismatch, r.Bidder.Hex())
+	}
+	return nil
+}
+
+// RevealedBid is a bidder's commitment with the reveal that opens it, which
+// anyone can check without trusting the auction that collected them
+type RevealedBid struct {
+	Sealed SignedSealedBid `json:"sealed"`
+	Reveal Reveal          `json:"reveal"`
+}
+
+// Verify checks both signatures and that the reveal opens the commitment
+func (r RevealedBid) Verify() error {
+	if err := r.Sealed.Verify(); err != nil {
+		return err
+	}
+	return r.Reveal.Opens(r.Sealed.SealedBid)
+}
+
+// Auction collects the commitments and reveals of a pool's auction for a
+// block. It is not safe for concurrent use.
+type Auction struct {
+	PoolId      common.Hash
+	BlockNumber uint32
+
+	schedule Schedule
+	sealed   map[common.Address]SignedSealedBid
+	revealed map[common.Address]Reveal
+}
+
+func New(poolId common.Hash, blockNumber uint32, schedule Schedule) *Auction {
+	return &Auction{
+		PoolId:      poolId,
+		BlockNumber: blockNumber,
+		schedule:    schedule,
+		sealed:      make(map[common.Address]SignedSealedBid),
+		revealed:    make(map[common.Address]Reveal),
+	}
+}
+
+// Phase returns the auction's phase with the chain at head
+func (a *Auction) Phase(head uint64) Phase {
+	return a.schedule.Phase(a.BlockNumber, head)
+}
+
+// Commit records a bidder's commitment, replacing any earlier one of theirs,
+// while the chain at head is in the commit phase
+func (a *Auction) Commit(sealed SignedSealedBid, head uint64) error {
+	if sealed.PoolId != a.PoolId || sealed.BlockNumber != a.BlockNumber {
+		return ErrWrongAuction
+	}
+	if err := sealed.Verify(); err != nil {
+		return err
+	}
+	if a.Phase(head) != PhaseCommit {
+		return ErrCommitClosed
+	}
+	a.sealed[sealed.Bidder] = sealed
+	return nil
+}
+
+// Reveal records the bid opening a bidder's commitment while the chain at
+// head is in the reveal phase
+func (a *Auction) Reveal(reveal Reveal, head uint64) error {
+	if reveal.PoolId != a.PoolId || reveal.BlockNumber != a.BlockNumber {
+		return ErrWrongAuction
+	}
+	switch a.Phase(head) {
+	case PhaseCommit:
+		return ErrRevealNotOpen
+	case PhaseClosed:
+		return ErrClosed
+	}
+
+	sealed, ok := a.sealed[reveal.Bidder]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoCommitment, reveal.Bidder.Hex())
+	}
+	if err := reveal.Opens(sealed.SealedBid); err != nil {
+		return err
+	}
+	a.revealed[reveal.Bidder] = reveal
+	return nil
+}
+
+// Committed reports whether the bidder has a commitment in the auction
+func (a *Auction) Committed(bidder common.Address) bool {
+	_, ok := a.sealed[bidder]
+	return ok
+}
+
+// Commitments returns how many bidders have committed
+func (a *Auction) Commitments() int {
+	return len(a.sealed)
+}
+
+// Revealed returns the opened commitments, highest bid first
+func (a *Auction) Revealed() []RevealedBid {
+	revealed := make([]RevealedBid, 0, len(a.revealed))
+	for bidder, reveal := range a.revealed {
+		revealed = append(revealed, RevealedBid{Sealed: a.sealed[bidder], Reveal: reveal})
+	}
+	sort.Slice(revealed, func(i, j int) bool {
+		return ranksAbove(revealed[i].Reveal.Bid, revealed[j].Reveal.Bid)
+	})
+	return revealed
+}
+
+// Open checks revealed bids collected by an auction elsewhere and returns
+// the bids of those that verify for the pool's auction for the block, and
+// the reasons the others were dropped. A bidder's bid is only counted once.
+func Open(poolId common.Hash, blockNumber uint32, revealed []RevealedBid) ([]bids.Bid, []error) {
+	var (
+		opened  []bids.Bid
+		dropped []error
+		seen    = make(map[common.Address]bool)
+	)
+	for _, r := range revealed {
+		bid := r.Reveal.Bid
+		if bid.PoolId != poolId || bid.BlockNumber != blockNumber {
+			dropped = append(dropped, fmt.Errorf("%w: bidder %s", ErrWrongAuction, bid.Bidder.Hex()))
+			continue
+		}
+		if err := r.Verify(); err != nil {
+			dropped = append(dropped, err)
+			continue
+		}
+		if seen[bid.Bidder] {
+			dropped = append(dropped, fmt.Errorf("duplicate bid from %s", bid.Bidder.Hex()))
+			continue
+		}
+		seen[bid.Bidder] = true
+		opened = append(opened, bid)
+	}
+	return opened, dropped
+}
+
+// Book is an auction's state as the aggregator serves it to operators
+type Book struct {
+	PoolId      common.Hash   `json:"poolId"`
+	BlockNumber uint32        `json:"blockNumber"`
+	Phase       Phase         `json:"phase"`
+	Commitments int           `json:"commitments"`
+	Revealed    []RevealedBid `json:"bids"`
+}
+
+// Book returns the auction's state with the chain at head
+func (a *Auction) Book(head uint64) Book {
+	return Book{
+		PoolId:      a.PoolId,
+		BlockNumber: a.BlockNumber,
+		Phase:       a.Phase(head),
+		Commitments: a.Commitments(),
+		Revealed:    a.Revealed(),
+	}
+}
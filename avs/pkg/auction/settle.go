@@ -0,0 +1,57 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/bids"
+	"github.com/eigenlvr/avs/pkg/escrow"
+)
+
+// EscrowCheck reports whether the bidder has the amount escrowed, returning
+// an error wrapping escrow.ErrInsufficientDeposit when it doesn't
+type EscrowCheck func(ctx context.Context, bidder common.Address, amount *big.Int) error
+
+// Settlement is an auction's outcome with the revealed bids it didn't count
+type Settlement struct {
+	Outcome
+	// Dropped are the reasons revealed bids were left out: reveals that don't
+	// verify for the auction and bids not covered by escrow
+	Dropped []error
+}
+
+// Settle opens the book's revealed bids for the pool's auction for the block,
+// leaves out bids the escrow check finds uncovered and determines the winner
+// under the rules. Operators sign their responses from it and the challenger
+// recomputes settled auctions with it, so both reach the same outcome from
+// the same book. A nil check counts every bid as escrowed.
+func Settle(ctx context.Context, poolId common.Hash, blockNumber uint32, book Book, rules Rules, escrowed EscrowCheck) (Settlement, error) {
+	opened, dropped := Open(poolId, blockNumber, book.Revealed)
+
+	counted := make([]bids.Bid, 0, len(opened))
+	for _, bid := range opened {
+		if escrowed != nil {
+			err := escrowed(ctx, bid.Bidder, bid.Amount)
+			if errors.Is(err, escrow.ErrInsufficientDeposit) {
+				dropped = append(dropped, fmt.Errorf("bid from %s: %w", bid.Bidder.Hex(), err))
+				continue
+			}
+			// Leaving out a bid whose deposit can't be read would settle a
+			// different winner than readers that could read it
+			if err != nil {
+				return Settlement{}, fmt.Errorf("failed to check escrow of bidder %s: %w", bid.Bidder.Hex(), err)
+			}
+		}
+		counted = append(counted, bid)
+	}
+
+	outcome, err := DetermineWinner(counted, rules)
+	if err != nil {
+		return Settlement{}, fmt.Errorf("failed to determine auction winner: %w", err)
+	}
+	return Settlement{Outcome: outcome, Dropped: dropped}, nil
+}
@@ -0,0 +1,166 @@
+package auction
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/bids"
+)
+
+// RulesV1 is the first version of the winner rules: bids below the minimum
+// are ignored, the highest remaining bid wins if it meets the reserve price,
+// and ties go to the lowest tie-break hash of pool, block and bidder
+const RulesV1 uint32 = 1
+
+// tieBreakDomain separates tie-break hashes from other hashes of a bid
+const tieBreakDomain = "eigenlvr auction tie-break"
+
+// ErrUnsupportedRules is returned for rules of a version this build doesn't
+// implement
+var ErrUnsupportedRules = errors.New("unsupported auction rules version")
+
+// Rules are what an auction's winner is determined by. Every operator must
+// use the same rules for a pool, or their responses won't aggregate.
+type Rules struct {
+	Version uint32 `json:"version"`
+	// MinBid is the smallest bid counted at all, nil counts every bid
+	MinBid *big.Int `json:"minBid,omitempty"`
+	// ReservePrice is the smallest winning bid. The auction has no winner
+	// when the highest bid is below it, nil accepts any bid.
+	ReservePrice *big.Int `json:"reservePrice,omitempty"`
+}
+
+// DefaultRules returns the latest rules with no minimum or reserve
+func DefaultRules() Rules {
+	return Rules{Version: RulesV1}
+}
+
+// ParseRules builds rules from config values: a version, 0 for the latest,
+// and decimal wei amounts for the minimum bid and reserve price, empty for
+// none
+func ParseRules(version uint32, minBid, reservePrice string) (Rules, error) {
+	rules := DefaultRules()
+	if version != 0 {
+		rules.Version = version
+	}
+	var err error
+	if rules.MinBid, err = parseWei("min bid", minBid); err != nil {
+		return Rules{}, err
+	}
+	if rules.ReservePrice, err = parseWei("reserve price", reservePrice); err != nil {
+		return Rules{}, err
+	}
+	if err := rules.Validate(); err != nil {
+		return Rules{}, err
+	}
+	return rules, nil
+}
+
+// Validate checks the rules' version and amounts
+func (r Rules) Validate() error {
+	if r.Version != RulesV1 {
+		return fmt.Errorf("%w: %d", ErrUnsupportedRules, r.Version)
+	}
+	if r.MinBid != nil && r.MinBid.Sign() < 0 {
+		return errors.New("minimum bid must not be negative")
+	}
+	if r.ReservePrice != nil && r.ReservePrice.Sign() < 0 {
+		return errors.New("reserve price must not be negative")
+	}
+	return nil
+}
+
+// Outcome is an auction's result under a version of the rules
+type Outcome struct {
+	// Winner is the zero address and WinningBid zero when no bid won
+	Winner     common.Address `json:"winner"`
+	WinningBid *big.Int       `json:"winningBid"`
+	// TotalBids counts the bids that met the minimum bid
+	TotalBids    uint32 `json:"totalBids"`
+	RulesVersion uint32 `json:"rulesVersion"`
+	BelowReserve bool   `json:"belowReserve,omitempty"`
+}
+
+// DetermineWinner returns the auction's outcome from its opened bids. The
+// result only depends on the set of bids and the rules, not on their order,
+// so operators holding the same bids reach the same response. Bids must all
+// be for one pool and block and come from distinct bidders, as Open returns
+// them.
+func DetermineWinner(opened []bids.Bid, rules Rules) (Outcome, error) {
+	if err := rules.Validate(); err != nil {
+		return Outcome{}, err
+	}
+
+	outcome := Outcome{WinningBid: big.NewInt(0), RulesVersion: rules.Version}
+	var (
+		winner  bids.Bid
+		found   bool
+		bidders = make(map[common.Address]bool, len(opened))
+	)
+	for _, bid := range opened {
+		if err := bid.Validate(); err != nil {
+			return Outcome{}, err
+		}
+		if len(bidders) > 0 && (bid.PoolId != opened[0].PoolId || bid.BlockNumber != opened[0].BlockNumber) {
+			return Outcome{}, fmt.Errorf("%w: bidder %s", ErrWrongAuction, bid.Bidder.Hex())
+		}
+		if bidders[bid.Bidder] {
+			return Outcome{}, fmt.Errorf("duplicate bid from %s", bid.Bidder.Hex())
+		}
+		bidders[bid.Bidder] = true
+
+		if rules.MinBid != nil && bid.Amount.Cmp(rules.MinBid) < 0 {
+			continue
+		}
+		outcome.TotalBids++
+		if !found || ranksAbove(bid, winner) {
+			winner, found = bid, true
+		}
+	}
+	if !found {
+		return outcome, nil
+	}
+	if rules.ReservePrice != nil && winner.Amount.Cmp(rules.ReservePrice) < 0 {
+		outcome.BelowReserve = true
+		return outcome, nil
+	}
+	outcome.Winner = winner.Bidder
+	outcome.WinningBid = new(big.Int).Set(winner.Amount)
+	return outcome, nil
+}
+
+// parseWei parses a decimal wei amount, nil when empty
+func parseWei(name, value string) (*big.Int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid %s: %q", name, value)
+	}
+	return amount, nil
+}
+
+// ranksAbove orders bids by amount, then by tie-break hash so the order
+// doesn't depend on arrival and no bidder address wins every tie
+func ranksAbove(a, b bids.Bid) bool {
+	if cmp := a.Amount.Cmp(b.Amount); cmp != 0 {
+		return cmp > 0
+	}
+	return tieBreak(a).Cmp(tieBreak(b)) < 0
+}
+
+// tieBreak is the hash equal bids are ranked by, lowest first
+func tieBreak(b bids.Bid) common.Hash {
+	buf := make([]byte, 0, len(tieBreakDomain)+common.HashLength+4+common.AddressLength)
+	buf = append(buf, tieBreakDomain...)
+	buf = append(buf, b.PoolId[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, b.BlockNumber)
+	buf = append(buf, b.Bidder[:]...)
+	return crypto.Keccak256Hash(buf)
+}
@@ -2,19 +2,40 @@ package avsregistry
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
+	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
 	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/wallet"
 	"github.com/Layr-Labs/eigensdk-go/chainio/txmgr"
+	chainioutils "github.com/Layr-Labs/eigensdk-go/chainio/utils"
+	avsdirectory "github.com/Layr-Labs/eigensdk-go/contracts/bindings/AVSDirectory"
+	opstateretriever "github.com/Layr-Labs/eigensdk-go/contracts/bindings/OperatorStateRetriever"
+	regcoord "github.com/Layr-Labs/eigensdk-go/contracts/bindings/RegistryCoordinator"
+	servicemanager "github.com/Layr-Labs/eigensdk-go/contracts/bindings/ServiceManagerBase"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
 	"github.com/Layr-Labs/eigensdk-go/logging"
 	"github.com/Layr-Labs/eigensdk-go/signerv2"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/eigenlvr/avs/pkg/remotesigner"
 )
 
+// confirmationPollInterval is how often the head is checked while waiting for
+// a transaction's confirmations
+const confirmationPollInterval = 2 * time.Second
+
+// ErrTransactionReverted is returned when a registry transaction is mined but
+// reverted
+var ErrTransactionReverted = errors.New("registry transaction reverted")
+
 type AvsRegistryChainReader struct {
 	avsregistry.AvsRegistryReader
 	logger logging.Logger
@@ -22,7 +43,16 @@ type AvsRegistryChainReader struct {
 
 type AvsRegistryChainWriter struct {
 	avsregistry.AvsRegistryWriter
-	logger logging.Logger
+	registryCoordinator *regcoord.ContractRegistryCoordinator
+	avsDirectory        *avsdirectory.ContractAVSDirectory
+	serviceManagerAddr  common.Address
+	ethClient           eth.Client
+	signer              remotesigner.Signer
+	chainId             *big.Int
+	// confirmations is how many blocks must follow a transaction's block
+	// before it is considered final
+	confirmations uint64
+	logger        logging.Logger
 }
 
 type AvsRegistryConfig struct {
@@ -36,7 +66,7 @@ func NewAvsRegistryChainReader(
 	ethClient eth.Client,
 	logger logging.Logger,
 ) (*AvsRegistryChainReader, error) {
-	avsRegistryReader, err := avsregistry.NewAvsRegistryReader(
+	avsRegistryReader, err := avsregistry.BuildAvsRegistryChainReader(
 		registryCoordinatorAddr,
 		operatorStateRetrieverAddr,
 		ethClient,
@@ -47,84 +77,384 @@ func NewAvsRegistryChainReader(
 	}
 
 	return &AvsRegistryChainReader{
-		AvsRegistryReader: *avsRegistryReader,
+		AvsRegistryReader: avsRegistryReader,
 		logger:            logger,
 	}, nil
 }
 
+// NewAvsRegistryChainWriter returns a writer sending from the signer's
+// address on the chain with chainId. Its transactions are waited on until
+// confirmations blocks follow theirs.
 func NewAvsRegistryChainWriter(
 	registryCoordinatorAddr common.Address,
 	operatorStateRetrieverAddr common.Address,
 	ethClient eth.Client,
-	privateKey *ecdsa.PrivateKey,
+	signer remotesigner.Signer,
+	chainId *big.Int,
+	confirmations uint64,
 	logger logging.Logger,
 ) (*AvsRegistryChainWriter, error) {
-	signerV2, _, err := signerv2.SignerFromConfig(signerv2.Config{PrivateKey: privateKey}, big.NewInt(1337))
+	txWallet, err := wallet.NewPrivateKeyWallet(ethClient, signerFn(signer, chainId), signer.Address(), logger)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to create wallet: %w", err)
 	}
+	txMgr := txmgr.NewSimpleTxManager(txWallet, ethClient, logger, signer.Address())
 
-	txMgr := txmgr.NewSimpleTxManager(ethClient.(*ethclient.Client), logger, signerV2, common.Address{})
-
-	avsRegistryWriter, err := avsregistry.NewAvsRegistryWriter(
+	avsRegistryWriter, err := avsregistry.BuildAvsRegistryChainWriter(
 		registryCoordinatorAddr,
 		operatorStateRetrieverAddr,
-		ethClient,
 		logger,
+		ethClient,
 		txMgr,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	registryCoordinator, err := regcoord.NewContractRegistryCoordinator(registryCoordinatorAddr, ethClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind registry coordinator: %w", err)
+	}
+	callOpts := &bind.CallOpts{}
+	serviceManagerAddr, err := registryCoordinator.ServiceManager(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service manager address: %w", err)
+	}
+	serviceManager, err := servicemanager.NewContractServiceManagerBase(serviceManagerAddr, ethClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind service manager: %w", err)
+	}
+	avsDirectoryAddr, err := serviceManager.AvsDirectory(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AVS directory address: %w", err)
+	}
+	avsDirectory, err := avsdirectory.NewContractAVSDirectory(avsDirectoryAddr, ethClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind AVS directory: %w", err)
+	}
+
 	return &AvsRegistryChainWriter{
-		AvsRegistryWriter: *avsRegistryWriter,
-		logger:            logger,
+		AvsRegistryWriter:   avsRegistryWriter,
+		registryCoordinator: registryCoordinator,
+		avsDirectory:        avsDirectory,
+		serviceManagerAddr:  serviceManagerAddr,
+		ethClient:           ethClient,
+		signer:              signer,
+		chainId:             chainId,
+		confirmations:       confirmations,
+		logger:              logger,
 	}, nil
 }
 
-// RegisterOperatorInQuorumWithAVSRegistryCoordinator registers an operator with the AVS registry
+// signerFn adapts the signer to the transaction manager, which may only send
+// from the signer's address
+func signerFn(signer remotesigner.Signer, chainId *big.Int) signerv2.SignerFn {
+	return func(ctx context.Context, address common.Address) (bind.SignerFn, error) {
+		if address != signer.Address() {
+			return nil, fmt.Errorf("signer holds the key of %s, not %s", signer.Address().Hex(), address.Hex())
+		}
+		return remotesigner.SignerFn(ctx, signer, chainId), nil
+	}
+}
+
+// GetOperatorStakeInQuorumsAtBlock returns the stake the operator held in each of its
+// quorums at the given block. A zero block number reads the current stake instead.
+func (r *AvsRegistryChainReader) GetOperatorStakeInQuorumsAtBlock(
+	ctx context.Context,
+	operatorId types.OperatorId,
+	blockNumber uint32,
+) (map[types.QuorumNum]*big.Int, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	if blockNumber == 0 {
+		return r.GetOperatorStakeInQuorumsOfOperatorAtCurrentBlock(opts, operatorId)
+	}
+
+	quorums, operatorStakes, err := r.GetOperatorsStakeInQuorumsOfOperatorAtBlock(opts, operatorId, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	stakes := make(map[types.QuorumNum]*big.Int)
+	for i, quorum := range quorums {
+		for _, operator := range operatorStakes[i] {
+			if types.OperatorId(operator.OperatorId) == operatorId {
+				stakes[quorum] = operator.Stake
+			}
+		}
+	}
+
+	return stakes, nil
+}
+
+// IsOperatorIdRegisteredAtBlock reports whether the operator was registered
+// with the registry coordinator at the given block. A zero block number reads
+// the current registration instead.
+func (r *AvsRegistryChainReader) IsOperatorIdRegisteredAtBlock(
+	ctx context.Context,
+	operatorId types.OperatorId,
+	blockNumber uint32,
+) (bool, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	if blockNumber != 0 {
+		opts.BlockNumber = new(big.Int).SetUint64(uint64(blockNumber))
+	}
+
+	// Operators that never registered have no address for their id
+	operatorAddr, err := r.GetOperatorFromId(opts, operatorId)
+	if err != nil {
+		return false, err
+	}
+	if operatorAddr == (common.Address{}) {
+		return false, nil
+	}
+	return r.IsOperatorRegistered(opts, operatorAddr)
+}
+
+// RegisterOperatorInQuorumWithAVSRegistryCoordinator registers the signer's
+// operator in the quorums with its BLS key and socket, and returns the
+// transaction hash once it has the configured confirmations. The operator's
+// signature over the AVSDirectory registration digest is made by the signer,
+// so remote signers that can sign digests work too.
 func (w *AvsRegistryChainWriter) RegisterOperatorInQuorumWithAVSRegistryCoordinator(
 	ctx context.Context,
-	operatorEcdsaPrivateKey *ecdsa.PrivateKey,
+	operatorSigner remotesigner.Signer,
 	operatorToAvsRegistrationSigSalt [32]byte,
 	operatorToAvsRegistrationSigExpiry *big.Int,
-	blsKeyPair *avsregistry.BlsKeyPair,
+	blsKeyPair *bls.KeyPair,
 	quorumNumbers []byte,
-) error {
-	w.logger.Info("Registering operator with AVS registry coordinator")
-	
-	// This would call the actual registration function from eigensdk-go
-	// For now, we'll just log the operation
-	w.logger.Info("Operator registration completed",
+	socket string,
+) (common.Hash, error) {
+	operatorAddr := operatorSigner.Address()
+	w.logger.Info("Registering operator with AVS registry coordinator",
+		"operator", operatorAddr.Hex(),
 		"quorumNumbers", quorumNumbers,
-		"blsPubkeyG1", blsKeyPair.PubkeyG1.String(),
-		"blsPubkeyG2", blsKeyPair.PubkeyG2.String(),
+		"socket", socket,
+	)
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	// The BLS key proves possession by signing the coordinator's message
+	// for the operator address
+	pubkeyMessage, err := w.registryCoordinator.PubkeyRegistrationMessageHash(callOpts, operatorAddr)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get pubkey registration message: %w", err)
+	}
+	pubkeySignature := blsKeyPair.SignHashedToCurveMessage(chainioutils.ConvertBn254GethToGnark(pubkeyMessage))
+	pubkeyParams := regcoord.IBLSApkRegistryPubkeyRegistrationParams{
+		PubkeyRegistrationSignature: chainioutils.ConvertToBN254G1Point(pubkeySignature.G1Point),
+		PubkeyG1:                    chainioutils.ConvertToBN254G1Point(blsKeyPair.GetPubKeyG1()),
+		PubkeyG2:                    chainioutils.ConvertToBN254G2Point(blsKeyPair.GetPubKeyG2()),
+	}
+
+	// The ECDSA key authorizes the AVSDirectory to register the operator
+	// with the service manager
+	digest, err := w.avsDirectory.CalculateOperatorAVSRegistrationDigestHash(
+		callOpts,
+		operatorAddr,
+		w.serviceManagerAddr,
+		operatorToAvsRegistrationSigSalt,
+		operatorToAvsRegistrationSigExpiry,
+	)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get operator registration digest: %w", err)
+	}
+	operatorSignature, err := operatorSigner.SignHash(ctx, digest)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to sign operator registration digest: %w", err)
+	}
+	// Solidity's ecrecover takes V as 27 or 28
+	operatorSignature[64] += 27
+
+	tx, err := w.registryCoordinator.RegisterOperator(
+		w.transactOpts(ctx),
+		quorumNumbers,
+		socket,
+		pubkeyParams,
+		regcoord.ISignatureUtilsSignatureWithSaltAndExpiry{
+			Signature: operatorSignature,
+			Salt:      operatorToAvsRegistrationSigSalt,
+			Expiry:    operatorToAvsRegistrationSigExpiry,
+		},
 	)
-	
-	return nil
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to send registration transaction: %w", err)
+	}
+	if err := w.waitConfirmed(ctx, tx); err != nil {
+		return tx.Hash(), err
+	}
+
+	w.logger.Info("Registered operator with AVS registry coordinator",
+		"txHash", tx.Hash().Hex(),
+		"operator", operatorAddr.Hex(),
+		"quorumNumbers", quorumNumbers,
+	)
+	return tx.Hash(), nil
 }
 
-// DeregisterOperator deregisters an operator from the AVS
+// DeregisterOperator deregisters the writer's operator from the quorums
 func (w *AvsRegistryChainWriter) DeregisterOperator(
 	ctx context.Context,
 	quorumNumbers []byte,
-) error {
+) (common.Hash, error) {
 	w.logger.Info("Deregistering operator from AVS",
 		"quorumNumbers", quorumNumbers,
 	)
-	
-	return nil
+
+	tx, err := w.registryCoordinator.DeregisterOperator(w.transactOpts(ctx), quorumNumbers)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to send deregistration transaction: %w", err)
+	}
+	if err := w.waitConfirmed(ctx, tx); err != nil {
+		return tx.Hash(), err
+	}
+
+	w.logger.Info("Deregistered operator from AVS", "txHash", tx.Hash().Hex())
+	return tx.Hash(), nil
 }
 
-// UpdateOperatorSocket updates the operator's socket address
+// UpdateOperatorSocket updates the writer's operator's socket address
 func (w *AvsRegistryChainWriter) UpdateOperatorSocket(
-	ctx context.Context, 
+	ctx context.Context,
 	socket string,
-) error {
+) (common.Hash, error) {
 	w.logger.Info("Updating operator socket",
 		"socket", socket,
 	)
-	
-	return nil
-}
\ No newline at end of file
+
+	tx, err := w.registryCoordinator.UpdateSocket(w.transactOpts(ctx), socket)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to send socket update transaction: %w", err)
+	}
+	if err := w.waitConfirmed(ctx, tx); err != nil {
+		return tx.Hash(), err
+	}
+
+	w.logger.Info("Updated operator socket", "txHash", tx.Hash().Hex())
+	return tx.Hash(), nil
+}
+
+// transactOpts sends from the writer's signer
+func (w *AvsRegistryChainWriter) transactOpts(ctx context.Context) *bind.TransactOpts {
+	return remotesigner.TransactOpts(ctx, w.signer, w.chainId)
+}
+
+// waitConfirmed waits for the transaction to be mined and buried under
+// the configured number of confirmations. A transaction reorged out while
+// waiting is waited for again, as it returns to the mempool.
+func (w *AvsRegistryChainWriter) waitConfirmed(ctx context.Context, tx *gethtypes.Transaction) error {
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := bind.WaitMined(ctx, w.ethClient, tx)
+		if err != nil {
+			return fmt.Errorf("failed waiting for transaction %s: %w", tx.Hash().Hex(), err)
+		}
+		if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+			return fmt.Errorf("%w: %s", ErrTransactionReverted, tx.Hash().Hex())
+		}
+
+		target := receipt.BlockNumber.Uint64() + w.confirmations
+		for {
+			head, err := w.ethClient.BlockNumber(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to get block number: %w", err)
+			}
+			if head >= target {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+		}
+
+		current, err := w.ethClient.TransactionReceipt(ctx, tx.Hash())
+		if err == nil && current.BlockHash == receipt.BlockHash {
+			return nil
+		}
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("failed to get receipt of %s: %w", tx.Hash().Hex(), err)
+		}
+		w.logger.Warn("Transaction reorged out while waiting for confirmations", "txHash", tx.Hash().Hex())
+	}
+}
+
+// RegisteredOperator is an operator registered in at least one quorum, with its
+// current stake in each of them
+type RegisteredOperator struct {
+	OperatorId     types.OperatorId
+	Address        common.Address
+	StakePerQuorum map[types.QuorumNum]*big.Int
+}
+
+// GetOperatorSetAtCurrentBlock returns every operator registered in any quorum
+// at the current block
+func (r *AvsRegistryChainReader) GetOperatorSetAtCurrentBlock(ctx context.Context) ([]RegisteredOperator, error) {
+	opts := &bind.CallOpts{Context: ctx}
+
+	quorumCount, err := r.GetQuorumCount(opts)
+	if err != nil {
+		return nil, err
+	}
+	if quorumCount == 0 {
+		return nil, nil
+	}
+
+	quorumNumbers := make(types.QuorumNums, quorumCount)
+	for i := range quorumNumbers {
+		quorumNumbers[i] = types.QuorumNum(i)
+	}
+
+	operatorStakes, err := r.GetOperatorsStakeInQuorumsAtCurrentBlock(opts, quorumNumbers)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectOperators(quorumNumbers, operatorStakes), nil
+}
+
+// GetOperatorSetAtBlock returns every operator registered in any of the
+// quorums at the given block, with its stake in each of them, as read from
+// the OperatorStateRetriever
+func (r *AvsRegistryChainReader) GetOperatorSetAtBlock(
+	ctx context.Context,
+	quorumNumbers types.QuorumNums,
+	blockNumber uint32,
+) ([]RegisteredOperator, error) {
+	if len(quorumNumbers) == 0 {
+		return nil, nil
+	}
+
+	operatorStakes, err := r.GetOperatorsStakeInQuorumsAtBlock(&bind.CallOpts{Context: ctx}, quorumNumbers, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectOperators(quorumNumbers, operatorStakes), nil
+}
+
+// collectOperators merges the per-quorum operator lists of the
+// OperatorStateRetriever into one entry per operator
+func collectOperators(quorumNumbers types.QuorumNums, operatorStakes [][]opstateretriever.OperatorStateRetrieverOperator) []RegisteredOperator {
+	var operators []RegisteredOperator
+	indices := make(map[types.OperatorId]int)
+	for i, quorum := range quorumNumbers {
+		for _, operator := range operatorStakes[i] {
+			operatorId := types.OperatorId(operator.OperatorId)
+			index, seen := indices[operatorId]
+			if !seen {
+				index = len(operators)
+				indices[operatorId] = index
+				operators = append(operators, RegisteredOperator{
+					OperatorId:     operatorId,
+					Address:        operator.Operator,
+					StakePerQuorum: make(map[types.QuorumNum]*big.Int),
+				})
+			}
+			operators[index].StakePerQuorum[quorum] = operator.Stake
+		}
+	}
+
+	return operators
+}
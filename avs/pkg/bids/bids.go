@@ -0,0 +1,104 @@
+// Package bids defines the bids searchers submit to a pool's LVR auction for
+// a block. Bids are signed by the bidder's key, so the aggregator and
+// operators can check who stands behind a bid without trusting whoever relayed
+// it.
+package bids
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+)
+
+// domain separates bid signatures from any other signature made with the
+// bidder's key
+const domain = "eigenlvr auction bid"
+
+var (
+	// ErrInvalidBid is returned for bids missing a pool, bidder or positive amount
+	ErrInvalidBid = errors.New("invalid bid")
+	// ErrInvalidSignature is returned when no signer can be recovered from a bid
+	ErrInvalidSignature = errors.New("invalid bid signature")
+	// ErrWrongSigner is returned when a bid wasn't signed by its bidder
+	ErrWrongSigner = errors.New("bid not signed by its bidder")
+)
+
+// Bid is what the bidder offers for the right to capture the pool's LVR in
+// the block
+type Bid struct {
+	PoolId      common.Hash    `json:"poolId"`
+	BlockNumber uint32         `json:"blockNumber"`
+	Bidder      common.Address `json:"bidder"`
+	// Amount is in wei, paid from the bidder's escrow deposit if it wins
+	Amount *big.Int `json:"amount"`
+}
+
+// SignedBid is a Bid with the bidder's signature over its digest
+type SignedBid struct {
+	Bid
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// Validate checks the bid's fields, not its signature
+func (b Bid) Validate() error {
+	switch {
+	case b.PoolId == (common.Hash{}):
+		return fmt.Errorf("%w: missing pool id", ErrInvalidBid)
+	case b.Bidder == (common.Address{}):
+		return fmt.Errorf("%w: missing bidder", ErrInvalidBid)
+	case b.Amount == nil || b.Amount.Sign() <= 0:
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidBid)
+	case b.Amount.BitLen() > 256:
+		return fmt.Errorf("%w: amount does not fit in uint256", ErrInvalidBid)
+	}
+	return nil
+}
+
+// Digest is the hash the bidder signs
+func (b Bid) Digest() common.Hash {
+	buf := make([]byte, 0, len(domain)+common.HashLength+4+common.AddressLength+32)
+	buf = append(buf, domain...)
+	buf = append(buf, b.PoolId[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, b.BlockNumber)
+	buf = append(buf, b.Bidder[:]...)
+	buf = append(buf, math.U256Bytes(new(big.Int).Set(b.Amount))...)
+	return crypto.Keccak256Hash(buf)
+}
+
+// Sign signs the bid with the bidder's key, which must be the bid's Bidder
+func Sign(ctx context.Context, b Bid, signer remotesigner.Signer) (SignedBid, error) {
+	if signer.Address() != b.Bidder {
+		return SignedBid{}, fmt.Errorf("%w: signer %s, bidder %s", ErrWrongSigner, signer.Address().Hex(), b.Bidder.Hex())
+	}
+	signature, err := signer.SignHash(ctx, b.Digest())
+	if err != nil {
+		return SignedBid{}, fmt.Errorf("failed to sign bid: %w", err)
+	}
+	return SignedBid{Bid: b, Signature: signature}, nil
+}
+
+// Verify checks the bid's fields and that its signature recovers to the bidder
+func (s SignedBid) Verify() error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	digest := s.Digest()
+	publicKey, err := crypto.SigToPub(digest[:], s.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if signer := crypto.PubkeyToAddress(*publicKey); signer != s.Bidder {
+		return fmt.Errorf("%w: recovered %s, bidder %s", ErrWrongSigner, signer.Hex(), s.Bidder.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+// Package bindings holds abigen bindings of the EigenLVR contracts, one
+// package per contract. They are generated from the Foundry artifacts of the
+// contracts project, so regenerating them after a contract change is
+//
+//	go generate ./pkg/bindings
+//
+// which builds the contracts with forge when their artifacts are missing. The
+// ABIs the bindings were generated from are kept under abi/ so changes to
+// them show up in review.
+package bindings
+
+//go:generate go run ./internal/genbindings -contracts ../../../contracts -name EigenLVRAVSServiceManager -pkg eigenlvrservicemanager
+//go:generate go run ./internal/genbindings -contracts ../../../contracts -name EigenLVRHook -pkg eigenlvrhook
+//go:generate go run ./internal/genbindings -contracts ../../../contracts -name ChainlinkPriceOracle -pkg chainlinkpriceoracle
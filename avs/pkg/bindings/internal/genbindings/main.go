@@ -0,0 +1,115 @@
+// Command genbindings extracts a contract's ABI from its Foundry artifact and
+// runs abigen on it. It is run by the go:generate directives of package
+// bindings, from that package's directory.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	contractsDir := flag.String("contracts", "", "Foundry project holding the contract")
+	name := flag.String("name", "", "Contract name, e.g. EigenLVRAVSServiceManager")
+	pkg := flag.String("pkg", "", "Go package of the generated binding")
+	outDir := flag.String("out", ".", "Directory the abi/ and package directories are written to")
+	abigen := flag.String("abigen", "", "abigen command (defaults to abigen on PATH, else go run at the go-ethereum version in go.mod)")
+	flag.Parse()
+
+	if *contractsDir == "" || *name == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	abi, err := readAbi(*contractsDir, *name)
+	if err != nil {
+		log.Fatalf("%s: %v", *name, err)
+	}
+
+	abiPath := filepath.Join(*outDir, "abi", *name+".abi")
+	if err := os.MkdirAll(filepath.Dir(abiPath), 0o755); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(abiPath, abi, 0o644); err != nil {
+		log.Fatal(err)
+	}
+
+	bindingPath := filepath.Join(*outDir, *pkg, "binding.go")
+	if err := os.MkdirAll(filepath.Dir(bindingPath), 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	command, err := abigenCommand(*abigen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	command = append(command, "--abi", abiPath, "--pkg", *pkg, "--type", *name, "--out", bindingPath)
+	if err := run(".", command...); err != nil {
+		log.Fatalf("%s: abigen failed: %v", *name, err)
+	}
+	fmt.Printf("generated %s from %s\n", bindingPath, abiPath)
+}
+
+// readAbi returns the indented ABI from out/<name>.sol/<name>.json, building
+// the project first if the artifact is missing
+func readAbi(contractsDir string, name string) ([]byte, error) {
+	artifactPath := filepath.Join(contractsDir, "out", name+".sol", name+".json")
+	if _, err := os.Stat(artifactPath); os.IsNotExist(err) {
+		if err := run(contractsDir, "forge", "build"); err != nil {
+			return nil, fmt.Errorf("no artifact at %s and forge build failed: %w", artifactPath, err)
+		}
+	}
+
+	data, err := os.ReadFile(artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	var artifact struct {
+		Abi json.RawMessage `json:"abi"`
+	}
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", artifactPath, err)
+	}
+	if len(artifact.Abi) == 0 {
+		return nil, fmt.Errorf("%s has no abi", artifactPath)
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, artifact.Abi, "", "  "); err != nil {
+		return nil, err
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes(), nil
+}
+
+// abigenCommand pins abigen to the go-ethereum version the module builds
+// against unless a command is given or abigen is on PATH
+func abigenCommand(abigen string) ([]string, error) {
+	if abigen != "" {
+		return strings.Fields(abigen), nil
+	}
+	if path, err := exec.LookPath("abigen"); err == nil {
+		return []string{path}, nil
+	}
+
+	version, err := exec.Command("go", "list", "-m", "-f", "{{.Version}}", "github.com/ethereum/go-ethereum").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the go-ethereum version: %w", err)
+	}
+	return []string{"go", "run", "github.com/ethereum/go-ethereum/cmd/abigen@" + strings.TrimSpace(string(version))}, nil
+}
+
+func run(dir string, command ...string) error {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,114 @@
+// Package blobs packs arbitrary data into EIP-4844 blobs and back. A blob is
+// 4096 field elements of 32 bytes, each of which must stay below the BLS12-381
+// modulus, so only the low 31 bytes of every element carry data and the top
+// byte is always zero. The data is prefixed with its length as a big-endian
+// uint32 so trailing zero padding can be told apart from the data.
+package blobs
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	fieldElements      = 4096
+	fieldElementLength = 32
+	// usableBytes of every field element carry data
+	usableBytes = fieldElementLength - 1
+	// BlobCapacity is how many bytes of data fit in one blob
+	BlobCapacity = fieldElements * usableBytes
+	// MaxBlobsPerTx is the most blobs one transaction can carry
+	MaxBlobsPerTx = params.MaxBlobGasPerBlock / params.BlobTxBlobGasPerBlob
+
+	lengthPrefix = 4
+)
+
+var (
+	// ErrTooLarge is returned when data doesn't fit in MaxBlobsPerTx blobs
+	ErrTooLarge = errors.New("data does not fit in one transaction's blobs")
+	// ErrMalformed is returned when blobs don't hold encoded data
+	ErrMalformed = errors.New("malformed blob data")
+)
+
+// Encode packs data into as few blobs as it fits in
+func Encode(data []byte) ([]kzg4844.Blob, error) {
+	stream := make([]byte, lengthPrefix+len(data))
+	binary.BigEndian.PutUint32(stream, uint32(len(data)))
+	copy(stream[lengthPrefix:], data)
+
+	count := (len(stream) + BlobCapacity - 1) / BlobCapacity
+	if count > MaxBlobsPerTx {
+		return nil, fmt.Errorf("%w: %d bytes need %d blobs", ErrTooLarge, len(data), count)
+	}
+
+	blobs := make([]kzg4844.Blob, count)
+	for i := range blobs {
+		chunk := stream[i*BlobCapacity : min((i+1)*BlobCapacity, len(stream))]
+		for element := 0; element*usableBytes < len(chunk); element++ {
+			start := element * usableBytes
+			copy(blobs[i][element*fieldElementLength+1:(element+1)*fieldElementLength], chunk[start:min(start+usableBytes, len(chunk))])
+		}
+	}
+	return blobs, nil
+}
+
+// Decode unpacks data packed by Encode
+func Decode(blobs []kzg4844.Blob) ([]byte, error) {
+	stream := make([]byte, 0, len(blobs)*BlobCapacity)
+	for i := range blobs {
+		for element := 0; element < fieldElements; element++ {
+			offset := element * fieldElementLength
+			if blobs[i][offset] != 0 {
+				return nil, fmt.Errorf("%w: field element %d of blob %d has its top byte set", ErrMalformed, element, i)
+			}
+			stream = append(stream, blobs[i][offset+1:offset+fieldElementLength]...)
+		}
+	}
+	if len(stream) < lengthPrefix {
+		return nil, fmt.Errorf("%w: no length prefix", ErrMalformed)
+	}
+	length := binary.BigEndian.Uint32(stream)
+	if uint64(length) > uint64(len(stream)-lengthPrefix) {
+		return nil, fmt.Errorf("%w: length %d exceeds the blobs", ErrMalformed, length)
+	}
+	return stream[lengthPrefix : lengthPrefix+int(length)], nil
+}
+
+// NewSidecar computes the KZG commitment and proof of every blob
+func NewSidecar(blobs []kzg4844.Blob) (*types.BlobTxSidecar, error) {
+	sidecar := &types.BlobTxSidecar{
+		Blobs:       blobs,
+		Commitments: make([]kzg4844.Commitment, len(blobs)),
+		Proofs:      make([]kzg4844.Proof, len(blobs)),
+	}
+	for i := range blobs {
+		commitment, err := kzg4844.BlobToCommitment(&blobs[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to commit to blob %d: %w", i, err)
+		}
+		proof, err := kzg4844.ComputeBlobProof(&blobs[i], commitment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prove blob %d: %w", i, err)
+		}
+		sidecar.Commitments[i] = commitment
+		sidecar.Proofs[i] = proof
+	}
+	return sidecar, nil
+}
+
+// VersionedHashes returns the hashes a blob transaction commits to
+func VersionedHashes(sidecar *types.BlobTxSidecar) []common.Hash {
+	hashes := make([]common.Hash, len(sidecar.Commitments))
+	hasher := sha256.New()
+	for i := range sidecar.Commitments {
+		hashes[i] = kzg4844.CalcBlobHashV1(hasher, &sidecar.Commitments[i])
+	}
+	return hashes
+}
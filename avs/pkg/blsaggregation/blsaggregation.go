@@ -0,0 +1,260 @@
+// Package blsaggregation runs task aggregation through eigensdk's
+// BlsAggregationService, which verifies each operator's signature against its
+// registered key, tracks signed stake per quorum at the task's reference block
+// and expires tasks that never reach their thresholds.
+//
+// The service only reports the aggregate and its non-signers. Service adds
+// what the aggregator needs on top: idempotent task initialization, the
+// signers behind each aggregate and the checkSignatures argument ready to
+// submit.
+package blsaggregation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	sdkavsregistry "github.com/Layr-Labs/eigensdk-go/chainio/clients/avsregistry"
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	avsregistryservice "github.com/Layr-Labs/eigensdk-go/services/avsregistry"
+	blsagg "github.com/Layr-Labs/eigensdk-go/services/bls_aggregation"
+	"github.com/Layr-Labs/eigensdk-go/services/operatorsinfo"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/sigchecker"
+)
+
+var (
+	// ErrIncorrectSignature is returned when a signature doesn't verify against
+	// the operator's registered BLS key
+	ErrIncorrectSignature = blsagg.IncorrectSignatureError
+
+	// ErrTaskClosed is returned for signatures on a task that has already been
+	// aggregated or has expired
+	ErrTaskClosed = errors.New("task aggregation is closed")
+)
+
+// Config locates the registry contracts the service reads operator state from
+type Config struct {
+	RegistryCoordinatorAddress    common.Address
+	OperatorStateRetrieverAddress common.Address
+	// EthWsClient follows pubkey registrations, which needs a subscription
+	EthClient   eth.Client
+	EthWsClient eth.Client
+	// HashFunction returns the digest operators sign over a task response
+	HashFunction types.TaskResponseHashFunction
+	// TaskExpiry is how long a task may collect signatures before it expires
+	TaskExpiry time.Duration
+}
+
+// Task is what a task is aggregated against
+type Task struct {
+	Index                      types.TaskIndex
+	ReferenceBlock             uint32
+	QuorumNumbers              types.QuorumNums
+	QuorumThresholdPercentages types.QuorumThresholdPercentages
+}
+
+// Result is the outcome of a task. When Err is set the task expired or
+// failed and the other fields are unset.
+type Result struct {
+	TaskIndex    types.TaskIndex
+	Err          error
+	TaskResponse types.TaskResponse
+	Digest       common.Hash
+	// Signers are the operators of the task's quorums whose signatures are in
+	// the aggregate, sorted
+	Signers                     []types.OperatorId
+	NonSignerStakesAndSignature sigchecker.NonSignerStakesAndSignature
+}
+
+// Service wraps a BlsAggregationService
+type Service struct {
+	aggregation blsagg.BlsAggregationService
+	registry    avsregistryservice.AvsRegistryService
+	expiry      time.Duration
+	logger      logging.Logger
+
+	mu    sync.Mutex
+	tasks map[types.TaskIndex]*task
+}
+
+type task struct {
+	Task
+	closed bool
+}
+
+// New builds the registry services behind the aggregation service. Pubkeys
+// registered before now are backfilled before it returns.
+func New(ctx context.Context, config Config, logger logging.Logger) (*Service, error) {
+	if config.TaskExpiry <= 0 {
+		return nil, fmt.Errorf("task expiry must be positive, got %s", config.TaskExpiry)
+	}
+
+	reader, err := sdkavsregistry.BuildAvsRegistryChainReader(
+		config.RegistryCoordinatorAddress,
+		config.OperatorStateRetrieverAddress,
+		config.EthClient,
+		logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avs registry reader: %w", err)
+	}
+
+	subscriber, err := sdkavsregistry.BuildAvsRegistryChainSubscriber(
+		config.RegistryCoordinatorAddress,
+		config.EthWsClient,
+		logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avs registry subscriber: %w", err)
+	}
+
+	operatorsInfo := operatorsinfo.NewOperatorsInfoServiceInMemory(ctx, subscriber, reader, nil, logger)
+	registry := avsregistryservice.NewAvsRegistryServiceChainCaller(reader, operatorsInfo, logger)
+
+	return &Service{
+		aggregation: blsagg.NewBlsAggregatorService(registry, config.HashFunction, logger),
+		registry:    registry,
+		expiry:      config.TaskExpiry,
+		logger:      logger,
+		tasks:       make(map[types.TaskIndex]*task),
+	}, nil
+}
+
+// Initialized reports whether the task has been initialized
+func (s *Service) Initialized(taskIndex types.TaskIndex) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.tasks[taskIndex]
+	return ok
+}
+
+// Initialize starts aggregating a task. Initializing a task again is a no-op,
+// so the first response for a task can initialize it without coordination.
+func (s *Service) Initialize(t Task) error {
+	if len(t.QuorumThresholdPercentages) != len(t.QuorumNumbers) {
+		return fmt.Errorf("got %d thresholds for %d quorums", len(t.QuorumThresholdPercentages), len(t.QuorumNumbers))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tasks[t.Index]; ok {
+		return nil
+	}
+	if err := s.aggregation.InitializeNewTask(t.Index, t.ReferenceBlock, t.QuorumNumbers, t.QuorumThresholdPercentages, s.expiry); err != nil {
+		return err
+	}
+	s.tasks[t.Index] = &task{Task: t}
+	return nil
+}
+
+// ProcessSignature verifies an operator's signature over a task response and
+// adds it to the task's aggregate for that response
+func (s *Service) ProcessSignature(
+	ctx context.Context,
+	taskIndex types.TaskIndex,
+	taskResponse types.TaskResponse,
+	signature *bls.Signature,
+	operatorId types.OperatorId,
+) error {
+	s.mu.Lock()
+	t, ok := s.tasks[taskIndex]
+	closed := ok && t.closed
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("task %d is not initialized", taskIndex)
+	}
+	if closed {
+		return ErrTaskClosed
+	}
+	return s.aggregation.ProcessNewSignature(ctx, taskIndex, taskResponse, signature, operatorId)
+}
+
+// Run hands every finished task to onResult until ctx is done
+func (s *Service) Run(ctx context.Context, onResult func(Result)) {
+	responses := s.aggregation.GetResponseChannel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case response := <-responses:
+			onResult(s.result(ctx, response))
+		}
+	}
+}
+
+// Forget drops a closed task's bookkeeping once the caller no longer needs it
+func (s *Service) Forget(taskIndex types.TaskIndex) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.tasks[taskIndex]; ok && t.closed {
+		delete(s.tasks, taskIndex)
+	}
+}
+
+func (s *Service) result(ctx context.Context, response blsagg.BlsAggregationServiceResponse) Result {
+	s.mu.Lock()
+	t, ok := s.tasks[response.TaskIndex]
+	if ok {
+		t.closed = true
+	}
+	s.mu.Unlock()
+
+	result := Result{TaskIndex: response.TaskIndex, Err: response.Err}
+	if response.Err != nil {
+		return result
+	}
+	if !ok {
+		result.Err = fmt.Errorf("task %d was aggregated without being initialized", response.TaskIndex)
+		return result
+	}
+
+	nonSigners := make(map[types.OperatorId]*bls.G1Point, len(response.NonSignersPubkeysG1))
+	for _, pubkey := range response.NonSignersPubkeysG1 {
+		nonSigners[types.OperatorIdFromG1Pubkey(pubkey)] = pubkey
+	}
+
+	// The service doesn't say who signed, so the signers are the quorums'
+	// operators at the reference block minus the non-signers it reports
+	operators, err := s.registry.GetOperatorsAvsStateAtBlock(ctx, t.QuorumNumbers, t.ReferenceBlock)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to get operators at reference block: %w", err)
+		return result
+	}
+	signers := make(map[types.OperatorId]*bls.G1Point, len(operators))
+	for operatorId, operator := range operators {
+		if _, nonSigner := nonSigners[operatorId]; !nonSigner {
+			signers[operatorId] = operator.OperatorInfo.Pubkeys.G1Pubkey
+		}
+	}
+
+	result.TaskResponse = response.TaskResponse
+	result.Digest = common.Hash(response.TaskResponseDigest)
+	result.Signers = sigchecker.SortedOperatorIds(signers)
+	result.NonSignerStakesAndSignature = sigchecker.Assemble(
+		sigchecker.Signature{
+			QuorumNumbers: t.QuorumNumbers,
+			QuorumApks:    response.QuorumApksG1,
+			NonSigners:    nonSigners,
+			SignersApkG2:  response.SignersApkG2,
+			Sigma:         response.SignersAggSigG1,
+		},
+		sigchecker.CheckSignaturesIndices{
+			NonSignerQuorumBitmapIndices: response.NonSignerQuorumBitmapIndices,
+			QuorumApkIndices:             response.QuorumApkIndices,
+			TotalStakeIndices:            response.TotalStakeIndices,
+			NonSignerStakeIndices:        response.NonSignerStakeIndices,
+		},
+	)
+	return result
+}
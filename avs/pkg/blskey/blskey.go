@@ -0,0 +1,76 @@
+// Package blskey reads and writes BLS keys in eigensdk's keystore format: the
+// encrypted private key alongside the plaintext G1 public key.
+package blskey
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// keystoreJSON mirrors the keystore eigensdk writes and reads
+type keystoreJSON struct {
+	PubKey string              `json:"pubKey"`
+	Crypto keystore.CryptoJSON `json:"crypto"`
+}
+
+// Load decrypts the keystore at path with password
+func Load(path, password string) (*bls.KeyPair, error) {
+	if _, err := PublicKey(path); err != nil {
+		return nil, err
+	}
+	keyPair, err := bls.ReadPrivateKeyFromFile(path, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt bls keystore %s: %w", path, err)
+	}
+	return keyPair, nil
+}
+
+// PublicKey reads the G1 public key a keystore records, without decrypting it
+func PublicKey(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read key file: %w", err)
+	}
+	var stored keystoreJSON
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return "", fmt.Errorf("failed to parse bls keystore %s: %w", path, err)
+	}
+	// ECDSA keystores have the same layout, less the public key
+	if stored.PubKey == "" {
+		return "", errors.New("not a bls keystore, it has no pubKey")
+	}
+	return stored.PubKey, nil
+}
+
+// Save writes the key pair to path as a keystore encrypted with password,
+// readable only by the owner
+func Save(path string, keyPair *bls.KeyPair, password string) error {
+	secret := keyPair.PrivKey.Bytes()
+	encrypted, err := keystore.EncryptDataV3(secret[:], []byte(password), keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt key: %w", err)
+	}
+	data, err := json.Marshal(keystoreJSON{PubKey: keyPair.PubKey.String(), Crypto: encrypted})
+	if err != nil {
+		return err
+	}
+	return writeKeyFile(path, data)
+}
+
+// writeKeyFile writes key material to path, creating its directory, with
+// permissions for the owner only
+func writeKeyFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create key directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+// Package certificate defines the auction certificate the aggregator issues
+// for each resolved task. A certificate carries the outcome, how much of each
+// quorum's stake signed it and the operators' aggregate BLS signature, and is
+// countersigned by the aggregator. The hook, UIs and searchers can check one
+// without trusting the aggregator or reading the chain: the aggregate must
+// verify over the response digest recomputed from the certificate, and every
+// quorum must meet its threshold.
+//
+// Certificates travel as a fixed big-endian layout, at most a few hundred
+// bytes:
+//
+//	version            uint8
+//	taskIndex          uint32
+//	poolId             bytes32
+//	taskCreatedBlock   uint32
+//	winner             address
+//	winningBid         uint256
+//	totalBids          uint32
+//	signers            uint16
+//	quorumCount        uint8
+//	quorums            quorumCount * (number uint8, thresholdPercent uint8, signedStake uint256, totalStake uint256)
+//	apkG2              uint256[4] (x.imaginary, x.real, y.imaginary, y.real)
+//	sigma              uint256[2]
+//	aggregator         address
+//	signature          bytes65, the aggregator's signature over keccak256(domain || everything above aggregator)
+//
+// The aggregate is checked against the APK of the signers as given. Binding
+// that APK to the registered operators needs the registry, which is what the
+// service manager's checkSignatures does on submission.
+package certificate
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/digest"
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+	"github.com/eigenlvr/avs/pkg/sigchecker"
+)
+
+// Version is the layout version certificates are encoded with
+const Version = 1
+
+// domain separates certificate signatures from any other signature made with
+// the aggregator's key
+const domain = "eigenlvr auction certificate"
+
+const (
+	wordLength      = 32
+	signatureLength = crypto.SignatureLength
+	// fixedLength is every field but the quorums and the signature
+	fixedLength  = 1 + 4 + common.HashLength + 4 + common.AddressLength + wordLength + 4 + 2 + 1 + 6*wordLength
+	quorumLength = 2 + 2*wordLength
+)
+
+var (
+	// ErrMalformed is returned when bytes aren't an encoded certificate
+	ErrMalformed = errors.New("malformed certificate")
+	// ErrWrongSigner is returned when a certificate was signed by an unexpected key
+	ErrWrongSigner = errors.New("certificate signed by unexpected address")
+	// ErrQuorumNotMet is returned when a quorum's signed stake is below its threshold
+	ErrQuorumNotMet = errors.New("quorum threshold not met")
+	// ErrInvalidAggregate is returned when the aggregate signature doesn't verify
+	ErrInvalidAggregate = errors.New("aggregate signature does not verify")
+)
+
+// Quorum is one quorum's participation in the aggregate
+type Quorum struct {
+	Number           uint8    `json:"number"`
+	ThresholdPercent uint8    `json:"thresholdPercent"`
+	SignedStake      *big.Int `json:"signedStake"`
+	TotalStake       *big.Int `json:"totalStake"`
+}
+
+// Certificate is what the aggregator attests to
+type Certificate struct {
+	TaskIndex        uint32         `json:"taskIndex"`
+	PoolId           common.Hash    `json:"poolId"`
+	TaskCreatedBlock uint32         `json:"taskCreatedBlock"`
+	Winner           common.Address `json:"winner"`
+	WinningBid       *big.Int       `json:"winningBid"`
+	TotalBids        uint32         `json:"totalBids"`
+	// Signers is how many operators the aggregate covers
+	Signers uint16   `json:"signers"`
+	Quorums []Quorum `json:"quorums"`
+	// ApkG2 is the signers' aggregate public key and Sigma their aggregate
+	// signature over the response digest
+	ApkG2 sigchecker.G2Point `json:"apkG2"`
+	Sigma sigchecker.G1Point `json:"sigma"`
+}
+
+// SignedCertificate is a Certificate with the aggregator's signature
+type SignedCertificate struct {
+	Certificate
+	Aggregator common.Address `json:"aggregator"`
+	Signature  hexutil.Bytes  `json:"signature"`
+}
+
+// ResponseDigest is keccak256(abi.encode(taskResponse)) of the certified
+// outcome, the message the operators signed
+func (c Certificate) ResponseDigest() (common.Hash, error) {
+	responseDigest, err := digest.AuctionTaskResponseDigest(c.TaskIndex, c.Winner, c.WinningBid, c.TotalBids)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.Hash(responseDigest), nil
+}
+
+// Digest is the hash the aggregator signs
+func (c Certificate) Digest() (common.Hash, error) {
+	encoded, err := c.encode()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte(domain), encoded), nil
+}
+
+// Sign signs the certificate with the aggregator's key
+func Sign(ctx context.Context, c Certificate, signer remotesigner.Signer) (SignedCertificate, error) {
+	digest, err := c.Digest()
+	if err != nil {
+		return SignedCertificate{}, err
+	}
+	signature, err := signer.SignHash(ctx, digest)
+	if err != nil {
+		return SignedCertificate{}, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+	return SignedCertificate{
+		Certificate: c,
+		Aggregator:  signer.Address(),
+		Signature:   signature,
+	}, nil
+}
+
+// MarshalBinary encodes the certificate in the compact layout
+func (s SignedCertificate) MarshalBinary() ([]byte, error) {
+	if len(s.Signature) != signatureLength {
+		return nil, fmt.Errorf("%w: signature is %d bytes", ErrMalformed, len(s.Signature))
+	}
+	encoded, err := s.encode()
+	if err != nil {
+		return nil, err
+	}
+	encoded = append(encoded, s.Aggregator[:]...)
+	return append(encoded, s.Signature...), nil
+}
+
+// UnmarshalBinary decodes a certificate in the compact layout
+func (s *SignedCertificate) UnmarshalBinary(data []byte) error {
+	if len(data) < fixedLength+common.AddressLength+signatureLength {
+		return fmt.Errorf("%w: %d bytes", ErrMalformed, len(data))
+	}
+	if data[0] != Version {
+		return fmt.Errorf("%w: unsupported version %d", ErrMalformed, data[0])
+	}
+
+	r := reader{data: data[1:]}
+	var c Certificate
+	c.TaskIndex = r.uint32()
+	c.PoolId = common.BytesToHash(r.next(common.HashLength))
+	c.TaskCreatedBlock = r.uint32()
+	c.Winner = common.BytesToAddress(r.next(common.AddressLength))
+	c.WinningBid = r.word()
+	c.TotalBids = r.uint32()
+	c.Signers = binary.BigEndian.Uint16(r.next(2))
+
+	quorumCount := int(r.next(1)[0])
+	if len(data) != fixedLength+quorumCount*quorumLength+common.AddressLength+signatureLength {
+		return fmt.Errorf("%w: %d bytes for %d quorums", ErrMalformed, len(data), quorumCount)
+	}
+	c.Quorums = make([]Quorum, quorumCount)
+	for i := range c.Quorums {
+		c.Quorums[i] = Quorum{
+			Number:           r.next(1)[0],
+			ThresholdPercent: r.next(1)[0],
+			SignedStake:      r.word(),
+			TotalStake:       r.word(),
+		}
+	}
+
+	c.ApkG2 = sigchecker.G2Point{
+		X: [2]*big.Int{r.word(), r.word()},
+		Y: [2]*big.Int{r.word(), r.word()},
+	}
+	c.Sigma = sigchecker.G1Point{X: r.word(), Y: r.word()}
+
+	*s = SignedCertificate{
+		Certificate: c,
+		Aggregator:  common.BytesToAddress(r.next(common.AddressLength)),
+		Signature:   append([]byte(nil), r.next(signatureLength)...),
+	}
+	return nil
+}
+
+// Verify checks the aggregator's signature, recovering to expectedAggregator
+// when that is set, that every quorum met its threshold, and that the
+// aggregate signature verifies over the response digest
+func (s SignedCertificate) Verify(expectedAggregator common.Address) error {
+	certificateDigest, err := s.Digest()
+	if err != nil {
+		return err
+	}
+	publicKey, err := crypto.SigToPub(certificateDigest[:], s.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid certificate signature: %w", err)
+	}
+	signer := crypto.PubkeyToAddress(*publicKey)
+	if signer != s.Aggregator {
+		return fmt.Errorf("%w: recovered %s, claimed %s", ErrWrongSigner, signer.Hex(), s.Aggregator.Hex())
+	}
+	if expectedAggregator != (common.Address{}) && signer != expectedAggregator {
+		return fmt.Errorf("%w: %s, expected %s", ErrWrongSigner, signer.Hex(), expectedAggregator.Hex())
+	}
+
+	for _, quorum := range s.Quorums {
+		// signed/total >= threshold/100, without division
+		signed := new(big.Int).Mul(quorum.SignedStake, big.NewInt(100))
+		required := new(big.Int).Mul(quorum.TotalStake, big.NewInt(int64(quorum.ThresholdPercent)))
+		if quorum.TotalStake.Sign() == 0 || signed.Cmp(required) < 0 {
+			return fmt.Errorf("%w: quorum %d signed %s of %s, threshold %d%%",
+				ErrQuorumNotMet, quorum.Number, quorum.SignedStake, quorum.TotalStake, quorum.ThresholdPercent)
+		}
+	}
+
+	responseDigest, err := s.ResponseDigest()
+	if err != nil {
+		return err
+	}
+	sigma := bls.Signature{G1Point: bls.NewG1Point(s.Sigma.X, s.Sigma.Y)}
+	apkG2 := bls.NewG2Point(s.ApkG2.X, s.ApkG2.Y)
+	valid, err := sigma.Verify(apkG2, responseDigest)
+	if err != nil {
+		return fmt.Errorf("failed to verify aggregate signature: %w", err)
+	}
+	if !valid {
+		return ErrInvalidAggregate
+	}
+	return nil
+}
+
+// encode lays out every field the aggregator signs
+func (c Certificate) encode() ([]byte, error) {
+	if len(c.Quorums) > 255 {
+		return nil, fmt.Errorf("%w: %d quorums", ErrMalformed, len(c.Quorums))
+	}
+	if c.WinningBid == nil || c.WinningBid.Sign() < 0 || c.WinningBid.BitLen() > 8*wordLength {
+		return nil, fmt.Errorf("%w: winning bid out of range", ErrMalformed)
+	}
+
+	buf := make([]byte, 0, fixedLength+len(c.Quorums)*quorumLength)
+	buf = append(buf, Version)
+	buf = binary.BigEndian.AppendUint32(buf, c.TaskIndex)
+	buf = append(buf, c.PoolId[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, c.TaskCreatedBlock)
+	buf = append(buf, c.Winner[:]...)
+	buf = appendWord(buf, c.WinningBid)
+	buf = binary.BigEndian.AppendUint32(buf, c.TotalBids)
+	buf = binary.BigEndian.AppendUint16(buf, c.Signers)
+	buf = append(buf, byte(len(c.Quorums)))
+	for _, quorum := range c.Quorums {
+		buf = append(buf, quorum.Number, quorum.ThresholdPercent)
+		buf = appendWord(buf, quorum.SignedStake)
+		buf = appendWord(buf, quorum.TotalStake)
+	}
+	buf = appendWord(buf, c.ApkG2.X[0])
+	buf = appendWord(buf, c.ApkG2.X[1])
+	buf = appendWord(buf, c.ApkG2.Y[0])
+	buf = appendWord(buf, c.ApkG2.Y[1])
+	buf = appendWord(buf, c.Sigma.X)
+	buf = appendWord(buf, c.Sigma.Y)
+	return buf, nil
+}
+
+// appendWord appends value as a 32-byte big-endian word, nil as zero
+func appendWord(buf []byte, value *big.Int) []byte {
+	var word [wordLength]byte
+	if value != nil {
+		value.FillBytes(word[:])
+	}
+	return append(buf, word[:]...)
+}
+
+// reader consumes a buffer whose length was checked up front
+type reader struct {
+	data []byte
+}
+
+func (r *reader) next(n int) []byte {
+	out := r.data[:n]
+	r.data = r.data[n:]
+	return out
+}
+
+func (r *reader) uint32() uint32 {
+	return binary.BigEndian.Uint32(r.next(4))
+}
+
+func (r *reader) word() *big.Int {
+	return new(big.Int).SetBytes(r.next(wordLength))
+}
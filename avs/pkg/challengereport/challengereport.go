@@ -0,0 +1,77 @@
+// Package challengereport defines the challenge a challenger raises against a
+// task's aggregated response through the aggregator's API. Reports are signed
+// by the challenger, so only the holder of an address can challenge as it.
+package challengereport
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+)
+
+// domain separates report signatures from any other signature made with the
+// challenger's key
+const domain = "eigenlvr challenge report"
+
+var (
+	// ErrWrongSigner is returned when a report was not signed by its challenger
+	ErrWrongSigner = errors.New("challenge report signed by unexpected address")
+	// ErrInvalidSignature is returned when a report's signature doesn't recover
+	ErrInvalidSignature = errors.New("invalid challenge report signature")
+)
+
+// Report is what the challenger attests to
+type Report struct {
+	TaskIndex  uint32         `json:"taskIndex"`
+	Challenger common.Address `json:"challenger"`
+	Reason     string         `json:"reason"`
+}
+
+// SignedReport is a Report with the challenger's signature over its digest
+type SignedReport struct {
+	Report
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// Digest is the hash the challenger signs
+func (r Report) Digest() common.Hash {
+	reasonHash := crypto.Keccak256Hash([]byte(r.Reason))
+	buf := make([]byte, 0, len(domain)+4+common.AddressLength+common.HashLength)
+	buf = append(buf, domain...)
+	buf = binary.BigEndian.AppendUint32(buf, r.TaskIndex)
+	buf = append(buf, r.Challenger[:]...)
+	buf = append(buf, reasonHash[:]...)
+	return crypto.Keccak256Hash(buf)
+}
+
+// Sign signs the report with the challenger's key
+func Sign(ctx context.Context, r Report, signer remotesigner.Signer) (SignedReport, error) {
+	if signer.Address() != r.Challenger {
+		return SignedReport{}, fmt.Errorf("%w: signer %s, challenger %s", ErrWrongSigner, signer.Address().Hex(), r.Challenger.Hex())
+	}
+	signature, err := signer.SignHash(ctx, r.Digest())
+	if err != nil {
+		return SignedReport{}, fmt.Errorf("failed to sign challenge report: %w", err)
+	}
+	return SignedReport{Report: r, Signature: signature}, nil
+}
+
+// Verify checks that the signature recovers to the challenger
+func (s SignedReport) Verify() error {
+	digest := s.Digest()
+	publicKey, err := crypto.SigToPub(digest[:], s.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	if signer := crypto.PubkeyToAddress(*publicKey); signer != s.Challenger {
+		return fmt.Errorf("%w: recovered %s, challenger %s", ErrWrongSigner, signer.Hex(), s.Challenger.Hex())
+	}
+	return nil
+}
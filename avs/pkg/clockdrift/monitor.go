@@ -0,0 +1,211 @@
+// Package clockdrift compares the local clock with recent block timestamps and
+// an NTP server. Auction windows are time and block sensitive, so an operator
+// with a drifting clock can sign for the wrong window.
+package clockdrift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrClockDrift is returned when the local clock is outside the allowed drift
+var ErrClockDrift = errors.New("local clock drift exceeds threshold")
+
+const (
+	SourceChain = "chain"
+	SourceNtp   = "ntp"
+)
+
+// HeaderReader reads block headers; eth clients satisfy it
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+}
+
+// Config configures drift checks
+type Config struct {
+	// Threshold is the largest tolerated offset from the reference clocks
+	Threshold time.Duration
+	// MaxBlockLag is how far behind local time the latest block may be
+	// timestamped before it counts as drift, on top of Threshold
+	MaxBlockLag time.Duration
+	// NtpServer is queried in addition to the chain when set
+	NtpServer string
+	Interval  time.Duration
+}
+
+// Status is the result of the most recent drift check
+type Status struct {
+	ChainOffset time.Duration `json:"chainOffset"`
+	NtpOffset   time.Duration `json:"ntpOffset"`
+	NtpChecked  bool          `json:"ntpChecked"`
+	Drifting    bool          `json:"drifting"`
+	Reason      string        `json:"reason,omitempty"`
+	CheckedAt   time.Time     `json:"checkedAt"`
+}
+
+// Monitor periodically measures clock drift
+type Monitor struct {
+	config  Config
+	headers HeaderReader
+	logger  logging.Logger
+
+	mu     sync.RWMutex
+	status Status
+
+	offsetGauge   *prometheus.GaugeVec
+	driftingGauge prometheus.Gauge
+}
+
+func NewMonitor(config Config, headers HeaderReader, reg prometheus.Registerer, logger logging.Logger) *Monitor {
+	monitor := &Monitor{
+		config:  config,
+		headers: headers,
+		logger:  logger.With("component", "clock-drift"),
+
+		offsetGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "clock",
+			Name:      "offset_seconds",
+			Help:      "Offset of the reference clock from the local clock; positive means the local clock is behind",
+		}, []string{"source"}),
+		driftingGauge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "clock",
+			Name:      "drifting",
+			Help:      "1 if local clock drift exceeds the configured threshold",
+		}),
+	}
+
+	reg.MustRegister(monitor.offsetGauge, monitor.driftingGauge)
+
+	return monitor
+}
+
+// Start checks drift until ctx is done
+func (m *Monitor) Start(ctx context.Context) {
+	m.logger.Info("Starting clock drift monitor",
+		"threshold", m.config.Threshold,
+		"ntpServer", m.config.NtpServer,
+		"interval", m.config.Interval,
+	)
+
+	m.checkAndLog(ctx)
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.checkAndLog(ctx)
+		}
+	}
+}
+
+func (m *Monitor) checkAndLog(ctx context.Context) {
+	status, err := m.Check(ctx)
+	if err != nil {
+		m.logger.Warn("Failed to check clock drift", "error", err)
+		return
+	}
+	if status.Drifting {
+		m.logger.Warn("Local clock is drifting",
+			"reason", status.Reason,
+			"chainOffset", status.ChainOffset,
+			"ntpOffset", status.NtpOffset,
+		)
+	}
+}
+
+// Check measures drift against the latest block and, when configured, the
+// NTP server. An unreachable NTP server is logged but doesn't fail the check.
+func (m *Monitor) Check(ctx context.Context) (Status, error) {
+	header, err := m.headers.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to get latest block header: %w", err)
+	}
+
+	now := time.Now()
+	status := Status{
+		ChainOffset: time.Unix(int64(header.Time), 0).Sub(now),
+		CheckedAt:   now,
+	}
+	m.offsetGauge.WithLabelValues(SourceChain).Set(status.ChainOffset.Seconds())
+
+	// Block timestamps have second resolution and trail local time by up to a
+	// block interval, so only a block from the future or an unexpectedly old
+	// block indicates drift
+	switch {
+	case status.ChainOffset > m.config.Threshold+time.Second:
+		status.Drifting = true
+		status.Reason = fmt.Sprintf("latest block is %s ahead of local time", status.ChainOffset)
+	case -status.ChainOffset > m.config.Threshold+m.config.MaxBlockLag:
+		status.Drifting = true
+		status.Reason = fmt.Sprintf("latest block is %s behind local time", -status.ChainOffset)
+	}
+
+	if m.config.NtpServer != "" {
+		ntpCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		offset, err := QueryNtpOffset(ntpCtx, m.config.NtpServer)
+		cancel()
+		if err != nil {
+			m.logger.Warn("Failed to query ntp server", "server", m.config.NtpServer, "error", err)
+		} else {
+			status.NtpOffset = offset
+			status.NtpChecked = true
+			m.offsetGauge.WithLabelValues(SourceNtp).Set(offset.Seconds())
+
+			// NTP is the more precise source, so it takes precedence
+			if abs(offset) > m.config.Threshold {
+				status.Drifting = true
+				status.Reason = fmt.Sprintf("ntp offset is %s", offset)
+			}
+		}
+	}
+
+	if status.Drifting {
+		m.driftingGauge.Set(1)
+	} else {
+		m.driftingGauge.Set(0)
+	}
+
+	m.mu.Lock()
+	m.status = status
+	m.mu.Unlock()
+
+	return status, nil
+}
+
+// Status returns the result of the most recent check
+func (m *Monitor) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.status
+}
+
+// Err returns an error wrapping ErrClockDrift if the last check found drift
+func (m *Monitor) Err() error {
+	status := m.Status()
+	if !status.Drifting {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrClockDrift, status.Reason)
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
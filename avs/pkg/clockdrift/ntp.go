@@ -0,0 +1,88 @@
+package clockdrift
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	ntpPacketSize = 48
+	// ntpEpochOffset is the number of seconds between 1900-01-01 and 1970-01-01
+	ntpEpochOffset = 2208988800
+)
+
+// QueryNtpOffset returns the offset of the server's clock from the local clock
+// using a single SNTP (RFC 4330) request. A positive offset means the local
+// clock is behind.
+func QueryNtpOffset(ctx context.Context, server string) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return 0, fmt.Errorf("failed to dial ntp server: %w", err)
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(5 * time.Second)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return 0, err
+	}
+
+	request := make([]byte, ntpPacketSize)
+	// LI = 0, VN = 4, Mode = 3 (client)
+	request[0] = 0x23
+
+	originTime := time.Now()
+	putNtpTime(request[40:], originTime)
+	if _, err := conn.Write(request); err != nil {
+		return 0, fmt.Errorf("failed to send ntp request: %w", err)
+	}
+
+	response := make([]byte, ntpPacketSize)
+	n, err := conn.Read(response)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read ntp response: %w", err)
+	}
+	destinationTime := time.Now()
+
+	if n < ntpPacketSize {
+		return 0, errors.New("short ntp response")
+	}
+	if mode := response[0] & 0x07; mode != 4 {
+		return 0, fmt.Errorf("unexpected ntp mode %d", mode)
+	}
+	if stratum := response[1]; stratum == 0 || stratum > 15 {
+		return 0, fmt.Errorf("ntp server is unsynchronized (stratum %d)", stratum)
+	}
+
+	receiveTime := ntpTime(response[32:])
+	transmitTime := ntpTime(response[40:])
+
+	// offset = ((T2 - T1) + (T3 - T4)) / 2
+	offset := (receiveTime.Sub(originTime) + transmitTime.Sub(destinationTime)) / 2
+	return offset, nil
+}
+
+func ntpTime(b []byte) time.Time {
+	seconds := binary.BigEndian.Uint32(b[0:4])
+	fraction := binary.BigEndian.Uint32(b[4:8])
+	nanos := (int64(fraction) * 1e9) >> 32
+	return time.Unix(int64(seconds)-ntpEpochOffset, nanos)
+}
+
+func putNtpTime(b []byte, t time.Time) {
+	seconds := uint32(t.Unix() + ntpEpochOffset)
+	fraction := uint32((int64(t.Nanosecond()) << 32) / 1e9)
+	binary.BigEndian.PutUint32(b[0:4], seconds)
+	binary.BigEndian.PutUint32(b[4:8], fraction)
+}
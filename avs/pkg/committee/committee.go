@@ -0,0 +1,145 @@
+// Package committee samples the operators that respond to a task. Rather
+// than every operator answering every task, a committee is drawn with
+// probability proportional to stake until it holds enough of each quorum's
+// stake to meet the task's threshold with margin. The draw is seeded by the
+// task hash the service manager stores, so every operator computes the same
+// committee without coordinating.
+//
+// The seeded draw is public once the task exists. With VRF selection each
+// operator instead draws a private ticket by signing the task hash with its
+// BLS key, and only reveals it, as proof of membership, with its response.
+//
+// Sampling only decides who does the work. The aggregate still has to carry
+// the threshold of each quorum's full stake, so a committee that falls short
+// delays the task rather than weakening it.
+package committee
+
+import (
+	"encoding/binary"
+	"math/big"
+	"sort"
+
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/avsregistry"
+)
+
+// domain separates committee seeds from any other use of the task hash
+const domain = "eigenlvr committee"
+
+// Params control how large a committee is drawn
+type Params struct {
+	// CoveragePercent of every quorum's stake the committee must hold
+	CoveragePercent uint32
+	// MinSize is the fewest operators drawn, stake permitting
+	MinSize int
+}
+
+// Seed derives the sampling seed of a task from its hash
+func Seed(taskHash common.Hash) common.Hash {
+	return crypto.Keccak256Hash([]byte(domain), taskHash[:])
+}
+
+// Sample draws operators without replacement, each draw choosing among the
+// remaining operators in proportion to their stake summed over the quorums.
+// It stops once the committee holds CoveragePercent of every quorum's stake
+// and has MinSize members, or when no staked operator is left. The result is
+// in draw order.
+func Sample(seed common.Hash, operators []avsregistry.RegisteredOperator, quorums types.QuorumNums, params Params) []types.OperatorId {
+	// Operators are ordered by id so the draw doesn't depend on how the
+	// operator set was read
+	candidates := make([]candidate, 0, len(operators))
+	totals := make(map[types.QuorumNum]*big.Int, len(quorums))
+	for _, quorum := range quorums {
+		totals[quorum] = new(big.Int)
+	}
+	for _, operator := range operators {
+		weight := new(big.Int)
+		for _, quorum := range quorums {
+			if stake := operator.StakePerQuorum[quorum]; stake != nil && stake.Sign() > 0 {
+				weight.Add(weight, stake)
+				totals[quorum].Add(totals[quorum], stake)
+			}
+		}
+		if weight.Sign() > 0 {
+			candidates = append(candidates, candidate{operator: operator, weight: weight})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return string(candidates[i].operator.OperatorId[:]) < string(candidates[j].operator.OperatorId[:])
+	})
+
+	remaining := new(big.Int)
+	for _, c := range candidates {
+		remaining.Add(remaining, c.weight)
+	}
+	covered := make(map[types.QuorumNum]*big.Int, len(quorums))
+	for _, quorum := range quorums {
+		covered[quorum] = new(big.Int)
+	}
+
+	var committee []types.OperatorId
+	for draw := uint64(0); len(candidates) > 0; draw++ {
+		if len(committee) >= params.MinSize && coverageMet(covered, totals, params.CoveragePercent) {
+			break
+		}
+
+		target := new(big.Int).Mod(drawValue(seed, draw), remaining)
+		picked := 0
+		for i, c := range candidates {
+			if target.Cmp(c.weight) < 0 {
+				picked = i
+				break
+			}
+			target.Sub(target, c.weight)
+		}
+
+		chosen := candidates[picked]
+		committee = append(committee, chosen.operator.OperatorId)
+		for _, quorum := range quorums {
+			if stake := chosen.operator.StakePerQuorum[quorum]; stake != nil && stake.Sign() > 0 {
+				covered[quorum].Add(covered[quorum], stake)
+			}
+		}
+		remaining.Sub(remaining, chosen.weight)
+		candidates = append(candidates[:picked], candidates[picked+1:]...)
+	}
+	return committee
+}
+
+// Contains reports whether the operator is in the committee
+func Contains(committee []types.OperatorId, operatorId types.OperatorId) bool {
+	for _, member := range committee {
+		if member == operatorId {
+			return true
+		}
+	}
+	return false
+}
+
+type candidate struct {
+	operator avsregistry.RegisteredOperator
+	weight   *big.Int
+}
+
+// drawValue is keccak256(seed || draw) as an integer
+func drawValue(seed common.Hash, draw uint64) *big.Int {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], draw)
+	return new(big.Int).SetBytes(crypto.Keccak256(seed[:], counter[:]))
+}
+
+// coverageMet reports whether covered holds percent of every quorum's total,
+// compared as covered*100 >= total*percent
+func coverageMet(covered, totals map[types.QuorumNum]*big.Int, percent uint32) bool {
+	for quorum, total := range totals {
+		lhs := new(big.Int).Mul(covered[quorum], big.NewInt(100))
+		rhs := new(big.Int).Mul(total, big.NewInt(int64(percent)))
+		if lhs.Cmp(rhs) < 0 {
+			return false
+		}
+	}
+	return true
+}
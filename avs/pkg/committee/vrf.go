@@ -0,0 +1,123 @@
+package committee
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/avsregistry"
+)
+
+// vrfDomain separates committee tickets from response signatures
+const vrfDomain = "eigenlvr committee vrf"
+
+// ErrInvalidProof is returned when a committee proof isn't the operator's
+// signature over the task's VRF message
+var ErrInvalidProof = errors.New("invalid committee proof")
+
+// VrfMessage is what an operator signs to draw its committee ticket for a task
+func VrfMessage(taskHash common.Hash) [32]byte {
+	return crypto.Keccak256Hash([]byte(vrfDomain), taskHash[:])
+}
+
+// Prove draws the operator's ticket for a task. BLS signatures are unique per
+// key and message, so the proof is a VRF keyed to the operator's BLS key: no
+// one can predict it before the task exists, and anyone holding the
+// operator's registered pubkey can check it afterwards.
+func Prove(keyPair *bls.KeyPair, taskHash common.Hash) *bls.Signature {
+	return keyPair.SignMessage(VrfMessage(taskHash))
+}
+
+// VerifyProof checks a proof against the operator's registered G2 pubkey
+func VerifyProof(pubkeyG2 *bls.G2Point, taskHash common.Hash, proof *bls.Signature) error {
+	if proof == nil || proof.G1Point == nil {
+		return fmt.Errorf("%w: missing", ErrInvalidProof)
+	}
+	valid, err := proof.Verify(pubkeyG2, VrfMessage(taskHash))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	if !valid {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// Output is the VRF output of a proof, keccak256(x || y) of the signature
+func Output(proof *bls.Signature) common.Hash {
+	x := proof.X.Bytes()
+	y := proof.Y.Bytes()
+	return crypto.Keccak256Hash(x[:], y[:])
+}
+
+// Selected reports whether an operator holding stake of totalStake is in the
+// committee, which is expectedSize operators on average. Each operator is in
+// with probability expectedSize*stake/totalStake, capped at one, by checking
+// output/2^256 < expectedSize*stake/totalStake without division. Operators
+// with a large share of stake are nearly always in, but unlike Sample the
+// committee's stake isn't bounded below, so expectedSize must leave enough
+// headroom over the quorum thresholds.
+func Selected(output common.Hash, stake *big.Int, totalStake *big.Int, expectedSize uint32) bool {
+	if stake == nil || stake.Sign() <= 0 || totalStake == nil || totalStake.Sign() <= 0 {
+		return false
+	}
+	lhs := new(big.Int).Mul(new(big.Int).SetBytes(output[:]), totalStake)
+	rhs := new(big.Int).Mul(stake, big.NewInt(int64(expectedSize)))
+	rhs.Lsh(rhs, 256)
+	return lhs.Cmp(rhs) < 0
+}
+
+// StakeOf returns the operator's stake and the total stake, each summed over
+// the quorums, for weighing its ticket
+func StakeOf(operators []avsregistry.RegisteredOperator, quorums types.QuorumNums, operatorId types.OperatorId) (*big.Int, *big.Int) {
+	stake := new(big.Int)
+	total := new(big.Int)
+	for _, operator := range operators {
+		for _, quorum := range quorums {
+			quorumStake := operator.StakePerQuorum[quorum]
+			if quorumStake == nil || quorumStake.Sign() <= 0 {
+				continue
+			}
+			total.Add(total, quorumStake)
+			if operator.OperatorId == operatorId {
+				stake.Add(stake, quorumStake)
+			}
+		}
+	}
+	return stake, total
+}
+
+// ExpectedStake returns how much of a quorum's stake a committee of
+// expectedSize holds on average, with tickets weighed over the quorums as
+// Selected does: the quorum stake of every operator times its chance of
+// being in. The contract measures aggregates against the quorum's full stake,
+// so a committee expected to hold less than the threshold mostly misses it.
+func ExpectedStake(operators []avsregistry.RegisteredOperator, quorums types.QuorumNums, quorum types.QuorumNum, expectedSize uint32) *big.Int {
+	expected := new(big.Int)
+	_, total := StakeOf(operators, quorums, types.OperatorId{})
+	if total.Sign() == 0 {
+		return expected
+	}
+	for _, operator := range operators {
+		quorumStake := operator.StakePerQuorum[quorum]
+		if quorumStake == nil || quorumStake.Sign() <= 0 {
+			continue
+		}
+		weight, _ := StakeOf([]avsregistry.RegisteredOperator{operator}, quorums, operator.OperatorId)
+
+		// quorumStake * min(1, expectedSize*weight/total)
+		share := new(big.Int).Mul(quorumStake, weight)
+		share.Mul(share, big.NewInt(int64(expectedSize)))
+		share.Quo(share, total)
+		if share.Cmp(quorumStake) > 0 {
+			share.Set(quorumStake)
+		}
+		expected.Add(expected, share)
+	}
+	return expected
+}
@@ -0,0 +1,251 @@
+// Package compression implements gzip and zstd request body compression with
+// negotiation: servers advertise the codings they accept in an
+// Accept-Encoding response header (RFC 7694) and clients compress subsequent
+// request bodies with the best coding both sides support.
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	Identity = "identity"
+	Gzip     = "gzip"
+	Zstd     = "zstd"
+
+	// Auto compresses with the best coding the server advertises
+	Auto = "auto"
+	// None never compresses request bodies
+	None = "none"
+
+	// DefaultMinSize is the smallest body worth compressing
+	DefaultMinSize = 1024
+
+	// DefaultMaxBodySize bounds request bodies in Middleware, both as sent
+	// and once decoded
+	DefaultMaxBodySize = 1 << 20
+
+	// zstdMaxWindow and zstdMaxMemory bound what a zstd stream can make the
+	// decoder allocate, whatever its frame header claims
+	zstdMaxWindow = 8 << 20
+	zstdMaxMemory = 64 << 20
+)
+
+// ErrUnsupportedEncoding is returned for a content coding this package can't handle
+var ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+
+// SupportedEncodings lists accepted request codings, most preferred first
+var SupportedEncodings = []string{Zstd, Gzip}
+
+// Compress encodes data with the given coding
+func Compress(encoding string, data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch encoding {
+	case Gzip:
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(data); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+	case Zstd:
+		writer, err := zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write(data); err != nil {
+			writer.Close()
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, encoding)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// NewReader returns a reader decoding r with the given coding
+func NewReader(encoding string, r io.Reader) (io.ReadCloser, error) {
+	switch encoding {
+	case "", Identity:
+		return io.NopCloser(r), nil
+	case Gzip:
+		return gzip.NewReader(r)
+	case Zstd:
+		// Streams are decoded synchronously, so closing the reader releases
+		// everything the decoder holds
+		decoder, err := zstd.NewReader(r,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxWindow(zstdMaxWindow),
+			zstd.WithDecoderMaxMemory(zstdMaxMemory),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return zstdReader{decoder}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, encoding)
+	}
+}
+
+// zstdReader closes its decoder along with it
+type zstdReader struct {
+	*zstd.Decoder
+}
+
+func (r zstdReader) Close() error {
+	r.Decoder.Close()
+	return nil
+}
+
+// Middleware is NewMiddleware with DefaultMaxBodySize
+func Middleware(next http.Handler) http.Handler {
+	return NewMiddleware(DefaultMaxBodySize)(next)
+}
+
+// NewMiddleware decodes compressed request bodies and advertises the
+// supported codings on every response. Compressed bodies are limited to
+// maxBodySize as sent and again once decoded, so a small body can't expand
+// into an unbounded one; larger bodies are refused with 413.
+func NewMiddleware(maxBodySize int64) func(http.Handler) http.Handler {
+	acceptEncoding := strings.Join(SupportedEncodings, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Accept-Encoding", acceptEncoding)
+
+			encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+			if encoding == "" || encoding == Identity {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := NewReader(encoding, http.MaxBytesReader(w, r.Body, maxBodySize))
+			var maxBytesErr *http.MaxBytesError
+			if errors.Is(err, ErrUnsupportedEncoding) {
+				http.Error(w, "Unsupported content encoding", http.StatusUnsupportedMediaType)
+				return
+			}
+			if errors.As(err, &maxBytesErr) {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				http.Error(w, "Invalid compressed body", http.StatusBadRequest)
+				return
+			}
+			defer body.Close()
+
+			r.Body = http.MaxBytesReader(w, io.NopCloser(body), maxBodySize)
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Negotiator tracks which coding to use for requests to a single server
+type Negotiator struct {
+	mode    string
+	minSize int
+
+	mu         sync.Mutex
+	negotiated string
+}
+
+// NewNegotiator creates a negotiator. Mode is Auto, None, or a fixed coding.
+func NewNegotiator(mode string, minSize int) (*Negotiator, error) {
+	switch mode {
+	case "":
+		mode = Auto
+	case Auto, None, Gzip, Zstd:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, mode)
+	}
+	if minSize <= 0 {
+		minSize = DefaultMinSize
+	}
+
+	return &Negotiator{mode: mode, minSize: minSize}, nil
+}
+
+// Encode returns the body to send and its Content-Encoding, or an empty
+// encoding when the body is sent uncompressed
+func (n *Negotiator) Encode(body []byte) ([]byte, string, error) {
+	if len(body) < n.minSize {
+		return body, "", nil
+	}
+
+	encoding := n.encoding()
+	if encoding == "" {
+		return body, "", nil
+	}
+
+	compressed, err := Compress(encoding, body)
+	if err != nil {
+		return nil, "", err
+	}
+	return compressed, encoding, nil
+}
+
+func (n *Negotiator) encoding() string {
+	switch n.mode {
+	case None:
+		return ""
+	case Gzip, Zstd:
+		return n.mode
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.negotiated
+}
+
+// Observe records the codings a server advertised in a response. A 415
+// response resets negotiation so the next request is sent uncompressed.
+func (n *Negotiator) Observe(resp *http.Response) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if resp.StatusCode == http.StatusUnsupportedMediaType {
+		n.negotiated = ""
+		return
+	}
+
+	advertised := resp.Header.Values("Accept-Encoding")
+	if len(advertised) == 0 {
+		return
+	}
+
+	accepted := make(map[string]bool)
+	for _, value := range advertised {
+		for _, coding := range strings.Split(value, ",") {
+			// Drop any quality parameter
+			coding = strings.TrimSpace(strings.SplitN(coding, ";", 2)[0])
+			accepted[strings.ToLower(coding)] = true
+		}
+	}
+
+	n.negotiated = ""
+	for _, coding := range SupportedEncodings {
+		if accepted[coding] {
+			n.negotiated = coding
+			return
+		}
+	}
+}
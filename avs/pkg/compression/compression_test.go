@@ -0,0 +1,101 @@
+package compression
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// readBody echoes the decoded body, answering 413 once it runs past the limit
+func readBody(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		http.Error(w, "too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Write(body)
+}
+
+func post(t *testing.T, handler http.Handler, encoding string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	compressed, err := Compress(encoding, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(compressed))
+	req.Header.Set("Content-Encoding", encoding)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddlewareDecodesBodies(t *testing.T) {
+	handler := NewMiddleware(1 << 16)(http.HandlerFunc(readBody))
+	body := bytes.Repeat([]byte("task response "), 1000)
+
+	for _, encoding := range SupportedEncodings {
+		rec := post(t, handler, encoding, body)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", encoding, rec.Code, rec.Body)
+		}
+		if !bytes.Equal(rec.Body.Bytes(), body) {
+			t.Fatalf("%s: decoded %d bytes, want the %d sent", encoding, rec.Body.Len(), len(body))
+		}
+		if got := rec.Header().Get("Accept-Encoding"); got != "zstd, gzip" {
+			t.Fatalf("%s: advertised %q", encoding, got)
+		}
+	}
+}
+
+func TestMiddlewareBoundsDecodedBodies(t *testing.T) {
+	const limit = 1 << 16
+	handler := NewMiddleware(limit)(http.HandlerFunc(readBody))
+	// Compresses to far below the limit
+	bomb := make([]byte, 64*limit)
+
+	for _, encoding := range SupportedEncodings {
+		rec := post(t, handler, encoding, bomb)
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("%s: status %d, want %d", encoding, rec.Code, http.StatusRequestEntityTooLarge)
+		}
+	}
+}
+
+func TestMiddlewareBoundsCompressedBodies(t *testing.T) {
+	const limit = 1 << 10
+	handler := NewMiddleware(limit)(http.HandlerFunc(readBody))
+	// Random bytes don't compress, so the body is over the limit as sent
+	incompressible := make([]byte, 4*limit)
+	if _, err := rand.Read(incompressible); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, encoding := range SupportedEncodings {
+		rec := post(t, handler, encoding, incompressible)
+		if rec.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("%s: status %d, want %d", encoding, rec.Code, http.StatusRequestEntityTooLarge)
+		}
+	}
+}
+
+func TestMiddlewareRefusesUnknownEncodings(t *testing.T) {
+	handler := Middleware(http.HandlerFunc(readBody))
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("body")))
+	req.Header.Set("Content-Encoding", "br")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("status %d, want %d", rec.Code, http.StatusUnsupportedMediaType)
+	}
+}
@@ -0,0 +1,318 @@
+// Package configfile loads the aggregator and operator config files. Both
+// YAML and JSON are accepted and decoded through the config structs' json
+// tags, so a key is spelled the same way in either format.
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the encoding of a config file
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// UnknownField is a key in the config file that matches no config field. It
+// is ignored, but usually means a typo or a key from another version.
+type UnknownField struct {
+	Path   string
+	Line   int
+	Column int
+}
+
+func (f UnknownField) String() string {
+	return fmt.Sprintf("%d:%d: unknown field %q", f.Line, f.Column, f.Path)
+}
+
+// Error is a config file problem with its location in the file
+type Error struct {
+	File   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %v", e.File, e.Err)
+	}
+	return fmt.Sprintf("%s:%d:%d: %v", e.File, e.Line, e.Column, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// DetectFormat picks the format from the file extension, or from the content
+// when the extension is neither: a JSON document starts with '{'
+func DetectFormat(path string, data []byte) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
+// Load decodes the config file at path into out, a pointer to a config
+// struct. The config may sit under a top-level section key, as in the shipped
+// "aggregator:" and "operator:" files, or at the top level. Keys matching no
+// field are returned rather than failing the load.
+func Load(path, section string, out interface{}) ([]UnknownField, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return Decode(path, data, section, out)
+}
+
+// Decode is Load for a config already read from path
+func Decode(path string, data []byte, section string, out interface{}) ([]UnknownField, error) {
+	// encoding/json reports JSON syntax errors more precisely than the YAML
+	// parser, which accepts JSON as well and handles everything after this
+	if DetectFormat(path, data) == FormatJSON {
+		var syntax interface{}
+		if err := json.Unmarshal(data, &syntax); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				// Offset is just past the offending byte
+				line, column := offsetPosition(data, syntaxErr.Offset-1)
+				return nil, &Error{File: path, Line: line, Column: column, Err: err}
+			}
+			return nil, &Error{File: path, Err: err}
+		}
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		// yaml.v3 errors already name the line
+		return nil, &Error{File: path, Err: err}
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+
+	d := &decoder{positions: make(map[string]*yaml.Node)}
+	root := sectionNode(document.Content[0], section, d)
+
+	value, err := d.value(root, reflect.TypeOf(out), "")
+	if err != nil {
+		return d.unknown, &Error{File: path, Err: err}
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return d.unknown, &Error{File: path, Err: err}
+	}
+	if err := json.Unmarshal(encoded, out); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			if node, ok := d.positions[typeErr.Field]; ok {
+				return d.unknown, &Error{
+					File:   path,
+					Line:   node.Line,
+					Column: node.Column,
+					Err:    fmt.Errorf("%s: cannot use %s as %s", typeErr.Field, typeErr.Value, typeErr.Type),
+				}
+			}
+		}
+		return d.unknown, &Error{File: path, Err: err}
+	}
+	return d.unknown, nil
+}
+
+// sectionNode returns the mapping under the section key when the document
+// has one, reporting any other top-level keys as unknown
+func sectionNode(root *yaml.Node, section string, d *decoder) *yaml.Node {
+	if section == "" || root.Kind != yaml.MappingNode {
+		return root
+	}
+
+	var inner *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == section && resolve(root.Content[i+1]).Kind == yaml.MappingNode {
+			inner = resolve(root.Content[i+1])
+		}
+	}
+	if inner == nil {
+		return root
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if key := root.Content[i]; key.Value != section {
+			d.unknown = append(d.unknown, UnknownField{Path: key.Value, Line: key.Line, Column: key.Column})
+		}
+	}
+	return inner
+}
+
+// decoder converts a YAML node tree into values encoding/json decodes into
+// the target type, recording where each field came from
+type decoder struct {
+	positions map[string]*yaml.Node
+	unknown   []UnknownField
+}
+
+func (d *decoder) value(node *yaml.Node, t reflect.Type, path string) (interface{}, error) {
+	node = resolve(node)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return nil, nil
+		}
+		// Keep the literal text for string fields, so unquoted values such as
+		// 0x addresses or 10m durations aren't resolved to numbers first
+		if t != nil && t.Kind() == reflect.String {
+			return node.Value, nil
+		}
+		var scalar interface{}
+		if err := node.Decode(&scalar); err != nil {
+			return nil, fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		return scalar, nil
+
+	case yaml.SequenceNode:
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		items := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := d.value(item, elem, path)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+
+	case yaml.MappingNode:
+		fields := map[string]reflect.Type{}
+		if t != nil && t.Kind() == reflect.Struct {
+			fields = jsonFields(t)
+		}
+		mapping := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, valueNode := node.Content[i], node.Content[i+1]
+
+			name, fieldType := key.Value, reflect.Type(nil)
+			switch {
+			case t != nil && t.Kind() == reflect.Map:
+				fieldType = t.Elem()
+			case t != nil && t.Kind() == reflect.Struct:
+				var ok bool
+				name, fieldType, ok = lookupField(fields, key.Value)
+				if !ok {
+					d.unknown = append(d.unknown, UnknownField{Path: joinPath(path, key.Value), Line: key.Line, Column: key.Column})
+					continue
+				}
+			}
+
+			fieldPath := joinPath(path, name)
+			if _, seen := d.positions[fieldPath]; !seen {
+				d.positions[fieldPath] = valueNode
+			}
+			value, err := d.value(valueNode, fieldType, fieldPath)
+			if err != nil {
+				return nil, err
+			}
+			mapping[key.Value] = value
+		}
+		return mapping, nil
+	}
+
+	return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+// jsonFields maps the json names of a struct's fields, including those of
+// embedded structs, to their types
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for embeddedName, embeddedType := range jsonFields(embedded) {
+					if _, ok := fields[embeddedName]; !ok {
+						fields[embeddedName] = embeddedType
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// lookupField matches a key to a field the way encoding/json does, preferring
+// an exact match over a case-insensitive one
+func lookupField(fields map[string]reflect.Type, key string) (string, reflect.Type, bool) {
+	if fieldType, ok := fields[key]; ok {
+		return key, fieldType, true
+	}
+	for name, fieldType := range fields {
+		if strings.EqualFold(name, key) {
+			return name, fieldType, true
+		}
+	}
+	return "", nil, false
+}
+
+func resolve(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// offsetPosition returns the 1-based line and column of the byte at offset
+func offsetPosition(data []byte, offset int64) (int, int) {
+	offset = max(0, min(offset, int64(len(data))))
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
@@ -0,0 +1,169 @@
+package configfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix starts the name of every environment variable that overrides a
+// config field: eth_rpc_url is EIGENLVR_ETH_RPC_URL
+const EnvPrefix = "EIGENLVR_"
+
+// EnvName is the environment variable that overrides the config key
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(key)
+}
+
+// ApplyEnv overrides the fields of out, a pointer to a config struct, from
+// EIGENLVR_* environment variables named after their json keys, and returns
+// the variables it applied. Scalars are parsed from their text; lists of
+// scalars may be comma-separated; anything else is given as JSON.
+func ApplyEnv(out interface{}) ([]string, error) {
+	return applyEnv(out, os.LookupEnv)
+}
+
+func applyEnv(out interface{}, lookup func(string) (string, bool)) ([]string, error) {
+	config := reflect.ValueOf(out)
+	if config.Kind() != reflect.Pointer || config.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config must be a pointer to a struct, got %T", out)
+	}
+	config = config.Elem()
+
+	fields := jsonFieldIndexes(config.Type())
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var applied []string
+	for _, key := range keys {
+		name := EnvName(key)
+		raw, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(config.FieldByIndex(fields[key]), raw); err != nil {
+			return applied, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		applied = append(applied, name)
+	}
+	return applied, nil
+}
+
+// jsonFieldIndexes maps the json names of a struct's fields, including those
+// of embedded structs, to their field indexes
+func jsonFieldIndexes(t reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embeddedName, index := range jsonFieldIndexes(field.Type) {
+				if _, ok := fields[embeddedName]; !ok {
+					fields[embeddedName] = append([]int{i}, index...)
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = []int{i}
+	}
+	return fields
+}
+
+func setFromEnv(field reflect.Value, raw string) error {
+	if field.Kind() == reflect.Pointer {
+		value := reflect.New(field.Type().Elem())
+		if err := setFromEnv(value.Elem(), raw); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(raw, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(raw, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(value)
+	case reflect.Slice:
+		if trimmed := strings.TrimSpace(raw); !strings.HasPrefix(trimmed, "[") && isScalar(field.Type().Elem()) {
+			items := reflect.MakeSlice(field.Type(), 0, 0)
+			if trimmed != "" {
+				for _, item := range strings.Split(trimmed, ",") {
+					value := reflect.New(field.Type().Elem()).Elem()
+					if err := setFromEnv(value, strings.TrimSpace(item)); err != nil {
+						return err
+					}
+					items = reflect.Append(items, value)
+				}
+			}
+			field.Set(items)
+			return nil
+		}
+		return setFromJSON(field, raw)
+	default:
+		return setFromJSON(field, raw)
+	}
+	return nil
+}
+
+// setFromJSON replaces the field with the JSON value, rather than merging
+// into what the file set
+func setFromJSON(field reflect.Value, raw string) error {
+	value := reflect.New(field.Type())
+	if err := json.Unmarshal([]byte(raw), value.Interface()); err != nil {
+		return err
+	}
+	field.Set(value.Elem())
+	return nil
+}
+
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
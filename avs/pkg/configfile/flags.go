@@ -0,0 +1,74 @@
+package configfile
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FlagName is the command-line flag that overrides the config key:
+// eth_rpc_url is -eth-rpc-url
+func FlagName(key string) string {
+	return strings.ReplaceAll(key, "_", "-")
+}
+
+// Flags are the command-line flags overriding config fields, one per key
+type Flags struct {
+	flagSet *flag.FlagSet
+	// keys maps flag names to the config keys they override
+	keys map[string]string
+}
+
+// RegisterFlags defines a flag on flagSet for every field of config, a
+// pointer to a config struct, named after its json key. Keys whose flag the
+// set already defines keep that flag. Values are parsed as for ApplyEnv.
+func RegisterFlags(flagSet *flag.FlagSet, config interface{}) *Flags {
+	flags := &Flags{flagSet: flagSet, keys: make(map[string]string)}
+
+	fields := jsonFieldIndexes(reflect.TypeOf(config).Elem())
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := FlagName(key)
+		if flagSet.Lookup(name) != nil {
+			continue
+		}
+		flagSet.String(name, "", fmt.Sprintf("Overrides %s from the config file and %s", key, EnvName(key)))
+		flags.keys[name] = key
+	}
+	return flags
+}
+
+// Apply overrides the fields of out, a pointer to the config struct the
+// flags were registered for, with the flags given on the command line, and
+// returns the flags it applied. It is applied last, over the file and the
+// environment.
+func (f *Flags) Apply(out interface{}) ([]string, error) {
+	config := reflect.ValueOf(out)
+	if config.Kind() != reflect.Pointer || config.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config must be a pointer to a struct, got %T", out)
+	}
+	config = config.Elem()
+	fields := jsonFieldIndexes(config.Type())
+
+	var applied []string
+	var err error
+	f.flagSet.Visit(func(set *flag.Flag) {
+		key, ok := f.keys[set.Name]
+		if !ok || err != nil {
+			return
+		}
+		if setErr := setFromEnv(config.FieldByIndex(fields[key]), set.Value.String()); setErr != nil {
+			err = fmt.Errorf("invalid -%s: %w", set.Name, setErr)
+			return
+		}
+		applied = append(applied, "-"+set.Name)
+	})
+	return applied, err
+}
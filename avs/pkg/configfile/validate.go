@@ -0,0 +1,154 @@
+package configfile
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ValidationError lists every problem found in a config
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid config:\n  - %s", strings.Join(e.Problems, "\n  - "))
+}
+
+// Problems collects what is wrong with a config, so startup can report all of
+// it at once instead of failing on the first. Each check names the config
+// key; empty optional values pass.
+type Problems []string
+
+// Addf records a problem
+func (p *Problems) Addf(format string, args ...interface{}) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+// Err returns a *ValidationError listing the problems, or nil if there are none
+func (p Problems) Err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p}
+}
+
+// Required checks that the value is set
+func (p *Problems) Required(key, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		p.Addf("%s is required", key)
+		return false
+	}
+	return true
+}
+
+// Address checks that the value is a hex address, and not the zero address
+func (p *Problems) Address(key, value string) {
+	if value == "" {
+		return
+	}
+	if !common.IsHexAddress(value) {
+		p.Addf("%s %q is not a hex address", key, value)
+		return
+	}
+	if common.HexToAddress(value) == (common.Address{}) {
+		p.Addf("%s is the zero address", key)
+	}
+}
+
+// RequiredAddress checks that the value is set to a non-zero hex address
+func (p *Problems) RequiredAddress(key, value string) {
+	if p.Required(key, value) {
+		p.Address(key, value)
+	}
+}
+
+// URL checks that the value is an absolute URL with one of the schemes
+func (p *Problems) URL(key, value string, schemes ...string) {
+	if value == "" {
+		return
+	}
+	parsed, err := url.Parse(value)
+	if err != nil {
+		p.Addf("%s %q is not a valid URL: %v", key, value, err)
+		return
+	}
+	if parsed.Host == "" {
+		p.Addf("%s %q has no host", key, value)
+		return
+	}
+	for _, scheme := range schemes {
+		if strings.EqualFold(parsed.Scheme, scheme) {
+			return
+		}
+	}
+	p.Addf("%s %q must use %s", key, value, strings.Join(schemes, " or "))
+}
+
+// HostPort checks that the value is a listen or dial address such as
+// "localhost:8090" or ":8090"
+func (p *Problems) HostPort(key, value string) {
+	if value == "" {
+		return
+	}
+	_, port, err := net.SplitHostPort(value)
+	if err != nil {
+		p.Addf("%s %q is not a host:port address: %v", key, value, err)
+		return
+	}
+	if _, err := net.LookupPort("tcp", port); err != nil {
+		p.Addf("%s %q has an invalid port: %v", key, value, err)
+	}
+}
+
+// File checks that the value names an existing regular file
+func (p *Problems) File(key, path string) {
+	if path == "" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			p.Addf("%s %q does not exist", key, path)
+		} else {
+			p.Addf("%s %q is not readable: %v", key, path, err)
+		}
+		return
+	}
+	if info.IsDir() {
+		p.Addf("%s %q is a directory", key, path)
+	}
+}
+
+// Duration checks that the value parses as a non-negative duration
+func (p *Problems) Duration(key, value string) {
+	if value == "" {
+		return
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		p.Addf("%s %q is not a duration such as 30s or 5m", key, value)
+		return
+	}
+	if parsed < 0 {
+		p.Addf("%s %q is negative", key, value)
+	}
+}
+
+// OneOf checks that the value is empty or one of the allowed values
+func (p *Problems) OneOf(key, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+	for _, option := range allowed {
+		if value == option {
+			return
+		}
+	}
+	p.Addf("%s %q must be one of %s", key, value, strings.Join(allowed, ", "))
+}
@@ -0,0 +1,120 @@
+package delegation
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// delegationManagerAbi is the subset of EigenLayer's DelegationManager used for monitoring
+const delegationManagerAbi = `[
+	{"type":"function","name":"operatorShares","stateMutability":"view","inputs":[{"name":"operator","type":"address"},{"name":"strategy","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// registryCoordinatorAbi is the subset of the AVS RegistryCoordinator used for monitoring
+const registryCoordinatorAbi = `[
+	{"type":"function","name":"stakeRegistry","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"getCurrentQuorumBitmap","stateMutability":"view","inputs":[{"name":"operatorId","type":"bytes32"}],"outputs":[{"name":"","type":"uint192"}]}
+]`
+
+// stakeRegistryAbi is the subset of the AVS StakeRegistry used for monitoring
+const stakeRegistryAbi = `[
+	{"type":"function","name":"minimumStakeForQuorum","stateMutability":"view","inputs":[{"name":"quorumNumber","type":"uint8"}],"outputs":[{"name":"","type":"uint96"}]},
+	{"type":"function","name":"weightOfOperatorForQuorum","stateMutability":"view","inputs":[{"name":"quorumNumber","type":"uint8"},{"name":"operator","type":"address"}],"outputs":[{"name":"","type":"uint96"}]}
+]`
+
+// Contracts reads delegation and stake state for a single operator
+type Contracts struct {
+	delegationManager   *bind.BoundContract
+	registryCoordinator *bind.BoundContract
+	stakeRegistry       *bind.BoundContract
+}
+
+// NewContracts binds the DelegationManager and the AVS registry contracts. The
+// StakeRegistry address is read from the RegistryCoordinator.
+func NewContracts(
+	ctx context.Context,
+	delegationManagerAddr common.Address,
+	registryCoordinatorAddr common.Address,
+	backend bind.ContractBackend,
+) (*Contracts, error) {
+	delegationManager, err := bindContract(delegationManagerAddr, delegationManagerAbi, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind delegation manager: %w", err)
+	}
+
+	registryCoordinator, err := bindContract(registryCoordinatorAddr, registryCoordinatorAbi, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind registry coordinator: %w", err)
+	}
+
+	var out []interface{}
+	if err := registryCoordinator.Call(&bind.CallOpts{Context: ctx}, &out, "stakeRegistry"); err != nil {
+		return nil, fmt.Errorf("failed to get stake registry address: %w", err)
+	}
+	stakeRegistryAddr := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	stakeRegistry, err := bindContract(stakeRegistryAddr, stakeRegistryAbi, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind stake registry: %w", err)
+	}
+
+	return &Contracts{
+		delegationManager:   delegationManager,
+		registryCoordinator: registryCoordinator,
+		stakeRegistry:       stakeRegistry,
+	}, nil
+}
+
+func bindContract(address common.Address, contractAbi string, backend bind.ContractBackend) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractAbi))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, backend, backend, backend), nil
+}
+
+// OperatorShares returns the shares delegated to operator in strategy
+func (c *Contracts) OperatorShares(ctx context.Context, operator, strategy common.Address) (*big.Int, error) {
+	return c.callBigInt(ctx, c.delegationManager, "operatorShares", operator, strategy)
+}
+
+// OperatorQuorums returns the quorums the operator is currently registered in
+func (c *Contracts) OperatorQuorums(ctx context.Context, operatorId [32]byte) ([]uint8, error) {
+	bitmap, err := c.callBigInt(ctx, c.registryCoordinator, "getCurrentQuorumBitmap", operatorId)
+	if err != nil {
+		return nil, err
+	}
+
+	var quorums []uint8
+	for i := 0; i < bitmap.BitLen(); i++ {
+		if bitmap.Bit(i) == 1 {
+			quorums = append(quorums, uint8(i))
+		}
+	}
+	return quorums, nil
+}
+
+// MinimumStakeForQuorum returns the stake an operator needs to stay in quorum
+func (c *Contracts) MinimumStakeForQuorum(ctx context.Context, quorum uint8) (*big.Int, error) {
+	return c.callBigInt(ctx, c.stakeRegistry, "minimumStakeForQuorum", quorum)
+}
+
+// WeightForQuorum returns the operator's live stake weight in quorum, i.e. the
+// stake it would have if its registry entry were updated now
+func (c *Contracts) WeightForQuorum(ctx context.Context, quorum uint8, operator common.Address) (*big.Int, error) {
+	return c.callBigInt(ctx, c.stakeRegistry, "weightOfOperatorForQuorum", quorum, operator)
+}
+
+func (c *Contracts) callBigInt(ctx context.Context, contract *bind.BoundContract, method string, args ...interface{}) (*big.Int, error) {
+	var out []interface{}
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, method, args...); err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
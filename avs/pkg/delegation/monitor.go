@@ -0,0 +1,346 @@
+package delegation
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// eventBufferSize bounds the number of undelivered events; older events
+	// are dropped once the buffer is full
+	eventBufferSize = 64
+
+	basisPoints = 10_000
+)
+
+// EventKind identifies a delegation change worth alerting on
+type EventKind string
+
+const (
+	// EventLargeDelegation is emitted when shares in a strategy grow by at least the threshold
+	EventLargeDelegation EventKind = "large_delegation"
+	// EventLargeUndelegation is emitted when shares in a strategy shrink by at least the threshold
+	EventLargeUndelegation EventKind = "large_undelegation"
+	// EventQuorumStandingChanged is emitted when the operator's weight crosses a quorum's minimum stake
+	EventQuorumStandingChanged EventKind = "quorum_standing_changed"
+)
+
+// Event describes a significant change in the operator's delegated stake
+type Event struct {
+	Kind     EventKind      `json:"kind"`
+	Strategy common.Address `json:"strategy,omitempty"`
+	Quorum   uint8          `json:"quorum,omitempty"`
+	Previous *big.Int       `json:"previous"`
+	Current  *big.Int       `json:"current"`
+	// MeetsMinimum is set for quorum standing events
+	MeetsMinimum bool      `json:"meetsMinimum,omitempty"`
+	ObservedAt   time.Time `json:"observedAt"`
+}
+
+// QuorumStanding is the operator's weight in a quorum relative to its minimum stake
+type QuorumStanding struct {
+	Quorum       uint8    `json:"quorum"`
+	Weight       *big.Int `json:"weight"`
+	MinimumStake *big.Int `json:"minimumStake"`
+	MeetsMinimum bool     `json:"meetsMinimum"`
+}
+
+// Snapshot is the latest delegation state observed by the monitor
+type Snapshot struct {
+	Operator  common.Address              `json:"operator"`
+	Shares    map[common.Address]*big.Int `json:"shares"`
+	Quorums   []QuorumStanding            `json:"quorums"`
+	UpdatedAt time.Time                   `json:"updatedAt"`
+}
+
+// Monitor polls the stake delegated to an operator, exposes it as metrics and
+// emits events for large delegation changes and quorum standing changes
+type Monitor struct {
+	operator       common.Address
+	operatorId     [32]byte
+	strategies     []common.Address
+	contracts      *Contracts
+	interval       time.Duration
+	largeChangeBps uint64
+	logger         logging.Logger
+	events         chan Event
+
+	mu        sync.RWMutex
+	shares    map[common.Address]*big.Int
+	standings map[uint8]QuorumStanding
+	updatedAt time.Time
+
+	sharesGauge       *prometheus.GaugeVec
+	quorumWeightGauge *prometheus.GaugeVec
+	quorumMinGauge    *prometheus.GaugeVec
+	quorumMeetsGauge  *prometheus.GaugeVec
+	eventsCounter     *prometheus.CounterVec
+}
+
+// NewMonitor creates a monitor for the given strategies. A share change of at
+// least largeChangeBps basis points of the previous balance is reported as a
+// large delegation or undelegation.
+func NewMonitor(
+	operator common.Address,
+	operatorId [32]byte,
+	strategies []common.Address,
+	contracts *Contracts,
+	interval time.Duration,
+	largeChangeBps uint64,
+	reg prometheus.Registerer,
+	logger logging.Logger,
+) *Monitor {
+	monitor := &Monitor{
+		operator:       operator,
+		operatorId:     operatorId,
+		strategies:     strategies,
+		contracts:      contracts,
+		interval:       interval,
+		largeChangeBps: largeChangeBps,
+		logger:         logger.With("component", "delegation-monitor"),
+		events:         make(chan Event, eventBufferSize),
+		shares:         make(map[common.Address]*big.Int),
+		standings:      make(map[uint8]QuorumStanding),
+
+		sharesGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator_delegation",
+			Name:      "shares",
+			Help:      "Shares delegated to the operator per strategy",
+		}, []string{"strategy"}),
+		quorumWeightGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator_delegation",
+			Name:      "quorum_weight",
+			Help:      "Operator stake weight per quorum",
+		}, []string{"quorum"}),
+		quorumMinGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator_delegation",
+			Name:      "quorum_minimum_stake",
+			Help:      "Minimum stake required to remain in each quorum",
+		}, []string{"quorum"}),
+		quorumMeetsGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator_delegation",
+			Name:      "quorum_meets_minimum",
+			Help:      "1 if the operator's weight meets the quorum's minimum stake, 0 otherwise",
+		}, []string{"quorum"}),
+		eventsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator_delegation",
+			Name:      "events_total",
+			Help:      "Delegation events emitted, by kind",
+		}, []string{"kind"}),
+	}
+
+	reg.MustRegister(
+		monitor.sharesGauge,
+		monitor.quorumWeightGauge,
+		monitor.quorumMinGauge,
+		monitor.quorumMeetsGauge,
+		monitor.eventsCounter,
+	)
+
+	return monitor
+}
+
+// Events returns the channel delegation events are delivered on
+func (m *Monitor) Events() <-chan Event {
+	return m.events
+}
+
+// Start polls delegation state until ctx is done
+func (m *Monitor) Start(ctx context.Context) {
+	m.logger.Info("Starting delegation monitor",
+		"operator", m.operator.Hex(),
+		"strategies", len(m.strategies),
+		"interval", m.interval,
+	)
+
+	if err := m.Update(ctx); err != nil {
+		m.logger.Warn("Failed to update delegation state", "error", err)
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Update(ctx); err != nil {
+				m.logger.Warn("Failed to update delegation state", "error", err)
+			}
+		}
+	}
+}
+
+// Update refreshes delegated shares and quorum standings, emitting events for
+// changes since the previous update. The first update only sets the baseline.
+func (m *Monitor) Update(ctx context.Context) error {
+	shares := make(map[common.Address]*big.Int, len(m.strategies))
+	for _, strategy := range m.strategies {
+		amount, err := m.contracts.OperatorShares(ctx, m.operator, strategy)
+		if err != nil {
+			return err
+		}
+		shares[strategy] = amount
+	}
+
+	quorums, err := m.contracts.OperatorQuorums(ctx, m.operatorId)
+	if err != nil {
+		return err
+	}
+
+	standings := make(map[uint8]QuorumStanding, len(quorums))
+	for _, quorum := range quorums {
+		weight, err := m.contracts.WeightForQuorum(ctx, quorum, m.operator)
+		if err != nil {
+			return err
+		}
+		minimumStake, err := m.contracts.MinimumStakeForQuorum(ctx, quorum)
+		if err != nil {
+			return err
+		}
+		standings[quorum] = QuorumStanding{
+			Quorum:       quorum,
+			Weight:       weight,
+			MinimumStake: minimumStake,
+			MeetsMinimum: weight.Cmp(minimumStake) >= 0,
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	baseline := m.updatedAt.IsZero()
+
+	for strategy, amount := range shares {
+		m.sharesGauge.WithLabelValues(strategy.Hex()).Set(toFloat(amount))
+
+		previous, ok := m.shares[strategy]
+		if baseline || !ok {
+			continue
+		}
+		if kind, large := m.classifyChange(previous, amount); large {
+			m.emit(Event{
+				Kind:       kind,
+				Strategy:   strategy,
+				Previous:   previous,
+				Current:    amount,
+				ObservedAt: now,
+			})
+		}
+	}
+
+	for quorum, standing := range standings {
+		label := quorumLabel(quorum)
+		m.quorumWeightGauge.WithLabelValues(label).Set(toFloat(standing.Weight))
+		m.quorumMinGauge.WithLabelValues(label).Set(toFloat(standing.MinimumStake))
+		if standing.MeetsMinimum {
+			m.quorumMeetsGauge.WithLabelValues(label).Set(1)
+		} else {
+			m.quorumMeetsGauge.WithLabelValues(label).Set(0)
+		}
+
+		previous, ok := m.standings[quorum]
+		if baseline || !ok || previous.MeetsMinimum == standing.MeetsMinimum {
+			continue
+		}
+		m.emit(Event{
+			Kind:         EventQuorumStandingChanged,
+			Quorum:       quorum,
+			Previous:     previous.Weight,
+			Current:      standing.Weight,
+			MeetsMinimum: standing.MeetsMinimum,
+			ObservedAt:   now,
+		})
+	}
+
+	m.shares = shares
+	m.standings = standings
+	m.updatedAt = now
+
+	return nil
+}
+
+// classifyChange reports whether the move from previous to current is at least
+// largeChangeBps of previous. Any delegation into an empty strategy is large.
+func (m *Monitor) classifyChange(previous, current *big.Int) (EventKind, bool) {
+	delta := new(big.Int).Sub(current, previous)
+	if delta.Sign() == 0 {
+		return "", false
+	}
+
+	kind := EventLargeDelegation
+	if delta.Sign() < 0 {
+		kind = EventLargeUndelegation
+	}
+
+	if previous.Sign() == 0 {
+		return kind, true
+	}
+
+	// |delta| * 10000 >= previous * threshold
+	lhs := new(big.Int).Mul(new(big.Int).Abs(delta), big.NewInt(basisPoints))
+	rhs := new(big.Int).Mul(previous, new(big.Int).SetUint64(m.largeChangeBps))
+	return kind, lhs.Cmp(rhs) >= 0
+}
+
+// emit delivers the event without blocking. Callers must hold the write lock.
+func (m *Monitor) emit(event Event) {
+	m.eventsCounter.WithLabelValues(string(event.Kind)).Inc()
+
+	select {
+	case m.events <- event:
+	default:
+		m.logger.Warn("Dropping delegation event, consumer is not keeping up", "kind", event.Kind)
+	}
+}
+
+// Snapshot returns the latest observed delegation state
+func (m *Monitor) Snapshot() Snapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	shares := make(map[common.Address]*big.Int, len(m.shares))
+	for strategy, amount := range m.shares {
+		shares[strategy] = amount
+	}
+
+	quorums := make([]QuorumStanding, 0, len(m.standings))
+	for quorum := 0; quorum < 256; quorum++ {
+		if standing, ok := m.standings[uint8(quorum)]; ok {
+			quorums = append(quorums, standing)
+		}
+	}
+
+	return Snapshot{
+		Operator:  m.operator,
+		Shares:    shares,
+		Quorums:   quorums,
+		UpdatedAt: m.updatedAt,
+	}
+}
+
+func quorumLabel(quorum uint8) string {
+	return strconv.Itoa(int(quorum))
+}
+
+func toFloat(amount *big.Int) float64 {
+	if amount == nil {
+		return 0
+	}
+	f, _ := new(big.Float).SetInt(amount).Float64()
+	return f
+}
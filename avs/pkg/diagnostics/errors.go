@@ -0,0 +1,171 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+
+	"github.com/eigenlvr/avs/pkg/redact"
+)
+
+// DefaultErrorRingSize is the number of recent errors kept for diagnostics
+const DefaultErrorRingSize = 50
+
+// ErrorEntry is an error logged by the process
+type ErrorEntry struct {
+	Time      time.Time         `json:"time"`
+	Component string            `json:"component,omitempty"`
+	Message   string            `json:"message"`
+	Fields    map[string]string `json:"fields,omitempty"`
+}
+
+// ErrorRing keeps the most recent errors in a fixed-size ring buffer
+type ErrorRing struct {
+	mu      sync.Mutex
+	entries []ErrorEntry
+	next    int
+	full    bool
+	total   uint64
+}
+
+func NewErrorRing(size int) *ErrorRing {
+	if size <= 0 {
+		size = DefaultErrorRingSize
+	}
+	return &ErrorRing{entries: make([]ErrorEntry, size)}
+}
+
+// Record adds an entry, overwriting the oldest once the ring is full
+func (r *ErrorRing) Record(entry ErrorEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.total++
+}
+
+// Entries returns the recorded errors, newest first
+func (r *ErrorRing) Entries() []ErrorEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.entries)
+	}
+
+	entries := make([]ErrorEntry, 0, count)
+	for i := 1; i <= count; i++ {
+		entries = append(entries, r.entries[(r.next-i+len(r.entries))%len(r.entries)])
+	}
+	return entries
+}
+
+// Total returns the number of errors recorded since start
+func (r *ErrorRing) Total() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.total
+}
+
+// RecordingLogger records Error and Fatal logs in an ErrorRing before
+// delegating to the wrapped logger. Recorded fields are redacted.
+type RecordingLogger struct {
+	logger logging.Logger
+	ring   *ErrorRing
+	tags   []any
+}
+
+var _ logging.Logger = (*RecordingLogger)(nil)
+
+// NewRecordingLogger returns a logger that records errors in ring
+func NewRecordingLogger(logger logging.Logger, ring *ErrorRing) logging.Logger {
+	return &RecordingLogger{logger: logger, ring: ring}
+}
+
+func (l *RecordingLogger) Debug(msg string, tags ...any) {
+	l.logger.Debug(msg, tags...)
+}
+
+func (l *RecordingLogger) Info(msg string, tags ...any) {
+	l.logger.Info(msg, tags...)
+}
+
+func (l *RecordingLogger) Warn(msg string, tags ...any) {
+	l.logger.Warn(msg, tags...)
+}
+
+func (l *RecordingLogger) Error(msg string, tags ...any) {
+	l.record(msg, tags)
+	l.logger.Error(msg, tags...)
+}
+
+func (l *RecordingLogger) Fatal(msg string, tags ...any) {
+	l.record(msg, tags)
+	l.logger.Fatal(msg, tags...)
+}
+
+func (l *RecordingLogger) Debugf(template string, args ...interface{}) {
+	l.logger.Debugf(template, args...)
+}
+
+func (l *RecordingLogger) Infof(template string, args ...interface{}) {
+	l.logger.Infof(template, args...)
+}
+
+func (l *RecordingLogger) Warnf(template string, args ...interface{}) {
+	l.logger.Warnf(template, args...)
+}
+
+func (l *RecordingLogger) Errorf(template string, args ...interface{}) {
+	l.record(fmt.Sprintf(template, redact.Args(args...)...), nil)
+	l.logger.Errorf(template, args...)
+}
+
+func (l *RecordingLogger) Fatalf(template string, args ...interface{}) {
+	l.record(fmt.Sprintf(template, redact.Args(args...)...), nil)
+	l.logger.Fatalf(template, args...)
+}
+
+func (l *RecordingLogger) With(tags ...any) logging.Logger {
+	combined := make([]any, 0, len(l.tags)+len(tags))
+	combined = append(combined, l.tags...)
+	combined = append(combined, tags...)
+
+	return &RecordingLogger{
+		logger: l.logger.With(tags...),
+		ring:   l.ring,
+		tags:   combined,
+	}
+}
+
+func (l *RecordingLogger) record(msg string, tags []any) {
+	entry := ErrorEntry{
+		Time:    time.Now().UTC(),
+		Message: msg,
+	}
+
+	all := append(append([]any{}, l.tags...), tags...)
+	all = redact.Tags(all...)
+	for i := 0; i+1 < len(all); i += 2 {
+		key := fmt.Sprint(all[i])
+		value := fmt.Sprint(all[i+1])
+		if key == "component" {
+			entry.Component = value
+			continue
+		}
+		if entry.Fields == nil {
+			entry.Fields = make(map[string]string)
+		}
+		entry.Fields[key] = value
+	}
+
+	l.ring.Record(entry)
+}
@@ -0,0 +1,118 @@
+// Package diagnostics serves a runtime status snapshot for quick triage:
+// goroutine count, memory statistics, queue depths and recent errors.
+package diagnostics
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Depth is the occupancy of a channel or queue. Cap is zero for unbounded queues.
+type Depth struct {
+	Len int `json:"len"`
+	Cap int `json:"cap"`
+}
+
+// MemoryStats is the subset of runtime.MemStats useful for triage
+type MemoryStats struct {
+	HeapAllocBytes uint64 `json:"heapAllocBytes"`
+	HeapInuseBytes uint64 `json:"heapInuseBytes"`
+	SysBytes       uint64 `json:"sysBytes"`
+	NumGC          uint32 `json:"numGc"`
+	LastGC         string `json:"lastGc,omitempty"`
+}
+
+// Status is the response of the /debug/status endpoint
+type Status struct {
+	Service      string           `json:"service"`
+	Version      string           `json:"version"`
+	StartedAt    time.Time        `json:"startedAt"`
+	Uptime       string           `json:"uptime"`
+	GoVersion    string           `json:"goVersion"`
+	Goroutines   int              `json:"goroutines"`
+	Memory       MemoryStats      `json:"memory"`
+	Depths       map[string]Depth `json:"depths"`
+	ErrorsTotal  uint64           `json:"errorsTotal"`
+	RecentErrors []ErrorEntry     `json:"recentErrors"`
+}
+
+// Collector gathers a Status from registered depth probes and an error ring
+type Collector struct {
+	service   string
+	version   string
+	startedAt time.Time
+	errors    *ErrorRing
+
+	mu     sync.RWMutex
+	depths map[string]func() Depth
+}
+
+func NewCollector(service, version string, errors *ErrorRing) *Collector {
+	return &Collector{
+		service:   service,
+		version:   version,
+		startedAt: time.Now().UTC(),
+		errors:    errors,
+		depths:    make(map[string]func() Depth),
+	}
+}
+
+// RegisterDepth adds a named probe reporting a channel or queue depth
+func (c *Collector) RegisterDepth(name string, probe func() Depth) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.depths[name] = probe
+}
+
+// Status collects the current runtime status
+func (c *Collector) Status() Status {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	memory := MemoryStats{
+		HeapAllocBytes: mem.HeapAlloc,
+		HeapInuseBytes: mem.HeapInuse,
+		SysBytes:       mem.Sys,
+		NumGC:          mem.NumGC,
+	}
+	if mem.LastGC > 0 {
+		memory.LastGC = time.Unix(0, int64(mem.LastGC)).UTC().Format(time.RFC3339)
+	}
+
+	c.mu.RLock()
+	names := make([]string, 0, len(c.depths))
+	for name := range c.depths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	depths := make(map[string]Depth, len(names))
+	for _, name := range names {
+		depths[name] = c.depths[name]()
+	}
+	c.mu.RUnlock()
+
+	return Status{
+		Service:      c.service,
+		Version:      c.version,
+		StartedAt:    c.startedAt,
+		Uptime:       time.Since(c.startedAt).Round(time.Second).String(),
+		GoVersion:    runtime.Version(),
+		Goroutines:   runtime.NumGoroutine(),
+		Memory:       memory,
+		Depths:       depths,
+		ErrorsTotal:  c.errors.Total(),
+		RecentErrors: c.errors.Entries(),
+	}
+}
+
+// Handler serves the status as JSON
+func (c *Collector) Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(c.Status())
+}
@@ -0,0 +1,69 @@
+package digest
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	uint32Type, _  = abi.NewType("uint32", "", nil)
+	uint256Type, _ = abi.NewType("uint256", "", nil)
+	addressType, _ = abi.NewType("address", "", nil)
+
+	// auctionTaskResponseArgs mirrors the AuctionTaskResponse struct in
+	// EigenLVRAVSServiceManager. The struct only has static fields, so
+	// abi.encode(struct) is the same as encoding the fields in order.
+	auctionTaskResponseArgs = abi.Arguments{
+		{Name: "referenceTaskIndex", Type: uint32Type},
+		{Name: "winner", Type: addressType},
+		{Name: "winningBid", Type: uint256Type},
+		{Name: "totalBids", Type: uint256Type},
+	}
+)
+
+// EncodeAuctionTaskResponse returns abi.encode(taskResponse) as computed by the service manager
+func EncodeAuctionTaskResponse(
+	referenceTaskIndex uint32,
+	winner common.Address,
+	winningBid *big.Int,
+	totalBids uint32,
+) ([]byte, error) {
+	if winningBid == nil {
+		winningBid = big.NewInt(0)
+	}
+	if winningBid.Sign() < 0 {
+		return nil, fmt.Errorf("winning bid must not be negative: %s", winningBid)
+	}
+
+	encoded, err := auctionTaskResponseArgs.Pack(
+		referenceTaskIndex,
+		winner,
+		winningBid,
+		new(big.Int).SetUint64(uint64(totalBids)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to abi encode task response: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// AuctionTaskResponseDigest returns keccak256(abi.encode(taskResponse)), the message
+// operators sign and the service manager passes to checkSignatures
+func AuctionTaskResponseDigest(
+	referenceTaskIndex uint32,
+	winner common.Address,
+	winningBid *big.Int,
+	totalBids uint32,
+) ([32]byte, error) {
+	encoded, err := EncodeAuctionTaskResponse(referenceTaskIndex, winner, winningBid, totalBids)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return crypto.Keccak256Hash(encoded), nil
+}
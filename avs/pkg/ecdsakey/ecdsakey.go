@@ -0,0 +1,76 @@
+// Package ecdsakey loads ECDSA private keys from either geth-style encrypted
+// keystore JSON files or files holding the raw hex key, and writes keystores.
+package ecdsakey
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+
+	"github.com/eigenlvr/avs/pkg/passphrase"
+)
+
+// Load reads the private key at path. A JSON file is decrypted as a geth-style
+// keystore with the passphrase lookup supplies; anything else is read as a raw
+// hex key.
+func Load(path string, lookup passphrase.Func) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	if !IsKeystore(data) {
+		return crypto.LoadECDSA(path)
+	}
+
+	password, err := lookup(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore %s: %w", path, err)
+	}
+	return key.PrivateKey, nil
+}
+
+// IsKeystore reports whether a key file's contents are a keystore rather than
+// a raw hex key
+func IsKeystore(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// Save writes the key to path as a keystore encrypted with password, readable
+// only by the owner
+func Save(path string, key *ecdsa.PrivateKey, password string) error {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+	encrypted, err := keystore.EncryptKey(&keystore.Key{
+		Id:         id,
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, password, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt key: %w", err)
+	}
+	return writeKeyFile(path, encrypted)
+}
+
+// writeKeyFile writes key material to path, creating its directory, with
+// permissions for the owner only
+func writeKeyFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create key directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+// Package erc4337 sends calls from an ERC-4337 smart account through a
+// bundler. Only EntryPoint v0.7 and accounts exposing SimpleAccount's
+// execute(address,uint256,bytes) with a single ECDSA owner are supported.
+//
+// Gas can be sponsored by a paymaster service speaking ERC-7677: stub data is
+// requested for gas estimation and the final paymaster data once the gas
+// limits are known.
+package erc4337
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// DefaultReceiptTimeout bounds the wait for a sent operation to be
+	// included when Config.ReceiptTimeout is unset
+	DefaultReceiptTimeout = 3 * time.Minute
+
+	// receiptPollInterval is how often the bundler is asked for the receipt
+	receiptPollInterval = 2 * time.Second
+)
+
+// EntryPointV07 is the canonical EntryPoint v0.7 deployment
+var EntryPointV07 = common.HexToAddress("0x0000000071727De22E5E9d8BAf0edAc6f37da032")
+
+// dummySignature is a well-formed ECDSA signature that recovers to some
+// address, so accounts run their full validation during gas estimation
+var dummySignature = hexutil.MustDecode("0xfffffffffffffffffffffffffffffff0000000000000000000000000000000007aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa1c")
+
+var (
+	// ErrOperationFailed is returned when an operation was included but its
+	// call reverted
+	ErrOperationFailed = errors.New("user operation reverted")
+	// ErrHashMismatch is returned when the bundler reports a different hash
+	// than the one signed, i.e. it doesn't agree on the EntryPoint or chain
+	ErrHashMismatch = errors.New("bundler returned a different user operation hash")
+)
+
+const contractsAbi = `[
+	{"type":"function","name":"execute","stateMutability":"nonpayable","inputs":[{"name":"dest","type":"address"},{"name":"value","type":"uint256"},{"name":"func","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"getNonce","stateMutability":"view","inputs":[{"name":"sender","type":"address"},{"name":"key","type":"uint192"}],"outputs":[{"name":"nonce","type":"uint256"}]}
+]`
+
+var contracts = mustParseAbi()
+
+func mustParseAbi() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(contractsAbi))
+	if err != nil {
+		panic(fmt.Sprintf("invalid erc4337 abi: %v", err))
+	}
+	return parsed
+}
+
+// Config configures a Sender
+type Config struct {
+	BundlerUrl string
+	// EntryPoint defaults to EntryPointV07
+	EntryPoint common.Address
+	// Account is the smart account calls are sent from
+	Account common.Address
+	// PaymasterUrl is an ERC-7677 paymaster service, empty when the account
+	// pays for its own gas
+	PaymasterUrl string
+	// PaymasterContext is passed to the paymaster service as is, e.g. a
+	// sponsorship policy id
+	PaymasterContext map[string]interface{}
+	// ReceiptTimeout bounds the wait for inclusion
+	ReceiptTimeout time.Duration
+}
+
+// Backend is the chain access a Sender needs
+type Backend interface {
+	bind.ContractCaller
+	ChainID(ctx context.Context) (*big.Int, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// UserOperation is a v0.7 user operation in the bundler RPC's unpacked form
+type UserOperation struct {
+	Sender                        common.Address  `json:"sender"`
+	Nonce                         *hexutil.Big    `json:"nonce"`
+	Factory                       *common.Address `json:"factory,omitempty"`
+	FactoryData                   hexutil.Bytes   `json:"factoryData,omitempty"`
+	CallData                      hexutil.Bytes   `json:"callData"`
+	CallGasLimit                  *hexutil.Big    `json:"callGasLimit"`
+	VerificationGasLimit          *hexutil.Big    `json:"verificationGasLimit"`
+	PreVerificationGas            *hexutil.Big    `json:"preVerificationGas"`
+	MaxFeePerGas                  *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas          *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Paymaster                     *common.Address `json:"paymaster,omitempty"`
+	PaymasterVerificationGasLimit *hexutil.Big    `json:"paymasterVerificationGasLimit,omitempty"`
+	PaymasterPostOpGasLimit       *hexutil.Big    `json:"paymasterPostOpGasLimit,omitempty"`
+	PaymasterData                 hexutil.Bytes   `json:"paymasterData,omitempty"`
+	Signature                     hexutil.Bytes   `json:"signature"`
+}
+
+// Hash returns the hash the account owner signs, as EntryPoint v0.7's
+// getUserOpHash computes it over the packed operation
+func (op *UserOperation) Hash(entryPoint common.Address, chainId *big.Int) common.Hash {
+	var initCode []byte
+	if op.Factory != nil {
+		initCode = append(op.Factory.Bytes(), op.FactoryData...)
+	}
+	var paymasterAndData []byte
+	if op.Paymaster != nil {
+		paymasterAndData = append(paymasterAndData, op.Paymaster.Bytes()...)
+		paymasterAndData = append(paymasterAndData, uint128(op.PaymasterVerificationGasLimit)...)
+		paymasterAndData = append(paymasterAndData, uint128(op.PaymasterPostOpGasLimit)...)
+		paymasterAndData = append(paymasterAndData, op.PaymasterData...)
+	}
+
+	packed := crypto.Keccak256(
+		common.LeftPadBytes(op.Sender.Bytes(), 32),
+		word(op.Nonce),
+		crypto.Keccak256(initCode),
+		crypto.Keccak256(op.CallData),
+		append(uint128(op.VerificationGasLimit), uint128(op.CallGasLimit)...),
+		word(op.PreVerificationGas),
+		append(uint128(op.MaxPriorityFeePerGas), uint128(op.MaxFeePerGas)...),
+		crypto.Keccak256(paymasterAndData),
+	)
+	return crypto.Keccak256Hash(
+		packed,
+		common.LeftPadBytes(entryPoint.Bytes(), 32),
+		common.LeftPadBytes(chainId.Bytes(), 32),
+	)
+}
+
+func word(value *hexutil.Big) []byte {
+	if value == nil {
+		return make([]byte, 32)
+	}
+	return common.LeftPadBytes(value.ToInt().Bytes(), 32)
+}
+
+func uint128(value *hexutil.Big) []byte {
+	return word(value)[16:]
+}
+
+// Receipt is the outcome of an included operation
+type Receipt struct {
+	UserOpHash  common.Hash
+	TxHash      common.Hash
+	BlockNumber uint64
+	// ActualGasCost is what the account or paymaster paid, in wei
+	ActualGasCost *big.Int
+}
+
+// Sender sends calls from the smart account, signing operations with one of
+// the account's owner keys. Owners can be rotated on the account without the
+// account's address, and so its permissions elsewhere, changing.
+type Sender struct {
+	config    Config
+	backend   Backend
+	bundler   *rpc.Client
+	paymaster *rpc.Client
+	owner     *ecdsa.PrivateKey
+	chainId   *big.Int
+}
+
+func NewSender(ctx context.Context, config Config, backend Backend, owner *ecdsa.PrivateKey) (*Sender, error) {
+	if config.EntryPoint == (common.Address{}) {
+		config.EntryPoint = EntryPointV07
+	}
+	if config.ReceiptTimeout == 0 {
+		config.ReceiptTimeout = DefaultReceiptTimeout
+	}
+
+	chainId, err := backend.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chain id: %w", err)
+	}
+	bundler, err := rpc.DialContext(ctx, config.BundlerUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial bundler: %w", err)
+	}
+	var paymaster *rpc.Client
+	if config.PaymasterUrl != "" {
+		paymaster, err = rpc.DialContext(ctx, config.PaymasterUrl)
+		if err != nil {
+			bundler.Close()
+			return nil, fmt.Errorf("failed to dial paymaster: %w", err)
+		}
+	}
+
+	return &Sender{
+		config:    config,
+		backend:   backend,
+		bundler:   bundler,
+		paymaster: paymaster,
+		owner:     owner,
+		chainId:   chainId,
+	}, nil
+}
+
+// Account returns the smart account's address
+func (s *Sender) Account() common.Address {
+	return s.config.Account
+}
+
+// Owner returns the address of the key operations are signed with
+func (s *Sender) Owner() common.Address {
+	return crypto.PubkeyToAddress(s.owner.PublicKey)
+}
+
+// Send calls target with data from the account and waits for the operation to
+// be included. sent is called with the operation hash once the bundler
+// accepted it.
+func (s *Sender) Send(ctx context.Context, target common.Address, data []byte, sent func(userOpHash common.Hash)) (*Receipt, error) {
+	op, err := s.build(ctx, target, data)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := op.Hash(s.config.EntryPoint, s.chainId)
+	signature, err := crypto.Sign(accounts.TextHash(hash.Bytes()), s.owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign user operation: %w", err)
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	op.Signature = signature
+
+	var accepted common.Hash
+	if err := s.bundler.CallContext(ctx, &accepted, "eth_sendUserOperation", op, s.config.EntryPoint); err != nil {
+		return nil, fmt.Errorf("failed to send user operation: %w", err)
+	}
+	if accepted != hash {
+		return nil, fmt.Errorf("%w: signed %s, got %s", ErrHashMismatch, hash.Hex(), accepted.Hex())
+	}
+	if sent != nil {
+		sent(hash)
+	}
+
+	return s.waitIncluded(ctx, hash)
+}
+
+// build fills in the operation's nonce, fees, gas limits and paymaster data
+func (s *Sender) build(ctx context.Context, target common.Address, data []byte) (*UserOperation, error) {
+	callData, err := contracts.Pack("execute", target, big.NewInt(0), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack execute call: %w", err)
+	}
+
+	var out []interface{}
+	entryPoint := bind.NewBoundContract(s.config.EntryPoint, contracts, s.backend, nil, nil)
+	if err := entryPoint.Call(&bind.CallOpts{Context: ctx}, &out, "getNonce", s.config.Account, big.NewInt(0)); err != nil {
+		return nil, fmt.Errorf("failed to read account nonce: %w", err)
+	}
+	nonce := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	tip, err := s.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas tip: %w", err)
+	}
+	gasPrice, err := s.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas price: %w", err)
+	}
+	// Leave room for the base fee to double before the bundler includes it
+	maxFee := new(big.Int).Add(new(big.Int).Mul(gasPrice, big.NewInt(2)), tip)
+
+	zero := (*hexutil.Big)(big.NewInt(0))
+	op := &UserOperation{
+		Sender:               s.config.Account,
+		Nonce:                (*hexutil.Big)(nonce),
+		CallData:             callData,
+		CallGasLimit:         zero,
+		VerificationGasLimit: zero,
+		PreVerificationGas:   zero,
+		MaxFeePerGas:         (*hexutil.Big)(maxFee),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tip),
+		Signature:            dummySignature,
+	}
+
+	if s.paymaster != nil {
+		if err := s.paymasterData(ctx, op, "pm_getPaymasterStubData"); err != nil {
+			return nil, err
+		}
+	}
+
+	var estimate struct {
+		PreVerificationGas            *hexutil.Big `json:"preVerificationGas"`
+		VerificationGasLimit          *hexutil.Big `json:"verificationGasLimit"`
+		CallGasLimit                  *hexutil.Big `json:"callGasLimit"`
+		PaymasterVerificationGasLimit *hexutil.Big `json:"paymasterVerificationGasLimit"`
+	}
+	if err := s.bundler.CallContext(ctx, &estimate, "eth_estimateUserOperationGas", op, s.config.EntryPoint); err != nil {
+		return nil, fmt.Errorf("failed to estimate user operation gas: %w", err)
+	}
+	if estimate.PreVerificationGas == nil || estimate.VerificationGasLimit == nil || estimate.CallGasLimit == nil {
+		return nil, errors.New("bundler returned an incomplete gas estimate")
+	}
+	op.PreVerificationGas = estimate.PreVerificationGas
+	op.VerificationGasLimit = estimate.VerificationGasLimit
+	op.CallGasLimit = estimate.CallGasLimit
+	if estimate.PaymasterVerificationGasLimit != nil && op.Paymaster != nil {
+		op.PaymasterVerificationGasLimit = estimate.PaymasterVerificationGasLimit
+	}
+
+	if s.paymaster != nil {
+		if err := s.paymasterData(ctx, op, "pm_getPaymasterData"); err != nil {
+			return nil, err
+		}
+	}
+	return op, nil
+}
+
+// paymasterData asks the paymaster service to sponsor the operation
+func (s *Sender) paymasterData(ctx context.Context, op *UserOperation, method string) error {
+	var result struct {
+		Paymaster                     *common.Address `json:"paymaster"`
+		PaymasterData                 hexutil.Bytes   `json:"paymasterData"`
+		PaymasterVerificationGasLimit *hexutil.Big    `json:"paymasterVerificationGasLimit"`
+		PaymasterPostOpGasLimit       *hexutil.Big    `json:"paymasterPostOpGasLimit"`
+	}
+	err := s.paymaster.CallContext(ctx, &result, method, op, s.config.EntryPoint, (*hexutil.Big)(s.chainId), s.config.PaymasterContext)
+	if err != nil {
+		return fmt.Errorf("failed to get paymaster data: %w", err)
+	}
+	if result.Paymaster == nil {
+		return fmt.Errorf("paymaster service returned no paymaster from %s", method)
+	}
+
+	op.Paymaster = result.Paymaster
+	op.PaymasterData = result.PaymasterData
+	// The final data may leave the stub's gas limits in place
+	if result.PaymasterVerificationGasLimit != nil {
+		op.PaymasterVerificationGasLimit = result.PaymasterVerificationGasLimit
+	}
+	if result.PaymasterPostOpGasLimit != nil {
+		op.PaymasterPostOpGasLimit = result.PaymasterPostOpGasLimit
+	}
+	if op.PaymasterVerificationGasLimit == nil {
+		op.PaymasterVerificationGasLimit = (*hexutil.Big)(big.NewInt(0))
+	}
+	if op.PaymasterPostOpGasLimit == nil {
+		op.PaymasterPostOpGasLimit = (*hexutil.Big)(big.NewInt(0))
+	}
+	return nil
+}
+
+// waitIncluded polls the bundler until the operation is included or the
+// receipt timeout passes
+func (s *Sender) waitIncluded(ctx context.Context, hash common.Hash) (*Receipt, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.ReceiptTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+	for {
+		var result *struct {
+			Success       bool        `json:"success"`
+			Reason        string      `json:"reason"`
+			ActualGasCost hexutil.Big `json:"actualGasCost"`
+			Receipt       struct {
+				TransactionHash common.Hash    `json:"transactionHash"`
+				BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+			} `json:"receipt"`
+		}
+		if err := s.bundler.CallContext(ctx, &result, "eth_getUserOperationReceipt", hash); err != nil && ctx.Err() == nil {
+			return nil, fmt.Errorf("failed to get user operation receipt: %w", err)
+		}
+		if result != nil {
+			receipt := &Receipt{
+				UserOpHash:    hash,
+				TxHash:        result.Receipt.TransactionHash,
+				BlockNumber:   uint64(result.Receipt.BlockNumber),
+				ActualGasCost: result.ActualGasCost.ToInt(),
+			}
+			if !result.Success {
+				return receipt, fmt.Errorf("%w in %s: %s", ErrOperationFailed, receipt.TxHash.Hex(), result.Reason)
+			}
+			return receipt, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("user operation %s not included: %w", hash.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close closes the bundler and paymaster connections
+func (s *Sender) Close() {
+	s.bundler.Close()
+	if s.paymaster != nil {
+		s.paymaster.Close()
+	}
+}
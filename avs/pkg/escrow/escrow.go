@@ -0,0 +1,70 @@
+// Package escrow reads searcher deposits from the auction escrow contract.
+// A bid is only worth winning if the searcher has escrowed enough to pay it,
+// so operators check deposits before signing a winner and the aggregator
+// checks again before settling.
+package escrow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// escrowAbi is the subset of the auction escrow used off-chain
+const escrowAbi = `[
+	{"type":"function","name":"depositOf","stateMutability":"view","inputs":[{"name":"bidder","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+// ErrInsufficientDeposit is returned when a bid exceeds the bidder's deposit
+var ErrInsufficientDeposit = errors.New("bid is not covered by the bidder's escrow deposit")
+
+// Reader reads deposits from the escrow
+type Reader struct {
+	address  common.Address
+	contract *bind.BoundContract
+}
+
+func NewReader(address common.Address, backend bind.ContractCaller) (*Reader, error) {
+	parsed, err := abi.JSON(strings.NewReader(escrowAbi))
+	if err != nil {
+		return nil, fmt.Errorf("invalid escrow abi: %w", err)
+	}
+	return &Reader{
+		address:  address,
+		contract: bind.NewBoundContract(address, parsed, backend, nil, nil),
+	}, nil
+}
+
+// Address returns the escrow address
+func (r *Reader) Address() common.Address {
+	return r.address
+}
+
+// DepositAt returns the bidder's deposit at the block, or at the latest block
+// when block is nil
+func (r *Reader) DepositAt(ctx context.Context, bidder common.Address, block *big.Int) (*big.Int, error) {
+	var out []interface{}
+	if err := r.contract.Call(&bind.CallOpts{Context: ctx, BlockNumber: block}, &out, "depositOf", bidder); err != nil {
+		return nil, fmt.Errorf("failed to call depositOf: %w", err)
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+// Verify returns ErrInsufficientDeposit unless the bidder's deposit at the
+// block covers the bid
+func (r *Reader) Verify(ctx context.Context, bidder common.Address, bid *big.Int, block *big.Int) error {
+	deposit, err := r.DepositAt(ctx, bidder, block)
+	if err != nil {
+		return err
+	}
+	if deposit.Cmp(bid) < 0 {
+		return fmt.Errorf("%w: bid %s, deposit %s", ErrInsufficientDeposit, bid, deposit)
+	}
+	return nil
+}
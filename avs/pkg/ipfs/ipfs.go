@@ -0,0 +1,104 @@
+// Package ipfs adds and pins documents through the Kubo RPC API, which IPFS
+// nodes and most pinning services expose.
+package ipfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// AddPath is the Kubo RPC endpoint that adds, and optionally pins, a file
+	AddPath = "/api/v0/add"
+
+	// requestTimeout bounds a single add
+	requestTimeout = 30 * time.Second
+)
+
+// Client adds documents to an IPFS node
+type Client struct {
+	apiUrl string
+	// authorization is sent as the Authorization header when set
+	authorization string
+	http          *http.Client
+}
+
+// NewClient returns a client for the node's RPC API. authorization is the
+// full Authorization header value, e.g. "Bearer <token>", or empty for a node
+// that doesn't require it.
+func NewClient(apiUrl string, authorization string) (*Client, error) {
+	if apiUrl == "" {
+		return nil, errors.New("ipfs api url is required")
+	}
+	return &Client{
+		apiUrl:        strings.TrimRight(apiUrl, "/"),
+		authorization: authorization,
+		http:          &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// AddJSON encodes the value, adds it as a pinned CIDv1 file and returns its CID
+func (c *Client) AddJSON(ctx context.Context, name string, value interface{}) (string, error) {
+	document, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return c.Add(ctx, name, document)
+}
+
+// Add adds the document as a pinned CIDv1 file and returns its CID
+func (c *Client) Add(ctx context.Context, name string, document []byte) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(document); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	query := url.Values{"pin": {"true"}, "cid-version": {"1"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiUrl+AddPath+"?"+query.Encode(), &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("ipfs add returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var added struct {
+		Hash string `json:"Hash"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&added); err != nil {
+		return "", fmt.Errorf("failed to decode ipfs add response: %w", err)
+	}
+	if added.Hash == "" {
+		return "", errors.New("ipfs add returned no cid")
+	}
+	return added.Hash, nil
+}
@@ -0,0 +1,86 @@
+package logwatcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Checkpoint stores the last block whose logs were fully processed
+type Checkpoint interface {
+	Load() (block uint64, ok bool, err error)
+	Save(block uint64) error
+}
+
+// FileCheckpoint persists the checkpoint as JSON. An empty path keeps it in
+// memory only.
+type FileCheckpoint struct {
+	mu    sync.Mutex
+	path  string
+	block uint64
+	ok    bool
+}
+
+type checkpointFile struct {
+	Block uint64 `json:"block"`
+}
+
+func NewFileCheckpoint(path string) *FileCheckpoint {
+	return &FileCheckpoint{path: path}
+}
+
+func (c *FileCheckpoint) Load() (uint64, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ok || c.path == "" {
+		return c.block, c.ok, nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var file checkpointFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return 0, false, fmt.Errorf("failed to decode checkpoint: %w", err)
+	}
+
+	c.block, c.ok = file.Block, true
+	return c.block, true, nil
+}
+
+func (c *FileCheckpoint) Save(block uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.block, c.ok = block, true
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(checkpointFile{Block: block})
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated checkpoint
+	tmpPath := c.path + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmpPath, c.path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,359 @@
+// Package logwatcher delivers contract logs from a websocket subscription and
+// falls back to eth_getLogs polling from the last checkpoint while the
+// websocket endpoint is unavailable.
+package logwatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	ModeWebsocket = "websocket"
+	ModePolling   = "polling"
+
+	defaultPollInterval    = 5 * time.Second
+	defaultWsRetryInterval = 30 * time.Second
+	// defaultMaxBlockRange keeps eth_getLogs requests within common RPC limits
+	defaultMaxBlockRange = 2000
+	// maxSeenLogs bounds the set used to drop logs delivered twice when
+	// switching between the subscription and polling
+	maxSeenLogs = 4096
+)
+
+// LogReader is the HTTP RPC used for polling and catch-up
+type LogReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]gethtypes.Log, error)
+}
+
+// Handler processes a log. Returning an error stops the watcher.
+type Handler func(log gethtypes.Log) error
+
+// Config configures a Watcher
+type Config struct {
+	Name      string
+	Addresses []common.Address
+	Topics    [][]common.Hash
+	// WsUrl is optional; without it the watcher only polls
+	WsUrl           string
+	PollInterval    time.Duration
+	WsRetryInterval time.Duration
+	MaxBlockRange   uint64
+}
+
+// Watcher follows logs matching a filter
+type Watcher struct {
+	config     Config
+	reader     LogReader
+	checkpoint Checkpoint
+	logger     logging.Logger
+
+	mu   sync.RWMutex
+	mode string
+	// live is closed once the watcher first subscribes or polls
+	live chan struct{}
+	// poke makes a polling watcher poll right away
+	poke chan struct{}
+
+	seen      map[logKey]struct{}
+	seenOrder []logKey
+	lastBlock uint64
+
+	modeGauge *prometheus.GaugeVec
+}
+
+type logKey struct {
+	txHash common.Hash
+	index  uint
+}
+
+func NewWatcher(config Config, reader LogReader, checkpoint Checkpoint, reg prometheus.Registerer, logger logging.Logger) *Watcher {
+	if config.PollInterval == 0 {
+		config.PollInterval = defaultPollInterval
+	}
+	if config.WsRetryInterval == 0 {
+		config.WsRetryInterval = defaultWsRetryInterval
+	}
+	if config.MaxBlockRange == 0 {
+		config.MaxBlockRange = defaultMaxBlockRange
+	}
+
+	watcher := &Watcher{
+		config:     config,
+		reader:     reader,
+		checkpoint: checkpoint,
+		logger:     logger.With("component", "log-watcher", "watcher", config.Name),
+		seen:       make(map[logKey]struct{}),
+		live:       make(chan struct{}),
+		poke:       make(chan struct{}, 1),
+
+		modeGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   "eigenlvr",
+			Subsystem:   "log_watcher",
+			Name:        "mode",
+			Help:        "1 for the mode the log watcher is currently using",
+			ConstLabels: prometheus.Labels{"watcher": config.Name},
+		}, []string{"mode"}),
+	}
+	reg.MustRegister(watcher.modeGauge)
+
+	return watcher
+}
+
+// Mode returns whether the watcher is currently subscribed or polling
+func (w *Watcher) Mode() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.mode
+}
+
+// Live is closed once the watcher is following logs, over the websocket
+// subscription or by polling
+func (w *Watcher) Live() <-chan struct{} {
+	return w.live
+}
+
+// Poke makes a polling watcher poll now rather than at its next interval,
+// for callers that learn about new blocks some faster way. A subscribed
+// watcher already receives logs as soon as the node has them.
+func (w *Watcher) Poke() {
+	select {
+	case w.poke <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Watcher) setMode(mode string) {
+	w.mu.Lock()
+	previous := w.mode
+	w.mode = mode
+	w.mu.Unlock()
+
+	if previous == mode {
+		return
+	}
+	w.modeGauge.WithLabelValues(ModeWebsocket).Set(0)
+	w.modeGauge.WithLabelValues(ModePolling).Set(0)
+	w.modeGauge.WithLabelValues(mode).Set(1)
+	if previous == "" {
+		close(w.live)
+	} else {
+		w.logger.Info("Log watcher switched mode", "from", previous, "to", mode)
+	}
+}
+
+// Run delivers logs to handler until ctx is done or handler fails. Logs from
+// reorged blocks are skipped. Processing resumes after the checkpoint, or
+// from the current head when there is none.
+func (w *Watcher) Run(ctx context.Context, handler Handler) error {
+	from, ok, err := w.checkpoint.Load()
+	if err != nil {
+		return err
+	}
+	if ok {
+		w.lastBlock = from
+	} else {
+		head, err := w.reader.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get head block: %w", err)
+		}
+		w.lastBlock = head
+		if err := w.checkpoint.Save(head); err != nil {
+			return err
+		}
+	}
+
+	for {
+		if w.config.WsUrl != "" {
+			err := w.subscribe(ctx, handler)
+			if ctx.Err() != nil {
+				return nil
+			}
+			var handlerErr *handlerError
+			if errors.As(err, &handlerErr) {
+				return handlerErr.err
+			}
+			w.logger.Warn("Websocket log subscription unavailable, polling instead", "error", err)
+		}
+
+		if err := w.poll(ctx, handler); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// handlerError distinguishes handler failures from connection failures
+type handlerError struct {
+	err error
+}
+
+func (e *handlerError) Error() string {
+	return e.err.Error()
+}
+
+// subscribe catches up from the checkpoint over HTTP, then follows the
+// websocket subscription until it fails
+func (w *Watcher) subscribe(ctx context.Context, handler Handler) error {
+	dialCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	wsClient, err := ethclient.DialContext(dialCtx, w.config.WsUrl)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to dial websocket: %w", err)
+	}
+	defer wsClient.Close()
+
+	logs := make(chan gethtypes.Log, 256)
+	sub, err := wsClient.SubscribeFilterLogs(ctx, w.query(nil, nil), logs)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to logs: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	// Logs emitted between the checkpoint and the subscription start arrive
+	// through catch-up; any overlap is dropped as already seen
+	if err := w.catchUp(ctx, handler); err != nil {
+		return err
+	}
+
+	w.setMode(ModeWebsocket)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			if err == nil {
+				err = errors.New("subscription closed")
+			}
+			return err
+		case log := <-logs:
+			if err := w.deliver(log, handler); err != nil {
+				return err
+			}
+			// A block's logs may still be arriving, so only the previous
+			// block is known to be complete
+			if log.BlockNumber > 0 && log.BlockNumber-1 > w.lastBlock {
+				w.saveCheckpoint(log.BlockNumber - 1)
+			}
+		}
+	}
+}
+
+// poll fetches logs over HTTP until the websocket retry interval elapses
+func (w *Watcher) poll(ctx context.Context, handler Handler) error {
+	w.setMode(ModePolling)
+
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	var retry <-chan time.Time
+	if w.config.WsUrl != "" {
+		retryTimer := time.NewTimer(w.config.WsRetryInterval)
+		defer retryTimer.Stop()
+		retry = retryTimer.C
+	}
+
+	for {
+		err := w.catchUp(ctx, handler)
+		var handlerErr *handlerError
+		if errors.As(err, &handlerErr) {
+			return handlerErr.err
+		}
+		if err != nil && ctx.Err() == nil {
+			w.logger.Warn("Failed to poll logs", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-retry:
+			return nil
+		case <-ticker.C:
+		case <-w.poke:
+		}
+	}
+}
+
+// catchUp delivers all logs from the checkpoint to the current head
+func (w *Watcher) catchUp(ctx context.Context, handler Handler) error {
+	head, err := w.reader.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get head block: %w", err)
+	}
+
+	for from := w.lastBlock + 1; from <= head; {
+		to := from + w.config.MaxBlockRange - 1
+		if to > head {
+			to = head
+		}
+
+		logs, err := w.reader.FilterLogs(ctx, w.query(new(big.Int).SetUint64(from), new(big.Int).SetUint64(to)))
+		if err != nil {
+			return fmt.Errorf("failed to get logs for blocks %d-%d: %w", from, to, err)
+		}
+		for _, log := range logs {
+			if err := w.deliver(log, handler); err != nil {
+				return err
+			}
+		}
+
+		w.saveCheckpoint(to)
+		from = to + 1
+	}
+
+	return nil
+}
+
+func (w *Watcher) deliver(log gethtypes.Log, handler Handler) error {
+	if log.Removed {
+		return nil
+	}
+
+	key := logKey{txHash: log.TxHash, index: log.Index}
+	if _, ok := w.seen[key]; ok {
+		return nil
+	}
+	w.seen[key] = struct{}{}
+	w.seenOrder = append(w.seenOrder, key)
+	if len(w.seenOrder) > maxSeenLogs {
+		delete(w.seen, w.seenOrder[0])
+		w.seenOrder = w.seenOrder[1:]
+	}
+
+	if err := handler(log); err != nil {
+		return &handlerError{err: err}
+	}
+	return nil
+}
+
+func (w *Watcher) saveCheckpoint(block uint64) {
+	w.lastBlock = block
+	if err := w.checkpoint.Save(block); err != nil {
+		w.logger.Warn("Failed to save log checkpoint", "block", block, "error", err)
+	}
+}
+
+func (w *Watcher) query(from, to *big.Int) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: from,
+		ToBlock:   to,
+		Addresses: w.config.Addresses,
+		Topics:    w.config.Topics,
+	}
+}
@@ -0,0 +1,37 @@
+package networks
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// ChainIdReader reads the chain id of an RPC endpoint, as eth_chainId
+type ChainIdReader interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// ResolveChainId returns the chain id transactions are signed for: the one
+// the RPC reports, which must match the configured chain id and the named
+// network's when those are set. A zero configured chain id and an empty
+// network leave it to the RPC.
+func ResolveChainId(ctx context.Context, client ChainIdReader, network string, configured uint64) (*big.Int, error) {
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chain id: %w", err)
+	}
+
+	if configured != 0 && (!chainId.IsUint64() || chainId.Uint64() != configured) {
+		return nil, fmt.Errorf("rpc is on chain %s, not the configured chain_id %d", chainId, configured)
+	}
+	if network != "" {
+		preset, err := Lookup(network)
+		if err != nil {
+			return nil, err
+		}
+		if !chainId.IsUint64() || chainId.Uint64() != preset.ChainId {
+			return nil, fmt.Errorf("rpc is on chain %s, not %s (%d)", chainId, preset.Name, preset.ChainId)
+		}
+	}
+	return chainId, nil
+}
@@ -0,0 +1,96 @@
+// Package networks holds contract address presets for the networks EigenLVR
+// runs on, selected with `network:` in the aggregator and operator configs.
+package networks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Preset is the set of contract addresses of one network. Empty addresses
+// aren't known for the network and must be configured explicitly.
+type Preset struct {
+	Name    string
+	ChainId uint64
+
+	// EigenLVR AVS contracts
+	RegistryCoordinatorAddress    string
+	OperatorStateRetrieverAddress string
+	ServiceManagerAddress         string
+
+	// EigenLayer core contracts
+	DelegationManagerAddress  string
+	RewardsCoordinatorAddress string
+
+	// Venues
+	UniswapV3FactoryAddress string
+
+	// ExplorerUrl is the block explorer task APIs link to
+	ExplorerUrl string
+}
+
+// The AVS contracts are filled in as EigenLVR is deployed to each network
+var presets = map[string]Preset{
+	"mainnet": {
+		Name:                      "mainnet",
+		ChainId:                   1,
+		DelegationManagerAddress:  "0x39053D51B77DC0d36036Fc1fCc8Cb819df8Ef37A",
+		RewardsCoordinatorAddress: "0x7750d328b314EfFa365A0402CcfD489B80B0adda",
+		UniswapV3FactoryAddress:   "0x1F98431c8aD98523631AE4a59f267346ea31F984",
+		ExplorerUrl:               "https://etherscan.io",
+	},
+	"holesky": {
+		Name:                      "holesky",
+		ChainId:                   17000,
+		DelegationManagerAddress:  "0xA44151489861Fe9e3055d95adC98FbD462B948e7",
+		RewardsCoordinatorAddress: "0xAcc1fb458a1317E886dB376Fc8141540537E68fE",
+		ExplorerUrl:               "https://holesky.etherscan.io",
+	},
+	"sepolia": {
+		Name:                    "sepolia",
+		ChainId:                 11155111,
+		UniswapV3FactoryAddress: "0x0227628f3F023bb0B980b67D528571c95c6DaC1c",
+		ExplorerUrl:             "https://sepolia.etherscan.io",
+	},
+}
+
+// Lookup returns the preset of the named network
+func Lookup(name string) (Preset, error) {
+	preset, ok := presets[strings.ToLower(name)]
+	if !ok {
+		return Preset{}, fmt.Errorf("unknown network %q, expected one of %s", name, strings.Join(Names(), ", "))
+	}
+	return preset, nil
+}
+
+// Names returns the networks with a preset, sorted
+func Names() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Fill sets an unset address from the preset. Explicitly configured addresses
+// always win; the zero address placeholder counts as unset.
+func Fill(address *string, preset string) {
+	if isUnset(*address) {
+		*address = preset
+	}
+}
+
+// Require returns an error naming the config key if the address is still unset
+func (p Preset) Require(address string, key string) error {
+	if isUnset(address) {
+		return fmt.Errorf("network %s has no preset %s, set it explicitly", p.Name, key)
+	}
+	return nil
+}
+
+func isUnset(address string) bool {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(address), "0x")
+	return strings.Trim(trimmed, "0") == ""
+}
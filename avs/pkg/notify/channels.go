@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// TelegramApiUrl is the Bot API messages are sent through
+	TelegramApiUrl = "https://api.telegram.org"
+
+	// discordMaxContent is the longest message a Discord webhook accepts
+	discordMaxContent = 2000
+)
+
+// Telegram posts messages to a chat through a bot
+type Telegram struct {
+	botToken string
+	chatId   string
+	client   *http.Client
+}
+
+func NewTelegram(botToken string, chatId string) (*Telegram, error) {
+	if botToken == "" || chatId == "" {
+		return nil, errors.New("telegram notifications require a bot token and chat id")
+	}
+	return &Telegram{
+		botToken: botToken,
+		chatId:   chatId,
+		client:   &http.Client{},
+	}, nil
+}
+
+func (t *Telegram) Name() string {
+	return "telegram"
+}
+
+func (t *Telegram) Send(ctx context.Context, message Message) error {
+	return postJSON(ctx, t.client, TelegramApiUrl+"/bot"+t.botToken+"/sendMessage", map[string]interface{}{
+		"chat_id":                  t.chatId,
+		"text":                     message.format(),
+		"disable_web_page_preview": true,
+	})
+}
+
+// Discord posts messages to a channel through a webhook
+type Discord struct {
+	webhookUrl string
+	client     *http.Client
+}
+
+func NewDiscord(webhookUrl string) (*Discord, error) {
+	if webhookUrl == "" {
+		return nil, errors.New("discord notifications require a webhook url")
+	}
+	return &Discord{
+		webhookUrl: webhookUrl,
+		client:     &http.Client{},
+	}, nil
+}
+
+func (d *Discord) Name() string {
+	return "discord"
+}
+
+func (d *Discord) Send(ctx context.Context, message Message) error {
+	content := message.format()
+	if len(content) > discordMaxContent {
+		content = content[:discordMaxContent-3] + "..."
+	}
+	return postJSON(ctx, d.client, d.webhookUrl, map[string]interface{}{
+		"content": content,
+	})
+}
+
+// postJSON posts the payload and fails on any non-2xx status. Errors never
+// include the URL, which carries the bot token or webhook secret.
+func postJSON(ctx context.Context, client *http.Client, endpoint string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("invalid notification url")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to post notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("notification returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package notify posts auction outcomes and incidents to chat channels such as
+// Telegram and Discord. Messages are queued and delivered in the background so
+// a slow or unreachable chat service never holds up aggregation.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+// Event is the kind of a message, which channels filter on
+type Event string
+
+const (
+	// EventAuctionOutcome is a task aggregated with its winner and bid
+	EventAuctionOutcome Event = "auction_outcome"
+	// EventMissedQuorum is a task that never reached its quorum thresholds
+	EventMissedQuorum Event = "missed_quorum"
+	// EventOperatorHealth is an operator going quiet, recovering or being
+	// banned, or responses disagreeing with their task's aggregate
+	EventOperatorHealth Event = "operator_health"
+	// EventParamUpdate is a runtime parameter update scheduled, cancelled or applied
+	EventParamUpdate Event = "param_update"
+	// EventShadowMismatch is a watch-only aggregator disagreeing with what the
+	// primary aggregator submitted
+	EventShadowMismatch Event = "shadow_mismatch"
+	// EventTaskChallenged is a task's aggregate challenged and reviewed
+	EventTaskChallenged Event = "task_challenged"
+)
+
+const (
+	// queueSize is how many messages may wait for delivery before new ones are dropped
+	queueSize = 256
+
+	// sendTimeout bounds delivering one message to one channel
+	sendTimeout = 10 * time.Second
+)
+
+// ParseEvent validates an event name from the config
+func ParseEvent(name string) (Event, error) {
+	switch event := Event(strings.TrimSpace(name)); event {
+	case EventAuctionOutcome, EventMissedQuorum, EventOperatorHealth, EventParamUpdate, EventShadowMismatch, EventTaskChallenged:
+		return event, nil
+	default:
+		return "", fmt.Errorf("unknown notification event %q", name)
+	}
+}
+
+// Message is one notification
+type Message struct {
+	Event Event
+	Title string
+	Text  string
+}
+
+// format renders the message as plain text
+func (m Message) format() string {
+	if m.Text == "" {
+		return m.Title
+	}
+	return m.Title + "\n" + m.Text
+}
+
+// Channel delivers messages to one chat destination
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, message Message) error
+}
+
+// route is a channel and the events it receives, all of them when events is nil
+type route struct {
+	channel Channel
+	events  map[Event]struct{}
+}
+
+func (r route) wants(event Event) bool {
+	if r.events == nil {
+		return true
+	}
+	_, ok := r.events[event]
+	return ok
+}
+
+// Notifier fans messages out to the channels subscribed to their event
+type Notifier struct {
+	routes []route
+	queue  chan Message
+	logger logging.Logger
+}
+
+func NewNotifier(logger logging.Logger) *Notifier {
+	return &Notifier{
+		queue:  make(chan Message, queueSize),
+		logger: logger,
+	}
+}
+
+// AddChannel subscribes the channel to the events, or to every event when
+// none are given. Channels must be added before Run.
+func (n *Notifier) AddChannel(channel Channel, events []Event) {
+	r := route{channel: channel}
+	if len(events) > 0 {
+		r.events = make(map[Event]struct{}, len(events))
+		for _, event := range events {
+			r.events[event] = struct{}{}
+		}
+	}
+	n.routes = append(n.routes, r)
+}
+
+// Notify queues the message for delivery. It never blocks; when the queue is
+// full the message is dropped and logged.
+func (n *Notifier) Notify(message Message) {
+	select {
+	case n.queue <- message:
+	default:
+		n.logger.Warn("Notification queue full, dropping message", "event", message.Event, "title", message.Title)
+	}
+}
+
+// Run delivers queued messages until ctx is done
+func (n *Notifier) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message := <-n.queue:
+			n.deliver(ctx, message)
+		}
+	}
+}
+
+func (n *Notifier) deliver(ctx context.Context, message Message) {
+	for _, r := range n.routes {
+		if !r.wants(message.Event) {
+			continue
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err := r.channel.Send(sendCtx, message)
+		cancel()
+		if err != nil {
+			n.logger.Warn("Failed to send notification",
+				"channel", r.channel.Name(),
+				"event", message.Event,
+				"error", err,
+			)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Package passphrase supplies the passphrases of encrypted key files: from a
+// file, as mounted by container secret stores, from an environment variable,
+// or from a prompt when stdin is a terminal.
+package passphrase
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	// EcdsaEnv holds the passphrase of encrypted ECDSA keystores
+	EcdsaEnv = "EIGENLVR_ECDSA_KEY_PASSWORD"
+	// BlsEnv holds the passphrase of BLS keystores
+	BlsEnv = "EIGENLVR_BLS_KEY_PASSWORD"
+)
+
+// ErrUnavailable is returned when no passphrase is configured and stdin is
+// not a terminal to prompt on
+var ErrUnavailable = errors.New("no passphrase configured")
+
+// Func supplies the passphrase of the key file at keyPath
+type Func func(keyPath string) (string, error)
+
+// Lookup reads the passphrase from file when it is set, else from the
+// environment variable, else prompts for it on the terminal
+func Lookup(file, envVar string) Func {
+	return func(keyPath string) (string, error) {
+		if file != "" {
+			return ReadFile(file)
+		}
+		if password, ok := os.LookupEnv(envVar); ok {
+			return password, nil
+		}
+		if !IsTerminal() {
+			return "", fmt.Errorf("%w for %s, set %s or a passphrase file", ErrUnavailable, keyPath, envVar)
+		}
+		return Prompt(fmt.Sprintf("Passphrase for %s: ", keyPath))
+	}
+}
+
+// ReadFile reads a passphrase file, dropping the trailing newline editors and
+// echo add
+func ReadFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// IsTerminal reports whether stdin is a terminal that can be prompted on
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// Prompt reads a passphrase from the terminal without echoing it
+func Prompt(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(password), nil
+}
+
+// PromptNew prompts for a new passphrase twice and checks that both match
+func PromptNew(prompt string) (string, error) {
+	password, err := Prompt(prompt)
+	if err != nil {
+		return "", err
+	}
+	confirmation, err := Prompt("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if password != confirmation {
+		return "", errors.New("passphrases do not match")
+	}
+	return password, nil
+}
@@ -0,0 +1,87 @@
+// Package poolmetrics maps pool ids to Prometheus label values while keeping
+// the number of distinct values bounded.
+package poolmetrics
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// LabelName is the label pool metrics are partitioned by
+	LabelName = "pool"
+
+	// OtherLabel is used for pools beyond the cap or outside the allowlist
+	OtherLabel = "other"
+	// UnknownLabel is used when the task's pool id isn't known
+	UnknownLabel = "unknown"
+
+	// DefaultMaxPools is the cap used when none is configured
+	DefaultMaxPools = 20
+)
+
+// Labeler hands out one label per pool. With an allowlist only the listed
+// pools get their own label; otherwise the first maxPools pools seen do.
+// Everything else shares OtherLabel.
+type Labeler struct {
+	allowlist map[common.Hash]struct{}
+	maxPools  int
+
+	mu   sync.Mutex
+	seen map[common.Hash]struct{}
+}
+
+// NewLabeler parses the allowlist of hex pool ids. A maxPools of zero or less
+// uses DefaultMaxPools.
+func NewLabeler(allowlist []string, maxPools int) (*Labeler, error) {
+	if maxPools <= 0 {
+		maxPools = DefaultMaxPools
+	}
+
+	labeler := &Labeler{
+		maxPools: maxPools,
+		seen:     make(map[common.Hash]struct{}),
+	}
+
+	if len(allowlist) > 0 {
+		labeler.allowlist = make(map[common.Hash]struct{}, len(allowlist))
+		for _, poolId := range allowlist {
+			trimmed := strings.TrimPrefix(strings.TrimSpace(poolId), "0x")
+			if len(trimmed) != 2*common.HashLength {
+				return nil, fmt.Errorf("invalid pool id %q", poolId)
+			}
+			labeler.allowlist[common.HexToHash(trimmed)] = struct{}{}
+		}
+	}
+
+	return labeler, nil
+}
+
+// Label returns the label value to record the pool under
+func (l *Labeler) Label(poolId common.Hash) string {
+	if poolId == (common.Hash{}) {
+		return UnknownLabel
+	}
+
+	if l.allowlist != nil {
+		if _, ok := l.allowlist[poolId]; ok {
+			return poolId.Hex()
+		}
+		return OtherLabel
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.seen[poolId]; ok {
+		return poolId.Hex()
+	}
+	if len(l.seen) >= l.maxPools {
+		return OtherLabel
+	}
+	l.seen[poolId] = struct{}{}
+	return poolId.Hex()
+}
@@ -0,0 +1,151 @@
+// Package poolregistry lists the pools the AVS runs auctions for, each with
+// its own auction parameters. Operators and the aggregator load the same
+// pools file, so they agree on which pools have auctions and how each one is
+// settled.
+package poolregistry
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/configfile"
+)
+
+// ErrPoolNotListed is returned for pools missing from the registry
+var ErrPoolNotListed = errors.New("pool is not listed in the pool registry")
+
+// Pool is a pool's auction parameters. Zero values fall back to the
+// operator's or aggregator's own config.
+type Pool struct {
+	PoolId common.Hash `json:"poolId"`
+	Name   string      `json:"name,omitempty"`
+	// AuctionDurationBlocks is how many blocks ahead of its block a pool's
+	// auction takes bids
+	AuctionDurationBlocks uint64 `json:"auctionDurationBlocks,omitempty"`
+	// ReservePrice is the smallest winning bid in wei
+	ReservePrice *big.Int `json:"reservePrice,omitempty"`
+	// FeeRecipient receives the protocol's share of the pool's winning bids
+	FeeRecipient common.Address `json:"feeRecipient,omitempty"`
+	// QuorumThresholdPercentage raises the signed stake percentage the
+	// pool's tasks were created with; it never lowers it
+	QuorumThresholdPercentage uint32 `json:"quorumThresholdPercentage,omitempty"`
+}
+
+// poolConfig is a pool as written in the pools file
+type poolConfig struct {
+	PoolId                    string `json:"pool_id"`
+	Name                      string `json:"name"`
+	AuctionDurationBlocks     uint64 `json:"auction_duration_blocks"`
+	ReservePrice              string `json:"reserve_price"`
+	FeeRecipient              string `json:"fee_recipient"`
+	QuorumThresholdPercentage uint32 `json:"quorum_threshold_percentage"`
+}
+
+// file is the pools file, a top-level "pools" list
+type file struct {
+	Pools []poolConfig `json:"pools"`
+}
+
+// Registry is a fixed set of pools. A nil Registry lists every pool, with
+// no parameters of its own.
+type Registry struct {
+	pools map[common.Hash]Pool
+	order []common.Hash
+}
+
+// Load reads the pools file at path, YAML or JSON, returning any keys that
+// match no field alongside the registry
+func Load(path string) (*Registry, []configfile.UnknownField, error) {
+	var f file
+	unknown, err := configfile.Load(path, "", &f)
+	if err != nil {
+		return nil, unknown, err
+	}
+
+	pools := make([]Pool, 0, len(f.Pools))
+	for i, entry := range f.Pools {
+		pool, err := entry.parse()
+		if err != nil {
+			return nil, unknown, fmt.Errorf("%s: pools[%d]: %w", path, i, err)
+		}
+		pools = append(pools, pool)
+	}
+	registry, err := New(pools)
+	if err != nil {
+		return nil, unknown, fmt.Errorf("%s: %w", path, err)
+	}
+	return registry, unknown, nil
+}
+
+func (c poolConfig) parse() (Pool, error) {
+	raw := common.FromHex(c.PoolId)
+	if len(raw) != common.HashLength {
+		return Pool{}, fmt.Errorf("invalid pool id %q", c.PoolId)
+	}
+	pool := Pool{
+		PoolId:                    common.BytesToHash(raw),
+		Name:                      c.Name,
+		AuctionDurationBlocks:     c.AuctionDurationBlocks,
+		QuorumThresholdPercentage: c.QuorumThresholdPercentage,
+	}
+	if c.ReservePrice != "" {
+		reservePrice, ok := new(big.Int).SetString(c.ReservePrice, 10)
+		if !ok || reservePrice.Sign() < 0 {
+			return Pool{}, fmt.Errorf("invalid reserve price %q", c.ReservePrice)
+		}
+		pool.ReservePrice = reservePrice
+	}
+	if c.FeeRecipient != "" {
+		if !common.IsHexAddress(c.FeeRecipient) {
+			return Pool{}, fmt.Errorf("invalid fee recipient %q", c.FeeRecipient)
+		}
+		pool.FeeRecipient = common.HexToAddress(c.FeeRecipient)
+	}
+	return pool, nil
+}
+
+// New returns a registry of the pools, which must be listed once each
+func New(pools []Pool) (*Registry, error) {
+	registry := &Registry{pools: make(map[common.Hash]Pool, len(pools))}
+	for _, pool := range pools {
+		if pool.PoolId == (common.Hash{}) {
+			return nil, errors.New("pool id is required")
+		}
+		if _, ok := registry.pools[pool.PoolId]; ok {
+			return nil, fmt.Errorf("pool %s is listed twice", pool.PoolId.Hex())
+		}
+		if pool.QuorumThresholdPercentage > 100 {
+			return nil, fmt.Errorf("pool %s: quorum threshold %d%% is over 100%%", pool.PoolId.Hex(), pool.QuorumThresholdPercentage)
+		}
+		registry.pools[pool.PoolId] = pool
+		registry.order = append(registry.order, pool.PoolId)
+	}
+	return registry, nil
+}
+
+// Pool returns the pool's parameters, or ErrPoolNotListed
+func (r *Registry) Pool(poolId common.Hash) (Pool, error) {
+	if r == nil {
+		return Pool{PoolId: poolId}, nil
+	}
+	pool, ok := r.pools[poolId]
+	if !ok {
+		return Pool{}, fmt.Errorf("%w: %s", ErrPoolNotListed, poolId.Hex())
+	}
+	return pool, nil
+}
+
+// Pools returns the listed pools in file order, nil for a nil Registry
+func (r *Registry) Pools() []Pool {
+	if r == nil {
+		return nil
+	}
+	pools := make([]Pool, len(r.order))
+	for i, poolId := range r.order {
+		pools[i] = r.pools[poolId]
+	}
+	return pools
+}
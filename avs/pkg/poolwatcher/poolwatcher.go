@@ -0,0 +1,276 @@
+// Package poolwatcher tracks the state of Uniswap v4 pools. Each pool's
+// slot0 and liquidity are read from the PoolManager's storage, and kept
+// current from the Swap events it emits, so the auction logic can price a
+// task's block against the pool as it stands rather than an assumed state.
+package poolwatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/eigenlvr/avs/pkg/logwatcher"
+	"github.com/eigenlvr/avs/pkg/venues"
+)
+
+const poolManagerAbi = `[
+	{"type":"function","name":"extsload","stateMutability":"view","inputs":[{"name":"startSlot","type":"bytes32"},{"name":"nSlots","type":"uint256"}],"outputs":[{"name":"","type":"bytes32[]"}]},
+	{"type":"event","name":"Swap","anonymous":false,"inputs":[{"name":"id","type":"bytes32","indexed":true},{"name":"sender","type":"address","indexed":true},{"name":"amount0","type":"int128","indexed":false},{"name":"amount1","type":"int128","indexed":false},{"name":"sqrtPriceX96","type":"uint160","indexed":false},{"name":"liquidity","type":"uint128","indexed":false},{"name":"tick","type":"int24","indexed":false},{"name":"fee","type":"uint24","indexed":false}]}
+]`
+
+// poolsSlot is the PoolManager storage slot of its pools mapping
+const poolsSlot = 6
+
+// Pool state slots, relative to a pool's base slot
+const (
+	slot0Offset     = 0
+	liquidityOffset = 3
+	stateSlots      = 4
+)
+
+var (
+	// SwapTopic is the PoolManager's Swap event, which carries the pool's
+	// price, tick and liquidity after the swap
+	SwapTopic = crypto.Keccak256Hash([]byte("Swap(bytes32,address,int128,int128,uint160,uint128,int24,uint24)"))
+	// ModifyLiquidityTopic is emitted when liquidity is added or removed,
+	// which can change the pool's in-range liquidity
+	ModifyLiquidityTopic = crypto.Keccak256Hash([]byte("ModifyLiquidity(bytes32,address,int24,int24,int256,bytes32)"))
+	// InitializeTopic is emitted when a pool gets its first price
+	InitializeTopic = crypto.Keccak256Hash([]byte("Initialize(bytes32,address,address,uint24,int24,address,uint160,int24)"))
+
+	// ErrUnknownPool is returned for pools that aren't watched
+	ErrUnknownPool = errors.New("pool is not watched")
+	// ErrNotInitialized is returned for pools the PoolManager has no price for
+	ErrNotInitialized = errors.New("pool is not initialized")
+
+	parsedPoolManagerAbi = mustParseAbi(poolManagerAbi)
+)
+
+// State is a pool's price and liquidity as of a block
+type State struct {
+	PoolId       common.Hash `json:"poolId"`
+	SqrtPriceX96 *big.Int    `json:"sqrtPriceX96"`
+	Tick         int32       `json:"tick"`
+	// Price is currency1 base units per currency0 base unit, scaled by 1e18
+	Price     *big.Int  `json:"price"`
+	Liquidity *big.Int  `json:"liquidity"`
+	LpFee     uint32    `json:"lpFee"`
+	Block     uint64    `json:"block"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Backend reads the PoolManager's storage and logs
+type Backend interface {
+	bind.ContractCaller
+	logwatcher.LogReader
+}
+
+// Config configures a Watcher
+type Config struct {
+	PoolManager common.Address
+	Pools       []common.Hash
+	// WsUrl is optional; without it Swap events are polled for
+	WsUrl        string
+	PollInterval time.Duration
+}
+
+// Watcher keeps the state of a set of pools current
+type Watcher struct {
+	config  Config
+	manager *bind.BoundContract
+	events  *logwatcher.Watcher
+	logger  logging.Logger
+
+	mu     sync.RWMutex
+	states map[common.Hash]State
+}
+
+func NewWatcher(config Config, backend Backend, reg prometheus.Registerer, logger logging.Logger) (*Watcher, error) {
+	if config.PoolManager == (common.Address{}) {
+		return nil, errors.New("pool manager address is required")
+	}
+	if len(config.Pools) == 0 {
+		return nil, errors.New("no pools to watch")
+	}
+
+	poolTopics := make([]common.Hash, len(config.Pools))
+	copy(poolTopics, config.Pools)
+
+	return &Watcher{
+		config:  config,
+		manager: bind.NewBoundContract(config.PoolManager, parsedPoolManagerAbi, backend, nil, nil),
+		events: logwatcher.NewWatcher(
+			logwatcher.Config{
+				Name:         "pools",
+				Addresses:    []common.Address{config.PoolManager},
+				Topics:       [][]common.Hash{{SwapTopic, ModifyLiquidityTopic, InitializeTopic}, poolTopics},
+				WsUrl:        config.WsUrl,
+				PollInterval: config.PollInterval,
+			},
+			backend,
+			// Pool state is re-read on start, so missed events don't matter
+			logwatcher.NewFileCheckpoint(""),
+			reg,
+			logger,
+		),
+		logger: logger.With("component", "pool-watcher"),
+		states: make(map[common.Hash]State),
+	}, nil
+}
+
+// Run reads every pool's state and then follows the PoolManager's events
+// until ctx is done
+func (w *Watcher) Run(ctx context.Context) error {
+	for _, poolId := range w.config.Pools {
+		if err := w.refresh(ctx, poolId, 0); err != nil {
+			w.logger.Warn("Failed to read pool state", "poolId", poolId.Hex(), "error", err)
+		}
+	}
+
+	return w.events.Run(ctx, func(log gethtypes.Log) error {
+		if len(log.Topics) < 2 {
+			return nil
+		}
+		poolId := log.Topics[1]
+		if log.Topics[0] == SwapTopic {
+			if err := w.applySwap(log); err != nil {
+				w.logger.Warn("Failed to decode swap", "poolId", poolId.Hex(), "txHash", log.TxHash.Hex(), "error", err)
+			}
+			return nil
+		}
+		// Other events don't carry the resulting state, so it is read again
+		if err := w.refresh(ctx, poolId, log.BlockNumber); err != nil {
+			w.logger.Warn("Failed to read pool state", "poolId", poolId.Hex(), "error", err)
+		}
+		return nil
+	})
+}
+
+// State returns the pool's latest known state
+func (w *Watcher) State(poolId common.Hash) (State, error) {
+	w.mu.RLock()
+	state, ok := w.states[poolId]
+	w.mu.RUnlock()
+	if ok {
+		return state, nil
+	}
+	for _, watched := range w.config.Pools {
+		if watched == poolId {
+			return State{}, ErrNotInitialized
+		}
+	}
+	return State{}, ErrUnknownPool
+}
+
+// Pools returns the watched pool ids
+func (w *Watcher) Pools() []common.Hash {
+	return w.config.Pools
+}
+
+// refresh reads the pool's slot0 and liquidity from the PoolManager's
+// storage. block is the block of the event that prompted the read, or 0.
+func (w *Watcher) refresh(ctx context.Context, poolId common.Hash, block uint64) error {
+	var out []interface{}
+	err := w.manager.Call(&bind.CallOpts{Context: ctx}, &out, "extsload", stateSlot(poolId), big.NewInt(stateSlots))
+	if err != nil {
+		return fmt.Errorf("failed to call extsload: %w", err)
+	}
+	slots := *abi.ConvertType(out[0], new([][32]byte)).(*[][32]byte)
+	if len(slots) != stateSlots {
+		return fmt.Errorf("extsload returned %d slots, expected %d", len(slots), stateSlots)
+	}
+
+	slot0 := new(big.Int).SetBytes(slots[slot0Offset][:])
+	sqrtPriceX96 := new(big.Int).And(slot0, maxUint160)
+	if sqrtPriceX96.Sign() == 0 {
+		return nil
+	}
+	w.store(State{
+		PoolId:       poolId,
+		SqrtPriceX96: sqrtPriceX96,
+		Tick:         int24(bits24(slot0, 160)),
+		Liquidity:    new(big.Int).SetBytes(slots[liquidityOffset][16:]),
+		LpFee:        bits24(slot0, 208),
+		Block:        block,
+	})
+	return nil
+}
+
+// applySwap sets the pool's state from a Swap event
+func (w *Watcher) applySwap(log gethtypes.Log) error {
+	var swap struct {
+		Amount0      *big.Int
+		Amount1      *big.Int
+		SqrtPriceX96 *big.Int
+		Liquidity    *big.Int
+		Tick         *big.Int
+		Fee          *big.Int
+	}
+	if err := parsedPoolManagerAbi.UnpackIntoInterface(&swap, "Swap", log.Data); err != nil {
+		return err
+	}
+	w.store(State{
+		PoolId:       log.Topics[1],
+		SqrtPriceX96: swap.SqrtPriceX96,
+		Tick:         int32(swap.Tick.Int64()),
+		Liquidity:    swap.Liquidity,
+		LpFee:        uint32(swap.Fee.Uint64()),
+		Block:        log.BlockNumber,
+	})
+	return nil
+}
+
+// store keeps the state unless a later block's state is already known
+func (w *Watcher) store(state State) {
+	state.Price = venues.PriceFromSqrtPriceX96(state.SqrtPriceX96)
+	state.UpdatedAt = time.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if current, ok := w.states[state.PoolId]; ok && state.Block != 0 && current.Block > state.Block {
+		return
+	}
+	w.states[state.PoolId] = state
+}
+
+var maxUint160 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+
+// stateSlot is the base storage slot of the pool's state: the pools mapping
+// entry for its id
+func stateSlot(poolId common.Hash) common.Hash {
+	return crypto.Keccak256Hash(poolId[:], common.BigToHash(big.NewInt(poolsSlot)).Bytes())
+}
+
+// bits24 returns the 24 bits of v starting at bit offset
+func bits24(v *big.Int, offset uint) uint32 {
+	field := new(big.Int).Rsh(v, offset)
+	return uint32(field.And(field, big.NewInt(0xffffff)).Uint64())
+}
+
+// int24 sign-extends a 24-bit value
+func int24(raw uint32) int32 {
+	if raw&0x800000 != 0 {
+		return int32(raw) - 1<<24
+	}
+	return int32(raw)
+}
+
+func mustParseAbi(contractAbi string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(contractAbi))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,127 @@
+// Package pricefeed prices the pools auctions run for. Each pool is mapped
+// onto its token pair, and the pair's mid-price is aggregated from the
+// configured venues: Chainlink feeds, centralized exchange order books and
+// other on-chain markets. How far the pool's own price is from that mid is
+// the arbitrage a block's LVR auction sells.
+package pricefeed
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/venues"
+)
+
+// DefaultMaxAge is how long a pool's mid-price is reused when Config.MaxAge
+// is unset. Tasks for the same pool arrive every block, so a mid-price
+// younger than a block is good for all of them.
+const DefaultMaxAge = 2 * time.Second
+
+// ErrUnknownPool is returned for pools the feed has no token pair for
+var ErrUnknownPool = errors.New("pool has no configured token pair")
+
+// Pool maps a pool id onto its token pair
+type Pool struct {
+	PoolId common.Hash
+	Pair   venues.Pair
+}
+
+// Config configures a Feed
+type Config struct {
+	Pools  []Pool
+	MaxAge time.Duration
+}
+
+// MidPrice is a pool's aggregated off-pool price. Price uses the venues
+// convention: Token1 base units per Token0 base unit, scaled by 1e18.
+type MidPrice struct {
+	PoolId common.Hash `json:"poolId"`
+	Pair   venues.Pair `json:"pair"`
+	Price  *big.Int    `json:"price"`
+	// SpreadBps is the spread between the highest and lowest source price
+	// relative to Price
+	SpreadBps int64     `json:"spreadBps"`
+	Sources   int       `json:"sources"`
+	SampledAt time.Time `json:"sampledAt"`
+	// ObservedAt is when the oldest quote behind Price was observed at its
+	// source, so feeds that stopped updating show up as a growing age
+	ObservedAt time.Time `json:"observedAt"`
+}
+
+// DivergenceBps returns how far the pool's own price is from the mid, in
+// basis points. It is negative when the pool is priced below the mid.
+func (m MidPrice) DivergenceBps(poolPrice *big.Int) int64 {
+	return venues.Reference{Price: m.Price}.DeviationBps(poolPrice)
+}
+
+// Feed serves per-pool mid-prices from a venue sampler
+type Feed struct {
+	sampler *venues.Sampler
+	pools   map[common.Hash]venues.Pair
+	maxAge  time.Duration
+
+	mu     sync.Mutex
+	prices map[common.Hash]MidPrice
+}
+
+func NewFeed(config Config, sampler *venues.Sampler) *Feed {
+	if config.MaxAge <= 0 {
+		config.MaxAge = DefaultMaxAge
+	}
+	pools := make(map[common.Hash]venues.Pair, len(config.Pools))
+	for _, pool := range config.Pools {
+		pools[pool.PoolId] = pool.Pair
+	}
+	return &Feed{
+		sampler: sampler,
+		pools:   pools,
+		maxAge:  config.MaxAge,
+		prices:  make(map[common.Hash]MidPrice),
+	}
+}
+
+// MidPrice returns the pool's mid-price, the median across every venue
+// quoting its pair, sampling the venues again once the last one is older
+// than the maximum age
+func (f *Feed) MidPrice(ctx context.Context, poolId common.Hash) (MidPrice, error) {
+	pair, ok := f.pools[poolId]
+	if !ok {
+		return MidPrice{}, ErrUnknownPool
+	}
+
+	f.mu.Lock()
+	cached, ok := f.prices[poolId]
+	f.mu.Unlock()
+	if ok && time.Since(cached.SampledAt) <= f.maxAge {
+		return cached, nil
+	}
+
+	reference, err := f.sampler.Reference(ctx, pair)
+	if err != nil {
+		return MidPrice{}, err
+	}
+	mid := MidPrice{
+		PoolId:    poolId,
+		Pair:      pair,
+		Price:     reference.Price,
+		SpreadBps: reference.SpreadBps,
+		Sources:   len(reference.Quotes),
+		SampledAt: time.Now(),
+	}
+	mid.ObservedAt = mid.SampledAt
+	for _, quote := range reference.Quotes {
+		if !quote.SampledAt.IsZero() && quote.SampledAt.Before(mid.ObservedAt) {
+			mid.ObservedAt = quote.SampledAt
+		}
+	}
+
+	f.mu.Lock()
+	f.prices[poolId] = mid
+	f.mu.Unlock()
+	return mid, nil
+}
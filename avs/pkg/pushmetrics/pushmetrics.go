@@ -0,0 +1,91 @@
+// Package pushmetrics reports the metrics of one-shot commands, such as
+// claim-rewards, to a Prometheus Pushgateway. The daemons are scraped, but a
+// command that exits within seconds can only be observed by pushing.
+package pushmetrics
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// pushTimeout bounds pushing a job's metrics
+const pushTimeout = 10 * time.Second
+
+// Job collects the metrics of one run of a command
+type Job struct {
+	name     string
+	registry *prometheus.Registry
+	started  time.Time
+
+	duration    prometheus.Gauge
+	success     prometheus.Gauge
+	lastSuccess prometheus.Gauge
+}
+
+// NewJob starts timing a run of the named command
+func NewJob(name string) *Job {
+	j := &Job{
+		name:     name,
+		registry: prometheus.NewRegistry(),
+		started:  time.Now(),
+		duration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Name:      "job_duration_seconds",
+			Help:      "How long the last run of the command took",
+		}),
+		success: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Name:      "job_success",
+			Help:      "Whether the last run of the command succeeded (1) or failed (0)",
+		}),
+		lastSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Name:      "job_last_success_timestamp_seconds",
+			Help:      "Unix time the command last succeeded",
+		}),
+	}
+	j.registry.MustRegister(j.duration, j.success)
+	return j
+}
+
+// Registry is where the command registers metrics of its own
+func (j *Job) Registry() *prometheus.Registry {
+	return j.registry
+}
+
+// Push records the run's outcome and pushes every metric to the gateway,
+// grouped by job and instance. Metrics are added to the group rather than
+// replacing it, so the last success timestamp survives failed runs. Push is
+// called once, as the command finishes.
+func (j *Job) Push(ctx context.Context, gatewayUrl string, err error) error {
+	j.duration.Set(time.Since(j.started).Seconds())
+	if err == nil {
+		j.success.Set(1)
+		j.lastSuccess.SetToCurrentTime()
+		j.registry.MustRegister(j.lastSuccess)
+	} else {
+		j.success.Set(0)
+	}
+
+	instance, hostErr := os.Hostname()
+	if hostErr != nil {
+		instance = "unknown"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pushTimeout)
+	defer cancel()
+
+	pushErr := push.New(gatewayUrl, j.name).
+		Grouping("instance", instance).
+		Gatherer(j.registry).
+		AddContext(ctx)
+	if pushErr != nil {
+		return fmt.Errorf("failed to push metrics to %s: %w", gatewayUrl, pushErr)
+	}
+	return nil
+}
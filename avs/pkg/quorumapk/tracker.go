@@ -0,0 +1,424 @@
+package quorumapk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrUnknownQuorum is returned when an aggregate references a quorum with no tracked APK
+	ErrUnknownQuorum = errors.New("unknown quorum")
+
+	// ErrUnknownSigner is returned when a signer has no registered pubkey or is
+	// not a member of any of the aggregate's quorums
+	ErrUnknownSigner = errors.New("unknown signer")
+
+	// ErrApkMismatch is returned when aggregate pubkeys that must agree do not
+	ErrApkMismatch = errors.New("aggregate pubkey mismatch")
+
+	// ErrInvalidSignature is returned when an aggregate or operator signature
+	// does not verify
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// registryCoordinatorAbi is the subset of the RegistryCoordinator used to find the BLSApkRegistry
+const registryCoordinatorAbi = `[
+	{"type":"function","name":"blsApkRegistry","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address"}]}
+]`
+
+// blsApkRegistryAbi is the subset of the BLSApkRegistry used to read quorum APKs
+const blsApkRegistryAbi = `[
+	{"type":"function","name":"getApk","stateMutability":"view","inputs":[{"name":"quorumNumber","type":"uint8"}],"outputs":[{"name":"","type":"tuple","components":[{"name":"X","type":"uint256"},{"name":"Y","type":"uint256"}]}]}
+]`
+
+// g1Point matches the BN254.G1Point tuple returned by the BLSApkRegistry
+type g1Point struct {
+	X *big.Int
+	Y *big.Int
+}
+
+// Operator is a registered operator and the quorums it belongs to
+type Operator struct {
+	OperatorId types.OperatorId
+	Address    common.Address
+	Quorums    []types.QuorumNum
+}
+
+// PubkeySource returns the BLS pubkeys registered between two blocks
+type PubkeySource interface {
+	QueryExistingRegisteredOperatorPubKeys(
+		ctx context.Context,
+		startBlock *big.Int,
+		stopBlock *big.Int,
+		blockRange *big.Int,
+	) ([]types.OperatorAddr, []types.OperatorPubkeys, error)
+}
+
+// Tracker maintains the aggregate pubkey of every quorum together with the
+// pubkeys of its members, so an aggregate signature can be checked off-chain
+// the same way the BLSSignatureChecker checks it on-chain
+type Tracker struct {
+	pubkeySource   PubkeySource
+	blsApkRegistry *bind.BoundContract
+
+	mu          sync.RWMutex
+	pubkeys     map[common.Address]types.OperatorPubkeys
+	operators   map[types.OperatorId]Operator
+	members     map[types.QuorumNum]map[types.OperatorId]struct{}
+	apks        map[types.QuorumNum]*bls.G1Point
+	syncedBlock uint64
+}
+
+// NewTracker binds the BLSApkRegistry, whose address is read from the RegistryCoordinator
+func NewTracker(
+	ctx context.Context,
+	pubkeySource PubkeySource,
+	registryCoordinatorAddr common.Address,
+	backend bind.ContractBackend,
+) (*Tracker, error) {
+	registryCoordinator, err := bindContract(registryCoordinatorAddr, registryCoordinatorAbi, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind registry coordinator: %w", err)
+	}
+
+	var out []interface{}
+	if err := registryCoordinator.Call(&bind.CallOpts{Context: ctx}, &out, "blsApkRegistry"); err != nil {
+		return nil, fmt.Errorf("failed to get bls apk registry address: %w", err)
+	}
+	blsApkRegistryAddr := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	blsApkRegistry, err := bindContract(blsApkRegistryAddr, blsApkRegistryAbi, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind bls apk registry: %w", err)
+	}
+
+	return &Tracker{
+		pubkeySource:   pubkeySource,
+		blsApkRegistry: blsApkRegistry,
+		pubkeys:        make(map[common.Address]types.OperatorPubkeys),
+		operators:      make(map[types.OperatorId]Operator),
+		members:        make(map[types.QuorumNum]map[types.OperatorId]struct{}),
+		apks:           make(map[types.QuorumNum]*bls.G1Point),
+	}, nil
+}
+
+// Refresh reads pubkeys registered since the last refresh and the current APK
+// of every quorum, and replaces the quorum membership with operators. The
+// on-chain APKs are authoritative; if the members' pubkeys don't sum to them
+// the tracker is still updated and the returned error wraps ErrApkMismatch.
+func (t *Tracker) Refresh(ctx context.Context, operators []Operator, currentBlock uint64) error {
+	t.mu.RLock()
+	fromBlock := t.syncedBlock
+	t.mu.RUnlock()
+
+	var addresses []types.OperatorAddr
+	var pubkeys []types.OperatorPubkeys
+	if currentBlock >= fromBlock {
+		var err error
+		addresses, pubkeys, err = t.pubkeySource.QueryExistingRegisteredOperatorPubKeys(
+			ctx,
+			new(big.Int).SetUint64(fromBlock),
+			new(big.Int).SetUint64(currentBlock),
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to query operator pubkeys: %w", err)
+		}
+	}
+
+	members := make(map[types.QuorumNum]map[types.OperatorId]struct{})
+	for _, operator := range operators {
+		for _, quorum := range operator.Quorums {
+			if members[quorum] == nil {
+				members[quorum] = make(map[types.OperatorId]struct{})
+			}
+			members[quorum][operator.OperatorId] = struct{}{}
+		}
+	}
+
+	apks := make(map[types.QuorumNum]*bls.G1Point, len(members))
+	for quorum := range members {
+		apk, err := t.getApk(ctx, quorum)
+		if err != nil {
+			return err
+		}
+		apks[quorum] = apk
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, address := range addresses {
+		t.pubkeys[address] = pubkeys[i]
+	}
+	t.operators = make(map[types.OperatorId]Operator, len(operators))
+	for _, operator := range operators {
+		t.operators[operator.OperatorId] = operator
+	}
+	t.members = members
+	t.apks = apks
+	t.syncedBlock = currentBlock + 1
+
+	var mismatched []string
+	for quorum, apk := range apks {
+		computed, err := t.sumG1(members[quorum])
+		if err != nil || !computed.Equal(apk.G1Affine) {
+			mismatched = append(mismatched, fmt.Sprint(quorum))
+		}
+	}
+	if len(mismatched) > 0 {
+		return fmt.Errorf("%w: member pubkeys don't sum to the on-chain apk of quorums %s", ErrApkMismatch, strings.Join(mismatched, ", "))
+	}
+
+	return nil
+}
+
+// QuorumApk returns the current aggregate G1 pubkey of the quorum
+func (t *Tracker) QuorumApk(quorum types.QuorumNum) (*bls.G1Point, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	apk, ok := t.apks[quorum]
+	if !ok {
+		return nil, false
+	}
+	return bls.NewZeroG1Point().Add(apk), true
+}
+
+// Quorums returns the quorums with a tracked APK
+func (t *Tracker) Quorums() types.QuorumNums {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	quorums := make(types.QuorumNums, 0, len(t.apks))
+	for quorum := range t.apks {
+		quorums = append(quorums, quorum)
+	}
+	sort.Slice(quorums, func(i, j int) bool { return quorums[i] < quorums[j] })
+	return quorums
+}
+
+// VerifyAggregate checks an aggregate signature over message by signers the way
+// the BLSSignatureChecker would: the G1 APK is each quorum's APK minus its
+// non-signers, and it must match the signers' G2 APK, which must verify the
+// signature. A nil error means the contract would accept the signature.
+func (t *Tracker) VerifyAggregate(
+	quorums types.QuorumNums,
+	signers []types.OperatorId,
+	signature *bls.Signature,
+	message [32]byte,
+) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	signerSet := make(map[types.OperatorId]struct{}, len(signers))
+	for _, signer := range signers {
+		signerSet[signer] = struct{}{}
+	}
+
+	apkG1 := bls.NewZeroG1Point()
+	apkG2 := bls.NewZeroG2Point()
+	inAnyQuorum := make(map[types.OperatorId]bool, len(signers))
+
+	for _, quorum := range quorums {
+		quorumApk, ok := t.apks[quorum]
+		if !ok {
+			return fmt.Errorf("%w: %d", ErrUnknownQuorum, quorum)
+		}
+		apkG1.Add(quorumApk)
+
+		for operatorId := range t.members[quorum] {
+			pubkeys, err := t.operatorPubkeys(operatorId)
+			if err != nil {
+				return err
+			}
+
+			if _, signed := signerSet[operatorId]; signed {
+				apkG2.Add(pubkeys.G2Pubkey)
+				inAnyQuorum[operatorId] = true
+			} else {
+				apkG1.Sub(pubkeys.G1Pubkey)
+			}
+		}
+	}
+
+	for _, signer := range signers {
+		if !inAnyQuorum[signer] {
+			return fmt.Errorf("%w: %x is not in any of the quorums", ErrUnknownSigner, signer[:])
+		}
+	}
+
+	equivalent, err := apkG1.VerifyEquivalence(apkG2)
+	if err != nil {
+		return fmt.Errorf("failed to compare aggregate pubkeys: %w", err)
+	}
+	if !equivalent {
+		return fmt.Errorf("%w: signers' G2 apk doesn't match the quorum apk minus non-signers", ErrApkMismatch)
+	}
+
+	valid, err := signature.Verify(apkG2, message)
+	if err != nil {
+		return fmt.Errorf("failed to verify aggregate signature: %w", err)
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// VerifySignature checks a single operator's signature over message against
+// its registered pubkeys. The G1 and G2 pubkeys must be of the same key, as
+// the G1 one is what the quorum APK is made of, while the G2 one verifies.
+func (t *Tracker) VerifySignature(operatorId types.OperatorId, signature *bls.Signature, message [32]byte) error {
+	pubkeys, err := t.Pubkeys(operatorId)
+	if err != nil {
+		return err
+	}
+
+	equivalent, err := pubkeys.G1Pubkey.VerifyEquivalence(pubkeys.G2Pubkey)
+	if err != nil {
+		return fmt.Errorf("failed to compare pubkeys of %x: %w", operatorId[:], err)
+	}
+	if !equivalent {
+		return fmt.Errorf("%w: registered G1 and G2 pubkeys of %x differ", ErrApkMismatch, operatorId[:])
+	}
+
+	valid, err := signature.Verify(pubkeys.G2Pubkey, message)
+	if err != nil {
+		return fmt.Errorf("failed to verify signature of %x: %w", operatorId[:], err)
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// SignerSet is what checkSignatures needs to know about the pubkeys behind an
+// aggregate: the quorum APKs, the G1 pubkeys of every non-signing member and
+// the signers' aggregate G2 pubkey
+type SignerSet struct {
+	// QuorumApks are in the order of the quorums passed to SignerSet
+	QuorumApks   []*bls.G1Point
+	NonSigners   map[types.OperatorId]*bls.G1Point
+	SignersApkG2 *bls.G2Point
+}
+
+// SignerSet splits the members of the quorums into signers and non-signers.
+// An operator in several quorums appears once.
+func (t *Tracker) SignerSet(quorums types.QuorumNums, signers []types.OperatorId) (SignerSet, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	signerSet := make(map[types.OperatorId]struct{}, len(signers))
+	for _, signer := range signers {
+		signerSet[signer] = struct{}{}
+	}
+
+	set := SignerSet{
+		QuorumApks:   make([]*bls.G1Point, 0, len(quorums)),
+		NonSigners:   make(map[types.OperatorId]*bls.G1Point),
+		SignersApkG2: bls.NewZeroG2Point(),
+	}
+	counted := make(map[types.OperatorId]struct{})
+
+	for _, quorum := range quorums {
+		quorumApk, ok := t.apks[quorum]
+		if !ok {
+			return SignerSet{}, fmt.Errorf("%w: %d", ErrUnknownQuorum, quorum)
+		}
+		set.QuorumApks = append(set.QuorumApks, bls.NewZeroG1Point().Add(quorumApk))
+
+		for operatorId := range t.members[quorum] {
+			if _, ok := counted[operatorId]; ok {
+				continue
+			}
+			counted[operatorId] = struct{}{}
+
+			pubkeys, err := t.operatorPubkeys(operatorId)
+			if err != nil {
+				return SignerSet{}, err
+			}
+			if _, signed := signerSet[operatorId]; signed {
+				set.SignersApkG2.Add(pubkeys.G2Pubkey)
+			} else {
+				set.NonSigners[operatorId] = pubkeys.G1Pubkey
+			}
+		}
+	}
+
+	return set, nil
+}
+
+// operatorPubkeys returns the registered pubkeys of the operator. Callers must hold mu.
+func (t *Tracker) operatorPubkeys(operatorId types.OperatorId) (types.OperatorPubkeys, error) {
+	operator, ok := t.operators[operatorId]
+	if !ok {
+		return types.OperatorPubkeys{}, fmt.Errorf("%w: %x is not registered", ErrUnknownSigner, operatorId[:])
+	}
+	pubkeys, ok := t.pubkeys[operator.Address]
+	if !ok || pubkeys.G1Pubkey == nil || pubkeys.G2Pubkey == nil {
+		return types.OperatorPubkeys{}, fmt.Errorf("%w: no pubkey registered for %x", ErrUnknownSigner, operatorId[:])
+	}
+	return pubkeys, nil
+}
+
+// sumG1 adds up the G1 pubkeys of the given operators. Callers must hold mu.
+func (t *Tracker) sumG1(operators map[types.OperatorId]struct{}) (*bls.G1Point, error) {
+	sum := bls.NewZeroG1Point()
+	for operatorId := range operators {
+		pubkeys, err := t.operatorPubkeys(operatorId)
+		if err != nil {
+			return nil, err
+		}
+		sum.Add(pubkeys.G1Pubkey)
+	}
+	return sum, nil
+}
+
+func (t *Tracker) getApk(ctx context.Context, quorum types.QuorumNum) (*bls.G1Point, error) {
+	var out []interface{}
+	if err := t.blsApkRegistry.Call(&bind.CallOpts{Context: ctx}, &out, "getApk", uint8(quorum)); err != nil {
+		return nil, fmt.Errorf("failed to get apk for quorum %d: %w", quorum, err)
+	}
+
+	point := abi.ConvertType(out[0], new(g1Point)).(*g1Point)
+	return bls.NewG1Point(point.X, point.Y), nil
+}
+
+func bindContract(address common.Address, contractAbi string, backend bind.ContractBackend) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractAbi))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, backend, backend, backend), nil
+}
+
+// Pubkeys returns the registered pubkeys of the operator
+func (t *Tracker) Pubkeys(operatorId types.OperatorId) (types.OperatorPubkeys, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.operatorPubkeys(operatorId)
+}
+
+// RefreshedBlock returns the block the APKs were last read at, or 0 before
+// the first refresh
+func (t *Tracker) RefreshedBlock() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.syncedBlock == 0 {
+		return 0
+	}
+	return t.syncedBlock - 1
+}
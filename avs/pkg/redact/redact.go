@@ -0,0 +1,216 @@
+package redact
+
+import (
+	"crypto/ecdsa"
+	"reflect"
+	"strings"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+// maxStructDepth bounds how deep nested structs are searched for sensitive
+// fields
+const maxStructDepth = 8
+
+// Redacted replaces the value of any sensitive log field
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are matched against normalized log keys (lowercase, without
+// separators), so "privateKey", "private_key" and "ecdsaPrivateKey" all match
+var sensitiveKeys = []string{
+	"privatekey",
+	"passphrase",
+	"password",
+	"secret",
+	"mnemonic",
+	"signature",
+	"apitoken",
+	"authtoken",
+	"accesstoken",
+}
+
+// Logger wraps a logging.Logger and redacts private keys, passphrases and raw
+// signatures from structured log fields before they reach the underlying logger
+type Logger struct {
+	logger logging.Logger
+}
+
+var _ logging.Logger = (*Logger)(nil)
+
+// NewLogger returns a logger that redacts sensitive fields before delegating to logger
+func NewLogger(logger logging.Logger) logging.Logger {
+	return &Logger{logger: logger}
+}
+
+func (l *Logger) Debug(msg string, tags ...any) {
+	l.logger.Debug(msg, Tags(tags...)...)
+}
+
+func (l *Logger) Info(msg string, tags ...any) {
+	l.logger.Info(msg, Tags(tags...)...)
+}
+
+func (l *Logger) Warn(msg string, tags ...any) {
+	l.logger.Warn(msg, Tags(tags...)...)
+}
+
+func (l *Logger) Error(msg string, tags ...any) {
+	l.logger.Error(msg, Tags(tags...)...)
+}
+
+func (l *Logger) Fatal(msg string, tags ...any) {
+	l.logger.Fatal(msg, Tags(tags...)...)
+}
+
+// The formatted variants have no keys to inspect, so only values that are
+// sensitive by type are redacted
+
+func (l *Logger) Debugf(template string, args ...interface{}) {
+	l.logger.Debugf(template, Args(args...)...)
+}
+
+func (l *Logger) Infof(template string, args ...interface{}) {
+	l.logger.Infof(template, Args(args...)...)
+}
+
+func (l *Logger) Warnf(template string, args ...interface{}) {
+	l.logger.Warnf(template, Args(args...)...)
+}
+
+func (l *Logger) Errorf(template string, args ...interface{}) {
+	l.logger.Errorf(template, Args(args...)...)
+}
+
+func (l *Logger) Fatalf(template string, args ...interface{}) {
+	l.logger.Fatalf(template, Args(args...)...)
+}
+
+func (l *Logger) With(tags ...any) logging.Logger {
+	return &Logger{logger: l.logger.With(Tags(tags...)...)}
+}
+
+// Tags returns a copy of the key/value pairs with sensitive values redacted
+func Tags(tags ...any) []any {
+	redacted := make([]any, len(tags))
+	copy(redacted, tags)
+
+	for i := 0; i < len(redacted); i++ {
+		if isSensitiveValue(redacted[i]) {
+			redacted[i] = Redacted
+			continue
+		}
+		if scrubbed, ok := scrub(reflect.ValueOf(redacted[i]), 0); ok {
+			redacted[i] = scrubbed
+		}
+
+		// Values follow their key
+		if i%2 == 0 && i+1 < len(redacted) {
+			if key, ok := redacted[i].(string); ok && IsSensitiveKey(key) {
+				redacted[i+1] = Redacted
+				i++
+			}
+		}
+	}
+
+	return redacted
+}
+
+// Args returns a copy of the format arguments with sensitive values redacted
+func Args(args ...interface{}) []interface{} {
+	redacted := make([]interface{}, len(args))
+	for i, arg := range args {
+		if isSensitiveValue(arg) {
+			redacted[i] = Redacted
+		} else if scrubbed, ok := scrub(reflect.ValueOf(arg), 0); ok {
+			redacted[i] = scrubbed
+		} else {
+			redacted[i] = arg
+		}
+	}
+	return redacted
+}
+
+// IsSensitiveKey reports whether values logged under key should be redacted.
+// Paths to key files are not secret and are left alone.
+func IsSensitiveKey(key string) bool {
+	normalized := strings.ToLower(key)
+	normalized = strings.NewReplacer("_", "", "-", "", ".", "", " ", "").Replace(normalized)
+
+	if strings.HasSuffix(normalized, "path") || strings.HasSuffix(normalized, "file") {
+		return false
+	}
+
+	for _, sensitiveKey := range sensitiveKeys {
+		if strings.Contains(normalized, sensitiveKey) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSensitiveValue reports whether the value is a private key or a raw
+// signature, whatever it is logged under
+func isSensitiveValue(value any) bool {
+	switch value.(type) {
+	case *ecdsa.PrivateKey, ecdsa.PrivateKey,
+		*bls.KeyPair, bls.KeyPair, *bls.PrivateKey, bls.PrivateKey,
+		*bls.Signature, bls.Signature:
+		return true
+	}
+	return false
+}
+
+// scrub returns a struct, or a pointer to one, as a map of its exported
+// fields keyed by their json names, with sensitive fields redacted. It
+// reports false, leaving the value to be logged as is, when none of its
+// fields are sensitive.
+func scrub(value reflect.Value, depth int) (any, bool) {
+	if depth > maxStructDepth || !value.IsValid() {
+		return nil, false
+	}
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, false
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	fields := make(map[string]any, value.NumField())
+	redacted := false
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldValue := value.Field(i)
+		switch {
+		case IsSensitiveKey(field.Name) || isSensitiveValue(fieldValue.Interface()):
+			fields[name] = Redacted
+			redacted = true
+		default:
+			if scrubbed, ok := scrub(fieldValue, depth+1); ok {
+				fields[name] = scrubbed
+				redacted = true
+			} else {
+				fields[name] = fieldValue.Interface()
+			}
+		}
+	}
+	if !redacted {
+		return nil, false
+	}
+	return fields, true
+}
@@ -0,0 +1,64 @@
+package redact
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+)
+
+func testKeyPair(t *testing.T) *bls.KeyPair {
+	t.Helper()
+
+	privateKey, err := bls.NewPrivateKey("12345")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bls.NewKeyPair(privateKey)
+}
+
+func TestTagsRedactBlsValuesUnderNeutralKeys(t *testing.T) {
+	keyPair := testKeyPair(t)
+	signature := keyPair.SignMessage([32]byte{1})
+
+	tags := Tags("keys", keyPair, "sig", signature, "value", *signature, "taskIndex", 3)
+	for i, want := range []any{"keys", Redacted, "sig", Redacted, "value", Redacted, "taskIndex", 3} {
+		if tags[i] != want {
+			t.Fatalf("tag %d is %v, want %v", i, tags[i], want)
+		}
+	}
+}
+
+func TestTagsRedactSignatureFieldsOfStructs(t *testing.T) {
+	type response struct {
+		TaskIndex uint32         `json:"taskIndex"`
+		Signature *bls.Signature `json:"blsSignature"`
+		Proof     bls.Signature  `json:"proof"`
+	}
+	type envelope struct {
+		Response response
+		Sender   string
+	}
+	keyPair := testKeyPair(t)
+	signature := keyPair.SignMessage([32]byte{1})
+
+	tags := Tags("response", envelope{Response: response{TaskIndex: 7, Signature: signature, Proof: *signature}, Sender: "operator"})
+	logged := fmt.Sprint(tags...)
+	if strings.Contains(logged, signature.String()) {
+		t.Fatalf("logged the raw signature: %s", logged)
+	}
+	want := fmt.Sprint(map[string]any{
+		"Response": map[string]any{"taskIndex": uint32(7), "blsSignature": Redacted, "proof": Redacted},
+		"Sender":   "operator",
+	})
+	if got := fmt.Sprint(tags[1]); got != want {
+		t.Fatalf("logged %s, want %s", got, want)
+	}
+
+	// Structs without anything sensitive are logged as they are
+	type status struct{ Healthy bool }
+	if tags := Tags("status", status{Healthy: true}); tags[1] != (status{Healthy: true}) {
+		t.Fatalf("rewrote an insensitive struct to %v", tags[1])
+	}
+}
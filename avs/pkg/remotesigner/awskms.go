@@ -0,0 +1,84 @@
+package remotesigner
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// awsKmsSigner signs with an ECC_SECG_P256K1 key in AWS KMS. Credentials and
+// region come from the usual AWS environment, shared config or instance role;
+// a key ARN carries its own region.
+type awsKmsSigner struct {
+	client  *kms.Client
+	keyId   string
+	address common.Address
+	timeout time.Duration
+}
+
+var _ Signer = (*awsKmsSigner)(nil)
+
+func newAwsKmsSigner(ctx context.Context, config Config) (*awsKmsSigner, error) {
+	if config.KeyId == "" {
+		return nil, fmt.Errorf("%s needs a key id", KindAwsKms)
+	}
+
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	signer := &awsKmsSigner{
+		client:  kms.NewFromConfig(awsConfig),
+		keyId:   config.KeyId,
+		timeout: config.Timeout,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+	key, err := signer.client.GetPublicKey(ctx, &kms.GetPublicKeyInput{KeyId: aws.String(config.KeyId)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AWS KMS public key: %w", err)
+	}
+	if key.KeySpec != kmstypes.KeySpecEccSecgP256k1 {
+		return nil, fmt.Errorf("AWS KMS key %s is %s, not %s", config.KeyId, key.KeySpec, kmstypes.KeySpecEccSecgP256k1)
+	}
+	publicKey, err := parsePublicKey(key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	signer.address = crypto.PubkeyToAddress(*publicKey)
+	return signer, nil
+}
+
+func (s *awsKmsSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *awsKmsSigner) SignHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	signed, err := s.client.Sign(ctx, &kms.SignInput{
+		KeyId:            aws.String(s.keyId),
+		Message:          hash[:],
+		MessageType:      kmstypes.MessageTypeDigest,
+		SigningAlgorithm: kmstypes.SigningAlgorithmSpecEcdsaSha256,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("AWS KMS failed to sign: %w", err)
+	}
+	return recoverableSignature(signed.Signature, hash, s.address)
+}
+
+func (s *awsKmsSigner) SignTx(ctx context.Context, tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	return signTxByHash(ctx, s, tx, chainId)
+}
@@ -0,0 +1,133 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcpKmsEndpoint = "https://cloudkms.googleapis.com/v1/"
+	gcpKmsScope    = "https://www.googleapis.com/auth/cloudkms"
+	// gcpKmsAlgorithm is the only secp256k1 signing algorithm GCP KMS offers
+	gcpKmsAlgorithm = "EC_SIGN_SECP256K1_SHA256"
+)
+
+// gcpKmsSigner signs with an EC_SIGN_SECP256K1_SHA256 key version in GCP KMS
+// through its REST API, authenticated with Application Default Credentials
+type gcpKmsSigner struct {
+	http    *http.Client
+	name    string
+	address common.Address
+}
+
+var _ Signer = (*gcpKmsSigner)(nil)
+
+func newGcpKmsSigner(ctx context.Context, config Config) (*gcpKmsSigner, error) {
+	if !strings.Contains(config.KeyId, "/cryptoKeyVersions/") {
+		return nil, fmt.Errorf("%s needs a key version name, projects/.../cryptoKeyVersions/N", KindGcpKms)
+	}
+
+	tokens, err := google.DefaultTokenSource(ctx, gcpKmsScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find GCP credentials: %w", err)
+	}
+	// The token source outlives ctx, which only bounds startup
+	client := oauth2.NewClient(context.Background(), tokens)
+	client.Timeout = config.Timeout
+	signer := &gcpKmsSigner{http: client, name: strings.TrimPrefix(config.KeyId, "/")}
+
+	var key struct {
+		Pem       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+	}
+	if err := signer.call(ctx, http.MethodGet, signer.name+"/publicKey", nil, &key); err != nil {
+		return nil, fmt.Errorf("failed to get GCP KMS public key: %w", err)
+	}
+	if key.Algorithm != gcpKmsAlgorithm {
+		return nil, fmt.Errorf("GCP KMS key %s is %s, not %s", signer.name, key.Algorithm, gcpKmsAlgorithm)
+	}
+	block, _ := pem.Decode([]byte(key.Pem))
+	if block == nil {
+		return nil, errors.New("GCP KMS returned no PEM public key")
+	}
+	publicKey, err := parsePublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer.address = crypto.PubkeyToAddress(*publicKey)
+	return signer, nil
+}
+
+func (s *gcpKmsSigner) Address() common.Address {
+	return s.address
+}
+
+// SignHash passes the hash as the request's SHA-256 digest, which KMS signs
+// without checking how it was computed
+func (s *gcpKmsSigner) SignHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	request := map[string]interface{}{
+		"digest": map[string]string{"sha256": base64.StdEncoding.EncodeToString(hash[:])},
+	}
+	var signed struct {
+		Signature string `json:"signature"`
+	}
+	if err := s.call(ctx, http.MethodPost, s.name+":asymmetricSign", request, &signed); err != nil {
+		return nil, fmt.Errorf("GCP KMS failed to sign: %w", err)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(signed.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode GCP KMS signature: %w", err)
+	}
+	return recoverableSignature(der, hash, s.address)
+}
+
+func (s *gcpKmsSigner) SignTx(ctx context.Context, tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	return signTxByHash(ctx, s, tx, chainId)
+}
+
+func (s *gcpKmsSigner) call(ctx context.Context, method, path string, request, response interface{}) error {
+	var body io.Reader
+	if request != nil {
+		encoded, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, gcpKmsEndpoint+path, body)
+	if err != nil {
+		return err
+	}
+	if request != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
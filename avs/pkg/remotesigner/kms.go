@@ -0,0 +1,70 @@
+package remotesigner
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KMS services return keys as DER SubjectPublicKeyInfo and signatures as DER
+// (r, s) pairs. crypto/x509 doesn't know secp256k1, so both are unpacked here.
+
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+type derSignature struct {
+	R, S *big.Int
+}
+
+var secp256k1HalfN = new(big.Int).Rsh(crypto.S256().Params().N, 1)
+
+// parsePublicKey reads a secp256k1 key from DER SubjectPublicKeyInfo
+func parsePublicKey(der []byte) (*ecdsa.PublicKey, error) {
+	var info subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	publicKey, err := crypto.UnmarshalPubkey(info.PublicKey.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("key is not a secp256k1 key: %w", err)
+	}
+	return publicKey, nil
+}
+
+// recoverableSignature turns a DER signature over hash into a 65-byte
+// [R || S || V] signature by address. S is moved to the lower half of the
+// curve order, which Ethereum requires and KMS services don't guarantee.
+func recoverableSignature(der []byte, hash common.Hash, address common.Address) ([]byte, error) {
+	var parsed derSignature
+	if _, err := asn1.Unmarshal(der, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse signature: %w", err)
+	}
+	if parsed.R == nil || parsed.S == nil || parsed.R.BitLen() > 256 || parsed.S.BitLen() > 256 {
+		return nil, errors.New("malformed signature")
+	}
+
+	s := parsed.S
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s = new(big.Int).Sub(crypto.S256().Params().N, s)
+	}
+
+	signature := make([]byte, crypto.SignatureLength)
+	parsed.R.FillBytes(signature[:32])
+	s.FillBytes(signature[32:64])
+	for v := byte(0); v < 2; v++ {
+		signature[crypto.RecoveryIDOffset] = v
+		publicKey, err := crypto.SigToPub(hash[:], signature)
+		if err == nil && crypto.PubkeyToAddress(*publicKey) == address {
+			return signature, nil
+		}
+	}
+	return nil, ErrWrongSigner
+}
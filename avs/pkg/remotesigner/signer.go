@@ -0,0 +1,150 @@
+// Package remotesigner signs with an ECDSA key that may be held outside the
+// process: by Web3Signer, AWS KMS or GCP KMS, or, for development, in a local
+// key file. Callers only see the key's address and ask for signatures.
+package remotesigner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// Signer kinds
+	KindLocal      = "local"
+	KindWeb3Signer = "web3signer"
+	KindAwsKms     = "aws-kms"
+	KindGcpKms     = "gcp-kms"
+
+	// DefaultTimeout bounds one signing request when Config.Timeout is unset
+	DefaultTimeout = 10 * time.Second
+)
+
+var (
+	// ErrHashSigningUnsupported is returned by signers that can only sign
+	// transactions, such as Web3Signer
+	ErrHashSigningUnsupported = errors.New("signer can only sign transactions")
+	// ErrWrongSigner is returned when a remote signature doesn't recover to
+	// the configured address
+	ErrWrongSigner = errors.New("signature does not recover to the signer address")
+)
+
+// Signer signs with one ECDSA key
+type Signer interface {
+	// Address is the address of the key
+	Address() common.Address
+	// SignHash signs a 32-byte digest as is, returning a 65-byte
+	// [R || S || V] signature with V of 0 or 1
+	SignHash(ctx context.Context, hash common.Hash) ([]byte, error)
+	// SignTx returns the transaction signed for the chain
+	SignTx(ctx context.Context, tx *types.Transaction, chainId *big.Int) (*types.Transaction, error)
+}
+
+// Config selects a remote signer
+type Config struct {
+	// Kind is one of KindWeb3Signer, KindAwsKms or KindGcpKms
+	Kind string
+	// Url is the Web3Signer endpoint
+	Url string
+	// KeyId is the AWS KMS key id or ARN, or the GCP KMS key version resource
+	// name, projects/.../cryptoKeys/.../cryptoKeyVersions/N
+	KeyId string
+	// Address is the key's address. Web3Signer needs it to pick the key; KMS
+	// keys are checked against it when set.
+	Address common.Address
+	// Timeout bounds one signing request
+	Timeout time.Duration
+}
+
+// New connects to the remote signer and checks that it holds the key
+func New(ctx context.Context, config Config) (Signer, error) {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+
+	var (
+		signer Signer
+		err    error
+	)
+	switch config.Kind {
+	case KindWeb3Signer:
+		signer, err = newWeb3Signer(ctx, config)
+	case KindAwsKms:
+		signer, err = newAwsKmsSigner(ctx, config)
+	case KindGcpKms:
+		signer, err = newGcpKmsSigner(ctx, config)
+	default:
+		return nil, fmt.Errorf("unknown remote signer %q", config.Kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Address != (common.Address{}) && signer.Address() != config.Address {
+		return nil, fmt.Errorf("%s key is %s, not the configured %s", config.Kind, signer.Address().Hex(), config.Address.Hex())
+	}
+	return signer, nil
+}
+
+// LocalSigner signs with a private key held in memory
+type LocalSigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+var _ Signer = (*LocalSigner)(nil)
+
+// NewLocal returns a signer for a key already loaded
+func NewLocal(key *ecdsa.PrivateKey) *LocalSigner {
+	return &LocalSigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+func (s *LocalSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *LocalSigner) SignHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	return crypto.Sign(hash[:], s.key)
+}
+
+func (s *LocalSigner) SignTx(ctx context.Context, tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainId), s.key)
+}
+
+// SignerFn returns a bind.SignerFn signing the key's transactions for the
+// chain, for contract bindings
+func SignerFn(ctx context.Context, signer Signer, chainId *big.Int) bind.SignerFn {
+	return func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if from != signer.Address() {
+			return nil, bind.ErrNotAuthorized
+		}
+		return signer.SignTx(ctx, tx, chainId)
+	}
+}
+
+// TransactOpts returns transact options sending from the key
+func TransactOpts(ctx context.Context, signer Signer, chainId *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:    signer.Address(),
+		Signer:  SignerFn(ctx, signer, chainId),
+		Context: ctx,
+	}
+}
+
+// signTxByHash signs a transaction through a signer of raw digests
+func signTxByHash(ctx context.Context, signer Signer, tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	txSigner := types.LatestSignerForChainID(chainId)
+	signature, err := signer.SignHash(ctx, txSigner.Hash(tx))
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(txSigner, signature)
+}
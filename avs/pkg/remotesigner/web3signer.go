@@ -0,0 +1,162 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// web3Signer signs transactions through Web3Signer's eth1 JSON-RPC API.
+// Web3Signer only signs data after hashing it itself, so raw digests can't be
+// signed with it.
+type web3Signer struct {
+	url     string
+	address common.Address
+	http    *http.Client
+}
+
+var _ Signer = (*web3Signer)(nil)
+
+type web3SignerTx struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 hexutil.Bytes   `json:"data"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	ChainId              *hexutil.Big    `json:"chainId"`
+}
+
+func newWeb3Signer(ctx context.Context, config Config) (*web3Signer, error) {
+	if config.Url == "" {
+		return nil, fmt.Errorf("%s needs a url", KindWeb3Signer)
+	}
+	if config.Address == (common.Address{}) {
+		return nil, fmt.Errorf("%s needs the address of the key to sign with", KindWeb3Signer)
+	}
+	signer := &web3Signer{
+		url:     strings.TrimRight(config.Url, "/"),
+		address: config.Address,
+		http:    &http.Client{Timeout: config.Timeout},
+	}
+
+	var accounts []common.Address
+	if err := signer.call(ctx, "eth_accounts", []interface{}{}, &accounts); err != nil {
+		return nil, fmt.Errorf("failed to list Web3Signer keys: %w", err)
+	}
+	for _, account := range accounts {
+		if account == config.Address {
+			return signer, nil
+		}
+	}
+	return nil, fmt.Errorf("Web3Signer at %s holds no key for %s", signer.url, config.Address.Hex())
+}
+
+func (s *web3Signer) Address() common.Address {
+	return s.address
+}
+
+func (s *web3Signer) SignHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	return nil, ErrHashSigningUnsupported
+}
+
+// SignTx signs legacy and EIP-1559 transactions; Web3Signer signs no others
+func (s *web3Signer) SignTx(ctx context.Context, tx *types.Transaction, chainId *big.Int) (*types.Transaction, error) {
+	request := web3SignerTx{
+		From:    s.address,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Data:    tx.Data(),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		ChainId: (*hexutil.Big)(chainId),
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		request.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		request.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		request.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	default:
+		return nil, fmt.Errorf("Web3Signer can't sign transactions of type %d", tx.Type())
+	}
+
+	var raw hexutil.Bytes
+	if err := s.call(ctx, "eth_signTransaction", []interface{}{request}, &raw); err != nil {
+		return nil, fmt.Errorf("Web3Signer failed to sign: %w", err)
+	}
+	signed := new(types.Transaction)
+	if err := signed.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("failed to decode Web3Signer transaction: %w", err)
+	}
+
+	// Make sure the signer signed what was asked, with the expected key
+	sender, err := types.Sender(types.LatestSignerForChainID(chainId), signed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Web3Signer signature: %w", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("%w: recovered %s", ErrWrongSigner, sender.Hex())
+	}
+	if signed.Nonce() != tx.Nonce() || signed.Gas() != tx.Gas() || signed.Value().Cmp(tx.Value()) != 0 || !bytes.Equal(signed.Data(), tx.Data()) {
+		return nil, errors.New("Web3Signer signed a different transaction")
+	}
+	return signed, nil
+}
+
+func (s *web3Signer) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Error != nil {
+		return fmt.Errorf("error %d: %s", response.Error.Code, response.Error.Message)
+	}
+	return json.Unmarshal(response.Result, result)
+}
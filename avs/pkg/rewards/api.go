@@ -0,0 +1,193 @@
+package rewards
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TokenRewards summarizes an earner's rewards in a single token
+type TokenRewards struct {
+	Token     common.Address `json:"token"`
+	Earned    *big.Int       `json:"earned"`
+	Claimed   *big.Int       `json:"claimed"`
+	Claimable *big.Int       `json:"claimable"`
+}
+
+// ApiClient queries rewards data computed off-chain by an EigenLayer sidecar
+type ApiClient struct {
+	baseUrl    string
+	httpClient *http.Client
+}
+
+func NewApiClient(baseUrl string) *ApiClient {
+	return &ApiClient{
+		baseUrl:    strings.TrimSuffix(baseUrl, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type summarizedRewardsResponse struct {
+	Rewards []struct {
+		Token     string `json:"token"`
+		Earned    string `json:"earned"`
+		Claimed   string `json:"claimed"`
+		Claimable string `json:"claimable"`
+	} `json:"rewards"`
+}
+
+// GetSummarizedRewards returns lifetime earned, claimed and claimable amounts per token for the earner
+func (c *ApiClient) GetSummarizedRewards(ctx context.Context, earner common.Address) ([]TokenRewards, error) {
+	url := fmt.Sprintf("%s/rewards/v1/earners/%s/summarized-rewards", c.baseUrl, earner.Hex())
+
+	var response summarizedRewardsResponse
+	if err := c.get(ctx, url, &response); err != nil {
+		return nil, err
+	}
+
+	rewards := make([]TokenRewards, 0, len(response.Rewards))
+	for _, reward := range response.Rewards {
+		tokenRewards := TokenRewards{Token: common.HexToAddress(reward.Token)}
+
+		var err error
+		if tokenRewards.Earned, err = parseAmount(reward.Earned); err != nil {
+			return nil, fmt.Errorf("invalid earned amount for token %s: %w", reward.Token, err)
+		}
+		if tokenRewards.Claimed, err = parseAmount(reward.Claimed); err != nil {
+			return nil, fmt.Errorf("invalid claimed amount for token %s: %w", reward.Token, err)
+		}
+		if tokenRewards.Claimable, err = parseAmount(reward.Claimable); err != nil {
+			return nil, fmt.Errorf("invalid claimable amount for token %s: %w", reward.Token, err)
+		}
+
+		rewards = append(rewards, tokenRewards)
+	}
+
+	return rewards, nil
+}
+
+type claimProofRequest struct {
+	EarnerAddress string   `json:"earnerAddress"`
+	Tokens        []string `json:"tokens"`
+}
+
+type claimProofResponse struct {
+	Proof struct {
+		RootIndex       uint32        `json:"rootIndex"`
+		EarnerIndex     uint32        `json:"earnerIndex"`
+		EarnerTreeProof hexutil.Bytes `json:"earnerTreeProof"`
+		EarnerLeaf      struct {
+			Earner          common.Address `json:"earner"`
+			EarnerTokenRoot common.Hash    `json:"earnerTokenRoot"`
+		} `json:"earnerLeaf"`
+		TokenIndices    []uint32        `json:"tokenIndices"`
+		TokenTreeProofs []hexutil.Bytes `json:"tokenTreeProofs"`
+		TokenLeaves     []struct {
+			Token              common.Address `json:"token"`
+			CumulativeEarnings string         `json:"cumulativeEarnings"`
+		} `json:"tokenLeaves"`
+	} `json:"proof"`
+}
+
+// GetClaimProof fetches a merkle claim against the latest claimable distribution
+// root for the earner's rewards in the given tokens
+func (c *ApiClient) GetClaimProof(ctx context.Context, earner common.Address, tokens []common.Address) (*RewardsMerkleClaim, error) {
+	request := claimProofRequest{EarnerAddress: earner.Hex()}
+	for _, token := range tokens {
+		request.Tokens = append(request.Tokens, token.Hex())
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode claim proof request: %w", err)
+	}
+
+	var response claimProofResponse
+	if err := c.post(ctx, c.baseUrl+"/rewards/v1/claim-proof", body, &response); err != nil {
+		return nil, err
+	}
+
+	proof := response.Proof
+	claim := &RewardsMerkleClaim{
+		RootIndex:       proof.RootIndex,
+		EarnerIndex:     proof.EarnerIndex,
+		EarnerTreeProof: proof.EarnerTreeProof,
+		EarnerLeaf: EarnerTreeMerkleLeaf{
+			Earner:          proof.EarnerLeaf.Earner,
+			EarnerTokenRoot: proof.EarnerLeaf.EarnerTokenRoot,
+		},
+		TokenIndices: proof.TokenIndices,
+	}
+	for _, tokenProof := range proof.TokenTreeProofs {
+		claim.TokenTreeProofs = append(claim.TokenTreeProofs, tokenProof)
+	}
+	for _, leaf := range proof.TokenLeaves {
+		cumulativeEarnings, err := parseAmount(leaf.CumulativeEarnings)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cumulative earnings for token %s: %w", leaf.Token.Hex(), err)
+		}
+		claim.TokenLeaves = append(claim.TokenLeaves, TokenTreeMerkleLeaf{
+			Token:              leaf.Token,
+			CumulativeEarnings: cumulativeEarnings,
+		})
+	}
+
+	return claim, nil
+}
+
+func (c *ApiClient) get(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	return c.do(req, out)
+}
+
+func (c *ApiClient) post(ctx context.Context, url string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req, out)
+}
+
+func (c *ApiClient) do(req *http.Request, out interface{}) error {
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query rewards api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("rewards api returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode rewards api response: %w", err)
+	}
+
+	return nil
+}
+
+func parseAmount(s string) (*big.Int, error) {
+	if s == "" {
+		return big.NewInt(0), nil
+	}
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("not a decimal integer: %q", s)
+	}
+	return amount, nil
+}
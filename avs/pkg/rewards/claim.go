@@ -0,0 +1,169 @@
+package rewards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+)
+
+var (
+	// ErrNothingToClaim is returned when no token has a claimable balance
+	ErrNothingToClaim = errors.New("no claimable rewards")
+
+	// ErrGasPriceTooHigh is returned when the current gas price exceeds the configured maximum
+	ErrGasPriceTooHigh = errors.New("gas price exceeds configured maximum")
+
+	// ErrInsufficientGasFunds is returned when the claimer can't pay for the claim transaction
+	ErrInsufficientGasFunds = errors.New("insufficient balance to pay for claim gas")
+)
+
+// ClaimResult describes a submitted rewards claim
+type ClaimResult struct {
+	TxHash    common.Hash      `json:"txHash"`
+	Recipient common.Address   `json:"recipient"`
+	Tokens    []common.Address `json:"tokens"`
+	GasUsed   uint64           `json:"gasUsed"`
+}
+
+// Claimer claims the operator's rewards from the RewardsCoordinator
+type Claimer struct {
+	coordinator *Coordinator
+	api         *ApiClient
+	ethClient   eth.Client
+	signer      remotesigner.Signer
+	earner      common.Address
+	recipient   common.Address
+	maxGasPrice *big.Int
+	logger      logging.Logger
+}
+
+// NewClaimer creates a claimer paying rewards out to recipient. A zero recipient
+// pays out to the earner, and a nil maxGasPrice disables the gas price check.
+func NewClaimer(
+	coordinator *Coordinator,
+	api *ApiClient,
+	ethClient eth.Client,
+	signer remotesigner.Signer,
+	recipient common.Address,
+	maxGasPrice *big.Int,
+	logger logging.Logger,
+) *Claimer {
+	earner := signer.Address()
+	if recipient == (common.Address{}) {
+		recipient = earner
+	}
+
+	return &Claimer{
+		coordinator: coordinator,
+		api:         api,
+		ethClient:   ethClient,
+		signer:      signer,
+		earner:      earner,
+		recipient:   recipient,
+		maxGasPrice: maxGasPrice,
+		logger:      logger.With("component", "rewards-claimer"),
+	}
+}
+
+// Claim fetches a proof for every token with a claimable balance, checks it
+// against the contract and the current gas price, and submits it. It waits for
+// the claim transaction to be mined.
+func (c *Claimer) Claim(ctx context.Context) (*ClaimResult, error) {
+	summary, err := c.api.GetSummarizedRewards(ctx, c.earner)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []common.Address
+	for _, token := range summary {
+		if token.Claimable != nil && token.Claimable.Sign() > 0 {
+			tokens = append(tokens, token.Token)
+		}
+	}
+	if len(tokens) == 0 {
+		return nil, ErrNothingToClaim
+	}
+
+	claim, err := c.api.GetClaimProof(ctx, c.earner, tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := c.coordinator.CheckClaim(ctx, *claim)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("claim proof for root %d was rejected by the rewards coordinator", claim.RootIndex)
+	}
+
+	opts, err := c.transactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build and estimate the transaction without sending it so gas costs can be
+	// checked before anything is broadcast
+	opts.NoSend = true
+	tx, err := c.coordinator.ProcessClaim(opts, *claim, c.recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	gasPrice := tx.GasFeeCap()
+	if c.maxGasPrice != nil && gasPrice.Cmp(c.maxGasPrice) > 0 {
+		return nil, fmt.Errorf("%w: %s > %s", ErrGasPriceTooHigh, gasPrice, c.maxGasPrice)
+	}
+
+	balance, err := c.ethClient.BalanceAt(ctx, c.earner, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claimer balance: %w", err)
+	}
+	gasCost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(tx.Gas()))
+	if balance.Cmp(gasCost) < 0 {
+		return nil, fmt.Errorf("%w: balance %s, max gas cost %s", ErrInsufficientGasFunds, balance, gasCost)
+	}
+
+	if err := c.ethClient.SendTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to send claim transaction: %w", err)
+	}
+
+	c.logger.Info("Submitted rewards claim",
+		"txHash", tx.Hash().Hex(),
+		"rootIndex", claim.RootIndex,
+		"recipient", c.recipient.Hex(),
+		"tokens", len(tokens),
+	)
+
+	receipt, err := bind.WaitMined(ctx, c.ethClient, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for claim transaction %s: %w", tx.Hash().Hex(), err)
+	}
+	if receipt.Status != 1 {
+		return nil, fmt.Errorf("claim transaction %s reverted", tx.Hash().Hex())
+	}
+
+	return &ClaimResult{
+		TxHash:    tx.Hash(),
+		Recipient: c.recipient,
+		Tokens:    tokens,
+		GasUsed:   receipt.GasUsed,
+	}, nil
+}
+
+func (c *Claimer) transactOpts(ctx context.Context) (*bind.TransactOpts, error) {
+	chainId, err := c.ethClient.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain id: %w", err)
+	}
+
+	return remotesigner.TransactOpts(ctx, c.signer, chainId), nil
+}
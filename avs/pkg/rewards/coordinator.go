@@ -0,0 +1,134 @@
+package rewards
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// rewardsCoordinatorAbi is the subset of EigenLayer's RewardsCoordinator used by the operator
+const rewardsCoordinatorAbi = `[
+	{"type":"function","name":"cumulativeClaimed","stateMutability":"view","inputs":[{"name":"earner","type":"address"},{"name":"token","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"getCurrentDistributionRoot","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"tuple","components":[{"name":"root","type":"bytes32"},{"name":"rewardsCalculationEndTimestamp","type":"uint32"},{"name":"activatedAt","type":"uint32"},{"name":"disabled","type":"bool"}]}]},
+	{"type":"function","name":"getDistributionRootsLength","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"checkClaim","stateMutability":"view","inputs":[{"name":"claim","type":"tuple","components":%[1]s}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"processClaim","stateMutability":"nonpayable","inputs":[{"name":"claim","type":"tuple","components":%[1]s},{"name":"recipient","type":"address"}],"outputs":[]}
+]`
+
+// rewardsMerkleClaimComponents is the ABI of IRewardsCoordinator.RewardsMerkleClaim
+const rewardsMerkleClaimComponents = `[
+	{"name":"rootIndex","type":"uint32"},
+	{"name":"earnerIndex","type":"uint32"},
+	{"name":"earnerTreeProof","type":"bytes"},
+	{"name":"earnerLeaf","type":"tuple","components":[{"name":"earner","type":"address"},{"name":"earnerTokenRoot","type":"bytes32"}]},
+	{"name":"tokenIndices","type":"uint32[]"},
+	{"name":"tokenTreeProofs","type":"bytes[]"},
+	{"name":"tokenLeaves","type":"tuple[]","components":[{"name":"token","type":"address"},{"name":"cumulativeEarnings","type":"uint256"}]}
+]`
+
+// EarnerTreeMerkleLeaf is the earner's leaf in the distribution root
+type EarnerTreeMerkleLeaf struct {
+	Earner          common.Address `json:"earner"`
+	EarnerTokenRoot [32]byte       `json:"earnerTokenRoot"`
+}
+
+// TokenTreeMerkleLeaf is a token leaf in the earner's token tree
+type TokenTreeMerkleLeaf struct {
+	Token              common.Address `json:"token"`
+	CumulativeEarnings *big.Int       `json:"cumulativeEarnings"`
+}
+
+// RewardsMerkleClaim mirrors IRewardsCoordinator.RewardsMerkleClaim
+type RewardsMerkleClaim struct {
+	RootIndex       uint32                `json:"rootIndex"`
+	EarnerIndex     uint32                `json:"earnerIndex"`
+	EarnerTreeProof []byte                `json:"earnerTreeProof"`
+	EarnerLeaf      EarnerTreeMerkleLeaf  `json:"earnerLeaf"`
+	TokenIndices    []uint32              `json:"tokenIndices"`
+	TokenTreeProofs [][]byte              `json:"tokenTreeProofs"`
+	TokenLeaves     []TokenTreeMerkleLeaf `json:"tokenLeaves"`
+}
+
+// DistributionRoot is a rewards merkle root posted to the RewardsCoordinator
+type DistributionRoot struct {
+	Root                           [32]byte `json:"root"`
+	RewardsCalculationEndTimestamp uint32   `json:"rewardsCalculationEndTimestamp"`
+	ActivatedAt                    uint32   `json:"activatedAt"`
+	Disabled                       bool     `json:"disabled"`
+}
+
+// Coordinator reads rewards state from the RewardsCoordinator contract
+type Coordinator struct {
+	address  common.Address
+	contract *bind.BoundContract
+}
+
+func NewCoordinator(address common.Address, backend bind.ContractBackend) (*Coordinator, error) {
+	parsed, err := abi.JSON(strings.NewReader(fmt.Sprintf(rewardsCoordinatorAbi, rewardsMerkleClaimComponents)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rewards coordinator abi: %w", err)
+	}
+
+	return &Coordinator{
+		address:  address,
+		contract: bind.NewBoundContract(address, parsed, backend, backend, backend),
+	}, nil
+}
+
+// Address returns the RewardsCoordinator address
+func (c *Coordinator) Address() common.Address {
+	return c.address
+}
+
+// CumulativeClaimed returns the total amount of token the earner has claimed so far
+func (c *Coordinator) CumulativeClaimed(ctx context.Context, earner common.Address, token common.Address) (*big.Int, error) {
+	var out []interface{}
+	if err := c.contract.Call(&bind.CallOpts{Context: ctx}, &out, "cumulativeClaimed", earner, token); err != nil {
+		return nil, fmt.Errorf("failed to call cumulativeClaimed: %w", err)
+	}
+	return *abi.ConvertType(out[0], new(*big.Int)).(**big.Int), nil
+}
+
+// CurrentDistributionRoot returns the latest distribution root, which may not be claimable yet
+func (c *Coordinator) CurrentDistributionRoot(ctx context.Context) (*DistributionRoot, error) {
+	var out []interface{}
+	if err := c.contract.Call(&bind.CallOpts{Context: ctx}, &out, "getCurrentDistributionRoot"); err != nil {
+		return nil, fmt.Errorf("failed to call getCurrentDistributionRoot: %w", err)
+	}
+	root := abi.ConvertType(out[0], new(DistributionRoot)).(*DistributionRoot)
+	return root, nil
+}
+
+// DistributionRootsLength returns the number of distribution roots posted so far
+func (c *Coordinator) DistributionRootsLength(ctx context.Context) (uint64, error) {
+	var out []interface{}
+	if err := c.contract.Call(&bind.CallOpts{Context: ctx}, &out, "getDistributionRootsLength"); err != nil {
+		return 0, fmt.Errorf("failed to call getDistributionRootsLength: %w", err)
+	}
+	length := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	return length.Uint64(), nil
+}
+
+// CheckClaim returns whether the claim would be accepted by the RewardsCoordinator
+func (c *Coordinator) CheckClaim(ctx context.Context, claim RewardsMerkleClaim) (bool, error) {
+	var out []interface{}
+	if err := c.contract.Call(&bind.CallOpts{Context: ctx}, &out, "checkClaim", claim); err != nil {
+		return false, fmt.Errorf("failed to call checkClaim: %w", err)
+	}
+	return *abi.ConvertType(out[0], new(bool)).(*bool), nil
+}
+
+// ProcessClaim submits the claim, paying the rewards out to recipient
+func (c *Coordinator) ProcessClaim(opts *bind.TransactOpts, claim RewardsMerkleClaim, recipient common.Address) (*gethtypes.Transaction, error) {
+	tx, err := c.contract.Transact(opts, "processClaim", claim, recipient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send processClaim: %w", err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,231 @@
+package rewards
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// maxEpochHistory bounds the number of per-epoch reports kept in memory
+	maxEpochHistory = 64
+)
+
+// EpochEarnings is the amount earned per token during one rewards epoch, i.e.
+// between two consecutive distribution roots
+type EpochEarnings struct {
+	RewardsCalculationEndTimestamp uint32                      `json:"rewardsCalculationEndTimestamp"`
+	Earned                         map[common.Address]*big.Int `json:"earned"`
+	ReportedAt                     time.Time                   `json:"reportedAt"`
+}
+
+// Earnings is a snapshot of the operator's rewards
+type Earnings struct {
+	Earner    common.Address  `json:"earner"`
+	Tokens    []TokenRewards  `json:"tokens"`
+	Epochs    []EpochEarnings `json:"epochs"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// Tracker periodically queries claimable and historical rewards for an earner,
+// exposes them as metrics and reports earnings for each new rewards epoch
+type Tracker struct {
+	earner      common.Address
+	coordinator *Coordinator
+	api         *ApiClient
+	interval    time.Duration
+	logger      logging.Logger
+
+	mu            sync.RWMutex
+	tokens        []TokenRewards
+	epochs        []EpochEarnings
+	lastEpoch     uint32
+	earnedAtEpoch map[common.Address]*big.Int
+	updatedAt     time.Time
+
+	earnedGauge      *prometheus.GaugeVec
+	claimedGauge     *prometheus.GaugeVec
+	claimableGauge   *prometheus.GaugeVec
+	epochEarnedGauge *prometheus.GaugeVec
+}
+
+func NewTracker(
+	earner common.Address,
+	coordinator *Coordinator,
+	api *ApiClient,
+	interval time.Duration,
+	reg prometheus.Registerer,
+	logger logging.Logger,
+) *Tracker {
+	tracker := &Tracker{
+		earner:        earner,
+		coordinator:   coordinator,
+		api:           api,
+		interval:      interval,
+		logger:        logger.With("component", "rewards-tracker"),
+		earnedAtEpoch: make(map[common.Address]*big.Int),
+
+		earnedGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator_rewards",
+			Name:      "earned",
+			Help:      "Lifetime rewards earned by the operator, in token base units",
+		}, []string{"token"}),
+		claimedGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator_rewards",
+			Name:      "claimed",
+			Help:      "Rewards claimed by the operator so far, in token base units",
+		}, []string{"token"}),
+		claimableGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator_rewards",
+			Name:      "claimable",
+			Help:      "Rewards currently claimable by the operator, in token base units",
+		}, []string{"token"}),
+		epochEarnedGauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "operator_rewards",
+			Name:      "last_epoch_earned",
+			Help:      "Rewards earned during the most recent rewards epoch, in token base units",
+		}, []string{"token"}),
+	}
+
+	reg.MustRegister(tracker.earnedGauge, tracker.claimedGauge, tracker.claimableGauge, tracker.epochEarnedGauge)
+
+	return tracker
+}
+
+// Start polls for rewards until ctx is done
+func (t *Tracker) Start(ctx context.Context) {
+	t.logger.Info("Starting rewards tracker", "earner", t.earner.Hex(), "interval", t.interval)
+
+	if err := t.Update(ctx); err != nil {
+		t.logger.Warn("Failed to update rewards", "error", err)
+	}
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.Update(ctx); err != nil {
+				t.logger.Warn("Failed to update rewards", "error", err)
+			}
+		}
+	}
+}
+
+// Update refreshes the rewards snapshot and records an epoch report when a new
+// distribution root has been posted since the last update
+func (t *Tracker) Update(ctx context.Context) error {
+	tokens, err := t.api.GetSummarizedRewards(ctx, t.earner)
+	if err != nil {
+		return err
+	}
+
+	// The contract is the source of truth for what has actually been claimed
+	for i := range tokens {
+		claimed, err := t.coordinator.CumulativeClaimed(ctx, t.earner, tokens[i].Token)
+		if err != nil {
+			return err
+		}
+		tokens[i].Claimed = claimed
+	}
+
+	root, err := t.coordinator.CurrentDistributionRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tokens = tokens
+	t.updatedAt = time.Now()
+
+	for _, token := range tokens {
+		t.earnedGauge.WithLabelValues(token.Token.Hex()).Set(toFloat(token.Earned))
+		t.claimedGauge.WithLabelValues(token.Token.Hex()).Set(toFloat(token.Claimed))
+		t.claimableGauge.WithLabelValues(token.Token.Hex()).Set(toFloat(token.Claimable))
+	}
+
+	if root.RewardsCalculationEndTimestamp > t.lastEpoch {
+		t.recordEpoch(root.RewardsCalculationEndTimestamp, tokens)
+	}
+
+	return nil
+}
+
+// recordEpoch computes what was earned since the previous epoch. The first epoch
+// observed only sets the baseline. Callers must hold the write lock.
+func (t *Tracker) recordEpoch(epoch uint32, tokens []TokenRewards) {
+	firstEpoch := t.lastEpoch == 0
+	t.lastEpoch = epoch
+
+	earned := make(map[common.Address]*big.Int, len(tokens))
+	for _, token := range tokens {
+		previous, ok := t.earnedAtEpoch[token.Token]
+		if !ok {
+			previous = big.NewInt(0)
+		}
+		earned[token.Token] = new(big.Int).Sub(token.Earned, previous)
+		t.earnedAtEpoch[token.Token] = new(big.Int).Set(token.Earned)
+	}
+
+	if firstEpoch {
+		return
+	}
+
+	t.epochs = append(t.epochs, EpochEarnings{
+		RewardsCalculationEndTimestamp: epoch,
+		Earned:                         earned,
+		ReportedAt:                     time.Now(),
+	})
+	if len(t.epochs) > maxEpochHistory {
+		t.epochs = t.epochs[len(t.epochs)-maxEpochHistory:]
+	}
+
+	for token, amount := range earned {
+		t.epochEarnedGauge.WithLabelValues(token.Hex()).Set(toFloat(amount))
+		t.logger.Info("Rewards epoch report",
+			"rewardsCalculationEndTimestamp", epoch,
+			"token", token.Hex(),
+			"earned", amount.String(),
+		)
+	}
+}
+
+// Earnings returns the latest rewards snapshot
+func (t *Tracker) Earnings() Earnings {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	tokens := make([]TokenRewards, len(t.tokens))
+	copy(tokens, t.tokens)
+	epochs := make([]EpochEarnings, len(t.epochs))
+	copy(epochs, t.epochs)
+
+	return Earnings{
+		Earner:    t.earner,
+		Tokens:    tokens,
+		Epochs:    epochs,
+		UpdatedAt: t.updatedAt,
+	}
+}
+
+func toFloat(amount *big.Int) float64 {
+	if amount == nil {
+		return 0
+	}
+	f, _ := new(big.Float).SetInt(amount).Float64()
+	return f
+}
@@ -0,0 +1,526 @@
+// Package rpccache runs a local JSON-RPC proxy that caches read calls in
+// front of an Ethereum node. Every component of a process that dials the
+// proxy's URL instead of the node shares one cache, so state read by several
+// of them for the same task costs one upstream call.
+//
+// Responses are keyed by method, params and the block they were read at:
+//
+//   - Calls at a block hash, or at a block number at least ReorgDepth below
+//     the head, can't change and stay cached until evicted.
+//   - Calls at "latest", "safe", "finalized" or a recent block number are
+//     keyed by the head block as well, so they are only reused until the
+//     head moves. The head is refreshed at most every HeadTtl.
+//   - Calls at "pending", writes and anything not listed pass through.
+//
+// Identical calls in flight at the same time are sent upstream once.
+package rpccache
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultSize is how many responses are cached when Config.Size is unset
+	DefaultSize = 10000
+	// DefaultHeadTtl is how long the head block is reused when
+	// Config.HeadTtl is unset
+	DefaultHeadTtl = time.Second
+	// DefaultReorgDepth is Config.ReorgDepth when unset
+	DefaultReorgDepth = 64
+
+	// upstreamTimeout bounds one forwarded request
+	upstreamTimeout = 30 * time.Second
+	// maxRequestBytes bounds a request body
+	maxRequestBytes = 16 << 20
+)
+
+// ErrUnsupportedUpstream is returned for upstreams that aren't HTTP endpoints
+var ErrUnsupportedUpstream = errors.New("only http upstreams can be cached")
+
+// Config configures a Proxy
+type Config struct {
+	// Size is how many responses are kept
+	Size int
+	// HeadTtl is how long the head block number is assumed current
+	HeadTtl time.Duration
+	// ReorgDepth is how far below the head a block must be to never change
+	ReorgDepth uint64
+}
+
+// blockParams is the position of the block parameter of the cached methods
+// that read state at a block
+var blockParams = map[string]int{
+	"eth_call":                1,
+	"eth_getBalance":          1,
+	"eth_getCode":             1,
+	"eth_getStorageAt":        2,
+	"eth_getTransactionCount": 1,
+	"eth_getProof":            2,
+	"eth_getBlockByNumber":    0,
+}
+
+// immutableMethods return the same result whatever the head
+var immutableMethods = map[string]bool{
+	"eth_chainId":        true,
+	"net_version":        true,
+	"eth_getBlockByHash": true,
+}
+
+type request struct {
+	JsonRpc string            `json:"jsonrpc"`
+	Id      json.RawMessage   `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JsonRpc string          `json:"jsonrpc"`
+	Id      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// Proxy is a caching JSON-RPC proxy listening on a loopback port
+type Proxy struct {
+	upstream string
+	config   Config
+	client   *http.Client
+	listener net.Listener
+	server   *http.Server
+
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List
+	inflight map[string]*call
+
+	headMu        sync.Mutex
+	head          uint64
+	headFetchedAt time.Time
+
+	requests *prometheus.CounterVec
+}
+
+type entry struct {
+	key    string
+	result json.RawMessage
+}
+
+// call is an upstream call other requests for the same key wait on
+type call struct {
+	done   chan struct{}
+	result json.RawMessage
+	err    json.RawMessage
+}
+
+var shared = struct {
+	sync.Mutex
+	proxies map[string]*Proxy
+}{proxies: make(map[string]*Proxy)}
+
+// Shared returns the process's proxy in front of upstream, starting it on
+// first use. Later calls get the same proxy whatever their config.
+func Shared(upstream string, config Config) (*Proxy, error) {
+	shared.Lock()
+	defer shared.Unlock()
+
+	if proxy, ok := shared.proxies[upstream]; ok {
+		return proxy, nil
+	}
+	proxy, err := NewProxy(upstream, config)
+	if err != nil {
+		return nil, err
+	}
+	shared.proxies[upstream] = proxy
+	return proxy, nil
+}
+
+// NewProxy starts a proxy in front of upstream
+func NewProxy(upstream string, config Config) (*Proxy, error) {
+	if !strings.HasPrefix(upstream, "http://") && !strings.HasPrefix(upstream, "https://") {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedUpstream, upstream)
+	}
+	if config.Size <= 0 {
+		config.Size = DefaultSize
+	}
+	if config.HeadTtl <= 0 {
+		config.HeadTtl = DefaultHeadTtl
+	}
+	if config.ReorgDepth == 0 {
+		config.ReorgDepth = DefaultReorgDepth
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for rpc cache: %w", err)
+	}
+
+	p := &Proxy{
+		upstream: upstream,
+		config:   config,
+		client:   &http.Client{Timeout: upstreamTimeout},
+		listener: listener,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		inflight: make(map[string]*call),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "eigenlvr",
+			Subsystem: "rpc_cache",
+			Name:      "requests_total",
+			Help:      "JSON-RPC calls through the cache by method and outcome (hit, miss or bypass)",
+		}, []string{"method", "outcome"}),
+	}
+	p.server = &http.Server{Handler: p}
+	go p.server.Serve(listener)
+	return p, nil
+}
+
+// Url is the proxy's endpoint, to dial instead of the upstream
+func (p *Proxy) Url() string {
+	return "http://" + p.listener.Addr().String()
+}
+
+// Collector returns the proxy's request counter for a metrics registry
+func (p *Proxy) Collector() prometheus.Collector {
+	return p.requests
+}
+
+// Close stops the proxy
+func (p *Proxy) Close() error {
+	return p.server.Close()
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBytes))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	batch := len(bytes.TrimSpace(body)) > 0 && bytes.TrimSpace(body)[0] == '['
+	var requests []request
+	if batch {
+		err = json.Unmarshal(body, &requests)
+	} else {
+		requests = make([]request, 1)
+		err = json.Unmarshal(body, &requests[0])
+	}
+	if err != nil {
+		// Leave it to the upstream to describe what's wrong
+		p.passThrough(w, r, body)
+		return
+	}
+
+	responses := make([]response, len(requests))
+	for i := range requests {
+		responses[i] = p.handle(r.Context(), requests[i])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if batch {
+		json.NewEncoder(w).Encode(responses)
+	} else {
+		json.NewEncoder(w).Encode(responses[0])
+	}
+}
+
+// handle answers one call from the cache or upstream
+func (p *Proxy) handle(ctx context.Context, req request) response {
+	reply := response{JsonRpc: "2.0", Id: req.Id}
+
+	if req.Method == "eth_blockNumber" {
+		head, err := p.headBlock(ctx)
+		if err != nil {
+			reply.Error = rpcError(err)
+			return reply
+		}
+		reply.Result, _ = json.Marshal(hexutil.Uint64(head))
+		return reply
+	}
+
+	key, cacheable, err := p.cacheKey(ctx, req)
+	if err != nil {
+		reply.Error = rpcError(err)
+		return reply
+	}
+	if !cacheable {
+		p.requests.WithLabelValues(req.Method, "bypass").Inc()
+		reply.Result, reply.Error = p.forward(ctx, req)
+		return reply
+	}
+
+	reply.Result, reply.Error = p.cached(ctx, key, req)
+	return reply
+}
+
+// cached serves key from the cache, or calls upstream once for every request
+// waiting on it
+func (p *Proxy) cached(ctx context.Context, key string, req request) (json.RawMessage, json.RawMessage) {
+	p.mu.Lock()
+	if element, ok := p.entries[key]; ok {
+		p.lru.MoveToFront(element)
+		result := element.Value.(*entry).result
+		p.mu.Unlock()
+		p.requests.WithLabelValues(req.Method, "hit").Inc()
+		return result, nil
+	}
+	if pending, ok := p.inflight[key]; ok {
+		p.mu.Unlock()
+		p.requests.WithLabelValues(req.Method, "hit").Inc()
+		select {
+		case <-pending.done:
+			return pending.result, pending.err
+		case <-ctx.Done():
+			return nil, rpcError(ctx.Err())
+		}
+	}
+	pending := &call{done: make(chan struct{})}
+	p.inflight[key] = pending
+	p.mu.Unlock()
+
+	p.requests.WithLabelValues(req.Method, "miss").Inc()
+	// Waiters share the result, so one caller's cancellation mustn't fail it
+	pending.result, pending.err = p.forward(context.WithoutCancel(ctx), req)
+
+	p.mu.Lock()
+	delete(p.inflight, key)
+	// A null result is a block or state the node doesn't have yet
+	if pending.err == nil && len(pending.result) > 0 && string(pending.result) != "null" {
+		p.store(key, pending.result)
+	}
+	p.mu.Unlock()
+	close(pending.done)
+
+	return pending.result, pending.err
+}
+
+// store adds a result, evicting the least recently used. Callers must hold mu.
+func (p *Proxy) store(key string, result json.RawMessage) {
+	p.entries[key] = p.lru.PushFront(&entry{key: key, result: result})
+	for p.lru.Len() > p.config.Size {
+		oldest := p.lru.Back()
+		p.lru.Remove(oldest)
+		delete(p.entries, oldest.Value.(*entry).key)
+	}
+}
+
+// cacheKey returns the key a call is cached under, or false when the call
+// must go upstream
+func (p *Proxy) cacheKey(ctx context.Context, req request) (string, bool, error) {
+	params, err := json.Marshal(req.Params)
+	if err != nil {
+		return "", false, nil
+	}
+	key := req.Method + string(params)
+
+	if immutableMethods[req.Method] {
+		return key, true, nil
+	}
+	if req.Method == "eth_getLogs" {
+		return p.logsKey(ctx, key, req)
+	}
+
+	position, ok := blockParams[req.Method]
+	if !ok {
+		return "", false, nil
+	}
+	var block json.RawMessage
+	if position < len(req.Params) {
+		block = req.Params[position]
+	}
+	return p.blockKey(ctx, key, block)
+}
+
+// blockKey qualifies key by the block a block parameter refers to
+func (p *Proxy) blockKey(ctx context.Context, key string, block json.RawMessage) (string, bool, error) {
+	var tag string
+	if len(block) == 0 || string(block) == "null" {
+		tag = "latest"
+	} else if err := json.Unmarshal(block, &tag); err != nil {
+		// EIP-1898 block object: a hash pins the block, a number is a number
+		var object struct {
+			BlockHash   *string         `json:"blockHash"`
+			BlockNumber *hexutil.Uint64 `json:"blockNumber"`
+		}
+		if err := json.Unmarshal(block, &object); err != nil {
+			return "", false, nil
+		}
+		if object.BlockHash != nil {
+			return key, true, nil
+		}
+		if object.BlockNumber == nil {
+			return "", false, nil
+		}
+		tag = hexutil.EncodeUint64(uint64(*object.BlockNumber))
+	}
+
+	switch tag {
+	case "pending":
+		return "", false, nil
+	case "earliest":
+		return key, true, nil
+	case "latest", "safe", "finalized":
+		return p.atHead(ctx, key)
+	}
+
+	number, err := hexutil.DecodeUint64(tag)
+	if err != nil {
+		return "", false, nil
+	}
+	return p.atNumber(ctx, key, number)
+}
+
+// logsKey qualifies an eth_getLogs key by the range the filter covers
+func (p *Proxy) logsKey(ctx context.Context, key string, req request) (string, bool, error) {
+	if len(req.Params) != 1 {
+		return "", false, nil
+	}
+	var filter struct {
+		BlockHash *string `json:"blockHash"`
+		FromBlock *string `json:"fromBlock"`
+		ToBlock   *string `json:"toBlock"`
+	}
+	if err := json.Unmarshal(req.Params[0], &filter); err != nil {
+		return "", false, nil
+	}
+	if filter.BlockHash != nil {
+		return key, true, nil
+	}
+	if filter.ToBlock == nil || filter.FromBlock == nil {
+		return p.atHead(ctx, key)
+	}
+	toBlock, err := hexutil.DecodeUint64(*filter.ToBlock)
+	if err != nil {
+		if *filter.ToBlock == "pending" {
+			return "", false, nil
+		}
+		return p.atHead(ctx, key)
+	}
+	return p.atNumber(ctx, key, toBlock)
+}
+
+// atNumber keys a call at a block number, which is final once deep enough
+func (p *Proxy) atNumber(ctx context.Context, key string, number uint64) (string, bool, error) {
+	head, err := p.headBlock(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	if number+p.config.ReorgDepth <= head {
+		return key, true, nil
+	}
+	return key + "@" + strconv.FormatUint(head, 10), true, nil
+}
+
+// atHead keys a call by the current head
+func (p *Proxy) atHead(ctx context.Context, key string) (string, bool, error) {
+	head, err := p.headBlock(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	return key + "@" + strconv.FormatUint(head, 10), true, nil
+}
+
+// headBlock returns the head block number, refreshed at most every HeadTtl
+func (p *Proxy) headBlock(ctx context.Context) (uint64, error) {
+	p.headMu.Lock()
+	defer p.headMu.Unlock()
+
+	if !p.headFetchedAt.IsZero() && time.Since(p.headFetchedAt) < p.config.HeadTtl {
+		p.requests.WithLabelValues("eth_blockNumber", "hit").Inc()
+		return p.head, nil
+	}
+
+	p.requests.WithLabelValues("eth_blockNumber", "miss").Inc()
+	result, rpcErr := p.forward(ctx, request{JsonRpc: "2.0", Id: json.RawMessage("1"), Method: "eth_blockNumber"})
+	if rpcErr != nil {
+		return 0, fmt.Errorf("failed to read head block: %s", rpcErr)
+	}
+	var head hexutil.Uint64
+	if err := json.Unmarshal(result, &head); err != nil {
+		return 0, fmt.Errorf("failed to decode head block: %w", err)
+	}
+
+	// The head never moves backwards, whichever node behind the upstream answered
+	p.head = max(p.head, uint64(head))
+	p.headFetchedAt = time.Now()
+	return p.head, nil
+}
+
+// forward sends one call upstream and returns its result or error object
+func (p *Proxy) forward(ctx context.Context, req request) (json.RawMessage, json.RawMessage) {
+	if req.Params == nil {
+		req.Params = []json.RawMessage{}
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, p.upstream, bytes.NewReader(body))
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	httpResponse, err := p.client.Do(httpRequest)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	defer httpResponse.Body.Close()
+
+	var reply response
+	if err := json.NewDecoder(io.LimitReader(httpResponse.Body, maxRequestBytes)).Decode(&reply); err != nil {
+		return nil, rpcError(fmt.Errorf("upstream returned %s: %w", httpResponse.Status, err))
+	}
+	if len(reply.Error) > 0 && string(reply.Error) != "null" {
+		return nil, reply.Error
+	}
+	return reply.Result, nil
+}
+
+// passThrough relays a request the proxy couldn't parse
+func (p *Proxy) passThrough(w http.ResponseWriter, r *http.Request, body []byte) {
+	httpRequest, err := http.NewRequestWithContext(r.Context(), http.MethodPost, p.upstream, bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpResponse, err := p.client.Do(httpRequest)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer httpResponse.Body.Close()
+
+	w.Header().Set("Content-Type", httpResponse.Header.Get("Content-Type"))
+	w.WriteHeader(httpResponse.StatusCode)
+	io.Copy(w, httpResponse.Body)
+}
+
+// rpcError is a JSON-RPC error object for a failure of the proxy itself
+func rpcError(err error) json.RawMessage {
+	encoded, _ := json.Marshal(map[string]interface{}{
+		"code":    -32603,
+		"message": err.Error(),
+	})
+	return encoded
+}
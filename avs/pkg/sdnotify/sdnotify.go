@@ -0,0 +1,147 @@
+// Package sdnotify implements the systemd notification protocol, so the
+// operator and aggregator can run as Type=notify services with a watchdog.
+// Outside systemd, where NOTIFY_SOCKET is unset, every call is a no-op.
+//
+// A unit using it looks like:
+//
+//	[Service]
+//	Type=notify
+//	NotifyAccess=main
+//	WatchdogSec=60s
+//	Restart=on-failure
+//
+// WatchdogSec must be longer than the slowest loop reporting to the Watchdog.
+package sdnotify
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+)
+
+// Notification states
+const (
+	StateReady    = "READY=1"
+	StateStopping = "STOPPING=1"
+	stateWatchdog = "WATCHDOG=1"
+)
+
+// Notify sends the state to systemd. It reports whether a notification
+// socket was configured at all.
+func Notify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	// Abstract namespace sockets are passed with a leading @
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return true, err
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(state))
+	return true, err
+}
+
+// Ready tells systemd the service has finished starting up
+func Ready() error {
+	_, err := Notify(StateReady)
+	return err
+}
+
+// Stopping tells systemd the service is shutting down
+func Stopping() error {
+	_, err := Notify(StateStopping)
+	return err
+}
+
+// Status sets the free-form status shown by systemctl status
+func Status(status string) error {
+	_, err := Notify("STATUS=" + status)
+	return err
+}
+
+// WatchdogInterval returns the watchdog timeout systemd expects to be petted
+// within, and false when the watchdog isn't enabled for this process
+func WatchdogInterval() (time.Duration, bool) {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, false
+	}
+	return time.Duration(usec) * time.Microsecond, true
+}
+
+// Watchdog pets the systemd watchdog only while every loop registered with it
+// keeps beating. A loop stuck on a deadlock or a hung call stops beating, the
+// watchdog goes unpetted and systemd restarts the process.
+type Watchdog struct {
+	mu    sync.Mutex
+	beats map[string]time.Time
+}
+
+func NewWatchdog() *Watchdog {
+	return &Watchdog{beats: make(map[string]time.Time)}
+}
+
+// Beat records that the named loop is making progress. The first beat
+// registers the loop.
+func (w *Watchdog) Beat(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.beats[name] = time.Now()
+}
+
+// stalled returns a loop that hasn't beaten within timeout, if any
+func (w *Watchdog) stalled(timeout time.Duration) (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cutoff := time.Now().Add(-timeout)
+	for name, beat := range w.beats {
+		if beat.Before(cutoff) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Run pets the watchdog at half its interval until ctx is done. It returns
+// immediately when the watchdog isn't enabled.
+func (w *Watchdog) Run(ctx context.Context, logger logging.Logger) {
+	interval, ok := WatchdogInterval()
+	if !ok {
+		return
+	}
+	logger.Info("Systemd watchdog enabled", "interval", interval)
+
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if name, stalled := w.stalled(interval); stalled {
+				logger.Error("Loop stalled, withholding systemd watchdog", "loop", name)
+				continue
+			}
+			if _, err := Notify(stateWatchdog); err != nil {
+				logger.Warn("Failed to pet systemd watchdog", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,279 @@
+// Package seqfeed follows an L2 sequencer's feed, which publishes blocks as
+// the sequencer orders them, before RPC nodes have them. Two feeds are
+// understood:
+//
+//   - Arbitrum's sequencer feed carries the sequenced messages but not their
+//     execution, so it only signals that a new block is coming.
+//   - OP Stack flashblocks, as served by rollup-boost, carry every partial
+//     block's receipts, so the logs of a block are known before it is sealed.
+//     Frames must be uncompressed JSON; a brotli-compressed stream needs a
+//     decompressing proxy in front of it.
+//
+// Everything from a feed is a preconfirmation. Consumers still have to
+// reconcile with the chain, which is what the RPC paths already do.
+package seqfeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	KindArbitrum    = "arbitrum"
+	KindFlashblocks = "flashblocks"
+
+	// Reconnect backoff for the feed websocket
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+
+	// readTimeout is how long the feed may stay silent before the connection
+	// is assumed dead. Both sequencers publish several times a second.
+	readTimeout = time.Minute
+	// maxMessageBytes bounds a single feed frame
+	maxMessageBytes = 16 << 20
+)
+
+// Config configures a Feed
+type Config struct {
+	// Kind is KindArbitrum or KindFlashblocks
+	Kind string
+	Url  string
+	// Addresses and Topics filter the delivered logs like an eth_getLogs
+	// filter. Only flashblocks carry logs.
+	Addresses []common.Address
+	Topics    [][]common.Hash
+}
+
+// Handler receives what the feed publishes. Either function may be nil.
+type Handler struct {
+	// Block is called once per new block. Arbitrum messages don't carry the
+	// L2 block number, so it is 0 for them.
+	Block func(number uint64)
+	// Log is called for every preconfirmed log matching the filter
+	Log func(log gethtypes.Log)
+}
+
+// Feed follows a sequencer feed
+type Feed struct {
+	config Config
+	logger logging.Logger
+}
+
+func NewFeed(config Config, logger logging.Logger) (*Feed, error) {
+	switch config.Kind {
+	case KindArbitrum, KindFlashblocks:
+	default:
+		return nil, fmt.Errorf("unknown sequencer feed kind %q", config.Kind)
+	}
+	if !strings.HasPrefix(config.Url, "ws://") && !strings.HasPrefix(config.Url, "wss://") {
+		return nil, fmt.Errorf("sequencer feed url must be a websocket url: %q", config.Url)
+	}
+	return &Feed{
+		config: config,
+		logger: logger.With("component", "sequencer-feed", "kind", config.Kind),
+	}, nil
+}
+
+// Run follows the feed until ctx is done, reconnecting with exponential
+// backoff whenever the connection drops
+func (f *Feed) Run(ctx context.Context, handler Handler) {
+	backoff := minBackoff
+
+	for {
+		connectedAt := time.Now()
+		err := f.follow(ctx, handler)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A connection that stayed up for a while resets the backoff
+		if time.Since(connectedAt) > maxBackoff {
+			backoff = minBackoff
+		}
+		f.logger.Warn("Sequencer feed disconnected", "error", err, "retryIn", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// follow connects and handles frames until the connection fails
+func (f *Feed) follow(ctx context.Context, handler Handler) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, f.config.Url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to dial sequencer feed: %w", err)
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxMessageBytes)
+	f.logger.Info("Connected to sequencer feed", "url", f.config.Url)
+
+	// Unblock the read below once ctx is done
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(readTimeout))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("failed to read from sequencer feed: %w", err)
+		}
+
+		switch f.config.Kind {
+		case KindArbitrum:
+			err = f.handleArbitrum(data, handler)
+		case KindFlashblocks:
+			err = f.handleFlashblock(data, handler)
+		}
+		if err != nil {
+			f.logger.Debug("Skipping undecodable sequencer feed frame", "error", err)
+		}
+	}
+}
+
+// arbitrumBroadcast is a frame of the Arbitrum sequencer feed. Only the
+// sequence numbers are read; each message becomes one L2 block.
+type arbitrumBroadcast struct {
+	Messages []struct {
+		SequenceNumber uint64 `json:"sequenceNumber"`
+	} `json:"messages"`
+}
+
+func (f *Feed) handleArbitrum(data []byte, handler Handler) error {
+	var broadcast arbitrumBroadcast
+	if err := json.Unmarshal(data, &broadcast); err != nil {
+		return err
+	}
+	if handler.Block == nil {
+		return nil
+	}
+	for range broadcast.Messages {
+		handler.Block(0)
+	}
+	return nil
+}
+
+// flashblock is a partial block published by rollup-boost. Index 0 starts a
+// block; receipts are keyed by transaction hash and wrapped in an object
+// named after the transaction type.
+type flashblock struct {
+	Index    uint64 `json:"index"`
+	Metadata struct {
+		BlockNumber flexUint64                                   `json:"block_number"`
+		Receipts    map[common.Hash]map[string]flashblockReceipt `json:"receipts"`
+	} `json:"metadata"`
+}
+
+type flashblockReceipt struct {
+	Logs []struct {
+		Address common.Address `json:"address"`
+		Topics  []common.Hash  `json:"topics"`
+		Data    hexutil.Bytes  `json:"data"`
+	} `json:"logs"`
+}
+
+func (f *Feed) handleFlashblock(data []byte, handler Handler) error {
+	if len(data) == 0 || data[0] != '{' {
+		return errors.New("frame is not uncompressed json")
+	}
+	var block flashblock
+	if err := json.Unmarshal(data, &block); err != nil {
+		return err
+	}
+	blockNumber := uint64(block.Metadata.BlockNumber)
+
+	if block.Index == 0 && handler.Block != nil {
+		handler.Block(blockNumber)
+	}
+	if handler.Log == nil {
+		return nil
+	}
+	for txHash, wrapped := range block.Metadata.Receipts {
+		for _, receipt := range wrapped {
+			for i, feedLog := range receipt.Logs {
+				log := gethtypes.Log{
+					Address:     feedLog.Address,
+					Topics:      feedLog.Topics,
+					Data:        feedLog.Data,
+					BlockNumber: blockNumber,
+					TxHash:      txHash,
+					Index:       uint(i),
+				}
+				if f.matches(log) {
+					handler.Log(log)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matches applies the address and topic filter the way eth_getLogs does
+func (f *Feed) matches(log gethtypes.Log) bool {
+	if len(f.config.Addresses) > 0 {
+		found := false
+		for _, address := range f.config.Addresses {
+			if address == log.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(f.config.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, alternatives := range f.config.Topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range alternatives {
+			if topic == log.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// flexUint64 decodes a JSON number or a hex quantity string
+type flexUint64 uint64
+
+func (u *flexUint64) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var quantity hexutil.Uint64
+		if err := quantity.UnmarshalJSON(data); err != nil {
+			return err
+		}
+		*u = flexUint64(quantity)
+		return nil
+	}
+	value, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	*u = flexUint64(value)
+	return nil
+}
@@ -0,0 +1,375 @@
+// Package servicemanager decodes events from and reads state of the
+// EigenLVRAVSServiceManager contract.
+package servicemanager
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/eigenlvr/avs/pkg/sigchecker"
+)
+
+// auctionTaskComponents is the ABI of EigenLVRAVSServiceManager.AuctionTask
+const auctionTaskComponents = `[
+	{"name":"poolId","type":"bytes32"},
+	{"name":"blockNumber","type":"uint256"},
+	{"name":"taskCreatedBlock","type":"uint256"},
+	{"name":"quorumNumbers","type":"bytes"},
+	{"name":"quorumThresholdPercentage","type":"uint32"}
+]`
+
+// auctionTaskResponseComponents is the ABI of EigenLVRAVSServiceManager.AuctionTaskResponse
+const auctionTaskResponseComponents = `[
+	{"name":"referenceTaskIndex","type":"uint32"},
+	{"name":"winner","type":"address"},
+	{"name":"winningBid","type":"uint256"},
+	{"name":"totalBids","type":"uint256"}
+]`
+
+// auctionTaskResponseMetadataComponents is the ABI of
+// EigenLVRAVSServiceManager.AuctionTaskResponseMetadata
+const auctionTaskResponseMetadataComponents = `[
+	{"name":"taskResponsedBlock","type":"uint32"},
+	{"name":"hashOfNonSigners","type":"bytes32"}
+]`
+
+// g1PointComponents is the ABI of BN254.G1Point
+const g1PointComponents = `[{"name":"X","type":"uint256"},{"name":"Y","type":"uint256"}]`
+
+// serviceManagerAbi is the subset of EigenLVRAVSServiceManager used off-chain
+const serviceManagerAbi = `[
+	{"type":"event","name":"NewAuctionTaskCreated","anonymous":false,"inputs":[{"name":"taskIndex","type":"uint32","indexed":true},{"name":"task","type":"tuple","indexed":false,"components":%[1]s}]},
+	{"type":"event","name":"AuctionTaskResponded","anonymous":false,"inputs":[{"name":"taskResponse","type":"tuple","indexed":false,"components":%[2]s},{"name":"taskResponseMetadata","type":"tuple","indexed":false,"components":%[4]s}]},
+	{"type":"event","name":"TaskCompleted","anonymous":false,"inputs":[{"name":"taskIndex","type":"uint32","indexed":true}]},
+	{"type":"event","name":"TaskChallenged","anonymous":false,"inputs":[{"name":"taskIndex","type":"uint32","indexed":true},{"name":"challenger","type":"address","indexed":false}]},
+	{"type":"event","name":"AuctionTaskExpired","anonymous":false,"inputs":[{"name":"taskIndex","type":"uint32","indexed":true}]},
+	{"type":"function","name":"latestTaskNum","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint32"}]},
+	{"type":"function","name":"allTaskHashes","stateMutability":"view","inputs":[{"name":"","type":"uint32"}],"outputs":[{"name":"","type":"bytes32"}]},
+	{"type":"function","name":"allTaskResponses","stateMutability":"view","inputs":[{"name":"","type":"uint32"}],"outputs":[{"name":"","type":"bytes32"}]},
+	{"type":"function","name":"TASK_CHALLENGE_WINDOW_BLOCK","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint32"}]},
+	{"type":"function","name":"expireAuctionTask","stateMutability":"nonpayable","inputs":[{"name":"task","type":"tuple","components":%[1]s},{"name":"taskIndex","type":"uint32"}],"outputs":[]},
+	{"type":"function","name":"raiseAndResolveChallenge","stateMutability":"nonpayable","inputs":[{"name":"task","type":"tuple","components":%[1]s},{"name":"taskResponse","type":"tuple","components":%[2]s},{"name":"taskResponseMetadata","type":"tuple","components":%[4]s},{"name":"pubkeysOfNonSigningOperators","type":"tuple[]","components":%[5]s}],"outputs":[]},
+	{"type":"function","name":"respondToAuctionTask","stateMutability":"nonpayable","inputs":[{"name":"task","type":"tuple","components":%[1]s},{"name":"taskResponse","type":"tuple","components":%[2]s},{"name":"nonSignerStakesAndSignature","type":"tuple","components":%[3]s}],"outputs":[]}
+]`
+
+// ABI is the parsed service manager ABI
+var ABI = mustParseAbi()
+
+var (
+	// NewAuctionTaskCreatedTopic is the topic of the NewAuctionTaskCreated event
+	NewAuctionTaskCreatedTopic = ABI.Events["NewAuctionTaskCreated"].ID
+	// AuctionTaskRespondedTopic is the topic of the AuctionTaskResponded event
+	AuctionTaskRespondedTopic = ABI.Events["AuctionTaskResponded"].ID
+	// TaskCompletedTopic is the topic of the TaskCompleted event
+	TaskCompletedTopic = ABI.Events["TaskCompleted"].ID
+	// TaskChallengedTopic is the topic of the TaskChallenged event
+	TaskChallengedTopic = ABI.Events["TaskChallenged"].ID
+	// AuctionTaskExpiredTopic is the topic of the AuctionTaskExpired event
+	AuctionTaskExpiredTopic = ABI.Events["AuctionTaskExpired"].ID
+
+	// ErrUnexpectedEvent is returned when a log is not the event being parsed
+	ErrUnexpectedEvent = errors.New("log is not the expected service manager event")
+	// ErrNotResponse is returned for a transaction that isn't a direct
+	// respondToAuctionTask call to the service manager
+	ErrNotResponse = errors.New("transaction is not a respondToAuctionTask call")
+)
+
+func mustParseAbi() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(fmt.Sprintf(
+		serviceManagerAbi,
+		auctionTaskComponents,
+		auctionTaskResponseComponents,
+		sigchecker.NonSignerStakesAndSignatureComponents,
+		auctionTaskResponseMetadataComponents,
+		g1PointComponents,
+	)))
+	if err != nil {
+		panic(fmt.Sprintf("invalid service manager abi: %v", err))
+	}
+	return parsed
+}
+
+// AuctionTask mirrors EigenLVRAVSServiceManager.AuctionTask
+type AuctionTask struct {
+	PoolId                    [32]byte
+	BlockNumber               *big.Int
+	TaskCreatedBlock          *big.Int
+	QuorumNumbers             []byte
+	QuorumThresholdPercentage uint32
+}
+
+// AuctionTaskResponse mirrors EigenLVRAVSServiceManager.AuctionTaskResponse
+type AuctionTaskResponse struct {
+	ReferenceTaskIndex uint32
+	Winner             common.Address
+	WinningBid         *big.Int
+	TotalBids          *big.Int
+}
+
+// NewAuctionTaskCreated is emitted when the hook creates a task
+type NewAuctionTaskCreated struct {
+	TaskIndex uint32
+	Task      AuctionTask
+	Raw       gethtypes.Log
+}
+
+// ParseNewAuctionTaskCreated decodes a NewAuctionTaskCreated log
+func ParseNewAuctionTaskCreated(log gethtypes.Log) (*NewAuctionTaskCreated, error) {
+	if len(log.Topics) != 2 || log.Topics[0] != NewAuctionTaskCreatedTopic {
+		return nil, ErrUnexpectedEvent
+	}
+
+	values, err := ABI.Unpack("NewAuctionTaskCreated", log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode NewAuctionTaskCreated: %w", err)
+	}
+
+	event := &NewAuctionTaskCreated{
+		TaskIndex: uint32(new(big.Int).SetBytes(log.Topics[1].Bytes()).Uint64()),
+		Raw:       log,
+	}
+	event.Task = *abi.ConvertType(values[0], new(AuctionTask)).(*AuctionTask)
+
+	return event, nil
+}
+
+// TaskResponseMetadata mirrors EigenLVRAVSServiceManager.TaskResponseMetadata
+type TaskResponseMetadata struct {
+	TaskResponsedBlock uint32
+	HashOfNonSigners   [32]byte
+}
+
+// AuctionTaskResponded is emitted when an aggregator's response is accepted
+type AuctionTaskResponded struct {
+	TaskResponse         AuctionTaskResponse
+	TaskResponseMetadata TaskResponseMetadata
+	Raw                  gethtypes.Log
+}
+
+// ParseAuctionTaskResponded decodes an AuctionTaskResponded log
+func ParseAuctionTaskResponded(log gethtypes.Log) (*AuctionTaskResponded, error) {
+	if len(log.Topics) != 1 || log.Topics[0] != AuctionTaskRespondedTopic {
+		return nil, ErrUnexpectedEvent
+	}
+
+	values, err := ABI.Unpack("AuctionTaskResponded", log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode AuctionTaskResponded: %w", err)
+	}
+
+	event := &AuctionTaskResponded{Raw: log}
+	event.TaskResponse = *abi.ConvertType(values[0], new(AuctionTaskResponse)).(*AuctionTaskResponse)
+	event.TaskResponseMetadata = *abi.ConvertType(values[1], new(TaskResponseMetadata)).(*TaskResponseMetadata)
+
+	return event, nil
+}
+
+// TaskChallenged is emitted when a challenger stakes against a completed
+// task's response
+type TaskChallenged struct {
+	TaskIndex  uint32
+	Challenger common.Address
+	Raw        gethtypes.Log
+}
+
+// ParseTaskChallenged decodes a TaskChallenged log
+func ParseTaskChallenged(log gethtypes.Log) (*TaskChallenged, error) {
+	if len(log.Topics) != 2 || log.Topics[0] != TaskChallengedTopic {
+		return nil, ErrUnexpectedEvent
+	}
+
+	// Only the task index is indexed, the challenger is in the data
+	values, err := ABI.Events["TaskChallenged"].Inputs.NonIndexed().Unpack(log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack TaskChallenged: %w", err)
+	}
+
+	return &TaskChallenged{
+		TaskIndex:  uint32(new(big.Int).SetBytes(log.Topics[1].Bytes()).Uint64()),
+		Challenger: *abi.ConvertType(values[0], new(common.Address)).(*common.Address),
+		Raw:        log,
+	}, nil
+}
+
+// HashAuctionTask returns keccak256(abi.encode(task)), the hash the service
+// manager stores for a task when it is created
+func HashAuctionTask(task AuctionTask) (common.Hash, error) {
+	encoded, err := ABI.Methods["respondToAuctionTask"].Inputs[:1].Pack(task)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode task: %w", err)
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// Reader reads task state from the service manager
+type Reader struct {
+	address  common.Address
+	contract *bind.BoundContract
+}
+
+func NewReader(address common.Address, backend bind.ContractCaller) *Reader {
+	return &Reader{
+		address:  address,
+		contract: bind.NewBoundContract(address, ABI, backend, nil, nil),
+	}
+}
+
+// Address returns the service manager address
+func (r *Reader) Address() common.Address {
+	return r.address
+}
+
+// LatestTaskNum returns the index the next task will be created with
+func (r *Reader) LatestTaskNum(ctx context.Context) (uint32, error) {
+	var out []interface{}
+	if err := r.contract.Call(&bind.CallOpts{Context: ctx}, &out, "latestTaskNum"); err != nil {
+		return 0, fmt.Errorf("failed to call latestTaskNum: %w", err)
+	}
+	return *abi.ConvertType(out[0], new(uint32)).(*uint32), nil
+}
+
+// TaskHash returns keccak256(abi.encode(task)) stored for the task index, or
+// the zero hash if no such task exists
+func (r *Reader) TaskHash(ctx context.Context, taskIndex uint32) (common.Hash, error) {
+	return r.hashAt(ctx, "allTaskHashes", taskIndex)
+}
+
+// TaskResponseHash returns the hash of the responded task response, or the
+// zero hash if the task has no response yet
+func (r *Reader) TaskResponseHash(ctx context.Context, taskIndex uint32) (common.Hash, error) {
+	return r.hashAt(ctx, "allTaskResponses", taskIndex)
+}
+
+func (r *Reader) hashAt(ctx context.Context, method string, taskIndex uint32) (common.Hash, error) {
+	var out []interface{}
+	if err := r.contract.Call(&bind.CallOpts{Context: ctx}, &out, method, taskIndex); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	return common.Hash(*abi.ConvertType(out[0], new([32]byte)).(*[32]byte)), nil
+}
+
+// ChallengeWindowBlocks returns how many blocks after its response was
+// recorded a task can be challenged on chain
+func (r *Reader) ChallengeWindowBlocks(ctx context.Context) (uint32, error) {
+	var out []interface{}
+	if err := r.contract.Call(&bind.CallOpts{Context: ctx}, &out, "TASK_CHALLENGE_WINDOW_BLOCK"); err != nil {
+		return 0, fmt.Errorf("failed to call TASK_CHALLENGE_WINDOW_BLOCK: %w", err)
+	}
+	return *abi.ConvertType(out[0], new(uint32)).(*uint32), nil
+}
+
+// SimulateRespondToAuctionTask runs respondToAuctionTask as an eth_call from
+// the given address against the latest block. A nil error means the call would
+// succeed; a revert is returned with its reason, see RevertReason.
+func (r *Reader) SimulateRespondToAuctionTask(
+	ctx context.Context,
+	from common.Address,
+	task AuctionTask,
+	response AuctionTaskResponse,
+	nonSignerStakesAndSignature sigchecker.NonSignerStakesAndSignature,
+) error {
+	var out []interface{}
+	err := r.contract.Call(&bind.CallOpts{Context: ctx, From: from}, &out, "respondToAuctionTask", task, response, nonSignerStakesAndSignature)
+	if err != nil {
+		return fmt.Errorf("respondToAuctionTask reverted: %w", err)
+	}
+	return nil
+}
+
+// PackRespondToAuctionTask returns the calldata of a respondToAuctionTask call
+func PackRespondToAuctionTask(
+	task AuctionTask,
+	response AuctionTaskResponse,
+	nonSignerStakesAndSignature sigchecker.NonSignerStakesAndSignature,
+) ([]byte, error) {
+	data, err := ABI.Pack("respondToAuctionTask", task, response, nonSignerStakesAndSignature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack respondToAuctionTask: %w", err)
+	}
+	return data, nil
+}
+
+// ExpireAuctionTask sends expireAuctionTask, recording on chain that the
+// task's response window closed without an aggregated response
+func ExpireAuctionTask(opts *bind.TransactOpts, backend bind.ContractBackend, address common.Address, task AuctionTask, taskIndex uint32) (*gethtypes.Transaction, error) {
+	contract := bind.NewBoundContract(address, ABI, backend, backend, backend)
+	tx, err := contract.Transact(opts, "expireAuctionTask", task, taskIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send expireAuctionTask: %w", err)
+	}
+	return tx, nil
+}
+
+// RespondToAuctionTask sends the respondToAuctionTask calldata PackRespondToAuctionTask built
+func RespondToAuctionTask(opts *bind.TransactOpts, backend bind.ContractBackend, address common.Address, calldata []byte) (*gethtypes.Transaction, error) {
+	contract := bind.NewBoundContract(address, ABI, backend, backend, backend)
+	tx, err := contract.RawTransact(opts, calldata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send respondToAuctionTask: %w", err)
+	}
+	return tx, nil
+}
+
+// UnpackRespondToAuctionTask decodes the arguments of a respondToAuctionTask
+// call from its calldata
+func UnpackRespondToAuctionTask(data []byte) (AuctionTask, AuctionTaskResponse, sigchecker.NonSignerStakesAndSignature, error) {
+	method := ABI.Methods["respondToAuctionTask"]
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return AuctionTask{}, AuctionTaskResponse{}, sigchecker.NonSignerStakesAndSignature{}, ErrNotResponse
+	}
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return AuctionTask{}, AuctionTaskResponse{}, sigchecker.NonSignerStakesAndSignature{}, fmt.Errorf("failed to unpack respondToAuctionTask: %w", err)
+	}
+	task := *abi.ConvertType(values[0], new(AuctionTask)).(*AuctionTask)
+	response := *abi.ConvertType(values[1], new(AuctionTaskResponse)).(*AuctionTaskResponse)
+	nonSignerStakesAndSignature := *abi.ConvertType(values[2], new(sigchecker.NonSignerStakesAndSignature)).(*sigchecker.NonSignerStakesAndSignature)
+	return task, response, nonSignerStakesAndSignature, nil
+}
+
+// RaiseAndResolveChallenge sends raiseAndResolveChallenge against the task's
+// recorded response, with the public keys of the operators that didn't sign it
+func RaiseAndResolveChallenge(
+	opts *bind.TransactOpts,
+	backend bind.ContractBackend,
+	address common.Address,
+	task AuctionTask,
+	response AuctionTaskResponse,
+	metadata TaskResponseMetadata,
+	pubkeysOfNonSigningOperators []sigchecker.G1Point,
+) (*gethtypes.Transaction, error) {
+	contract := bind.NewBoundContract(address, ABI, backend, backend, backend)
+	tx, err := contract.Transact(opts, "raiseAndResolveChallenge", task, response, metadata, pubkeysOfNonSigningOperators)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send raiseAndResolveChallenge: %w", err)
+	}
+	return tx, nil
+}
+
+// RevertReason extracts the Error(string) reason from a reverted call's error
+func RevertReason(err error) (string, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return "", false
+	}
+	data, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return "", false
+	}
+	reason, unpackErr := abi.UnpackRevert(common.FromHex(data))
+	if unpackErr != nil {
+		return "", false
+	}
+	return reason, true
+}
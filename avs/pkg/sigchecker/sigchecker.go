@@ -0,0 +1,239 @@
+// Package sigchecker builds the NonSignerStakesAndSignature argument that
+// BLSSignatureChecker.checkSignatures, and so the service manager's
+// respondToAuctionTask, expects alongside an aggregate signature.
+package sigchecker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// NonSignerStakesAndSignatureComponents is the ABI of
+// IBLSSignatureChecker.NonSignerStakesAndSignature, for contracts that take it
+// as a tuple argument
+const NonSignerStakesAndSignatureComponents = `[
+	{"name":"nonSignerQuorumBitmapIndices","type":"uint32[]"},
+	{"name":"nonSignerPubkeys","type":"tuple[]","components":[{"name":"X","type":"uint256"},{"name":"Y","type":"uint256"}]},
+	{"name":"quorumApks","type":"tuple[]","components":[{"name":"X","type":"uint256"},{"name":"Y","type":"uint256"}]},
+	{"name":"apkG2","type":"tuple","components":[{"name":"X","type":"uint256[2]"},{"name":"Y","type":"uint256[2]"}]},
+	{"name":"sigma","type":"tuple","components":[{"name":"X","type":"uint256"},{"name":"Y","type":"uint256"}]},
+	{"name":"quorumApkIndices","type":"uint32[]"},
+	{"name":"totalStakeIndices","type":"uint32[]"},
+	{"name":"nonSignerStakeIndices","type":"uint32[][]"}
+]`
+
+// operatorStateRetrieverAbi is the subset of the OperatorStateRetriever used to
+// look up the history indices checkSignatures verifies against
+const operatorStateRetrieverAbi = `[
+	{"type":"function","name":"getCheckSignaturesIndices","stateMutability":"view","inputs":[{"name":"registryCoordinator","type":"address"},{"name":"referenceBlockNumber","type":"uint32"},{"name":"quorumNumbers","type":"bytes"},{"name":"nonSignerOperatorIds","type":"bytes32[]"}],"outputs":[{"name":"","type":"tuple","components":[{"name":"nonSignerQuorumBitmapIndices","type":"uint32[]"},{"name":"quorumApkIndices","type":"uint32[]"},{"name":"totalStakeIndices","type":"uint32[]"},{"name":"nonSignerStakeIndices","type":"uint32[][]"}]}]}
+]`
+
+// G1Point matches BN254.G1Point
+type G1Point struct {
+	X *big.Int
+	Y *big.Int
+}
+
+// G2Point matches BN254.G2Point. Each coordinate is ordered [imaginary, real]
+// as the precompile expects.
+type G2Point struct {
+	X [2]*big.Int
+	Y [2]*big.Int
+}
+
+// NonSignerStakesAndSignature matches IBLSSignatureChecker.NonSignerStakesAndSignature
+type NonSignerStakesAndSignature struct {
+	NonSignerQuorumBitmapIndices []uint32
+	NonSignerPubkeys             []G1Point
+	QuorumApks                   []G1Point
+	ApkG2                        G2Point
+	Sigma                        G1Point
+	QuorumApkIndices             []uint32
+	TotalStakeIndices            []uint32
+	NonSignerStakeIndices        [][]uint32
+}
+
+// CheckSignaturesIndices matches OperatorStateRetriever.CheckSignaturesIndices
+type CheckSignaturesIndices struct {
+	NonSignerQuorumBitmapIndices []uint32
+	QuorumApkIndices             []uint32
+	TotalStakeIndices            []uint32
+	NonSignerStakeIndices        [][]uint32
+}
+
+// Signature is an aggregate signature with the pubkeys behind it
+type Signature struct {
+	QuorumNumbers types.QuorumNums
+	// QuorumApks are the APKs of QuorumNumbers, in the same order
+	QuorumApks   []*bls.G1Point
+	NonSigners   map[types.OperatorId]*bls.G1Point
+	SignersApkG2 *bls.G2Point
+	Sigma        *bls.Signature
+}
+
+// Builder assembles NonSignerStakesAndSignature with indices read from the
+// OperatorStateRetriever
+type Builder struct {
+	operatorStateRetriever  *bind.BoundContract
+	registryCoordinatorAddr common.Address
+}
+
+func NewBuilder(
+	operatorStateRetrieverAddr common.Address,
+	registryCoordinatorAddr common.Address,
+	backend bind.ContractBackend,
+) (*Builder, error) {
+	operatorStateRetriever, err := bindContract(operatorStateRetrieverAddr, operatorStateRetrieverAbi, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind operator state retriever: %w", err)
+	}
+	return &Builder{
+		operatorStateRetriever:  operatorStateRetriever,
+		registryCoordinatorAddr: registryCoordinatorAddr,
+	}, nil
+}
+
+// CheckSignaturesIndices reads the indices for the non-signers, which must
+// already be sorted, at the reference block
+func (b *Builder) CheckSignaturesIndices(
+	ctx context.Context,
+	referenceBlock uint32,
+	quorumNumbers types.QuorumNums,
+	nonSignerIds []types.OperatorId,
+) (CheckSignaturesIndices, error) {
+	ids := make([][32]byte, len(nonSignerIds))
+	for i, operatorId := range nonSignerIds {
+		ids[i] = operatorId
+	}
+
+	var out []interface{}
+	err := b.operatorStateRetriever.Call(
+		&bind.CallOpts{Context: ctx},
+		&out,
+		"getCheckSignaturesIndices",
+		b.registryCoordinatorAddr,
+		referenceBlock,
+		quorumBytes(quorumNumbers),
+		ids,
+	)
+	if err != nil {
+		return CheckSignaturesIndices{}, fmt.Errorf("failed to get check signatures indices: %w", err)
+	}
+	return *abi.ConvertType(out[0], new(CheckSignaturesIndices)).(*CheckSignaturesIndices), nil
+}
+
+// Build returns the checkSignatures argument for the signature at the
+// reference block, which must be the block the task's stakes were taken at
+func (b *Builder) Build(ctx context.Context, referenceBlock uint32, signature Signature) (NonSignerStakesAndSignature, error) {
+	if len(signature.QuorumApks) != len(signature.QuorumNumbers) {
+		return NonSignerStakesAndSignature{}, fmt.Errorf("got %d quorum apks for %d quorums", len(signature.QuorumApks), len(signature.QuorumNumbers))
+	}
+
+	indices, err := b.CheckSignaturesIndices(ctx, referenceBlock, signature.QuorumNumbers, SortedOperatorIds(signature.NonSigners))
+	if err != nil {
+		return NonSignerStakesAndSignature{}, err
+	}
+	return Assemble(signature, indices), nil
+}
+
+// Assemble combines a signature with indices already read for its non-signers
+func Assemble(signature Signature, indices CheckSignaturesIndices) NonSignerStakesAndSignature {
+	nonSignerIds := SortedOperatorIds(signature.NonSigners)
+	params := NonSignerStakesAndSignature{
+		NonSignerQuorumBitmapIndices: indices.NonSignerQuorumBitmapIndices,
+		NonSignerPubkeys:             make([]G1Point, len(nonSignerIds)),
+		QuorumApks:                   make([]G1Point, len(signature.QuorumApks)),
+		ApkG2:                        ToG2Point(signature.SignersApkG2),
+		Sigma:                        ToG1Point(signature.Sigma.G1Point),
+		QuorumApkIndices:             indices.QuorumApkIndices,
+		TotalStakeIndices:            indices.TotalStakeIndices,
+		NonSignerStakeIndices:        indices.NonSignerStakeIndices,
+	}
+	for i, operatorId := range nonSignerIds {
+		params.NonSignerPubkeys[i] = ToG1Point(signature.NonSigners[operatorId])
+	}
+	for i, apk := range signature.QuorumApks {
+		params.QuorumApks[i] = ToG1Point(apk)
+	}
+	return params
+}
+
+// SortedOperatorIds returns the operator ids in ascending order. checkSignatures
+// requires non-signers sorted by pubkey hash, which is the operator id.
+func SortedOperatorIds(pubkeys map[types.OperatorId]*bls.G1Point) []types.OperatorId {
+	ids := make([]types.OperatorId, 0, len(pubkeys))
+	for operatorId := range pubkeys {
+		ids = append(ids, operatorId)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return bytes.Compare(ids[i][:], ids[j][:]) < 0
+	})
+	return ids
+}
+
+func quorumBytes(quorumNumbers types.QuorumNums) []byte {
+	out := make([]byte, len(quorumNumbers))
+	for i, quorum := range quorumNumbers {
+		out[i] = byte(quorum)
+	}
+	return out
+}
+
+// frModulus is the order of the BN254 scalar field, BN254.FR_MODULUS
+var frModulus, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+// Gamma returns the coefficient BLSSignatureChecker.trySignatureAndApkVerification
+// derives from the message and points to check the signature and the
+// G1/G2 APK equivalence in a single pairing
+func Gamma(msgHash [32]byte, apk *bls.G1Point, apkG2 *bls.G2Point, sigma *bls.Signature) *big.Int {
+	apkPoint := ToG1Point(apk)
+	apkG2Point := ToG2Point(apkG2)
+	sigmaPoint := ToG1Point(sigma.G1Point)
+
+	packed := append([]byte{}, msgHash[:]...)
+	for _, word := range []*big.Int{
+		apkPoint.X, apkPoint.Y,
+		apkG2Point.X[0], apkG2Point.X[1], apkG2Point.Y[0], apkG2Point.Y[1],
+		sigmaPoint.X, sigmaPoint.Y,
+	} {
+		packed = append(packed, common.LeftPadBytes(word.Bytes(), 32)...)
+	}
+
+	gamma := new(big.Int).SetBytes(crypto.Keccak256(packed))
+	return gamma.Mod(gamma, frModulus)
+}
+
+// ToG1Point converts the point to its contract representation
+func ToG1Point(point *bls.G1Point) G1Point {
+	return G1Point{
+		X: point.X.BigInt(new(big.Int)),
+		Y: point.Y.BigInt(new(big.Int)),
+	}
+}
+
+// ToG2Point converts the point to its contract representation
+func ToG2Point(point *bls.G2Point) G2Point {
+	return G2Point{
+		X: [2]*big.Int{point.X.A1.BigInt(new(big.Int)), point.X.A0.BigInt(new(big.Int))},
+		Y: [2]*big.Int{point.Y.A1.BigInt(new(big.Int)), point.Y.A0.BigInt(new(big.Int))},
+	}
+}
+
+func bindContract(address common.Address, contractAbi string, backend bind.ContractBackend) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractAbi))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, backend, backend, backend), nil
+}
@@ -0,0 +1,293 @@
+// Package subgraph queries Uniswap subgraphs on The Graph for historical pool
+// data, such as TVL and daily volume, used to prioritize tasks and for
+// analytics. Responses are cached per pool and requests are spaced out so a
+// burst of tasks can't exhaust the gateway's query budget.
+package subgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultCacheTtl is how long pool data is served from cache when Config.CacheTtl is unset
+	DefaultCacheTtl = 5 * time.Minute
+
+	// DefaultMinRequestInterval spaces requests when Config.MinRequestInterval is unset
+	DefaultMinRequestInterval = 200 * time.Millisecond
+
+	// DefaultHistoryDays is the number of daily snapshots fetched when Config.HistoryDays is unset
+	DefaultHistoryDays = 7
+
+	// requestTimeout bounds a single query
+	requestTimeout = 10 * time.Second
+)
+
+// ErrPoolNotFound is returned when the subgraph doesn't index the pool
+var ErrPoolNotFound = errors.New("pool not found in subgraph")
+
+// poolQuery fetches a pool and its most recent daily snapshots
+const poolQuery = `query Pool($id: ID!, $pool: String!, $days: Int!) {
+  pool(id: $id) {
+    id
+    totalValueLockedUSD
+    volumeUSD
+    feesUSD
+    txCount
+  }
+  poolDayDatas(first: $days, orderBy: date, orderDirection: desc, where: {pool: $pool}) {
+    date
+    tvlUSD
+    volumeUSD
+    feesUSD
+  }
+}`
+
+// Config locates the subgraph
+type Config struct {
+	// Url is the subgraph's query endpoint
+	Url string
+	// ApiKey is sent as a bearer token when set, for gateways that take it
+	// in a header rather than in the URL
+	ApiKey             string
+	CacheTtl           time.Duration
+	MinRequestInterval time.Duration
+	HistoryDays        int
+}
+
+// PoolDay is one daily snapshot of a pool
+type PoolDay struct {
+	Date      time.Time `json:"date"`
+	TvlUsd    float64   `json:"tvlUsd"`
+	VolumeUsd float64   `json:"volumeUsd"`
+	FeesUsd   float64   `json:"feesUsd"`
+}
+
+// PoolStats is a pool's lifetime totals and recent history, newest day first
+type PoolStats struct {
+	PoolId    common.Hash `json:"poolId"`
+	TvlUsd    float64     `json:"tvlUsd"`
+	VolumeUsd float64     `json:"volumeUsd"`
+	FeesUsd   float64     `json:"feesUsd"`
+	TxCount   uint64      `json:"txCount"`
+	Days      []PoolDay   `json:"days"`
+	FetchedAt time.Time   `json:"fetchedAt"`
+}
+
+// AverageDailyVolumeUsd returns the mean volume over the fetched days
+func (s PoolStats) AverageDailyVolumeUsd() float64 {
+	if len(s.Days) == 0 {
+		return 0
+	}
+	var total float64
+	for _, day := range s.Days {
+		total += day.VolumeUsd
+	}
+	return total / float64(len(s.Days))
+}
+
+// Client queries a Uniswap subgraph
+type Client struct {
+	config Config
+	http   *http.Client
+
+	// requestMu serializes requests so they can be spaced out
+	requestMu   sync.Mutex
+	lastRequest time.Time
+
+	cacheMu sync.Mutex
+	cache   map[common.Hash]PoolStats
+}
+
+func NewClient(config Config) (*Client, error) {
+	if config.Url == "" {
+		return nil, errors.New("subgraph url is required")
+	}
+	if config.CacheTtl <= 0 {
+		config.CacheTtl = DefaultCacheTtl
+	}
+	if config.MinRequestInterval <= 0 {
+		config.MinRequestInterval = DefaultMinRequestInterval
+	}
+	if config.HistoryDays <= 0 {
+		config.HistoryDays = DefaultHistoryDays
+	}
+
+	return &Client{
+		config: config,
+		http:   &http.Client{Timeout: requestTimeout},
+		cache:  make(map[common.Hash]PoolStats),
+	}, nil
+}
+
+// PoolStats returns the pool's stats, from cache while they are fresh
+func (c *Client) PoolStats(ctx context.Context, poolId common.Hash) (PoolStats, error) {
+	c.cacheMu.Lock()
+	stats, ok := c.cache[poolId]
+	c.cacheMu.Unlock()
+	if ok && time.Since(stats.FetchedAt) < c.config.CacheTtl {
+		return stats, nil
+	}
+
+	stats, err := c.fetchPoolStats(ctx, poolId)
+	if err != nil {
+		return PoolStats{}, err
+	}
+
+	c.cacheMu.Lock()
+	c.cache[poolId] = stats
+	c.cacheMu.Unlock()
+	return stats, nil
+}
+
+// PoolValue returns the pool's TVL in whole US dollars, so the client can rank
+// tasks as the operator's PoolValuer
+func (c *Client) PoolValue(ctx context.Context, poolId common.Hash) (*big.Int, error) {
+	stats, err := c.PoolStats(ctx, poolId)
+	if err != nil {
+		return nil, err
+	}
+	value, _ := big.NewFloat(stats.TvlUsd).Int(nil)
+	return value, nil
+}
+
+func (c *Client) fetchPoolStats(ctx context.Context, poolId common.Hash) (PoolStats, error) {
+	id := strings.ToLower(poolId.Hex())
+
+	var data struct {
+		Pool *struct {
+			TotalValueLockedUsd string `json:"totalValueLockedUSD"`
+			VolumeUsd           string `json:"volumeUSD"`
+			FeesUsd             string `json:"feesUSD"`
+			TxCount             string `json:"txCount"`
+		} `json:"pool"`
+		PoolDayDatas []struct {
+			Date      int64  `json:"date"`
+			TvlUsd    string `json:"tvlUSD"`
+			VolumeUsd string `json:"volumeUSD"`
+			FeesUsd   string `json:"feesUSD"`
+		} `json:"poolDayDatas"`
+	}
+	err := c.query(ctx, poolQuery, map[string]interface{}{
+		"id":   id,
+		"pool": id,
+		"days": c.config.HistoryDays,
+	}, &data)
+	if err != nil {
+		return PoolStats{}, err
+	}
+	if data.Pool == nil {
+		return PoolStats{}, fmt.Errorf("%w: %s", ErrPoolNotFound, poolId.Hex())
+	}
+
+	stats := PoolStats{
+		PoolId:    poolId,
+		TvlUsd:    parseDecimal(data.Pool.TotalValueLockedUsd),
+		VolumeUsd: parseDecimal(data.Pool.VolumeUsd),
+		FeesUsd:   parseDecimal(data.Pool.FeesUsd),
+		Days:      make([]PoolDay, 0, len(data.PoolDayDatas)),
+		FetchedAt: time.Now(),
+	}
+	stats.TxCount, _ = strconv.ParseUint(data.Pool.TxCount, 10, 64)
+	for _, day := range data.PoolDayDatas {
+		stats.Days = append(stats.Days, PoolDay{
+			Date:      time.Unix(day.Date, 0).UTC(),
+			TvlUsd:    parseDecimal(day.TvlUsd),
+			VolumeUsd: parseDecimal(day.VolumeUsd),
+			FeesUsd:   parseDecimal(day.FeesUsd),
+		})
+	}
+	return stats, nil
+}
+
+// query runs a GraphQL query once the minimum request interval has passed
+// and decodes its data into out
+func (c *Client) query(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	if err := c.waitTurn(ctx); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.ApiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.ApiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query subgraph: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("subgraph returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode subgraph response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("subgraph query failed: %s", result.Errors[0].Message)
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to decode subgraph data: %w", err)
+	}
+	return nil
+}
+
+// waitTurn blocks until MinRequestInterval has passed since the last request
+func (c *Client) waitTurn(ctx context.Context) error {
+	c.requestMu.Lock()
+	defer c.requestMu.Unlock()
+
+	if wait := c.config.MinRequestInterval - time.Since(c.lastRequest); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	c.lastRequest = time.Now()
+	return nil
+}
+
+// parseDecimal parses the BigDecimal strings subgraphs return, as zero if malformed
+func parseDecimal(s string) float64 {
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
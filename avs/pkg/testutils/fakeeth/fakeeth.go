@@ -0,0 +1,953 @@
+// Package fakeeth is an in-memory eth.Client for unit tests. The chain is
+// whatever the test makes of it: blocks are mined on demand, carrying the
+// logs and transactions given to them, contract calls are answered by
+// programmed results, and any method can be made to fail. Nothing touches the
+// network, so operator, aggregator and avsregistry tests run without a node.
+package fakeeth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/Layr-Labs/eigensdk-go/chainio/clients/eth"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Defaults of a new client
+const (
+	BlockTime      = 12
+	GenesisTime    = 1_700_000_000
+	GasLimit       = 30_000_000
+	DefaultGas     = 100_000
+	DefaultBaseFee = 1_000_000_000
+	DefaultTipCap  = 1_000_000_000
+)
+
+// ErrNotFound is returned for blocks, transactions and receipts the fake
+// chain doesn't have, like ethereum.NotFound from a real node
+var ErrNotFound = ethereum.NotFound
+
+// CallHandler answers eth_call to a contract
+type CallHandler func(msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+
+var _ eth.Client = (*Client)(nil)
+
+// Client is a fake chain. It is safe for concurrent use.
+type Client struct {
+	mu      sync.Mutex
+	chainId *big.Int
+
+	headers      []*types.Header
+	blocks       []*types.Block
+	logs         []types.Log
+	pending      []*types.Transaction
+	transactions map[common.Hash]*types.Transaction
+	receipts     map[common.Hash]*types.Receipt
+	failTxs      map[common.Hash]bool
+
+	balances      map[common.Address]*big.Int
+	nonces        map[common.Address]uint64
+	pendingNonces map[common.Address]uint64
+	codes         map[common.Address][]byte
+	storage       map[common.Address]map[common.Hash]common.Hash
+
+	handlers map[common.Address]CallHandler
+	results  map[callKey][]byte
+
+	gasEstimate uint64
+	gasPrice    *big.Int
+	tipCap      *big.Int
+
+	errs map[string]error
+
+	headSubs []*headSubscription
+	logSubs  []*logSubscription
+}
+
+// callKey programs the result of calling a method of a contract
+type callKey struct {
+	to       common.Address
+	selector [4]byte
+}
+
+// New returns a chain with only its genesis block
+func New(chainId int64) *Client {
+	c := &Client{
+		chainId:       big.NewInt(chainId),
+		transactions:  make(map[common.Hash]*types.Transaction),
+		receipts:      make(map[common.Hash]*types.Receipt),
+		failTxs:       make(map[common.Hash]bool),
+		balances:      make(map[common.Address]*big.Int),
+		nonces:        make(map[common.Address]uint64),
+		pendingNonces: make(map[common.Address]uint64),
+		codes:         make(map[common.Address][]byte),
+		storage:       make(map[common.Address]map[common.Hash]common.Hash),
+		handlers:      make(map[common.Address]CallHandler),
+		results:       make(map[callKey][]byte),
+		gasEstimate:   DefaultGas,
+		gasPrice:      big.NewInt(DefaultBaseFee + DefaultTipCap),
+		tipCap:        big.NewInt(DefaultTipCap),
+		errs:          make(map[string]error),
+	}
+	c.mine(nil)
+	return c
+}
+
+// MineBlock mines a block holding the pending transactions and the logs. The
+// logs' block fields are filled in. Subscribers are sent the new head and the
+// logs they filter for before MineBlock returns, so a subscriber that doesn't
+// read its channel blocks it.
+func (c *Client) MineBlock(logs ...types.Log) *types.Header {
+	c.mu.Lock()
+	header, mined := c.mine(logs)
+	headSubs := append([]*headSubscription(nil), c.headSubs...)
+	logSubs := append([]*logSubscription(nil), c.logSubs...)
+	c.mu.Unlock()
+
+	for _, sub := range headSubs {
+		select {
+		case sub.ch <- types.CopyHeader(header):
+		case <-sub.quit:
+		}
+	}
+	for _, sub := range logSubs {
+		for _, log := range mined {
+			if !matches(log, sub.query) {
+				continue
+			}
+			select {
+			case sub.ch <- log:
+			case <-sub.quit:
+			}
+		}
+	}
+	return types.CopyHeader(header)
+}
+
+// MineBlocks mines n empty blocks and returns the last header
+func (c *Client) MineBlocks(n int) *types.Header {
+	var header *types.Header
+	for i := 0; i < n; i++ {
+		header = c.MineBlock()
+	}
+	return header
+}
+
+// mine appends a block and returns it with its logs. c.mu must be held,
+// except by New.
+func (c *Client) mine(logs []types.Log) (*types.Header, []types.Log) {
+	number := uint64(len(c.headers))
+	header := &types.Header{
+		Number:     new(big.Int).SetUint64(number),
+		Time:       GenesisTime + number*BlockTime,
+		GasLimit:   GasLimit,
+		Difficulty: new(big.Int),
+		BaseFee:    big.NewInt(DefaultBaseFee),
+	}
+	if number > 0 {
+		header.ParentHash = c.headers[number-1].Hash()
+	}
+
+	txs := c.pending
+	c.pending = nil
+	var gasUsed uint64
+	for _, tx := range txs {
+		gasUsed += tx.Gas()
+	}
+	header.GasUsed = gasUsed
+
+	block := types.NewBlockWithHeader(header).WithBody(txs, nil)
+	hash := block.Hash()
+
+	var cumulativeGas uint64
+	for i, tx := range txs {
+		cumulativeGas += tx.Gas()
+		receipt := types.NewReceipt(nil, c.failTxs[tx.Hash()], cumulativeGas)
+		receipt.Type = tx.Type()
+		receipt.TxHash = tx.Hash()
+		receipt.GasUsed = tx.Gas()
+		receipt.EffectiveGasPrice = tx.GasPrice()
+		receipt.BlockHash = hash
+		receipt.BlockNumber = header.Number
+		receipt.TransactionIndex = uint(i)
+		if tx.To() == nil {
+			if sender, err := c.sender(tx); err == nil {
+				receipt.ContractAddress = crypto.CreateAddress(sender, tx.Nonce())
+			}
+		}
+		c.receipts[tx.Hash()] = receipt
+
+		if sender, err := c.sender(tx); err == nil && tx.Nonce() >= c.nonces[sender] {
+			c.nonces[sender] = tx.Nonce() + 1
+		}
+	}
+
+	mined := make([]types.Log, len(logs))
+	for i, log := range logs {
+		log.BlockNumber = number
+		log.BlockHash = hash
+		log.Index = uint(len(c.logs) + i)
+		mined[i] = log
+	}
+	c.logs = append(c.logs, mined...)
+
+	c.headers = append(c.headers, header)
+	c.blocks = append(c.blocks, block)
+	return header, mined
+}
+
+// Head returns the latest block's header
+func (c *Client) Head() *types.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return types.CopyHeader(c.headers[len(c.headers)-1])
+}
+
+// SetCallHandler answers every call to the contract with the handler
+func (c *Client) SetCallHandler(to common.Address, handler CallHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[to] = handler
+}
+
+// SetCallResult answers calls of the method with the 4-byte selector on the
+// contract with the ABI-encoded result, whatever the arguments. It takes
+// precedence over the contract's call handler.
+func (c *Client) SetCallResult(to common.Address, selector []byte, result []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var key callKey
+	key.to = to
+	copy(key.selector[:], selector)
+	c.results[key] = result
+}
+
+// SetBalance sets the account's balance
+func (c *Client) SetBalance(account common.Address, balance *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.balances[account] = new(big.Int).Set(balance)
+}
+
+// SetNonce sets the account's nonce
+func (c *Client) SetNonce(account common.Address, nonce uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nonces[account] = nonce
+}
+
+// SetCode deploys code at the address, so it reads as a contract
+func (c *Client) SetCode(account common.Address, code []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.codes[account] = code
+}
+
+// SetStorageAt sets a storage slot of the account
+func (c *Client) SetStorageAt(account common.Address, key common.Hash, value common.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.storage[account] == nil {
+		c.storage[account] = make(map[common.Hash]common.Hash)
+	}
+	c.storage[account][key] = value
+}
+
+// SetGas sets what EstimateGas, SuggestGasPrice and SuggestGasTipCap return
+func (c *Client) SetGas(estimate uint64, gasPrice *big.Int, tipCap *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gasEstimate = estimate
+	c.gasPrice = new(big.Int).Set(gasPrice)
+	c.tipCap = new(big.Int).Set(tipCap)
+}
+
+// FailTransaction makes the transaction revert when it is mined
+func (c *Client) FailTransaction(hash common.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failTxs[hash] = true
+}
+
+// SetError makes the named method, e.g. "BlockNumber", return err until it is
+// cleared with a nil err
+func (c *Client) SetError(method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		delete(c.errs, method)
+		return
+	}
+	c.errs[method] = err
+}
+
+// Sent returns every transaction sent, mined or not, in no particular order
+func (c *Client) Sent() []*types.Transaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	txs := make([]*types.Transaction, 0, len(c.transactions))
+	for _, tx := range c.transactions {
+		txs = append(txs, tx)
+	}
+	return txs
+}
+
+// Pending returns the transactions waiting for the next block
+func (c *Client) Pending() []*types.Transaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*types.Transaction(nil), c.pending...)
+}
+
+// DropSubscriptions ends every subscription with err, like a websocket
+// connection to a node going down
+func (c *Client) DropSubscriptions(err error) {
+	c.mu.Lock()
+	headSubs := append([]*headSubscription(nil), c.headSubs...)
+	logSubs := append([]*logSubscription(nil), c.logSubs...)
+	c.mu.Unlock()
+
+	for _, sub := range headSubs {
+		sub.end(err)
+	}
+	for _, sub := range logSubs {
+		sub.end(err)
+	}
+}
+
+// header resolves a block number the way a node does: nil or negative
+// (latest, pending, safe, finalized) is the head. c.mu must be held.
+func (c *Client) header(number *big.Int) (*types.Header, error) {
+	if number == nil || number.Sign() < 0 {
+		return c.headers[len(c.headers)-1], nil
+	}
+	if !number.IsUint64() || number.Uint64() >= uint64(len(c.headers)) {
+		return nil, ErrNotFound
+	}
+	return c.headers[number.Uint64()], nil
+}
+
+// blockIndex returns the number of the block with the hash. c.mu must be held.
+func (c *Client) blockIndex(hash common.Hash) (int, error) {
+	for i, block := range c.blocks {
+		if block.Hash() == hash {
+			return i, nil
+		}
+	}
+	return 0, ErrNotFound
+}
+
+// failure returns the error programmed for the method. c.mu must be held.
+func (c *Client) failure(method string) error {
+	return c.errs[method]
+}
+
+// sender recovers the transaction's sender on this chain
+func (c *Client) sender(tx *types.Transaction) (common.Address, error) {
+	return types.Sender(types.LatestSignerForChainID(c.chainId), tx)
+}
+
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("ChainID"); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(c.chainId), nil
+}
+
+func (c *Client) NetworkID(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("NetworkID"); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(c.chainId), nil
+}
+
+func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("BalanceAt"); err != nil {
+		return nil, err
+	}
+	return c.balance(account), nil
+}
+
+func (c *Client) PendingBalanceAt(ctx context.Context, account common.Address) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("PendingBalanceAt"); err != nil {
+		return nil, err
+	}
+	return c.balance(account), nil
+}
+
+func (c *Client) balance(account common.Address) *big.Int {
+	if balance, ok := c.balances[account]; ok {
+		return new(big.Int).Set(balance)
+	}
+	return new(big.Int)
+}
+
+func (c *Client) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("BlockByHash"); err != nil {
+		return nil, err
+	}
+	i, err := c.blockIndex(hash)
+	if err != nil {
+		return nil, err
+	}
+	return c.blocks[i], nil
+}
+
+func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("BlockByNumber"); err != nil {
+		return nil, err
+	}
+	header, err := c.header(number)
+	if err != nil {
+		return nil, err
+	}
+	return c.blocks[header.Number.Uint64()], nil
+}
+
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("BlockNumber"); err != nil {
+		return 0, err
+	}
+	return uint64(len(c.headers) - 1), nil
+}
+
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("HeaderByHash"); err != nil {
+		return nil, err
+	}
+	i, err := c.blockIndex(hash)
+	if err != nil {
+		return nil, err
+	}
+	return types.CopyHeader(c.headers[i]), nil
+}
+
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("HeaderByNumber"); err != nil {
+		return nil, err
+	}
+	header, err := c.header(number)
+	if err != nil {
+		return nil, err
+	}
+	return types.CopyHeader(header), nil
+}
+
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.mu.Lock()
+	if err := c.failure("CallContract"); err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	c.mu.Unlock()
+	return c.call(msg, blockNumber)
+}
+
+func (c *Client) CallContractAtHash(ctx context.Context, msg ethereum.CallMsg, blockHash common.Hash) ([]byte, error) {
+	c.mu.Lock()
+	if err := c.failure("CallContractAtHash"); err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	i, err := c.blockIndex(blockHash)
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return c.call(msg, big.NewInt(int64(i)))
+}
+
+func (c *Client) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	c.mu.Lock()
+	if err := c.failure("PendingCallContract"); err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	c.mu.Unlock()
+	return c.call(msg, nil)
+}
+
+// call answers with a programmed result or the contract's handler, which is
+// run without c.mu held so it may use the client itself
+func (c *Client) call(msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if msg.To == nil {
+		return nil, errors.New("fakeeth: call without a contract address")
+	}
+
+	c.mu.Lock()
+	var key callKey
+	key.to = *msg.To
+	copy(key.selector[:], msg.Data)
+	result, hasResult := c.results[key]
+	handler := c.handlers[*msg.To]
+	c.mu.Unlock()
+
+	if hasResult && len(msg.Data) >= 4 {
+		return append([]byte(nil), result...), nil
+	}
+	if handler != nil {
+		return handler(msg, blockNumber)
+	}
+	return nil, fmt.Errorf("fakeeth: no call result programmed for %s", msg.To.Hex())
+}
+
+func (c *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("CodeAt"); err != nil {
+		return nil, err
+	}
+	return c.code(account), nil
+}
+
+func (c *Client) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("PendingCodeAt"); err != nil {
+		return nil, err
+	}
+	return c.code(account), nil
+}
+
+// code returns the account's code. Contracts with a call handler or
+// programmed results have placeholder code, so bindings that check for code
+// before calling accept them.
+func (c *Client) code(account common.Address) []byte {
+	if code, ok := c.codes[account]; ok {
+		return append([]byte(nil), code...)
+	}
+	if _, ok := c.handlers[account]; ok {
+		return []byte{0x00}
+	}
+	for key := range c.results {
+		if key.to == account {
+			return []byte{0x00}
+		}
+	}
+	return nil
+}
+
+func (c *Client) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("StorageAt"); err != nil {
+		return nil, err
+	}
+	value := c.storage[account][key]
+	return value.Bytes(), nil
+}
+
+func (c *Client) PendingStorageAt(ctx context.Context, account common.Address, key common.Hash) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("PendingStorageAt"); err != nil {
+		return nil, err
+	}
+	value := c.storage[account][key]
+	return value.Bytes(), nil
+}
+
+func (c *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("EstimateGas"); err != nil {
+		return 0, err
+	}
+	return c.gasEstimate, nil
+}
+
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("SuggestGasPrice"); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(c.gasPrice), nil
+}
+
+func (c *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("SuggestGasTipCap"); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(c.tipCap), nil
+}
+
+func (c *Client) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("FeeHistory"); err != nil {
+		return nil, err
+	}
+	last, err := c.header(lastBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	lastNumber := last.Number.Uint64()
+	if blockCount > lastNumber+1 {
+		blockCount = lastNumber + 1
+	}
+	oldest := lastNumber + 1 - blockCount
+	history := &ethereum.FeeHistory{OldestBlock: new(big.Int).SetUint64(oldest)}
+	for number := oldest; number <= lastNumber; number++ {
+		header := c.headers[number]
+		history.BaseFee = append(history.BaseFee, new(big.Int).Set(header.BaseFee))
+		history.GasUsedRatio = append(history.GasUsedRatio, float64(header.GasUsed)/float64(header.GasLimit))
+		rewards := make([]*big.Int, len(rewardPercentiles))
+		for i := range rewards {
+			rewards[i] = new(big.Int).Set(c.tipCap)
+		}
+		history.Reward = append(history.Reward, rewards)
+	}
+	// The base fee of the block after the last one is included, like a node
+	history.BaseFee = append(history.BaseFee, big.NewInt(DefaultBaseFee))
+	return history, nil
+}
+
+func (c *Client) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("FilterLogs"); err != nil {
+		return nil, err
+	}
+
+	if q.BlockHash != nil {
+		if _, err := c.blockIndex(*q.BlockHash); err != nil {
+			return nil, err
+		}
+	} else {
+		from, err := c.header(q.FromBlock)
+		if err != nil {
+			return nil, err
+		}
+		to, err := c.header(q.ToBlock)
+		if err != nil {
+			return nil, err
+		}
+		if from.Number.Cmp(to.Number) > 0 {
+			return nil, errors.New("invalid block range")
+		}
+	}
+
+	var found []types.Log
+	for _, log := range c.logs {
+		if q.BlockHash == nil && !inRange(log.BlockNumber, c.resolve(q.FromBlock), c.resolve(q.ToBlock)) {
+			continue
+		}
+		if matches(log, q) {
+			found = append(found, log)
+		}
+	}
+	return found, nil
+}
+
+// resolve returns the number of the block. c.mu must be held.
+func (c *Client) resolve(number *big.Int) uint64 {
+	header, err := c.header(number)
+	if err != nil {
+		return uint64(len(c.headers) - 1)
+	}
+	return header.Number.Uint64()
+}
+
+func inRange(number uint64, from uint64, to uint64) bool {
+	return number >= from && number <= to
+}
+
+// matches reports whether the log passes the query's block hash, address and
+// topic filters. Block ranges are checked by the caller.
+func matches(log types.Log, q ethereum.FilterQuery) bool {
+	if q.BlockHash != nil && log.BlockHash != *q.BlockHash {
+		return false
+	}
+	if len(q.Addresses) > 0 {
+		found := false
+		for _, address := range q.Addresses {
+			if log.Address == address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(q.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, alternatives := range q.Topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range alternatives {
+			if log.Topics[i] == topic {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("NonceAt"); err != nil {
+		return 0, err
+	}
+	return c.nonces[account], nil
+}
+
+func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("PendingNonceAt"); err != nil {
+		return 0, err
+	}
+	if pending := c.pendingNonces[account]; pending > c.nonces[account] {
+		return pending, nil
+	}
+	return c.nonces[account], nil
+}
+
+func (c *Client) PeerCount(ctx context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("PeerCount"); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func (c *Client) PendingTransactionCount(ctx context.Context) (uint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("PendingTransactionCount"); err != nil {
+		return 0, err
+	}
+	return uint(len(c.pending)), nil
+}
+
+// SendTransaction queues the transaction for the next mined block
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("SendTransaction"); err != nil {
+		return err
+	}
+	if _, known := c.transactions[tx.Hash()]; known {
+		return errors.New("already known")
+	}
+	if sender, err := c.sender(tx); err == nil {
+		if tx.Nonce() < c.nonces[sender] {
+			return errors.New("nonce too low")
+		}
+		if tx.Nonce() >= c.pendingNonces[sender] {
+			c.pendingNonces[sender] = tx.Nonce() + 1
+		}
+	}
+	c.transactions[tx.Hash()] = tx
+	c.pending = append(c.pending, tx)
+	return nil
+}
+
+func (c *Client) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("SyncProgress"); err != nil {
+		return nil, err
+	}
+	// nil means the node is synced
+	return nil, nil
+}
+
+func (c *Client) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("TransactionByHash"); err != nil {
+		return nil, false, err
+	}
+	tx, ok := c.transactions[hash]
+	if !ok {
+		return nil, false, ErrNotFound
+	}
+	_, mined := c.receipts[hash]
+	return tx, !mined, nil
+}
+
+func (c *Client) TransactionCount(ctx context.Context, blockHash common.Hash) (uint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("TransactionCount"); err != nil {
+		return 0, err
+	}
+	i, err := c.blockIndex(blockHash)
+	if err != nil {
+		return 0, err
+	}
+	return uint(len(c.blocks[i].Transactions())), nil
+}
+
+func (c *Client) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("TransactionInBlock"); err != nil {
+		return nil, err
+	}
+	i, err := c.blockIndex(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	txs := c.blocks[i].Transactions()
+	if index >= uint(len(txs)) {
+		return nil, ErrNotFound
+	}
+	return txs[index], nil
+}
+
+func (c *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("TransactionReceipt"); err != nil {
+		return nil, err
+	}
+	receipt, ok := c.receipts[txHash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *receipt
+	return &copied, nil
+}
+
+func (c *Client) TransactionSender(ctx context.Context, tx *types.Transaction, block common.Hash, index uint) (common.Address, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("TransactionSender"); err != nil {
+		return common.Address{}, err
+	}
+	return c.sender(tx)
+}
+
+// SubscribeNewHead sends the header of every block mined from now on
+func (c *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("SubscribeNewHead"); err != nil {
+		return nil, err
+	}
+	sub := &headSubscription{subscription: newSubscription(c), ch: ch}
+	c.headSubs = append(c.headSubs, sub)
+	return sub, nil
+}
+
+// SubscribeFilterLogs sends every log matching the query that is mined from
+// now on. Block ranges are ignored, as they are by nodes.
+func (c *Client) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.failure("SubscribeFilterLogs"); err != nil {
+		return nil, err
+	}
+	sub := &logSubscription{subscription: newSubscription(c), query: q, ch: ch}
+	c.logSubs = append(c.logSubs, sub)
+	return sub, nil
+}
+
+// subscription implements ethereum.Subscription
+type subscription struct {
+	client *Client
+	once   sync.Once
+	quit   chan struct{}
+	errs   chan error
+}
+
+type headSubscription struct {
+	*subscription
+	ch chan<- *types.Header
+}
+
+type logSubscription struct {
+	*subscription
+	query ethereum.FilterQuery
+	ch    chan<- types.Log
+}
+
+func newSubscription(client *Client) *subscription {
+	return &subscription{
+		client: client,
+		quit:   make(chan struct{}),
+		errs:   make(chan error, 1),
+	}
+}
+
+func (s *subscription) Unsubscribe() {
+	s.end(nil)
+}
+
+func (s *subscription) Err() <-chan error {
+	return s.errs
+}
+
+// end removes the subscription, sends err if there is one and closes the
+// error channel, as go-ethereum subscriptions do
+func (s *subscription) end(err error) {
+	s.once.Do(func() {
+		s.client.unsubscribe(s)
+		close(s.quit)
+		if err != nil {
+			s.errs <- err
+		}
+		close(s.errs)
+	})
+}
+
+func (c *Client) unsubscribe(s *subscription) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	headSubs := c.headSubs[:0]
+	for _, sub := range c.headSubs {
+		if sub.subscription != s {
+			headSubs = append(headSubs, sub)
+		}
+	}
+	c.headSubs = headSubs
+
+	logSubs := c.logSubs[:0]
+	for _, sub := range c.logSubs {
+		if sub.subscription != s {
+			logSubs = append(logSubs, sub)
+		}
+	}
+	c.logSubs = logSubs
+}
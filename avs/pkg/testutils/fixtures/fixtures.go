@@ -0,0 +1,211 @@
+// Package fixtures derives operator keys, tasks and signed responses from a
+// seed. The same seed always yields the same fixtures, on any machine and in
+// any release, so aggregator tests are reproducible and signatures produced by
+// one version can be checked by another.
+//
+// Derivations must never change once released; add new labels instead.
+package fixtures
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/digest"
+)
+
+// domain separates fixture derivations from any other use of the seed
+const domain = "eigenlvr fixtures v1"
+
+// DefaultQuorumThresholdPercentage is the threshold of generated tasks
+const DefaultQuorumThresholdPercentage = 67
+
+// Generator derives fixtures from a seed
+type Generator struct {
+	seed []byte
+}
+
+func New(seed string) *Generator {
+	return &Generator{seed: []byte(seed)}
+}
+
+// derive returns keccak256(domain || seed || label || index || counter)
+func (g *Generator) derive(label string, index uint64, counter uint32) []byte {
+	var suffix [12]byte
+	binary.BigEndian.PutUint64(suffix[:8], index)
+	binary.BigEndian.PutUint32(suffix[8:], counter)
+	return crypto.Keccak256([]byte(domain), g.seed, []byte(label), suffix[:])
+}
+
+// Operator is a registered operator's keys
+type Operator struct {
+	Index      int
+	BlsKeyPair *bls.KeyPair
+	EcdsaKey   *ecdsa.PrivateKey
+	Address    common.Address
+	OperatorId types.OperatorId
+	PubkeyG2   *bls.G2Point
+	Stake      *big.Int
+}
+
+// Operator returns the i-th operator. Its stake is i+1 ether, so operators
+// carry distinct, predictable weights.
+func (g *Generator) Operator(i int) Operator {
+	blsSecret := new(big.Int).SetBytes(g.derive("bls", uint64(i), 0))
+	blsKey, err := bls.NewPrivateKey(blsSecret.String())
+	if err != nil {
+		panic(fmt.Sprintf("fixtures: invalid bls key: %v", err))
+	}
+	keyPair := bls.NewKeyPair(blsKey)
+
+	// A digest outside the secp256k1 order is vanishingly rare; derive
+	// another one if it happens
+	var ecdsaKey *ecdsa.PrivateKey
+	for counter := uint32(0); ecdsaKey == nil; counter++ {
+		ecdsaKey, _ = crypto.ToECDSA(g.derive("ecdsa", uint64(i), counter))
+	}
+
+	stake := new(big.Int).Mul(big.NewInt(int64(i+1)), big.NewInt(1e18))
+	return Operator{
+		Index:      i,
+		BlsKeyPair: keyPair,
+		EcdsaKey:   ecdsaKey,
+		Address:    crypto.PubkeyToAddress(ecdsaKey.PublicKey),
+		OperatorId: types.OperatorIdFromG1Pubkey(keyPair.GetPubKeyG1()),
+		PubkeyG2:   keyPair.GetPubKeyG2(),
+		Stake:      stake,
+	}
+}
+
+// Operators returns operators 0 to n-1
+func (g *Generator) Operators(n int) []Operator {
+	operators := make([]Operator, n)
+	for i := range operators {
+		operators[i] = g.Operator(i)
+	}
+	return operators
+}
+
+// Task mirrors the fields of an AuctionTask
+type Task struct {
+	TaskIndex                 uint32           `json:"taskIndex"`
+	PoolId                    common.Hash      `json:"poolId"`
+	BlockNumber               uint32           `json:"blockNumber"`
+	TaskCreatedBlock          uint32           `json:"taskCreatedBlock"`
+	QuorumNumbers             types.QuorumNums `json:"quorumNumbers"`
+	QuorumThresholdPercentage uint32           `json:"quorumThresholdPercentage"`
+}
+
+// Task returns the task with the index, created at block 1000+index in
+// quorum 0
+func (g *Generator) Task(taskIndex uint32) Task {
+	block := 1000 + taskIndex
+	return Task{
+		TaskIndex:                 taskIndex,
+		PoolId:                    common.BytesToHash(g.derive("pool", uint64(taskIndex), 0)),
+		BlockNumber:               block,
+		TaskCreatedBlock:          block,
+		QuorumNumbers:             types.QuorumNums{0},
+		QuorumThresholdPercentage: DefaultQuorumThresholdPercentage,
+	}
+}
+
+// TaskResponse mirrors the fields and JSON encoding of an AuctionTaskResponse
+type TaskResponse struct {
+	ReferenceTaskIndex uint32         `json:"referenceTaskIndex"`
+	Winner             common.Address `json:"winner"`
+	WinningBid         *big.Int       `json:"winningBid"`
+	TotalBids          uint32         `json:"totalBids"`
+}
+
+// Digest is the message operators sign for the response
+func (r TaskResponse) Digest() (common.Hash, error) {
+	return digest.AuctionTaskResponseDigest(r.ReferenceTaskIndex, r.Winner, r.WinningBid, r.TotalBids)
+}
+
+// Response returns the agreed outcome of the task: a winner, a winning bid
+// below 1 ether and between 1 and 16 bids
+func (g *Generator) Response(task Task) TaskResponse {
+	outcome := g.derive("response", uint64(task.TaskIndex), 0)
+	bid := new(big.Int).SetBytes(outcome[20:28])
+	bid.Mod(bid, big.NewInt(1e18))
+	return TaskResponse{
+		ReferenceTaskIndex: task.TaskIndex,
+		Winner:             common.BytesToAddress(outcome[:20]),
+		WinningBid:         bid,
+		TotalBids:          uint32(outcome[28]%16) + 1,
+	}
+}
+
+// DivergentResponse returns a response to the task that disagrees with
+// Response, e.g. from a faulty operator. Variants differ from each other.
+func (g *Generator) DivergentResponse(task Task, variant int) TaskResponse {
+	response := g.Response(task)
+	response.Winner = common.BytesToAddress(g.derive("divergent", uint64(task.TaskIndex), uint32(variant))[:20])
+	return response
+}
+
+// SignedResponse is a response with an operator's BLS signature over its digest
+type SignedResponse struct {
+	TaskResponse TaskResponse     `json:"taskResponse"`
+	Digest       common.Hash      `json:"digest"`
+	BlsSignature *bls.Signature   `json:"blsSignature"`
+	OperatorId   types.OperatorId `json:"operatorId"`
+}
+
+// Sign signs the response as the operator. BLS signatures are deterministic,
+// so the result is as reproducible as the keys.
+func (o Operator) Sign(response TaskResponse) (SignedResponse, error) {
+	responseDigest, err := response.Digest()
+	if err != nil {
+		return SignedResponse{}, err
+	}
+	return SignedResponse{
+		TaskResponse: response,
+		Digest:       responseDigest,
+		BlsSignature: o.BlsKeyPair.SignMessage(responseDigest),
+		OperatorId:   o.OperatorId,
+	}, nil
+}
+
+// SignedResponses has every operator sign the task's agreed response
+func (g *Generator) SignedResponses(task Task, operators []Operator) ([]SignedResponse, error) {
+	response := g.Response(task)
+	signed := make([]SignedResponse, 0, len(operators))
+	for _, operator := range operators {
+		signedResponse, err := operator.Sign(response)
+		if err != nil {
+			return nil, fmt.Errorf("operator %d: %w", operator.Index, err)
+		}
+		signed = append(signed, signedResponse)
+	}
+	return signed, nil
+}
+
+// Aggregate sums the signatures of the signed responses, which must all be
+// over the same digest, and the G2 public keys of their signers among the
+// operators
+func Aggregate(signed []SignedResponse, operators []Operator) (*bls.Signature, *bls.G2Point, error) {
+	pubkeys := make(map[types.OperatorId]*bls.G2Point, len(operators))
+	for _, operator := range operators {
+		pubkeys[operator.OperatorId] = operator.PubkeyG2
+	}
+
+	signature := bls.NewZeroSignature()
+	pubkey := bls.NewZeroG2Point()
+	for _, response := range signed {
+		signerPubkey, ok := pubkeys[response.OperatorId]
+		if !ok {
+			return nil, nil, fmt.Errorf("signer %x is not among the operators", response.OperatorId)
+		}
+		signature.Add(response.BlsSignature)
+		pubkey.Add(signerPubkey)
+	}
+	return signature, pubkey, nil
+}
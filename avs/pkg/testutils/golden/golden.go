@@ -0,0 +1,193 @@
+// Package golden locks in the values the Go side and the Solidity contracts
+// must agree on: the ABI encoding and digest of task responses, the curve
+// point operators sign, and the inputs BLSSignatureChecker derives from an
+// aggregate signature. The vectors in vectors.json are generated from fixtures
+// and asserted by contracts/test/GoldenVectors.t.sol, so a change on either
+// side that breaks compatibility fails Verify or the Foundry test.
+//
+// After an intended change to the encoding, regenerate both files together,
+// vectors.json with
+//
+//	go run ./cmd/vectors -out pkg/testutils/golden/vectors.json
+package golden
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/Layr-Labs/eigensdk-go/crypto/bls"
+	"github.com/Layr-Labs/eigensdk-go/crypto/bn254"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/digest"
+	"github.com/eigenlvr/avs/pkg/sigchecker"
+	"github.com/eigenlvr/avs/pkg/testutils/fixtures"
+)
+
+// Seed, Operators and Tasks are what vectors.json was generated from
+const (
+	Seed      = "eigenlvr golden vectors"
+	Operators = 4
+	Tasks     = 4
+)
+
+//go:embed vectors.json
+var vectorsJson []byte
+
+// File is the layout of vectors.json
+type File struct {
+	Seed      string   `json:"seed"`
+	Operators int      `json:"operators"`
+	Vectors   []Vector `json:"vectors"`
+}
+
+// G1 is a G1 point as the contracts encode it
+type G1 struct {
+	X *hexutil.Big `json:"x"`
+	Y *hexutil.Big `json:"y"`
+}
+
+// G2 is a G2 point as the contracts encode it, each coordinate ordered
+// [imaginary, real]
+type G2 struct {
+	X [2]*hexutil.Big `json:"x"`
+	Y [2]*hexutil.Big `json:"y"`
+}
+
+// Vector is one task response signed by a set of operators
+type Vector struct {
+	Task     fixtures.Task         `json:"task"`
+	Response fixtures.TaskResponse `json:"response"`
+	// Encoded is abi.encode(taskResponse)
+	Encoded hexutil.Bytes `json:"encoded"`
+	// Digest is keccak256(Encoded), the message passed to checkSignatures
+	Digest common.Hash `json:"digest"`
+	// MessageG1 is BN254.hashToG1(Digest), the point operators sign
+	MessageG1 G1 `json:"messageG1"`
+	// Signers are the indices of the operators that signed
+	Signers []int `json:"signers"`
+	Apk     G1    `json:"apk"`
+	ApkG2   G2    `json:"apkG2"`
+	Sigma   G1    `json:"sigma"`
+	// Gamma is the coefficient trySignatureAndApkVerification derives
+	Gamma *hexutil.Big `json:"gamma"`
+}
+
+// Load returns the committed vectors
+func Load() (File, error) {
+	var file File
+	if err := json.Unmarshal(vectorsJson, &file); err != nil {
+		return File{}, fmt.Errorf("failed to decode golden vectors: %w", err)
+	}
+	return file, nil
+}
+
+// Generate derives the vectors of tasks 0 to tasks-1. Task i is signed by all
+// but the last i%operators operators, so vectors cover shrinking signer sets.
+func Generate(seed string, operators int, tasks int) (File, error) {
+	generator := fixtures.New(seed)
+	keys := generator.Operators(operators)
+
+	file := File{Seed: seed, Operators: operators}
+	for i := 0; i < tasks; i++ {
+		task := generator.Task(uint32(i))
+		signers := keys[:operators-i%operators]
+
+		signed, err := generator.SignedResponses(task, signers)
+		if err != nil {
+			return File{}, err
+		}
+		vector, err := newVector(task, signed, signers)
+		if err != nil {
+			return File{}, fmt.Errorf("task %d: %w", i, err)
+		}
+		file.Vectors = append(file.Vectors, vector)
+	}
+	return file, nil
+}
+
+func newVector(task fixtures.Task, signed []fixtures.SignedResponse, signers []fixtures.Operator) (Vector, error) {
+	response := signed[0].TaskResponse
+	encoded, err := digest.EncodeAuctionTaskResponse(response.ReferenceTaskIndex, response.Winner, response.WinningBid, response.TotalBids)
+	if err != nil {
+		return Vector{}, err
+	}
+	responseDigest := crypto.Keccak256Hash(encoded)
+
+	sigma, apkG2, err := fixtures.Aggregate(signed, signers)
+	if err != nil {
+		return Vector{}, err
+	}
+	apk := bls.NewZeroG1Point()
+	indices := make([]int, len(signers))
+	for i, signer := range signers {
+		apk.Add(signer.BlsKeyPair.GetPubKeyG1())
+		indices[i] = signer.Index
+	}
+
+	messageG1 := bn254.MapToCurve(responseDigest)
+	return Vector{
+		Task:      task,
+		Response:  response,
+		Encoded:   encoded,
+		Digest:    responseDigest,
+		MessageG1: g1(sigchecker.ToG1Point(&bls.G1Point{G1Affine: messageG1})),
+		Signers:   indices,
+		Apk:       g1(sigchecker.ToG1Point(apk)),
+		ApkG2:     g2(sigchecker.ToG2Point(apkG2)),
+		Sigma:     g1(sigchecker.ToG1Point(sigma.G1Point)),
+		Gamma:     (*hexutil.Big)(sigchecker.Gamma(responseDigest, apk, apkG2, sigma)),
+	}, nil
+}
+
+// Verify regenerates the file's vectors and reports the first that differs,
+// then checks each aggregate signature verifies against its signers' APK
+func Verify(file File) error {
+	regenerated, err := Generate(file.Seed, file.Operators, len(file.Vectors))
+	if err != nil {
+		return err
+	}
+
+	for i, vector := range file.Vectors {
+		want, err := json.Marshal(vector)
+		if err != nil {
+			return err
+		}
+		got, err := json.Marshal(regenerated.Vectors[i])
+		if err != nil {
+			return err
+		}
+		if string(got) != string(want) {
+			return fmt.Errorf("vector for task %d changed:\n got: %s\nwant: %s", vector.Task.TaskIndex, got, want)
+		}
+
+		sigma := bls.Signature{G1Point: bls.NewG1Point(vector.Sigma.X.ToInt(), vector.Sigma.Y.ToInt())}
+		apkG2 := bls.NewG2Point(
+			[2]*big.Int{vector.ApkG2.X[0].ToInt(), vector.ApkG2.X[1].ToInt()},
+			[2]*big.Int{vector.ApkG2.Y[0].ToInt(), vector.ApkG2.Y[1].ToInt()},
+		)
+		valid, err := sigma.Verify(apkG2, vector.Digest)
+		if err != nil {
+			return fmt.Errorf("failed to verify task %d signature: %w", vector.Task.TaskIndex, err)
+		}
+		if !valid {
+			return fmt.Errorf("task %d signature doesn't verify against its apk", vector.Task.TaskIndex)
+		}
+	}
+	return nil
+}
+
+func g1(point sigchecker.G1Point) G1 {
+	return G1{X: (*hexutil.Big)(point.X), Y: (*hexutil.Big)(point.Y)}
+}
+
+func g2(point sigchecker.G2Point) G2 {
+	return G2{
+		X: [2]*hexutil.Big{(*hexutil.Big)(point.X[0]), (*hexutil.Big)(point.X[1])},
+		Y: [2]*hexutil.Big{(*hexutil.Big)(point.Y[0]), (*hexutil.Big)(point.Y[1])},
+	}
+}
@@ -0,0 +1,17 @@
+package golden
+
+import "testing"
+
+func TestVectorsMatchGenerator(t *testing.T) {
+	file, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Seed != Seed || file.Operators != Operators || len(file.Vectors) != Tasks {
+		t.Fatalf("vectors.json holds seed %q, %d operators and %d tasks, want %q, %d and %d",
+			file.Seed, file.Operators, len(file.Vectors), Seed, Operators, Tasks)
+	}
+	if err := Verify(file); err != nil {
+		t.Fatal(err)
+	}
+}
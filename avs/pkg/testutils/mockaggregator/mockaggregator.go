@@ -0,0 +1,297 @@
+// Package mockaggregator is an in-process stand-in for the aggregator's task
+// response API. Each request is answered by the next scripted behavior, so
+// tests can drive an operator's send, retry and resend logic through
+// acceptances, rejections, slow responses and dropped connections without
+// running a real aggregator.
+package mockaggregator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eigenlvr/avs/pkg/apiversion"
+	"github.com/eigenlvr/avs/pkg/compression"
+)
+
+// ResponsePath is the route task responses are posted to
+const ResponsePath = "/task-response"
+
+// Action is how the server answers a request
+type Action int
+
+const (
+	// ActionAccept answers 200 like an aggregator that accepted the response
+	ActionAccept Action = iota
+	// ActionReject answers with a 4xx status, which operators treat as final
+	ActionReject
+	// ActionFail answers with a 5xx or 429 status, which operators retry
+	ActionFail
+	// ActionDrop closes the connection without answering
+	ActionDrop
+)
+
+// Behavior scripts the answer to one request
+type Behavior struct {
+	Action Action
+	// Delay is waited before answering, or before dropping the connection
+	Delay time.Duration
+	// Status overrides the status code of ActionReject and ActionFail
+	Status  int
+	Message string
+}
+
+// Accept answers 200
+func Accept() Behavior {
+	return Behavior{Action: ActionAccept}
+}
+
+// Reject answers 400 with the message
+func Reject(message string) Behavior {
+	return Behavior{Action: ActionReject, Status: http.StatusBadRequest, Message: message}
+}
+
+// Fail answers with the status, e.g. 503 or 429
+func Fail(status int) Behavior {
+	return Behavior{Action: ActionFail, Status: status, Message: http.StatusText(status)}
+}
+
+// Drop closes the connection without answering
+func Drop() Behavior {
+	return Behavior{Action: ActionDrop}
+}
+
+// After delays the behavior, e.g. Accept().After(time.Second) to answer
+// slowly or Drop().After(time.Minute) to time a client out
+func (b Behavior) After(delay time.Duration) Behavior {
+	b.Delay = delay
+	return b
+}
+
+// Request is a task response the server received
+type Request struct {
+	Path string
+	// Encoding is the Content-Encoding the body was sent with
+	Encoding string
+	// Body is the decoded request body
+	Body       json.RawMessage
+	TaskIndex  uint32
+	Behavior   Behavior
+	ReceivedAt time.Time
+}
+
+// Options configures the server
+type Options struct {
+	// Legacy serves only the unversioned routes, without a version header,
+	// like an aggregator that predates API versioning
+	Legacy bool
+	// Default answers requests once the script runs out, Accept() if unset
+	Default *Behavior
+}
+
+// Server is a running mock aggregator
+type Server struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	script       []Behavior
+	defaultReply Behavior
+	received     []Request
+	// notify is closed and replaced whenever a request is received
+	notify chan struct{}
+}
+
+// New starts a mock aggregator listening on a local port
+func New(options Options) *Server {
+	s := &Server{
+		defaultReply: Accept(),
+		notify:       make(chan struct{}),
+	}
+	if options.Default != nil {
+		s.defaultReply = *options.Default
+	}
+
+	mux := http.NewServeMux()
+	if options.Legacy {
+		mux.HandleFunc(ResponsePath, s.handleResponse)
+	} else {
+		mux.HandleFunc(apiversion.Path(ResponsePath), s.handleResponse)
+	}
+
+	var handler http.Handler = withEncoding(compression.Middleware(mux))
+	if !options.Legacy {
+		handler = withApiVersion(handler)
+	}
+	s.server = httptest.NewServer(handler)
+	return s
+}
+
+// Addr is the host:port to configure as the operator's aggregator address
+func (s *Server) Addr() string {
+	return strings.TrimPrefix(s.server.URL, "http://")
+}
+
+// URL is the server's base URL
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close shuts the server down, dropping any request still being delayed
+func (s *Server) Close() {
+	s.server.CloseClientConnections()
+	s.server.Close()
+}
+
+// Script queues behaviors answering the next requests, in order
+func (s *Server) Script(behaviors ...Behavior) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.script = append(s.script, behaviors...)
+}
+
+// SetDefault sets the behavior used once the script runs out
+func (s *Server) SetDefault(behavior Behavior) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.defaultReply = behavior
+}
+
+// Received returns every request received so far, in order
+func (s *Server) Received() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.received...)
+}
+
+// Accepted returns the requests that were answered with ActionAccept
+func (s *Server) Accepted() []Request {
+	var accepted []Request
+	for _, request := range s.Received() {
+		if request.Behavior.Action == ActionAccept {
+			accepted = append(accepted, request)
+		}
+	}
+	return accepted
+}
+
+// WaitForRequests blocks until at least n requests have been received or ctx
+// is done
+func (s *Server) WaitForRequests(ctx context.Context, n int) error {
+	for {
+		s.mu.Lock()
+		count, notify := len(s.received), s.notify
+		s.mu.Unlock()
+		if count >= n {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// next records the request and pops the behavior answering it
+func (s *Server) next(request Request) Behavior {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	behavior := s.defaultReply
+	if len(s.script) > 0 {
+		behavior, s.script = s.script[0], s.script[1:]
+	}
+
+	request.Behavior = behavior
+	s.received = append(s.received, request)
+	close(s.notify)
+	s.notify = make(chan struct{})
+	return behavior
+}
+
+func (s *Server) handleResponse(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	var decoded struct {
+		TaskResponse struct {
+			ReferenceTaskIndex uint32 `json:"referenceTaskIndex"`
+		} `json:"taskResponse"`
+	}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	behavior := s.next(Request{
+		Path:       r.URL.Path,
+		Encoding:   r.Context().Value(encodingKey{}).(string),
+		Body:       body,
+		TaskIndex:  decoded.TaskResponse.ReferenceTaskIndex,
+		ReceivedAt: time.Now(),
+	})
+
+	if behavior.Delay > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(behavior.Delay):
+		}
+	}
+
+	switch behavior.Action {
+	case ActionAccept:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"status": "accepted"})
+	case ActionReject, ActionFail:
+		status := behavior.Status
+		if status == 0 {
+			status = http.StatusBadRequest
+			if behavior.Action == ActionFail {
+				status = http.StatusServiceUnavailable
+			}
+		}
+		http.Error(w, behavior.Message, status)
+	case ActionDrop:
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			panic("mockaggregator: connection can't be hijacked")
+		}
+		conn, _, err := hijacker.Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}
+}
+
+// encodingKey carries the request's Content-Encoding past the decompression
+// middleware, which strips it
+type encodingKey struct{}
+
+func withEncoding(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), encodingKey{}, r.Header.Get("Content-Encoding"))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func withApiVersion(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(apiversion.Header, apiversion.Current)
+		next.ServeHTTP(w, r)
+	})
+}
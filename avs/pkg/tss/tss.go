@@ -0,0 +1,276 @@
+// Package tss signs with an ECDSA key that no single machine holds. The key is
+// generated and shared among signing parties with a threshold ECDSA protocol,
+// so that any Threshold of them can sign together while fewer learn nothing
+// about the key. Each party runs a signing daemon holding its share and its
+// own policy about what it is willing to sign.
+//
+// The aggregator coordinates: it opens a signing session by sending the
+// digest, and the transaction it belongs to, to every party. The parties
+// that accept run the protocol rounds among themselves and each returns the
+// joint signature. A compromised aggregator can therefore only ask for
+// signatures; Threshold parties still have to agree to produce one.
+//
+// A party serves one endpoint:
+//
+//	POST /v1/sign  {"sessionId","address","digest","threshold","parties","transaction"}
+//	-> 200 {"signature": "0x<r||s||v>"} once the session completes
+//	-> 4xx with a reason when the party refuses to sign
+package tss
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// SignPath is the endpoint a party opens or joins a signing session at
+	SignPath = "/v1/sign"
+
+	// DefaultTimeout bounds a signing session when Config.Timeout is unset
+	DefaultTimeout = 30 * time.Second
+)
+
+var (
+	// ErrNotEnoughParties is returned when too many parties refused or failed
+	// for the rest to reach the threshold
+	ErrNotEnoughParties = errors.New("not enough signing parties")
+	// ErrWrongSigner is returned when a party returns a signature that doesn't
+	// recover to the shared key's address
+	ErrWrongSigner = errors.New("signature does not recover to the shared key")
+)
+
+// Config describes the signing parties of a shared key
+type Config struct {
+	// Parties are the base URLs of the parties' signing daemons
+	Parties []string
+	// Threshold is how many parties must take part in a signature
+	Threshold int
+	// Address is the address of the shared key
+	Address common.Address
+	// Authorization is sent as the Authorization header when set
+	Authorization string
+	// Timeout bounds one signing session
+	Timeout time.Duration
+}
+
+// Transaction is the transaction a digest belongs to, sent along so every
+// party can check what it is signing against its own policy
+type Transaction struct {
+	ChainId   *big.Int        `json:"chainId"`
+	Nonce     uint64          `json:"nonce"`
+	To        *common.Address `json:"to"`
+	Value     *big.Int        `json:"value"`
+	Gas       uint64          `json:"gas"`
+	GasPrice  *big.Int        `json:"gasPrice,omitempty"`
+	GasFeeCap *big.Int        `json:"gasFeeCap,omitempty"`
+	GasTipCap *big.Int        `json:"gasTipCap,omitempty"`
+	Data      hexutil.Bytes   `json:"data"`
+}
+
+type signRequest struct {
+	SessionId   string         `json:"sessionId"`
+	Address     common.Address `json:"address"`
+	Digest      common.Hash    `json:"digest"`
+	Threshold   int            `json:"threshold"`
+	Parties     int            `json:"parties"`
+	Transaction *Transaction   `json:"transaction,omitempty"`
+}
+
+type signResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// Signer coordinates signing sessions with the parties of a shared key
+type Signer struct {
+	config Config
+	http   *http.Client
+}
+
+func NewSigner(config Config) (*Signer, error) {
+	if len(config.Parties) == 0 {
+		return nil, errors.New("no signing parties configured")
+	}
+	if config.Threshold < 2 || config.Threshold > len(config.Parties) {
+		return nil, fmt.Errorf("threshold %d must be between 2 and the %d parties", config.Threshold, len(config.Parties))
+	}
+	if config.Address == (common.Address{}) {
+		return nil, errors.New("shared key address is required")
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+
+	parties := make([]string, len(config.Parties))
+	for i, party := range config.Parties {
+		parties[i] = strings.TrimRight(party, "/")
+	}
+	config.Parties = parties
+
+	return &Signer{
+		config: config,
+		http:   &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Address returns the address of the shared key
+func (s *Signer) Address() common.Address {
+	return s.config.Address
+}
+
+// Sign runs a signing session over the digest and returns a 65-byte
+// [R || S || V] signature with V of 0 or 1. It returns as soon as one party
+// returns a signature that recovers to the shared key, and fails once so many
+// parties have refused that the threshold can't be reached.
+func (s *Signer) Sign(ctx context.Context, digest common.Hash, tx *Transaction) ([]byte, error) {
+	var sessionId [16]byte
+	if _, err := rand.Read(sessionId[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate session id: %w", err)
+	}
+	body, err := json.Marshal(signRequest{
+		SessionId:   hex.EncodeToString(sessionId[:]),
+		Address:     s.config.Address,
+		Digest:      digest,
+		Threshold:   s.config.Threshold,
+		Parties:     len(s.config.Parties),
+		Transaction: tx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signing request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	type result struct {
+		party     string
+		signature []byte
+		err       error
+	}
+	results := make(chan result, len(s.config.Parties))
+	for _, party := range s.config.Parties {
+		go func(party string) {
+			signature, err := s.requestSignature(ctx, party, body, digest)
+			results <- result{party: party, signature: signature, err: err}
+		}(party)
+	}
+
+	// A signature needs Threshold parties, so once more than the rest have
+	// failed no signature can come
+	tolerated := len(s.config.Parties) - s.config.Threshold
+	var failures []string
+	for range s.config.Parties {
+		r := <-results
+		if r.err == nil {
+			return r.signature, nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", r.party, r.err))
+		if len(failures) > tolerated {
+			break
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNotEnoughParties, strings.Join(failures, "; "))
+}
+
+// SignerFn returns a bind.SignerFn signing transactions for the chain with
+// the shared key
+func (s *Signer) SignerFn(chainId *big.Int) bind.SignerFn {
+	signer := types.LatestSignerForChainID(chainId)
+	return func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if from != s.config.Address {
+			return nil, bind.ErrNotAuthorized
+		}
+		details := &Transaction{
+			ChainId:  chainId,
+			Nonce:    tx.Nonce(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Gas:      tx.Gas(),
+			GasPrice: tx.GasPrice(),
+			Data:     tx.Data(),
+		}
+		if tx.Type() == types.DynamicFeeTxType {
+			details.GasPrice = nil
+			details.GasFeeCap = tx.GasFeeCap()
+			details.GasTipCap = tx.GasTipCap()
+		}
+
+		signature, err := s.Sign(context.Background(), signer.Hash(tx), details)
+		if err != nil {
+			return nil, err
+		}
+		return tx.WithSignature(signer, signature)
+	}
+}
+
+// requestSignature asks one party to join the session and checks the
+// signature it returns
+func (s *Signer) requestSignature(ctx context.Context, party string, body []byte, digest common.Hash) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, party+SignPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.config.Authorization != "" {
+		req.Header.Set("Authorization", s.config.Authorization)
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var signed signResponse
+	if err := json.NewDecoder(resp.Body).Decode(&signed); err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
+	return s.checkSignature(digest, signed.Signature)
+}
+
+// checkSignature normalizes V to 0 or 1 and checks that the signature is
+// canonical and recovers to the shared key
+func (s *Signer) checkSignature(digest common.Hash, signature []byte) ([]byte, error) {
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("signature is %d bytes", len(signature))
+	}
+	normalized := append([]byte(nil), signature...)
+	if normalized[crypto.RecoveryIDOffset] >= 27 {
+		normalized[crypto.RecoveryIDOffset] -= 27
+	}
+
+	r := new(big.Int).SetBytes(normalized[:32])
+	sValue := new(big.Int).SetBytes(normalized[32:64])
+	if !crypto.ValidateSignatureValues(normalized[crypto.RecoveryIDOffset], r, sValue, true) {
+		return nil, errors.New("signature is not canonical")
+	}
+
+	publicKey, err := crypto.SigToPub(digest[:], normalized)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	if recovered := crypto.PubkeyToAddress(*publicKey); recovered != s.config.Address {
+		return nil, fmt.Errorf("%w: recovered %s", ErrWrongSigner, recovered.Hex())
+	}
+	return normalized, nil
+}
@@ -0,0 +1,113 @@
+package tss
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/testutils/fixtures"
+)
+
+// party serves a signing daemon that answers every session with sign
+func party(t *testing.T, sign func(digest common.Hash) []byte) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request signRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(signResponse{Signature: hexutil.Bytes(sign(request.Digest))})
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// signWith returns a signature over the digest with V of 27 or 28, as
+// Ethereum tooling usually returns them
+func signWith(t *testing.T, key *ecdsa.PrivateKey, digest common.Hash) []byte {
+	t.Helper()
+
+	signature, err := crypto.Sign(digest[:], key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature
+}
+
+// malleate returns the high-s twin of a signature with V of 27 or 28, which
+// recovers to the same key but isn't canonical
+func malleate(signature []byte) []byte {
+	twin := append([]byte(nil), signature...)
+	s := new(big.Int).SetBytes(twin[32:64])
+	new(big.Int).Sub(crypto.S256().Params().N, s).FillBytes(twin[32:64])
+	twin[crypto.RecoveryIDOffset] = 27 + ((twin[crypto.RecoveryIDOffset] - 27) ^ 1)
+	return twin
+}
+
+func TestSignRejectsForgedAndMalleatedSignatures(t *testing.T) {
+	keys := fixtures.New("tss").Operators(2)
+	shared, other := keys[0], keys[1]
+	digest := crypto.Keccak256Hash([]byte("transaction"))
+
+	wrongKey := func(digest common.Hash) []byte { return signWith(t, other.EcdsaKey, digest) }
+	highS := func(digest common.Hash) []byte { return malleate(signWith(t, shared.EcdsaKey, digest)) }
+	honest := func(digest common.Hash) []byte { return signWith(t, shared.EcdsaKey, digest) }
+
+	// The high-s twin does recover to the shared key, so only the canonical
+	// check turns it away
+	twin := malleate(signWith(t, shared.EcdsaKey, digest))
+	twin[crypto.RecoveryIDOffset] -= 27
+	if publicKey, err := crypto.SigToPub(digest[:], twin); err != nil || crypto.PubkeyToAddress(*publicKey) != shared.Address {
+		t.Fatalf("high-s twin doesn't recover to the shared key: %v", err)
+	}
+
+	// Every party misbehaves alike, as Sign stops at whichever failures
+	// leave the threshold out of reach
+	for _, tc := range []struct {
+		name   string
+		sign   func(common.Hash) []byte
+		reason string
+	}{
+		{"wrong key", wrongKey, ErrWrongSigner.Error()},
+		{"high s", highS, "not canonical"},
+		{"truncated", func(digest common.Hash) []byte { return honest(digest)[:64] }, "64 bytes"},
+	} {
+		signer, err := NewSigner(Config{Parties: []string{party(t, tc.sign), party(t, tc.sign), party(t, tc.sign)}, Threshold: 2, Address: shared.Address})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signature, err := signer.Sign(context.Background(), digest, nil)
+		if !errors.Is(err, ErrNotEnoughParties) || !strings.Contains(err.Error(), tc.reason) {
+			t.Fatalf("%s: got signature %x and error %v, want %v for %q", tc.name, signature, err, ErrNotEnoughParties, tc.reason)
+		}
+	}
+
+	// One party's bad signature doesn't stop the others' canonical one,
+	// which is returned with V of 0 or 1
+	signer, err := NewSigner(Config{Parties: []string{party(t, wrongKey), party(t, honest), party(t, honest)}, Threshold: 2, Address: shared.Address})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := signer.Sign(context.Background(), digest, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := crypto.SigToPub(digest[:], signature)
+	if err != nil || crypto.PubkeyToAddress(*publicKey) != shared.Address {
+		t.Fatalf("signature %x doesn't recover to the shared key: %v", signature, err)
+	}
+}
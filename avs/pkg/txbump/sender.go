@@ -0,0 +1,314 @@
+package txbump
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Defaults applied to unset Config fields
+const (
+	DefaultStuckAfter   = 30 * time.Second
+	DefaultBumpPercent  = 12
+	DefaultMaxBumps     = 5
+	DefaultPollInterval = 2 * time.Second
+)
+
+const (
+	// minBumpPercent is the smallest fee increase nodes accept for a replacement
+	minBumpPercent = 10
+)
+
+var (
+	// ErrFeeCapReached is returned when a stuck transaction can't be bumped
+	// further without exceeding MaxGasPrice
+	ErrFeeCapReached = errors.New("fee cap reached while transaction is stuck")
+
+	// ErrTooManyBumps is returned when a transaction is still stuck after MaxBumps replacements
+	ErrTooManyBumps = errors.New("transaction still stuck after maximum fee bumps")
+
+	// ErrNonceConsumed is returned when the transaction's nonce was used by a
+	// transaction this sender didn't broadcast
+	ErrNonceConsumed = errors.New("nonce consumed by another transaction")
+)
+
+// Client is the subset of an Ethereum client the sender needs
+type Client interface {
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// Config controls when and how far stuck transactions are bumped
+type Config struct {
+	// StuckAfter is how long a transaction may stay pending before it's replaced
+	StuckAfter time.Duration
+	// BumpPercent is the fee increase of each replacement, at least 10
+	BumpPercent int
+	// MaxGasPrice caps the fee cap (or gas price) of replacements; nil disables the cap
+	MaxGasPrice *big.Int
+	MaxBumps    int
+	// PollInterval is how often pending transactions are checked for receipts
+	PollInterval time.Duration
+}
+
+// Sender broadcasts transactions and replaces them with higher fees while they
+// stay pending, so one stuck transaction doesn't hold up everything behind it
+type Sender struct {
+	client Client
+	from   common.Address
+	signer bind.SignerFn
+	config Config
+	logger logging.Logger
+}
+
+func NewSender(client Client, from common.Address, signer bind.SignerFn, config Config, logger logging.Logger) *Sender {
+	if config.StuckAfter <= 0 {
+		config.StuckAfter = DefaultStuckAfter
+	}
+	if config.BumpPercent < minBumpPercent {
+		config.BumpPercent = DefaultBumpPercent
+	}
+	if config.MaxBumps <= 0 {
+		config.MaxBumps = DefaultMaxBumps
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+
+	return &Sender{
+		client: client,
+		from:   from,
+		signer: signer,
+		config: config,
+		logger: logger.With("component", "tx-sender"),
+	}
+}
+
+// TransactOpts returns options that sign contract calls as the sender
+// without broadcasting them, so the signed transaction can be passed to Send
+func (s *Sender) TransactOpts(ctx context.Context) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From:    s.from,
+		Signer:  s.signer,
+		Context: ctx,
+		NoSend:  true,
+	}
+}
+
+// Send broadcasts the signed transaction and waits for it, or one of its
+// replacements, to be mined. onSent, if not nil, is called with the original
+// transaction and every replacement as it is broadcast.
+func (s *Sender) Send(ctx context.Context, tx *types.Transaction, onSent func(*types.Transaction)) (*types.Receipt, error) {
+	if err := s.client.SendTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+	if onSent != nil {
+		onSent(tx)
+	}
+
+	sent := []*types.Transaction{tx}
+	current := tx
+	lastSentAt := time.Now()
+	var nonceConsumedAt time.Time
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		// Any of the broadcast versions may be the one that gets mined
+		for _, candidate := range sent {
+			receipt, err := s.client.TransactionReceipt(ctx, candidate.Hash())
+			if err == nil {
+				if candidate != current {
+					s.logger.Info("Earlier version of replaced transaction was mined", "txHash", candidate.Hash().Hex())
+				}
+				return receipt, nil
+			}
+			if !errors.Is(err, ethereum.NotFound) {
+				s.logger.Warn("Failed to get transaction receipt", "txHash", candidate.Hash().Hex(), "error", err)
+			}
+		}
+
+		// A receipt can lag the nonce by a poll or two; give it StuckAfter before
+		// concluding someone else used the nonce
+		nonce, err := s.client.NonceAt(ctx, s.from, nil)
+		if err != nil {
+			s.logger.Warn("Failed to get account nonce", "error", err)
+			continue
+		}
+		if nonce > tx.Nonce() {
+			if nonceConsumedAt.IsZero() {
+				nonceConsumedAt = time.Now()
+			} else if time.Since(nonceConsumedAt) > s.config.StuckAfter {
+				return nil, fmt.Errorf("%w: nonce %d", ErrNonceConsumed, tx.Nonce())
+			}
+			continue
+		}
+
+		if time.Since(lastSentAt) < s.config.StuckAfter {
+			continue
+		}
+		if len(sent) > s.config.MaxBumps {
+			return nil, fmt.Errorf("%w: %s", ErrTooManyBumps, current.Hash().Hex())
+		}
+
+		replacement, err := s.bump(ctx, current)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.client.SendTransaction(ctx, replacement); err != nil {
+			// The node may already have the replacement, or want a larger bump
+			// than this one; either way keep polling and retry after StuckAfter
+			s.logger.Warn("Failed to send replacement transaction",
+				"txHash", replacement.Hash().Hex(),
+				"nonce", replacement.Nonce(),
+				"error", err,
+			)
+			if !isAlreadyKnown(err) {
+				lastSentAt = time.Now()
+				continue
+			}
+		}
+
+		s.logger.Info("Replaced stuck transaction",
+			"previousTxHash", current.Hash().Hex(),
+			"txHash", replacement.Hash().Hex(),
+			"nonce", replacement.Nonce(),
+			"gasFeeCap", replacement.GasFeeCap().String(),
+			"gasTipCap", replacement.GasTipCap().String(),
+		)
+
+		sent = append(sent, replacement)
+		current = replacement
+		lastSentAt = time.Now()
+		if onSent != nil {
+			onSent(replacement)
+		}
+	}
+}
+
+// bump re-signs tx with the same nonce and fees raised by BumpPercent, or to
+// the current network fees if those are higher
+func (s *Sender) bump(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	var replacement types.TxData
+
+	switch tx.Type() {
+	case types.LegacyTxType:
+		suggested, err := s.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest gas price: %w", err)
+		}
+
+		gasPrice, err := s.capFee(tx.GasPrice(), bigMax(s.increase(tx.GasPrice()), suggested))
+		if err != nil {
+			return nil, err
+		}
+
+		replacement = &types.LegacyTx{
+			Nonce:    tx.Nonce(),
+			GasPrice: gasPrice,
+			Gas:      tx.Gas(),
+			To:       tx.To(),
+			Value:    tx.Value(),
+			Data:     tx.Data(),
+		}
+
+	case types.DynamicFeeTxType:
+		suggestedTip, err := s.client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest gas tip cap: %w", err)
+		}
+		header, err := s.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest header: %w", err)
+		}
+
+		tipCap := bigMax(s.increase(tx.GasTipCap()), suggestedTip)
+		feeCap := s.increase(tx.GasFeeCap())
+		if header.BaseFee != nil {
+			// Leave room for the base fee to keep rising for a few blocks
+			feeCap = bigMax(feeCap, new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), tipCap))
+		}
+
+		feeCap, err = s.capFee(tx.GasFeeCap(), feeCap)
+		if err != nil {
+			return nil, err
+		}
+		if tipCap.Cmp(feeCap) > 0 {
+			tipCap = feeCap
+		}
+
+		replacement = &types.DynamicFeeTx{
+			ChainID:    tx.ChainId(),
+			Nonce:      tx.Nonce(),
+			GasTipCap:  tipCap,
+			GasFeeCap:  feeCap,
+			Gas:        tx.Gas(),
+			To:         tx.To(),
+			Value:      tx.Value(),
+			Data:       tx.Data(),
+			AccessList: tx.AccessList(),
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", tx.Type())
+	}
+
+	signed, err := s.signer(s.from, types.NewTx(replacement))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign replacement transaction: %w", err)
+	}
+	return signed, nil
+}
+
+// capFee limits a bumped fee to MaxGasPrice. The capped fee must still be a
+// valid replacement for the previous one, otherwise the transaction is left stuck.
+func (s *Sender) capFee(previous, bumped *big.Int) (*big.Int, error) {
+	if s.config.MaxGasPrice == nil || bumped.Cmp(s.config.MaxGasPrice) <= 0 {
+		return bumped, nil
+	}
+
+	minReplacement := new(big.Int).Div(new(big.Int).Mul(previous, big.NewInt(100+minBumpPercent)), big.NewInt(100))
+	if s.config.MaxGasPrice.Cmp(minReplacement) < 0 {
+		return nil, fmt.Errorf("%w: %s wei", ErrFeeCapReached, s.config.MaxGasPrice.String())
+	}
+	return new(big.Int).Set(s.config.MaxGasPrice), nil
+}
+
+// increase returns fee raised by BumpPercent, rounded up
+func (s *Sender) increase(fee *big.Int) *big.Int {
+	increased := new(big.Int).Mul(fee, big.NewInt(int64(100+s.config.BumpPercent)))
+	increased.Add(increased, big.NewInt(99))
+	return increased.Div(increased, big.NewInt(100))
+}
+
+func bigMax(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
+
+func isAlreadyKnown(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "already known")
+}
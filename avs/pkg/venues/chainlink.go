@@ -0,0 +1,154 @@
+package venues
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultChainlinkMaxPriceAge is how old an answer may be when
+// ChainlinkConfig.MaxPriceAge is unset. It covers the hour-long heartbeat of
+// the major feeds with some slack.
+const DefaultChainlinkMaxPriceAge = 75 * time.Minute
+
+const chainlinkAggregatorAbi = `[
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"latestRoundData","stateMutability":"view","inputs":[],"outputs":[{"name":"roundId","type":"uint80"},{"name":"answer","type":"int256"},{"name":"startedAt","type":"uint256"},{"name":"updatedAt","type":"uint256"},{"name":"answeredInRound","type":"uint80"}]}
+]`
+
+// ErrAnswerStale is returned when a Chainlink feed's latest answer is older
+// than the maximum age
+var ErrAnswerStale = errors.New("chainlink answer is stale")
+
+// ChainlinkFeed maps an on-chain Chainlink price feed, read through its
+// aggregator proxy, onto a token pair
+type ChainlinkFeed struct {
+	Aggregator    common.Address
+	Base          common.Address
+	BaseDecimals  uint8
+	Quote         common.Address
+	QuoteDecimals uint8
+}
+
+func (f ChainlinkFeed) feedPair() FeedPair {
+	return FeedPair{
+		Base:          f.Base,
+		BaseDecimals:  f.BaseDecimals,
+		Quote:         f.Quote,
+		QuoteDecimals: f.QuoteDecimals,
+	}
+}
+
+// ChainlinkConfig configures the Chainlink venue
+type ChainlinkConfig struct {
+	MaxPriceAge time.Duration
+	Feeds       []ChainlinkFeed
+}
+
+// Chainlink quotes pairs from the latest answers of on-chain Chainlink price
+// feeds. Answers only move by their deviation threshold or heartbeat, so they
+// lag the market; they anchor the reference rather than track it.
+type Chainlink struct {
+	config  ChainlinkConfig
+	backend bind.ContractCaller
+
+	// A feed's decimals never change, so they are read once
+	mu       sync.Mutex
+	decimals map[common.Address]uint8
+}
+
+func NewChainlink(config ChainlinkConfig, backend bind.ContractCaller) *Chainlink {
+	if config.MaxPriceAge <= 0 {
+		config.MaxPriceAge = DefaultChainlinkMaxPriceAge
+	}
+	return &Chainlink{
+		config:   config,
+		backend:  backend,
+		decimals: make(map[common.Address]uint8),
+	}
+}
+
+func (c *Chainlink) Name() string {
+	return "chainlink"
+}
+
+// Quotes returns one quote per configured feed for the pair. A stale answer
+// fails the whole venue, like the other feeds.
+func (c *Chainlink) Quotes(ctx context.Context, pair Pair) ([]Quote, error) {
+	var quotes []Quote
+	for _, feed := range c.config.Feeds {
+		feedPair := feed.feedPair()
+		if !feedPair.lists(pair) {
+			continue
+		}
+
+		quote, err := c.quoteFeed(ctx, pair, feed, feedPair)
+		if err != nil {
+			return nil, fmt.Errorf("feed %s: %w", feed.Aggregator.Hex(), err)
+		}
+		quotes = append(quotes, quote)
+	}
+	if len(quotes) == 0 {
+		return nil, ErrPairNotListed
+	}
+	return quotes, nil
+}
+
+func (c *Chainlink) quoteFeed(ctx context.Context, pair Pair, feed ChainlinkFeed, feedPair FeedPair) (Quote, error) {
+	aggregator, err := bindContract(feed.Aggregator, chainlinkAggregatorAbi, c.backend)
+	if err != nil {
+		return Quote{}, err
+	}
+	decimals, err := c.feedDecimals(ctx, feed.Aggregator, aggregator)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	out, err := aggregator.call(ctx, "latestRoundData")
+	if err != nil {
+		return Quote{}, err
+	}
+	answer := *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	updatedAt := time.Unix((*abi.ConvertType(out[3], new(*big.Int)).(**big.Int)).Int64(), 0)
+	if age := time.Since(updatedAt); age > c.config.MaxPriceAge {
+		return Quote{}, fmt.Errorf("%w: updated %s ago", ErrAnswerStale, age.Truncate(time.Second))
+	}
+
+	price := feedPair.venuePrice(pair, answer, decimals)
+	if price == nil {
+		return Quote{}, fmt.Errorf("non-positive answer %s", answer)
+	}
+	return Quote{
+		Venue:     c.Name(),
+		Pool:      feed.Aggregator,
+		Price:     price,
+		SampledAt: updatedAt,
+	}, nil
+}
+
+func (c *Chainlink) feedDecimals(ctx context.Context, address common.Address, aggregator *boundContract) (uint8, error) {
+	c.mu.Lock()
+	decimals, ok := c.decimals[address]
+	c.mu.Unlock()
+	if ok {
+		return decimals, nil
+	}
+
+	out, err := aggregator.call(ctx, "decimals")
+	if err != nil {
+		return 0, err
+	}
+	decimals = *abi.ConvertType(out[0], new(uint8)).(*uint8)
+
+	c.mu.Lock()
+	c.decimals[address] = decimals
+	c.mu.Unlock()
+	return decimals, nil
+}
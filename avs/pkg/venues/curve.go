@@ -0,0 +1,173 @@
+package venues
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Stableswap pools index coins with int128, cryptoswap pools with uint256
+const curveStableswapAbi = `[
+	{"type":"function","name":"coins","stateMutability":"view","inputs":[{"name":"i","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"get_dy","stateMutability":"view","inputs":[{"name":"i","type":"int128"},{"name":"j","type":"int128"},{"name":"dx","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+const curveCryptoswapAbi = `[
+	{"type":"function","name":"coins","stateMutability":"view","inputs":[{"name":"i","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"get_dy","stateMutability":"view","inputs":[{"name":"i","type":"uint256"},{"name":"j","type":"uint256"},{"name":"dx","type":"uint256"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+const erc20DecimalsAbi = `[
+	{"type":"function","name":"decimals","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint8"}]}
+]`
+
+// maxCurveCoins is the largest number of coins a Curve pool holds
+const maxCurveCoins = 8
+
+// CurvePool configures a Curve pool to sample
+type CurvePool struct {
+	Address common.Address
+	// Cryptoswap pools take uint256 coin indices in get_dy
+	Cryptoswap bool
+}
+
+// Curve quotes configured Curve pools by simulating a swap of one whole
+// Token0 through get_dy
+type Curve struct {
+	pools   []CurvePool
+	backend bind.ContractCaller
+
+	mu       sync.Mutex
+	coins    map[common.Address][]common.Address
+	decimals map[common.Address]uint8
+}
+
+func NewCurve(pools []CurvePool, backend bind.ContractCaller) *Curve {
+	return &Curve{
+		pools:    pools,
+		backend:  backend,
+		coins:    make(map[common.Address][]common.Address),
+		decimals: make(map[common.Address]uint8),
+	}
+}
+
+func (c *Curve) Name() string {
+	return "curve"
+}
+
+// Quotes returns a quote from every configured pool holding both tokens
+func (c *Curve) Quotes(ctx context.Context, pair Pair) ([]Quote, error) {
+	var quotes []Quote
+	for _, pool := range c.pools {
+		contractAbi := curveStableswapAbi
+		if pool.Cryptoswap {
+			contractAbi = curveCryptoswapAbi
+		}
+		bound, err := bindContract(pool.Address, contractAbi, c.backend)
+		if err != nil {
+			return nil, err
+		}
+
+		coins, err := c.poolCoins(ctx, pool.Address, bound)
+		if err != nil {
+			return nil, fmt.Errorf("pool %s: %w", pool.Address.Hex(), err)
+		}
+		i, j := indexOf(coins, pair.Token0), indexOf(coins, pair.Token1)
+		if i < 0 || j < 0 {
+			continue
+		}
+
+		decimals, err := c.tokenDecimals(ctx, pair.Token0)
+		if err != nil {
+			return nil, err
+		}
+		dx := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+
+		out, err := bound.call(ctx, "get_dy", big.NewInt(int64(i)), big.NewInt(int64(j)), dx)
+		if err != nil {
+			return nil, fmt.Errorf("pool %s: %w", pool.Address.Hex(), err)
+		}
+		dy := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+		price := new(big.Int).Mul(dy, PriceScale)
+		quotes = append(quotes, Quote{
+			Venue:     c.Name(),
+			Pool:      pool.Address,
+			Price:     price.Quo(price, dx),
+			SampledAt: time.Now(),
+		})
+	}
+
+	if len(quotes) == 0 {
+		return nil, ErrPairNotListed
+	}
+	return quotes, nil
+}
+
+// poolCoins reads and caches the pool's coin list. Curve pools revert on
+// coins(i) past the last coin, which ends the scan.
+func (c *Curve) poolCoins(ctx context.Context, poolAddr common.Address, pool *boundContract) ([]common.Address, error) {
+	c.mu.Lock()
+	coins, ok := c.coins[poolAddr]
+	c.mu.Unlock()
+	if ok {
+		return coins, nil
+	}
+
+	for i := 0; i < maxCurveCoins; i++ {
+		out, err := pool.call(ctx, "coins", big.NewInt(int64(i)))
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			break
+		}
+		coins = append(coins, *abi.ConvertType(out[0], new(common.Address)).(*common.Address))
+	}
+
+	c.mu.Lock()
+	c.coins[poolAddr] = coins
+	c.mu.Unlock()
+
+	return coins, nil
+}
+
+func (c *Curve) tokenDecimals(ctx context.Context, token common.Address) (uint8, error) {
+	c.mu.Lock()
+	decimals, ok := c.decimals[token]
+	c.mu.Unlock()
+	if ok {
+		return decimals, nil
+	}
+
+	erc20, err := bindContract(token, erc20DecimalsAbi, c.backend)
+	if err != nil {
+		return 0, err
+	}
+	out, err := erc20.call(ctx, "decimals")
+	if err != nil {
+		return 0, fmt.Errorf("token %s: %w", token.Hex(), err)
+	}
+	decimals = *abi.ConvertType(out[0], new(uint8)).(*uint8)
+
+	c.mu.Lock()
+	c.decimals[token] = decimals
+	c.mu.Unlock()
+
+	return decimals, nil
+}
+
+func indexOf(coins []common.Address, token common.Address) int {
+	for i, coin := range coins {
+		if coin == token {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,314 @@
+package venues
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// DataStreamsLatestReportPath is the Data Streams REST endpoint serving
+	// the latest report of a feed
+	DataStreamsLatestReportPath = "/api/v1/reports/latest"
+
+	// DefaultDataStreamsMaxReportAge is how old a report's observation may be
+	// when DataStreamsConfig.MaxReportAge is unset
+	DefaultDataStreamsMaxReportAge = 30 * time.Second
+
+	// dataStreamsPriceDecimals is the fixed-point precision of v3 report prices
+	dataStreamsPriceDecimals = 18
+
+	// dataStreamsRequestTimeout bounds a single report request
+	dataStreamsRequestTimeout = 5 * time.Second
+)
+
+var (
+	// ErrReportUnverified is returned when a report lacks enough valid
+	// signatures from the configured DON signers
+	ErrReportUnverified = errors.New("data streams report not verified")
+
+	// ErrReportStale is returned when a report has expired or its observation
+	// is older than the maximum report age
+	ErrReportStale = errors.New("data streams report is stale")
+)
+
+var (
+	bytes32Type, _    = abi.NewType("bytes32", "", nil)
+	bytes32x3Type, _  = abi.NewType("bytes32[3]", "", nil)
+	bytes32ArrType, _ = abi.NewType("bytes32[]", "", nil)
+	bytesType, _      = abi.NewType("bytes", "", nil)
+	uint32Type, _     = abi.NewType("uint32", "", nil)
+	uint192Type, _    = abi.NewType("uint192", "", nil)
+	int192Type, _     = abi.NewType("int192", "", nil)
+
+	// dataStreamsFullReport is the signed envelope around a report
+	dataStreamsFullReport = abi.Arguments{
+		{Name: "reportContext", Type: bytes32x3Type},
+		{Name: "reportBlob", Type: bytesType},
+		{Name: "rawRs", Type: bytes32ArrType},
+		{Name: "rawSs", Type: bytes32ArrType},
+		{Name: "rawVs", Type: bytes32Type},
+	}
+
+	// dataStreamsReportV3 is the v3 (crypto) report schema
+	dataStreamsReportV3 = abi.Arguments{
+		{Name: "feedId", Type: bytes32Type},
+		{Name: "validFromTimestamp", Type: uint32Type},
+		{Name: "observationsTimestamp", Type: uint32Type},
+		{Name: "nativeFee", Type: uint192Type},
+		{Name: "linkFee", Type: uint192Type},
+		{Name: "expiresAt", Type: uint32Type},
+		{Name: "benchmarkPrice", Type: int192Type},
+		{Name: "bid", Type: int192Type},
+		{Name: "ask", Type: int192Type},
+	}
+)
+
+// DataStreamsConfig configures the Chainlink Data Streams venue
+type DataStreamsConfig struct {
+	// ApiUrl is the Data Streams REST API, e.g. https://api.dataengine.chain.link
+	ApiUrl       string
+	ClientId     string
+	ClientSecret string
+	// Signers are the DON's report signing addresses. A report is only used
+	// once MinSigners distinct signers among them have signed it.
+	Signers      []common.Address
+	MinSigners   int
+	MaxReportAge time.Duration
+	Feeds        []FeedPair
+}
+
+// DataStreams quotes pairs from Chainlink Data Streams reports. Every report
+// is verified off-chain the way the Data Streams verifier contract does it,
+// so a compromised API endpoint can't inject prices.
+type DataStreams struct {
+	config  DataStreamsConfig
+	signers map[common.Address]struct{}
+	client  *http.Client
+}
+
+// DataStreamsReport is a verified v3 report
+type DataStreamsReport struct {
+	FeedId         common.Hash
+	ObservedAt     time.Time
+	ExpiresAt      time.Time
+	BenchmarkPrice *big.Int
+	Bid            *big.Int
+	Ask            *big.Int
+}
+
+func NewDataStreams(config DataStreamsConfig) (*DataStreams, error) {
+	if config.ApiUrl == "" {
+		return nil, errors.New("data streams api url is required")
+	}
+	if config.ClientId == "" || config.ClientSecret == "" {
+		return nil, errors.New("data streams client id and secret are required")
+	}
+	if len(config.Signers) == 0 {
+		return nil, errors.New("data streams reports can't be verified without signers")
+	}
+	if config.MinSigners <= 0 || config.MinSigners > len(config.Signers) {
+		return nil, fmt.Errorf("data streams min signers must be between 1 and %d, got %d", len(config.Signers), config.MinSigners)
+	}
+	if config.MaxReportAge <= 0 {
+		config.MaxReportAge = DefaultDataStreamsMaxReportAge
+	}
+
+	signers := make(map[common.Address]struct{}, len(config.Signers))
+	for _, signer := range config.Signers {
+		signers[signer] = struct{}{}
+	}
+
+	return &DataStreams{
+		config:  config,
+		signers: signers,
+		client:  &http.Client{Timeout: dataStreamsRequestTimeout},
+	}, nil
+}
+
+func (d *DataStreams) Name() string {
+	return "chainlink-data-streams"
+}
+
+// Quotes returns one quote per configured feed for the pair, priced at the
+// report's benchmark price
+func (d *DataStreams) Quotes(ctx context.Context, pair Pair) ([]Quote, error) {
+	var quotes []Quote
+	for _, feed := range d.config.Feeds {
+		if !feed.lists(pair) {
+			continue
+		}
+
+		report, err := d.LatestReport(ctx, feed.FeedId)
+		if err != nil {
+			return nil, fmt.Errorf("feed %s: %w", feed.FeedId.Hex(), err)
+		}
+
+		price := feed.venuePrice(pair, report.BenchmarkPrice, dataStreamsPriceDecimals)
+		if price == nil {
+			return nil, fmt.Errorf("feed %s: non-positive benchmark price %s", feed.FeedId.Hex(), report.BenchmarkPrice)
+		}
+		quotes = append(quotes, Quote{
+			Venue:     d.Name(),
+			Price:     price,
+			SampledAt: report.ObservedAt,
+		})
+	}
+
+	if len(quotes) == 0 {
+		return nil, ErrPairNotListed
+	}
+	return quotes, nil
+}
+
+// LatestReport fetches, verifies and decodes the latest report of a feed
+func (d *DataStreams) LatestReport(ctx context.Context, feedId common.Hash) (DataStreamsReport, error) {
+	fullReport, err := d.fetchLatest(ctx, feedId)
+	if err != nil {
+		return DataStreamsReport{}, err
+	}
+
+	reportBlob, err := d.verify(fullReport)
+	if err != nil {
+		return DataStreamsReport{}, err
+	}
+
+	report, err := decodeDataStreamsReport(reportBlob)
+	if err != nil {
+		return DataStreamsReport{}, err
+	}
+	if report.FeedId != feedId {
+		return DataStreamsReport{}, fmt.Errorf("got a report for feed %s", report.FeedId.Hex())
+	}
+
+	now := time.Now()
+	if now.After(report.ExpiresAt) || now.Sub(report.ObservedAt) > d.config.MaxReportAge {
+		return DataStreamsReport{}, fmt.Errorf("%w: observed at %s", ErrReportStale, report.ObservedAt.UTC().Format(time.RFC3339))
+	}
+	return report, nil
+}
+
+// fetchLatest requests the feed's latest full report, authenticated with the
+// HMAC scheme of the Data Streams API
+func (d *DataStreams) fetchLatest(ctx context.Context, feedId common.Hash) ([]byte, error) {
+	path := DataStreamsLatestReportPath + "?" + url.Values{"feedID": {feedId.Hex()}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(d.config.ApiUrl, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	bodyHash := sha256.Sum256(nil)
+	mac := hmac.New(sha256.New, []byte(d.config.ClientSecret))
+	fmt.Fprintf(mac, "%s %s %s %s %s", http.MethodGet, path, hex.EncodeToString(bodyHash[:]), d.config.ClientId, timestamp)
+
+	req.Header.Set("Authorization", d.config.ClientId)
+	req.Header.Set("X-Authorization-Timestamp", timestamp)
+	req.Header.Set("X-Authorization-Signature-SHA256", hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request report: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("report request returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Report struct {
+			FullReport hexutil.Bytes `json:"fullReport"`
+		} `json:"report"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode report: %w", err)
+	}
+	return payload.Report.FullReport, nil
+}
+
+// verify checks the DON signatures on a full report and returns the signed
+// report blob. Each signature is over
+// keccak256(keccak256(reportBlob) || reportContext), with its recovery id in
+// the matching byte of rawVs.
+func (d *DataStreams) verify(fullReport []byte) ([]byte, error) {
+	values, err := dataStreamsFullReport.Unpack(fullReport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode full report: %w", err)
+	}
+	reportContext := values[0].([3][32]byte)
+	reportBlob := values[1].([]byte)
+	rawRs := values[2].([][32]byte)
+	rawSs := values[3].([][32]byte)
+	rawVs := values[4].([32]byte)
+
+	if len(rawRs) != len(rawSs) || len(rawRs) > len(rawVs) {
+		return nil, fmt.Errorf("%w: malformed signatures", ErrReportUnverified)
+	}
+
+	digest := crypto.Keccak256(crypto.Keccak256(reportBlob), reportContext[0][:], reportContext[1][:], reportContext[2][:])
+
+	signedBy := make(map[common.Address]struct{}, len(rawRs))
+	for i := range rawRs {
+		signature := make([]byte, 65)
+		copy(signature[:32], rawRs[i][:])
+		copy(signature[32:64], rawSs[i][:])
+		signature[64] = rawVs[i]
+
+		pubkey, err := crypto.SigToPub(digest, signature)
+		if err != nil {
+			continue
+		}
+		signer := crypto.PubkeyToAddress(*pubkey)
+		if _, ok := d.signers[signer]; ok {
+			signedBy[signer] = struct{}{}
+		}
+	}
+
+	if len(signedBy) < d.config.MinSigners {
+		return nil, fmt.Errorf("%w: %d of %d required signers", ErrReportUnverified, len(signedBy), d.config.MinSigners)
+	}
+	return reportBlob, nil
+}
+
+// decodeDataStreamsReport decodes a report blob. The schema version is the
+// first two bytes of the feed id, and only v3 reports carry a bid and ask.
+func decodeDataStreamsReport(reportBlob []byte) (DataStreamsReport, error) {
+	if len(reportBlob) < 32 {
+		return DataStreamsReport{}, errors.New("report blob too short")
+	}
+	if version := binary.BigEndian.Uint16(reportBlob[:2]); version != 3 {
+		return DataStreamsReport{}, fmt.Errorf("unsupported report schema v%d", version)
+	}
+
+	values, err := dataStreamsReportV3.Unpack(reportBlob)
+	if err != nil {
+		return DataStreamsReport{}, fmt.Errorf("failed to decode report: %w", err)
+	}
+	return DataStreamsReport{
+		FeedId:         common.Hash(values[0].([32]byte)),
+		ObservedAt:     time.Unix(int64(values[2].(uint32)), 0),
+		ExpiresAt:      time.Unix(int64(values[5].(uint32)), 0),
+		BenchmarkPrice: values[6].(*big.Int),
+		Bid:            values[7].(*big.Int),
+		Ask:            values[8].(*big.Int),
+	}, nil
+}
@@ -0,0 +1,337 @@
+package venues
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	ExchangeBinance  = "binance"
+	ExchangeCoinbase = "coinbase"
+
+	// DefaultBinanceUrl and DefaultCoinbaseUrl are the public market data
+	// websockets used when ExchangeConfig.Url is unset
+	DefaultBinanceUrl  = "wss://stream.binance.com:9443"
+	DefaultCoinbaseUrl = "wss://ws-feed.exchange.coinbase.com"
+
+	// DefaultExchangeMaxTickAge is how old a market's best bid and ask may be
+	// when ExchangeConfig.MaxTickAge is unset
+	DefaultExchangeMaxTickAge = 10 * time.Second
+
+	// Reconnect backoff for the exchange websocket
+	exchangeMinBackoff = time.Second
+	exchangeMaxBackoff = 30 * time.Second
+
+	// exchangeReadTimeout is how long the websocket may stay silent before
+	// the connection is assumed dead
+	exchangeReadTimeout = time.Minute
+	// exchangeMaxMessageBytes bounds a single frame
+	exchangeMaxMessageBytes = 1 << 20
+)
+
+// ErrTickStale is returned when a market has no best bid and ask, or they
+// are older than the maximum age
+var ErrTickStale = errors.New("exchange ticker is stale")
+
+// ExchangeMarket maps an exchange market, e.g. ETHUSDC on Binance or ETH-USD
+// on Coinbase, onto the tokens standing in for its base and quote assets
+type ExchangeMarket struct {
+	Symbol        string
+	Base          common.Address
+	BaseDecimals  uint8
+	Quote         common.Address
+	QuoteDecimals uint8
+}
+
+func (m ExchangeMarket) feedPair() FeedPair {
+	return FeedPair{
+		Base:          m.Base,
+		BaseDecimals:  m.BaseDecimals,
+		Quote:         m.Quote,
+		QuoteDecimals: m.QuoteDecimals,
+	}
+}
+
+// ExchangeConfig configures an Exchange venue
+type ExchangeConfig struct {
+	// Kind is ExchangeBinance or ExchangeCoinbase
+	Kind       string
+	Url        string
+	MaxTickAge time.Duration
+	Markets    []ExchangeMarket
+}
+
+// Exchange quotes pairs from the best bid and ask of centralized exchange
+// markets, streamed over the exchange's public websocket. CEX prices lead
+// on-chain pools, so the gap between them is the LVR an arbitrageur captures.
+// Run must be running for the venue to have quotes.
+type Exchange struct {
+	config ExchangeConfig
+	logger logging.Logger
+
+	mu    sync.RWMutex
+	ticks map[string]exchangeTick
+}
+
+// exchangeTick is a market's best bid and ask, as exact decimals
+type exchangeTick struct {
+	bid        *big.Rat
+	ask        *big.Rat
+	receivedAt time.Time
+}
+
+func NewExchange(config ExchangeConfig, logger logging.Logger) (*Exchange, error) {
+	switch config.Kind {
+	case ExchangeBinance:
+		if config.Url == "" {
+			config.Url = DefaultBinanceUrl
+		}
+	case ExchangeCoinbase:
+		if config.Url == "" {
+			config.Url = DefaultCoinbaseUrl
+		}
+	default:
+		return nil, fmt.Errorf("unknown exchange %q", config.Kind)
+	}
+	if !strings.HasPrefix(config.Url, "ws://") && !strings.HasPrefix(config.Url, "wss://") {
+		return nil, fmt.Errorf("exchange url must be a websocket url: %q", config.Url)
+	}
+	if len(config.Markets) == 0 {
+		return nil, errors.New("exchange has no markets")
+	}
+	if config.MaxTickAge <= 0 {
+		config.MaxTickAge = DefaultExchangeMaxTickAge
+	}
+
+	return &Exchange{
+		config: config,
+		logger: logger.With("component", "exchange-venue", "exchange", config.Kind),
+		ticks:  make(map[string]exchangeTick),
+	}, nil
+}
+
+func (e *Exchange) Name() string {
+	return e.config.Kind
+}
+
+// Quotes returns the mid price of each configured market for the pair. The
+// half-spread is reported as the quote's confidence.
+func (e *Exchange) Quotes(ctx context.Context, pair Pair) ([]Quote, error) {
+	var quotes []Quote
+	for _, market := range e.config.Markets {
+		feedPair := market.feedPair()
+		if !feedPair.lists(pair) {
+			continue
+		}
+
+		e.mu.RLock()
+		tick, ok := e.ticks[market.Symbol]
+		e.mu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("market %s: %w: no ticker received", market.Symbol, ErrTickStale)
+		}
+		if age := time.Since(tick.receivedAt); age > e.config.MaxTickAge {
+			return nil, fmt.Errorf("market %s: %w: received %s ago", market.Symbol, ErrTickStale, age.Truncate(time.Second))
+		}
+
+		mid := new(big.Rat).Add(tick.bid, tick.ask)
+		mid.Quo(mid, big.NewRat(2, 1))
+		price := feedPair.venuePrice(pair, ratToFixed(mid), 18)
+		if price == nil {
+			return nil, fmt.Errorf("market %s: non-positive mid price", market.Symbol)
+		}
+
+		var confidenceBps int64
+		if mid.Sign() > 0 {
+			halfSpread := new(big.Rat).Sub(tick.ask, tick.bid)
+			halfSpread.Quo(halfSpread, big.NewRat(2, 1))
+			halfSpread.Mul(halfSpread, big.NewRat(10_000, 1))
+			confidenceBps = ratToInt(halfSpread.Quo(halfSpread, mid)).Int64()
+		}
+		quotes = append(quotes, Quote{
+			Venue:         e.Name(),
+			Price:         price,
+			ConfidenceBps: confidenceBps,
+			SampledAt:     tick.receivedAt,
+		})
+	}
+	if len(quotes) == 0 {
+		return nil, ErrPairNotListed
+	}
+	return quotes, nil
+}
+
+// Run follows the exchange's websocket until ctx is done, reconnecting with
+// exponential backoff whenever the connection drops
+func (e *Exchange) Run(ctx context.Context) {
+	backoff := exchangeMinBackoff
+
+	for {
+		connectedAt := time.Now()
+		err := e.follow(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// A connection that stayed up for a while resets the backoff
+		if time.Since(connectedAt) > exchangeMaxBackoff {
+			backoff = exchangeMinBackoff
+		}
+		e.logger.Warn("Exchange websocket disconnected", "error", err, "retryIn", backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, exchangeMaxBackoff)
+	}
+}
+
+// follow connects, subscribes and records tickers until the connection fails
+func (e *Exchange) follow(ctx context.Context) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, e.streamUrl(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to dial exchange: %w", err)
+	}
+	defer conn.Close()
+	conn.SetReadLimit(exchangeMaxMessageBytes)
+
+	if e.config.Kind == ExchangeCoinbase {
+		if err := conn.WriteJSON(e.coinbaseSubscription()); err != nil {
+			return fmt.Errorf("failed to subscribe to tickers: %w", err)
+		}
+	}
+	e.logger.Info("Connected to exchange websocket", "url", e.config.Url)
+
+	// Unblock the read below once ctx is done
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(exchangeReadTimeout))
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("failed to read from exchange: %w", err)
+		}
+
+		switch e.config.Kind {
+		case ExchangeBinance:
+			err = e.handleBinance(data)
+		case ExchangeCoinbase:
+			err = e.handleCoinbase(data)
+		}
+		if err != nil {
+			e.logger.Debug("Skipping undecodable exchange frame", "error", err)
+		}
+	}
+}
+
+// streamUrl is the url to dial. Binance takes its subscriptions in the url
+// as a combined stream; Coinbase takes a subscribe message.
+func (e *Exchange) streamUrl() string {
+	if e.config.Kind != ExchangeBinance {
+		return e.config.Url
+	}
+	streams := make([]string, len(e.config.Markets))
+	for i, market := range e.config.Markets {
+		streams[i] = strings.ToLower(market.Symbol) + "@bookTicker"
+	}
+	return strings.TrimRight(e.config.Url, "/") + "/stream?streams=" + strings.Join(streams, "/")
+}
+
+// binanceBookTicker is a combined stream frame of a market's best bid and ask
+type binanceBookTicker struct {
+	Data struct {
+		Symbol string `json:"s"`
+		Bid    string `json:"b"`
+		Ask    string `json:"a"`
+	} `json:"data"`
+}
+
+func (e *Exchange) handleBinance(data []byte) error {
+	var frame binanceBookTicker
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return err
+	}
+	return e.record(frame.Data.Symbol, frame.Data.Bid, frame.Data.Ask)
+}
+
+// coinbaseTicker is a ticker channel message; subscription acks and other
+// message types are ignored
+type coinbaseTicker struct {
+	Type      string `json:"type"`
+	ProductId string `json:"product_id"`
+	BestBid   string `json:"best_bid"`
+	BestAsk   string `json:"best_ask"`
+}
+
+func (e *Exchange) coinbaseSubscription() interface{} {
+	productIds := make([]string, len(e.config.Markets))
+	for i, market := range e.config.Markets {
+		productIds[i] = market.Symbol
+	}
+	return map[string]interface{}{
+		"type":        "subscribe",
+		"product_ids": productIds,
+		"channels":    []string{"ticker"},
+	}
+}
+
+func (e *Exchange) handleCoinbase(data []byte) error {
+	var message coinbaseTicker
+	if err := json.Unmarshal(data, &message); err != nil {
+		return err
+	}
+	if message.Type != "ticker" {
+		return nil
+	}
+	return e.record(message.ProductId, message.BestBid, message.BestAsk)
+}
+
+// record stores a market's best bid and ask. Symbols are matched case
+// insensitively, since Binance streams are lowercase but report uppercase.
+func (e *Exchange) record(symbol, bid, ask string) error {
+	bidRat, ok := new(big.Rat).SetString(bid)
+	if !ok {
+		return fmt.Errorf("market %s: invalid bid %q", symbol, bid)
+	}
+	askRat, ok := new(big.Rat).SetString(ask)
+	if !ok {
+		return fmt.Errorf("market %s: invalid ask %q", symbol, ask)
+	}
+	if bidRat.Sign() <= 0 || askRat.Cmp(bidRat) < 0 {
+		return fmt.Errorf("market %s: crossed or empty book %s/%s", symbol, bid, ask)
+	}
+
+	for _, market := range e.config.Markets {
+		if !strings.EqualFold(market.Symbol, symbol) {
+			continue
+		}
+		e.mu.Lock()
+		e.ticks[market.Symbol] = exchangeTick{bid: bidRat, ask: askRat, receivedAt: time.Now()}
+		e.mu.Unlock()
+		return nil
+	}
+	return nil
+}
+
+// ratToFixed returns r with 18 decimals, rounded down
+func ratToFixed(r *big.Rat) *big.Int {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(PriceScale))
+	return ratToInt(scaled)
+}
+
+func ratToInt(r *big.Rat) *big.Int {
+	return new(big.Int).Quo(r.Num(), r.Denom())
+}
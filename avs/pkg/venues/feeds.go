@@ -0,0 +1,47 @@
+package venues
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FeedPair maps an oracle feed onto a token pair. Feeds price one whole Base
+// unit in whole Quote units, so a feed such as ETH/USD is mapped onto the
+// tokens standing in for each side, e.g. WETH and USDC.
+type FeedPair struct {
+	FeedId        common.Hash
+	Base          common.Address
+	BaseDecimals  uint8
+	Quote         common.Address
+	QuoteDecimals uint8
+}
+
+// lists reports whether the feed prices the pair, in either direction
+func (f FeedPair) lists(pair Pair) bool {
+	return NewPair(f.Base, f.Quote) == pair
+}
+
+// venuePrice converts a feed price with the given number of decimals into
+// the Quote convention: Token1 base units per Token0 base unit, scaled by
+// PriceScale. It returns nil for a non-positive price.
+func (f FeedPair) venuePrice(pair Pair, price *big.Int, decimals uint8) *big.Int {
+	if price == nil || price.Sign() <= 0 {
+		return nil
+	}
+
+	// Quote base units per Base base unit is price * 10^quoteDecimals /
+	// (10^decimals * 10^baseDecimals)
+	numerator := new(big.Int).Mul(price, pow10(f.QuoteDecimals))
+	denominator := new(big.Int).Mul(pow10(decimals), pow10(f.BaseDecimals))
+	if pair.Token0 != f.Base {
+		numerator, denominator = denominator, numerator
+	}
+
+	numerator.Mul(numerator, PriceScale)
+	return numerator.Quo(numerator, denominator)
+}
+
+func pow10(exponent uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
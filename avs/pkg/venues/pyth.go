@@ -0,0 +1,228 @@
+package venues
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// PythLatestPricePath is the Hermes endpoint serving the latest price updates
+	PythLatestPricePath = "/v2/updates/price/latest"
+
+	// DefaultPythMaxPriceAge is how old a price may be when PythConfig.MaxPriceAge is unset
+	DefaultPythMaxPriceAge = time.Minute
+
+	// DefaultPythMaxConfidenceBps is the widest confidence interval accepted,
+	// relative to the price, when PythConfig.MaxConfidenceBps is unset
+	DefaultPythMaxConfidenceBps = 100
+
+	// pythRequestTimeout bounds a single Hermes request
+	pythRequestTimeout = 5 * time.Second
+)
+
+var (
+	// ErrPriceStale is returned when a feed's price is older than the maximum age
+	ErrPriceStale = errors.New("pyth price is stale")
+
+	// ErrConfidenceTooWide is returned when a feed's confidence interval is
+	// too wide relative to its price for the price to be useful
+	ErrConfidenceTooWide = errors.New("pyth confidence interval too wide")
+)
+
+// PythConfig configures the Pyth venue
+type PythConfig struct {
+	// HermesUrl is the Hermes price service, e.g. https://hermes.pyth.network
+	HermesUrl        string
+	MaxPriceAge      time.Duration
+	MaxConfidenceBps int64
+	Feeds            []FeedPair
+}
+
+// Pyth quotes pairs from Pyth prices served by Hermes, which covers long-tail
+// assets that on-chain venues and other feeds price poorly
+type Pyth struct {
+	config PythConfig
+	client *http.Client
+}
+
+// PythPrice is a feed's latest price. The price and confidence interval are
+// both scaled by 10^Expo.
+type PythPrice struct {
+	FeedId      common.Hash
+	Price       *big.Int
+	Confidence  *big.Int
+	Expo        int32
+	PublishedAt time.Time
+}
+
+// ConfidenceBps returns the confidence interval relative to the price, in
+// basis points
+func (p PythPrice) ConfidenceBps() int64 {
+	if p.Price.Sign() <= 0 {
+		return 0
+	}
+	bps := new(big.Int).Mul(p.Confidence, big.NewInt(10_000))
+	return bps.Quo(bps, p.Price).Int64()
+}
+
+func NewPyth(config PythConfig) (*Pyth, error) {
+	if config.HermesUrl == "" {
+		return nil, errors.New("pyth hermes url is required")
+	}
+	if config.MaxPriceAge <= 0 {
+		config.MaxPriceAge = DefaultPythMaxPriceAge
+	}
+	if config.MaxConfidenceBps <= 0 {
+		config.MaxConfidenceBps = DefaultPythMaxConfidenceBps
+	}
+
+	return &Pyth{
+		config: config,
+		client: &http.Client{Timeout: pythRequestTimeout},
+	}, nil
+}
+
+func (p *Pyth) Name() string {
+	return "pyth"
+}
+
+// Quotes returns one quote per configured feed for the pair. Prices that are
+// stale or too uncertain fail the whole venue rather than being skipped, so
+// the failure is logged by the sampler.
+func (p *Pyth) Quotes(ctx context.Context, pair Pair) ([]Quote, error) {
+	var feeds []FeedPair
+	for _, feed := range p.config.Feeds {
+		if feed.lists(pair) {
+			feeds = append(feeds, feed)
+		}
+	}
+	if len(feeds) == 0 {
+		return nil, ErrPairNotListed
+	}
+
+	ids := make([]common.Hash, len(feeds))
+	for i, feed := range feeds {
+		ids[i] = feed.FeedId
+	}
+	prices, err := p.LatestPrices(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make([]Quote, 0, len(feeds))
+	for _, feed := range feeds {
+		price, ok := prices[feed.FeedId]
+		if !ok {
+			return nil, fmt.Errorf("feed %s: no price returned", feed.FeedId.Hex())
+		}
+		if err := p.check(price); err != nil {
+			return nil, fmt.Errorf("feed %s: %w", feed.FeedId.Hex(), err)
+		}
+
+		// Positive exponents are folded into the price so it has whole decimals
+		value, decimals := new(big.Int).Set(price.Price), uint8(0)
+		if price.Expo < 0 {
+			decimals = uint8(-price.Expo)
+		} else {
+			value.Mul(value, pow10(uint8(price.Expo)))
+		}
+
+		venuePrice := feed.venuePrice(pair, value, decimals)
+		if venuePrice == nil {
+			return nil, fmt.Errorf("feed %s: non-positive price %s", feed.FeedId.Hex(), price.Price)
+		}
+		quotes = append(quotes, Quote{
+			Venue:         p.Name(),
+			Price:         venuePrice,
+			ConfidenceBps: price.ConfidenceBps(),
+			SampledAt:     price.PublishedAt,
+		})
+	}
+	return quotes, nil
+}
+
+// check rejects prices that are stale or whose confidence interval is wider
+// than allowed
+func (p *Pyth) check(price PythPrice) error {
+	if age := time.Since(price.PublishedAt); age > p.config.MaxPriceAge {
+		return fmt.Errorf("%w: published %s ago", ErrPriceStale, age.Truncate(time.Second))
+	}
+	if price.Price.Sign() <= 0 {
+		return nil
+	}
+	if confidenceBps := price.ConfidenceBps(); confidenceBps > p.config.MaxConfidenceBps {
+		return fmt.Errorf("%w: %d bps, at most %d allowed", ErrConfidenceTooWide, confidenceBps, p.config.MaxConfidenceBps)
+	}
+	return nil
+}
+
+// LatestPrices fetches the latest price of each feed in a single request
+func (p *Pyth) LatestPrices(ctx context.Context, feedIds []common.Hash) (map[common.Hash]PythPrice, error) {
+	query := url.Values{"parsed": {"true"}}
+	for _, feedId := range feedIds {
+		query.Add("ids[]", feedId.Hex())
+	}
+	endpoint := strings.TrimRight(p.config.HermesUrl, "/") + PythLatestPricePath + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request prices: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("price request returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Parsed []struct {
+			Id    string `json:"id"`
+			Price struct {
+				Price       string `json:"price"`
+				Conf        string `json:"conf"`
+				Expo        int32  `json:"expo"`
+				PublishTime int64  `json:"publish_time"`
+			} `json:"price"`
+		} `json:"parsed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode prices: %w", err)
+	}
+
+	prices := make(map[common.Hash]PythPrice, len(payload.Parsed))
+	for _, update := range payload.Parsed {
+		price, ok := new(big.Int).SetString(update.Price.Price, 10)
+		if !ok {
+			return nil, fmt.Errorf("feed %s: invalid price %q", update.Id, update.Price.Price)
+		}
+		confidence, ok := new(big.Int).SetString(update.Price.Conf, 10)
+		if !ok {
+			return nil, fmt.Errorf("feed %s: invalid confidence %q", update.Id, update.Price.Conf)
+		}
+
+		feedId := common.HexToHash(update.Id)
+		prices[feedId] = PythPrice{
+			FeedId:      feedId,
+			Price:       price,
+			Confidence:  confidence,
+			Expo:        update.Price.Expo,
+			PublishedAt: time.Unix(update.Price.PublishTime, 0),
+		}
+	}
+	return prices, nil
+}
@@ -0,0 +1,118 @@
+package venues
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const uniswapV3FactoryAbi = `[
+	{"type":"function","name":"getPool","stateMutability":"view","inputs":[{"name":"tokenA","type":"address"},{"name":"tokenB","type":"address"},{"name":"fee","type":"uint24"}],"outputs":[{"name":"","type":"address"}]}
+]`
+
+const uniswapV3PoolAbi = `[
+	{"type":"function","name":"slot0","stateMutability":"view","inputs":[],"outputs":[{"name":"sqrtPriceX96","type":"uint160"},{"name":"tick","type":"int24"},{"name":"observationIndex","type":"uint16"},{"name":"observationCardinality","type":"uint16"},{"name":"observationCardinalityNext","type":"uint16"},{"name":"feeProtocol","type":"uint8"},{"name":"unlocked","type":"bool"}]},
+	{"type":"function","name":"liquidity","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint128"}]}
+]`
+
+// DefaultUniswapV3FeeTiers are the fee tiers enabled on the canonical factory
+var DefaultUniswapV3FeeTiers = []uint32{100, 500, 3000, 10000}
+
+// UniswapV3 quotes every fee tier pool the factory has for a pair
+type UniswapV3 struct {
+	factory  *boundContract
+	backend  bind.ContractCaller
+	feeTiers []uint32
+}
+
+func NewUniswapV3(factory common.Address, feeTiers []uint32, backend bind.ContractCaller) (*UniswapV3, error) {
+	bound, err := bindContract(factory, uniswapV3FactoryAbi, backend)
+	if err != nil {
+		return nil, err
+	}
+	if len(feeTiers) == 0 {
+		feeTiers = DefaultUniswapV3FeeTiers
+	}
+
+	return &UniswapV3{
+		factory:  bound,
+		backend:  backend,
+		feeTiers: feeTiers,
+	}, nil
+}
+
+func (u *UniswapV3) Name() string {
+	return "uniswap-v3"
+}
+
+// Quotes returns one quote per initialized fee tier pool
+func (u *UniswapV3) Quotes(ctx context.Context, pair Pair) ([]Quote, error) {
+	var quotes []Quote
+	for _, fee := range u.feeTiers {
+		out, err := u.factory.call(ctx, "getPool", pair.Token0, pair.Token1, big.NewInt(int64(fee)))
+		if err != nil {
+			return nil, err
+		}
+		poolAddr := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+		if poolAddr == (common.Address{}) {
+			continue
+		}
+
+		quote, err := u.quotePool(ctx, poolAddr)
+		if err != nil {
+			return nil, fmt.Errorf("fee tier %d: %w", fee, err)
+		}
+		if quote != nil {
+			quotes = append(quotes, *quote)
+		}
+	}
+
+	if len(quotes) == 0 {
+		return nil, ErrPairNotListed
+	}
+	return quotes, nil
+}
+
+// quotePool returns nil for pools that exist but were never initialized
+func (u *UniswapV3) quotePool(ctx context.Context, poolAddr common.Address) (*Quote, error) {
+	pool, err := bindContract(poolAddr, uniswapV3PoolAbi, u.backend)
+	if err != nil {
+		return nil, err
+	}
+
+	slot0, err := pool.call(ctx, "slot0")
+	if err != nil {
+		return nil, err
+	}
+	sqrtPriceX96 := *abi.ConvertType(slot0[0], new(*big.Int)).(**big.Int)
+	if sqrtPriceX96.Sign() == 0 {
+		return nil, nil
+	}
+
+	out, err := pool.call(ctx, "liquidity")
+	if err != nil {
+		return nil, err
+	}
+	liquidity := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return &Quote{
+		Venue:     u.Name(),
+		Pool:      poolAddr,
+		Price:     PriceFromSqrtPriceX96(sqrtPriceX96),
+		Liquidity: liquidity,
+		SampledAt: time.Now(),
+	}, nil
+}
+
+// PriceFromSqrtPriceX96 converts a Uniswap sqrtPriceX96 into a PriceScale
+// fixed-point price: (sqrtPriceX96^2 * 1e18) >> 192
+func PriceFromSqrtPriceX96(sqrtPriceX96 *big.Int) *big.Int {
+	price := new(big.Int).Mul(sqrtPriceX96, sqrtPriceX96)
+	price.Mul(price, PriceScale)
+	return price.Rsh(price, 192)
+}
@@ -0,0 +1,187 @@
+// Package venues samples spot prices for a token pair from on-chain venues
+// other than the auctioned pool, and from off-chain oracle feeds, so LVR and
+// bid sanity checks can be made against a reference that a single
+// manipulated pool can't move.
+package venues
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigensdk-go/logging"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// PriceScale is the fixed-point scale of all prices (1e18)
+	PriceScale = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+	// ErrNoQuotes is returned when no venue could price the pair
+	ErrNoQuotes = errors.New("no venue quotes available for pair")
+
+	// ErrPairNotListed is returned by a venue that has no market for the pair
+	ErrPairNotListed = errors.New("pair not listed on venue")
+)
+
+// Pair is a token pair ordered like Uniswap pools: Token0 has the lower address
+type Pair struct {
+	Token0 common.Address `json:"token0"`
+	Token1 common.Address `json:"token1"`
+}
+
+// NewPair returns the pair with its tokens sorted
+func NewPair(tokenA, tokenB common.Address) Pair {
+	if bytes.Compare(tokenA.Bytes(), tokenB.Bytes()) > 0 {
+		tokenA, tokenB = tokenB, tokenA
+	}
+	return Pair{Token0: tokenA, Token1: tokenB}
+}
+
+// Quote is one venue's spot price for a pair. Price is the amount of Token1
+// base units per Token0 base unit, scaled by PriceScale, which is the same
+// convention as the hook's on-chain pool price.
+type Quote struct {
+	Venue string         `json:"venue"`
+	Pool  common.Address `json:"pool"`
+	Price *big.Int       `json:"price"`
+	// Liquidity is the venue's in-range liquidity when it reports one
+	Liquidity *big.Int `json:"liquidity,omitempty"`
+	// ConfidenceBps is a feed's confidence interval relative to Price, for
+	// feeds that publish one
+	ConfidenceBps int64     `json:"confidenceBps,omitempty"`
+	SampledAt     time.Time `json:"sampledAt"`
+}
+
+// Venue is an on-chain market or a price feed that can quote a pair
+type Venue interface {
+	Name() string
+	Quotes(ctx context.Context, pair Pair) ([]Quote, error)
+}
+
+// Reference is the cross-venue reference price for a pair
+type Reference struct {
+	Pair   Pair    `json:"pair"`
+	Quotes []Quote `json:"quotes"`
+	// Price is the median of all quotes
+	Price *big.Int `json:"price"`
+	// SpreadBps is the spread between the highest and lowest quote relative
+	// to Price, in basis points
+	SpreadBps int64 `json:"spreadBps"`
+}
+
+// DeviationBps returns how far price is from the reference, in basis points.
+// The result is negative when price is below the reference.
+func (r Reference) DeviationBps(price *big.Int) int64 {
+	return deviationBps(price, r.Price)
+}
+
+// Sampler queries every configured venue for a pair
+type Sampler struct {
+	venues []Venue
+	logger logging.Logger
+}
+
+func NewSampler(venues []Venue, logger logging.Logger) *Sampler {
+	return &Sampler{
+		venues: venues,
+		logger: logger.With("component", "venue-sampler"),
+	}
+}
+
+// Reference samples all venues concurrently and returns the median price.
+// Venues that fail or don't list the pair are skipped.
+func (s *Sampler) Reference(ctx context.Context, pair Pair) (Reference, error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		quotes []Quote
+	)
+
+	for _, venue := range s.venues {
+		wg.Add(1)
+		go func(venue Venue) {
+			defer wg.Done()
+
+			venueQuotes, err := venue.Quotes(ctx, pair)
+			if errors.Is(err, ErrPairNotListed) {
+				return
+			}
+			if err != nil {
+				s.logger.Warn("Failed to sample venue", "venue", venue.Name(), "error", err)
+				return
+			}
+
+			mu.Lock()
+			quotes = append(quotes, venueQuotes...)
+			mu.Unlock()
+		}(venue)
+	}
+	wg.Wait()
+
+	if len(quotes) == 0 {
+		return Reference{Pair: pair}, ErrNoQuotes
+	}
+
+	sort.Slice(quotes, func(i, j int) bool {
+		return quotes[i].Price.Cmp(quotes[j].Price) < 0
+	})
+
+	median := medianPrice(quotes)
+	spread := new(big.Int).Sub(quotes[len(quotes)-1].Price, quotes[0].Price)
+
+	return Reference{
+		Pair:      pair,
+		Quotes:    quotes,
+		Price:     median,
+		SpreadBps: deviationBps(new(big.Int).Add(median, spread), median),
+	}, nil
+}
+
+// medianPrice returns the median of quotes sorted by price
+func medianPrice(quotes []Quote) *big.Int {
+	mid := len(quotes) / 2
+	if len(quotes)%2 == 1 {
+		return new(big.Int).Set(quotes[mid].Price)
+	}
+	sum := new(big.Int).Add(quotes[mid-1].Price, quotes[mid].Price)
+	return sum.Rsh(sum, 1)
+}
+
+func deviationBps(price, reference *big.Int) int64 {
+	if price == nil || reference == nil || reference.Sign() == 0 {
+		return 0
+	}
+	delta := new(big.Int).Sub(price, reference)
+	delta.Mul(delta, big.NewInt(10_000))
+	return delta.Quo(delta, reference).Int64()
+}
+
+// boundContract is a contract binding with a helper for single-value calls
+type boundContract struct {
+	*bind.BoundContract
+}
+
+func bindContract(address common.Address, contractAbi string, backend bind.ContractCaller) (*boundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractAbi))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse abi: %w", err)
+	}
+	return &boundContract{bind.NewBoundContract(address, parsed, backend, nil, nil)}, nil
+}
+
+func (c *boundContract) call(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	var out []interface{}
+	if err := c.Call(&bind.CallOpts{Context: ctx}, &out, method, args...); err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,128 @@
+// Package wsproto defines the messages exchanged over the persistent WebSocket
+// between operators and the aggregator.
+//
+// The aggregator opens every connection with a challenge. The operator answers
+// with its operator id and an ECDSA signature of the challenge by its
+// registered operator address. Once authenticated the aggregator pushes new
+// tasks, and the operator sends signed task responses, each answered by an ack
+// or an error carrying the same id.
+package wsproto
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eigenlvr/avs/pkg/remotesigner"
+)
+
+// Path is the operator WebSocket route under the API version prefix
+const Path = "/operator/ws"
+
+// Message types
+const (
+	TypeChallenge     = "challenge"
+	TypeAuth          = "auth"
+	TypeAuthenticated = "authenticated"
+	TypeTask          = "task"
+	TypeTaskResponse  = "taskResponse"
+	TypeAck           = "ack"
+	TypeError         = "error"
+)
+
+const (
+	// AuthTimeout is how long the aggregator waits for the auth message
+	AuthTimeout = 10 * time.Second
+
+	// PingInterval is how often each side pings; a connection that sees no
+	// traffic for PongTimeout is considered dead
+	PingInterval = 20 * time.Second
+	PongTimeout  = 2 * PingInterval
+
+	// MaxMessageBytes caps the size of a single message
+	MaxMessageBytes = 1 << 20
+
+	authMessagePrefix = "eigenlvr aggregator websocket auth:"
+	nonceBytes        = 32
+)
+
+// Message is the envelope of every WebSocket message
+type Message struct {
+	Type string `json:"type"`
+	// Id pairs a task response with its ack or error
+	Id      uint64          `json:"id,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	// Rejected marks an error that resending the same response won't fix
+	Rejected bool `json:"rejected,omitempty"`
+}
+
+// Challenge is the payload of a challenge message
+type Challenge struct {
+	Nonce hexutil.Bytes `json:"nonce"`
+}
+
+// Auth is the payload of an auth message
+type Auth struct {
+	OperatorId common.Hash   `json:"operatorId"`
+	Signature  hexutil.Bytes `json:"signature"`
+}
+
+// Task is the payload of a task message, mirroring the service manager's AuctionTask
+type Task struct {
+	TaskIndex                 uint32      `json:"taskIndex"`
+	PoolId                    common.Hash `json:"poolId"`
+	BlockNumber               uint32      `json:"blockNumber"`
+	TaskCreatedBlock          uint32      `json:"taskCreatedBlock"`
+	QuorumNumbers             []byte      `json:"quorumNumbers"`
+	QuorumThresholdPercentage uint32      `json:"quorumThresholdPercentage"`
+}
+
+// NewMessage encodes payload into a message of the given type
+func NewMessage(messageType string, id uint64, payload interface{}) (Message, error) {
+	message := Message{Type: messageType, Id: id}
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return message, fmt.Errorf("failed to encode %s payload: %w", messageType, err)
+		}
+		message.Payload = encoded
+	}
+	return message, nil
+}
+
+// NewChallenge returns a challenge with a random nonce
+func NewChallenge() (Challenge, error) {
+	nonce := make([]byte, nonceBytes)
+	if _, err := rand.Read(nonce); err != nil {
+		return Challenge{}, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return Challenge{Nonce: nonce}, nil
+}
+
+// SignChallenge signs the challenge nonce with the operator's ECDSA key
+func SignChallenge(ctx context.Context, challenge Challenge, signer remotesigner.Signer) ([]byte, error) {
+	return signer.SignHash(ctx, common.BytesToHash(authHash(challenge.Nonce)))
+}
+
+// RecoverSigner returns the address that signed the challenge nonce
+func RecoverSigner(challenge Challenge, signature []byte) (common.Address, error) {
+	publicKey, err := crypto.SigToPub(authHash(challenge.Nonce), signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid auth signature: %w", err)
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// authHash prefixes the nonce so the signature can't be replayed as anything
+// other than a WebSocket login
+func authHash(nonce []byte) []byte {
+	return accounts.TextHash(append([]byte(authMessagePrefix), nonce...))
+}